@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type client struct {
+	server        string
+	token         string
+	internalToken string
+	timezone      string
+}
+
+// do sends a request to the API and returns the response body. Non-2xx
+// responses are turned into an error carrying the server's message.
+func (c *client) do(method, path string, query url.Values, body any) (io.ReadCloser, error) {
+	var r io.Reader
+	if body != nil {
+		byt, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(byt)
+	}
+
+	u := strings.TrimSuffix(c.server, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if strings.HasPrefix(path, "/internal/") {
+		if c.internalToken != "" {
+			req.Header.Set("X-Internal-Token", c.internalToken)
+		}
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.timezone != "" {
+		req.Header.Set("X-Timezone", c.timezone)
+	}
+
+	// No client timeout: exports stream for as long as the server sends.
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res.Body, nil
+	}
+	defer res.Body.Close()
+
+	var apiErr struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&apiErr); err != nil || apiErr.Error.Message == "" {
+		return nil, fmt.Errorf("%s %s: %s", method, path, res.Status)
+	}
+
+	return nil, fmt.Errorf("%s: %s", apiErr.Error.Status, apiErr.Error.Message)
+}
+
+// print sends a request and pretty-prints the JSON response to stdout.
+func (c *client) print(method, path string, query url.Values, body any) error {
+	rc, err := c.do(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	var v any
+	if err := json.NewDecoder(rc).Decode(&v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (c *client) requireToken() error {
+	if c.token == "" {
+		return errors.New("no access token, run 'contactqrctl login' and set CONTACTQR_TOKEN")
+	}
+	return nil
+}
+
+// stamp is used in default export file names.
+func stamp() string {
+	return time.Now().Format("20060102-150405")
+}
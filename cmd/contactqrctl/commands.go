@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newLoginCmd(c *client) *cobra.Command {
+	var username, password string
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Sign in and print an access and refresh token",
+		Long: "Sign in and print an access and refresh token. The password is read " +
+			"from --password, CONTACTQR_PASSWORD or the first line of stdin.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if username == "" {
+				return fmt.Errorf("--username is required")
+			}
+			if password == "" {
+				password = os.Getenv("CONTACTQR_PASSWORD")
+			}
+			if password == "" {
+				fmt.Fprint(os.Stderr, "Password: ")
+				line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				password = strings.TrimRight(line, "\r\n")
+			}
+
+			return c.print(http.MethodPost, "/v1/auth/login", nil, map[string]string{
+				"username": username,
+				"password": password,
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&username, "username", "u", "", "employee code")
+	cmd.Flags().StringVarP(&password, "password", "p", "", "password")
+
+	return cmd
+}
+
+func newJobsCmd(c *client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List and run scheduled jobs",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List scheduled jobs and their last result",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				if err := c.requireToken(); err != nil {
+					return err
+				}
+				return c.print(http.MethodGet, "/v1/admin/jobs", nil, nil)
+			},
+		},
+		&cobra.Command{
+			Use:   "run NAME",
+			Short: "Run a scheduled job now",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := c.requireToken(); err != nil {
+					return err
+				}
+				return c.print(http.MethodPost, "/v1/admin/jobs/"+url.PathEscape(args[0])+"/run", nil, nil)
+			},
+		},
+	)
+
+	return cmd
+}
+
+func newCardsCmd(c *client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cards",
+		Short: "Change card status and export cards",
+	}
+
+	var (
+		remark string
+		dryRun bool
+	)
+	reject := &cobra.Command{
+		Use:   "reject ID",
+		Short: "Reject a pending card",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.requireToken(); err != nil {
+				return err
+			}
+			return c.print(http.MethodPost, "/v1/business-cards/reject", dryRunQuery(dryRun), map[string]string{
+				"cardId": args[0],
+				"remark": remark,
+			})
+		},
+	}
+	reject.Flags().StringVar(&remark, "remark", "", "reason shown to the card owner")
+	reject.Flags().BoolVar(&dryRun, "dry-run", false, dryRunUsage)
+
+	var (
+		out             string
+		status          string
+		includeArchived bool
+	)
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export cards as newline-delimited JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := c.requireToken(); err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if status != "" {
+				query.Set("status", strings.ToUpper(status))
+			}
+			if includeArchived {
+				query.Set("includeArchived", "true")
+			}
+			rc, err := c.do(http.MethodGet, "/v1/business-cards/stream", query, nil)
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+
+			if out == "" {
+				out = fmt.Sprintf("business-cards-%s.ndjson", stamp())
+			}
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			n, err := io.Copy(f, rc)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "wrote %d bytes to %s\n", n, out)
+			return nil
+		},
+	}
+	export.Flags().StringVarP(&out, "out", "o", "", "output file (default business-cards-<time>.ndjson)")
+	export.Flags().StringVar(&status, "status", "", "only export cards in this status")
+	export.Flags().BoolVar(&includeArchived, "include-archived", false, "export archived cards as well")
+
+	cmd.AddCommand(
+		statusCmd(c, "approve", "Approve a pending card", "/v1/business-cards/approve", true),
+		reject,
+		statusCmd(c, "publish", "Publish an approved card", "/v1/business-cards/publish", true),
+		statusCmd(c, "archive", "Archive a card", "/v1/business-cards/archive", false),
+		statusCmd(c, "unarchive", "Restore an archived card", "/v1/business-cards/unarchive", false),
+		export,
+	)
+
+	return cmd
+}
+
+const dryRunUsage = "check the status change and print the resulting card without saving it"
+
+// statusCmd returns a command moving the card given as its argument to a
+// new status through path. Commands for endpoints taking dryRun get a
+// --dry-run flag.
+func statusCmd(c *client, use, short, path string, withDryRun bool) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   use + " ID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.requireToken(); err != nil {
+				return err
+			}
+			return c.print(http.MethodPost, path, dryRunQuery(dryRun), map[string]string{
+				"cardId": args[0],
+			})
+		},
+	}
+	if withDryRun {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", false, dryRunUsage)
+	}
+
+	return cmd
+}
+
+func dryRunQuery(dryRun bool) url.Values {
+	if !dryRun {
+		return nil
+	}
+	return url.Values{"dryRun": {"true"}}
+}
+
+func newAuditCmd(c *client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the card audit log",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "verify",
+		Short: "Verify the audit log hash chain",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := c.requireToken(); err != nil {
+				return err
+			}
+			return c.print(http.MethodGet, "/v1/audit/verify", nil, nil)
+		},
+	})
+
+	return cmd
+}
+
+func newRotateKeysCmd(c *client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Reload the token keys and print their versions",
+		Long: "Reload the token keys from the server's config, so a key version added " +
+			"there starts encrypting new tokens, and print the versions in use. Only " +
+			"the instance --server reaches reloads, so run it against every instance.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return c.print(http.MethodPost, "/internal/token-keys/rotate", nil, nil)
+		},
+	}
+}
+
+func newMigrateCmd(c *client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Check and apply database migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "status",
+			Short: "Print the schema version and the pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				return c.print(http.MethodGet, "/internal/migrations", nil, nil)
+			},
+		},
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply the pending migrations shipped with the server",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				return c.print(http.MethodPost, "/internal/migrations/apply", nil, nil)
+			},
+		},
+	)
+
+	return cmd
+}
+
+func newRevokeTokenCmd(c *client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-token USERNAME",
+		Short: "Revoke every session and refresh token of a user",
+		Long: "Revoke every session of a user, so their refresh tokens stop working. " +
+			"Access tokens already issued stay valid until they expire.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.print(http.MethodPost, "/internal/users/"+url.PathEscape(args[0])+"/sessions/revoke", nil, nil)
+		},
+	}
+}
@@ -0,0 +1,57 @@
+// Command contactqrctl runs administrative tasks against a contactqr server
+// from the command line, so runbooks do not need hand-written curl calls.
+//
+// It authenticates with an access token taken from --token or
+// CONTACTQR_TOKEN; "contactqrctl login" prints one. The commands calling the
+// server's /internal endpoints, rotate-keys, migrate and revoke-token, send
+// the internal token from --internal-token or CONTACTQR_INTERNAL_TOKEN
+// instead.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	c := new(client)
+
+	root := &cobra.Command{
+		Use:           "contactqrctl",
+		Short:         "Administer a contactqr server",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&c.server, "server", getEnv("CONTACTQR_SERVER", "http://localhost:8089"), "server base URL (env CONTACTQR_SERVER)")
+	root.PersistentFlags().StringVar(&c.token, "token", os.Getenv("CONTACTQR_TOKEN"), "access token (env CONTACTQR_TOKEN)")
+	root.PersistentFlags().StringVar(&c.internalToken, "internal-token", os.Getenv("CONTACTQR_INTERNAL_TOKEN"), "token of the /internal endpoints, none from loopback (env CONTACTQR_INTERNAL_TOKEN)")
+	root.PersistentFlags().StringVar(&c.timezone, "timezone", os.Getenv("CONTACTQR_TIMEZONE"), "IANA timezone for local timestamps, server default if empty (env CONTACTQR_TIMEZONE)")
+
+	root.AddCommand(
+		newLoginCmd(c),
+		newJobsCmd(c),
+		newCardsCmd(c),
+		newAuditCmd(c),
+		newRotateKeysCmd(c),
+		newMigrateCmd(c),
+		newRevokeTokenCmd(c),
+	)
+
+	return root
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
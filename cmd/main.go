@@ -3,32 +3,83 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"aidanwoods.dev/go-paseto"
 	httpPb "github.com/10664kls/contactqr/genproto/go/http/v1"
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/alert"
+	"github.com/10664kls/contactqr/internal/audit"
 	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/auth/geoip"
+	"github.com/10664kls/contactqr/internal/auth/ldap"
+	"github.com/10664kls/contactqr/internal/backup"
+	"github.com/10664kls/contactqr/internal/breaker"
 	"github.com/10664kls/contactqr/internal/card"
+	"github.com/10664kls/contactqr/internal/config"
+	"github.com/10664kls/contactqr/internal/corpmail"
+	"github.com/10664kls/contactqr/internal/diag"
+	"github.com/10664kls/contactqr/internal/drain"
 	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/envelope"
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/export"
+	"github.com/10664kls/contactqr/internal/grpc"
+	"github.com/10664kls/contactqr/internal/health"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/jobs"
 	"github.com/10664kls/contactqr/internal/middleware"
+	"github.com/10664kls/contactqr/internal/migrate"
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/10664kls/contactqr/internal/password"
+	"github.com/10664kls/contactqr/internal/phone"
+	"github.com/10664kls/contactqr/internal/pii"
+	"github.com/10664kls/contactqr/internal/policy"
+	"github.com/10664kls/contactqr/internal/poster"
+	"github.com/10664kls/contactqr/internal/probe"
+	"github.com/10664kls/contactqr/internal/push"
+	"github.com/10664kls/contactqr/internal/ratelimit"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/scheduler"
+	"github.com/10664kls/contactqr/internal/seed"
 	"github.com/10664kls/contactqr/internal/server"
+	"github.com/10664kls/contactqr/internal/storage"
+	hrsync "github.com/10664kls/contactqr/internal/sync"
+	"github.com/10664kls/contactqr/internal/template"
+	"github.com/10664kls/contactqr/internal/tracing"
+	"github.com/10664kls/contactqr/internal/translit"
+	"github.com/10664kls/contactqr/internal/wallet"
+	"github.com/10664kls/contactqr/internal/web"
+	"github.com/10664kls/contactqr/internal/webhook"
+	"github.com/10664kls/contactqr/migrations"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/labstack/echo/v4"
 	stdmw "github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	gogrpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
-	_ "github.com/denisenkom/go-mssqldb"
+	mssql "github.com/denisenkom/go-mssqldb"
 )
 
 func main() {
@@ -38,6 +89,19 @@ func main() {
 }
 
 func run() error {
+	seedDB := flag.Bool("seed", false, "populate a development database with fake data and exit")
+	seedEmployees := flag.Int("seed-employees", 40, "number of staff created by --seed")
+	backupTo := flag.String("backup", "", "export the service tables to this archive and exit")
+	migrateOnly := flag.Bool("migrate", false, "apply pending database migrations and exit")
+	restoreFrom := flag.String("restore", "", "restore the service tables from this archive into an empty instance and exit")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "read settings from this YAML file, overridden by the environment")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -47,54 +111,365 @@ func run() error {
 	}
 	zap.ReplaceGlobals(zlog)
 
-	db, err := sql.Open(
-		"sqlserver",
-		fmt.Sprintf("sqlserver://%s:%s@%s:%s?database=%s&TrustServerCertificate=true",
-			os.Getenv("DB_USER"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("DB_HOST"),
-			os.Getenv("DB_PORT"),
-			os.Getenv("DB_NAME"),
-		),
-	)
+	if cfg.Tracing.Endpoint != "" {
+		shutdown, err := tracing.Setup(ctx, tracing.Options{
+			Endpoint:    cfg.Tracing.Endpoint,
+			ServiceName: cfg.Tracing.ServiceName,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdown(ctx); err != nil {
+				zlog.Error("failed to flush spans", zap.Error(err))
+			}
+		}()
+	}
+
+	connector, err := mssql.NewConnector(cfg.DB.DSN())
 	if err != nil {
 		return fmt.Errorf("failed to create db connection: %w", err)
 	}
+
+	dbBreaker := breaker.New(cfg.DB.BreakerThreshold, cfg.DB.BreakerCooldown)
+	db := sql.OpenDB(tracing.Connector(breaker.Connector(connector, dbBreaker)))
 	defer db.Close()
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DB.ConnMaxIdleTime)
+	dbHealth := must(health.New(dbBreaker))
 
-	if err := db.PingContext(ctx); err != nil {
+	if err := pingDB(
+		ctx,
+		db,
+		zlog,
+		cfg.DB.ConnectAttempts,
+		cfg.DB.ConnectBackoff,
+	); err != nil {
 		return fmt.Errorf("failed to ping DB: %w", err)
 	}
+	go watchDB(ctx, db, zlog, cfg.DB.WatchInterval)
+
+	if *migrateOnly {
+		migrator := must(migrate.NewMigrator(ctx, db, migrationsFS(cfg.DB.MigrationsDir), zlog))
+		return migrator.Up(ctx)
+	}
+	if err := migrateDB(ctx, db, &cfg.DB, zlog); err != nil {
+		return fmt.Errorf("failed to migrate DB: %w", err)
+	}
 
-	aKey := must(paseto.V4SymmetricKeyFromHex(os.Getenv("PASETO_ACCESS_KEY")))
-	rKey := must(paseto.V4SymmetricKeyFromHex(os.Getenv("PASETO_REFRESH_KEY")))
+	if *backupTo != "" || *restoreFrom != "" {
+		return backupDB(ctx, db, migrationsFS(cfg.DB.MigrationsDir), zlog, *backupTo, *restoreFrom)
+	}
+
+	kek, err := piiKeyWrapper(&cfg.PII, &cfg.AWS)
+	if err != nil {
+		return fmt.Errorf("failed to create PII key wrapper: %w", err)
+	}
+	if kek != nil {
+		pii.ReplaceGlobal(must(pii.NewCipher(ctx, kek)))
+
+		n, err := employee.RestoreLegacyContacts(ctx, db)
+		if err != nil {
+			return fmt.Errorf("failed to restore legacy employee contacts: %w", err)
+		}
+		if n > 0 {
+			zlog.Info("restored legacy employee contacts", zap.Int("count", n))
+		}
+
+		n, err = card.EncryptStoredVCFs(ctx, db)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt stored vCards: %w", err)
+		}
+		if n > 0 {
+			zlog.Info("encrypted stored vCards", zap.Int("count", n))
+		}
+	}
+
+	aKeys := must(auth.NewKeyRing(tokenKeys(*configFile, func(c *config.Config) []config.Key { return c.Token.AccessKeys })))
+	rKeys := must(auth.NewKeyRing(tokenKeys(*configFile, func(c *config.Config) []config.Key { return c.Token.RefreshKeys })))
+	var shareKeys *auth.KeyRing
+	if len(cfg.Token.ShareKeys) > 0 {
+		shareKeys = must(auth.NewKeyRing(tokenKeys(*configFile, func(c *config.Config) []config.Key { return c.Token.ShareKeys })))
+	}
+
+	notifier := newNotifier(&cfg.SMTP, zlog)
+	detector := must(alert.NewDetector(ctx, notifier, zlog, must(alertConfig(&cfg.Alert))))
 
 	e := echo.New()
 	e.HideBanner = true
+	trustedProxies := must(middleware.ParseNetworks(cfg.HTTP.TrustedProxies))
+	e.IPExtractor = middleware.ClientIP(trustedProxies)
+	e.Server.ReadHeaderTimeout = cfg.HTTP.ReadHeaderTimeout
+	e.Server.ReadTimeout = cfg.HTTP.ReadTimeout
+	e.Server.WriteTimeout = cfg.HTTP.WriteTimeout
+	e.Server.IdleTimeout = cfg.HTTP.IdleTimeout
+	e.Server.MaxHeaderBytes = cfg.HTTP.MaxHeaderBytes
+	drainer := must(drain.New(cfg.Server.DrainTimeout, zlog))
+
+	e.Use(middleware.RequestID())
+	e.Use(middleware.Tracing())
+	e.Use(envelope.Middleware(must(responseShape(cfg.Server.ResponseEnvelope))))
+	e.Use(drainer.Middleware())
+	e.Use(detector.Middleware())
 	e.Use(httpLogger(zlog))
-	e.Use(stdMws()...)
+	limits, rdb, closeLimits := rateLimitStore(&cfg.Redis, zlog)
+	defer closeLimits()
+	e.Use(stdMws(&cfg.HTTP, &cfg.Secure, limits, zlog)...)
+	e.Use(middleware.ReadOnlyOnOutage(dbHealth))
+	displayLoc := must(time.LoadLocation(cfg.Server.DisplayTimezone))
+	e.Use(middleware.Timezone(displayLoc))
 	e.HTTPErrorHandler = httpErr
 
+	assets := must(newAssets(&cfg.Assets, &cfg.AWS))
+
+	auditLog := must(audit.NewLog(ctx, db, zlog))
+
+	sched := must(scheduler.NewScheduler(ctx, db, dbHealth, zlog))
+	if err := sched.Register(&scheduler.Job{
+		Name: "audit-anchor",
+		Spec: cfg.Schedules.AuditAnchor,
+		Run:  auditLog.Anchor,
+	}); err != nil {
+		return fmt.Errorf("failed to register job: %w", err)
+	}
+
 	employeeService := must(employee.NewService(ctx, db, zlog))
-	cardService := must(card.NewService(ctx, db, zlog, employeeService))
-	authService := must(auth.NewAuth(ctx, db, aKey, rKey, zlog))
+	events, closeEvents, err := eventPublisher(&cfg.Events, zlog)
+	if err != nil {
+		return fmt.Errorf("failed to create event publisher: %w", err)
+	}
+	defer closeEvents()
+
+	pushService := must(push.NewService(ctx, db, must(pushProvider(&cfg.Push, zlog)), employeeService, zlog))
+
+	webhookService := must(webhook.NewService(ctx, db, zlog))
+	go webhookService.RunDeliveries(ctx, cfg.Jobs.WebhookDeliveryInterval)
+
+	outbox := must(event.NewOutbox(ctx, db, []event.Subscriber{
+		{Name: "broker", Publisher: events},
+		{Name: "push", Publisher: pushService},
+		{Name: "webhook", Publisher: webhookService},
+	}, zlog))
+	go outbox.RunRelay(ctx, cfg.Events.RelayInterval)
+
+	templateService := must(template.NewService(ctx, db, zlog))
+	go templateService.RunReload(ctx, cfg.Jobs.TemplateReloadInterval)
+	addressService := must(address.NewService(ctx, db, zlog))
+
+	// The side effects of requests, such as workflow emails, run from the
+	// job queue. Its handlers are registered by the services.
+	queue := must(jobs.NewQueue(ctx, db, zlog))
+
+	cardService := must(card.NewService(ctx, db, zlog, employeeService, assets, auditLog, outbox, notifier, phoneRegions(&cfg.Cards.Phone), phoneStyles(&cfg.Cards.Phone), emailPolicy(&cfg.Cards.Email), must(posterBrands(&cfg.Cards.Poster)), policy.Cards{MaxActive: cfg.Cards.MaxActive}, dbHealth, templateService, queue, shareKeys, must(walletSigner(&cfg.Cards.Wallet)), cfg.Cards.IdempotencyWindow))
+
+	if err := sched.Register(&scheduler.Job{
+		Name: "idempotency-key-purge",
+		Spec: cfg.Schedules.IdempotencyKeyPurge,
+		Run:  cardService.PurgeIdempotencyKeys,
+	}); err != nil {
+		return fmt.Errorf("failed to register job: %w", err)
+	}
+	go queue.Run(ctx, cfg.Jobs.Workers, cfg.Jobs.PollInterval)
+
+	if err := sched.Register(&scheduler.Job{
+		Name: "photo-refresh",
+		Spec: cfg.Schedules.PhotoRefresh,
+		Run:  cardService.RefreshPhotos,
+	}); err != nil {
+		return fmt.Errorf("failed to register job: %w", err)
+	}
+
+	exporter := must(export.New(ctx, db, awsCredentials(&cfg.AWS), zlog))
+	for name, src := range map[string]export.Source{
+		"cards": cardService.ExportCards,
+		"scans": cardService.ExportScans,
+		"audit": auditLog.ExportEntries,
+	} {
+		if err := exporter.AddSource(name, src); err != nil {
+			return fmt.Errorf("failed to add export source: %w", err)
+		}
+	}
+	exports, err := exportConfigs(cfg.Exports.File)
+	if err != nil {
+		return err
+	}
+	for _, exp := range exports {
+		run, err := exporter.Job(exp)
+		if err != nil {
+			return fmt.Errorf("failed to configure export: %w", err)
+		}
+		if err := sched.Register(&scheduler.Job{
+			Name: "export-" + exp.Name,
+			Spec: exp.Schedule,
+			Run:  run,
+		}); err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+
+	// The HR sync source is a drop folder or endpoint, see hrsync.ParseSource.
+	if raw := cfg.HRSync.Source; raw != "" {
+		importer := must(hrsync.NewImporter(ctx, db, must(hrsync.ParseSource(raw, cfg.HRSync.Token)), zlog))
+		if err := sched.Register(&scheduler.Job{
+			Name: "hr-sync",
+			Spec: cfg.HRSync.Schedule,
+			Run:  importer.Import,
+		}); err != nil {
+			return fmt.Errorf("failed to register job: %w", err)
+		}
+	}
+
+	if *seedDB {
+		seeder := must(seed.NewSeeder(ctx, db, cardService, zlog))
+		return seeder.Seed(ctx, seed.Config{
+			Employees: *seedEmployees,
+			Password:  cfg.Seed.Password,
+			Rand:      1,
+		})
+	}
+
+	var geo auth.GeoLocator = auth.NopGeoLocator{}
+	if path := cfg.Login.GeoIPDatabase; path != "" {
+		locator := must(geoip.Open(path))
+		defer locator.Close()
+		geo = locator
+	}
+	sessions := must(auth.NewSessions(
+		ctx,
+		db,
+		zlog,
+		notifier,
+		geo,
+		cfg.Login.ReportURL,
+	))
+	var directory auth.Directory = auth.NopDirectory{}
+	if cfg.LDAP.URL != "" {
+		directory = must(ldap.NewDirectory(ctx, ldap.Options{
+			URL:          cfg.LDAP.URL,
+			StartTLS:     cfg.LDAP.StartTLS,
+			BindDN:       cfg.LDAP.BindDN,
+			BindPassword: cfg.LDAP.BindPassword,
+			BaseDN:       cfg.LDAP.BaseDN,
+			UserFilter:   cfg.LDAP.UserFilter,
+			Attributes: ldap.Attributes{
+				DisplayName: cfg.LDAP.DisplayNameAttr,
+				Email:       cfg.LDAP.EmailAttr,
+				Phone:       cfg.LDAP.PhoneAttr,
+				Mobile:      cfg.LDAP.MobileAttr,
+			},
+			Timeout: cfg.LDAP.Timeout,
+		}))
+	}
+	authService := must(auth.NewAuth(ctx, db, aKeys, rKeys, auth.TTL{Access: cfg.Token.AccessTTL, Refresh: cfg.Token.RefreshTTL}, zlog, detector, sessions, directory, auth.Lockout{
+		Threshold: cfg.Login.LockoutThreshold,
+		Base:      cfg.Login.LockoutBase,
+		Max:       cfg.Login.LockoutMax,
+		Window:    cfg.Login.FailureWindow,
+	}))
+	if err := sched.Register(&scheduler.Job{
+		Name: "refresh-token-purge",
+		Spec: cfg.Schedules.RefreshTokenPurge,
+		Run:  authService.PurgeRefreshTokens,
+	}); err != nil {
+		return fmt.Errorf("failed to register job: %w", err)
+	}
+	if err := sched.Register(&scheduler.Job{
+		Name: "login-attempt-purge",
+		Spec: cfg.Schedules.LoginAttemptPurge,
+		Run:  authService.PurgeLoginAttempts,
+	}); err != nil {
+		return fmt.Errorf("failed to register job: %w", err)
+	}
+
+	roles := must(rbac.NewResolver(ctx, db, zlog))
 
 	mws := []echo.MiddlewareFunc{
 		middleware.PASETO(middleware.PASETOConfig{
-			SymmetricKey: aKey,
+			Keys: aKeys,
 		}),
 		middleware.SetContextClaimsFromToken,
+		middleware.SetContextRoles(roles),
 	}
 
-	server := must(server.NewServer(employeeService, cardService, authService))
+	drainer.OnDrain(sched.Drain)
+	drainer.OnDrain(queue.Drain)
+
+	translitService := must(translit.NewService(ctx, db, zlog))
+
+	diagnostics := must(newDiagnostics(db, rdb, assets, notifier, webhookService, events, outbox, queue, cfg.Server.DiagnosticsTimeout, zlog))
+
+	var pageTemplates fs.FS
+	if dir := cfg.Server.PageTemplatesDir; dir != "" {
+		pageTemplates = os.DirFS(dir)
+	}
+	pages := must(web.NewRenderer(pageTemplates))
+
+	probes := must(probe.New(cfg.Server.ProbeTimeout, zlog))
+	if err := errors.Join(
+		probes.Register("database", db.PingContext),
+		probes.Register("drain", func(context.Context) error {
+			if !drainer.Ready() {
+				return errors.New("instance is draining")
+			}
+			return nil
+		}),
+	); err != nil {
+		return fmt.Errorf("failed to register probe: %w", err)
+	}
+	e.GET("/healthz", healthz)
+	e.GET("/readyz", readyz(probes))
+
+	passwordService := must(password.NewService(
+		ctx,
+		db,
+		authService,
+		notifier,
+		zlog,
+		cfg.Login.PasswordResetURL,
+		cfg.Login.PasswordResetTTL,
+	))
+	server := must(server.NewServer(employeeService, cardService, authService, auditLog, sched, drainer, translitService, pushService, diagnostics, exporter, webhookService, passwordService, templateService, addressService, pages, must(migrate.NewMigrator(ctx, db, migrationsFS(cfg.DB.MigrationsDir), zlog))))
 	if err := server.Install(e, mws...); err != nil {
 		return fmt.Errorf("failed to install server: %w", err)
 	}
+	if err := server.InstallInternal(e, middleware.InternalOnly(cfg.Server.InternalToken)); err != nil {
+		return fmt.Errorf("failed to install internal server: %w", err)
+	}
+
+	var grpcServer *gogrpc.Server
+	if cfg.GRPC.Port != "" {
+		api := must(grpc.NewServer(authService, cardService, employeeService, aKeys, roles, trustedProxies, displayLoc, zlog))
+		grpcServer = gogrpc.NewServer(gogrpc.UnaryInterceptor(api.Intercept))
+		api.Register(grpcServer)
 
-	errCh := make(chan error, 1)
+		gateway := must(grpc.NewGateway(ctx, fmt.Sprintf("localhost:%s", cfg.GRPC.Port)))
+		e.Any("/rpc/*", echo.WrapHandler(http.StripPrefix("/rpc", gateway)))
+	}
+
+	go func() {
+		if err := sched.Run(ctx); err != nil {
+			zlog.Error("failed to run scheduler", zap.Error(err))
+		}
+	}()
+
+	errCh := make(chan error, 2)
 	go func() {
-		errCh <- e.Start(fmt.Sprintf(":%s", getEnv("PORT", "8089")))
+		errCh <- e.Start(fmt.Sprintf(":%s", cfg.HTTP.Port))
 	}()
+	if grpcServer != nil {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPC.Port))
+		if err != nil {
+			return fmt.Errorf("failed to listen for grpc: %w", err)
+		}
+		go func() {
+			errCh <- grpcServer.Serve(lis)
+		}()
+	}
 
 	ctx, cancel = signal.NotifyContext(ctx, os.Interrupt, os.Kill, syscall.SIGTERM)
 	defer cancel()
@@ -109,6 +484,9 @@ func run() error {
 			zlog.Error("failed to shutdown server", zap.Error(err))
 			return err
 		}
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
 
 		zlog.Info("server shut down gracefully")
 
@@ -121,11 +499,211 @@ func run() error {
 	return nil
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// pingDB pings the database until it answers, doubling the wait between
+// attempts up to 30 seconds, so the service survives a database failover
+// during startup instead of crash-looping.
+func pingDB(ctx context.Context, db *sql.DB, zlog *zap.Logger, attempts int, backoff time.Duration) error {
+	const maxBackoff = 30 * time.Second
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if i == attempts {
+			break
+		}
+
+		zlog.Warn("failed to ping DB, retrying",
+			zap.Int("attempt", i),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	return err
+}
+
+// migrateDB checks the schema version against the migrations shipped with
+// the binary, per cfg.Migrate: "check" refuses to start on any mismatch,
+// "apply" applies pending migrations first and "off" skips both.
+func migrateDB(ctx context.Context, db *sql.DB, cfg *config.DB, zlog *zap.Logger) error {
+	if cfg.Migrate == "off" {
+		return nil
+	}
+
+	migrator := must(migrate.NewMigrator(ctx, db, migrationsFS(cfg.MigrationsDir), zlog))
+	if cfg.Migrate == "apply" {
+		return migrator.Up(ctx)
+	}
+
+	st, err := migrator.Check(ctx)
+	if errors.Is(err, migrate.ErrPending) {
+		return fmt.Errorf("%w (set DB_MIGRATE=apply to apply them)", err)
+	}
+	if err != nil {
+		return err
+	}
+	zlog.Info("database schema is up to date", zap.Uint64("version", st.Current))
+
+	return nil
+}
+
+// migrationsFS returns the migrations embedded in the binary, or those in
+// dir when it is set.
+func migrationsFS(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	return migrations.FS
+}
+
+// backupDB exports the service tables to the archive at to, or restores
+// them from the archive at from.
+func backupDB(ctx context.Context, db *sql.DB, migrations fs.FS, zlog *zap.Logger, to, from string) error {
+	migrator := must(migrate.NewMigrator(ctx, db, migrations, zlog))
+	st, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if to != "" {
+		f, err := os.Create(to)
+		if err != nil {
+			return err
+		}
+		m, err := backup.Export(ctx, db, f, st.Current)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export backup: %w", err)
+		}
+
+		zlog.Info("exported backup", zap.String("file", to), zap.Any("rows", m.Rows))
+		return nil
+	}
+
+	f, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m, err := backup.Restore(ctx, db, f, st.Current)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	zlog.Info("restored backup", zap.String("file", from), zap.Time("createdAt", m.CreatedAt), zap.Any("rows", m.Rows))
+	return nil
+}
+
+// watchDB periodically pings the database and logs when it becomes
+// unreachable or recovers. database/sql reconnects on its own; this only
+// makes the outage visible.
+func watchDB(ctx context.Context, db *sql.DB, zlog *zap.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := db.PingContext(pingCtx)
+		cancel()
+
+		switch {
+		case err != nil && healthy:
+			zlog.Error("lost connection to DB", zap.Error(err))
+			healthy = false
+
+		case err == nil && !healthy:
+			zlog.Info("reconnected to DB")
+			healthy = true
+		}
+	}
+}
+
+// newAssets returns the storage of generated assets and uploaded photos:
+// the S3 bucket when one is configured, local disk otherwise.
+func newAssets(cfg *config.Assets, creds *config.AWS) (storage.Storage, error) {
+	if cfg.S3Bucket == "" {
+		return storage.NewDisk(cfg.Dir)
+	}
+
+	return storage.NewS3(storage.S3Config{
+		Bucket:      cfg.S3Bucket,
+		Region:      cfg.S3Region,
+		Prefix:      cfg.S3Prefix,
+		Endpoint:    cfg.S3Endpoint,
+		Credentials: awsCredentials(creds),
+	})
+}
+
+// piiKeyWrapper returns the wrapper of the keys personal data is encrypted
+// with: the KMS key when one is configured, else the master key, else nil
+// to leave personal data unencrypted.
+func piiKeyWrapper(cfg *config.PII, creds *config.AWS) (pii.KeyWrapper, error) {
+	switch {
+	case cfg.KMSKeyID != "":
+		return pii.NewKMSKeyWrapper(pii.KMSConfig{
+			KeyID:       cfg.KMSKeyID,
+			Region:      cfg.KMSRegion,
+			Endpoint:    cfg.KMSEndpoint,
+			Credentials: awsCredentials(creds),
+		})
+
+	case cfg.MasterKey != "":
+		return pii.NewLocalKeyWrapper(cfg.MasterKey)
+	}
+
+	return nil, nil
+}
+
+// awsCredentials returns the configured AWS credentials.
+func awsCredentials(cfg *config.AWS) aws.Credentials {
+	return aws.Credentials{
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+		SessionToken:    cfg.SessionToken,
+	}
+}
+
+// tokenKeys returns a loader of the token keys pick selects from the config.
+// The config is read again on every load, so a version added to the config
+// file, or to a secret mounted as a *_FILE, is picked up on rotation; the
+// environment of a running process cannot change.
+func tokenKeys(path string, pick func(*config.Config) []config.Key) auth.KeyLoader {
+	return func() ([]auth.Key, error) {
+		cfg, err := config.Load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]auth.Key, 0)
+		for _, k := range pick(cfg) {
+			key, err := paseto.V4SymmetricKeyFromHex(k.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key version %d: %w", k.Version, err)
+			}
+			keys = append(keys, auth.Key{Version: k.Version, Key: key})
+		}
+		return keys, nil
 	}
-	return fallback
 }
 
 func newLogger() (*zap.Logger, error) {
@@ -161,10 +739,14 @@ func newLogger() (*zap.Logger, error) {
 }
 
 func httpErr(err error, c echo.Context) {
+	lang := i18n.Negotiate(c.Request().Header.Get("Accept-Language"))
+
+	if errors.Is(err, breaker.ErrOpen) {
+		err = i18n.Error(codes.Unavailable, i18n.DBUnavailable)
+	}
+
 	if s, ok := status.FromError(err); ok {
-		he := httpStatusPbFromRPC(s)
-		jsonb, _ := protojson.Marshal(he)
-		c.JSONBlob(int(he.Error.Code), jsonb)
+		writeErr(c, httpStatusPbFromRPC(reqid.Status(c.Request().Context(), i18n.Localize(s, lang))))
 		return
 	}
 
@@ -173,38 +755,48 @@ func httpErr(err error, c echo.Context) {
 		switch he.Code {
 		case http.StatusNotFound,
 			http.StatusMethodNotAllowed:
-			s = status.New(codes.NotFound, "Not found!")
+			s = i18n.Status(codes.NotFound, i18n.NotFound)
 
 		case http.StatusTooManyRequests:
-			s = status.New(codes.ResourceExhausted, "Too many requests.")
+			s = i18n.Status(codes.ResourceExhausted, i18n.TooManyRequests)
 
 		case http.StatusInternalServerError:
-			s = status.New(codes.Internal, "An internal error occurred.")
+			s = i18n.Status(codes.Internal, i18n.Internal)
 
 		default:
-			s = status.New(codes.Unknown, "Unknown error!")
+			s = i18n.Status(codes.Unknown, i18n.Unknown)
 		}
 
-		hbp := httpStatusPbFromRPC(s)
-		jsonb, _ := protojson.Marshal(hbp)
-		c.JSONBlob(int(hbp.Error.Code), jsonb)
+		writeErr(c, httpStatusPbFromRPC(reqid.Status(c.Request().Context(), i18n.Localize(s, lang))))
 		return
 	}
 
-	c.JSON(http.StatusInternalServerError, echo.Map{
+	jsonb, _ := json.Marshal(echo.Map{
 		"code":    500,
 		"status":  "INTERNAL_ERROR",
-		"message": "An internal error occurred",
+		"message": i18n.Message(i18n.Internal, lang, nil),
 	})
+	envelope.Error(c, http.StatusInternalServerError, jsonb)
+}
+
+// writeErr writes he in the response shape the client asked for.
+func writeErr(c echo.Context, he *httpPb.Error) {
+	jsonb, _ := protojson.Marshal(he.Error)
+	envelope.Error(c, int(he.Error.Code), jsonb)
 }
 
-func stdMws() []echo.MiddlewareFunc {
+func stdMws(cfg *config.HTTP, secure *config.Secure, limits ratelimit.Store, zlog *zap.Logger) []echo.MiddlewareFunc {
+	routes := make(map[string]ratelimit.Limit, len(cfg.RateLimits))
+	for route, r := range cfg.RateLimits {
+		routes[route] = ratelimit.Per(r)
+	}
+
 	return []echo.MiddlewareFunc{
 		stdmw.RemoveTrailingSlash(),
 		stdmw.Recover(),
 		stdmw.CORSWithConfig(stdmw.CORSConfig{
 			AllowOriginFunc: func(origin string) (bool, error) {
-				return true, nil
+				return len(cfg.CORSOrigins) == 0 || slices.Contains(cfg.CORSOrigins, origin), nil
 			},
 			AllowMethods: []string{
 				http.MethodHead,
@@ -218,8 +810,310 @@ func stdMws() []echo.MiddlewareFunc {
 			AllowCredentials: true,
 			MaxAge:           86400,
 		}),
-		stdmw.RateLimiter(stdmw.NewRateLimiterMemoryStore(10)),
-		stdmw.Secure(),
+		middleware.RateLimit(middleware.RateLimitConfig{
+			Store:   limits,
+			Default: ratelimit.Per(cfg.RateLimit),
+			Routes:  routes,
+		}, zlog),
+		middleware.Secure(secureConfig(secure)),
+	}
+}
+
+// rateLimitStore returns the store requests are counted in, Redis when
+// configured so every replica shares the counts, the Redis client, nil
+// without Redis, and a func releasing it on shutdown.
+func rateLimitStore(cfg *config.Redis, zlog *zap.Logger) (ratelimit.Store, *redis.Client, func()) {
+	if cfg.Addr == "" {
+		return ratelimit.NewMemoryStore(3 * time.Minute), nil, func() {}
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	return ratelimit.NewRedisStore(client, "contactqr:ratelimit:"), client, func() {
+		if err := client.Close(); err != nil {
+			zlog.Error("failed to close redis client", zap.Error(err))
+		}
+	}
+}
+
+// eventPublisher returns the broker card events are published to and a func
+// releasing it on shutdown.
+func eventPublisher(cfg *config.Events, zlog *zap.Logger) (event.Publisher, func(), error) {
+	if cfg.Publisher != "kafka" {
+		p, err := event.NewLogPublisher(zlog)
+		return p, func() {}, err
+	}
+
+	k, err := event.NewKafka(cfg.KafkaBrokers, cfg.KafkaTopic)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, func() {
+		if err := k.Close(); err != nil {
+			zlog.Error("failed to close kafka publisher", zap.Error(err))
+		}
+	}, nil
+}
+
+// pushProvider returns the provider push notifications are delivered
+// through: FCM and APNs, or the log in development.
+func pushProvider(cfg *config.Push, zlog *zap.Logger) (push.Provider, error) {
+	if cfg.Provider != "mobile" {
+		return push.NewLogProvider(zlog)
+	}
+
+	fcm, err := push.NewFCMProvider([]byte(cfg.FCMCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FCM provider: %w", err)
+	}
+	apns, err := push.NewAPNsProvider(push.APNsConfig{
+		Key:     []byte(cfg.APNsKey),
+		KeyID:   cfg.APNsKeyID,
+		TeamID:  cfg.APNsTeamID,
+		Topic:   cfg.APNsTopic,
+		Sandbox: cfg.APNsSandbox,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create APNs provider: %w", err)
+	}
+
+	return push.Providers{
+		push.Android: fcm,
+		push.IOS:     apns,
+	}, nil
+}
+
+// newDiagnostics registers a check for each dependency card publishing
+// relies on. Each check gets timeout. rdb is nil without Redis.
+func newDiagnostics(db *sql.DB, rdb *redis.Client, assets storage.Storage, notifier notify.Notifier, webhooks *webhook.Service, events event.Publisher, outbox *event.Outbox, queue *jobs.Queue, timeout time.Duration, zlog *zap.Logger) (*diag.Diagnostics, error) {
+	d, err := diag.New(timeout, zlog)
+	if err != nil {
+		return nil, err
+	}
+
+	storageCheck := func(ctx context.Context) error {
+		const key = "diagnostics/probe"
+		if err := assets.Put(ctx, key, []byte(time.Now().Format(time.RFC3339))); err != nil {
+			return err
+		}
+		_, err := assets.Get(ctx, key)
+		return err
+	}
+
+	if err := errors.Join(
+		d.Register("database", db.PingContext),
+		d.Register("storage", storageCheck),
+		d.Register("event-outbox", outbox.Check),
+		d.Register("job-queue", queue.Check),
+		d.Register("webhook-targets", webhooks.Check),
+	); err != nil {
+		return nil, err
+	}
+
+	if rdb != nil {
+		if err := d.Register("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() }); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only brokers and mail servers that can be reached over the network
+	// are checked.
+	type pinger interface{ Ping(context.Context) error }
+	if p, ok := events.(pinger); ok {
+		if err := d.Register("event-publisher", p.Ping); err != nil {
+			return nil, err
+		}
+	}
+	if p, ok := notifier.(pinger); ok {
+		if err := d.Register("smtp", p.Ping); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// phoneRegions returns the region assumed for phone numbers entered without
+// a country, per company.
+func phoneRegions(cfg *config.Phone) phone.Regions {
+	regions := phone.Regions{
+		Default:   strings.ToUpper(cfg.Region),
+		Companies: make(map[int64]string, len(cfg.CompanyRegions)),
+	}
+	for companyID, region := range cfg.CompanyRegions {
+		regions.Companies[companyID] = strings.ToUpper(region)
+	}
+	return regions
+}
+
+// phoneStyles returns how phone numbers are displayed on cards and posters,
+// per company.
+func phoneStyles(cfg *config.Phone) phone.Styles {
+	def, _ := phone.ParseStyle(cfg.Format)
+	styles := phone.Styles{
+		Default:   def,
+		Companies: make(map[int64]phone.Style, len(cfg.CompanyFormats)),
+	}
+	for companyID, format := range cfg.CompanyFormats {
+		styles.Companies[companyID], _ = phone.ParseStyle(format)
+	}
+	return styles
+}
+
+// emailPolicy returns the corporate email domains, per company.
+func emailPolicy(cfg *config.Email) corpmail.Policy {
+	return corpmail.Policy{
+		Mode:      corpmail.Mode(cfg.Policy),
+		Default:   cfg.Domains,
+		Companies: cfg.CompanyDomains,
+	}
+}
+
+// posterBrands returns the band color of printed posters, per company.
+func posterBrands(cfg *config.Poster) (poster.Brands, error) {
+	def, err := poster.ParseColor(cfg.Color)
+	if err != nil {
+		return poster.Brands{}, err
+	}
+	brands := poster.Brands{
+		Default:   def,
+		Companies: make(map[int64]poster.Color, len(cfg.CompanyColors)),
+	}
+	for companyID, color := range cfg.CompanyColors {
+		if brands.Companies[companyID], err = poster.ParseColor(color); err != nil {
+			return poster.Brands{}, err
+		}
+	}
+	return brands, nil
+}
+
+// walletSigner returns the signer of Wallet passes, nil when no pass type
+// is configured.
+func walletSigner(cfg *config.Wallet) (*wallet.Signer, error) {
+	if cfg.PassTypeID == "" {
+		return nil, nil
+	}
+	return wallet.NewSigner(wallet.Config{
+		PassTypeID:   cfg.PassTypeID,
+		TeamID:       cfg.TeamID,
+		Organization: cfg.Organization,
+		Certificate:  []byte(cfg.Certificate),
+		Key:          []byte(cfg.Key),
+		WWDR:         []byte(cfg.WWDR),
+	})
+}
+
+// newNotifier returns the SMTP notifier when an SMTP server is configured.
+// Without one notifications are only logged, e.g. in development.
+func newNotifier(cfg *config.SMTP, zlog *zap.Logger) notify.Notifier {
+	if cfg.Addr == "" {
+		return must(notify.NewLogNotifier(zlog))
+	}
+
+	return must(notify.NewSMTPNotifier(notify.SMTPConfig{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	}))
+}
+
+// exportConfigs reads the recurring exports from the JSON array in the file
+// at path, e.g.
+//
+//	[{"name": "cards-monthly", "source": "cards", "schedule": "@monthly",
+//	  "destination": "s3://bi-drop/contactqr?region=ap-southeast-1"}]
+//
+// Sources are cards, scans and audit. Without a file nothing is exported.
+func exportConfigs(path string) ([]*export.Config, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exports file: %w", err)
+	}
+	var configs []*export.Config
+	if err := json.Unmarshal(b, &configs); err != nil {
+		return nil, fmt.Errorf("invalid exports file %s: %w", path, err)
+	}
+	return configs, nil
+}
+
+// responseShape is the response shape of clients that do not send the
+// X-Response-Envelope header. It stays legacy until they have migrated.
+func responseShape(v string) (envelope.Shape, error) {
+	shape, ok := envelope.ParseShape(v)
+	if !ok {
+		return "", fmt.Errorf("invalid response envelope %q, expected legacy or v1", v)
+	}
+	return shape, nil
+}
+
+func alertConfig(cfg *config.Alert) (alert.Config, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return alert.Config{}, err
+	}
+	start, end, err := cfg.Office()
+	if err != nil {
+		return alert.Config{}, err
+	}
+
+	return alert.Config{
+		Rules: map[string]alert.Rule{
+			alert.RuleForbidden: {
+				Threshold: cfg.ForbiddenThreshold,
+				Window:    cfg.ForbiddenWindow,
+			},
+			alert.RuleVCFBurst: {
+				Threshold: cfg.VCFThreshold,
+				Window:    cfg.VCFWindow,
+			},
+		},
+		OfficeStart: start,
+		OfficeEnd:   end,
+		Location:    loc,
+		Recipients:  cfg.Recipients,
+	}, nil
+}
+
+func secureConfig(cfg *config.Secure) middleware.SecureConfig {
+	config := middleware.DefaultSecureConfig
+
+	config.API.HSTSMaxAge = cfg.HSTSMaxAge
+	config.Page.HSTSMaxAge = cfg.HSTSMaxAge
+
+	config.API.ContentSecurityPolicy = cfg.APIContentSecurityPolicy
+	config.API.ReferrerPolicy = cfg.APIReferrerPolicy
+	config.API.XFrameOptions = cfg.APIFrameOptions
+
+	config.Page.ContentSecurityPolicy = cfg.PageContentSecurityPolicy
+	config.Page.ReferrerPolicy = cfg.PageReferrerPolicy
+	config.Page.XFrameOptions = cfg.PageFrameOptions
+
+	return config
+}
+
+// healthz answers the liveness probe: the process serves requests, whatever
+// the state of its dependencies.
+func healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": probe.StatusOK})
+}
+
+// readyz answers the readiness probe with the outcome of every registered
+// check, and 503 unless all of them pass.
+func readyz(probes *probe.Probes) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := probes.Ready(c.Request().Context())
+		if !report.Ready {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
 	}
 }
 
@@ -240,11 +1134,7 @@ func httpLogger(zlog *zap.Logger) echo.MiddlewareFunc {
 				zap.String("request", fmt.Sprintf("%s %s", req.Method, req.RequestURI)),
 				zap.Int("status", res.Status),
 				zap.String("user_agent", req.UserAgent()),
-			}
-
-			id := req.Header.Get(echo.HeaderXRequestID)
-			if id != "" {
-				fields = append(fields, zap.String("request_id", id))
+				zap.String("request_id", reqid.FromContext(req.Context())),
 			}
 
 			n := res.Status
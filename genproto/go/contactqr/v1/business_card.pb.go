@@ -0,0 +1,3066 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: contactqr/v1/business_card.proto
+
+package contactqr
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// buf:lint:ignore ENUM_VALUE_PREFIX
+// buf:lint:ignore ENUM_ZERO_VALUE_SUFFIX
+// Clients match on the bare names, which predate the proto contract.
+type BusinessCard_Status int32
+
+const (
+	BusinessCard_UNSPECIFIED BusinessCard_Status = 0
+	BusinessCard_PENDING     BusinessCard_Status = 1
+	BusinessCard_APPROVED    BusinessCard_Status = 2
+	BusinessCard_REJECTED    BusinessCard_Status = 3
+	BusinessCard_PUBLISHED   BusinessCard_Status = 4
+	// Hidden from listings unless asked for; unarchiving restores the
+	// status the card had.
+	BusinessCard_ARCHIVED BusinessCard_Status = 5
+)
+
+// Enum value maps for BusinessCard_Status.
+var (
+	BusinessCard_Status_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "PENDING",
+		2: "APPROVED",
+		3: "REJECTED",
+		4: "PUBLISHED",
+		5: "ARCHIVED",
+	}
+	BusinessCard_Status_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"PENDING":     1,
+		"APPROVED":    2,
+		"REJECTED":    3,
+		"PUBLISHED":   4,
+		"ARCHIVED":    5,
+	}
+)
+
+func (x BusinessCard_Status) Enum() *BusinessCard_Status {
+	p := new(BusinessCard_Status)
+	*p = x
+	return p
+}
+
+func (x BusinessCard_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BusinessCard_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_contactqr_v1_business_card_proto_enumTypes[0].Descriptor()
+}
+
+func (BusinessCard_Status) Type() protoreflect.EnumType {
+	return &file_contactqr_v1_business_card_proto_enumTypes[0]
+}
+
+func (x BusinessCard_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BusinessCard_Status.Descriptor instead.
+func (BusinessCard_Status) EnumDescriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{30, 0}
+}
+
+type PhoneNumber struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO Alpha-2 code: "LA", "TH", "US", etc. Default: the company's region.
+	Country string `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	// Phone number in E.164, international or national format, e.g.
+	// "+8562055123456", "+856 20 55 123 456" or "020 55 123 456". It is
+	// parsed in the country's numbering plan by the service.
+	Number        string `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhoneNumber) Reset() {
+	*x = PhoneNumber{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhoneNumber) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhoneNumber) ProtoMessage() {}
+
+func (x *PhoneNumber) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhoneNumber.ProtoReflect.Descriptor instead.
+func (*PhoneNumber) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PhoneNumber) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *PhoneNumber) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+type BusinessCardRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required. Numbers are parsed by the service, which knows the caller's
+	// default region.
+	Phone  *PhoneNumber `protobuf:"bytes,1,opt,name=phone,proto3" json:"phone,omitempty"`
+	Mobile *PhoneNumber `protobuf:"bytes,2,opt,name=mobile,proto3" json:"mobile,omitempty"`
+	// Phonetic spelling of the name, optional.
+	PhoneticGivenName  string `protobuf:"bytes,3,opt,name=phonetic_given_name,json=phoneticGivenName,proto3" json:"phonetic_given_name,omitempty"`
+	PhoneticFamilyName string `protobuf:"bytes,4,opt,name=phonetic_family_name,json=phoneticFamilyName,proto3" json:"phonetic_family_name,omitempty"`
+	// The languages the name is shown in, en and lo, the first being the
+	// display name. Default: the card's current ones, en for a new card.
+	NameLanguages []string     `protobuf:"bytes,5,rep,name=name_languages,json=nameLanguages,proto3" json:"name_languages,omitempty"`
+	SocialLinks   *SocialLinks `protobuf:"bytes,6,opt,name=social_links,json=socialLinks,proto3" json:"social_links,omitempty"`
+	// Makes retrying a create return the card created first instead of
+	// another, see the Idempotency-Key header.
+	IdempotencyKey string `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BusinessCardRequest) Reset() {
+	*x = BusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusinessCardRequest) ProtoMessage() {}
+
+func (x *BusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*BusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BusinessCardRequest) GetPhone() *PhoneNumber {
+	if x != nil {
+		return x.Phone
+	}
+	return nil
+}
+
+func (x *BusinessCardRequest) GetMobile() *PhoneNumber {
+	if x != nil {
+		return x.Mobile
+	}
+	return nil
+}
+
+func (x *BusinessCardRequest) GetPhoneticGivenName() string {
+	if x != nil {
+		return x.PhoneticGivenName
+	}
+	return ""
+}
+
+func (x *BusinessCardRequest) GetPhoneticFamilyName() string {
+	if x != nil {
+		return x.PhoneticFamilyName
+	}
+	return ""
+}
+
+func (x *BusinessCardRequest) GetNameLanguages() []string {
+	if x != nil {
+		return x.NameLanguages
+	}
+	return nil
+}
+
+func (x *BusinessCardRequest) GetSocialLinks() *SocialLinks {
+	if x != nil {
+		return x.SocialLinks
+	}
+	return nil
+}
+
+func (x *BusinessCardRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+// SocialLinks are the owner's accounts a card links to, all optional.
+type SocialLinks struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A phone number, parsed like the card's phone by the service.
+	Whatsapp      string `protobuf:"bytes,1,opt,name=whatsapp,proto3" json:"whatsapp,omitempty"`
+	Line          string `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	Wechat        string `protobuf:"bytes,3,opt,name=wechat,proto3" json:"wechat,omitempty"`
+	Linkedin      string `protobuf:"bytes,4,opt,name=linkedin,proto3" json:"linkedin,omitempty"`
+	Website       string `protobuf:"bytes,5,opt,name=website,proto3" json:"website,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SocialLinks) Reset() {
+	*x = SocialLinks{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SocialLinks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SocialLinks) ProtoMessage() {}
+
+func (x *SocialLinks) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SocialLinks.ProtoReflect.Descriptor instead.
+func (*SocialLinks) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SocialLinks) GetWhatsapp() string {
+	if x != nil {
+		return x.Whatsapp
+	}
+	return ""
+}
+
+func (x *SocialLinks) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *SocialLinks) GetWechat() string {
+	if x != nil {
+		return x.Wechat
+	}
+	return ""
+}
+
+func (x *SocialLinks) GetLinkedin() string {
+	if x != nil {
+		return x.Linkedin
+	}
+	return ""
+}
+
+func (x *SocialLinks) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+type GetBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBusinessCardRequest) Reset() {
+	*x = GetBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBusinessCardRequest) ProtoMessage() {}
+
+func (x *GetBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*GetBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetBusinessCardRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListMyBusinessCardsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageToken     string                 `protobuf:"bytes,1,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageSize      uint64                 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMyBusinessCardsRequest) Reset() {
+	*x = ListMyBusinessCardsRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMyBusinessCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMyBusinessCardsRequest) ProtoMessage() {}
+
+func (x *ListMyBusinessCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMyBusinessCardsRequest.ProtoReflect.Descriptor instead.
+func (*ListMyBusinessCardsRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListMyBusinessCardsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListMyBusinessCardsRequest) GetPageSize() uint64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ApproveBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveBusinessCardRequest) Reset() {
+	*x = ApproveBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveBusinessCardRequest) ProtoMessage() {}
+
+func (x *ApproveBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*ApproveBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ApproveBusinessCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type RejectBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	Remark        string                 `protobuf:"bytes,2,opt,name=remark,proto3" json:"remark,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectBusinessCardRequest) Reset() {
+	*x = RejectBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectBusinessCardRequest) ProtoMessage() {}
+
+func (x *RejectBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*RejectBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RejectBusinessCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *RejectBusinessCardRequest) GetRemark() string {
+	if x != nil {
+		return x.Remark
+	}
+	return ""
+}
+
+type PublishBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishBusinessCardRequest) Reset() {
+	*x = PublishBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishBusinessCardRequest) ProtoMessage() {}
+
+func (x *PublishBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*PublishBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PublishBusinessCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type ArchiveBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveBusinessCardRequest) Reset() {
+	*x = ArchiveBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveBusinessCardRequest) ProtoMessage() {}
+
+func (x *ArchiveBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ArchiveBusinessCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type UnarchiveBusinessCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardId        string                 `protobuf:"bytes,1,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveBusinessCardRequest) Reset() {
+	*x = UnarchiveBusinessCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveBusinessCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveBusinessCardRequest) ProtoMessage() {}
+
+func (x *UnarchiveBusinessCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveBusinessCardRequest.ProtoReflect.Descriptor instead.
+func (*UnarchiveBusinessCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UnarchiveBusinessCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type BatchArchiveBusinessCardsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cards created before this time are archived.
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// Optional filters.
+	DepartmentId  int64  `protobuf:"varint,2,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	CompanyId     int64  `protobuf:"varint,3,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Status        string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchArchiveBusinessCardsRequest) Reset() {
+	*x = BatchArchiveBusinessCardsRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchArchiveBusinessCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchArchiveBusinessCardsRequest) ProtoMessage() {}
+
+func (x *BatchArchiveBusinessCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchArchiveBusinessCardsRequest.ProtoReflect.Descriptor instead.
+func (*BatchArchiveBusinessCardsRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchArchiveBusinessCardsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *BatchArchiveBusinessCardsRequest) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *BatchArchiveBusinessCardsRequest) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *BatchArchiveBusinessCardsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetQRRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The card's public ID.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Default: png.
+	Format        string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRRequest) Reset() {
+	*x = GetQRRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRRequest) ProtoMessage() {}
+
+func (x *GetQRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQRRequest.ProtoReflect.Descriptor instead.
+func (*GetQRRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetQRRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetQRRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type GetVCFRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The card's ID, or public ID on the public routes.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Default: 2.1.
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Quoted-printable text fields, for vCard 2.1 only.
+	Legacy        bool `protobuf:"varint,3,opt,name=legacy,proto3" json:"legacy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVCFRequest) Reset() {
+	*x = GetVCFRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVCFRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVCFRequest) ProtoMessage() {}
+
+func (x *GetVCFRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVCFRequest.ProtoReflect.Descriptor instead.
+func (*GetVCFRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetVCFRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetVCFRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetVCFRequest) GetLegacy() bool {
+	if x != nil {
+		return x.Legacy
+	}
+	return false
+}
+
+type GetCardQRRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The card's ID.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Default: png.
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Width and height in pixels. Default: 512.
+	Size int32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// Error correction level. Default: M.
+	Level         string `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCardQRRequest) Reset() {
+	*x = GetCardQRRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCardQRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCardQRRequest) ProtoMessage() {}
+
+func (x *GetCardQRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCardQRRequest.ProtoReflect.Descriptor instead.
+func (*GetCardQRRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCardQRRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetCardQRRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetCardQRRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetCardQRRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type GetNDEFRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Default: auto.
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Default: ntag215.
+	Tag           string `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNDEFRequest) Reset() {
+	*x = GetNDEFRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNDEFRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNDEFRequest) ProtoMessage() {}
+
+func (x *GetNDEFRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNDEFRequest.ProtoReflect.Descriptor instead.
+func (*GetNDEFRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetNDEFRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetNDEFRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetNDEFRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type GetPosterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The card's ID, or the department's for a department poster.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Default: a4.
+	Size          string `protobuf:"bytes,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPosterRequest) Reset() {
+	*x = GetPosterRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPosterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPosterRequest) ProtoMessage() {}
+
+func (x *GetPosterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPosterRequest.ProtoReflect.Descriptor instead.
+func (*GetPosterRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetPosterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetPosterRequest) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+type ExportBusinessCardsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Default: csv.
+	Format        string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportBusinessCardsRequest) Reset() {
+	*x = ExportBusinessCardsRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportBusinessCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportBusinessCardsRequest) ProtoMessage() {}
+
+func (x *ExportBusinessCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportBusinessCardsRequest.ProtoReflect.Descriptor instead.
+func (*ExportBusinessCardsRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportBusinessCardsRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// An endpoint notified of card lifecycle events.
+// A company's card design. Colors are hex RGB, e.g. "#1a3c8c".
+type CardTemplateRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CompanyId    int64                  `protobuf:"varint,1,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	LogoUrl      string                 `protobuf:"bytes,3,opt,name=logo_url,json=logoUrl,proto3" json:"logo_url,omitempty"`
+	PrimaryColor string                 `protobuf:"bytes,4,opt,name=primary_color,json=primaryColor,proto3" json:"primary_color,omitempty"`
+	AccentColor  string                 `protobuf:"bytes,5,opt,name=accent_color,json=accentColor,proto3" json:"accent_color,omitempty"`
+	// The landing page layout, see Variant.
+	Layout        string `protobuf:"bytes,6,opt,name=layout,proto3" json:"layout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardTemplateRequest) Reset() {
+	*x = CardTemplateRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardTemplateRequest) ProtoMessage() {}
+
+func (x *CardTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CardTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CardTemplateRequest) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *CardTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CardTemplateRequest) GetLogoUrl() string {
+	if x != nil {
+		return x.LogoUrl
+	}
+	return ""
+}
+
+func (x *CardTemplateRequest) GetPrimaryColor() string {
+	if x != nil {
+		return x.PrimaryColor
+	}
+	return ""
+}
+
+func (x *CardTemplateRequest) GetAccentColor() string {
+	if x != nil {
+		return x.AccentColor
+	}
+	return ""
+}
+
+func (x *CardTemplateRequest) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+type CompanyAddressRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CompanyId  int64                  `protobuf:"varint,1,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Street     string                 `protobuf:"bytes,2,opt,name=street,proto3" json:"street,omitempty"`
+	Locality   string                 `protobuf:"bytes,3,opt,name=locality,proto3" json:"locality,omitempty"`
+	Region     string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode string                 `protobuf:"bytes,5,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country    string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	// The office's location, both or neither.
+	Latitude      *float64 `protobuf:"fixed64,7,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude     *float64 `protobuf:"fixed64,8,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompanyAddressRequest) Reset() {
+	*x = CompanyAddressRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompanyAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompanyAddressRequest) ProtoMessage() {}
+
+func (x *CompanyAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompanyAddressRequest.ProtoReflect.Descriptor instead.
+func (*CompanyAddressRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CompanyAddressRequest) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *CompanyAddressRequest) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *CompanyAddressRequest) GetLocality() string {
+	if x != nil {
+		return x.Locality
+	}
+	return ""
+}
+
+func (x *CompanyAddressRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CompanyAddressRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *CompanyAddressRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CompanyAddressRequest) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *CompanyAddressRequest) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+// The office address of a card's company, for the vCard's ADR and GEO.
+type CompanyAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CompanyId     int64                  `protobuf:"varint,1,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Street        string                 `protobuf:"bytes,2,opt,name=street,proto3" json:"street,omitempty"`
+	Locality      string                 `protobuf:"bytes,3,opt,name=locality,proto3" json:"locality,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,5,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	Latitude      *float64               `protobuf:"fixed64,7,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude     *float64               `protobuf:"fixed64,8,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompanyAddress) Reset() {
+	*x = CompanyAddress{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompanyAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompanyAddress) ProtoMessage() {}
+
+func (x *CompanyAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompanyAddress.ProtoReflect.Descriptor instead.
+func (*CompanyAddress) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CompanyAddress) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *CompanyAddress) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *CompanyAddress) GetLocality() string {
+	if x != nil {
+		return x.Locality
+	}
+	return ""
+}
+
+func (x *CompanyAddress) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CompanyAddress) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *CompanyAddress) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CompanyAddress) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *CompanyAddress) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+// The design a card is shown with: its company's template or, without one,
+// the default, which has no id.
+type CardTemplate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CompanyId     int64                  `protobuf:"varint,2,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	LogoUrl       string                 `protobuf:"bytes,4,opt,name=logo_url,json=logoUrl,proto3" json:"logo_url,omitempty"`
+	PrimaryColor  string                 `protobuf:"bytes,5,opt,name=primary_color,json=primaryColor,proto3" json:"primary_color,omitempty"`
+	AccentColor   string                 `protobuf:"bytes,6,opt,name=accent_color,json=accentColor,proto3" json:"accent_color,omitempty"`
+	Layout        string                 `protobuf:"bytes,7,opt,name=layout,proto3" json:"layout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardTemplate) Reset() {
+	*x = CardTemplate{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardTemplate) ProtoMessage() {}
+
+func (x *CardTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardTemplate.ProtoReflect.Descriptor instead.
+func (*CardTemplate) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CardTemplate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CardTemplate) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *CardTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CardTemplate) GetLogoUrl() string {
+	if x != nil {
+		return x.LogoUrl
+	}
+	return ""
+}
+
+func (x *CardTemplate) GetPrimaryColor() string {
+	if x != nil {
+		return x.PrimaryColor
+	}
+	return ""
+}
+
+func (x *CardTemplate) GetAccentColor() string {
+	if x != nil {
+		return x.AccentColor
+	}
+	return ""
+}
+
+func (x *CardTemplate) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+type WebhookRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Key the payloads are signed with.
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Default: every type.
+	Events        []string `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookRequest) Reset() {
+	*x = WebhookRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookRequest) ProtoMessage() {}
+
+func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookRequest.ProtoReflect.Descriptor instead.
+func (*WebhookRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *WebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// A visitor leaves a phone number, an email address or both.
+type SubmitLeadRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PhoneNumber  string                 `protobuf:"bytes,2,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	EmailAddress string                 `protobuf:"bytes,3,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	Message      string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// A honeypot hidden from people, so only bots fill it in.
+	Website       string `protobuf:"bytes,5,opt,name=website,proto3" json:"website,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitLeadRequest) Reset() {
+	*x = SubmitLeadRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitLeadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitLeadRequest) ProtoMessage() {}
+
+func (x *SubmitLeadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitLeadRequest.ProtoReflect.Descriptor instead.
+func (*SubmitLeadRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SubmitLeadRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubmitLeadRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *SubmitLeadRequest) GetEmailAddress() string {
+	if x != nil {
+		return x.EmailAddress
+	}
+	return ""
+}
+
+func (x *SubmitLeadRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmitLeadRequest) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+type EventCardRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Label string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// Default: now.
+	ValidFrom *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	// At most 90 days after valid_from.
+	ValidUntil    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventCardRequest) Reset() {
+	*x = EventCardRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventCardRequest) ProtoMessage() {}
+
+func (x *EventCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventCardRequest.ProtoReflect.Descriptor instead.
+func (*EventCardRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *EventCardRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *EventCardRequest) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *EventCardRequest) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
+type IssueEventCardsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Label      string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	ValidFrom  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidUntil *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	// The employees HR issues the event card to.
+	EmployeeIds   []int64 `protobuf:"varint,4,rep,packed,name=employee_ids,json=employeeIds,proto3" json:"employee_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueEventCardsRequest) Reset() {
+	*x = IssueEventCardsRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueEventCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueEventCardsRequest) ProtoMessage() {}
+
+func (x *IssueEventCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueEventCardsRequest.ProtoReflect.Descriptor instead.
+func (*IssueEventCardsRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *IssueEventCardsRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *IssueEventCardsRequest) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *IssueEventCardsRequest) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
+func (x *IssueEventCardsRequest) GetEmployeeIds() []int64 {
+	if x != nil {
+		return x.EmployeeIds
+	}
+	return nil
+}
+
+type GuestRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName    string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	EmailAddress   string                 `protobuf:"bytes,2,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	CompanyId      int64                  `protobuf:"varint,3,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	DepartmentName string                 `protobuf:"bytes,4,opt,name=department_name,json=departmentName,proto3" json:"department_name,omitempty"`
+	PositionName   string                 `protobuf:"bytes,5,opt,name=position_name,json=positionName,proto3" json:"position_name,omitempty"`
+	// The employee who approves the guest's cards. Default: the caller.
+	SponsorId     int64 `protobuf:"varint,6,opt,name=sponsor_id,json=sponsorId,proto3" json:"sponsor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GuestRequest) Reset() {
+	*x = GuestRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GuestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GuestRequest) ProtoMessage() {}
+
+func (x *GuestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GuestRequest.ProtoReflect.Descriptor instead.
+func (*GuestRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GuestRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *GuestRequest) GetEmailAddress() string {
+	if x != nil {
+		return x.EmailAddress
+	}
+	return ""
+}
+
+func (x *GuestRequest) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *GuestRequest) GetDepartmentName() string {
+	if x != nil {
+		return x.DepartmentName
+	}
+	return ""
+}
+
+func (x *GuestRequest) GetPositionName() string {
+	if x != nil {
+		return x.PositionName
+	}
+	return ""
+}
+
+func (x *GuestRequest) GetSponsorId() int64 {
+	if x != nil {
+		return x.SponsorId
+	}
+	return 0
+}
+
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Layout        string                 `protobuf:"bytes,1,opt,name=layout,proto3" json:"layout,omitempty"`
+	Weight        int64                  `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Variant) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// An experiment runs on either a card or a company.
+type ExperimentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CardId        string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	CompanyId     int64                  `protobuf:"varint,3,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Variants      []*Variant             `protobuf:"bytes,4,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExperimentRequest) Reset() {
+	*x = ExperimentRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExperimentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExperimentRequest) ProtoMessage() {}
+
+func (x *ExperimentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExperimentRequest.ProtoReflect.Descriptor instead.
+func (*ExperimentRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExperimentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExperimentRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+func (x *ExperimentRequest) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *ExperimentRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type ScanQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The first and last day of the report, inclusive, as YYYY-MM-DD.
+	From         string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To           string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	CompanyId    int64  `protobuf:"varint,3,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	DepartmentId int64  `protobuf:"varint,4,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	// The number of top cards per department. Default: 5.
+	Limit         uint64 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanQuery) Reset() {
+	*x = ScanQuery{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanQuery) ProtoMessage() {}
+
+func (x *ScanQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanQuery.ProtoReflect.Descriptor instead.
+func (*ScanQuery) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ScanQuery) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ScanQuery) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ScanQuery) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *ScanQuery) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *ScanQuery) GetLimit() uint64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Physical cards printed from a published card.
+type CreatePrintRequestRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Quantity int32                  `protobuf:"varint,1,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// The office the printed cards are delivered to.
+	DeliveryOffice string `protobuf:"bytes,2,opt,name=delivery_office,json=deliveryOffice,proto3" json:"delivery_office,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePrintRequestRequest) Reset() {
+	*x = CreatePrintRequestRequest{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePrintRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePrintRequestRequest) ProtoMessage() {}
+
+func (x *CreatePrintRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePrintRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreatePrintRequestRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreatePrintRequestRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CreatePrintRequestRequest) GetDeliveryOffice() string {
+	if x != nil {
+		return x.DeliveryOffice
+	}
+	return ""
+}
+
+type BusinessCard struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Set once the card is published.
+	PublicId       string `protobuf:"bytes,2,opt,name=public_id,json=publicId,proto3" json:"public_id,omitempty"`
+	EmployeeId     int64  `protobuf:"varint,3,opt,name=employee_id,json=employeeId,proto3" json:"employee_id,omitempty"`
+	DepartmentId   int64  `protobuf:"varint,4,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	PositionId     int64  `protobuf:"varint,5,opt,name=position_id,json=positionId,proto3" json:"position_id,omitempty"`
+	CompanyId      int64  `protobuf:"varint,6,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	EmployeeCode   string `protobuf:"bytes,7,opt,name=employee_code,json=employeeCode,proto3" json:"employee_code,omitempty"`
+	DisplayName    string `protobuf:"bytes,8,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	EmailAddress   string `protobuf:"bytes,9,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	PhoneNumber    string `protobuf:"bytes,10,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	PhoneE164      string `protobuf:"bytes,11,opt,name=phone_e164,json=phoneE164,proto3" json:"phone_e164,omitempty"`
+	PhoneNational  string `protobuf:"bytes,12,opt,name=phone_national,json=phoneNational,proto3" json:"phone_national,omitempty"`
+	MobileNumber   string `protobuf:"bytes,13,opt,name=mobile_number,json=mobileNumber,proto3" json:"mobile_number,omitempty"`
+	MobileE164     string `protobuf:"bytes,14,opt,name=mobile_e164,json=mobileE164,proto3" json:"mobile_e164,omitempty"`
+	MobileNational string `protobuf:"bytes,15,opt,name=mobile_national,json=mobileNational,proto3" json:"mobile_national,omitempty"`
+	// The numbers as the card's company displays them.
+	PhoneDisplay  string `protobuf:"bytes,16,opt,name=phone_display,json=phoneDisplay,proto3" json:"phone_display,omitempty"`
+	MobileDisplay string `protobuf:"bytes,17,opt,name=mobile_display,json=mobileDisplay,proto3" json:"mobile_display,omitempty"`
+	// Set on cards issued to guests instead of employees.
+	GuestId            *int64              `protobuf:"varint,18,opt,name=guest_id,json=guestId,proto3,oneof" json:"guest_id,omitempty"`
+	PhoneticGivenName  string              `protobuf:"bytes,19,opt,name=phonetic_given_name,json=phoneticGivenName,proto3" json:"phonetic_given_name,omitempty"`
+	PhoneticFamilyName string              `protobuf:"bytes,20,opt,name=phonetic_family_name,json=phoneticFamilyName,proto3" json:"phonetic_family_name,omitempty"`
+	PositionName       string              `protobuf:"bytes,21,opt,name=position_name,json=positionName,proto3" json:"position_name,omitempty"`
+	DepartmentName     string              `protobuf:"bytes,22,opt,name=department_name,json=departmentName,proto3" json:"department_name,omitempty"`
+	CompanyName        string              `protobuf:"bytes,23,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	Status             BusinessCard_Status `protobuf:"varint,24,opt,name=status,proto3,enum=contactqr.v1.BusinessCard_Status" json:"status,omitempty"`
+	// Only set for viewers allowed to see them: the owner, the approving
+	// manager and HR see the remark and whether the email is not a corporate
+	// one; only HR sees who created and last updated the card.
+	Remark       *string                `protobuf:"bytes,25,opt,name=remark,proto3,oneof" json:"remark,omitempty"`
+	EmailFlagged *bool                  `protobuf:"varint,26,opt,name=email_flagged,json=emailFlagged,proto3,oneof" json:"email_flagged,omitempty"`
+	CreatedBy    *string                `protobuf:"bytes,27,opt,name=created_by,json=createdBy,proto3,oneof" json:"created_by,omitempty"`
+	UpdatedBy    *string                `protobuf:"bytes,28,opt,name=updated_by,json=updatedBy,proto3,oneof" json:"updated_by,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,29,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,30,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// The timestamps in the viewer's display timezone.
+	CreatedAtLocal string `protobuf:"bytes,31,opt,name=created_at_local,json=createdAtLocal,proto3" json:"created_at_local,omitempty"`
+	UpdatedAtLocal string `protobuf:"bytes,32,opt,name=updated_at_local,json=updatedAtLocal,proto3" json:"updated_at_local,omitempty"`
+	Timezone       string `protobuf:"bytes,33,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Set while the card is archived.
+	ArchivedAt *timestamppb.Timestamp `protobuf:"bytes,34,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	// Path of the photo uploaded for the card, if any.
+	PhotoUrl string        `protobuf:"bytes,35,opt,name=photo_url,json=photoUrl,proto3" json:"photo_url,omitempty"`
+	Template *CardTemplate `protobuf:"bytes,36,opt,name=template,proto3" json:"template,omitempty"`
+	// The name in English and Lao, and the languages the card shows it in,
+	// the first being display_name.
+	DisplayNameEn string   `protobuf:"bytes,37,opt,name=display_name_en,json=displayNameEn,proto3" json:"display_name_en,omitempty"`
+	DisplayNameLo string   `protobuf:"bytes,38,opt,name=display_name_lo,json=displayNameLo,proto3" json:"display_name_lo,omitempty"`
+	NameLanguages []string `protobuf:"bytes,39,rep,name=name_languages,json=nameLanguages,proto3" json:"name_languages,omitempty"`
+	// WhatsApp is the number in E.164.
+	SocialLinks *SocialLinks `protobuf:"bytes,40,opt,name=social_links,json=socialLinks,proto3" json:"social_links,omitempty"`
+	// Unset when the card's company has no address.
+	Address *CompanyAddress `protobuf:"bytes,41,opt,name=address,proto3" json:"address,omitempty"`
+	// Set on a published card when an HR import changed its owner's name,
+	// email or organisation. Only set for the owner, the approving manager
+	// and HR.
+	EmployeeChangedAt *timestamppb.Timestamp `protobuf:"bytes,42,opt,name=employee_changed_at,json=employeeChangedAt,proto3" json:"employee_changed_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *BusinessCard) Reset() {
+	*x = BusinessCard{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusinessCard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusinessCard) ProtoMessage() {}
+
+func (x *BusinessCard) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusinessCard.ProtoReflect.Descriptor instead.
+func (*BusinessCard) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BusinessCard) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPublicId() string {
+	if x != nil {
+		return x.PublicId
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetEmployeeId() int64 {
+	if x != nil {
+		return x.EmployeeId
+	}
+	return 0
+}
+
+func (x *BusinessCard) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *BusinessCard) GetPositionId() int64 {
+	if x != nil {
+		return x.PositionId
+	}
+	return 0
+}
+
+func (x *BusinessCard) GetCompanyId() int64 {
+	if x != nil {
+		return x.CompanyId
+	}
+	return 0
+}
+
+func (x *BusinessCard) GetEmployeeCode() string {
+	if x != nil {
+		return x.EmployeeCode
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetEmailAddress() string {
+	if x != nil {
+		return x.EmailAddress
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPhoneE164() string {
+	if x != nil {
+		return x.PhoneE164
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPhoneNational() string {
+	if x != nil {
+		return x.PhoneNational
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetMobileNumber() string {
+	if x != nil {
+		return x.MobileNumber
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetMobileE164() string {
+	if x != nil {
+		return x.MobileE164
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetMobileNational() string {
+	if x != nil {
+		return x.MobileNational
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPhoneDisplay() string {
+	if x != nil {
+		return x.PhoneDisplay
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetMobileDisplay() string {
+	if x != nil {
+		return x.MobileDisplay
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetGuestId() int64 {
+	if x != nil && x.GuestId != nil {
+		return *x.GuestId
+	}
+	return 0
+}
+
+func (x *BusinessCard) GetPhoneticGivenName() string {
+	if x != nil {
+		return x.PhoneticGivenName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPhoneticFamilyName() string {
+	if x != nil {
+		return x.PhoneticFamilyName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetPositionName() string {
+	if x != nil {
+		return x.PositionName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetDepartmentName() string {
+	if x != nil {
+		return x.DepartmentName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetCompanyName() string {
+	if x != nil {
+		return x.CompanyName
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetStatus() BusinessCard_Status {
+	if x != nil {
+		return x.Status
+	}
+	return BusinessCard_UNSPECIFIED
+}
+
+func (x *BusinessCard) GetRemark() string {
+	if x != nil && x.Remark != nil {
+		return *x.Remark
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetEmailFlagged() bool {
+	if x != nil && x.EmailFlagged != nil {
+		return *x.EmailFlagged
+	}
+	return false
+}
+
+func (x *BusinessCard) GetCreatedBy() string {
+	if x != nil && x.CreatedBy != nil {
+		return *x.CreatedBy
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetUpdatedBy() string {
+	if x != nil && x.UpdatedBy != nil {
+		return *x.UpdatedBy
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetCreatedAtLocal() string {
+	if x != nil {
+		return x.CreatedAtLocal
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetUpdatedAtLocal() string {
+	if x != nil {
+		return x.UpdatedAtLocal
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetPhotoUrl() string {
+	if x != nil {
+		return x.PhotoUrl
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetTemplate() *CardTemplate {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetDisplayNameEn() string {
+	if x != nil {
+		return x.DisplayNameEn
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetDisplayNameLo() string {
+	if x != nil {
+		return x.DisplayNameLo
+	}
+	return ""
+}
+
+func (x *BusinessCard) GetNameLanguages() []string {
+	if x != nil {
+		return x.NameLanguages
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetSocialLinks() *SocialLinks {
+	if x != nil {
+		return x.SocialLinks
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetAddress() *CompanyAddress {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *BusinessCard) GetEmployeeChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EmployeeChangedAt
+	}
+	return nil
+}
+
+type BusinessCardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BusinessCard  *BusinessCard          `protobuf:"bytes,1,opt,name=business_card,json=businessCard,proto3" json:"business_card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BusinessCardResponse) Reset() {
+	*x = BusinessCardResponse{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusinessCardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusinessCardResponse) ProtoMessage() {}
+
+func (x *BusinessCardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusinessCardResponse.ProtoReflect.Descriptor instead.
+func (*BusinessCardResponse) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BusinessCardResponse) GetBusinessCard() *BusinessCard {
+	if x != nil {
+		return x.BusinessCard
+	}
+	return nil
+}
+
+// A card as it would be created, with the vCard and QR code it would be
+// published with.
+type BusinessCardPreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BusinessCard  *BusinessCard          `protobuf:"bytes,1,opt,name=business_card,json=businessCard,proto3" json:"business_card,omitempty"`
+	Vcf           string                 `protobuf:"bytes,2,opt,name=vcf,proto3" json:"vcf,omitempty"`
+	QrSvg         string                 `protobuf:"bytes,3,opt,name=qr_svg,json=qrSvg,proto3" json:"qr_svg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BusinessCardPreview) Reset() {
+	*x = BusinessCardPreview{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusinessCardPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusinessCardPreview) ProtoMessage() {}
+
+func (x *BusinessCardPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusinessCardPreview.ProtoReflect.Descriptor instead.
+func (*BusinessCardPreview) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BusinessCardPreview) GetBusinessCard() *BusinessCard {
+	if x != nil {
+		return x.BusinessCard
+	}
+	return nil
+}
+
+func (x *BusinessCardPreview) GetVcf() string {
+	if x != nil {
+		return x.Vcf
+	}
+	return ""
+}
+
+func (x *BusinessCardPreview) GetQrSvg() string {
+	if x != nil {
+		return x.QrSvg
+	}
+	return ""
+}
+
+type BusinessCardPreviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Preview       *BusinessCardPreview   `protobuf:"bytes,1,opt,name=preview,proto3" json:"preview,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BusinessCardPreviewResponse) Reset() {
+	*x = BusinessCardPreviewResponse{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BusinessCardPreviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BusinessCardPreviewResponse) ProtoMessage() {}
+
+func (x *BusinessCardPreviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BusinessCardPreviewResponse.ProtoReflect.Descriptor instead.
+func (*BusinessCardPreviewResponse) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BusinessCardPreviewResponse) GetPreview() *BusinessCardPreview {
+	if x != nil {
+		return x.Preview
+	}
+	return nil
+}
+
+type ListBusinessCardsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BusinessCards []*BusinessCard        `protobuf:"bytes,1,rep,name=business_cards,json=businessCards,proto3" json:"business_cards,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of matching cards across all pages, set when include_total was
+	// requested.
+	TotalSize     *int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3,oneof" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBusinessCardsResponse) Reset() {
+	*x = ListBusinessCardsResponse{}
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBusinessCardsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBusinessCardsResponse) ProtoMessage() {}
+
+func (x *ListBusinessCardsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_business_card_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBusinessCardsResponse.ProtoReflect.Descriptor instead.
+func (*ListBusinessCardsResponse) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_business_card_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListBusinessCardsResponse) GetBusinessCards() []*BusinessCard {
+	if x != nil {
+		return x.BusinessCards
+	}
+	return nil
+}
+
+func (x *ListBusinessCardsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListBusinessCardsResponse) GetTotalSize() int32 {
+	if x != nil && x.TotalSize != nil {
+		return *x.TotalSize
+	}
+	return 0
+}
+
+var File_contactqr_v1_business_card_proto protoreflect.FileDescriptor
+
+const file_contactqr_v1_business_card_proto_rawDesc = "" +
+	"\n" +
+	" contactqr/v1/business_card.proto\x12\fcontactqr.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"?\n" +
+	"\vPhoneNumber\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\"\x9d\x04\n" +
+	"\x13BusinessCardRequest\x12/\n" +
+	"\x05phone\x18\x01 \x01(\v2\x19.contactqr.v1.PhoneNumberR\x05phone\x121\n" +
+	"\x06mobile\x18\x02 \x01(\v2\x19.contactqr.v1.PhoneNumberR\x06mobile\x127\n" +
+	"\x13phonetic_given_name\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18dR\x11phoneticGivenName\x129\n" +
+	"\x14phonetic_family_name\x18\x04 \x01(\tB\a\xbaH\x04r\x02\x18dR\x12phoneticFamilyName\x12\xbc\x01\n" +
+	"\x0ename_languages\x18\x05 \x03(\tB\x94\x01\xbaH\x90\x01\xba\x01\x8c\x01\n" +
+	"\rINVALID_VALUE\x12(name languages must be distinct en or lo\x1aQthis.all(l, l in ['en', 'lo']) && this.all(l, this.filter(m, m == l).size() == 1)R\rnameLanguages\x12<\n" +
+	"\fsocial_links\x18\x06 \x01(\v2\x19.contactqr.v1.SocialLinksR\vsocialLinks\x121\n" +
+	"\x0fidempotency_key\x18\a \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x0eidempotencyKey\"\x80\x05\n" +
+	"\vSocialLinks\x12\x1a\n" +
+	"\bwhatsapp\x18\x01 \x01(\tR\bwhatsapp\x12w\n" +
+	"\x04line\x18\x02 \x01(\tBc\xbaH`\xba\x01]\n" +
+	"\x0eINVALID_HANDLE\x12\x16line must be a LINE ID\x1a3this == '' || this.matches('^@?[a-z0-9._-]{4,20}$')R\x04line\x12\x87\x01\n" +
+	"\x06wechat\x18\x03 \x01(\tBo\xbaHl\xba\x01i\n" +
+	"\x0eINVALID_HANDLE\x12\x1awechat must be a WeChat ID\x1a;this == '' || this.matches('^[a-zA-Z][a-zA-Z0-9_-]{5,19}$')R\x06wechat\x12\xbe\x01\n" +
+	"\blinkedin\x18\x04 \x01(\tB\xa1\x01\xbaH\x9d\x01\xba\x01\x94\x01\n" +
+	"\x14INVALID_LINKEDIN_URL\x12'linkedin must be a LinkedIn profile URL\x1aSthis == '' || this.matches('^https://([a-z]{2,3}[.])?linkedin[.]com/in/[^/?#]+/?$')r\x03\x18\xc8\x01R\blinkedin\x12\x90\x01\n" +
+	"\awebsite\x18\x05 \x01(\tBv\xbaHs\xba\x01k\n" +
+	"\x0eHTTPS_REQUIRED\x12\x1cwebsite must be an https URL\x1a;this == '' || (this.startsWith('https://') && this.isUri())r\x03\x18\xc8\x01R\awebsite\"0\n" +
+	"\x16GetBusinessCardRequest\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x02id\"X\n" +
+	"\x1aListMyBusinessCardsRequest\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x01 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x04R\bpageSize\"=\n" +
+	"\x1aApproveBusinessCardRequest\x12\x1f\n" +
+	"\acard_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06cardId\"\\\n" +
+	"\x19RejectBusinessCardRequest\x12\x1f\n" +
+	"\acard_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06cardId\x12\x1e\n" +
+	"\x06remark\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06remark\"=\n" +
+	"\x1aPublishBusinessCardRequest\x12\x1f\n" +
+	"\acard_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06cardId\"=\n" +
+	"\x1aArchiveBusinessCardRequest\x12\x1f\n" +
+	"\acard_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06cardId\"?\n" +
+	"\x1cUnarchiveBusinessCardRequest\x12\x1f\n" +
+	"\acard_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06cardId\"\xdb\x02\n" +
+	" BatchArchiveBusinessCardsRequest\x12I\n" +
+	"\x0ecreated_before\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampB\x06\xbaH\x03\xc8\x01\x01R\rcreatedBefore\x12#\n" +
+	"\rdepartment_id\x18\x02 \x01(\x03R\fdepartmentId\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x03 \x01(\x03R\tcompanyId\x12\xa7\x01\n" +
+	"\x06status\x18\x04 \x01(\tB\x8e\x01\xbaH\x8a\x01\xba\x01\x86\x01\n" +
+	"\rINVALID_VALUE\x127status must be PENDING, APPROVED, REJECTED or PUBLISHED\x1a<this in ['', 'PENDING', 'APPROVED', 'REJECTED', 'PUBLISHED']R\x06status\"\x88\x01\n" +
+	"\fGetQRRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12h\n" +
+	"\x06format\x18\x02 \x01(\tBP\xbaHM\xba\x01J\n" +
+	"\x15UNSUPPORTED_QR_FORMAT\x12\x19format must be png or svg\x1a\x16this in ['png', 'svg']R\x06format\"\xa8\x01\n" +
+	"\rGetVCFRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12o\n" +
+	"\aversion\x18\x02 \x01(\tBU\xbaHR\xba\x01O\n" +
+	"\x19UNSUPPORTED_VCARD_VERSION\x12\x1aversion must be 2.1 or 4.0\x1a\x16this in ['2.1', '4.0']R\aversion\x12\x16\n" +
+	"\x06legacy\x18\x03 \x01(\bR\x06legacy\"\xe7\x02\n" +
+	"\x10GetCardQRRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12h\n" +
+	"\x06format\x18\x02 \x01(\tBP\xbaHM\xba\x01J\n" +
+	"\x15UNSUPPORTED_QR_FORMAT\x12\x19format must be png or svg\x1a\x16this in ['png', 'svg']R\x06format\x12k\n" +
+	"\x04size\x18\x03 \x01(\x05BW\xbaHT\xba\x01Q\n" +
+	"\x0fINVALID_QR_SIZE\x12!size must be between 128 and 2048\x1a\x1bthis >= 128 && this <= 2048R\x04size\x12l\n" +
+	"\x05level\x18\x04 \x01(\tBV\xbaHS\xba\x01P\n" +
+	"\x14UNSUPPORTED_QR_LEVEL\x12\x1alevel must be L, M, Q or H\x1a\x1cthis in ['L', 'M', 'Q', 'H']R\x05level\"\xa2\x02\n" +
+	"\x0eGetNDEFRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12|\n" +
+	"\x06format\x18\x02 \x01(\tBd\xbaHa\xba\x01^\n" +
+	"\x17UNSUPPORTED_NDEF_FORMAT\x12!format must be url, vcard or auto\x1a this in ['url', 'vcard', 'auto']R\x06format\x12\x81\x01\n" +
+	"\x03tag\x18\x03 \x01(\tBo\xbaHl\xba\x01i\n" +
+	"\x13UNSUPPORTED_NFC_TAG\x12'tag must be ntag213, ntag215 or ntag216\x1a)this in ['ntag213', 'ntag215', 'ntag216']R\x03tag\"\x83\x01\n" +
+	"\x10GetPosterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12_\n" +
+	"\x04size\x18\x02 \x01(\tBK\xbaHH\xba\x01E\n" +
+	"\x16UNSUPPORTED_PAPER_SIZE\x12\x15size must be a4 or a5\x1a\x14this in ['a4', 'a5']R\x04size\"\x8c\x01\n" +
+	"\x1aExportBusinessCardsRequest\x12n\n" +
+	"\x06format\x18\x01 \x01(\tBV\xbaHS\xba\x01P\n" +
+	"\x19UNSUPPORTED_EXPORT_FORMAT\x12\x1aformat must be csv or xlsx\x1a\x17this in ['csv', 'xlsx']R\x06format\"\x9e\x05\n" +
+	"\x13CardTemplateRequest\x12%\n" +
+	"\n" +
+	"company_id\x18\x01 \x01(\x03B\x06\xbaH\x03\xc8\x01\x01R\tcompanyId\x12\x1e\n" +
+	"\x04name\x18\x02 \x01(\tB\n" +
+	"\xbaH\a\xc8\x01\x01r\x02\x18dR\x04name\x12\x92\x01\n" +
+	"\blogo_url\x18\x03 \x01(\tBw\xbaHt\xba\x01l\n" +
+	"\x0eHTTPS_REQUIRED\x12\x1dlogo_url must be an https URL\x1a;this == '' || (this.startsWith('https://') && this.isUri())r\x03\x18\xf4\x03R\alogoUrl\x12\x8c\x01\n" +
+	"\rprimary_color\x18\x04 \x01(\tBg\xbaHd\xba\x01a\n" +
+	"\rINVALID_COLOR\x12-color must be a hex RGB color such as #1a3c8c\x1a!this.matches('^#[0-9a-fA-F]{6}$')R\fprimaryColor\x12\x8a\x01\n" +
+	"\faccent_color\x18\x05 \x01(\tBg\xbaHd\xba\x01a\n" +
+	"\rINVALID_COLOR\x12-color must be a hex RGB color such as #1a3c8c\x1a!this.matches('^#[0-9a-fA-F]{6}$')R\vaccentColor\x12\x8e\x01\n" +
+	"\x06layout\x18\x06 \x01(\tBv\xbaHs\xba\x01p\n" +
+	"\x0eINVALID_LAYOUT\x123layout must be lowercase letters, digits and dashes\x1a)this.matches('^[a-z0-9][a-z0-9-]{0,31}$')R\x06layout\"\x9e\x04\n" +
+	"\x15CompanyAddressRequest\x12%\n" +
+	"\n" +
+	"company_id\x18\x01 \x01(\x03B\x06\xbaH\x03\xc8\x01\x01R\tcompanyId\x12#\n" +
+	"\x06street\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\x06street\x12#\n" +
+	"\blocality\x18\x03 \x01(\tB\a\xbaH\x04r\x02\x18dR\blocality\x12\x1f\n" +
+	"\x06region\x18\x04 \x01(\tB\a\xbaH\x04r\x02\x18dR\x06region\x12(\n" +
+	"\vpostal_code\x18\x05 \x01(\tB\a\xbaH\x04r\x02\x18\x14R\n" +
+	"postalCode\x12$\n" +
+	"\acountry\x18\x06 \x01(\tB\n" +
+	"\xbaH\a\xc8\x01\x01r\x02\x18dR\acountry\x12\x7f\n" +
+	"\blatitude\x18\a \x01(\x01B^\xbaH[\xba\x01X\n" +
+	"\x13INVALID_COORDINATES\x12\"latitude must be within -90 and 90\x1a\x1dthis >= -90.0 && this <= 90.0H\x00R\blatitude\x88\x01\x01\x12\x86\x01\n" +
+	"\tlongitude\x18\b \x01(\x01Bc\xbaH`\xba\x01]\n" +
+	"\x13INVALID_COORDINATES\x12%longitude must be within -180 and 180\x1a\x1fthis >= -180.0 && this <= 180.0H\x01R\tlongitude\x88\x01\x01B\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitude\"\x95\x02\n" +
+	"\x0eCompanyAddress\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x01 \x01(\x03R\tcompanyId\x12\x16\n" +
+	"\x06street\x18\x02 \x01(\tR\x06street\x12\x1a\n" +
+	"\blocality\x18\x03 \x01(\tR\blocality\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x05 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\x12\x1f\n" +
+	"\blatitude\x18\a \x01(\x01H\x00R\blatitude\x88\x01\x01\x12!\n" +
+	"\tlongitude\x18\b \x01(\x01H\x01R\tlongitude\x88\x01\x01B\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitude\"\xcc\x01\n" +
+	"\fCardTemplate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x02 \x01(\x03R\tcompanyId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x19\n" +
+	"\blogo_url\x18\x04 \x01(\tR\alogoUrl\x12#\n" +
+	"\rprimary_color\x18\x05 \x01(\tR\fprimaryColor\x12!\n" +
+	"\faccent_color\x18\x06 \x01(\tR\vaccentColor\x12\x16\n" +
+	"\x06layout\x18\a \x01(\tR\x06layout\"\xec\x02\n" +
+	"\x0eWebhookRequest\x12\x82\x01\n" +
+	"\x03url\x18\x01 \x01(\tBp\xbaHm\xba\x01g\n" +
+	"\x0eHTTPS_REQUIRED\x12\x18url must be an https URL\x1a;this == '' || (this.startsWith('https://') && this.isUri())\xc8\x01\x01R\x03url\x12\x1f\n" +
+	"\x06secret\x18\x02 \x01(\tB\a\xbaH\x04r\x02\x10\x10R\x06secret\x12\xb3\x01\n" +
+	"\x06events\x18\x03 \x03(\tB\x9a\x01\xbaH\x96\x01\x92\x01\x92\x01\"\x8f\x01\xba\x01\x8b\x01\n" +
+	"\x16UNSUPPORTED_EVENT_TYPE\x127events must be CREATED, APPROVED, REJECTED or PUBLISHED\x1a8this in ['CREATED', 'APPROVED', 'REJECTED', 'PUBLISHED']R\x06events\"\xc6\x01\n" +
+	"\x11SubmitLeadRequest\x12\x1f\n" +
+	"\x04name\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\x04name\x12!\n" +
+	"\fphone_number\x18\x02 \x01(\tR\vphoneNumber\x12/\n" +
+	"\remail_address\x18\x03 \x01(\tB\n" +
+	"\xbaH\a\xd8\x01\x01r\x02`\x01R\femailAddress\x12\"\n" +
+	"\amessage\x18\x04 \x01(\tB\b\xbaH\x05r\x03\x18\xe8\aR\amessage\x12\x18\n" +
+	"\awebsite\x18\x05 \x01(\tR\awebsite\"\xb5\x01\n" +
+	"\x10EventCardRequest\x12!\n" +
+	"\x05label\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\x05label\x129\n" +
+	"\n" +
+	"valid_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x12C\n" +
+	"\vvalid_until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x06\xbaH\x03\xc8\x01\x01R\n" +
+	"validUntil\"\xeb\x01\n" +
+	"\x16IssueEventCardsRequest\x12!\n" +
+	"\x05label\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\x05label\x129\n" +
+	"\n" +
+	"valid_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x12C\n" +
+	"\vvalid_until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\x06\xbaH\x03\xc8\x01\x01R\n" +
+	"validUntil\x12.\n" +
+	"\femployee_ids\x18\x04 \x03(\x03B\v\xbaH\b\x92\x01\x05\b\x01\x10\xf4\x03R\vemployeeIds\"\xc3\x02\n" +
+	"\fGuestRequest\x12.\n" +
+	"\fdisplay_name\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\vdisplayName\x12/\n" +
+	"\remail_address\x18\x02 \x01(\tB\n" +
+	"\xbaH\a\xd8\x01\x01r\x02`\x01R\femailAddress\x12N\n" +
+	"\n" +
+	"company_id\x18\x03 \x01(\x03B/\xbaH,\xba\x01)\n" +
+	"\bREQUIRED\x12\x13company must be set\x1a\bthis > 0R\tcompanyId\x121\n" +
+	"\x0fdepartment_name\x18\x04 \x01(\tB\b\xbaH\x05r\x03\x18\xc8\x01R\x0edepartmentName\x120\n" +
+	"\rposition_name\x18\x05 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\fpositionName\x12\x1d\n" +
+	"\n" +
+	"sponsor_id\x18\x06 \x01(\x03R\tsponsorId\"\x8e\x02\n" +
+	"\aVariant\x12\x8e\x01\n" +
+	"\x06layout\x18\x01 \x01(\tBv\xbaHs\xba\x01p\n" +
+	"\x0eINVALID_LAYOUT\x123layout must be lowercase letters, digits and dashes\x1a)this.matches('^[a-z0-9][a-z0-9-]{0,31}$')R\x06layout\x12r\n" +
+	"\x06weight\x18\x02 \x01(\x03BZ\xbaHW\xba\x01T\n" +
+	"\x16INVALID_VARIANT_WEIGHT\x12 weight must be between 1 and 100\x1a\x18this >= 1 && this <= 100R\x06weight\"\xd6\x02\n" +
+	"\x11ExperimentRequest\x12\x1e\n" +
+	"\x04name\x18\x01 \x01(\tB\n" +
+	"\xbaH\a\xc8\x01\x01r\x02\x18dR\x04name\x12\x17\n" +
+	"\acard_id\x18\x02 \x01(\tR\x06cardId\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x03 \x01(\x03R\tcompanyId\x12\xe8\x01\n" +
+	"\bvariants\x18\x04 \x03(\v2\x15.contactqr.v1.VariantB\xb4\x01\xbaH\xb0\x01\xba\x01\xac\x01\n" +
+	"\x10INVALID_VARIANTS\x123there must be 2 to 5 variants with distinct layouts\x1acsize(this) >= 2 && size(this) <= 5 && this.all(v, this.filter(w, w.layout == v.layout).size() == 1)R\bvariants\"\x89\x01\n" +
+	"\tScanQuery\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x03 \x01(\x03R\tcompanyId\x12#\n" +
+	"\rdepartment_id\x18\x04 \x01(\x03R\fdepartmentId\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x04R\x05limit\"\xcd\x01\n" +
+	"\x19CreatePrintRequestRequest\x12z\n" +
+	"\bquantity\x18\x01 \x01(\x05B^\xbaH[\xba\x01X\n" +
+	"\x16INVALID_PRINT_QUANTITY\x12#quantity must be between 1 and 1000\x1a\x19this >= 1 && this <= 1000R\bquantity\x124\n" +
+	"\x0fdelivery_office\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x18\xc8\x01R\x0edeliveryOffice\"\xe9\x0e\n" +
+	"\fBusinessCard\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tpublic_id\x18\x02 \x01(\tR\bpublicId\x12\x1f\n" +
+	"\vemployee_id\x18\x03 \x01(\x03R\n" +
+	"employeeId\x12#\n" +
+	"\rdepartment_id\x18\x04 \x01(\x03R\fdepartmentId\x12\x1f\n" +
+	"\vposition_id\x18\x05 \x01(\x03R\n" +
+	"positionId\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x06 \x01(\x03R\tcompanyId\x12#\n" +
+	"\remployee_code\x18\a \x01(\tR\femployeeCode\x12!\n" +
+	"\fdisplay_name\x18\b \x01(\tR\vdisplayName\x12#\n" +
+	"\remail_address\x18\t \x01(\tR\femailAddress\x12!\n" +
+	"\fphone_number\x18\n" +
+	" \x01(\tR\vphoneNumber\x12\x1d\n" +
+	"\n" +
+	"phone_e164\x18\v \x01(\tR\tphoneE164\x12%\n" +
+	"\x0ephone_national\x18\f \x01(\tR\rphoneNational\x12#\n" +
+	"\rmobile_number\x18\r \x01(\tR\fmobileNumber\x12\x1f\n" +
+	"\vmobile_e164\x18\x0e \x01(\tR\n" +
+	"mobileE164\x12'\n" +
+	"\x0fmobile_national\x18\x0f \x01(\tR\x0emobileNational\x12#\n" +
+	"\rphone_display\x18\x10 \x01(\tR\fphoneDisplay\x12%\n" +
+	"\x0emobile_display\x18\x11 \x01(\tR\rmobileDisplay\x12\x1e\n" +
+	"\bguest_id\x18\x12 \x01(\x03H\x00R\aguestId\x88\x01\x01\x12.\n" +
+	"\x13phonetic_given_name\x18\x13 \x01(\tR\x11phoneticGivenName\x120\n" +
+	"\x14phonetic_family_name\x18\x14 \x01(\tR\x12phoneticFamilyName\x12#\n" +
+	"\rposition_name\x18\x15 \x01(\tR\fpositionName\x12'\n" +
+	"\x0fdepartment_name\x18\x16 \x01(\tR\x0edepartmentName\x12!\n" +
+	"\fcompany_name\x18\x17 \x01(\tR\vcompanyName\x129\n" +
+	"\x06status\x18\x18 \x01(\x0e2!.contactqr.v1.BusinessCard.StatusR\x06status\x12\x1b\n" +
+	"\x06remark\x18\x19 \x01(\tH\x01R\x06remark\x88\x01\x01\x12(\n" +
+	"\remail_flagged\x18\x1a \x01(\bH\x02R\femailFlagged\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"created_by\x18\x1b \x01(\tH\x03R\tcreatedBy\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"updated_by\x18\x1c \x01(\tH\x04R\tupdatedBy\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x1d \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x1e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12(\n" +
+	"\x10created_at_local\x18\x1f \x01(\tR\x0ecreatedAtLocal\x12(\n" +
+	"\x10updated_at_local\x18  \x01(\tR\x0eupdatedAtLocal\x12\x1a\n" +
+	"\btimezone\x18! \x01(\tR\btimezone\x12;\n" +
+	"\varchived_at\x18\" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x1b\n" +
+	"\tphoto_url\x18# \x01(\tR\bphotoUrl\x126\n" +
+	"\btemplate\x18$ \x01(\v2\x1a.contactqr.v1.CardTemplateR\btemplate\x12&\n" +
+	"\x0fdisplay_name_en\x18% \x01(\tR\rdisplayNameEn\x12&\n" +
+	"\x0fdisplay_name_lo\x18& \x01(\tR\rdisplayNameLo\x12%\n" +
+	"\x0ename_languages\x18' \x03(\tR\rnameLanguages\x12<\n" +
+	"\fsocial_links\x18( \x01(\v2\x19.contactqr.v1.SocialLinksR\vsocialLinks\x126\n" +
+	"\aaddress\x18) \x01(\v2\x1c.contactqr.v1.CompanyAddressR\aaddress\x12J\n" +
+	"\x13employee_changed_at\x18* \x01(\v2\x1a.google.protobuf.TimestampR\x11employeeChangedAt\"_\n" +
+	"\x06Status\x12\x0f\n" +
+	"\vUNSPECIFIED\x10\x00\x12\v\n" +
+	"\aPENDING\x10\x01\x12\f\n" +
+	"\bAPPROVED\x10\x02\x12\f\n" +
+	"\bREJECTED\x10\x03\x12\r\n" +
+	"\tPUBLISHED\x10\x04\x12\f\n" +
+	"\bARCHIVED\x10\x05B\v\n" +
+	"\t_guest_idB\t\n" +
+	"\a_remarkB\x10\n" +
+	"\x0e_email_flaggedB\r\n" +
+	"\v_created_byB\r\n" +
+	"\v_updated_by\"W\n" +
+	"\x14BusinessCardResponse\x12?\n" +
+	"\rbusiness_card\x18\x01 \x01(\v2\x1a.contactqr.v1.BusinessCardR\fbusinessCard\"\x7f\n" +
+	"\x13BusinessCardPreview\x12?\n" +
+	"\rbusiness_card\x18\x01 \x01(\v2\x1a.contactqr.v1.BusinessCardR\fbusinessCard\x12\x10\n" +
+	"\x03vcf\x18\x02 \x01(\tR\x03vcf\x12\x15\n" +
+	"\x06qr_svg\x18\x03 \x01(\tR\x05qrSvg\"Z\n" +
+	"\x1bBusinessCardPreviewResponse\x12;\n" +
+	"\apreview\x18\x01 \x01(\v2!.contactqr.v1.BusinessCardPreviewR\apreview\"\xb9\x01\n" +
+	"\x19ListBusinessCardsResponse\x12A\n" +
+	"\x0ebusiness_cards\x18\x01 \x03(\v2\x1a.contactqr.v1.BusinessCardR\rbusinessCards\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\"\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05H\x00R\ttotalSize\x88\x01\x01B\r\n" +
+	"\v_total_size2\xb9\a\n" +
+	"\vCardService\x12z\n" +
+	"\x12CreateBusinessCard\x12!.contactqr.v1.BusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/business-cards\x12\x87\x01\n" +
+	"\x13ListMyBusinessCards\x12(.contactqr.v1.ListMyBusinessCardsRequest\x1a'.contactqr.v1.ListBusinessCardsResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/business-cards/me\x12\x81\x01\n" +
+	"\x11GetMyBusinessCard\x12$.contactqr.v1.GetBusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/business-cards/me/{id}\x12|\n" +
+	"\x0fGetBusinessCard\x12$.contactqr.v1.GetBusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/business-cards/{id}\x12\x8a\x01\n" +
+	"\x13ApproveBusinessCard\x12(.contactqr.v1.ApproveBusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/business-cards/approve\x12\x87\x01\n" +
+	"\x12RejectBusinessCard\x12'.contactqr.v1.RejectBusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/business-cards/reject\x12\x8a\x01\n" +
+	"\x13PublishBusinessCard\x12(.contactqr.v1.PublishBusinessCardRequest\x1a\".contactqr.v1.BusinessCardResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/business-cards/publishBBZ@github.com/10664kls/contactqr/genproto/go/contactqr/v1;contactqrb\x06proto3"
+
+var (
+	file_contactqr_v1_business_card_proto_rawDescOnce sync.Once
+	file_contactqr_v1_business_card_proto_rawDescData []byte
+)
+
+func file_contactqr_v1_business_card_proto_rawDescGZIP() []byte {
+	file_contactqr_v1_business_card_proto_rawDescOnce.Do(func() {
+		file_contactqr_v1_business_card_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contactqr_v1_business_card_proto_rawDesc), len(file_contactqr_v1_business_card_proto_rawDesc)))
+	})
+	return file_contactqr_v1_business_card_proto_rawDescData
+}
+
+var file_contactqr_v1_business_card_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_contactqr_v1_business_card_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_contactqr_v1_business_card_proto_goTypes = []any{
+	(BusinessCard_Status)(0),                 // 0: contactqr.v1.BusinessCard.Status
+	(*PhoneNumber)(nil),                      // 1: contactqr.v1.PhoneNumber
+	(*BusinessCardRequest)(nil),              // 2: contactqr.v1.BusinessCardRequest
+	(*SocialLinks)(nil),                      // 3: contactqr.v1.SocialLinks
+	(*GetBusinessCardRequest)(nil),           // 4: contactqr.v1.GetBusinessCardRequest
+	(*ListMyBusinessCardsRequest)(nil),       // 5: contactqr.v1.ListMyBusinessCardsRequest
+	(*ApproveBusinessCardRequest)(nil),       // 6: contactqr.v1.ApproveBusinessCardRequest
+	(*RejectBusinessCardRequest)(nil),        // 7: contactqr.v1.RejectBusinessCardRequest
+	(*PublishBusinessCardRequest)(nil),       // 8: contactqr.v1.PublishBusinessCardRequest
+	(*ArchiveBusinessCardRequest)(nil),       // 9: contactqr.v1.ArchiveBusinessCardRequest
+	(*UnarchiveBusinessCardRequest)(nil),     // 10: contactqr.v1.UnarchiveBusinessCardRequest
+	(*BatchArchiveBusinessCardsRequest)(nil), // 11: contactqr.v1.BatchArchiveBusinessCardsRequest
+	(*GetQRRequest)(nil),                     // 12: contactqr.v1.GetQRRequest
+	(*GetVCFRequest)(nil),                    // 13: contactqr.v1.GetVCFRequest
+	(*GetCardQRRequest)(nil),                 // 14: contactqr.v1.GetCardQRRequest
+	(*GetNDEFRequest)(nil),                   // 15: contactqr.v1.GetNDEFRequest
+	(*GetPosterRequest)(nil),                 // 16: contactqr.v1.GetPosterRequest
+	(*ExportBusinessCardsRequest)(nil),       // 17: contactqr.v1.ExportBusinessCardsRequest
+	(*CardTemplateRequest)(nil),              // 18: contactqr.v1.CardTemplateRequest
+	(*CompanyAddressRequest)(nil),            // 19: contactqr.v1.CompanyAddressRequest
+	(*CompanyAddress)(nil),                   // 20: contactqr.v1.CompanyAddress
+	(*CardTemplate)(nil),                     // 21: contactqr.v1.CardTemplate
+	(*WebhookRequest)(nil),                   // 22: contactqr.v1.WebhookRequest
+	(*SubmitLeadRequest)(nil),                // 23: contactqr.v1.SubmitLeadRequest
+	(*EventCardRequest)(nil),                 // 24: contactqr.v1.EventCardRequest
+	(*IssueEventCardsRequest)(nil),           // 25: contactqr.v1.IssueEventCardsRequest
+	(*GuestRequest)(nil),                     // 26: contactqr.v1.GuestRequest
+	(*Variant)(nil),                          // 27: contactqr.v1.Variant
+	(*ExperimentRequest)(nil),                // 28: contactqr.v1.ExperimentRequest
+	(*ScanQuery)(nil),                        // 29: contactqr.v1.ScanQuery
+	(*CreatePrintRequestRequest)(nil),        // 30: contactqr.v1.CreatePrintRequestRequest
+	(*BusinessCard)(nil),                     // 31: contactqr.v1.BusinessCard
+	(*BusinessCardResponse)(nil),             // 32: contactqr.v1.BusinessCardResponse
+	(*BusinessCardPreview)(nil),              // 33: contactqr.v1.BusinessCardPreview
+	(*BusinessCardPreviewResponse)(nil),      // 34: contactqr.v1.BusinessCardPreviewResponse
+	(*ListBusinessCardsResponse)(nil),        // 35: contactqr.v1.ListBusinessCardsResponse
+	(*timestamppb.Timestamp)(nil),            // 36: google.protobuf.Timestamp
+}
+var file_contactqr_v1_business_card_proto_depIdxs = []int32{
+	1,  // 0: contactqr.v1.BusinessCardRequest.phone:type_name -> contactqr.v1.PhoneNumber
+	1,  // 1: contactqr.v1.BusinessCardRequest.mobile:type_name -> contactqr.v1.PhoneNumber
+	3,  // 2: contactqr.v1.BusinessCardRequest.social_links:type_name -> contactqr.v1.SocialLinks
+	36, // 3: contactqr.v1.BatchArchiveBusinessCardsRequest.created_before:type_name -> google.protobuf.Timestamp
+	36, // 4: contactqr.v1.EventCardRequest.valid_from:type_name -> google.protobuf.Timestamp
+	36, // 5: contactqr.v1.EventCardRequest.valid_until:type_name -> google.protobuf.Timestamp
+	36, // 6: contactqr.v1.IssueEventCardsRequest.valid_from:type_name -> google.protobuf.Timestamp
+	36, // 7: contactqr.v1.IssueEventCardsRequest.valid_until:type_name -> google.protobuf.Timestamp
+	27, // 8: contactqr.v1.ExperimentRequest.variants:type_name -> contactqr.v1.Variant
+	0,  // 9: contactqr.v1.BusinessCard.status:type_name -> contactqr.v1.BusinessCard.Status
+	36, // 10: contactqr.v1.BusinessCard.created_at:type_name -> google.protobuf.Timestamp
+	36, // 11: contactqr.v1.BusinessCard.updated_at:type_name -> google.protobuf.Timestamp
+	36, // 12: contactqr.v1.BusinessCard.archived_at:type_name -> google.protobuf.Timestamp
+	21, // 13: contactqr.v1.BusinessCard.template:type_name -> contactqr.v1.CardTemplate
+	3,  // 14: contactqr.v1.BusinessCard.social_links:type_name -> contactqr.v1.SocialLinks
+	20, // 15: contactqr.v1.BusinessCard.address:type_name -> contactqr.v1.CompanyAddress
+	36, // 16: contactqr.v1.BusinessCard.employee_changed_at:type_name -> google.protobuf.Timestamp
+	31, // 17: contactqr.v1.BusinessCardResponse.business_card:type_name -> contactqr.v1.BusinessCard
+	31, // 18: contactqr.v1.BusinessCardPreview.business_card:type_name -> contactqr.v1.BusinessCard
+	33, // 19: contactqr.v1.BusinessCardPreviewResponse.preview:type_name -> contactqr.v1.BusinessCardPreview
+	31, // 20: contactqr.v1.ListBusinessCardsResponse.business_cards:type_name -> contactqr.v1.BusinessCard
+	2,  // 21: contactqr.v1.CardService.CreateBusinessCard:input_type -> contactqr.v1.BusinessCardRequest
+	5,  // 22: contactqr.v1.CardService.ListMyBusinessCards:input_type -> contactqr.v1.ListMyBusinessCardsRequest
+	4,  // 23: contactqr.v1.CardService.GetMyBusinessCard:input_type -> contactqr.v1.GetBusinessCardRequest
+	4,  // 24: contactqr.v1.CardService.GetBusinessCard:input_type -> contactqr.v1.GetBusinessCardRequest
+	6,  // 25: contactqr.v1.CardService.ApproveBusinessCard:input_type -> contactqr.v1.ApproveBusinessCardRequest
+	7,  // 26: contactqr.v1.CardService.RejectBusinessCard:input_type -> contactqr.v1.RejectBusinessCardRequest
+	8,  // 27: contactqr.v1.CardService.PublishBusinessCard:input_type -> contactqr.v1.PublishBusinessCardRequest
+	32, // 28: contactqr.v1.CardService.CreateBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	35, // 29: contactqr.v1.CardService.ListMyBusinessCards:output_type -> contactqr.v1.ListBusinessCardsResponse
+	32, // 30: contactqr.v1.CardService.GetMyBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	32, // 31: contactqr.v1.CardService.GetBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	32, // 32: contactqr.v1.CardService.ApproveBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	32, // 33: contactqr.v1.CardService.RejectBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	32, // 34: contactqr.v1.CardService.PublishBusinessCard:output_type -> contactqr.v1.BusinessCardResponse
+	28, // [28:35] is the sub-list for method output_type
+	21, // [21:28] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_contactqr_v1_business_card_proto_init() }
+func file_contactqr_v1_business_card_proto_init() {
+	if File_contactqr_v1_business_card_proto != nil {
+		return
+	}
+	file_contactqr_v1_business_card_proto_msgTypes[18].OneofWrappers = []any{}
+	file_contactqr_v1_business_card_proto_msgTypes[19].OneofWrappers = []any{}
+	file_contactqr_v1_business_card_proto_msgTypes[30].OneofWrappers = []any{}
+	file_contactqr_v1_business_card_proto_msgTypes[34].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contactqr_v1_business_card_proto_rawDesc), len(file_contactqr_v1_business_card_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   35,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contactqr_v1_business_card_proto_goTypes,
+		DependencyIndexes: file_contactqr_v1_business_card_proto_depIdxs,
+		EnumInfos:         file_contactqr_v1_business_card_proto_enumTypes,
+		MessageInfos:      file_contactqr_v1_business_card_proto_msgTypes,
+	}.Build()
+	File_contactqr_v1_business_card_proto = out.File
+	file_contactqr_v1_business_card_proto_goTypes = nil
+	file_contactqr_v1_business_card_proto_depIdxs = nil
+}
@@ -0,0 +1,359 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: contactqr/v1/business_card.proto
+
+package contactqr
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CardService_CreateBusinessCard_FullMethodName  = "/contactqr.v1.CardService/CreateBusinessCard"
+	CardService_ListMyBusinessCards_FullMethodName = "/contactqr.v1.CardService/ListMyBusinessCards"
+	CardService_GetMyBusinessCard_FullMethodName   = "/contactqr.v1.CardService/GetMyBusinessCard"
+	CardService_GetBusinessCard_FullMethodName     = "/contactqr.v1.CardService/GetBusinessCard"
+	CardService_ApproveBusinessCard_FullMethodName = "/contactqr.v1.CardService/ApproveBusinessCard"
+	CardService_RejectBusinessCard_FullMethodName  = "/contactqr.v1.CardService/RejectBusinessCard"
+	CardService_PublishBusinessCard_FullMethodName = "/contactqr.v1.CardService/PublishBusinessCard"
+)
+
+// CardServiceClient is the client API for CardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CardService serves business cards through the same service layer as the
+// HTTP API.
+type CardServiceClient interface {
+	CreateBusinessCard(ctx context.Context, in *BusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+	ListMyBusinessCards(ctx context.Context, in *ListMyBusinessCardsRequest, opts ...grpc.CallOption) (*ListBusinessCardsResponse, error)
+	GetMyBusinessCard(ctx context.Context, in *GetBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+	// GetBusinessCard returns any card the caller may see: their own, their
+	// reports' and, for HR, every card.
+	GetBusinessCard(ctx context.Context, in *GetBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+	ApproveBusinessCard(ctx context.Context, in *ApproveBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+	RejectBusinessCard(ctx context.Context, in *RejectBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+	PublishBusinessCard(ctx context.Context, in *PublishBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error)
+}
+
+type cardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCardServiceClient(cc grpc.ClientConnInterface) CardServiceClient {
+	return &cardServiceClient{cc}
+}
+
+func (c *cardServiceClient) CreateBusinessCard(ctx context.Context, in *BusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_CreateBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) ListMyBusinessCards(ctx context.Context, in *ListMyBusinessCardsRequest, opts ...grpc.CallOption) (*ListBusinessCardsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBusinessCardsResponse)
+	err := c.cc.Invoke(ctx, CardService_ListMyBusinessCards_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) GetMyBusinessCard(ctx context.Context, in *GetBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_GetMyBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) GetBusinessCard(ctx context.Context, in *GetBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_GetBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) ApproveBusinessCard(ctx context.Context, in *ApproveBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_ApproveBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) RejectBusinessCard(ctx context.Context, in *RejectBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_RejectBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cardServiceClient) PublishBusinessCard(ctx context.Context, in *PublishBusinessCardRequest, opts ...grpc.CallOption) (*BusinessCardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BusinessCardResponse)
+	err := c.cc.Invoke(ctx, CardService_PublishBusinessCard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CardServiceServer is the server API for CardService service.
+// All implementations must embed UnimplementedCardServiceServer
+// for forward compatibility.
+//
+// CardService serves business cards through the same service layer as the
+// HTTP API.
+type CardServiceServer interface {
+	CreateBusinessCard(context.Context, *BusinessCardRequest) (*BusinessCardResponse, error)
+	ListMyBusinessCards(context.Context, *ListMyBusinessCardsRequest) (*ListBusinessCardsResponse, error)
+	GetMyBusinessCard(context.Context, *GetBusinessCardRequest) (*BusinessCardResponse, error)
+	// GetBusinessCard returns any card the caller may see: their own, their
+	// reports' and, for HR, every card.
+	GetBusinessCard(context.Context, *GetBusinessCardRequest) (*BusinessCardResponse, error)
+	ApproveBusinessCard(context.Context, *ApproveBusinessCardRequest) (*BusinessCardResponse, error)
+	RejectBusinessCard(context.Context, *RejectBusinessCardRequest) (*BusinessCardResponse, error)
+	PublishBusinessCard(context.Context, *PublishBusinessCardRequest) (*BusinessCardResponse, error)
+	mustEmbedUnimplementedCardServiceServer()
+}
+
+// UnimplementedCardServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCardServiceServer struct{}
+
+func (UnimplementedCardServiceServer) CreateBusinessCard(context.Context, *BusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) ListMyBusinessCards(context.Context, *ListMyBusinessCardsRequest) (*ListBusinessCardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyBusinessCards not implemented")
+}
+func (UnimplementedCardServiceServer) GetMyBusinessCard(context.Context, *GetBusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMyBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) GetBusinessCard(context.Context, *GetBusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) ApproveBusinessCard(context.Context, *ApproveBusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) RejectBusinessCard(context.Context, *RejectBusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) PublishBusinessCard(context.Context, *PublishBusinessCardRequest) (*BusinessCardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishBusinessCard not implemented")
+}
+func (UnimplementedCardServiceServer) mustEmbedUnimplementedCardServiceServer() {}
+func (UnimplementedCardServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeCardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CardServiceServer will
+// result in compilation errors.
+type UnsafeCardServiceServer interface {
+	mustEmbedUnimplementedCardServiceServer()
+}
+
+func RegisterCardServiceServer(s grpc.ServiceRegistrar, srv CardServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCardServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CardService_ServiceDesc, srv)
+}
+
+func _CardService_CreateBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).CreateBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_CreateBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).CreateBusinessCard(ctx, req.(*BusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_ListMyBusinessCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyBusinessCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).ListMyBusinessCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_ListMyBusinessCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).ListMyBusinessCards(ctx, req.(*ListMyBusinessCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_GetMyBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).GetMyBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_GetMyBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).GetMyBusinessCard(ctx, req.(*GetBusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_GetBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).GetBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_GetBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).GetBusinessCard(ctx, req.(*GetBusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_ApproveBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveBusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).ApproveBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_ApproveBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).ApproveBusinessCard(ctx, req.(*ApproveBusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_RejectBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectBusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).RejectBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_RejectBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).RejectBusinessCard(ctx, req.(*RejectBusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CardService_PublishBusinessCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishBusinessCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CardServiceServer).PublishBusinessCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CardService_PublishBusinessCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CardServiceServer).PublishBusinessCard(ctx, req.(*PublishBusinessCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CardService_ServiceDesc is the grpc.ServiceDesc for CardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "contactqr.v1.CardService",
+	HandlerType: (*CardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateBusinessCard",
+			Handler:    _CardService_CreateBusinessCard_Handler,
+		},
+		{
+			MethodName: "ListMyBusinessCards",
+			Handler:    _CardService_ListMyBusinessCards_Handler,
+		},
+		{
+			MethodName: "GetMyBusinessCard",
+			Handler:    _CardService_GetMyBusinessCard_Handler,
+		},
+		{
+			MethodName: "GetBusinessCard",
+			Handler:    _CardService_GetBusinessCard_Handler,
+		},
+		{
+			MethodName: "ApproveBusinessCard",
+			Handler:    _CardService_ApproveBusinessCard_Handler,
+		},
+		{
+			MethodName: "RejectBusinessCard",
+			Handler:    _CardService_RejectBusinessCard_Handler,
+		},
+		{
+			MethodName: "PublishBusinessCard",
+			Handler:    _CardService_PublishBusinessCard_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "contactqr/v1/business_card.proto",
+}
@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: contactqr/v1/employee.proto
+
+package contactqr
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PreferencesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// en, lo or th; regional variants such as en-US are accepted.
+	NotificationLanguage string `protobuf:"bytes,1,opt,name=notification_language,json=notificationLanguage,proto3" json:"notification_language,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PreferencesRequest) Reset() {
+	*x = PreferencesRequest{}
+	mi := &file_contactqr_v1_employee_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreferencesRequest) ProtoMessage() {}
+
+func (x *PreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_employee_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreferencesRequest.ProtoReflect.Descriptor instead.
+func (*PreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_employee_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PreferencesRequest) GetNotificationLanguage() string {
+	if x != nil {
+		return x.NotificationLanguage
+	}
+	return ""
+}
+
+type DeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Platform      string                 `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceRequest) Reset() {
+	*x = DeviceRequest{}
+	mi := &file_contactqr_v1_employee_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceRequest) ProtoMessage() {}
+
+func (x *DeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_employee_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceRequest.ProtoReflect.Descriptor instead.
+func (*DeviceRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_employee_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeviceRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeviceRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+type Preferences struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	NotificationLanguage string                 `protobuf:"bytes,1,opt,name=notification_language,json=notificationLanguage,proto3" json:"notification_language,omitempty"`
+	UpdateTime           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Preferences) Reset() {
+	*x = Preferences{}
+	mi := &file_contactqr_v1_employee_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Preferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Preferences) ProtoMessage() {}
+
+func (x *Preferences) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_employee_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Preferences.ProtoReflect.Descriptor instead.
+func (*Preferences) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_employee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Preferences) GetNotificationLanguage() string {
+	if x != nil {
+		return x.NotificationLanguage
+	}
+	return ""
+}
+
+func (x *Preferences) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+var File_contactqr_v1_employee_proto protoreflect.FileDescriptor
+
+const file_contactqr_v1_employee_proto_rawDesc = "" +
+	"\n" +
+	"\x1bcontactqr/v1/employee.proto\x12\fcontactqr.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"Q\n" +
+	"\x12PreferencesRequest\x12;\n" +
+	"\x15notification_language\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x14notificationLanguage\"\xa4\x01\n" +
+	"\rDeviceRequest\x12\x1c\n" +
+	"\x05token\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x05token\x12u\n" +
+	"\bplatform\x18\x02 \x01(\tBY\xbaHV\xba\x01S\n" +
+	"\x14UNSUPPORTED_PLATFORM\x12\x1fplatform must be android or ios\x1a\x1athis in ['android', 'ios']R\bplatform\"\x7f\n" +
+	"\vPreferences\x123\n" +
+	"\x15notification_language\x18\x01 \x01(\tR\x14notificationLanguage\x12;\n" +
+	"\vupdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"updateTime2\xfb\x01\n" +
+	"\x0fEmployeeService\x12k\n" +
+	"\x10GetMyPreferences\x12\x16.google.protobuf.Empty\x1a\x19.contactqr.v1.Preferences\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/employees/me/preferences\x12{\n" +
+	"\x13UpdateMyPreferences\x12 .contactqr.v1.PreferencesRequest\x1a\x19.contactqr.v1.Preferences\"'\x82\xd3\xe4\x93\x02!:\x01*\x1a\x1c/v1/employees/me/preferencesBBZ@github.com/10664kls/contactqr/genproto/go/contactqr/v1;contactqrb\x06proto3"
+
+var (
+	file_contactqr_v1_employee_proto_rawDescOnce sync.Once
+	file_contactqr_v1_employee_proto_rawDescData []byte
+)
+
+func file_contactqr_v1_employee_proto_rawDescGZIP() []byte {
+	file_contactqr_v1_employee_proto_rawDescOnce.Do(func() {
+		file_contactqr_v1_employee_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contactqr_v1_employee_proto_rawDesc), len(file_contactqr_v1_employee_proto_rawDesc)))
+	})
+	return file_contactqr_v1_employee_proto_rawDescData
+}
+
+var file_contactqr_v1_employee_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_contactqr_v1_employee_proto_goTypes = []any{
+	(*PreferencesRequest)(nil),    // 0: contactqr.v1.PreferencesRequest
+	(*DeviceRequest)(nil),         // 1: contactqr.v1.DeviceRequest
+	(*Preferences)(nil),           // 2: contactqr.v1.Preferences
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 4: google.protobuf.Empty
+}
+var file_contactqr_v1_employee_proto_depIdxs = []int32{
+	3, // 0: contactqr.v1.Preferences.update_time:type_name -> google.protobuf.Timestamp
+	4, // 1: contactqr.v1.EmployeeService.GetMyPreferences:input_type -> google.protobuf.Empty
+	0, // 2: contactqr.v1.EmployeeService.UpdateMyPreferences:input_type -> contactqr.v1.PreferencesRequest
+	2, // 3: contactqr.v1.EmployeeService.GetMyPreferences:output_type -> contactqr.v1.Preferences
+	2, // 4: contactqr.v1.EmployeeService.UpdateMyPreferences:output_type -> contactqr.v1.Preferences
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_contactqr_v1_employee_proto_init() }
+func file_contactqr_v1_employee_proto_init() {
+	if File_contactqr_v1_employee_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contactqr_v1_employee_proto_rawDesc), len(file_contactqr_v1_employee_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contactqr_v1_employee_proto_goTypes,
+		DependencyIndexes: file_contactqr_v1_employee_proto_depIdxs,
+		MessageInfos:      file_contactqr_v1_employee_proto_msgTypes,
+	}.Build()
+	File_contactqr_v1_employee_proto = out.File
+	file_contactqr_v1_employee_proto_goTypes = nil
+	file_contactqr_v1_employee_proto_depIdxs = nil
+}
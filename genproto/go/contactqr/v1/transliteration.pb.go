@@ -0,0 +1,181 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: contactqr/v1/transliteration.proto
+
+package contactqr
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SuggestTransliterationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestTransliterationRequest) Reset() {
+	*x = SuggestTransliterationRequest{}
+	mi := &file_contactqr_v1_transliteration_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestTransliterationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTransliterationRequest) ProtoMessage() {}
+
+func (x *SuggestTransliterationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_transliteration_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTransliterationRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTransliterationRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_transliteration_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SuggestTransliterationRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// An override maps one Lao word to its Latin spelling; each is a single
+// word of its own script.
+type TransliterationOverrideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lao           string                 `protobuf:"bytes,1,opt,name=lao,proto3" json:"lao,omitempty"`
+	Latin         string                 `protobuf:"bytes,2,opt,name=latin,proto3" json:"latin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransliterationOverrideRequest) Reset() {
+	*x = TransliterationOverrideRequest{}
+	mi := &file_contactqr_v1_transliteration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransliterationOverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransliterationOverrideRequest) ProtoMessage() {}
+
+func (x *TransliterationOverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contactqr_v1_transliteration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransliterationOverrideRequest.ProtoReflect.Descriptor instead.
+func (*TransliterationOverrideRequest) Descriptor() ([]byte, []int) {
+	return file_contactqr_v1_transliteration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransliterationOverrideRequest) GetLao() string {
+	if x != nil {
+		return x.Lao
+	}
+	return ""
+}
+
+func (x *TransliterationOverrideRequest) GetLatin() string {
+	if x != nil {
+		return x.Latin
+	}
+	return ""
+}
+
+var File_contactqr_v1_transliteration_proto protoreflect.FileDescriptor
+
+const file_contactqr_v1_transliteration_proto_rawDesc = "" +
+	"\n" +
+	"\"contactqr/v1/transliteration.proto\x12\fcontactqr.v1\x1a\x1bbuf/validate/validate.proto\";\n" +
+	"\x1dSuggestTransliterationRequest\x12\x1a\n" +
+	"\x04text\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x04text\"X\n" +
+	"\x1eTransliterationOverrideRequest\x12\x18\n" +
+	"\x03lao\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x03lao\x12\x1c\n" +
+	"\x05latin\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x05latinBBZ@github.com/10664kls/contactqr/genproto/go/contactqr/v1;contactqrb\x06proto3"
+
+var (
+	file_contactqr_v1_transliteration_proto_rawDescOnce sync.Once
+	file_contactqr_v1_transliteration_proto_rawDescData []byte
+)
+
+func file_contactqr_v1_transliteration_proto_rawDescGZIP() []byte {
+	file_contactqr_v1_transliteration_proto_rawDescOnce.Do(func() {
+		file_contactqr_v1_transliteration_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contactqr_v1_transliteration_proto_rawDesc), len(file_contactqr_v1_transliteration_proto_rawDesc)))
+	})
+	return file_contactqr_v1_transliteration_proto_rawDescData
+}
+
+var file_contactqr_v1_transliteration_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_contactqr_v1_transliteration_proto_goTypes = []any{
+	(*SuggestTransliterationRequest)(nil),  // 0: contactqr.v1.SuggestTransliterationRequest
+	(*TransliterationOverrideRequest)(nil), // 1: contactqr.v1.TransliterationOverrideRequest
+}
+var file_contactqr_v1_transliteration_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_contactqr_v1_transliteration_proto_init() }
+func file_contactqr_v1_transliteration_proto_init() {
+	if File_contactqr_v1_transliteration_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contactqr_v1_transliteration_proto_rawDesc), len(file_contactqr_v1_transliteration_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_contactqr_v1_transliteration_proto_goTypes,
+		DependencyIndexes: file_contactqr_v1_transliteration_proto_depIdxs,
+		MessageInfos:      file_contactqr_v1_transliteration_proto_msgTypes,
+	}.Build()
+	File_contactqr_v1_transliteration_proto = out.File
+	file_contactqr_v1_transliteration_proto_goTypes = nil
+	file_contactqr_v1_transliteration_proto_depIdxs = nil
+}
@@ -0,0 +1,249 @@
+// Package address keeps the office address of each company, which the
+// cards of its employees carry and put in their vCard as ADR and GEO. HR
+// keeps them; a company has at most one.
+package address
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// Address is the office address of a company as cards carry it. Latitude
+// and longitude are both set or both nil.
+type Address struct {
+	CompanyID  int64    `json:"companyId"`
+	Street     string   `json:"street"`
+	Locality   string   `json:"locality"`
+	Region     string   `json:"region"`
+	PostalCode string   `json:"postalCode"`
+	Country    string   `json:"country"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+
+// HasGeo reports whether the address has its coordinates.
+func (a *Address) HasGeo() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
+
+func (a *Address) Proto() *contactqrPb.CompanyAddress {
+	return &contactqrPb.CompanyAddress{
+		CompanyId:  a.CompanyID,
+		Street:     a.Street,
+		Locality:   a.Locality,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Latitude:   a.Latitude,
+		Longitude:  a.Longitude,
+	}
+}
+
+// CompanyAddress is an address as HR keeps it, with who last changed it.
+type CompanyAddress struct {
+	Address
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Service struct {
+	db   *sql.DB
+	zlog *zap.Logger
+}
+
+func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Service{
+		db:   db,
+		zlog: zlog,
+	}, nil
+}
+
+type AddressReq struct {
+	CompanyID  int64    `json:"-" param:"id"`
+	Street     string   `json:"street"`
+	Locality   string   `json:"locality"`
+	Region     string   `json:"region"`
+	PostalCode string   `json:"postalCode"`
+	Country    string   `json:"country"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+func (r *AddressReq) Validate() error {
+	r.Street = strings.TrimSpace(r.Street)
+	r.Locality = strings.TrimSpace(r.Locality)
+	r.Region = strings.TrimSpace(r.Region)
+	r.PostalCode = strings.TrimSpace(r.PostalCode)
+	r.Country = strings.TrimSpace(r.Country)
+
+	violations, err := validate.Violations(&contactqrPb.CompanyAddressRequest{
+		CompanyId:  r.CompanyID,
+		Street:     r.Street,
+		Locality:   r.Locality,
+		Region:     r.Region,
+		PostalCode: r.PostalCode,
+		Country:    r.Country,
+		Latitude:   r.Latitude,
+		Longitude:  r.Longitude,
+	})
+	if err != nil {
+		return err
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		field := "latitude"
+		if r.Latitude != nil {
+			field = "longitude"
+		}
+		violations = append(violations, i18n.Violation(field, i18n.InvalidGeo))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidAddress).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+type ListAddressesResult struct {
+	Addresses []*CompanyAddress `json:"addresses"`
+}
+
+// ListAddresses lists the addresses by company. It is for HR only.
+func (s *Service) ListAddresses(ctx context.Context) (*ListAddressesResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListAddresses"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageAddresses) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AddressesForbidden)
+	}
+
+	addresses, err := listAddresses(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list addresses", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListAddressesResult{Addresses: addresses}, nil
+}
+
+// GetAddress returns the address of a company. It is for HR only.
+func (s *Service) GetAddress(ctx context.Context, companyID int64) (*CompanyAddress, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetAddress"),
+		zap.String("username", claims.Code),
+		zap.Int64("company_id", companyID),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageAddresses) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AddressesForbidden)
+	}
+
+	a, err := getAddress(ctx, s.db, companyID)
+	if errors.Is(err, ErrAddressNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.AddressNotFound, "companyId", strconv.FormatInt(companyID, 10))
+	}
+	if err != nil {
+		zlog.Error("failed to get address", zap.Error(err))
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// SaveAddress sets the address of a company, replacing the one it had. It
+// is for HR only.
+func (s *Service) SaveAddress(ctx context.Context, in *AddressReq) (*CompanyAddress, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SaveAddress"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageAddresses) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AddressesForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &CompanyAddress{
+		Address: Address{
+			CompanyID:  in.CompanyID,
+			Street:     in.Street,
+			Locality:   in.Locality,
+			Region:     in.Region,
+			PostalCode: in.PostalCode,
+			Country:    in.Country,
+			Latitude:   in.Latitude,
+			Longitude:  in.Longitude,
+		},
+		UpdatedBy: claims.Code,
+		UpdatedAt: time.Now(),
+	}
+	if err := saveAddress(ctx, s.db, a); err != nil {
+		zlog.Error("failed to save address", zap.Error(err))
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DeleteAddress removes the address of a company, which its cards stop
+// carrying. It is for HR only.
+func (s *Service) DeleteAddress(ctx context.Context, companyID int64) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "DeleteAddress"),
+		zap.String("username", claims.Code),
+		zap.Int64("company_id", companyID),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageAddresses) {
+		return i18n.Error(codes.PermissionDenied, i18n.AddressesForbidden)
+	}
+
+	err := deleteAddress(ctx, s.db, companyID)
+	if errors.Is(err, ErrAddressNotFound) {
+		return i18n.Error(codes.NotFound, i18n.AddressNotFound, "companyId", strconv.FormatInt(companyID, 10))
+	}
+	if err != nil {
+		zlog.Error("failed to delete address", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
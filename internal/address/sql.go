@@ -0,0 +1,150 @@
+package address
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	sq "github.com/Masterminds/squirrel"
+)
+
+func selectAddresses() sq.SelectBuilder {
+	return sq.
+		Select(
+			"company_id",
+			"street",
+			"locality",
+			"region",
+			"postal_code",
+			"country",
+			"latitude",
+			"longitude",
+			"updated_by",
+			"updated_at",
+		).
+		From("dbo.company_address")
+}
+
+func scanAddress(row interface{ Scan(...any) error }) (*CompanyAddress, error) {
+	var a CompanyAddress
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(
+		&a.CompanyID,
+		&a.Street,
+		&a.Locality,
+		&a.Region,
+		&a.PostalCode,
+		&a.Country,
+		&latitude,
+		&longitude,
+		&a.UpdatedBy,
+		&a.UpdatedAt,
+	)
+	if latitude.Valid && longitude.Valid {
+		a.Latitude = &latitude.Float64
+		a.Longitude = &longitude.Float64
+	}
+	return &a, err
+}
+
+func listAddresses(ctx context.Context, db *sql.DB) ([]*CompanyAddress, error) {
+	q, args := selectAddresses().
+		OrderBy("company_id").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := make([]*CompanyAddress, 0)
+	for rows.Next() {
+		a, err := scanAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		addresses = append(addresses, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+func getAddress(ctx context.Context, db *sql.DB, companyID int64) (*CompanyAddress, error) {
+	q, args := selectAddresses().
+		Where(sq.Eq{"company_id": companyID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	a, err := scanAddress(db.QueryRowContext(ctx, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return a, nil
+}
+
+func saveAddress(ctx context.Context, db *sql.DB, in *CompanyAddress) error {
+	q := `
+MERGE dbo.company_address WITH (HOLDLOCK) AS t
+USING (SELECT @p1 AS company_id) AS s ON t.company_id = s.company_id
+WHEN MATCHED THEN UPDATE SET street = @p2, locality = @p3, region = @p4, postal_code = @p5, country = @p6,
+	latitude = @p7, longitude = @p8, updated_by = @p9, updated_at = @p10
+WHEN NOT MATCHED THEN INSERT (company_id, street, locality, region, postal_code, country, latitude, longitude, updated_by, updated_at)
+	VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10);`
+
+	var latitude, longitude sql.NullFloat64
+	if in.HasGeo() {
+		latitude = sql.NullFloat64{Float64: *in.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: *in.Longitude, Valid: true}
+	}
+
+	if _, err := db.ExecContext(ctx, q,
+		in.CompanyID,
+		in.Street,
+		in.Locality,
+		in.Region,
+		in.PostalCode,
+		in.Country,
+		latitude,
+		longitude,
+		in.UpdatedBy,
+		pager.DateTime(in.UpdatedAt),
+	); err != nil {
+		return fmt.Errorf("failed to execute save address: %w", err)
+	}
+
+	return nil
+}
+
+func deleteAddress(ctx context.Context, db *sql.DB, companyID int64) error {
+	q, args := sq.
+		Delete("dbo.company_address").
+		Where(sq.Eq{"company_id": companyID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrAddressNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	RuleForbidden   = "forbidden"
+	RuleVCFBurst    = "vcf_burst"
+	RuleOffHoursHR  = "off_hours_hr_login"
+	maxTrackedKeys  = 10000
+	notifyTimeout   = 10 * time.Second
+	vcfDownloadPath = "/v1/public/business-cards/:id/vcf"
+)
+
+// Rule raises an alert when Threshold events for the same key happen within
+// Window.
+type Rule struct {
+	Threshold int
+	Window    time.Duration
+}
+
+type Config struct {
+	Rules map[string]Rule
+
+	// OfficeStart and OfficeEnd bound office hours as offsets from midnight
+	// in Location. HR logins outside them raise an alert.
+	OfficeStart time.Duration
+	OfficeEnd   time.Duration
+	Location    *time.Location
+
+	Recipients []string
+}
+
+// Detector counts security relevant events and notifies Recipients when a
+// rule's threshold is crossed.
+type Detector struct {
+	notifier notify.Notifier
+	zlog     *zap.Logger
+	config   Config
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+type window struct {
+	rule    string
+	start   time.Time
+	count   int
+	alerted bool
+}
+
+func NewDetector(_ context.Context, notifier notify.Notifier, zlog *zap.Logger, config Config) (*Detector, error) {
+	if notifier == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+
+	return &Detector{
+		notifier: notifier,
+		zlog:     zlog,
+		config:   config,
+		windows:  make(map[string]*window),
+	}, nil
+}
+
+// Observe records an event for rule and key, alerting once per window when
+// the rule's threshold is reached.
+func (d *Detector) Observe(rule, key string) {
+	r, ok := d.config.Rules[rule]
+	if !ok || r.Threshold <= 0 {
+		return
+	}
+
+	now := time.Now()
+	id := rule + "|" + key
+
+	d.mu.Lock()
+	w, ok := d.windows[id]
+	if !ok || now.Sub(w.start) > r.Window {
+		if len(d.windows) >= maxTrackedKeys {
+			d.pruneLocked(now)
+		}
+		w = &window{rule: rule, start: now}
+		d.windows[id] = w
+	}
+	w.count++
+	fire := w.count >= r.Threshold && !w.alerted
+	if fire {
+		w.alerted = true
+	}
+	count := w.count
+	d.mu.Unlock()
+
+	if fire {
+		d.alert(rule, fmt.Sprintf("%d events for %q within %s.", count, key, r.Window))
+	}
+}
+
+// ObserveLogin alerts when an HR account logs in outside office hours.
+func (d *Detector) ObserveLogin(username string, isHR bool, at time.Time) {
+	if !isHR || d.config.OfficeEnd <= d.config.OfficeStart {
+		return
+	}
+
+	local := at.In(d.config.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, d.config.Location)
+	offset := local.Sub(midnight)
+	if offset >= d.config.OfficeStart && offset < d.config.OfficeEnd {
+		return
+	}
+
+	d.alert(RuleOffHoursHR, fmt.Sprintf("HR account %q logged in at %s.", username, local.Format(time.RFC3339)))
+}
+
+// Middleware observes forbidden responses and VCF downloads per client IP.
+// It must run outside the error handling middleware so the final response
+// status is known.
+func (d *Detector) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			status := c.Response().Status
+			switch {
+			case status == http.StatusForbidden:
+				d.Observe(RuleForbidden, c.RealIP())
+
+			case status == http.StatusOK && c.Path() == vcfDownloadPath:
+				d.Observe(RuleVCFBurst, c.RealIP())
+			}
+
+			return err
+		}
+	}
+}
+
+func (d *Detector) alert(rule, detail string) {
+	d.zlog.Warn("suspicious activity detected",
+		zap.String("rule", rule),
+		zap.String("detail", detail),
+	)
+
+	if len(d.config.Recipients) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		err := d.notifier.Notify(ctx, &notify.Message{
+			To:      d.config.Recipients,
+			Subject: fmt.Sprintf("[contactqr] Suspicious activity: %s", rule),
+			Body:    detail,
+		})
+		if err != nil {
+			d.zlog.Error("failed to send alert", zap.String("rule", rule), zap.Error(err))
+		}
+	}()
+}
+
+func (d *Detector) pruneLocked(now time.Time) {
+	for id, w := range d.windows {
+		if now.Sub(w.start) > d.config.Rules[w.rule].Window {
+			delete(d.windows, id)
+		}
+	}
+}
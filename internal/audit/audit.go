@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// Log records card status transitions as a hash chain: every entry stores
+// the hash of the previous one, so editing or deleting a row in the database
+// breaks every hash after it.
+type Log struct {
+	db   *sql.DB
+	zlog *zap.Logger
+}
+
+func NewLog(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Log, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Log{
+		db:   db,
+		zlog: zlog,
+	}, nil
+}
+
+type Entry struct {
+	ID         int64     `json:"id"`
+	CardID     string    `json:"cardId"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Remark     string    `json:"remark"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"createdAt"`
+	PrevHash   string    `json:"prevHash"`
+	Hash       string    `json:"hash"`
+}
+
+// computeHash returns the hash of the entry chained to its PrevHash.
+func (e *Entry) computeHash() string {
+	h := sha256.New()
+	for _, f := range []string{
+		e.PrevHash,
+		e.CardID,
+		e.FromStatus,
+		e.ToStatus,
+		e.Remark,
+		e.Actor,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(strconv.Itoa(len(f))))
+		h.Write([]byte{':'})
+		h.Write([]byte(f))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Record appends e to the chain within tx, so the entry is only kept when
+// the change it describes is committed.
+func (l *Log) Record(ctx context.Context, tx *sql.Tx, e *Entry) error {
+	prev, err := lastHash(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Stored as DATETIME2(3); truncating keeps the hash reproducible.
+	e.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	e.PrevHash = prev
+	e.Hash = e.computeHash()
+
+	return createEntry(ctx, tx, e)
+}
+
+type Verification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int64  `json:"entries"`
+	Anchors  int64  `json:"anchors"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAuditLog walks the whole chain and checks every hash and anchor.
+func (l *Log) VerifyAuditLog(ctx context.Context) (*Verification, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := l.zlog.With(
+		zap.String("method", "VerifyAuditLog"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAudit) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AuditForbidden)
+	}
+
+	anchors, err := listAnchors(ctx, l.db)
+	if err != nil {
+		zlog.Error("failed to list anchors", zap.Error(err))
+		return nil, err
+	}
+
+	v := &Verification{Valid: true}
+	var prev string
+	err = iterEntries(ctx, l.db, sq.And{}, func(e *Entry) error {
+		v.Entries++
+
+		switch {
+		case e.PrevHash != prev:
+			v.Reason = "previous hash does not match"
+
+		case e.computeHash() != e.Hash:
+			v.Reason = "entry hash does not match its content"
+
+		case anchors[e.ID] != "" && anchors[e.ID] != e.Hash:
+			v.Reason = "entry hash does not match its anchor"
+		}
+		if v.Reason != "" {
+			v.Valid = false
+			v.BrokenAt = e.ID
+			return errStop
+		}
+
+		if anchors[e.ID] != "" {
+			v.Anchors++
+		}
+		prev = e.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		zlog.Error("failed to verify audit log", zap.Error(err))
+		return nil, err
+	}
+
+	if v.Valid && v.Anchors < int64(len(anchors)) {
+		v.Valid = false
+		v.Reason = "anchored entries are missing from the log"
+	}
+
+	return v, nil
+}
+
+var errStop = errors.New("stop iteration")
+
+// ListCardEntries lists the entries of one card, oldest first. Callers
+// check the caller may see the card.
+func (l *Log) ListCardEntries(ctx context.Context, cardID string) ([]*Entry, error) {
+	entries := make([]*Entry, 0)
+	err := iterEntries(ctx, l.db, sq.Eq{"card_id": cardID}, func(e *Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExportEntries writes the entries recorded in [from, to) as
+// newline-delimited JSON for BI exports, in chain order. A zero from
+// exports every entry before to.
+func (l *Log) ExportEntries(ctx context.Context, w io.Writer, from, to time.Time) error {
+	// Entries are stored in UTC.
+	and := sq.And{
+		sq.Lt{"created_at": to.UTC()},
+	}
+	if !from.IsZero() {
+		and = append(and, sq.GtOrEq{"created_at": from.UTC()})
+	}
+
+	enc := json.NewEncoder(w)
+	return iterEntries(ctx, l.db, and, func(e *Entry) error {
+		return enc.Encode(e)
+	})
+}
+
+// Anchor records the current head of the chain. Anchors are also written to
+// the application log so they survive outside the database.
+func (l *Log) Anchor(ctx context.Context) error {
+	id, hash, err := createAnchor(ctx, l.db)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		return nil
+	}
+
+	l.zlog.Info("anchored audit log",
+		zap.Int64("entry_id", id),
+		zap.String("hash", hash),
+	)
+	return nil
+}
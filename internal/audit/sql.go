@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func lastHash(ctx context.Context, tx *sql.Tx) (string, error) {
+	// UPDLOCK/HOLDLOCK serializes writers so two entries never share a
+	// previous hash.
+	q, args := sq.
+		Select("TOP 1 hash").
+		From("dbo.business_card_history WITH (UPDLOCK, HOLDLOCK)").
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var hash string
+	err := tx.QueryRowContext(ctx, q, args...).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return hash, nil
+}
+
+func createEntry(ctx context.Context, tx *sql.Tx, in *Entry) error {
+	q, args := sq.
+		Insert("dbo.business_card_history").
+		Columns(
+			"card_id",
+			"from_status",
+			"to_status",
+			"remark",
+			"actor",
+			"created_at",
+			"prev_hash",
+			"hash",
+		).
+		Values(
+			in.CardID,
+			in.FromStatus,
+			in.ToStatus,
+			in.Remark,
+			in.Actor,
+			in.CreatedAt,
+			in.PrevHash,
+			in.Hash,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if err := tx.QueryRowContext(ctx, q, args...).Scan(&in.ID); err != nil {
+		return fmt.Errorf("failed to execute create history: %w", err)
+	}
+
+	return nil
+}
+
+// iterEntries calls fn for every entry matching pred, in chain order.
+func iterEntries(ctx context.Context, db *sql.DB, pred sq.Sqlizer, fn func(*Entry) error) error {
+	q, args := sq.
+		Select(
+			"id",
+			"card_id",
+			"from_status",
+			"to_status",
+			"remark",
+			"actor",
+			"created_at",
+			"prev_hash",
+			"hash",
+		).
+		From("dbo.business_card_history").
+		Where(pred).
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(
+			&e.ID,
+			&e.CardID,
+			&e.FromStatus,
+			&e.ToStatus,
+			&e.Remark,
+			&e.Actor,
+			&e.CreatedAt,
+			&e.PrevHash,
+			&e.Hash,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+func listAnchors(ctx context.Context, db *sql.DB) (map[int64]string, error) {
+	q, args := sq.
+		Select(
+			"history_id",
+			"hash",
+		).
+		From("dbo.business_card_history_anchor").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	anchors := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		anchors[id] = hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return anchors, nil
+}
+
+// createAnchor stores the head of the chain unless it is already anchored.
+// It returns the anchored entry, or a zero id when there was nothing new.
+func createAnchor(ctx context.Context, db *sql.DB) (int64, string, error) {
+	q := `INSERT INTO dbo.business_card_history_anchor (history_id, hash)
+OUTPUT INSERTED.history_id, INSERTED.hash
+SELECT TOP 1 h.id, h.hash
+FROM dbo.business_card_history AS h
+WHERE NOT EXISTS (
+  SELECT 1 FROM dbo.business_card_history_anchor AS a WHERE a.history_id = h.id
+)
+ORDER BY h.id DESC`
+
+	var id int64
+	var hash string
+	err := db.QueryRowContext(ctx, q).Scan(&id, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to execute create anchor: %w", err)
+	}
+
+	return id, hash, nil
+}
@@ -9,41 +9,84 @@ import (
 	"time"
 
 	"aidanwoods.dev/go-paseto"
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pii"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
 	sq "github.com/Masterminds/squirrel"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
-	rpcStatus "google.golang.org/grpc/status"
 )
 
 var ErrUserNotFound = errors.New("user not found")
 
 type Auth struct {
-	db   *sql.DB
-	aKey paseto.V4SymmetricKey
-	rKey paseto.V4SymmetricKey
-	zlog *zap.Logger
+	db       *sql.DB
+	aKeys    *KeyRing
+	rKeys    *KeyRing
+	ttl      TTL
+	zlog     *zap.Logger
+	observer LoginObserver
+	sessions *Sessions
+	dir      Directory
+	lockout  Lockout
 }
 
-func NewAuth(_ context.Context, db *sql.DB, aKey, rKey paseto.V4SymmetricKey, zlog *zap.Logger) (*Auth, error) {
+// LoginObserver is told about every successful login.
+type LoginObserver interface {
+	ObserveLogin(username string, isHR bool, at time.Time)
+}
+
+// TTL is how long the tokens Auth issues are valid.
+type TTL struct {
+	Access  time.Duration
+	Refresh time.Duration
+}
+
+func NewAuth(_ context.Context, db *sql.DB, aKeys, rKeys *KeyRing, ttl TTL, zlog *zap.Logger, observer LoginObserver, sessions *Sessions, dir Directory, lockout Lockout) (*Auth, error) {
 	if db == nil {
 		return nil, errors.New("db is nil")
 	}
+	if aKeys == nil || rKeys == nil {
+		return nil, errors.New("keys are nil")
+	}
+	if ttl.Access <= 0 || ttl.Refresh <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
 	if zlog == nil {
 		return nil, errors.New("zlog is nil")
 	}
+	if observer == nil {
+		return nil, errors.New("observer is nil")
+	}
+	if sessions == nil {
+		return nil, errors.New("sessions is nil")
+	}
+	if dir == nil {
+		return nil, errors.New("dir is nil")
+	}
+	if lockout.Threshold <= 0 || lockout.Base <= 0 || lockout.Max < lockout.Base || lockout.Window <= 0 {
+		return nil, errors.New("lockout is not valid")
+	}
 
 	return &Auth{
-		db:   db,
-		aKey: aKey,
-		rKey: rKey,
-		zlog: zlog,
+		db:       db,
+		aKeys:    aKeys,
+		rKeys:    rKeys,
+		ttl:      ttl,
+		zlog:     zlog,
+		observer: observer,
+		sessions: sessions,
+		dir:      dir,
+		lockout:  lockout,
 	}, nil
 }
 
 func (s *Auth) Profile(ctx context.Context) (*User, error) {
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "Profile"),
 	)
 
@@ -51,7 +94,7 @@ func (s *Auth) Profile(ctx context.Context) (*User, error) {
 	user, err := getUserByUsername(ctx, s.db, claims.Code)
 	if errors.Is(err, ErrUserNotFound) {
 		zlog.Info("failed to get user", zap.Error(err))
-		return nil, rpcStatus.Error(codes.PermissionDenied, "Your are not allowed to access this user or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.UserNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get user", zap.Error(err))
@@ -61,8 +104,12 @@ func (s *Auth) Profile(ctx context.Context) (*User, error) {
 	return user, nil
 }
 
+// Login issues tokens to a user whose password the directory accepts or,
+// failing that, matches the one in the database. Either way the user must
+// have a login in the database, which their claims come from. Failed
+// logins lock the username as s's Lockout says.
 func (s *Auth) Login(ctx context.Context, in *LoginReq) (*Token, error) {
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "Login"),
 	)
 
@@ -70,26 +117,54 @@ func (s *Auth) Login(ctx context.Context, in *LoginReq) (*Token, error) {
 		return nil, err
 	}
 
+	lockedUntil, err := getLoginLock(ctx, s.db, attemptKey(in.Username))
+	if err != nil {
+		zlog.Error("failed to get login lock", zap.Error(err))
+		return nil, err
+	}
+	if now := time.Now(); lockedUntil.After(now) {
+		zlog.Info("login is locked", zap.Time("locked_until", lockedUntil))
+		return nil, lockedError(lockedUntil, now)
+	}
+
+	dirUser, err := s.dir.Authenticate(ctx, in.Username, in.Password)
+	if err != nil && !errors.Is(err, ErrDirectoryRejected) {
+		zlog.Warn("failed to authenticate against directory", zap.Error(err))
+	}
+
 	user, err := getUserByUsername(ctx, s.db, in.Username)
 	if errors.Is(err, ErrUserNotFound) {
 		zlog.Info("failed to get user", zap.Error(err))
-		return nil, rpcStatus.Error(codes.Unauthenticated, "Your credentials not valid. Please check your username and password and try again.")
+		return nil, s.failLogin(ctx, zlog, in.Username)
 	}
 	if err != nil {
 		zlog.Error("failed to get user", zap.Error(err))
 		return nil, err
 	}
 
-	if passed, err := user.Compare(in.Password); err != nil || !passed {
+	if dirUser != nil {
+		user.useDirectory(dirUser)
+	} else if passed, err := user.Compare(in.Password); err != nil || !passed {
 		zlog.Info("failed to compare password", zap.Error(err))
-		return nil, rpcStatus.Error(codes.Unauthenticated, "Your credentials not valid. Please check your username and password and try again.")
+		return nil, s.failLogin(ctx, zlog, in.Username)
+	}
+
+	if err := deleteLoginAttempts(ctx, s.db, attemptKey(in.Username)); err != nil {
+		zlog.Warn("failed to reset login failures", zap.Error(err))
+	}
+
+	session, err := s.sessions.start(ctx, user, in.client)
+	if err != nil {
+		zlog.Error("failed to start session", zap.Error(err))
+		return nil, err
 	}
 
-	token, err := s.genToken(user)
+	token, err := s.issueToken(ctx, user, session.id)
 	if err != nil {
 		zlog.Error("failed to generate token", zap.Error(err))
 		return nil, err
 	}
+	s.observer.ObserveLogin(user.Code, user.IsHR, time.Now())
 
 	return token, nil
 }
@@ -97,32 +172,29 @@ func (s *Auth) Login(ctx context.Context, in *LoginReq) (*Token, error) {
 type LoginReq struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+
+	client ClientInfo
 }
 
-func (r *LoginReq) Validate() error {
-	violations := make([]*edPb.BadRequest_FieldViolation, 0)
+// SetClient records where the login request came from.
+func (r *LoginReq) SetClient(client ClientInfo) {
+	r.client = client
+}
 
+func (r *LoginReq) Validate() error {
 	r.Username = strings.TrimSpace(r.Username)
-	if r.Username == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "username",
-			Description: "username must not be empty",
-		})
-	}
-
 	r.Password = strings.TrimSpace(r.Password)
-	if r.Password == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "password",
-			Description: "password must not be empty",
-		})
+
+	violations, err := validate.Violations(&contactqrPb.LoginRequest{
+		Username: r.Username,
+		Password: r.Password,
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(violations) > 0 {
-		s, _ := rpcStatus.New(
-			codes.InvalidArgument,
-			"Credentials are not valid or incomplete. Please check the errors and try again, see details for more information.",
-		).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidLogin).WithDetails(&edPb.BadRequest{FieldViolations: violations})
 		return s.Err()
 	}
 
@@ -134,7 +206,7 @@ type NewTokenReq struct {
 }
 
 func (s *Auth) RefreshToken(ctx context.Context, in *NewTokenReq) (*Token, error) {
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "RefreshToken"),
 		zap.Any("req", in),
 	)
@@ -145,29 +217,76 @@ func (s *Auth) RefreshToken(ctx context.Context, in *NewTokenReq) (*Token, error
 	}
 
 	parser := paseto.MakeParser(rules)
-	t, err := parser.ParseV4Local(s.rKey, in.Token, nil)
+	t, err := s.rKeys.Parse(parser, in.Token, nil)
 	if err != nil {
 		zlog.Info("failed to parse token", zap.Error(err))
-		return nil, rpcStatus.Error(codes.Unauthenticated, "Your credentials not valid. Please check your token and try again.")
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidRefreshToken)
 	}
 
 	claims := new(Claims)
 	if err := t.Get("profile", claims); err != nil {
 		zlog.Info("failed to get claims", zap.Error(err))
-		return nil, rpcStatus.Error(codes.Unauthenticated, "Your credentials not valid. Please check your token and try again.")
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidRefreshToken)
+	}
+
+	revoked, err := s.sessions.revoked(ctx, claims.SessionID)
+	if err != nil {
+		zlog.Error("failed to check session", zap.Error(err))
+		return nil, err
+	}
+	if revoked {
+		zlog.Info("session is revoked", zap.Int64("session_id", claims.SessionID))
+		return nil, i18n.Error(codes.Unauthenticated, i18n.TokenRevoked)
+	}
+
+	// Refresh tokens are used once. Tokens issued before they were tracked
+	// carry no jti and are no longer accepted.
+	jti, err := t.GetJti()
+	if err != nil || jti == "" {
+		zlog.Info("refresh token has no jti")
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidRefreshToken)
+	}
+	err = useRefreshToken(ctx, s.db, jti)
+	if errors.Is(err, ErrRefreshTokenNotFound) {
+		zlog.Info("refresh token not found", zap.String("jti", jti))
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidRefreshToken)
+	}
+	if errors.Is(err, ErrRefreshTokenUsed) {
+		// Someone holds a copy of the token: end the session for both.
+		zlog.Warn("refresh token reused, revoking session", zap.String("jti", jti), zap.Int64("session_id", claims.SessionID))
+		if err := revokeSession(ctx, s.db, claims.SessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			zlog.Error("failed to revoke session", zap.Error(err))
+			return nil, err
+		}
+		return nil, i18n.Error(codes.Unauthenticated, i18n.TokenRevoked)
+	}
+	if err != nil {
+		zlog.Error("failed to use refresh token", zap.Error(err))
+		return nil, err
 	}
 
 	u, err := getUserByUsername(ctx, s.db, claims.Code)
 	if errors.Is(err, ErrUserNotFound) {
 		zlog.Info("failed to get user by username", zap.Error(err))
-		return nil, rpcStatus.Error(codes.Unauthenticated, "Your credentials not valid. Please check your token and try again.")
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidRefreshToken)
 	}
 	if err != nil {
 		zlog.Error("failed to get user by username", zap.Error(err))
 		return nil, err
 	}
 
-	token, err := s.genToken(u)
+	// The directory is not asked again, its attributes are kept from the
+	// login.
+	if claims.Directory {
+		u.useDirectory(&DirectoryUser{
+			DisplayName: claims.DisplayName,
+			Email:       claims.Email,
+			Phone:       claims.Phone,
+			Mobile:      claims.Mobile,
+		})
+	}
+
+	token, err := s.issueToken(ctx, u, claims.SessionID)
 	if err != nil {
 		zlog.Error("failed to generate token", zap.Error(err))
 		return nil, err
@@ -181,14 +300,14 @@ type Token struct {
 	Refresh string `json:"refreshToken"`
 }
 
-func (s *Auth) genToken(u *User) (*Token, error) {
-	now := time.Now()
+func (s *Auth) genToken(u *User, rt *refreshToken) (*Token, error) {
+	now := rt.issuedAt
 
 	t := paseto.NewToken()
 	t.SetSubject(u.Code)
 	t.SetIssuedAt(now)
 	t.SetNotBefore(now)
-	t.SetExpiration(now.Add(time.Hour))
+	t.SetExpiration(now.Add(s.ttl.Access))
 	t.SetFooter([]byte(now.Format(time.RFC3339)))
 
 	if err := t.Set("profile", &Claims{
@@ -203,14 +322,17 @@ func (s *Auth) genToken(u *User) (*Token, error) {
 		Phone:        u.phone,
 		Mobile:       u.mobile,
 		IsHR:         u.IsHR,
+		SessionID:    rt.sessionID,
+		Directory:    u.directory,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to set claims: %w", err)
 	}
 
-	accessToken := t.V4Encrypt(s.aKey, nil)
+	accessToken := s.aKeys.Encrypt(&t)
 
-	t.SetExpiration(now.Add(time.Hour * 24 * 7))
-	refreshToken := t.V4Encrypt(s.rKey, nil)
+	t.SetJti(rt.jti)
+	t.SetExpiration(rt.expiresAt)
+	refreshToken := s.rKeys.Encrypt(&t)
 
 	return &Token{
 		Access:  accessToken,
@@ -230,6 +352,11 @@ type Claims struct {
 	Phone        string `json:"phoneNumber"`
 	Mobile       string `json:"mobileNumber"`
 	IsHR         bool   `json:"isHR"`
+	SessionID    int64  `json:"sessionId"`
+
+	// Directory is whether the user logged in through the directory,
+	// whose attributes the claims carry.
+	Directory bool `json:"directory,omitempty"`
 }
 
 type ctxKey int
@@ -265,9 +392,29 @@ type User struct {
 	phone    string
 	mobile   string
 	password string
+
+	// passwordHash is the bcrypt hash of the password set through the API,
+	// empty if there is none.
+	passwordHash string
+
+	// lang is the language notifications to the user are written in.
+	lang i18n.Lang
+
+	// directory is whether the user was authenticated by the directory.
+	directory bool
 }
 
+// Compare reports whether password is u's. A password set through the API
+// is checked against its hash, else the legacy one of the login table.
 func (u *User) Compare(password string) (bool, error) {
+	if u.passwordHash != "" {
+		err := bcrypt.CompareHashAndPassword([]byte(u.passwordHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
 	hashed, err := bcrypt.GenerateFromPassword([]byte(u.password), bcrypt.DefaultCost)
 	if err != nil {
 		return false, err
@@ -276,6 +423,20 @@ func (u *User) Compare(password string) (bool, error) {
 	return bcrypt.CompareHashAndPassword(hashed, []byte(password)) == nil, nil
 }
 
+// CheckPassword reports whether password is the one of username, who may
+// not exist.
+func (s *Auth) CheckPassword(ctx context.Context, username, password string) (bool, error) {
+	user, err := getUserByUsername(ctx, s.db, username)
+	if errors.Is(err, ErrUserNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return user.Compare(password)
+}
+
 func getUserByUsername(ctx context.Context, db *sql.DB, username string) (*User, error) {
 	q, args := sq.
 		Select(
@@ -286,14 +447,19 @@ func getUserByUsername(ctx context.Context, db *sql.DB, username string) (*User,
 			"e.bid",
 			"e.depid",
 			"e.poid",
-			"e.Emails",
-			"e.phone_number",
-			"e.mobile_number",
+			"CASE WHEN c.employee_id IS NULL THEN e.Emails ELSE c.email END",
+			"CASE WHEN c.employee_id IS NULL THEN e.phone_number ELSE c.phone_number END",
+			"CASE WHEN c.employee_id IS NULL THEN e.mobile_number ELSE c.mobile_number END",
 			"u.tokenkey",
+			"COALESCE(pw.password_hash, '')",
 			`CASE WHEN u.hrkey IN (0,1) THEN 1 ELSE 0 END AS hr`,
+			"COALESCE(p.notification_language, 'en')",
 		).
 		From("dbo.tb_userlogin AS u").
 		InnerJoin("dbo.vm_employee AS e ON u.eid = e.EID").
+		LeftJoin("dbo.employee_contact AS c ON c.employee_id = e.EID").
+		LeftJoin("dbo.employee_preference AS p ON p.employee_id = e.EID").
+		LeftJoin("dbo.user_password AS pw ON pw.username = u.username").
 		Where(
 			sq.Eq{
 				"u.username": username,
@@ -313,11 +479,13 @@ func getUserByUsername(ctx context.Context, db *sql.DB, username string) (*User,
 		&u.companyID,
 		&u.positionID,
 		&u.departmentID,
-		&u.email,
-		&u.phone,
-		&u.mobile,
+		(*pii.Text)(&u.email),
+		(*pii.Text)(&u.phone),
+		(*pii.Text)(&u.mobile),
 		&u.password,
+		&u.passwordHash,
 		&u.IsHR,
+		&u.lang,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrUserNotFound
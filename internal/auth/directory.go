@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDirectoryRejected is returned by a Directory that does not know the
+// user or not with that password.
+var ErrDirectoryRejected = errors.New("directory rejected the credentials")
+
+// Directory authenticates users against an external directory, such as
+// Active Directory, before Login falls back to the password in the
+// database. See package ldap.
+type Directory interface {
+	Authenticate(ctx context.Context, username, password string) (*DirectoryUser, error)
+}
+
+// DirectoryUser is what a directory knows of a user it authenticated. Its
+// attributes replace those of HR's records in the user's claims; empty
+// ones keep them.
+type DirectoryUser struct {
+	DisplayName string
+	Email       string
+	Phone       string
+	Mobile      string
+}
+
+// NopDirectory authenticates no one, so Login always checks the password
+// in the database.
+type NopDirectory struct{}
+
+func (NopDirectory) Authenticate(context.Context, string, string) (*DirectoryUser, error) {
+	return nil, ErrDirectoryRejected
+}
+
+// useDirectory makes the claims of u carry the attributes of d.
+func (u *User) useDirectory(d *DirectoryUser) {
+	u.directory = true
+	if d.DisplayName != "" {
+		u.DisplayName = d.DisplayName
+	}
+	if d.Email != "" {
+		u.email = d.Email
+	}
+	if d.Phone != "" {
+		u.phone = d.Phone
+	}
+	if d.Mobile != "" {
+		u.mobile = d.Mobile
+	}
+}
@@ -0,0 +1,61 @@
+// Package geoip resolves the country of an IP address from a MaxMind
+// database, such as GeoLite2-Country or GeoIP2-City, for auth.Sessions.
+//
+// The database is read once, when the Locator is created; a database
+// updated by geoipupdate is picked up on restart.
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Locator is an auth.GeoLocator backed by a MaxMind database.
+type Locator struct {
+	db *maxminddb.Reader
+}
+
+var _ auth.GeoLocator = (*Locator)(nil)
+
+// Open opens the MaxMind database at path.
+func Open(path string) (*Locator, error) {
+	if path == "" {
+		return nil, errors.New("path is empty")
+	}
+
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+
+	return &Locator{db: db}, nil
+}
+
+// Country returns the ISO code of the country ip is located in, or ""
+// when the database does not know it, e.g. for a private address.
+func (l *Locator) Country(_ context.Context, ip string) (string, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return "", nil
+	}
+
+	var record struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+	}
+	if err := l.db.Lookup(addr, &record); err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", ip, err)
+	}
+
+	return record.Country.ISOCode, nil
+}
+
+func (l *Locator) Close() error {
+	return l.db.Close()
+}
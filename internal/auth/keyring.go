@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+)
+
+// Key is one version of a token key.
+type Key struct {
+	Version int
+	Key     paseto.V4SymmetricKey
+}
+
+// KeyLoader returns every version of a token key, e.g. from the config.
+type KeyLoader func() ([]Key, error)
+
+// minKeyReload is how often a token naming an unknown key version may make
+// the ring reload its keys.
+const minKeyReload = 10 * time.Second
+
+// KeyRing holds the versions of a token key. Tokens are encrypted with the
+// newest version, named in their footer, and decrypted with the version
+// they name, so a new version can be added without invalidating the tokens
+// in flight. Tokens without a footer predate the ring and are decrypted
+// with whichever version fits.
+//
+// A token naming a version the ring does not know, e.g. one issued by an
+// instance that reloaded its keys first, makes the ring reload its keys.
+type KeyRing struct {
+	load KeyLoader
+
+	mu       sync.RWMutex
+	keys     []Key
+	loadedAt time.Time
+}
+
+type keyFooter struct {
+	Kid string `json:"kid"`
+}
+
+// NewKeyRing returns a ring holding the keys load returns.
+func NewKeyRing(load KeyLoader) (*KeyRing, error) {
+	if load == nil {
+		return nil, errors.New("load is nil")
+	}
+
+	r := &KeyRing{load: load}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// KeyRingState is the versions a ring holds.
+type KeyRingState struct {
+	// Signing is the version new tokens are encrypted with.
+	Signing  int   `json:"signing"`
+	Versions []int `json:"versions"`
+}
+
+// Reload replaces the keys of the ring with those load returns. On error
+// the ring keeps its keys.
+func (r *KeyRing) Reload() (*KeyRingState, error) {
+	keys, err := r.load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no keys")
+	}
+
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, func(a, b Key) int { return b.Version - a.Version })
+	for i := 1; i < len(keys); i++ {
+		if keys[i].Version == keys[i-1].Version {
+			return nil, fmt.Errorf("key version %d is duplicated", keys[i].Version)
+		}
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+
+	return r.State(), nil
+}
+
+// State returns the versions the ring holds.
+func (r *KeyRing) State() *KeyRingState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state := &KeyRingState{
+		Signing:  r.keys[0].Version,
+		Versions: make([]int, 0, len(r.keys)),
+	}
+	for _, k := range r.keys {
+		state.Versions = append(state.Versions, k.Version)
+	}
+	return state
+}
+
+// Encrypt encrypts t with the newest key, naming its version in the footer.
+func (r *KeyRing) Encrypt(t *paseto.Token) string {
+	r.mu.RLock()
+	k := r.keys[0]
+	r.mu.RUnlock()
+
+	footer, _ := json.Marshal(&keyFooter{Kid: strconv.Itoa(k.Version)})
+	t.SetFooter(footer)
+	return t.V4Encrypt(k.Key, nil)
+}
+
+// Parse decrypts the token tainted with the key it names and validates it
+// with parser.
+func (r *KeyRing) Parse(parser paseto.Parser, tainted string, implicit []byte) (*paseto.Token, error) {
+	footer, err := parser.UnsafeParseFooter(paseto.V4Local, tainted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(footer) == 0 {
+		var lastErr error
+		for _, k := range r.snapshot() {
+			t, err := parser.ParseV4Local(k.Key, tainted, implicit)
+			if err == nil {
+				return t, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+
+	var f keyFooter
+	if err := json.Unmarshal(footer, &f); err != nil {
+		return nil, fmt.Errorf("invalid footer: %w", err)
+	}
+	version, err := strconv.Atoi(f.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key id %q", f.Kid)
+	}
+
+	k, ok := r.find(version)
+	if !ok && r.reloadDue() {
+		if _, err := r.Reload(); err != nil {
+			return nil, err
+		}
+		k, ok = r.find(version)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %d", version)
+	}
+
+	return parser.ParseV4Local(k.Key, tainted, implicit)
+}
+
+// KeyState is the versions of the token keys in use.
+type KeyState struct {
+	Access  *KeyRingState `json:"access"`
+	Refresh *KeyRingState `json:"refresh"`
+}
+
+// RotateKeys reloads the token keys, so the newest version in the config
+// encrypts new tokens while those encrypted with older versions stay valid
+// until they expire. Deploy tooling calls it on every instance after adding
+// a version, and drops the oldest one once its tokens have expired.
+func (s *Auth) RotateKeys(ctx context.Context) (*KeyState, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "RotateKeys"),
+	)
+
+	access, err := s.aKeys.Reload()
+	if err != nil {
+		zlog.Error("failed to reload access token keys", zap.Error(err))
+		return nil, err
+	}
+	refresh, err := s.rKeys.Reload()
+	if err != nil {
+		zlog.Error("failed to reload refresh token keys", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Info("token keys rotated",
+		zap.Int("access_version", access.Signing),
+		zap.Int("refresh_version", refresh.Signing),
+	)
+	return &KeyState{Access: access, Refresh: refresh}, nil
+}
+
+func (r *KeyRing) snapshot() []Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.keys
+}
+
+func (r *KeyRing) find(version int) (Key, bool) {
+	for _, k := range r.snapshot() {
+		if k.Version == version {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+func (r *KeyRing) reloadDue() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return time.Since(r.loadedAt) >= minKeyReload
+}
@@ -0,0 +1,173 @@
+// Package ldap authenticates users against Active Directory, or any LDAP
+// directory, for auth.Login.
+//
+// A user is looked up by their username, with the service account when one
+// is configured, and authenticated by binding as the entry found. The
+// entry's attributes become the user's display name and contacts in their
+// claims.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Attributes name the directory attributes mapped to the claims.
+type Attributes struct {
+	DisplayName string
+	Email       string
+	Phone       string
+	Mobile      string
+}
+
+// Options are the settings of the directory.
+type Options struct {
+	// URL is the directory server, e.g. "ldaps://ad.example.com:636".
+	// ldap:// URLs are upgraded with StartTLS when StartTLS is set.
+	URL      string
+	StartTLS bool
+
+	// BindDN and BindPassword are the service account users are looked up
+	// with. Empty looks them up anonymously.
+	BindDN       string
+	BindPassword string
+
+	// BaseDN is where users are looked up, e.g. "DC=example,DC=com".
+	BaseDN string
+
+	// UserFilter finds the entry of a username, which replaces its %s, e.g.
+	// "(&(objectClass=user)(sAMAccountName=%s))".
+	UserFilter string
+
+	Attributes Attributes
+
+	// Timeout bounds dialing and each operation.
+	Timeout time.Duration
+}
+
+// DefaultAttributes are the Active Directory attributes of the claims.
+var DefaultAttributes = Attributes{
+	DisplayName: "displayName",
+	Email:       "mail",
+	Phone:       "telephoneNumber",
+	Mobile:      "mobile",
+}
+
+// Directory is an auth.Directory backed by an LDAP server.
+type Directory struct {
+	opts Options
+}
+
+func NewDirectory(_ context.Context, opts Options) (*Directory, error) {
+	if opts.URL == "" {
+		return nil, errors.New("url is empty")
+	}
+	if opts.BaseDN == "" {
+		return nil, errors.New("base dn is empty")
+	}
+	if strings.Count(opts.UserFilter, "%s") != 1 {
+		return nil, errors.New("user filter must contain one %s")
+	}
+	if opts.Timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	if opts.Attributes == (Attributes{}) {
+		opts.Attributes = DefaultAttributes
+	}
+
+	return &Directory{opts: opts}, nil
+}
+
+// Authenticate returns the directory's entry of username if password is
+// theirs, and auth.ErrDirectoryRejected if it is not or there is no such
+// entry.
+func (d *Directory) Authenticate(ctx context.Context, username, password string) (*auth.DirectoryUser, error) {
+	// An empty password is an unauthenticated bind, which succeeds.
+	if username == "" || password == "" {
+		return nil, auth.ErrDirectoryRejected
+	}
+
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// The client does not take a context; closing the connection aborts
+	// the operation in flight.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if d.opts.BindDN != "" {
+		if err := conn.Bind(d.opts.BindDN, d.opts.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind service account: %w", err)
+		}
+	}
+
+	a := d.opts.Attributes
+	res, err := conn.Search(goldap.NewSearchRequest(
+		d.opts.BaseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		2,
+		int(d.opts.Timeout/time.Second),
+		false,
+		fmt.Sprintf(d.opts.UserFilter, goldap.EscapeFilter(username)),
+		[]string{a.DisplayName, a.Email, a.Phone, a.Mobile},
+		nil,
+	))
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("failed to search user: %w", err)
+	}
+	// More than one entry means the filter is ambiguous; none is trusted.
+	if res == nil || len(res.Entries) != 1 {
+		return nil, auth.ErrDirectoryRejected
+	}
+	entry := res.Entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return nil, auth.ErrDirectoryRejected
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind user: %w", err)
+	}
+
+	return &auth.DirectoryUser{
+		DisplayName: entry.GetAttributeValue(a.DisplayName),
+		Email:       entry.GetAttributeValue(a.Email),
+		Phone:       entry.GetAttributeValue(a.Phone),
+		Mobile:      entry.GetAttributeValue(a.Mobile),
+	}, nil
+}
+
+func (d *Directory) dial() (*goldap.Conn, error) {
+	conn, err := goldap.DialURL(d.opts.URL, goldap.DialWithDialer(&net.Dialer{Timeout: d.opts.Timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial directory: %w", err)
+	}
+	conn.SetTimeout(d.opts.Timeout)
+
+	if d.opts.StartTLS {
+		u, err := url.Parse(d.opts.URL)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to parse url: %w", err)
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: u.Hostname()}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	return conn, nil
+}
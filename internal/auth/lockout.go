@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/reqid"
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Lockout throttles password guessing. Threshold failed logins in a row
+// lock the username for Base, and every further failure locks it for twice
+// as long as the one before, up to Max. Failures more than Window apart
+// are not in a row.
+type Lockout struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Window    time.Duration
+}
+
+// lockFor returns how long failures in a row lock the username for.
+func (l Lockout) lockFor(failures int) time.Duration {
+	if failures < l.Threshold {
+		return 0
+	}
+
+	d := l.Base << (failures - l.Threshold)
+	if d <= 0 || d > l.Max {
+		return l.Max
+	}
+	return d
+}
+
+// attemptKey is the username failures are counted for, whatever its case.
+func attemptKey(username string) string {
+	key := []rune(strings.ToLower(username))
+	if len(key) > 100 {
+		key = key[:100]
+	}
+	return string(key)
+}
+
+// lockedError is the error of a login to a username locked until then.
+// Its RetryInfo tells the client when to try again.
+func lockedError(until, now time.Time) error {
+	st, _ := i18n.Status(codes.ResourceExhausted, i18n.AccountLocked).WithDetails(&edPb.RetryInfo{
+		RetryDelay: durationpb.New(until.Sub(now).Round(time.Second)),
+	})
+	return st.Err()
+}
+
+// failLogin records a failed login of username and returns the error the
+// client gets.
+func (s *Auth) failLogin(ctx context.Context, zlog *zap.Logger, username string) error {
+	now := time.Now()
+
+	failures, err := addLoginFailure(ctx, s.db, attemptKey(username), now, now.Add(-s.lockout.Window))
+	if err != nil {
+		zlog.Error("failed to record login failure", zap.Error(err))
+		return err
+	}
+
+	d := s.lockout.lockFor(failures)
+	if d == 0 {
+		return i18n.Error(codes.Unauthenticated, i18n.InvalidCredentials)
+	}
+
+	until := now.Add(d)
+	if err := lockLogin(ctx, s.db, attemptKey(username), until); err != nil {
+		zlog.Error("failed to lock login", zap.Error(err))
+		return err
+	}
+	zlog.Warn("login locked", zap.Int("failures", failures), zap.Time("locked_until", until))
+
+	return lockedError(until, now)
+}
+
+// PurgeLoginAttempts deletes the failures that no longer count nor lock.
+// It runs as a scheduled job.
+func (s *Auth) PurgeLoginAttempts(ctx context.Context) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "PurgeLoginAttempts"),
+	)
+
+	now := time.Now()
+	n, err := deleteStaleLoginAttempts(ctx, s.db, now.Add(-s.lockout.Window), now)
+	if err != nil {
+		zlog.Error("failed to delete stale login attempts", zap.Error(err))
+		return err
+	}
+
+	if n > 0 {
+		zlog.Info("purged stale login attempts", zap.Int64("attempts", n))
+	}
+	return nil
+}
+
+// getLoginLock returns until when username is locked, the zero time if it
+// is not.
+func getLoginLock(ctx context.Context, db *sql.DB, username string) (time.Time, error) {
+	q, args := sq.
+		Select("locked_until").
+		From("dbo.login_attempt").
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var until sql.NullTime
+	err := db.QueryRowContext(ctx, q, args...).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return until.Time, nil
+}
+
+// addLoginFailure counts a failure of username at now and returns the
+// failures in a row, which restart when the last one was before since.
+func addLoginFailure(ctx context.Context, db *sql.DB, username string, now, since time.Time) (int, error) {
+	q := `
+MERGE dbo.login_attempt WITH (HOLDLOCK) AS t
+USING (SELECT @p1 AS username) AS s ON t.username = s.username
+WHEN MATCHED THEN UPDATE SET
+  failures = CASE WHEN t.last_failure_at < @p3 THEN 1 ELSE t.failures + 1 END,
+  last_failure_at = @p2
+WHEN NOT MATCHED THEN INSERT (username, failures, last_failure_at) VALUES (@p1, 1, @p2)
+OUTPUT inserted.failures;`
+
+	var failures int
+	if err := db.QueryRowContext(ctx, q, username, pager.DateTime(now), pager.DateTime(since)).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("failed to execute add login failure: %w", err)
+	}
+
+	return failures, nil
+}
+
+func lockLogin(ctx context.Context, db *sql.DB, username string, until time.Time) error {
+	q, args := sq.
+		Update("dbo.login_attempt").
+		Set("locked_until", pager.DateTime(until)).
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func deleteLoginAttempts(ctx context.Context, db *sql.DB, username string) error {
+	q, args := sq.
+		Delete("dbo.login_attempt").
+		Where(sq.Eq{"username": username}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func deleteStaleLoginAttempts(ctx context.Context, db *sql.DB, since, now time.Time) (int64, error) {
+	q, args := sq.
+		Delete("dbo.login_attempt").
+		Where(sq.Lt{"last_failure_at": pager.DateTime(since)}).
+		Where(sq.Or{
+			sq.Eq{"locked_until": nil},
+			sq.Lt{"locked_until": pager.DateTime(now)},
+		}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n, nil
+}
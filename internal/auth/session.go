@@ -0,0 +1,483 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// GeoLocator resolves the ISO country code of an IP address.
+type GeoLocator interface {
+	Country(ctx context.Context, ip string) (string, error)
+}
+
+// NopGeoLocator never knows the country, which disables new-location alerts.
+type NopGeoLocator struct{}
+
+func (NopGeoLocator) Country(context.Context, string) (string, error) {
+	return "", nil
+}
+
+// ClientInfo describes where a login request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+
+	// DeviceID is an optional identifier sent by the mobile app.
+	DeviceID string
+}
+
+// fingerprint identifies the device without storing the raw identifiers.
+func (c ClientInfo) fingerprint() string {
+	sum := sha256.Sum256([]byte(c.UserAgent + "|" + c.DeviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sessions records every login with its device and location, warns users
+// about logins from devices or countries they never used before, and lets
+// them revoke such a session.
+type Sessions struct {
+	db        *sql.DB
+	zlog      *zap.Logger
+	notifier  notify.Notifier
+	geo       GeoLocator
+	reportURL string
+}
+
+// NewSessions creates Sessions. reportURL is a format string receiving the
+// report token, linking to the page where a user disowns a login.
+func NewSessions(_ context.Context, db *sql.DB, zlog *zap.Logger, notifier notify.Notifier, geo GeoLocator, reportURL string) (*Sessions, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	if geo == nil {
+		return nil, errors.New("geo is nil")
+	}
+
+	return &Sessions{
+		db:        db,
+		zlog:      zlog,
+		notifier:  notifier,
+		geo:       geo,
+		reportURL: reportURL,
+	}, nil
+}
+
+type session struct {
+	id          int64
+	username    string
+	deviceHash  string
+	userAgent   string
+	ip          string
+	country     string
+	reportToken string
+	createdAt   time.Time
+}
+
+// start records a login for u and alerts the user when it comes from an
+// unseen device or country.
+func (s *Sessions) start(ctx context.Context, u *User, client ClientInfo) (*session, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "start"),
+		zap.String("username", u.Code),
+	)
+
+	country, err := s.geo.Country(ctx, client.IP)
+	if err != nil {
+		zlog.Warn("failed to locate ip", zap.String("ip", client.IP), zap.Error(err))
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate report token: %w", err)
+	}
+
+	userAgent := client.UserAgent
+	if r := []rune(userAgent); len(r) > 512 {
+		userAgent = string(r[:512])
+	}
+
+	ss := &session{
+		username:    u.Code,
+		deviceHash:  client.fingerprint(),
+		userAgent:   userAgent,
+		ip:          client.IP,
+		country:     strings.ToUpper(country),
+		reportToken: hex.EncodeToString(token),
+		createdAt:   time.Now(),
+	}
+
+	seen, err := getSeenLogin(ctx, s.db, ss)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createSession(ctx, s.db, ss); err != nil {
+		return nil, err
+	}
+
+	// The very first login has nothing to compare with.
+	if seen.logins > 0 && (!seen.device || (ss.country != "" && !seen.country)) {
+		go s.notifyNewLogin(u, ss)
+	}
+
+	return ss, nil
+}
+
+var newLoginTemplate = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "New sign-in to your business card account",
+		i18n.Lao:     "ມີການເຂົ້າສູ່ລະບົບບັນຊີນາມບັດຂອງທ່ານໃໝ່",
+		i18n.Thai:    "มีการเข้าสู่ระบบบัญชีนามบัตรของคุณใหม่",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello {{.Name}},
+
+Your account was used to sign in from a new device or location.
+
+Time: {{.Time}}
+IP address: {{.IP}}
+Country: {{.Country}}
+Device: {{.Device}}
+
+If this wasn't you, report it here so the session is revoked:
+{{.ReportURL}}
+`,
+		i18n.Lao: `ສະບາຍດີ {{.Name}},
+
+ບັນຊີຂອງທ່ານຖືກໃຊ້ເຂົ້າສູ່ລະບົບຈາກອຸປະກອນ ຫຼື ສະຖານທີ່ໃໝ່.
+
+ເວລາ: {{.Time}}
+ທີ່ຢູ່ IP: {{.IP}}
+ປະເທດ: {{.Country}}
+ອຸປະກອນ: {{.Device}}
+
+ຖ້າບໍ່ແມ່ນທ່ານ, ກະລຸນາລາຍງານທີ່ນີ້ເພື່ອຍົກເລີກເຊດຊັນ:
+{{.ReportURL}}
+`,
+		i18n.Thai: `สวัสดี {{.Name}},
+
+บัญชีของคุณถูกใช้เข้าสู่ระบบจากอุปกรณ์หรือสถานที่ใหม่
+
+เวลา: {{.Time}}
+ที่อยู่ IP: {{.IP}}
+ประเทศ: {{.Country}}
+อุปกรณ์: {{.Device}}
+
+หากไม่ใช่คุณ กรุณารายงานที่นี่เพื่อเพิกถอนเซสชัน:
+{{.ReportURL}}
+`,
+	},
+}
+
+func (s *Sessions) notifyNewLogin(u *User, ss *session) {
+	if u.email == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msg, err := newLoginTemplate.Render(u.lang, map[string]string{
+		"Name":      u.DisplayName,
+		"Time":      ss.createdAt.Format(time.RFC1123),
+		"IP":        ss.ip,
+		"Country":   ss.country,
+		"Device":    ss.userAgent,
+		"ReportURL": fmt.Sprintf(s.reportURL, ss.reportToken),
+	})
+	if err != nil {
+		s.zlog.Error("failed to render new login notification", zap.String("username", u.Code), zap.Error(err))
+		return
+	}
+	msg.To = []string{u.email}
+
+	err = s.notifier.Notify(ctx, msg)
+	if err != nil {
+		s.zlog.Error("failed to notify new login", zap.String("username", u.Code), zap.Error(err))
+	}
+}
+
+// revoked reports whether the session was revoked. Tokens issued before
+// sessions were tracked carry no session and are never revoked.
+func (s *Sessions) revoked(ctx context.Context, id int64) (bool, error) {
+	if id <= 0 {
+		return false, nil
+	}
+
+	return isSessionRevoked(ctx, s.db, id)
+}
+
+type ReportSessionReq struct {
+	Token string `json:"token"`
+}
+
+func (r *ReportSessionReq) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+
+	violations, err := validate.Violations(&contactqrPb.ReportSessionRequest{
+		Token: r.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidReport).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// ReportSession revokes the session identified by the report token sent in
+// a new-login email. Its refresh token stops working immediately.
+func (s *Auth) ReportSession(ctx context.Context, in *ReportSessionReq) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ReportSession"),
+	)
+
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	err := revokeSessionByReportToken(ctx, s.sessions.db, in.Token)
+	if errors.Is(err, ErrSessionNotFound) {
+		return i18n.Error(codes.NotFound, i18n.SessionNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to revoke session", zap.Error(err))
+		return err
+	}
+
+	zlog.Warn("session reported by user")
+	return nil
+}
+
+type RevokeSessionsResult struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// RevokeUserSessions revokes every active session of the user, e.g. when
+// the user's credentials leaked. Their refresh tokens stop working
+// immediately; access tokens already issued stay valid until they expire.
+func (s *Auth) RevokeUserSessions(ctx context.Context, username string) (*RevokeSessionsResult, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "RevokeUserSessions"),
+		zap.String("target_username", username),
+	)
+
+	_, err := getUserByUsername(ctx, s.db, username)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.UserNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get user by username", zap.Error(err))
+		return nil, err
+	}
+
+	n, err := revokeUserSessions(ctx, s.db, username)
+	if err != nil {
+		zlog.Error("failed to revoke sessions", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Warn("sessions revoked", zap.Int64("sessions", n))
+	return &RevokeSessionsResult{Revoked: n}, nil
+}
+
+func revokeSession(ctx context.Context, db *sql.DB, id int64) error {
+	q, args := sq.
+		Update("dbo.login_session").
+		Set("revoked_at", time.Now()).
+		Where(
+			sq.Eq{
+				"id":         id,
+				"revoked_at": nil,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+type seenLogin struct {
+	logins  int
+	device  bool
+	country bool
+}
+
+func getSeenLogin(ctx context.Context, db *sql.DB, in *session) (*seenLogin, error) {
+	q, args := sq.
+		Select("COUNT(*)").
+		Column("COALESCE(SUM(CASE WHEN device_hash = ? THEN 1 ELSE 0 END), 0)", in.deviceHash).
+		Column("COALESCE(SUM(CASE WHEN country = ? THEN 1 ELSE 0 END), 0)", in.country).
+		From("dbo.login_session").
+		Where(
+			sq.Eq{
+				"username": in.username,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var logins, devices, countries int
+	if err := db.QueryRowContext(ctx, q, args...).Scan(&logins, &devices, &countries); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return &seenLogin{
+		logins:  logins,
+		device:  devices > 0,
+		country: countries > 0,
+	}, nil
+}
+
+func createSession(ctx context.Context, db *sql.DB, in *session) error {
+	q, args := sq.
+		Insert("dbo.login_session").
+		Columns(
+			"username",
+			"device_hash",
+			"user_agent",
+			"ip",
+			"country",
+			"report_token",
+			"created_at",
+		).
+		Values(
+			in.username,
+			in.deviceHash,
+			in.userAgent,
+			in.ip,
+			in.country,
+			in.reportToken,
+			in.createdAt,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if err := db.QueryRowContext(ctx, q, args...).Scan(&in.id); err != nil {
+		return fmt.Errorf("failed to execute create session: %w", err)
+	}
+
+	return nil
+}
+
+func isSessionRevoked(ctx context.Context, db *sql.DB, id int64) (bool, error) {
+	q, args := sq.
+		Select("CASE WHEN revoked_at IS NULL THEN 0 ELSE 1 END").
+		From("dbo.login_session").
+		Where(
+			sq.Eq{
+				"id": id,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var revoked bool
+	err := db.QueryRowContext(ctx, q, args...).Scan(&revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return revoked, nil
+}
+
+func revokeUserSessions(ctx context.Context, db *sql.DB, username string) (int64, error) {
+	q, args := sq.
+		Update("dbo.login_session").
+		Set("revoked_at", time.Now()).
+		Where(
+			sq.Eq{
+				"username":   username,
+				"revoked_at": nil,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n, nil
+}
+
+func revokeSessionByReportToken(ctx context.Context, db *sql.DB, token string) error {
+	q, args := sq.
+		Update("dbo.login_session").
+		Set("revoked_at", time.Now()).
+		Where(
+			sq.Eq{
+				"report_token": token,
+				"revoked_at":   nil,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
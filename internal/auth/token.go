@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/reqid"
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenUsed     = errors.New("refresh token already used")
+)
+
+// refreshToken is an issued refresh token. Its session is the token family:
+// every token issued by refreshing descends from the login's token.
+type refreshToken struct {
+	jti       string
+	sessionID int64
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+// issueToken generates a token pair for u in the session and records its
+// refresh token.
+func (s *Auth) issueToken(ctx context.Context, u *User, sessionID int64) (*Token, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate jti: %w", err)
+	}
+
+	rt := &refreshToken{
+		jti:       hex.EncodeToString(b),
+		sessionID: sessionID,
+		issuedAt:  time.Now(),
+	}
+	rt.expiresAt = rt.issuedAt.Add(s.ttl.Refresh)
+
+	token, err := s.genToken(u, rt)
+	if err != nil {
+		return nil, err
+	}
+	if err := createRefreshToken(ctx, s.db, rt); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Logout revokes the caller's session, and with it every refresh token of
+// the family. Access tokens already issued stay valid until they expire.
+func (s *Auth) Logout(ctx context.Context) error {
+	claims := ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "Logout"),
+		zap.String("username", claims.Code),
+		zap.Int64("session_id", claims.SessionID),
+	)
+
+	// Tokens issued before sessions were tracked have nothing to revoke.
+	if claims.SessionID <= 0 {
+		return nil
+	}
+
+	err := revokeSession(ctx, s.db, claims.SessionID)
+	if err != nil && !errors.Is(err, ErrSessionNotFound) {
+		zlog.Error("failed to revoke session", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// PurgeRefreshTokens deletes the records of expired refresh tokens. It runs
+// as a scheduled job.
+func (s *Auth) PurgeRefreshTokens(ctx context.Context) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "PurgeRefreshTokens"),
+	)
+
+	n, err := deleteExpiredRefreshTokens(ctx, s.db, time.Now())
+	if err != nil {
+		zlog.Error("failed to delete expired refresh tokens", zap.Error(err))
+		return err
+	}
+
+	if n > 0 {
+		zlog.Info("purged expired refresh tokens", zap.Int64("tokens", n))
+	}
+	return nil
+}
+
+func createRefreshToken(ctx context.Context, db *sql.DB, in *refreshToken) error {
+	q, args := sq.
+		Insert("dbo.refresh_token").
+		Columns(
+			"jti",
+			"session_id",
+			"issued_at",
+			"expires_at",
+		).
+		Values(
+			in.jti,
+			in.sessionID,
+			in.issuedAt,
+			in.expiresAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// useRefreshToken marks the refresh token jti used. It fails with
+// ErrRefreshTokenUsed if it already was, so of two concurrent refreshes
+// with the same token only one succeeds.
+func useRefreshToken(ctx context.Context, db *sql.DB, jti string) error {
+	q, args := sq.
+		Update("dbo.refresh_token").
+		Set("used_at", time.Now()).
+		Where(
+			sq.Eq{
+				"jti":     jti,
+				"used_at": nil,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	q, args = sq.
+		Select("COUNT(*)").
+		From("dbo.refresh_token").
+		Where(sq.Eq{"jti": jti}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var count int
+	if err := db.QueryRowContext(ctx, q, args...).Scan(&count); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	if count > 0 {
+		return ErrRefreshTokenUsed
+	}
+
+	return ErrRefreshTokenNotFound
+}
+
+func deleteExpiredRefreshTokens(ctx context.Context, db *sql.DB, now time.Time) (int64, error) {
+	q, args := sq.
+		Delete("dbo.refresh_token").
+		Where(sq.Lt{"expires_at": pager.DateTime(now)}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,373 @@
+// Package backup exports the tables owned by the service into a portable
+// archive and restores such an archive into an empty instance, e.g. to move
+// to another database server.
+//
+// The archive is a gzipped tar with one <table>.ndjson file per table, in
+// restore order, followed by manifest.json. The first line of a table file
+// lists its columns and their types, every further line is one row as a JSON
+// array. Encrypted PII columns are copied as stored, so the target needs the
+// same PII KMS key or master key. The HR tables cards refer to are not part of the
+// archive and must already exist on the target.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is the archive format version written into the manifest.
+const Format = 1
+
+const manifestName = "manifest.json"
+
+type table struct {
+	name string
+
+	// identity is the table's identity column, kept as is on restore.
+	identity string
+}
+
+// tables are the service-owned tables in an order that satisfies their
+// foreign keys on restore.
+var tables = []table{
+	{name: "dbo.guest_person", identity: "id"},
+	{name: "dbo.employee_photo"},
+	{name: "dbo.business_card"},
+	{name: "dbo.business_card_history", identity: "id"},
+	{name: "dbo.business_card_history_anchor"},
+	{name: "dbo.business_card_version", identity: "id"},
+	{name: "dbo.business_card_lead", identity: "id"},
+	{name: "dbo.business_card_print_request", identity: "id"},
+	{name: "dbo.business_card_tombstone"},
+	{name: "dbo.business_card_event"},
+	{name: "dbo.business_card_share_link"},
+	{name: "dbo.business_card_scan", identity: "id"},
+	{name: "dbo.business_card_view"},
+	{name: "dbo.landing_experiment"},
+	{name: "dbo.landing_event", identity: "id"},
+	{name: "dbo.event_outbox", identity: "seq"},
+	{name: "dbo.event_outbox_delivery"},
+	{name: "dbo.login_session", identity: "id"},
+	{name: "dbo.refresh_token"},
+	{name: "dbo.login_attempt"},
+	{name: "dbo.user_password"},
+	{name: "dbo.password_reset"},
+	{name: "dbo.scheduled_job"},
+	{name: "dbo.transliteration_override"},
+	{name: "dbo.employee_preference"},
+	{name: "dbo.employee_contact"},
+	{name: "dbo.push_device"},
+	{name: "dbo.export_delivery", identity: "id"},
+	{name: "dbo.webhook"},
+	{name: "dbo.webhook_delivery", identity: "id"},
+	{name: "dbo.card_template"},
+	{name: "dbo.company_address"},
+	{name: "dbo.card_idempotency_key"},
+	{name: "dbo.job_queue", identity: "id"},
+	{name: "dbo.employee_role"},
+}
+
+type Manifest struct {
+	Format        int            `json:"format"`
+	SchemaVersion uint64         `json:"schemaVersion"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	Rows          map[string]int `json:"rows"`
+}
+
+type column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Export writes every service-owned table to w. schemaVersion is recorded so
+// the archive is only restored into a database with the same schema.
+func Export(ctx context.Context, db *sql.DB, w io.Writer, schemaVersion uint64) (*Manifest, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// A snapshot transaction gives a consistent view across tables without
+	// blocking writers. It needs ALLOW_SNAPSHOT_ISOLATION on the database.
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSnapshot, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	m := &Manifest{
+		Format:        Format,
+		SchemaVersion: schemaVersion,
+		CreatedAt:     time.Now(),
+		Rows:          make(map[string]int),
+	}
+	for _, t := range tables {
+		var buf bytes.Buffer
+		n, err := exportTable(ctx, tx, t.name, &buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", t.name, err)
+		}
+		m.Rows[t.name] = n
+
+		if err := writeFile(tw, fileName(t.name), buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	byt, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(tw, manifestName, byt); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, name string, w io.Writer) (int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	cols := make([]column, len(types))
+	for i, t := range types {
+		cols[i] = column{Name: t.Name(), Type: t.DatabaseTypeName()}
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(cols); err != nil {
+		return 0, err
+	}
+
+	var n int
+	values := make([]any, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := enc.Encode(values); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return n, nil
+}
+
+// Restore loads an archive written by Export into db in one transaction.
+// It refuses to restore into tables that already hold rows or into a
+// database whose schema version differs from the archive's.
+func Restore(ctx context.Context, db *sql.DB, r io.Reader, schemaVersion uint64) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The archive is read as it is restored, so unlike utils.WithTx this
+	// transaction cannot be retried.
+	var m *Manifest
+	restored := make(map[string]int)
+	err = func() error {
+		for _, t := range tables {
+			if err := ensureEmpty(ctx, tx, t.name); err != nil {
+				return err
+			}
+		}
+
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read archive: %w", err)
+			}
+
+			if hdr.Name == manifestName {
+				m = new(Manifest)
+				if err := json.NewDecoder(tr).Decode(m); err != nil {
+					return fmt.Errorf("failed to decode manifest: %w", err)
+				}
+				continue
+			}
+
+			t, ok := tableOf(hdr.Name)
+			if !ok {
+				return fmt.Errorf("unknown file %q in archive", hdr.Name)
+			}
+			n, err := restoreTable(ctx, tx, t, tr)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", t.name, err)
+			}
+			restored[t.name] = n
+		}
+
+		switch {
+		case m == nil:
+			return errors.New("archive has no manifest")
+
+		case m.Format != Format:
+			return fmt.Errorf("archive format %d is not supported", m.Format)
+
+		case m.SchemaVersion != schemaVersion:
+			return fmt.Errorf("archive schema version %d does not match database schema version %d", m.SchemaVersion, schemaVersion)
+		}
+
+		for name, want := range m.Rows {
+			if got := restored[name]; got != want {
+				return fmt.Errorf("archive is incomplete: %s has %d of %d rows", name, got, want)
+			}
+		}
+
+		return nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func restoreTable(ctx context.Context, tx *sql.Tx, t table, r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var cols []column
+	if err := dec.Decode(&cols); err != nil {
+		return 0, fmt.Errorf("failed to decode columns: %w", err)
+	}
+
+	if t.identity != "" {
+		if err := setIdentityInsert(ctx, tx, t.name, true); err != nil {
+			return 0, err
+		}
+		defer setIdentityInsert(ctx, tx, t.name, false)
+	}
+
+	var n int
+	for dec.More() {
+		var raw []any
+		if err := dec.Decode(&raw); err != nil {
+			return 0, fmt.Errorf("failed to decode row %d: %w", n+1, err)
+		}
+		if len(raw) != len(cols) {
+			return 0, fmt.Errorf("row %d has %d values, want %d", n+1, len(raw), len(cols))
+		}
+
+		values := make([]any, len(raw))
+		for i, v := range raw {
+			var err error
+			if values[i], err = decodeValue(cols[i].Type, v); err != nil {
+				return 0, fmt.Errorf("row %d, column %s: %w", n+1, cols[i].Name, err)
+			}
+		}
+
+		if err := insertRow(ctx, tx, t.name, cols, values); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// decodeValue turns a JSON value back into the Go type the driver expects
+// for a column of type typ.
+func decodeValue(typ string, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch typ {
+	case "DATETIME", "DATETIME2", "SMALLDATETIME", "DATE", "DATETIMEOFFSET":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected time, got %T", v)
+		}
+		return time.Parse(time.RFC3339Nano, s)
+
+	case "VARBINARY", "BINARY", "IMAGE":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected bytes, got %T", v)
+		}
+		return base64.StdEncoding.DecodeString(s)
+
+	case "BIGINT", "INT", "SMALLINT", "TINYINT":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("expected integer, got %T", v)
+		}
+		return n.Int64()
+	}
+
+	return v, nil
+}
+
+func tableOf(file string) (table, bool) {
+	for _, t := range tables {
+		if fileName(t.name) == file {
+			return t, true
+		}
+	}
+	return table{}, false
+}
+
+func fileName(table string) string {
+	return strings.TrimPrefix(table, "dbo.") + ".ndjson"
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+	return err
+}
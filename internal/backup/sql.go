@@ -0,0 +1,55 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func ensureEmpty(ctx context.Context, tx *sql.Tx, table string) error {
+	var n int64
+	q := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
+	if err := tx.QueryRowContext(ctx, q).Scan(&n); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	if n > 0 {
+		return fmt.Errorf("%s already has %d rows, restore needs an empty instance", table, n)
+	}
+
+	return nil
+}
+
+func setIdentityInsert(ctx context.Context, tx *sql.Tx, table string, on bool) error {
+	state := "OFF"
+	if on {
+		state = "ON"
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET IDENTITY_INSERT %s %s", table, state)); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func insertRow(ctx context.Context, tx *sql.Tx, table string, cols []column, values []any) error {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = "[" + c.Name + "]"
+	}
+
+	q, args := sq.
+		Insert(table).
+		Columns(names...).
+		Values(values...).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
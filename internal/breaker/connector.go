@@ -0,0 +1,44 @@
+package breaker
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type connector struct {
+	driver.Connector
+	b *Breaker
+}
+
+// Connector guards new database connections with b. When the database is
+// down every query fails fast with ErrOpen instead of waiting for the dial
+// timeout, and database/sql's bad-connection retry sends broken pooled
+// connections through here as well.
+func Connector(c driver.Connector, b *Breaker) driver.Connector {
+	return &connector{
+		Connector: c,
+		b:         b,
+	}
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if err := c.b.Allow(); err != nil {
+		return nil, err
+	}
+
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		// A caller giving up is not a sign of an unhealthy database, but
+		// the call must end all the same, or a half-open breaker would
+		// wait for its trial forever.
+		if ctx.Err() != nil {
+			c.b.Release()
+		} else {
+			c.b.Failure()
+		}
+		return nil, err
+	}
+
+	c.b.Success()
+	return conn, nil
+}
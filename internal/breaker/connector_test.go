@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+// dialer is a driver.Connector whose Connect blocks until its context is
+// done when hang is set, and fails with err otherwise.
+type dialer struct {
+	hang bool
+	err  error
+}
+
+func (d *dialer) Connect(ctx context.Context) (driver.Conn, error) {
+	if d.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, d.err
+}
+
+func (d *dialer) Driver() driver.Driver { return nil }
+
+func TestConnectCancelledTrialReleasesBreaker(t *testing.T) {
+	b := New(1, time.Millisecond)
+	d := &dialer{err: errors.New("connection refused")}
+	c := Connector(d, b)
+
+	if _, err := c.Connect(context.Background()); err == nil {
+		t.Fatal("Connect succeeded, want an error")
+	}
+	if !b.Open() {
+		t.Fatal("breaker closed after a failure, want it open")
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// The trial's caller gives up.
+	d.hang = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Connect(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Connect = %v, want context.Canceled", err)
+	}
+
+	// The next call must be let through as the trial.
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow after a cancelled trial = %v, want nil", err)
+	}
+	b.Success()
+	if b.Open() {
+		t.Fatal("breaker open after a successful trial, want it closed")
+	}
+}
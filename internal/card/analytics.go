@@ -0,0 +1,392 @@
+package card
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// scan is one visit to a card's public vCard, made by scanning its QR code
+// or tapping its NFC tag.
+type scan struct {
+	cardID      string
+	eventCardID string
+
+	// visitor identifies the scanning device, see visitorID.
+	visitor   string
+	device    string
+	os        string
+	scannedAt time.Time
+}
+
+func newScan(cardID, eventCardID, remoteIP, userAgent string) *scan {
+	device, os := classifyAgent(userAgent)
+
+	return &scan{
+		cardID:      cardID,
+		eventCardID: eventCardID,
+		visitor:     visitorID(remoteIP, userAgent),
+		device:      device,
+		os:          os,
+		scannedAt:   time.Now(),
+	}
+}
+
+// visitorID identifies a visitor by a hash of their remote IP and user
+// agent, so visitors can be told apart without storing their address.
+func visitorID(remoteIP, userAgent string) string {
+	sum := sha256.Sum256([]byte(remoteIP + "\x00" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// Devices and operating systems scans are broken down by.
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+	DeviceOther   = "other"
+
+	OSAndroid = "android"
+	OSIOS     = "ios"
+	OSWindows = "windows"
+	OSMacOS   = "macos"
+	OSLinux   = "linux"
+	OSOther   = "other"
+)
+
+// classifyAgent returns the kind of device and operating system a user
+// agent belongs to. It recognizes the common phone and desktop browsers,
+// not every agent; the rest are "other".
+func classifyAgent(ua string) (device, os string) {
+	ua = strings.ToLower(ua)
+
+	for _, bot := range []string{"bot", "crawler", "spider", "preview", "curl", "wget"} {
+		if strings.Contains(ua, bot) {
+			return DeviceBot, OSOther
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "android"):
+		os = OSAndroid
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		os = OSIOS
+	case strings.Contains(ua, "windows"):
+		os = OSWindows
+	case strings.Contains(ua, "mac os x"), strings.Contains(ua, "macintosh"):
+		os = OSMacOS
+	case strings.Contains(ua, "linux"), strings.Contains(ua, "x11"):
+		os = OSLinux
+	default:
+		os = OSOther
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad"), strings.Contains(ua, "tablet"),
+		os == OSAndroid && !strings.Contains(ua, "mobile"):
+		device = DeviceTablet
+	case strings.Contains(ua, "mobi"), os == OSIOS, os == OSAndroid:
+		device = DeviceMobile
+	case os == OSWindows, os == OSMacOS, os == OSLinux:
+		device = DeviceDesktop
+	default:
+		device = DeviceOther
+	}
+
+	return device, os
+}
+
+// recordScan logs a scan for the analytics reports. Bots such as link
+// previewers are not counted. A failure is logged and otherwise ignored:
+// the visitor still gets the card.
+func (s *Service) recordScan(ctx context.Context, zlog *zap.Logger, sc *scan) {
+	// Scans made while the database is down are not counted.
+	if sc.device == DeviceBot || s.health.ReadOnly() {
+		return
+	}
+	if err := createScan(ctx, s.db, sc); err != nil {
+		zlog.Warn("failed to record scan", zap.Error(err))
+	}
+}
+
+// Limits of the analytics queries.
+const (
+	defaultScanDays = 30
+	maxScanDays     = 366
+
+	defaultTopCards = 5
+	maxTopCards     = 50
+)
+
+// scanDate is the format of the dates scan reports take and return.
+const scanDate = time.DateOnly
+
+type ScanQuery struct {
+	// From and To are the first and last day of the report, inclusive, as
+	// YYYY-MM-DD in the caller's timezone. Default: the last 30 days.
+	From string `json:"from" query:"from"`
+	To   string `json:"to" query:"to"`
+
+	CompanyID    int64 `json:"companyId" query:"companyId"`
+	DepartmentID int64 `json:"departmentId" query:"departmentId"`
+
+	// Limit is the number of top cards per department. Default: 5.
+	Limit uint64 `json:"limit" query:"limit"`
+
+	// start and end bound the report in time, end exclusive. offset is
+	// the timezone's offset from UTC in minutes, which days are counted
+	// in. Set by Validate.
+	start, end time.Time
+	offset     int
+}
+
+// Validate checks the date range and resolves it in loc.
+func (q *ScanQuery) Validate(loc *time.Location) error {
+	violations := make([]*edPb.BadRequest_FieldViolation, 0)
+
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if q.To = strings.TrimSpace(q.To); q.To != "" {
+		t, err := time.ParseInLocation(scanDate, q.To, loc)
+		if err != nil {
+			violations = append(violations, i18n.Violation("to", i18n.InvalidDate))
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, 1-defaultScanDays)
+	if q.From = strings.TrimSpace(q.From); q.From != "" {
+		t, err := time.ParseInLocation(scanDate, q.From, loc)
+		if err != nil {
+			violations = append(violations, i18n.Violation("from", i18n.InvalidDate))
+		}
+		from = t
+	}
+
+	if len(violations) == 0 && (to.Before(from) || to.After(from.AddDate(0, 0, maxScanDays-1))) {
+		violations = append(violations, i18n.Violation("to", i18n.InvalidDateRange))
+	}
+
+	if q.Limit == 0 {
+		q.Limit = defaultTopCards
+	}
+	if q.Limit > maxTopCards {
+		q.Limit = maxTopCards
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidScanQuery).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	q.From = from.Format(scanDate)
+	q.To = to.Format(scanDate)
+	q.start = from.UTC()
+	q.end = to.AddDate(0, 0, 1).UTC()
+	_, offset := from.Zone()
+	q.offset = offset / 60
+
+	return nil
+}
+
+// key identifies the report of q in the cache.
+func (q *ScanQuery) key(report string) string {
+	return fmt.Sprintf("%s|%s|%s|%d|%d|%d|%d", report, q.From, q.To, q.offset, q.CompanyID, q.DepartmentID, q.Limit)
+}
+
+// Breakdown is the number of scans from one kind of device or OS.
+type Breakdown struct {
+	Name  string `json:"name"`
+	Scans int64  `json:"scans"`
+}
+
+type ScanSummary struct {
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	Scans          int64        `json:"scans"`
+	UniqueVisitors int64        `json:"uniqueVisitors"`
+	Devices        []*Breakdown `json:"devices"`
+	OS             []*Breakdown `json:"operatingSystems"`
+}
+
+type DailyScans struct {
+	Date           string `json:"date"`
+	Scans          int64  `json:"scans"`
+	UniqueVisitors int64  `json:"uniqueVisitors"`
+}
+
+type ListDailyScansResult struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Days []*DailyScans `json:"days"`
+}
+
+type TopCard struct {
+	CardID      string `json:"cardId"`
+	DisplayName string `json:"displayName"`
+	Scans       int64  `json:"scans"`
+}
+
+// DepartmentTopCards are the most scanned cards of a department. Guest
+// cards have no department and are listed under department 0.
+type DepartmentTopCards struct {
+	DepartmentID   int64      `json:"departmentId"`
+	DepartmentName string     `json:"departmentName"`
+	Cards          []*TopCard `json:"cards"`
+}
+
+type ListTopCardsResult struct {
+	From        string                `json:"from"`
+	To          string                `json:"to"`
+	Departments []*DepartmentTopCards `json:"departments"`
+}
+
+// GetScanSummary returns the number of scans and unique visitors in a date
+// range, broken down by device and operating system. It is for HR only.
+func (s *Service) GetScanSummary(ctx context.Context, in *ScanQuery) (*ScanSummary, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetScanSummary"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAnalytics) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AnalyticsForbidden)
+	}
+
+	if err := in.Validate(tz.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	key := in.key("summary")
+	if v, ok := s.reports.get(key); ok {
+		return v.(*ScanSummary), nil
+	}
+
+	summary, err := summarizeScans(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to summarize scans", zap.Error(err))
+		return nil, err
+	}
+	summary.From, summary.To = in.From, in.To
+
+	s.reports.set(key, summary)
+	return summary, nil
+}
+
+// ListDailyScans returns the number of scans and unique visitors for every
+// day in a date range, days without scans included. It is for HR only.
+func (s *Service) ListDailyScans(ctx context.Context, in *ScanQuery) (*ListDailyScansResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListDailyScans"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAnalytics) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AnalyticsForbidden)
+	}
+
+	loc := tz.FromContext(ctx)
+	if err := in.Validate(loc); err != nil {
+		return nil, err
+	}
+
+	key := in.key("daily")
+	if v, ok := s.reports.get(key); ok {
+		return v.(*ListDailyScansResult), nil
+	}
+
+	counted, err := countDailyScans(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to count daily scans", zap.Error(err))
+		return nil, err
+	}
+
+	byDate := make(map[string]*DailyScans, len(counted))
+	for _, d := range counted {
+		byDate[d.Date] = d
+	}
+
+	days := make([]*DailyScans, 0)
+	for d := in.start.In(loc); d.Before(in.end); d = d.AddDate(0, 0, 1) {
+		date := d.Format(scanDate)
+		if day, ok := byDate[date]; ok {
+			days = append(days, day)
+			continue
+		}
+		days = append(days, &DailyScans{Date: date})
+	}
+
+	res := &ListDailyScansResult{
+		From: in.From,
+		To:   in.To,
+		Days: days,
+	}
+	s.reports.set(key, res)
+	return res, nil
+}
+
+// ListTopCards returns the most scanned cards of each department in a date
+// range. It is for HR only.
+func (s *Service) ListTopCards(ctx context.Context, in *ScanQuery) (*ListTopCardsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListTopCards"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAnalytics) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.AnalyticsForbidden)
+	}
+
+	if err := in.Validate(tz.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	key := in.key("top-cards")
+	if v, ok := s.reports.get(key); ok {
+		return v.(*ListTopCardsResult), nil
+	}
+
+	departments, err := listTopCards(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to list top cards", zap.Error(err))
+		return nil, err
+	}
+	slices.SortFunc(departments, func(a, b *DepartmentTopCards) int {
+		return cmp.Or(
+			cmp.Compare(a.DepartmentName, b.DepartmentName),
+			cmp.Compare(a.DepartmentID, b.DepartmentID),
+		)
+	})
+
+	res := &ListTopCardsResult{
+		From:        in.From,
+		To:          in.To,
+		Departments: departments,
+	}
+	s.reports.set(key, res)
+	return res, nil
+}
@@ -0,0 +1,253 @@
+package card
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Limits of a bulk archive.
+const (
+	archivePageSize = 100
+
+	// maxBulkArchive is the most cards one bulk archive request archives;
+	// the caller repeats the request while HasMore is set.
+	maxBulkArchive = 1000
+)
+
+// Archived hides the card from listings, keeping it for audits. A published
+// card is no longer served publicly until it is unarchived.
+func (c *Card) Archived(by string) {
+	if c.Status == StatusArchived {
+		return
+	}
+
+	now := time.Now()
+	c.archivedFrom = c.Status
+	c.Status = StatusArchived
+	c.ArchivedAt = &now
+	c.updatedBy = by
+	c.UpdatedAt = now
+}
+
+// Unarchived restores the card to the status it was archived in.
+func (c *Card) Unarchived(by string) {
+	if c.Status != StatusArchived {
+		return
+	}
+
+	c.Status = cmp.Or(c.archivedFrom, StatusPending)
+	c.archivedFrom = StatusUnspecified
+	c.ArchivedAt = nil
+	c.updatedBy = by
+	c.UpdatedAt = time.Now()
+}
+
+type ArchiveBusinessCardReq struct {
+	ID string `json:"cardId" param:"id"`
+}
+
+func (r *ArchiveBusinessCardReq) Validate() error {
+	r.ID = strings.TrimSpace(r.ID)
+
+	violations, err := validate.Violations(&contactqrPb.ArchiveBusinessCardRequest{
+		CardId: r.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidArchive).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// ArchiveBusinessCard archives a card. It is for HR only.
+func (s *Service) ArchiveBusinessCard(ctx context.Context, in *ArchiveBusinessCardReq) (*Card, error) {
+	return s.setArchived(ctx, "ArchiveBusinessCard", in, true)
+}
+
+// UnarchiveBusinessCard restores an archived card. It is for HR only.
+func (s *Service) UnarchiveBusinessCard(ctx context.Context, in *ArchiveBusinessCardReq) (*Card, error) {
+	return s.setArchived(ctx, "UnarchiveBusinessCard", in, false)
+}
+
+func (s *Service) setArchived(ctx context.Context, method string, in *ArchiveBusinessCardReq, archived bool) (*Card, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", method),
+		zap.String("username", claims.Code),
+		zap.Any("req", in),
+	)
+
+	if !rbac.CanArchiveCard(ctx) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		ID: in.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	from := card.Status
+	if archived {
+		card.Archived(claims.Code)
+	} else {
+		card.Unarchived(claims.Code)
+	}
+
+	if card.Status != from {
+		if err := s.saveCard(ctx, card, from); err != nil {
+			zlog.Error("failed to update card", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return s.shapeCard(ctx, card, false), nil
+}
+
+// BatchArchiveReq selects the cards a bulk archive archives: those created
+// before CreatedBefore, optionally only of one department, company or
+// status.
+type BatchArchiveReq struct {
+	CreatedBefore time.Time `json:"createdBefore"`
+	DepartmentID  int64     `json:"departmentId"`
+	CompanyID     int64     `json:"companyId"`
+	Status        string    `json:"status"`
+
+	// DryRun counts the cards that would be archived without archiving
+	// them.
+	DryRun bool `json:"-" query:"dryRun"`
+}
+
+func (r *BatchArchiveReq) Validate() error {
+	r.Status = strings.ToUpper(strings.TrimSpace(r.Status))
+
+	req := &contactqrPb.BatchArchiveBusinessCardsRequest{
+		DepartmentId: r.DepartmentID,
+		CompanyId:    r.CompanyID,
+		Status:       r.Status,
+	}
+	if !r.CreatedBefore.IsZero() {
+		req.CreatedBefore = timestamppb.New(r.CreatedBefore)
+	}
+
+	violations, err := validate.Violations(req)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidBulkArchive).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+type BatchArchiveResult struct {
+	// Archived is the number of cards archived, or that would be in a dry
+	// run.
+	Archived int  `json:"archived"`
+	DryRun   bool `json:"dryRun"`
+
+	// HasMore is set when more cards match than one request archives;
+	// repeat the request to archive them.
+	HasMore bool `json:"hasMore"`
+}
+
+// BatchArchiveBusinessCards archives the cards selected by in, e.g. for a
+// periodic cleanup of old cards. It is for HR only.
+func (s *Service) BatchArchiveBusinessCards(ctx context.Context, in *BatchArchiveReq) (*BatchArchiveResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "BatchArchiveBusinessCards"),
+		zap.String("username", claims.Code),
+		zap.Any("req", in),
+	)
+
+	if !rbac.CanArchiveCard(ctx) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := &CardQuery{
+		DepartmentID:  in.DepartmentID,
+		CompanyID:     in.CompanyID,
+		Status:        in.Status,
+		CreatedBefore: in.CreatedBefore,
+		PageSize:      archivePageSize,
+	}
+
+	res := &BatchArchiveResult{DryRun: in.DryRun}
+	for {
+		cards, err := listCards(ctx, s.db, q)
+		if err != nil {
+			zlog.Error("failed to list cards", zap.Error(err))
+			return nil, err
+		}
+
+		for _, card := range cards {
+			if res.Archived == maxBulkArchive {
+				res.HasMore = true
+				return res, nil
+			}
+
+			if !in.DryRun {
+				from := card.Status
+				card.Archived(claims.Code)
+				if err := s.saveCard(ctx, card, from); err != nil {
+					zlog.Error("failed to update card", zap.String("card_id", card.ID), zap.Error(err))
+					return nil, err
+				}
+			}
+			res.Archived++
+		}
+
+		if len(cards) < archivePageSize {
+			break
+		}
+		last := cards[len(cards)-1]
+		q.PageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   last.ID,
+			Time: last.CreatedAt,
+		})
+	}
+
+	if !in.DryRun {
+		zlog.Info("archived cards", zap.Int("cards", res.Archived))
+	}
+	return res, nil
+}
@@ -0,0 +1,265 @@
+package card
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/imaging"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/wallet"
+	qrcode "github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// Preview PNGs are sized for link previews: the card's photo on the left
+// and the QR code of its vCard on the right, over its company's brand
+// color.
+const (
+	previewWidth  = 1200
+	previewHeight = 630
+	previewPhoto  = 420
+	previewMargin = 105
+)
+
+// artifactHash identifies what a card's preview and pass show: its vCard
+// with the photo when it has one, so a new photo renders new artifacts.
+func (c *Card) artifactHash() string {
+	if len(c.vcfPhoto) == 0 {
+		return c.vcfHash
+	}
+	sum := sha256.Sum256(c.vcfPhoto)
+	return hex.EncodeToString(sum[:])
+}
+
+func previewKey(hash string) string {
+	return fmt.Sprintf("previews/%s.png", hash)
+}
+
+func passKey(hash string) string {
+	return fmt.Sprintf("passes/%s.pkpass", hash)
+}
+
+// photoImage returns the decoded photo of a card, nil if it has none.
+func (s *Service) photoImage(ctx context.Context, c *Card) (image.Image, error) {
+	photo, err := s.photoOf(ctx, c)
+	if errors.Is(err, employee.ErrPhotoNotFound) || errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return imaging.Decode(photo.Data)
+}
+
+func (s *Service) brandColor(c *Card) color.RGBA {
+	b := s.brands.For(c.CompanyID)
+	return color.RGBA{R: b.R, G: b.G, B: b.B, A: 0xff}
+}
+
+// renderPreview renders the preview PNG of a card. A card without a photo
+// has its QR code centered.
+func (s *Service) renderPreview(ctx context.Context, c *Card) ([]byte, error) {
+	photo, err := s.photoImage(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := genQRPNG(c.vcf, qrcode.Medium, previewPhoto)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := imaging.Solid(s.brandColor(c), previewWidth, previewHeight)
+	qrAt := image.Pt((previewWidth-previewPhoto)/2, previewMargin)
+	if photo != nil {
+		at := image.Pt(previewMargin+15, previewMargin)
+		draw.Draw(dst, image.Rectangle{Min: at, Max: at.Add(image.Pt(previewPhoto, previewPhoto))}, imaging.Fill(photo, previewPhoto, previewPhoto), image.Point{}, draw.Src)
+		qrAt.X = previewWidth - previewMargin - 15 - previewPhoto
+	}
+	draw.Draw(dst, image.Rectangle{Min: qrAt, Max: qrAt.Add(qr.Bounds().Size())}, qr, qr.Bounds().Min, draw.Src)
+
+	return imaging.EncodePNG(dst)
+}
+
+// renderPass renders the Wallet pass of a card. Its QR code encodes the
+// photo-free vCard, like printed codes.
+func (s *Service) renderPass(ctx context.Context, c *Card) ([]byte, error) {
+	photo, err := s.photoImage(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.passes.Build(&wallet.Pass{
+		SerialNumber: c.PublicID,
+		Description:  "Business card of " + c.DisplayName,
+		Name:         c.DisplayName,
+		Fields: []wallet.Field{
+			{Key: "position", Label: "Position", Value: c.PositionName},
+			{Key: "department", Label: "Department", Value: c.DepartmentName},
+			{Key: "company", Label: "Company", Value: c.CompanyName},
+			{Key: "phone", Label: "Phone", Value: c.PhoneDisplay},
+		},
+		Barcode: string(c.vcf),
+		Color:   s.brandColor(c),
+		Photo:   photo,
+	})
+}
+
+// storePreview renders the preview PNG of a card and saves it to the
+// assets storage under a key derived from artifactHash.
+func (s *Service) storePreview(ctx context.Context, c *Card) (*storage.Object, error) {
+	data, err := s.renderPreview(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	key := previewKey(c.artifactHash())
+	if err := s.assets.Put(ctx, key, data); err != nil {
+		return nil, err
+	}
+
+	return &storage.Object{
+		Key:         key,
+		ContentType: "image/png",
+		Data:        data,
+		ModTime:     time.Now(),
+	}, nil
+}
+
+// storePass is storePreview for the Wallet pass.
+func (s *Service) storePass(ctx context.Context, c *Card) (*storage.Object, error) {
+	data, err := s.renderPass(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	key := passKey(c.artifactHash())
+	if err := s.assets.Put(ctx, key, data); err != nil {
+		return nil, err
+	}
+
+	return &storage.Object{
+		Key:         key,
+		ContentType: wallet.ContentType,
+		Data:        data,
+		ModTime:     time.Now(),
+	}, nil
+}
+
+// storeArtifacts renders the preview and pass of a card once it is
+// published or its photo changes. Like QR codes they are rendered again on
+// a miss, so a failure here is only logged.
+func (s *Service) storeArtifacts(ctx context.Context, zlog *zap.Logger, c *Card) {
+	if _, err := s.storePreview(ctx, c); err != nil {
+		zlog.Warn("failed to store preview", zap.Error(err))
+	}
+	if s.passes == nil {
+		return
+	}
+	if _, err := s.storePass(ctx, c); err != nil {
+		zlog.Warn("failed to store pass", zap.Error(err))
+	}
+}
+
+// GetPublicBusinessCardPreview returns the preview PNG of a published card,
+// shown when a link to it is shared.
+func (s *Service) GetPublicBusinessCardPreview(ctx context.Context, publicID string) (*storage.Object, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicBusinessCardPreview"),
+		zap.String("public_id", publicID),
+	)
+
+	card, err := s.publishedArtifactCard(ctx, zlog, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := s.assets.Get(ctx, previewKey(card.artifactHash()))
+	if err == nil {
+		return obj, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		zlog.Warn("failed to get stored preview", zap.Error(err))
+	}
+
+	obj, err = s.storePreview(ctx, card)
+	if err != nil {
+		zlog.Error("failed to store preview", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// GetPublicBusinessCardPass returns the Apple Wallet pass of a published
+// card, named after its public ID.
+func (s *Service) GetPublicBusinessCardPass(ctx context.Context, publicID string) (*storage.Object, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicBusinessCardPass"),
+		zap.String("public_id", publicID),
+	)
+
+	if s.passes == nil {
+		return nil, i18n.Error(codes.Unimplemented, i18n.WalletDisabled)
+	}
+
+	card, err := s.publishedArtifactCard(ctx, zlog, publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := s.assets.Get(ctx, passKey(card.artifactHash()))
+	if err != nil {
+		if !errors.Is(err, storage.ErrObjectNotFound) {
+			zlog.Warn("failed to get stored pass", zap.Error(err))
+		}
+		obj, err = s.storePass(ctx, card)
+		if err != nil {
+			zlog.Error("failed to store pass", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	obj.Key = card.PublicID + ".pkpass"
+	obj.ContentType = wallet.ContentType
+	return obj, nil
+}
+
+// publishedArtifactCard returns the published card with the given public
+// ID and its vCard, rendered if it was published before vCards were
+// stored.
+func (s *Service) publishedArtifactCard(ctx context.Context, zlog *zap.Logger, publicID string) (*Card, error) {
+	card, err := s.getPublishedCard(ctx, publicID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	if len(card.vcf) == 0 {
+		if err := card.renderVCF(); err != nil {
+			zlog.Error("failed to gen vcf", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return card, nil
+}
@@ -0,0 +1,94 @@
+package card
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/imaging"
+	"github.com/10664kls/contactqr/internal/poster"
+	"github.com/10664kls/contactqr/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+func newArtifactService(t *testing.T) *Service {
+	t.Helper()
+
+	assets, err := storage.NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDisk: %v", err)
+	}
+	return &Service{
+		assets:    assets,
+		brands:    poster.Brands{Default: poster.Color{R: 0, G: 82, B: 155}},
+		published: newCardCache(10, time.Hour),
+		zlog:      zap.NewNop(),
+	}
+}
+
+func TestPreviewEmbedsPhoto(t *testing.T) {
+	s := newArtifactService(t)
+	ctx := context.Background()
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	data, err := imaging.EncodePNG(imaging.Solid(red, 300, 400))
+	if err != nil {
+		t.Fatalf("EncodePNG: %v", err)
+	}
+	if err := s.assets.Put(ctx, "photos/red.png", data); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	c := &Card{ID: "C1", PublicID: "p1", DisplayName: "Visitor", vcf: []byte("BEGIN:VCARD\r\nEND:VCARD\r\n"), vcfHash: "h1"}
+	plain := c.artifactHash()
+
+	obj, err := s.storePreview(ctx, c)
+	if err != nil {
+		t.Fatalf("storePreview: %v", err)
+	}
+	img, err := imaging.Decode(obj.Data)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(previewWidth, previewHeight) {
+		t.Fatalf("preview is %v, want %dx%d", got, previewWidth, previewHeight)
+	}
+	if got := color.RGBAModel.Convert(img.At(previewWidth/4, previewHeight/2)); got == red {
+		t.Fatal("preview of a card without a photo shows one")
+	}
+
+	// The photo vCard changes with the photo, and with it the preview.
+	c.photoKey = "photos/red.png"
+	c.vcfPhoto = []byte("BEGIN:VCARD\r\nPHOTO:red\r\nEND:VCARD\r\n")
+	if c.artifactHash() == plain {
+		t.Fatal("artifactHash did not change with the photo")
+	}
+
+	obj, err = s.storePreview(ctx, c)
+	if err != nil {
+		t.Fatalf("storePreview: %v", err)
+	}
+	if obj.Key != previewKey(c.artifactHash()) {
+		t.Fatalf("preview stored as %q, want %q", obj.Key, previewKey(c.artifactHash()))
+	}
+	if img, err = imaging.Decode(obj.Data); err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if got := color.RGBAModel.Convert(img.At(previewWidth/4, previewHeight/2)); got != red {
+		t.Fatalf("preview shows %v where the photo goes, want %v", got, red)
+	}
+}
+
+func TestGetPassDisabled(t *testing.T) {
+	s := newArtifactService(t)
+	s.published.set(&Card{ID: "C1", PublicID: "p1"})
+
+	_, err := s.GetPublicBusinessCardPass(context.Background(), "p1")
+	if grpcStatus.Code(err) != codes.Unimplemented {
+		t.Fatalf("GetPublicBusinessCardPass without a signer = %v, want Unimplemented", err)
+	}
+}
@@ -0,0 +1,157 @@
+package card
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cardCache is a size-bounded LRU of published cards keyed by public ID.
+// Entries also expire after ttl so replicas that missed an invalidation
+// converge on the database state. Expired entries are kept until evicted,
+// to be served while the database is unreachable.
+type cardCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type cacheEntry struct {
+	card      *Card
+	expiresAt time.Time
+}
+
+func newCardCache(size int, ttl time.Duration) *cardCache {
+	return &cardCache{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *cardCache) get(publicID string) (*Card, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[publicID]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	card := *entry.card
+	return &card, true
+}
+
+// stale returns the cached card even if it expired.
+func (c *cardCache) stale(publicID string) (*Card, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[publicID]
+	if !ok {
+		return nil, false
+	}
+
+	card := *el.Value.(*cacheEntry).card
+	return &card, true
+}
+
+func (c *cardCache) set(card *Card) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cp := *card
+	entry := &cacheEntry{
+		card:      &cp,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+
+	if el, ok := c.items[card.PublicID]; ok {
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[card.PublicID] = c.ll.PushFront(entry)
+	if c.ll.Len() > c.size {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.items, last.Value.(*cacheEntry).card.PublicID)
+	}
+}
+
+func (c *cardCache) delete(publicID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[publicID]; ok {
+		c.ll.Remove(el)
+		delete(c.items, publicID)
+	}
+}
+
+// reportCache holds computed analytics reports for ttl. Reports are cheap
+// to recompute but expensive enough that a dashboard refreshing every few
+// seconds should not rerun them; being a few minutes stale is fine.
+type reportCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	items map[string]reportEntry
+}
+
+type reportEntry struct {
+	report    any
+	expiresAt time.Time
+}
+
+func newReportCache(size int, ttl time.Duration) *reportCache {
+	return &reportCache{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[string]reportEntry),
+	}
+}
+
+func (c *reportCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.report, true
+}
+
+func (c *reportCache) set(key string, report any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.items) >= c.size {
+		for k, e := range c.items {
+			if now.After(e.expiresAt) {
+				delete(c.items, k)
+			}
+		}
+	}
+	// Still full of live reports: start over rather than track recency.
+	if len(c.items) >= c.size {
+		clear(c.items)
+	}
+
+	c.items[key] = reportEntry{
+		report:    report,
+		expiresAt: now.Add(c.ttl),
+	}
+}
@@ -2,30 +2,85 @@ package card
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/audit"
 	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/corpmail"
 	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/health"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/jobs"
+	"github.com/10664kls/contactqr/internal/notify"
 	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/phone"
+	"github.com/10664kls/contactqr/internal/policy"
+	"github.com/10664kls/contactqr/internal/poster"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/template"
+	"github.com/10664kls/contactqr/internal/tracing"
+	"github.com/10664kls/contactqr/internal/utils"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/10664kls/contactqr/internal/visibility"
+	"github.com/10664kls/contactqr/internal/wallet"
 	"github.com/google/uuid"
-	e164 "github.com/nyaruka/phonenumbers"
+	qrcode "github.com/skip2/go-qrcode"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
-	rpcStatus "google.golang.org/grpc/status"
 )
 
 type Service struct {
-	employee *employee.Service
-	db       *sql.DB
-	zlog     *zap.Logger
+	employee  *employee.Service
+	assets    storage.Storage
+	audit     *audit.Log
+	outbox    *event.Outbox
+	notifier  notify.Notifier
+	regions   phone.Regions
+	styles    phone.Styles
+	emails    corpmail.Policy
+	brands    poster.Brands
+	limits    policy.Cards
+	health    *health.State
+	templates *template.Service
+	jobs      *jobs.Queue
+	db        *sql.DB
+
+	// shares encrypts the tokens of share links, nil if they are disabled.
+	shares *auth.KeyRing
+
+	// passes signs the Wallet passes of published cards, nil if they are
+	// disabled.
+	passes *wallet.Signer
+
+	// idempotency is how long a create retried with the same
+	// Idempotency-Key returns the card created first.
+	idempotency time.Duration
+	zlog        *zap.Logger
+
+	// published caches published cards for the public VCF path.
+	published *cardCache
+
+	// reports caches the scan analytics reports.
+	reports *reportCache
 }
 
-func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger, employee *employee.Service) (*Service, error) {
+// NewService creates a Service. A create retried with the same
+// Idempotency-Key within idempotency returns the card created first. A nil
+// shares disables share links and a nil passes Wallet passes.
+func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger, employee *employee.Service, assets storage.Storage, audit *audit.Log, outbox *event.Outbox, notifier notify.Notifier, regions phone.Regions, styles phone.Styles, emails corpmail.Policy, brands poster.Brands, limits policy.Cards, health *health.State, templates *template.Service, queue *jobs.Queue, shares *auth.KeyRing, passes *wallet.Signer, idempotency time.Duration) (*Service, error) {
 	if db == nil {
 		return nil, errors.New("db is nil")
 	}
@@ -36,23 +91,75 @@ func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger, employee *emplo
 	if employee == nil {
 		return nil, errors.New("employee is nil")
 	}
+	if assets == nil {
+		return nil, errors.New("assets is nil")
+	}
+	if audit == nil {
+		return nil, errors.New("audit is nil")
+	}
+	if outbox == nil {
+		return nil, errors.New("outbox is nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	if health == nil {
+		return nil, errors.New("health is nil")
+	}
+	if templates == nil {
+		return nil, errors.New("templates is nil")
+	}
+	if queue == nil {
+		return nil, errors.New("queue is nil")
+	}
+	if idempotency <= 0 {
+		return nil, errors.New("idempotency window must be positive")
+	}
 
-	return &Service{
-		db:       db,
-		zlog:     zlog,
-		employee: employee,
-	}, nil
+	s := &Service{
+		db:        db,
+		zlog:      zlog,
+		employee:  employee,
+		assets:    assets,
+		audit:     audit,
+		outbox:    outbox,
+		notifier:  notifier,
+		regions:   regions,
+		styles:    styles,
+		emails:    emails,
+		brands:    brands,
+		limits:    limits,
+		health:    health,
+		templates: templates,
+		jobs:      queue,
+		shares:    shares,
+		passes:    passes,
+
+		idempotency: idempotency,
+
+		published: newCardCache(1024, 5*time.Minute),
+		reports:   newReportCache(256, 5*time.Minute),
+	}
+	if err := queue.Handle(workflowMailJob, s.sendWorkflowMail); err != nil {
+		return nil, err
+	}
+	if err := queue.Handle(leadMailJob, s.sendLeadMail); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
 func (s *Service) CreateBusinessCard(ctx context.Context, in *CardReq) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "CreateBusinessCard"),
 		zap.Any("req", in),
 		zap.String("username", claims.Code),
 	)
 
+	in.region = s.regions.For(claims.CompanyID)
 	if err := in.Validate(); err != nil {
 		return nil, err
 	}
@@ -62,25 +169,103 @@ func (s *Service) CreateBusinessCard(ctx context.Context, in *CardReq) (*Card, e
 		return nil, err
 	}
 
+	// A retried create, which the pending card it made would otherwise
+	// fail, returns that card.
+	if in.IdempotencyKey != "" {
+		card, err := s.getIdempotentCard(ctx, employee.ID, in.IdempotencyKey)
+		if err == nil {
+			zlog.Info("create replayed", zap.String("card_id", card.ID))
+			return s.shapeCard(ctx, card, false), nil
+		}
+		if !errors.Is(err, ErrCardNotFound) {
+			zlog.Error("failed to get idempotent card", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	held := make([]policy.Card, 0)
+	err = iterCards(ctx, s.db, &CardQuery{EmployeeID: employee.ID}, 0, func(c *Card) error {
+		held = append(held, policy.Card{ID: c.ID, Status: c.Status.String()})
+		return nil
+	})
+	if err != nil {
+		zlog.Error("failed to list cards", zap.Error(err))
+		return nil, err
+	}
+	if err := s.limits.CheckNew(held); err != nil {
+		return nil, err
+	}
+
+	flagged, err := s.checkEmail(employee)
+	if err != nil {
+		return nil, err
+	}
+
 	employee.SetPhone(in.Phone.Number)
 	employee.SetMobile(in.Mobile.Number)
 	card := newCardFromEmployee(employee)
-	if err := createCard(ctx, s.db, card); err != nil {
+	card.setPhones(in.phone, in.mobile)
+	card.EmailFlagged = flagged
+	card.PhoneticGivenName = in.PhoneticGivenName
+	card.PhoneticFamilyName = in.PhoneticFamilyName
+	card.setNameLanguages(in.nameLanguages())
+	card.SocialLinks = in.SocialLinks
+	card.idempotencyKey = in.IdempotencyKey
+	err = s.saveCard(ctx, card, StatusUnspecified)
+	if errors.Is(err, errIdempotencyKeyUsed) {
+		// A concurrent retry created the card first.
+		card, err = s.getIdempotentCard(ctx, employee.ID, in.IdempotencyKey)
+	}
+	if err != nil {
 		zlog.Error("failed to create card", zap.Error(err))
 		return nil, err
 	}
-	return card, nil
+	return s.shapeCard(ctx, card, false), nil
+}
+
+// getIdempotentCard returns the card the employee created with key within
+// the idempotency window.
+func (s *Service) getIdempotentCard(ctx context.Context, employeeID int64, key string) (*Card, error) {
+	id, err := getIdempotentCardID(ctx, s.db, employeeID, key, time.Now().Add(-s.idempotency))
+	if err != nil {
+		return nil, err
+	}
+
+	return getCard(ctx, s.db, &CardQuery{
+		EmployeeID: employeeID,
+		ID:         id,
+	})
+}
+
+// PurgeIdempotencyKeys deletes the Idempotency-Keys past the window. It
+// runs as a scheduled job.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "PurgeIdempotencyKeys"),
+	)
+
+	n, err := deleteIdempotencyKeys(ctx, s.db, time.Now().Add(-s.idempotency))
+	if err != nil {
+		zlog.Error("failed to delete idempotency keys", zap.Error(err))
+		return err
+	}
+
+	if n > 0 {
+		zlog.Info("purged idempotency keys", zap.Int64("keys", n))
+	}
+	return nil
 }
 
 func (s *Service) UpdateBusinessCard(ctx context.Context, in *CardReq) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "UpdateBusinessCard"),
 		zap.Any("req", in),
 		zap.String("username", claims.Code),
 	)
 
+	in.region = s.regions.For(claims.CompanyID)
 	if err := in.Validate(); err != nil {
 		return nil, err
 	}
@@ -95,100 +280,156 @@ func (s *Service) UpdateBusinessCard(ctx context.Context, in *CardReq) (*Card, e
 		ID:         in.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
+	flagged, err := s.checkEmail(employee)
+	if err != nil {
+		return nil, err
+	}
+
+	from := card.Status
 	employee.SetPhone(in.Phone.Number)
 	employee.SetMobile(in.Mobile.Number)
-	card.UpdateFromEmployee(employee)
-	if err := updateCard(ctx, s.db, card); err != nil {
+	if err := card.UpdateFromEmployee(employee); err != nil {
+		return nil, err
+	}
+	card.setPhones(in.phone, in.mobile)
+	card.EmailFlagged = flagged
+	card.PhoneticGivenName = in.PhoneticGivenName
+	card.PhoneticFamilyName = in.PhoneticFamilyName
+	card.setNameLanguages(in.nameLanguages())
+	card.SocialLinks = in.SocialLinks
+	if err := s.saveCard(ctx, card, from); err != nil {
 		zlog.Error("failed to update card", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, false), nil
 }
 
 type ListCardsResult struct {
 	Cards         []*Card `json:"businessCards"`
 	NextPageToken string  `json:"nextPageToken"`
+
+	// TotalSize is the number of matching cards across all pages, set when
+	// the query asks for it.
+	TotalSize *int64 `json:"totalSize,omitempty"`
+}
+
+// listPage returns the page of cards req selects, with the token of the
+// next page in cursor mode and the total if req asks for it.
+func (s *Service) listPage(ctx context.Context, req *CardQuery, approver bool) (*ListCardsResult, error) {
+	ctx, span := tracing.Start(ctx, "card.listPage")
+	defer span.End()
+
+	cards, err := listCards(ctx, s.db, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListCardsResult{Cards: s.shapeCards(ctx, cards, approver)}
+	if l := len(cards); req.Page == 0 && l > 0 && l == int(pager.Size(req.PageSize)) {
+		last := cards[l-1]
+		res.NextPageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   last.ID,
+			Time: last.CreatedAt,
+		})
+	}
+	if req.IncludeTotal {
+		total, err := countCards(ctx, s.db, req)
+		if err != nil {
+			return nil, err
+		}
+		res.TotalSize = &total
+	}
+
+	return res, nil
 }
 
 func (s *Service) ListBusinessCards(ctx context.Context, req *CardQuery) (*ListCardsResult, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "ListBusinessCards"),
 		zap.Any("req", req),
 		zap.String("username", claims.Code),
 	)
 
-	if !claims.IsHR {
-		return nil, rpcStatus.Error(
-			codes.PermissionDenied,
-			"You are not allowed to access theses business cards.",
-		)
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
 	}
 
-	cards, err := listCards(ctx, s.db, req)
+	res, err := s.listPage(ctx, req, false)
 	if err != nil {
 		zlog.Error("failed to list business cards", zap.Error(err))
 		return nil, err
 	}
 
-	var pageToken string
-	if l := len(cards); l > 0 && l == int(pager.Size(req.PageSize)) {
-		last := cards[l-1]
-		pageToken = pager.EncodeCursor(&pager.Cursor{
-			ID:   last.ID,
-			Time: last.CreatedAt,
-		})
+	return res, nil
+}
+
+// StreamBusinessCards calls fn for every card matching req, ignoring the
+// page size, so large result sets can be written out as they are read.
+func (s *Service) StreamBusinessCards(ctx context.Context, req *CardQuery, fn func(*Card) error) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "StreamBusinessCards"),
+		zap.Any("req", req),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
 	}
 
-	return &ListCardsResult{
-		Cards:         cards,
-		NextPageToken: pageToken,
-	}, nil
+	err := iterCards(ctx, s.db, req, 0, func(c *Card) error {
+		return fn(s.shapeCard(ctx, c, false))
+	})
+	if err != nil {
+		zlog.Error("failed to stream business cards", zap.Error(err))
+		return err
+	}
+
+	return nil
 }
 
 func (s *Service) GetBusinessCardByID(ctx context.Context, id string) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetBusinessCardByID"),
 		zap.String("username", claims.Code),
 		zap.String("id", id),
 	)
 
-	if !claims.IsHR {
-		return nil, rpcStatus.Error(
-			codes.PermissionDenied,
-			"You are not allowed to access this card or (it may not exist)",
-		)
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 
 	card, err := getCard(ctx, s.db, &CardQuery{
 		ID: id,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, false), nil
 }
 
 func (s *Service) GetMyBusinessCardByID(ctx context.Context, id string) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetMyBusinessCardByID"),
 		zap.String("username", claims.Code),
 		zap.String("id", id),
@@ -199,51 +440,39 @@ func (s *Service) GetMyBusinessCardByID(ctx context.Context, id string) (*Card,
 		EmployeeID: claims.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, false), nil
 }
 
 func (s *Service) ListMyApprovalBusinessCards(ctx context.Context, req *CardQuery) (*ListCardsResult, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "ListMyApprovalBusinessCards"),
 		zap.Any("req", req),
 		zap.String("username", claims.Code),
 	)
 
 	req.managerID = claims.ID
-	cards, err := listCards(ctx, s.db, req)
+	res, err := s.listPage(ctx, req, true)
 	if err != nil {
 		zlog.Error("failed to list cards", zap.Error(err))
 		return nil, err
 	}
 
-	var pageToken string
-	if l := len(cards); l > 0 && l == int(pager.Size(req.PageSize)) {
-		last := cards[l-1]
-		pageToken = pager.EncodeCursor(&pager.Cursor{
-			ID:   last.ID,
-			Time: last.CreatedAt,
-		})
-	}
-
-	return &ListCardsResult{
-		Cards:         cards,
-		NextPageToken: pageToken,
-	}, nil
+	return res, nil
 }
 
 func (s *Service) GetMyApprovalBusinessCardByID(ctx context.Context, id string) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetMyApprovalBusinessCardByID"),
 		zap.String("username", claims.Code),
 		zap.String("id", id),
@@ -254,67 +483,55 @@ func (s *Service) GetMyApprovalBusinessCardByID(ctx context.Context, id string)
 		managerID: claims.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, true), nil
 }
 
 func (s *Service) ListMyBusinessCards(ctx context.Context, req *CardQuery) (*ListCardsResult, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "ListMyBusinessCards"),
 		zap.Any("req", req),
 		zap.String("username", claims.Code),
 	)
 
 	req.EmployeeID = claims.ID
-	cards, err := listCards(ctx, s.db, req)
+	res, err := s.listPage(ctx, req, false)
 	if err != nil {
 		zlog.Error("failed to list cards", zap.Error(err))
 		return nil, err
 	}
 
-	var pageToken string
-	if l := len(cards); l > 0 && l == int(pager.Size(req.PageSize)) {
-		last := cards[l-1]
-		pageToken = pager.EncodeCursor(&pager.Cursor{
-			ID:   last.ID,
-			Time: last.CreatedAt,
-		})
-	}
-
-	return &ListCardsResult{
-		Cards:         cards,
-		NextPageToken: pageToken,
-	}, nil
+	return res, nil
 }
 
 type ApproveBusinessCardReq struct {
 	ID string `json:"cardId" param:"id"`
+
+	// DryRun runs every check and returns the resulting card without
+	// saving it.
+	DryRun bool `json:"-" query:"dryRun"`
 }
 
 func (r *ApproveBusinessCardReq) Validate() error {
-	violations := make([]*edPb.BadRequest_FieldViolation, 0)
-
 	r.ID = strings.TrimSpace(r.ID)
-	if r.ID == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "cardId",
-			Description: "cardId must not be empty",
-		})
+
+	violations, err := validate.Violations(&contactqrPb.ApproveBusinessCardRequest{
+		CardId: r.ID,
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(violations) > 0 {
-		s, _ := rpcStatus.New(
-			codes.InvalidArgument,
-			"Your approval business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
-		).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidApproval).WithDetails(&edPb.BadRequest{FieldViolations: violations})
 		return s.Err()
 	}
 
@@ -324,7 +541,7 @@ func (r *ApproveBusinessCardReq) Validate() error {
 func (s *Service) ApproveBusinessCard(ctx context.Context, in *ApproveBusinessCardReq) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "ApproveBusinessCard"),
 		zap.String("username", claims.Code),
 		zap.String("req", in.ID),
@@ -339,54 +556,50 @@ func (s *Service) ApproveBusinessCard(ctx context.Context, in *ApproveBusinessCa
 		managerID: claims.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
+	from := card.Status
 	if err := card.Approved(claims.Code); err != nil {
 		return nil, err
 	}
 
-	if err := updateCard(ctx, s.db, card); err != nil {
+	if in.DryRun {
+		return s.shapeCard(ctx, card, true), nil
+	}
+
+	if err := s.saveCard(ctx, card, from); err != nil {
 		zlog.Error("failed to update card", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, true), nil
 }
 
 type RejectBusinessCardReq struct {
 	Remark string `json:"remark"`
 	ID     string `json:"cardId" param:"id"`
+	DryRun bool   `json:"-" query:"dryRun"` // See ApproveBusinessCardReq.
 }
 
 func (r *RejectBusinessCardReq) Validate() error {
-	violations := make([]*edPb.BadRequest_FieldViolation, 0)
-
 	r.ID = strings.TrimSpace(r.ID)
-	if r.ID == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "cardId",
-			Description: "cardId must not be empty",
-		})
-	}
-
 	r.Remark = strings.TrimSpace(r.Remark)
-	if r.Remark == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "remark",
-			Description: "remark must not be empty",
-		})
+
+	violations, err := validate.Violations(&contactqrPb.RejectBusinessCardRequest{
+		CardId: r.ID,
+		Remark: r.Remark,
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(violations) > 0 {
-		s, _ := rpcStatus.New(
-			codes.InvalidArgument,
-			"Your reject business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
-		).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidRejection).WithDetails(&edPb.BadRequest{FieldViolations: violations})
 		return s.Err()
 	}
 
@@ -396,7 +609,7 @@ func (r *RejectBusinessCardReq) Validate() error {
 func (s *Service) RejectBusinessCard(ctx context.Context, in *RejectBusinessCardReq) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "RejectBusinessCard"),
 		zap.String("username", claims.Code),
 		zap.Any("req", in),
@@ -411,45 +624,47 @@ func (s *Service) RejectBusinessCard(ctx context.Context, in *RejectBusinessCard
 		managerID: claims.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
+	from := card.Status
 	if err := card.Rejected(claims.Code, in.Remark); err != nil {
 		return nil, err
 	}
 
-	if err := updateCard(ctx, s.db, card); err != nil {
+	if in.DryRun {
+		return s.shapeCard(ctx, card, true), nil
+	}
+
+	if err := s.saveCard(ctx, card, from); err != nil {
 		zlog.Error("failed to update card", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	return s.shapeCard(ctx, card, true), nil
 }
 
 type PublishBusinessCardReq struct {
-	ID string `json:"cardId" param:"id"`
+	ID     string `json:"cardId" param:"id"`
+	DryRun bool   `json:"-" query:"dryRun"` // See ApproveBusinessCardReq.
 }
 
 func (r *PublishBusinessCardReq) Validate() error {
-	violations := make([]*edPb.BadRequest_FieldViolation, 0)
-
 	r.ID = strings.TrimSpace(r.ID)
-	if r.ID == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "cardId",
-			Description: "cardId must not be empty",
-		})
+
+	violations, err := validate.Violations(&contactqrPb.PublishBusinessCardRequest{
+		CardId: r.ID,
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(violations) > 0 {
-		s, _ := rpcStatus.New(
-			codes.InvalidArgument,
-			"Your publish business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
-		).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPublication).WithDetails(&edPb.BadRequest{FieldViolations: violations})
 		return s.Err()
 	}
 
@@ -459,17 +674,14 @@ func (r *PublishBusinessCardReq) Validate() error {
 func (s *Service) PublishBusinessCard(ctx context.Context, in *PublishBusinessCardReq) (*Card, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "PublishBusinessCard"),
 		zap.String("username", claims.Code),
 		zap.Any("req", in),
 	)
 
-	if !claims.IsHR {
-		return nil, rpcStatus.Error(
-			codes.PermissionDenied,
-			"You are not allowed to access this card or (it may not exist)",
-		)
+	if !rbac.CanPublishCard(ctx) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 
 	if err := in.Validate(); err != nil {
@@ -480,103 +692,195 @@ func (s *Service) PublishBusinessCard(ctx context.Context, in *PublishBusinessCa
 		ID: in.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
+	from := card.Status
 	if err := card.Published(claims.Code); err != nil {
 		return nil, err
 	}
 
-	if err := updateCard(ctx, s.db, card); err != nil {
+	// A dry run leaves a new public ID unassigned, since it would not be
+	// reserved for the card.
+	if card.PublicID == "" && !in.DryRun {
+		card.PublicID = newPublicID()
+	}
+
+	if err := card.renderVCF(); err != nil {
+		zlog.Error("failed to gen vcf", zap.Error(err))
+		return nil, err
+	}
+	if err := s.renderPhotoVCF(ctx, card); err != nil {
+		zlog.Error("failed to gen photo vcf", zap.Error(err))
+		return nil, err
+	}
+
+	if in.DryRun {
+		return s.shapeCard(ctx, card, false), nil
+	}
+
+	if err := s.saveCard(ctx, card, from); err != nil {
 		zlog.Error("failed to update card", zap.Error(err))
 		return nil, err
 	}
 
-	return card, nil
+	// QR artifacts are derived from the stored vCard, so a failure here only
+	// costs a render on the first scan.
+	for _, format := range []string{QRFormatPNG, QRFormatSVG} {
+		if _, err := s.storeQR(ctx, card, format); err != nil {
+			zlog.Warn("failed to store qr", zap.String("format", format), zap.Error(err))
+		}
+	}
+	s.storeArtifacts(ctx, zlog, card)
+
+	return s.shapeCard(ctx, card, false), nil
 }
 
 type CardReq struct {
 	ID     string      `json:"-" param:"id"`
 	Phone  PhoneNumber `json:"phone"`
 	Mobile PhoneNumber `json:"mobile"`
+
+	// Phonetic spelling of the name, optional. Phones use it to sort and
+	// pronounce the imported contact.
+	PhoneticGivenName  string `json:"phoneticGivenName"`
+	PhoneticFamilyName string `json:"phoneticFamilyName"`
+
+	// NameLanguages are the languages the name is shown in, "en" and "lo",
+	// the first being the display name. Default: the card's current ones,
+	// English for a new card.
+	NameLanguages []string `json:"nameLanguages"`
+
+	SocialLinks SocialLinks `json:"socialLinks"`
+
+	// IdempotencyKey, from the Idempotency-Key header, makes a retried
+	// create return the card created first. Optional; ignored by updates.
+	IdempotencyKey string `json:"-"`
+
+	// region is the caller's company default for numbers sent without a
+	// country.
+	region string
+
+	// phone and mobile are set by Validate.
+	phone  *phone.Number
+	mobile *phone.Number
+}
+
+// nameLanguages returns the languages the request asks the name in.
+func (r *CardReq) nameLanguages() []i18n.Lang {
+	langs := make([]i18n.Lang, 0, len(r.NameLanguages))
+	for _, l := range r.NameLanguages {
+		langs = append(langs, i18n.Lang(l))
+	}
+	return langs
+}
+
+// SocialLinks are the owner's accounts a card links to, all optional.
+type SocialLinks struct {
+	// WhatsApp is a phone number, parsed like the card's phone and stored
+	// in E.164.
+	WhatsApp string `json:"whatsapp"`
+
+	Line     string `json:"line"`     // LINE ID.
+	WeChat   string `json:"wechat"`   // WeChat ID.
+	LinkedIn string `json:"linkedin"` // Profile URL, e.g. https://www.linkedin.com/in/name.
+	Website  string `json:"website"`  // https URL.
+}
+
+func (l *SocialLinks) Proto() *contactqrPb.SocialLinks {
+	return &contactqrPb.SocialLinks{
+		Whatsapp: l.WhatsApp,
+		Line:     l.Line,
+		Wechat:   l.WeChat,
+		Linkedin: l.LinkedIn,
+		Website:  l.Website,
+	}
 }
 
 type PhoneNumber struct {
-	// ISO Alpha-2 code: "LA", "TH", "US", etc.
+	// ISO Alpha-2 code: "LA", "TH", "US", etc. Default: the company's region.
 	Country string `json:"country"`
 
-	// Phone number in E.164 format.
+	// Phone number in E.164, international or national format, e.g.
+	// "+8562055123456", "+856 20 55 123 456" or "020 55 123 456".
 	Number string `json:"number"`
 }
 
 func (r *CardReq) Validate() error {
-	violations := make([]*edPb.BadRequest_FieldViolation, 0)
-
-	r.Phone.Number = strings.TrimSpace(r.Phone.Number)
-	if r.Phone.Number == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "phone.number",
-			Description: "phone number must not be empty",
-		})
+	r.PhoneticGivenName = strings.TrimSpace(r.PhoneticGivenName)
+	r.PhoneticFamilyName = strings.TrimSpace(r.PhoneticFamilyName)
+	r.IdempotencyKey = strings.TrimSpace(r.IdempotencyKey)
+	for i, l := range r.NameLanguages {
+		r.NameLanguages[i] = strings.ToLower(strings.TrimSpace(l))
 	}
-
-	r.Phone.Country = strings.TrimSpace(r.Phone.Country)
-	if r.Phone.Country == "" {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "phone.country",
-			Description: "phone country must not be empty.",
-		})
+	links := &r.SocialLinks
+	links.WhatsApp = strings.TrimSpace(links.WhatsApp)
+	links.Line = strings.ToLower(strings.TrimSpace(links.Line))
+	links.WeChat = strings.TrimSpace(links.WeChat)
+	links.LinkedIn = strings.TrimSpace(links.LinkedIn)
+	links.Website = strings.TrimSpace(links.Website)
+
+	violations, err := validate.Violations(&contactqrPb.BusinessCardRequest{
+		Phone:              &contactqrPb.PhoneNumber{Country: r.Phone.Country, Number: r.Phone.Number},
+		Mobile:             &contactqrPb.PhoneNumber{Country: r.Mobile.Country, Number: r.Mobile.Number},
+		PhoneticGivenName:  r.PhoneticGivenName,
+		PhoneticFamilyName: r.PhoneticFamilyName,
+		NameLanguages:      r.NameLanguages,
+		SocialLinks:        links.Proto(),
+		IdempotencyKey:     r.IdempotencyKey,
+	})
+	if err != nil {
+		return err
 	}
 
-	phone, err := e164.Parse(r.Phone.Number, r.Phone.Country)
-	if err != nil {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "phone.number",
-			Description: "phone number must be a valid number",
-		})
+	region := strings.TrimSpace(r.Phone.Country)
+	if region == "" {
+		region = r.region
 	}
-	if !e164.IsValidNumber(phone) {
-		violations = append(violations, &edPb.BadRequest_FieldViolation{
-			Field:       "phone.number",
-			Description: "phone number must be a valid number",
-		})
+
+	r.Phone.Number = strings.TrimSpace(r.Phone.Number)
+	if r.Phone.Number == "" {
+		violations = append(violations, i18n.Violation("phone.number", i18n.Required))
+	} else if n, err := phone.Parse(r.Phone.Number, region); err != nil {
+		violations = append(violations, i18n.Violation("phone.number", i18n.InvalidPhone))
+	} else {
+		r.phone = n
+		r.Phone.Number = n.International
+		r.Phone.Country = n.Region
 	}
-	r.Phone.Number = e164.Format(phone, e164.INTERNATIONAL)
 
+	r.Mobile.Number = strings.TrimSpace(r.Mobile.Number)
 	if r.Mobile.Number != "" {
-		r.Mobile.Country = strings.TrimSpace(r.Mobile.Country)
-		if r.Mobile.Country == "" {
-			violations = append(violations, &edPb.BadRequest_FieldViolation{
-				Field:       "mobile.country",
-				Description: "mobile country must not be empty",
-			})
+		region := strings.TrimSpace(r.Mobile.Country)
+		if region == "" {
+			region = r.region
 		}
 
-		mobile, err := e164.Parse(r.Mobile.Number, r.Mobile.Country)
+		n, err := phone.Parse(r.Mobile.Number, region)
 		if err != nil {
-			violations = append(violations, &edPb.BadRequest_FieldViolation{
-				Field:       "mobile.number",
-				Description: "mobile number must be a valid number",
-			})
+			violations = append(violations, i18n.Violation("mobile.number", i18n.InvalidPhone))
+		} else {
+			r.mobile = n
+			r.Mobile.Number = n.International
+			r.Mobile.Country = n.Region
 		}
-		if !e164.IsValidNumber(mobile) {
-			violations = append(violations, &edPb.BadRequest_FieldViolation{
-				Field:       "mobile.number",
-				Description: "mobile number must be a valid number",
-			})
+	}
+
+	if links.WhatsApp != "" {
+		n, err := phone.Parse(links.WhatsApp, r.region)
+		if err != nil {
+			violations = append(violations, i18n.Violation("socialLinks.whatsapp", i18n.InvalidPhone))
+		} else {
+			links.WhatsApp = n.E164
 		}
-		r.Mobile.Number = e164.Format(mobile, e164.INTERNATIONAL)
 	}
 
 	if len(violations) > 0 {
-		s, _ := rpcStatus.New(
-			codes.InvalidArgument,
-			"Card is not valid or incomplete. Please check the errors and try again, see details for more information.",
-		).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidCard).WithDetails(&edPb.BadRequest{FieldViolations: violations})
 		return s.Err()
 	}
 
@@ -585,65 +889,685 @@ func (r *CardReq) Validate() error {
 
 type VCF struct {
 	Content string `json:"vcf"`
+	Hash    string `json:"hash"`
 }
 
-func (s *Service) GetMyVCFBusinessCardByID(ctx context.Context, id string) (*VCF, error) {
-	// claims := auth.ClaimsFromContext(ctx)
+type VCFReq struct {
+	// ID is the card ID on the authenticated route and the public ID on
+	// the public route.
+	ID string `json:"id" param:"id"`
+
+	// Version is the vCard version, 2.1 or 4.0. Default: 2.1.
+	Version string `json:"version" query:"version"`
+
+	// Legacy selects the vCard 2.1 quoted-printable output for older phones.
+	Legacy bool `json:"legacy" query:"legacy"`
 
-	zlog := s.zlog.With(
+	// Merged combines all published cards of the card's owner into one
+	// contact, the requested card first. Public route only.
+	Merged bool `json:"merged" query:"merged"`
+
+	remoteIP  string
+	userAgent string
+
+	// baseURL is where the public routes are served, e.g.
+	// https://cards.example.com, for the photo link of vCard 4.0.
+	baseURL string
+}
+
+// SetBaseURL records where the public routes are served.
+func (r *VCFReq) SetBaseURL(u string) {
+	r.baseURL = strings.TrimRight(u, "/")
+}
+
+// SetClient records who requested the vCard for the public access log.
+func (r *VCFReq) SetClient(remoteIP, userAgent string) {
+	r.remoteIP = remoteIP
+	r.userAgent = userAgent
+}
+
+func (r *VCFReq) Validate() error {
+	r.Version = strings.TrimSpace(r.Version)
+	if r.Version == "" {
+		r.Version = VCardV21
+	}
+
+	violations, err := validate.Violations(&contactqrPb.GetVCFRequest{
+		Id:      r.ID,
+		Version: r.Version,
+		Legacy:  r.Legacy,
+	})
+	if err != nil {
+		return err
+	}
+	// Quoted-printable is a vCard 2.1 encoding.
+	if r.Legacy && r.Version != VCardV21 {
+		violations = append(violations, i18n.Violation("legacy", i18n.InvalidValue))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidVCF).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// vcfOptions returns the encoding of card the request asks for.
+func (r *VCFReq) vcfOptions(card *Card) *vcfOptions {
+	opts := &vcfOptions{
+		version: r.Version,
+		legacy:  r.Legacy,
+	}
+	hasPhoto := card.photoKey != "" || len(card.vcfPhoto) > 0
+	if r.Version == VCardV4 && r.baseURL != "" && card.PublicID != "" && hasPhoto {
+		opts.photoURL = r.baseURL + "/v1/public/business-cards/" + card.PublicID + "/photo"
+	}
+
+	return opts
+}
+
+func (s *Service) GetMyVCFBusinessCardByID(ctx context.Context, in *VCFReq) (*VCF, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetMyVCFBusinessCardByID"),
-		// zap.String("username", claims.Code),
-		zap.String("id", id),
+		zap.String("username", claims.Code),
+		zap.Any("req", in),
 	)
 
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
 	card, err := getCard(ctx, s.db, &CardQuery{
-		ID: id,
-		// EmployeeID: claims.ID,
+		ID:         in.ID,
+		EmployeeID: claims.ID,
 	})
 	if errors.Is(err, ErrCardNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get card by id", zap.Error(err))
 		return nil, err
 	}
 
-	if card.Status != StatusPublished {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this card or (it may not exist)")
+	err = showPublished(ctx, s.db, card)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card version", zap.Error(err))
+		return nil, err
+	}
+
+	card.vcf, card.vcfHash, err = getCardVCF(ctx, s.db, card.ID)
+	if err != nil {
+		zlog.Error("failed to get card vcf", zap.Error(err))
+		return nil, err
+	}
+	card.vcfPhoto, err = getCardPhotoVCF(ctx, s.db, card.ID)
+	if err != nil {
+		zlog.Error("failed to get card photo vcf", zap.Error(err))
+		return nil, err
 	}
 
-	byt, err := genVCF(card)
+	vcf, err := encodeVCF(ctx, card, in.vcfOptions(card))
 	if err != nil {
 		zlog.Error("failed to gen vcf", zap.Error(err))
 		return nil, err
 	}
 
+	return vcf, nil
+}
+
+// GetPublicVCFBusinessCard serves the vCard behind a card's QR code to
+// anonymous visitors. Every access is written to the public access log.
+func (s *Service) GetPublicVCFBusinessCard(ctx context.Context, in *VCFReq) (*VCF, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicVCFBusinessCard"),
+		zap.Any("req", in),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := s.getPublishedCard(ctx, in.ID)
+	if errors.Is(err, ErrCardNotFound) {
+		zlog.Info("public card access denied", zap.String("remote_ip", in.remoteIP))
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Info("public card accessed",
+		zap.String("card_id", card.ID),
+		zap.String("remote_ip", in.remoteIP),
+		zap.String("user_agent", in.userAgent),
+	)
+	s.recordScan(ctx, zlog, newScan(card.ID, "", in.remoteIP, in.userAgent))
+	s.countDownload(ctx, zlog, card.ID, in.userAgent)
+
+	if in.Merged {
+		// While the database is down the card's own vCard is served instead.
+		roles, err := s.listRoles(ctx, card)
+		if err != nil && !s.health.ReadOnly() {
+			zlog.Error("failed to list roles", zap.Error(err))
+			return nil, err
+		}
+		if len(roles) > 0 {
+			vcf, err := encodeMergedVCF(ctx, card, roles, in.vcfOptions(card))
+			if err != nil {
+				zlog.Error("failed to gen merged vcf", zap.Error(err))
+				return nil, err
+			}
+			return vcf, nil
+		}
+	}
+
+	vcf, err := encodeVCF(ctx, card, in.vcfOptions(card))
+	if err != nil {
+		zlog.Error("failed to gen vcf", zap.Error(err))
+		return nil, err
+	}
+
+	return vcf, nil
+}
+
+// encodeVCF returns the vCard stored at publish time, with the owner's photo
+// when there is one, generating it only for the legacy format, vCard 4.0 or
+// cards published before vCards were stored.
+func encodeVCF(ctx context.Context, card *Card, opts *vcfOptions) (*VCF, error) {
+	_, span := tracing.Start(ctx, "card.encodeVCF", attribute.String("card.id", card.ID))
+	defer span.End()
+
+	stored := !opts.legacy && opts.version != VCardV4
+
+	byt, hash := card.vcf, card.vcfHash
+	if stored && len(card.vcfPhoto) > 0 {
+		byt, hash = card.vcfPhoto, vcfHash(card.vcfPhoto)
+	}
+	if !stored || len(byt) == 0 {
+		var err error
+		byt, err = genVCF(card, opts)
+		if err != nil {
+			return nil, err
+		}
+		hash = vcfHash(byt)
+	}
+
 	return &VCF{
 		Content: base64.StdEncoding.EncodeToString(byt),
+		Hash:    hash,
 	}, nil
 }
 
+// checkEmail applies the corporate email policy to the email a card of e
+// is issued with. It reports whether the card must be flagged for the
+// approver, or rejects it when the policy says so.
+func (s *Service) checkEmail(e *employee.Employee) (bool, error) {
+	if e.Email == "" || s.emails.Corporate(e.CompanyID, e.Email) {
+		return false, nil
+	}
+	if s.emails.Mode != corpmail.Reject {
+		return true, nil
+	}
+
+	violations := []*edPb.BadRequest_FieldViolation{
+		i18n.Violation("emailAddress", i18n.NonCorporateEmail),
+	}
+	st, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidCard).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+	return false, st.Err()
+}
+
+// saveCard creates or updates card and records its status change from the
+// given status in the audit log, in a single transaction. The emails of the
+// change are queued in it too, and a card being published has its version
+// kept. A from status of StatusUnspecified creates the
+// card.
+func (s *Service) saveCard(ctx context.Context, card *Card, from status) error {
+	err := utils.WithTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		if from == StatusUnspecified {
+			if err := createCard(ctx, tx, card); err != nil {
+				return err
+			}
+			if card.idempotencyKey != "" {
+				now := time.Now()
+				created, err := createIdempotencyKey(ctx, tx, card.EmployeeID, card.idempotencyKey, card.ID, now.Add(-s.idempotency), now)
+				if err != nil {
+					return err
+				}
+				if !created {
+					return errIdempotencyKeyUsed
+				}
+			}
+		} else {
+			if err := updateCard(ctx, tx, card); err != nil {
+				return err
+			}
+		}
+		if card.Status == StatusPublished && from != StatusPublished {
+			// A restored card is published again without its vCard being
+			// rendered, the version needs one all the same.
+			if len(card.vcf) == 0 {
+				if err := card.renderVCF(); err != nil {
+					return err
+				}
+			}
+			if err := createCardVersion(ctx, tx, card); err != nil {
+				return err
+			}
+		}
+
+		err := s.audit.Record(ctx, tx, &audit.Entry{
+			CardID:     card.ID,
+			FromStatus: from.String(),
+			ToStatus:   card.Status.String(),
+			Remark:     card.Remark,
+			Actor:      card.updatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.enqueueWorkflowMail(ctx, tx, card, from); err != nil {
+			return err
+		}
+
+		// The event is stored with the change and relayed to downstream
+		// systems after commit.
+		typ, ok := eventType(from, card.Status)
+		if !ok {
+			return nil
+		}
+		return s.outbox.Enqueue(ctx, tx, &event.Event{
+			ID:         uuid.NewString(),
+			Type:       typ,
+			CardID:     card.ID,
+			PublicID:   card.PublicID,
+			EmployeeID: card.EmployeeID,
+			Status:     card.Status.String(),
+			Actor:      card.updatedBy,
+			OccurredAt: card.UpdatedAt,
+			RequestID:  reqid.FromContext(ctx),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.published.delete(card.PublicID)
+
+	return nil
+}
+
+// eventType returns the event emitted when a card moves from one status to
+// another. Edits that keep a card pending emit nothing.
+func eventType(from, to status) (event.Type, bool) {
+	switch {
+	case from == StatusUnspecified:
+		return event.TypeCreated, true
+
+	case from == to:
+		return "", false
+
+	// Restoring an archived card only concerns downstream systems when
+	// it is public again.
+	case from == StatusArchived:
+		return event.TypePublished, to == StatusPublished
+
+	// A published card being edited stays public until the edit is
+	// published in turn.
+	case from == StatusPublished && to == StatusPending:
+		return "", false
+
+	case from == StatusPublished:
+		return event.TypeRevoked, true
+
+	case to == StatusApproved:
+		return event.TypeApproved, true
+
+	case to == StatusRejected:
+		return event.TypeRejected, true
+
+	case to == StatusPublished:
+		return event.TypePublished, true
+	}
+
+	return "", false
+}
+
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+type QRReq struct {
+	ID     string `json:"id" param:"id"`         // Public ID of the card.
+	Format string `json:"format" query:"format"` // png or svg. Default: png.
+}
+
+func (r *QRReq) Validate() error {
+	r.Format = strings.ToLower(strings.TrimSpace(r.Format))
+	if r.Format == "" {
+		r.Format = QRFormatPNG
+	}
+
+	violations, err := validate.Violations(&contactqrPb.GetQRRequest{
+		Id:     r.ID,
+		Format: r.Format,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidQR).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+func (s *Service) GetPublicQRBusinessCard(ctx context.Context, in *QRReq) (*storage.Object, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicQRBusinessCard"),
+		zap.Any("req", in),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := s.getPublishedCard(ctx, in.ID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	if len(card.vcf) == 0 {
+		if err := card.renderVCF(); err != nil {
+			zlog.Error("failed to gen vcf", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	obj, err := s.assets.Get(ctx, qrKey(card.vcfHash, in.Format))
+	if err == nil {
+		return obj, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		zlog.Warn("failed to get stored qr", zap.Error(err))
+	}
+
+	obj, err = s.storeQR(ctx, card, in.Format)
+	if err != nil {
+		zlog.Error("failed to store qr", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// storeQR renders the QR code of a card's vCard and saves it to the assets
+// storage under a key derived from the vCard hash.
+func (s *Service) storeQR(ctx context.Context, card *Card, format string) (*storage.Object, error) {
+	var (
+		data        []byte
+		err         error
+		contentType string
+	)
+	switch format {
+	case QRFormatSVG:
+		data, err = genQRSVG(card.vcf, qrcode.Medium, 0)
+		contentType = "image/svg+xml"
+
+	default:
+		data, err = genQRPNG(card.vcf, qrcode.Medium, qrSize)
+		contentType = "image/png"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := qrKey(card.vcfHash, format)
+	if err := s.assets.Put(ctx, key, data); err != nil {
+		return nil, err
+	}
+
+	return &storage.Object{
+		Key:         key,
+		ContentType: contentType,
+		Data:        data,
+		ModTime:     time.Now(),
+	}, nil
+}
+
+// getPublishedCard returns the published card with the given public ID, reading
+// through the cache. A card edited since it was published is returned as
+// last published. Cards never published or archived are reported as not
+// found.
+func (s *Service) getPublishedCard(ctx context.Context, publicID string) (*Card, error) {
+	if card, ok := s.published.get(publicID); ok {
+		return card, nil
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		publicID: publicID,
+	})
+	if err != nil && s.health.ReadOnly() {
+		// The card as last seen beats no card while the database is down.
+		if card, ok := s.published.stale(publicID); ok {
+			return card, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := showPublished(ctx, s.db, card); err != nil {
+		return nil, err
+	}
+
+	card.vcf, card.vcfHash, err = getCardVCF(ctx, s.db, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	card.vcfPhoto, err = getCardPhotoVCF(ctx, s.db, card.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.published.set(card)
+	return card, nil
+}
+
 type Card struct {
-	EmployeeID     int64     `json:"employeeId"`
-	DepartmentID   int64     `json:"departmentId"`
-	PositionID     int64     `json:"positionId"`
-	CompanyID      int64     `json:"companyId"`
-	ID             string    `json:"id"`
-	EmployeeCode   string    `json:"employeeCode"`
-	DisplayName    string    `json:"displayName"`
-	Email          string    `json:"emailAddress"`
-	PhoneNumber    string    `json:"phoneNumber"`
-	MobileNumber   string    `json:"mobileNumber"`
-	PositionName   string    `json:"positionName"`
-	DepartmentName string    `json:"departmentName"`
-	CompanyName    string    `json:"companyName"`
-	Remark         string    `json:"remark"`
-	Status         status    `json:"status"` // PENDING, APPROVED, REJECTED, PUBLISHED. Default: PENDING.
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
+	EmployeeID     int64  `json:"employeeId"`
+	DepartmentID   int64  `json:"departmentId"`
+	PositionID     int64  `json:"positionId"`
+	CompanyID      int64  `json:"companyId"`
+	ID             string `json:"id"`
+	PublicID       string `json:"publicId"` // Set once the card is published.
+	EmployeeCode   string `json:"employeeCode"`
+	DisplayName    string `json:"displayName"` // The name in the first of NameLanguages.
+	DisplayNameEn  string `json:"displayNameEn"`
+	DisplayNameLo  string `json:"displayNameLo"`
+	Email          string `json:"emailAddress"`
+	PhoneNumber    string `json:"phoneNumber"`
+	PhoneE164      string `json:"phoneE164"`
+	PhoneNational  string `json:"phoneNational"`
+	MobileNumber   string `json:"mobileNumber"`
+	MobileE164     string `json:"mobileE164"`
+	MobileNational string `json:"mobileNational"`
+	PhoneDisplay   string `json:"phoneDisplay"`       // The number as the company displays it, see phone.Styles.
+	MobileDisplay  string `json:"mobileDisplay"`      // The number as the company displays it, see phone.Styles.
+	EmailFlagged   bool   `json:"emailFlagged"`       // The email is not a corporate one.
+	GuestID        int64  `json:"guestId,omitempty"`  // Set on cards issued to guests instead of employees.
+	PhotoURL       string `json:"photoUrl,omitempty"` // Path of the uploaded photo, if any.
+
+	// Template is the design of the card's company, see shapeCard.
+	Template *template.Design `json:"template,omitempty"`
+
+	PhoneticGivenName  string `json:"phoneticGivenName"`
+	PhoneticFamilyName string `json:"phoneticFamilyName"`
+
+	// NameLanguages are the languages the card shows the name in, see
+	// setNameLanguages.
+	NameLanguages []i18n.Lang `json:"nameLanguages"`
+
+	SocialLinks SocialLinks `json:"socialLinks"`
+
+	// Address is the office address of the card's company, nil if it has
+	// none.
+	Address *address.Address `json:"address,omitempty"`
+
+	PositionName   string     `json:"positionName"`
+	DepartmentName string     `json:"departmentName"`
+	CompanyName    string     `json:"companyName"`
+	Remark         string     `json:"remark"`
+	Status         status     `json:"status"` // PENDING, APPROVED, REJECTED, PUBLISHED, ARCHIVED. Default: PENDING.
+	ArchivedAt     *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+
+	// EmployeeChangedAt is when an HR import changed the owner's name,
+	// email or organisation after the card was published, nil if it has
+	// not.
+	EmployeeChangedAt *time.Time `json:"employeeChangedAt,omitempty"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
 
 	createdBy string
 	updatedBy string
+
+	// archivedFrom is the status an archived card is restored to.
+	archivedFrom status
+
+	// vcf is the vCard rendered when the card was published.
+	vcf     []byte
+	vcfHash string
+
+	// vcfPhoto is vcf with the owner's directory photo embedded, served
+	// for download. QR codes, posters and NFC tags encode vcf, which
+	// stays small enough to fit. photoHash identifies the embedded photo.
+	vcfPhoto  []byte
+	photoHash string
+
+	// photoKey is the asset key of the photo uploaded for the card.
+	photoKey string
+
+	// idempotencyKey is the Idempotency-Key the card is being created
+	// with, see saveCard.
+	idempotencyKey string
+
+	// viewer is who the card is being shown to and loc the timezone its
+	// timestamps are displayed in, see shapeCard.
+	viewer visibility.Role
+	loc    *time.Location
+}
+
+// setPhones stores the canonical and display forms of the card's numbers.
+// A nil mobile clears it.
+func (c *Card) setPhones(p, m *phone.Number) {
+	if p != nil {
+		c.PhoneNumber = p.International
+		c.PhoneE164 = p.E164
+		c.PhoneNational = p.National
+	}
+
+	c.MobileNumber, c.MobileE164, c.MobileNational = "", "", ""
+	if m != nil {
+		c.MobileNumber = m.International
+		c.MobileE164 = m.E164
+		c.MobileNational = m.National
+	}
+}
+
+// nameLanguages are the languages a card may show the name in.
+var nameLanguages = []i18n.Lang{i18n.English, i18n.Lao}
+
+// nameIn returns the card's name in lang, empty if it has none.
+func (c *Card) nameIn(lang i18n.Lang) string {
+	switch lang {
+	case i18n.English:
+		return c.DisplayNameEn
+	case i18n.Lao:
+		return c.DisplayNameLo
+	}
+	return ""
+}
+
+// setNameLanguages shows the name in langs, or in the card's current
+// languages if langs is empty. Languages the card has no name in are left
+// out; a card left with none shows the English name. DisplayName is the name
+// in the first language.
+func (c *Card) setNameLanguages(langs []i18n.Lang) {
+	if len(langs) == 0 {
+		langs = c.NameLanguages
+	}
+
+	shown := make([]i18n.Lang, 0, len(langs))
+	for _, l := range langs {
+		if c.nameIn(l) != "" && !slices.Contains(shown, l) {
+			shown = append(shown, l)
+		}
+	}
+	if len(shown) == 0 {
+		shown = append(shown, i18n.English)
+	}
+
+	c.NameLanguages = shown
+	c.DisplayName = c.nameIn(shown[0])
+}
+
+// fillNames derives the localized names of cards saved before they were
+// stored, whose name is the English one.
+func (c *Card) fillNames(languages string) {
+	if c.DisplayNameEn == "" {
+		c.DisplayNameEn = c.DisplayName
+	}
+
+	c.NameLanguages = make([]i18n.Lang, 0)
+	for _, l := range strings.Split(languages, ",") {
+		if l != "" {
+			c.NameLanguages = append(c.NameLanguages, i18n.Lang(l))
+		}
+	}
+	if len(c.NameLanguages) == 0 {
+		c.NameLanguages = append(c.NameLanguages, i18n.English)
+	}
+}
+
+// fillPhoneFormats derives the formatted numbers of cards saved before both
+// formats were stored.
+func (c *Card) fillPhoneFormats() {
+	if c.PhoneE164 == "" && c.PhoneNumber != "" {
+		if n, err := phone.Parse(c.PhoneNumber, phone.DefaultRegion); err == nil {
+			c.PhoneE164, c.PhoneNational = n.E164, n.National
+		}
+	}
+	if c.MobileE164 == "" && c.MobileNumber != "" {
+		if n, err := phone.Parse(c.MobileNumber, phone.DefaultRegion); err == nil {
+			c.MobileE164, c.MobileNational = n.E164, n.National
+		}
+	}
+}
+
+// renderVCF generates and stores the vCard served for a published card.
+func (c *Card) renderVCF() error {
+	byt, err := genVCF(c, nil)
+	if err != nil {
+		return err
+	}
+
+	c.vcf = byt
+	c.vcfHash = vcfHash(byt)
+	return nil
 }
 
 func (c *Card) Approved(by string) error {
@@ -652,10 +1576,10 @@ func (c *Card) Approved(by string) error {
 		return nil
 
 	case StatusRejected:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in REJECTED status. Only PENDING status can be APPROVED.")
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotApprovable, "status", c.Status.String())
 
-	case StatusPublished:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in PUBLISHED status. Only PENDING status can be APPROVED.")
+	case StatusPublished, StatusArchived:
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotApprovable, "status", c.Status.String())
 
 	}
 
@@ -672,10 +1596,10 @@ func (c *Card) Rejected(by, remark string) error {
 		return nil
 
 	case StatusApproved:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in APPROVED status. Only PENDING status can be REJECTED.")
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotRejectable, "status", c.Status.String())
 
-	case StatusPublished:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in PUBLISHED status. Only PENDING status can be REJECTED.")
+	case StatusPublished, StatusArchived:
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotRejectable, "status", c.Status.String())
 	}
 
 	c.Status = StatusRejected
@@ -692,10 +1616,10 @@ func (c *Card) Published(by string) error {
 		return nil
 
 	case StatusPending:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in PENDING status. Only APPROVED status can be PUBLISHED.")
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotPublishable, "status", c.Status.String())
 
-	case StatusRejected:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in REJECTED status. Only APPROVED status can be PUBLISHED.")
+	case StatusRejected, StatusArchived:
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotPublishable, "status", c.Status.String())
 
 	}
 
@@ -706,18 +1630,19 @@ func (c *Card) Published(by string) error {
 	return nil
 }
 
+// UpdateFromEmployee makes c a draft of in, pending approval. A published
+// card stays public as last published until the draft is published.
 func (c *Card) UpdateFromEmployee(in *employee.Employee) error {
 	switch c.Status {
-	case StatusPublished:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in PUBLISHED status. Only PENDING and REJECTED status can be updated.")
-
-	case StatusApproved:
-		return rpcStatus.Error(codes.FailedPrecondition, "Card is in APPROVED status. Only PENDING and REJECTED status can be updated.")
+	case StatusApproved, StatusArchived:
+		return i18n.Error(codes.FailedPrecondition, i18n.CardNotUpdatable, "status", c.Status.String())
 
 	}
 
 	c.EmployeeCode = in.Code
-	c.DisplayName = in.DisplayName
+	c.DisplayNameEn = in.DisplayNameEn
+	c.DisplayNameLo = in.DisplayNameLo
+	c.setNameLanguages(nil)
 	c.PhoneNumber = in.Phone
 	c.MobileNumber = in.Mobile
 	c.Email = in.Email
@@ -727,6 +1652,7 @@ func (c *Card) UpdateFromEmployee(in *employee.Employee) error {
 	c.DepartmentName = in.DepartmentName
 	c.CompanyID = in.CompanyID
 	c.CompanyName = in.CompanyName
+	c.EmployeeChangedAt = nil
 	c.Status = StatusPending
 	c.updatedBy = in.Code
 	c.UpdatedAt = time.Now()
@@ -734,6 +1660,14 @@ func (c *Card) UpdateFromEmployee(in *employee.Employee) error {
 	return nil
 }
 
+// newPublicID returns a random identifier used in public card URLs. Unlike
+// the card ID it reveals nothing about the card and cannot be enumerated.
+func newPublicID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
 func newCardFromEmployee(e *employee.Employee) *Card {
 	c := new(Card)
 	now := time.Now()
@@ -742,7 +1676,9 @@ func newCardFromEmployee(e *employee.Employee) *Card {
 	c.ID = strings.ToUpper(strings.Split(id, "-")[4])
 	c.EmployeeID = e.ID
 	c.EmployeeCode = e.Code
-	c.DisplayName = e.DisplayName
+	c.DisplayNameEn = e.DisplayNameEn
+	c.DisplayNameLo = e.DisplayNameLo
+	c.setNameLanguages(nil)
 	c.PositionID = e.PositionID
 	c.PositionName = e.PositionName
 	c.DepartmentID = e.DepartmentID
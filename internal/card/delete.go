@@ -0,0 +1,94 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/audit"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// deletedStatus is the status history entries record a card deleted in.
+// Deleted cards keep their last status; they are only left out of every
+// query.
+const deletedStatus = "DELETED"
+
+// DeleteMyBusinessCard deletes one of the caller's cards. The card is kept
+// with its history, for audits, but is no longer listed or served, and the
+// caller's syncs get a tombstone for it. Cards to keep but take out of use
+// are archived by HR instead.
+func (s *Service) DeleteMyBusinessCard(ctx context.Context, id string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "DeleteMyBusinessCard"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		ID:         id,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	err = utils.WithTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := deleteCard(ctx, tx, card, claims.Code, now); err != nil {
+			return err
+		}
+
+		err := s.audit.Record(ctx, tx, &audit.Entry{
+			CardID:     card.ID,
+			FromStatus: card.Status.String(),
+			ToStatus:   deletedStatus,
+			Remark:     card.Remark,
+			Actor:      claims.Code,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Downstream systems only hear of cards that were public.
+		if card.Status != StatusPublished {
+			return nil
+		}
+		return s.outbox.Enqueue(ctx, tx, &event.Event{
+			ID:         uuid.NewString(),
+			Type:       event.TypeRevoked,
+			CardID:     card.ID,
+			PublicID:   card.PublicID,
+			EmployeeID: card.EmployeeID,
+			Status:     card.Status.String(),
+			Actor:      claims.Code,
+			OccurredAt: now,
+			RequestID:  reqid.FromContext(ctx),
+		})
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		// Deleted by a concurrent request.
+		return i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to delete card", zap.Error(err))
+		return err
+	}
+
+	s.published.delete(card.PublicID)
+	return nil
+}
@@ -0,0 +1,120 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// fields returns the fields of the version in the order a diff lists
+// them, named as in the card's JSON.
+func (v *cardVersion) fields() [][2]string {
+	langs := make([]string, 0, len(v.NameLanguages))
+	for _, l := range v.NameLanguages {
+		langs = append(langs, string(l))
+	}
+
+	return [][2]string{
+		{"displayName", v.DisplayName},
+		{"displayNameEn", v.DisplayNameEn},
+		{"displayNameLo", v.DisplayNameLo},
+		{"nameLanguages", strings.Join(langs, ",")},
+		{"phoneticGivenName", v.PhoneticGivenName},
+		{"phoneticFamilyName", v.PhoneticFamilyName},
+		{"emailAddress", v.Email},
+		{"phoneNumber", v.PhoneNumber},
+		{"mobileNumber", v.MobileNumber},
+		{"positionName", v.PositionName},
+		{"departmentName", v.DepartmentName},
+		{"companyName", v.CompanyName},
+		{"socialLinks.whatsapp", v.SocialLinks.WhatsApp},
+		{"socialLinks.line", v.SocialLinks.Line},
+		{"socialLinks.wechat", v.SocialLinks.WeChat},
+		{"socialLinks.linkedin", v.SocialLinks.LinkedIn},
+		{"socialLinks.website", v.SocialLinks.Website},
+	}
+}
+
+// FieldChange is a field that differs from the published version.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"oldValue"`
+	New   string `json:"newValue"`
+}
+
+// CardDiff is what a card changes from the version last published.
+type CardDiff struct {
+	CardID string `json:"cardId"`
+
+	// BaseCardID is the card the published version is of: the card itself
+	// or, for a card never published, the owner's card published last.
+	// Empty when there is none, the changes then listing every field set.
+	BaseCardID      string     `json:"baseCardId,omitempty"`
+	BasePublishedAt *time.Time `json:"basePublishedAt,omitempty"`
+
+	Changes []*FieldChange `json:"changes"`
+}
+
+func diffVersions(base, v *cardVersion) []*FieldChange {
+	changes := make([]*FieldChange, 0)
+	old := base.fields()
+	for i, f := range v.fields() {
+		if old[i][1] != f[1] {
+			changes = append(changes, &FieldChange{
+				Field: f[0],
+				Old:   old[i][1],
+				New:   f[1],
+			})
+		}
+	}
+	return changes
+}
+
+// GetMyApprovalBusinessCardDiff compares a card the caller approves with
+// the version last published, so that they can see what they are asked to
+// approve again.
+func (s *Service) GetMyApprovalBusinessCardDiff(ctx context.Context, id string) (*CardDiff, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetMyApprovalBusinessCardDiff"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		ID:        id,
+		managerID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	diff := &CardDiff{CardID: card.ID}
+	base := new(cardVersion)
+	v, err := getLastCardVersion(ctx, s.db, card.ID, card.EmployeeID)
+	switch {
+	case errors.Is(err, errVersionNotFound):
+	case err != nil:
+		zlog.Error("failed to get last card version", zap.Error(err))
+		return nil, err
+	default:
+		base = v.version
+		diff.BaseCardID = v.cardID
+		diff.BasePublishedAt = &v.createdAt
+	}
+	diff.Changes = diffVersions(base, versionOf(card))
+
+	return diff, nil
+}
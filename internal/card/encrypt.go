@@ -0,0 +1,81 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/10664kls/contactqr/internal/pii"
+	"github.com/10664kls/contactqr/internal/utils"
+)
+
+// encryptBatch is the number of rows EncryptStoredVCFs encrypts per query.
+const encryptBatch = 100
+
+// EncryptStoredVCFs encrypts the vCards stored in plain text by earlier
+// versions, on the cards and on their published versions. It needs the
+// global pii Cipher and returns how many rows it encrypted.
+func EncryptStoredVCFs(ctx context.Context, db *sql.DB) (int, error) {
+	// 0x656E633A76313A is "enc:v1:", the prefix of encrypted values.
+	cards := fmt.Sprintf(`
+SELECT TOP %d id, vcf, vcf_photo
+FROM dbo.business_card
+WHERE (DATALENGTH(vcf) > 0 AND SUBSTRING(vcf, 1, 7) <> 0x656E633A76313A)
+   OR (DATALENGTH(vcf_photo) > 0 AND SUBSTRING(vcf_photo, 1, 7) <> 0x656E633A76313A)`, encryptBatch)
+
+	versions := fmt.Sprintf(`
+SELECT TOP %d id, vcf, NULL
+FROM dbo.business_card_version
+WHERE DATALENGTH(vcf) > 0 AND SUBSTRING(vcf, 1, 7) <> 0x656E633A76313A`, encryptBatch)
+
+	n, err := encryptVCFs(ctx, db, cards, "UPDATE dbo.business_card SET vcf = @p2, vcf_photo = @p3 WHERE id = @p1")
+	if err != nil {
+		return n, err
+	}
+
+	m, err := encryptVCFs(ctx, db, versions, "UPDATE dbo.business_card_version SET vcf = @p2 WHERE id = @p1")
+	return n + m, err
+}
+
+// encryptVCFs writes back, encrypted, the rows the query selects until it
+// selects none. The query selects the key, the vCard and the photo vCard
+// of each row.
+func encryptVCFs(ctx context.Context, db *sql.DB, query, update string) (int, error) {
+	type row struct {
+		id       any
+		vcf      pii.Bytes
+		vcfPhoto pii.Bytes
+	}
+
+	var n int
+	for {
+		rows, err := utils.QueryContext(ctx, db, query)
+		if err != nil {
+			return n, fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		var batch []row
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.id, &r.vcf, &r.vcfPhoto); err != nil {
+				rows.Close()
+				return n, fmt.Errorf("failed to scan row: %w", err)
+			}
+			batch = append(batch, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return n, fmt.Errorf("failed to iterate rows: %w", err)
+		}
+		if len(batch) == 0 {
+			return n, nil
+		}
+
+		for _, r := range batch {
+			if _, err := utils.ExecContext(ctx, db, update, r.id, r.vcf, r.vcfPhoto); err != nil {
+				return n, fmt.Errorf("failed to execute encrypt vcf: %w", err)
+			}
+			n++
+		}
+	}
+}
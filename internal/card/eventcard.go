@@ -0,0 +1,412 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/utils"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var ErrEventCardNotFound = errors.New("event card not found")
+
+// EventCard is a temporary version of a published card for an event, with
+// the holder's role there, its own QR code and a validity window after
+// which it stops working.
+type EventCard struct {
+	ID         string    `json:"id"`
+	PublicID   string    `json:"publicId"`
+	CardID     string    `json:"cardId"`
+	Label      string    `json:"label"`
+	ValidFrom  time.Time `json:"validFrom"`
+	ValidUntil time.Time `json:"validUntil"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	createdBy string
+}
+
+// Event card states, derived from the validity window.
+const (
+	EventCardScheduled = "SCHEDULED"
+	EventCardActive    = "ACTIVE"
+	EventCardExpired   = "EXPIRED"
+)
+
+// StatusAt returns the state of the event card at t.
+func (e *EventCard) StatusAt(t time.Time) string {
+	switch {
+	case t.Before(e.ValidFrom):
+		return EventCardScheduled
+	case t.Before(e.ValidUntil):
+		return EventCardActive
+	}
+	return EventCardExpired
+}
+
+func (e *EventCard) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.JSONView())
+}
+
+// JSONView returns the event card as MarshalJSON encodes it, with its
+// current state.
+func (e *EventCard) JSONView() any {
+	type alias EventCard
+	return &struct {
+		*alias
+		Status string `json:"status"`
+	}{
+		alias:  (*alias)(e),
+		Status: e.StatusAt(time.Now()),
+	}
+}
+
+// maxEventWindow is the longest an event card stays valid.
+const maxEventWindow = 90 * 24 * time.Hour
+
+type EventCardReq struct {
+	// CardID is the base card on the owner's route.
+	CardID     string    `json:"-" param:"id"`
+	Label      string    `json:"label"`
+	ValidFrom  time.Time `json:"validFrom"`
+	ValidUntil time.Time `json:"validUntil"`
+
+	// EmployeeIDs are the employees HR issues the event card to.
+	EmployeeIDs []int64 `json:"employeeIds"`
+}
+
+func (r *EventCardReq) Validate(bulk bool) error {
+	r.Label = strings.TrimSpace(r.Label)
+	if r.ValidFrom.IsZero() {
+		r.ValidFrom = time.Now()
+	}
+
+	var validUntil *timestamppb.Timestamp
+	if !r.ValidUntil.IsZero() {
+		validUntil = timestamppb.New(r.ValidUntil)
+	}
+
+	var msg proto.Message = &contactqrPb.EventCardRequest{
+		Label:      r.Label,
+		ValidFrom:  timestamppb.New(r.ValidFrom),
+		ValidUntil: validUntil,
+	}
+	if bulk {
+		msg = &contactqrPb.IssueEventCardsRequest{
+			Label:       r.Label,
+			ValidFrom:   timestamppb.New(r.ValidFrom),
+			ValidUntil:  validUntil,
+			EmployeeIds: r.EmployeeIDs,
+		}
+	}
+
+	violations, err := validate.Violations(msg)
+	if err != nil {
+		return err
+	}
+
+	if !r.ValidUntil.IsZero() && (!r.ValidUntil.After(r.ValidFrom) || r.ValidUntil.Sub(r.ValidFrom) > maxEventWindow) {
+		violations = append(violations, i18n.Violation("validUntil", i18n.InvalidWindow))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidEventCard).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+func newEventCard(card *Card, in *EventCardReq, by string) *EventCard {
+	return &EventCard{
+		ID:         strings.ToUpper(strings.Split(uuid.NewString(), "-")[4]),
+		PublicID:   newPublicID(),
+		CardID:     card.ID,
+		Label:      in.Label,
+		ValidFrom:  in.ValidFrom,
+		ValidUntil: in.ValidUntil,
+		CreatedAt:  time.Now(),
+		createdBy:  by,
+	}
+}
+
+// CreateMyEventCard issues an event card from one of the caller's
+// published cards.
+func (s *Service) CreateMyEventCard(ctx context.Context, in *EventCardReq) (*EventCard, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateMyEventCard"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(false); err != nil {
+		return nil, err
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		ID:         in.CardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	if card.Status != StatusPublished {
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.CardNotPublished, "status", card.Status.String())
+	}
+
+	ec := newEventCard(card, in, claims.Code)
+	err = utils.WithTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		return createEventCard(ctx, tx, ec)
+	})
+	if err != nil {
+		zlog.Error("failed to create event card", zap.Error(err))
+		return nil, err
+	}
+
+	return ec, nil
+}
+
+type ListEventCardsResult struct {
+	EventCards []*EventCard `json:"eventCards"`
+}
+
+// ListMyEventCards lists the event cards issued from one of the caller's
+// cards, newest first.
+func (s *Service) ListMyEventCards(ctx context.Context, cardID string) (*ListEventCardsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListMyEventCards"),
+		zap.String("card_id", cardID),
+		zap.String("username", claims.Code),
+	)
+
+	_, err := getCard(ctx, s.db, &CardQuery{
+		ID:         cardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	eventCards, err := listEventCards(ctx, s.db, cardID)
+	if err != nil {
+		zlog.Error("failed to list event cards", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListEventCardsResult{EventCards: eventCards}, nil
+}
+
+// SkippedEmployee is an employee a bulk issue left out, and why.
+type SkippedEmployee struct {
+	EmployeeID int64    `json:"employeeId"`
+	Reason     i18n.Key `json:"reason"`
+}
+
+type IssueEventCardsResult struct {
+	EventCards []*EventCard       `json:"eventCards"`
+	Skipped    []*SkippedEmployee `json:"skipped"`
+}
+
+// IssueEventCards issues an event card to every listed employee with a
+// published card, e.g. all speakers of a conference. Employees without one
+// are reported as skipped. It is for HR only.
+func (s *Service) IssueEventCards(ctx context.Context, in *EventCardReq) (*IssueEventCardsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "IssueEventCards"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageCards) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
+	}
+
+	if err := in.Validate(true); err != nil {
+		return nil, err
+	}
+
+	res := &IssueEventCardsResult{
+		EventCards: make([]*EventCard, 0, len(in.EmployeeIDs)),
+		Skipped:    make([]*SkippedEmployee, 0),
+	}
+	seen := make(map[int64]bool, len(in.EmployeeIDs))
+	for _, id := range in.EmployeeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		cards, err := listCards(ctx, s.db, &CardQuery{
+			EmployeeID: id,
+			Status:     StatusPublished.String(),
+			PageSize:   1,
+		})
+		if err != nil {
+			zlog.Error("failed to list cards", zap.Error(err))
+			return nil, err
+		}
+		if len(cards) == 0 {
+			res.Skipped = append(res.Skipped, &SkippedEmployee{EmployeeID: id, Reason: i18n.CardNotPublished})
+			continue
+		}
+		res.EventCards = append(res.EventCards, newEventCard(cards[0], in, claims.Code))
+	}
+
+	err := utils.WithTx(ctx, s.db, func(ctx context.Context, tx *sql.Tx) error {
+		for _, ec := range res.EventCards {
+			if err := createEventCard(ctx, tx, ec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zlog.Error("failed to create event cards", zap.Error(err))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// getActiveEventCard returns the event card with the given public ID and
+// its base card, if the event card is within its window and the base card
+// still published.
+func (s *Service) getActiveEventCard(ctx context.Context, publicID string) (*EventCard, *Card, error) {
+	ec, err := getEventCardByPublicID(ctx, s.db, publicID)
+	if errors.Is(err, ErrEventCardNotFound) {
+		return nil, nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if ec.StatusAt(time.Now()) != EventCardActive {
+		return nil, nil, ErrCardNotFound
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{ID: ec.CardID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if card.Status != StatusPublished {
+		return nil, nil, ErrCardNotFound
+	}
+
+	card.vcf, err = genVCF(card, &vcfOptions{role: ec.Label})
+	if err != nil {
+		return nil, nil, err
+	}
+	card.vcfHash = vcfHash(card.vcf)
+
+	return ec, card, nil
+}
+
+// GetPublicVCFEventCard serves the vCard behind an event card's QR code
+// while the event card is valid.
+func (s *Service) GetPublicVCFEventCard(ctx context.Context, in *VCFReq) (*VCF, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicVCFEventCard"),
+		zap.Any("req", in),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	ec, card, err := s.getActiveEventCard(ctx, in.ID)
+	if errors.Is(err, ErrCardNotFound) {
+		zlog.Info("public event card access denied", zap.String("remote_ip", in.remoteIP))
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get event card", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Info("public event card accessed",
+		zap.String("event_card_id", ec.ID),
+		zap.String("remote_ip", in.remoteIP),
+		zap.String("user_agent", in.userAgent),
+	)
+	s.recordScan(ctx, zlog, newScan(card.ID, ec.ID, in.remoteIP, in.userAgent))
+	s.countDownload(ctx, zlog, card.ID, in.userAgent)
+
+	if in.Legacy || in.Version == VCardV4 {
+		opts := in.vcfOptions(card)
+		opts.role = ec.Label
+		card.vcf, err = genVCF(card, opts)
+		if err != nil {
+			zlog.Error("failed to gen vcf", zap.Error(err))
+			return nil, err
+		}
+		card.vcfHash = vcfHash(card.vcf)
+	}
+
+	return encodeVCF(ctx, card, new(vcfOptions))
+}
+
+// GetPublicQREventCard serves the QR code of an event card while it is
+// valid.
+func (s *Service) GetPublicQREventCard(ctx context.Context, in *QRReq) (*storage.Object, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicQREventCard"),
+		zap.Any("req", in),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, card, err := s.getActiveEventCard(ctx, in.ID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get event card", zap.Error(err))
+		return nil, err
+	}
+
+	obj, err := s.assets.Get(ctx, qrKey(card.vcfHash, in.Format))
+	if err == nil {
+		return obj, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotFound) {
+		zlog.Warn("failed to get stored qr", zap.Error(err))
+	}
+
+	obj, err = s.storeQR(ctx, card, in.Format)
+	if err != nil {
+		zlog.Error("failed to store qr", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
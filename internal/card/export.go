@@ -0,0 +1,279 @@
+package card
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/export"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/10664kls/contactqr/internal/visibility"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// ExportCards writes a snapshot of every card, archived ones included, as
+// newline-delimited JSON for BI exports. Cards are exported unmasked, as HR
+// sees them, with times in UTC; from and to are ignored.
+func (s *Service) ExportCards(ctx context.Context, w io.Writer, _, _ time.Time) error {
+	enc := json.NewEncoder(w)
+	return iterCards(ctx, s.db, &CardQuery{IncludeArchived: true}, 0, func(c *Card) error {
+		shaped := *c
+		shaped.viewer = visibility.RoleHR
+		shaped.loc = time.UTC
+		style := s.styles.For(c.CompanyID)
+		shaped.PhoneDisplay = displayNumber(c.PhoneE164, c.PhoneNumber, style)
+		shaped.MobileDisplay = displayNumber(c.MobileE164, c.MobileNumber, style)
+		return enc.Encode(&shaped)
+	})
+}
+
+// ExportedScan is one scan as written by ExportScans.
+type ExportedScan struct {
+	CardID      string    `json:"cardId"`
+	EventCardID string    `json:"eventCardId,omitempty"`
+	Visitor     string    `json:"visitor"`
+	Device      string    `json:"device"`
+	OS          string    `json:"os"`
+	ScannedAt   time.Time `json:"scannedAt"`
+}
+
+// ExportScans writes the scans of [from, to) as newline-delimited JSON for
+// BI exports, oldest first. A zero from exports every scan before to.
+func (s *Service) ExportScans(ctx context.Context, w io.Writer, from, to time.Time) error {
+	enc := json.NewEncoder(w)
+	return iterScans(ctx, s.db, from, to, func(sc *ExportedScan) error {
+		sc.ScannedAt = sc.ScannedAt.UTC()
+		return enc.Encode(sc)
+	})
+}
+
+// CardColumns are the columns of a card export, named as the JSON fields.
+// Phone numbers are written as the company displays them and times in the
+// caller's timezone.
+var CardColumns = []export.Column[*Card]{
+	{Name: "id", Value: func(c *Card) string { return c.ID }},
+	{Name: "publicId", Value: func(c *Card) string { return c.PublicID }},
+	{Name: "employeeCode", Value: func(c *Card) string { return c.EmployeeCode }},
+	{Name: "displayName", Value: func(c *Card) string { return c.DisplayName }},
+	{Name: "positionName", Value: func(c *Card) string { return c.PositionName }},
+	{Name: "departmentName", Value: func(c *Card) string { return c.DepartmentName }},
+	{Name: "companyName", Value: func(c *Card) string { return c.CompanyName }},
+	{Name: "emailAddress", Value: func(c *Card) string { return c.Email }},
+	{Name: "phoneNumber", Value: func(c *Card) string { return c.PhoneDisplay }},
+	{Name: "mobileNumber", Value: func(c *Card) string { return c.MobileDisplay }},
+	{Name: "status", Value: func(c *Card) string { return c.Status.String() }},
+	{Name: "createdAt", Value: func(c *Card) string { return tz.Format(c.CreatedAt, c.loc) }},
+	{Name: "updatedAt", Value: func(c *Card) string { return tz.Format(c.UpdatedAt, c.loc) }},
+}
+
+// ExportCardsReq selects the cards of an export with the filters of
+// CardQuery, the file format, csv or xlsx, and the columns, as a comma
+// separated list of CardColumns names. Default: csv with every column.
+type ExportCardsReq struct {
+	CardQuery
+
+	Format  string `json:"format" query:"format"`
+	Columns string `json:"columns" query:"columns"`
+
+	format  export.Format
+	columns []export.Column[*Card]
+}
+
+func (r *ExportCardsReq) Validate() error {
+	r.Format = strings.ToLower(strings.TrimSpace(r.Format))
+	if r.Format == "" {
+		r.Format = string(export.CSV)
+	}
+
+	violations, err := validate.Violations(&contactqrPb.ExportBusinessCardsRequest{
+		Format: r.Format,
+	})
+	if err != nil {
+		return err
+	}
+	r.format, _ = export.ParseFormat(r.Format)
+
+	names := make([]string, 0)
+	for _, name := range strings.Split(r.Columns, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	var unknown string
+	r.columns, unknown = export.SelectColumns(CardColumns, names)
+	if unknown != "" {
+		violations = append(violations, i18n.Violation("columns", i18n.UnknownColumn))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidCardExport).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// ExportBusinessCards writes the cards matching req to w as a CSV or XLSX
+// file of the columns req selects, for HR to work on the directory in a
+// spreadsheet. Nothing is written to w before the first card is read, so
+// the caller can still report errors up to then.
+func (s *Service) ExportBusinessCards(ctx context.Context, req *ExportCardsReq, w io.Writer) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ExportBusinessCards"),
+		zap.Any("req", req),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
+	}
+
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	var table *export.Table[*Card]
+	start := func() (err error) {
+		table, err = export.NewTable(w, req.format, req.columns)
+		return err
+	}
+
+	err := iterCards(ctx, s.db, &req.CardQuery, 0, func(c *Card) error {
+		if table == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return table.Write(s.shapeCard(ctx, c, false))
+	})
+	if err != nil {
+		zlog.Error("failed to export business cards", zap.Error(err))
+		return err
+	}
+
+	if table == nil {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return table.Close()
+}
+
+// vCard bundle formats of a department export.
+const (
+	BundleVCF = "vcf" // One file of the vCards in turn.
+	BundleZip = "zip" // A zip of a file per vCard.
+)
+
+// DepartmentVCFReq selects the department whose published cards are
+// exported and how their vCards are bundled, vcf or zip. Default: vcf.
+type DepartmentVCFReq struct {
+	ID     string `json:"id" param:"id"`
+	Format string `json:"format" query:"format"`
+}
+
+func (r *DepartmentVCFReq) Validate() error {
+	r.Format = strings.ToLower(strings.TrimSpace(r.Format))
+	if r.Format == "" {
+		r.Format = BundleVCF
+	}
+
+	var violations []*edPb.BadRequest_FieldViolation
+	if r.Format != BundleVCF && r.Format != BundleZip {
+		violations = append(violations, i18n.Violation("format", i18n.UnsupportedBundle))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidCardExport).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// ExportDepartmentVCF writes the vCards of every published card in a
+// department to w, as served behind their QR codes, for importing into
+// shared phones and CRM systems. It is for HR only. Nothing is written to
+// w before the first card is read, so the caller can still report errors
+// up to then, a department without published cards among them.
+func (s *Service) ExportDepartmentVCF(ctx context.Context, in *DepartmentVCFReq, w io.Writer) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ExportDepartmentVCF"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	departmentID, err := strconv.ParseInt(in.ID, 10, 64)
+	if err != nil || departmentID <= 0 {
+		return i18n.Error(codes.NotFound, i18n.NoPublishedCards, "departmentId", in.ID)
+	}
+
+	var zw *zip.Writer
+	n := 0
+	err = iterCards(ctx, s.db, &CardQuery{
+		DepartmentID: departmentID,
+		Status:       StatusPublished.String(),
+	}, 0, func(c *Card) error {
+		vcf, _, err := getCardVCF(ctx, s.db, c.ID)
+		if err != nil {
+			return err
+		}
+		if len(vcf) == 0 {
+			if vcf, err = genVCF(c, nil); err != nil {
+				return err
+			}
+		}
+		n++
+
+		if in.Format == BundleVCF {
+			_, err := w.Write(vcf)
+			return err
+		}
+
+		if zw == nil {
+			zw = zip.NewWriter(w)
+		}
+		f, err := zw.Create(c.EmployeeCode + "-" + c.ID + ".vcf")
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(vcf)
+		return err
+	})
+	if err != nil {
+		zlog.Error("failed to export department vcards", zap.Error(err))
+		return err
+	}
+	if n == 0 {
+		return i18n.Error(codes.NotFound, i18n.NoPublishedCards, "departmentId", in.ID)
+	}
+
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
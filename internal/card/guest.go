@@ -0,0 +1,276 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrGuestNotFound = errors.New("guest not found")
+
+// Guest is a person HR issues cards to who is not in the HR system, such
+// as a board member or a long-term consultant. Their cards go through the
+// same approval and publishing as employees', with the guest's sponsor
+// approving in place of a manager.
+type Guest struct {
+	ID             int64     `json:"id"`
+	DisplayName    string    `json:"displayName"`
+	Email          string    `json:"emailAddress"`
+	CompanyID      int64     `json:"companyId"`
+	CompanyName    string    `json:"companyName"`
+	DepartmentName string    `json:"departmentName"`
+	PositionName   string    `json:"positionName"`
+	SponsorID      int64     `json:"sponsorId"` // Employee who approves the guest's cards.
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+
+	createdBy string
+}
+
+type GuestReq struct {
+	DisplayName    string `json:"displayName"`
+	Email          string `json:"emailAddress"`
+	CompanyID      int64  `json:"companyId"`
+	DepartmentName string `json:"departmentName"`
+	PositionName   string `json:"positionName"`
+
+	// SponsorID is the employee who approves the guest's cards. Default:
+	// the caller.
+	SponsorID int64 `json:"sponsorId"`
+}
+
+func (r *GuestReq) Validate() error {
+	r.DisplayName = strings.TrimSpace(r.DisplayName)
+	r.Email = strings.TrimSpace(r.Email)
+	r.DepartmentName = strings.TrimSpace(r.DepartmentName)
+	r.PositionName = strings.TrimSpace(r.PositionName)
+
+	violations, err := validate.Violations(&contactqrPb.GuestRequest{
+		DisplayName:    r.DisplayName,
+		EmailAddress:   r.Email,
+		CompanyId:      r.CompanyID,
+		DepartmentName: r.DepartmentName,
+		PositionName:   r.PositionName,
+		SponsorId:      r.SponsorID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidGuest).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreateGuest registers a guest. It is for HR only.
+func (s *Service) CreateGuest(ctx context.Context, in *GuestReq) (*Guest, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateGuest"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageGuests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.GuestsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	if in.SponsorID <= 0 {
+		in.SponsorID = claims.ID
+	}
+	if _, err := s.employee.GetEmployeeByID(ctx, in.SponsorID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	g := &Guest{
+		DisplayName:    in.DisplayName,
+		Email:          in.Email,
+		CompanyID:      in.CompanyID,
+		DepartmentName: in.DepartmentName,
+		PositionName:   in.PositionName,
+		SponsorID:      in.SponsorID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		createdBy:      claims.Code,
+	}
+	if err := createGuest(ctx, s.db, g); err != nil {
+		zlog.Error("failed to create guest", zap.Error(err))
+		return nil, err
+	}
+
+	return getGuest(ctx, s.db, g.ID)
+}
+
+type GuestQuery struct {
+	DisplayName string `json:"displayName" query:"displayName"`
+	PageToken   string `json:"pageToken" query:"pageToken"`
+	PageSize    uint64 `json:"pageSize" query:"pageSize"`
+}
+
+type ListGuestsResult struct {
+	Guests        []*Guest `json:"guests"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// ListGuests lists guests, newest first. It is for HR only.
+func (s *Service) ListGuests(ctx context.Context, in *GuestQuery) (*ListGuestsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListGuests"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageGuests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.GuestsForbidden)
+	}
+
+	guests, err := listGuests(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to list guests", zap.Error(err))
+		return nil, err
+	}
+
+	var pageToken string
+	if l := len(guests); l > 0 && l == int(pager.Size(in.PageSize)) {
+		last := guests[l-1]
+		pageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   strconv.FormatInt(last.ID, 10),
+			Time: last.CreatedAt,
+		})
+	}
+
+	return &ListGuestsResult{
+		Guests:        guests,
+		NextPageToken: pageToken,
+	}, nil
+}
+
+// GetGuestByID returns a guest. It is for HR only.
+func (s *Service) GetGuestByID(ctx context.Context, id int64) (*Guest, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetGuestByID"),
+		zap.String("username", claims.Code),
+		zap.Int64("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageGuests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.GuestsForbidden)
+	}
+
+	g, err := getGuest(ctx, s.db, id)
+	if errors.Is(err, ErrGuestNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.GuestNotFound, "guestId", strconv.FormatInt(id, 10))
+	}
+	if err != nil {
+		zlog.Error("failed to get guest by id", zap.Error(err))
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// CreateGuestBusinessCard submits a card for a guest, identified by in.ID.
+// The card then waits for the guest's sponsor to approve it like any
+// other. It is for HR only.
+func (s *Service) CreateGuestBusinessCard(ctx context.Context, in *CardReq) (*Card, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateGuestBusinessCard"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageGuests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.GuestsForbidden)
+	}
+
+	guestID, _ := strconv.ParseInt(in.ID, 10, 64)
+	g, err := getGuest(ctx, s.db, guestID)
+	if errors.Is(err, ErrGuestNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.GuestNotFound, "guestId", in.ID)
+	}
+	if err != nil {
+		zlog.Error("failed to get guest by id", zap.Error(err))
+		return nil, err
+	}
+
+	in.region = s.regions.For(g.CompanyID)
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	pending, err := listCards(ctx, s.db, &CardQuery{
+		guestID:  g.ID,
+		Status:   StatusPending.String(),
+		PageSize: 1,
+	})
+	if err != nil {
+		zlog.Error("failed to list pending cards", zap.Error(err))
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, i18n.Error(codes.AlreadyExists, i18n.DuplicateCard, "cardId", pending[0].ID)
+	}
+
+	card := newCardFromGuest(g, claims.Code)
+	card.setPhones(in.phone, in.mobile)
+	card.PhoneticGivenName = in.PhoneticGivenName
+	card.PhoneticFamilyName = in.PhoneticFamilyName
+	if err := s.saveCard(ctx, card, StatusUnspecified); err != nil {
+		zlog.Error("failed to create card", zap.Error(err))
+		return nil, err
+	}
+
+	return s.shapeCard(ctx, card, false), nil
+}
+
+func newCardFromGuest(g *Guest, by string) *Card {
+	c := new(Card)
+	now := time.Now()
+	id := uuid.NewString()
+
+	c.ID = strings.ToUpper(strings.Split(id, "-")[4])
+	c.GuestID = g.ID
+	c.DisplayNameEn = g.DisplayName
+	c.setNameLanguages(nil)
+	c.PositionName = g.PositionName
+	c.DepartmentName = g.DepartmentName
+	c.CompanyID = g.CompanyID
+	c.CompanyName = g.CompanyName
+	c.Email = g.Email
+	c.Status = StatusPending
+	c.createdBy = by
+	c.updatedBy = by
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	return c
+}
@@ -0,0 +1,62 @@
+package card
+
+import (
+	"context"
+	"errors"
+
+	"github.com/10664kls/contactqr/internal/audit"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+type CardHistory struct {
+	CardID string `json:"cardId"`
+
+	// Entries are the card's status transitions, oldest first.
+	Entries []*audit.Entry `json:"entries"`
+}
+
+// GetBusinessCardHistory returns every status transition of a card with who
+// made it, when and their remark. HR may see the history of any card, a
+// manager that of the cards they approve.
+func (s *Service) GetBusinessCardHistory(ctx context.Context, id string) (*CardHistory, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetBusinessCardHistory"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	q := &CardQuery{ID: id}
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		// Without an employee ID the manager filter would match any card.
+		if claims.ID <= 0 {
+			return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+		}
+		q.managerID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	entries, err := s.audit.ListCardEntries(ctx, card.ID)
+	if err != nil {
+		zlog.Error("failed to list history", zap.Error(err))
+		return nil, err
+	}
+
+	return &CardHistory{
+		CardID:  card.ID,
+		Entries: entries,
+	}, nil
+}
@@ -0,0 +1,498 @@
+package card
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/template"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrExperimentNotFound = errors.New("experiment not found")
+
+// Experiment splits the visitors of a card's landing page, or of every card
+// of a company, between alternative layouts to compare how many of them
+// save the contact. An experiment on a card takes precedence over one on
+// its company.
+type Experiment struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	CardID    string     `json:"cardId,omitempty"`
+	CompanyID int64      `json:"companyId,omitempty"`
+	Variants  []*Variant `json:"variants"`
+	CreatedAt time.Time  `json:"createdAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"` // Set once the experiment is stopped.
+
+	createdBy string
+}
+
+// Variant is a layout of an experiment and its share of the visitors,
+// relative to the other variants' weights.
+type Variant struct {
+	Layout string `json:"layout"`
+	Weight int    `json:"weight"`
+}
+
+// assign returns the variant visitor sees. A visitor always gets the same
+// variant of an experiment, and visitors spread over the variants by
+// weight.
+func (e *Experiment) assign(visitor string) *Variant {
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+
+	sum := sha256.Sum256([]byte(e.ID + "\x00" + visitor))
+	n := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, v := range e.Variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+type ExperimentReq struct {
+	Name      string     `json:"name"`
+	CardID    string     `json:"cardId"`
+	CompanyID int64      `json:"companyId"`
+	Variants  []*Variant `json:"variants"`
+}
+
+// Validate checks the request. An experiment runs on either a card or a
+// company.
+func (r *ExperimentReq) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.CardID = strings.TrimSpace(r.CardID)
+
+	variants := make([]*contactqrPb.Variant, 0, len(r.Variants))
+	for _, v := range r.Variants {
+		if v == nil {
+			v = new(Variant)
+		}
+		v.Layout = strings.ToLower(strings.TrimSpace(v.Layout))
+		variants = append(variants, &contactqrPb.Variant{
+			Layout: v.Layout,
+			Weight: int64(v.Weight),
+		})
+	}
+
+	violations, err := validate.Violations(&contactqrPb.ExperimentRequest{
+		Name:      r.Name,
+		CardId:    r.CardID,
+		CompanyId: r.CompanyID,
+		Variants:  variants,
+	})
+	if err != nil {
+		return err
+	}
+
+	if (r.CardID == "") == (r.CompanyID <= 0) {
+		violations = append(violations, i18n.Violation("cardId", i18n.InvalidScope))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidExperiment).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreateExperiment starts an experiment on a card or a company. Only one
+// experiment runs on each at a time. It is for HR only.
+func (s *Service) CreateExperiment(ctx context.Context, in *ExperimentReq) (*Experiment, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateExperiment"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageExperiments) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.ExperimentsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	if in.CardID != "" {
+		_, err := getCard(ctx, s.db, &CardQuery{ID: in.CardID})
+		if errors.Is(err, ErrCardNotFound) {
+			return nil, i18n.Error(codes.NotFound, i18n.CardNotFound)
+		}
+		if err != nil {
+			zlog.Error("failed to get card by id", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	running, err := getRunningExperiment(ctx, s.db, in.CardID, in.CompanyID, true)
+	if err != nil && !errors.Is(err, ErrExperimentNotFound) {
+		zlog.Error("failed to get running experiment", zap.Error(err))
+		return nil, err
+	}
+	if running != nil {
+		return nil, i18n.Error(codes.AlreadyExists, i18n.ExperimentRunning, "experimentId", running.ID)
+	}
+
+	e := &Experiment{
+		ID:        strings.ToUpper(strings.Split(uuid.NewString(), "-")[4]),
+		Name:      in.Name,
+		CardID:    in.CardID,
+		CompanyID: in.CompanyID,
+		Variants:  in.Variants,
+		CreatedAt: time.Now(),
+		createdBy: claims.Code,
+	}
+	if err := createExperiment(ctx, s.db, e); err != nil {
+		zlog.Error("failed to create experiment", zap.Error(err))
+		return nil, err
+	}
+
+	return e, nil
+}
+
+type ListExperimentsResult struct {
+	Experiments []*Experiment `json:"experiments"`
+}
+
+// ListExperiments lists the experiments, newest first. It is for HR only.
+func (s *Service) ListExperiments(ctx context.Context) (*ListExperimentsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListExperiments"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageExperiments) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.ExperimentsForbidden)
+	}
+
+	experiments, err := listExperiments(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list experiments", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListExperimentsResult{Experiments: experiments}, nil
+}
+
+// StopExperiment ends an experiment. Its visitors see the default layout
+// again and its results stay available. It is for HR only.
+func (s *Service) StopExperiment(ctx context.Context, id string) (*Experiment, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "StopExperiment"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageExperiments) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.ExperimentsForbidden)
+	}
+
+	e, err := getExperiment(ctx, s.db, id)
+	if errors.Is(err, ErrExperimentNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.ExperimentNotFound, "experimentId", id)
+	}
+	if err != nil {
+		zlog.Error("failed to get experiment", zap.Error(err))
+		return nil, err
+	}
+	if e.EndedAt != nil {
+		return e, nil
+	}
+
+	now := time.Now()
+	if err := endExperiment(ctx, s.db, id, now); err != nil {
+		zlog.Error("failed to end experiment", zap.Error(err))
+		return nil, err
+	}
+	e.EndedAt = &now
+
+	return e, nil
+}
+
+// VariantResult is how the visitors shown one layout behaved.
+type VariantResult struct {
+	Layout         string  `json:"layout"`
+	Views          int64   `json:"views"`
+	Visitors       int64   `json:"visitors"`
+	Conversions    int64   `json:"conversions"`    // Visitors who saved the contact.
+	ConversionRate float64 `json:"conversionRate"` // Conversions per visitor, 0 to 1.
+}
+
+type ExperimentResults struct {
+	Experiment *Experiment      `json:"experiment"`
+	Variants   []*VariantResult `json:"variants"`
+}
+
+// GetExperimentResults compares the layouts of an experiment. It is for HR
+// only.
+func (s *Service) GetExperimentResults(ctx context.Context, id string) (*ExperimentResults, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetExperimentResults"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageExperiments) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.ExperimentsForbidden)
+	}
+
+	e, err := getExperiment(ctx, s.db, id)
+	if errors.Is(err, ErrExperimentNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.ExperimentNotFound, "experimentId", id)
+	}
+	if err != nil {
+		zlog.Error("failed to get experiment", zap.Error(err))
+		return nil, err
+	}
+
+	counted, err := countLandingEvents(ctx, s.db, id)
+	if err != nil {
+		zlog.Error("failed to count landing events", zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]*VariantResult, 0, len(e.Variants))
+	for _, v := range e.Variants {
+		r, ok := counted[v.Layout]
+		if !ok {
+			r = &VariantResult{Layout: v.Layout}
+		}
+		if r.Visitors > 0 {
+			r.ConversionRate = float64(r.Conversions) / float64(r.Visitors)
+		}
+		results = append(results, r)
+	}
+
+	return &ExperimentResults{
+		Experiment: e,
+		Variants:   results,
+	}, nil
+}
+
+// Kinds of landing page events.
+const (
+	landingView       = "VIEW"
+	landingConversion = "CONVERSION"
+)
+
+// landingEvent is a view of a landing page variant or a visitor saving the
+// contact from it.
+type landingEvent struct {
+	experimentID string
+	layout       string
+	visitor      string
+	kind         string
+	occurredAt   time.Time
+}
+
+type LandingReq struct {
+	// CardID is the public ID of the card.
+	CardID string `json:"-" param:"id"`
+
+	// VisitorID is the landing page's own identifier for the visitor, kept
+	// on the device so they see the same layout on every visit. Default: a
+	// hash of the visitor's address and user agent.
+	VisitorID string `json:"visitorId" query:"visitorId"`
+
+	// ExperimentID is the experiment the visitor saw, for conversions.
+	ExperimentID string `json:"experimentId"`
+
+	remoteIP  string
+	userAgent string
+}
+
+// SetClient records where the request came from.
+func (r *LandingReq) SetClient(remoteIP, userAgent string) {
+	r.remoteIP = remoteIP
+	r.userAgent = userAgent
+}
+
+// maxVisitorID is the length of the visitor column.
+const maxVisitorID = 64
+
+func (r *LandingReq) visitor() string {
+	if v := strings.TrimSpace(r.VisitorID); v != "" && len(v) <= maxVisitorID {
+		return v
+	}
+	return visitorID(r.remoteIP, r.userAgent)
+}
+
+// Landing tells a card's landing page which layout to render.
+type Landing struct {
+	Layout       string `json:"layout"`
+	ExperimentID string `json:"experimentId,omitempty"`
+	VisitorID    string `json:"visitorId"`
+
+	// PhoneNumber is the card's number in its company's display style.
+	PhoneNumber string `json:"phoneNumber"`
+
+	// Roles is the number of published cards the card's owner holds. With
+	// more than one, the page offers the merged vCard.
+	Roles int `json:"roles"`
+
+	// Template is the design of the card's company. Its layout is the
+	// page's unless an experiment assigns the visitor another.
+	Template *template.Design `json:"template"`
+}
+
+// GetLanding returns the layout a visitor of a published card's landing
+// page sees and records the view.
+func (s *Service) GetLanding(ctx context.Context, in *LandingReq) (*Landing, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetLanding"),
+		zap.String("card_id", in.CardID),
+		zap.String("remote_ip", in.remoteIP),
+	)
+
+	card, err := s.getPublishedCard(ctx, in.CardID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	s.countView(ctx, zlog, card.ID, in.userAgent)
+
+	design := s.templates.For(card.CompanyID).Design()
+	landing := &Landing{
+		Layout:      design.Layout,
+		VisitorID:   in.visitor(),
+		PhoneNumber: displayNumber(card.PhoneE164, card.PhoneNumber, s.styles.For(card.CompanyID)),
+		Roles:       1,
+		Template:    design,
+	}
+
+	if roles, err := s.listRoles(ctx, card); err != nil {
+		// The page still renders, offering the card's own vCard only.
+		zlog.Warn("failed to list roles", zap.Error(err))
+	} else {
+		landing.Roles += len(roles)
+	}
+
+	e, err := getRunningExperiment(ctx, s.db, card.ID, card.CompanyID, false)
+	if errors.Is(err, ErrExperimentNotFound) {
+		return landing, nil
+	}
+	if err != nil {
+		// The page still renders with the template's layout.
+		zlog.Warn("failed to get running experiment", zap.Error(err))
+		return landing, nil
+	}
+
+	v := e.assign(landing.VisitorID)
+	landing.Layout = v.Layout
+	landing.ExperimentID = e.ID
+
+	s.recordLanding(ctx, zlog, in, &landingEvent{
+		experimentID: e.ID,
+		layout:       v.Layout,
+		visitor:      landing.VisitorID,
+		kind:         landingView,
+		occurredAt:   time.Now(),
+	})
+
+	return landing, nil
+}
+
+// GetPublicBusinessCard returns a published card as anyone may see it, for
+// the card page served to visitors, and counts the view.
+func (s *Service) GetPublicBusinessCard(ctx context.Context, publicID, userAgent string) (*Card, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicBusinessCard"),
+		zap.String("card_id", publicID),
+	)
+
+	card, err := s.getPublishedCard(ctx, publicID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	s.countView(ctx, zlog, card.ID, userAgent)
+
+	return s.shapeCard(ctx, card, false), nil
+}
+
+// SaveLandingConversion records that a visitor saved the contact from a
+// card's landing page. The variant is the one the visitor is assigned, not
+// one the client claims.
+func (s *Service) SaveLandingConversion(ctx context.Context, in *LandingReq) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SaveLandingConversion"),
+		zap.String("card_id", in.CardID),
+		zap.String("remote_ip", in.remoteIP),
+	)
+
+	card, err := s.getPublishedCard(ctx, in.CardID)
+	if errors.Is(err, ErrCardNotFound) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return err
+	}
+
+	e, err := getRunningExperiment(ctx, s.db, card.ID, card.CompanyID, false)
+	if errors.Is(err, ErrExperimentNotFound) {
+		return nil
+	}
+	if err != nil {
+		zlog.Error("failed to get running experiment", zap.Error(err))
+		return err
+	}
+	// The visitor saw another experiment, since stopped or replaced.
+	if in.ExperimentID != "" && in.ExperimentID != e.ID {
+		return nil
+	}
+
+	visitor := in.visitor()
+	s.recordLanding(ctx, zlog, in, &landingEvent{
+		experimentID: e.ID,
+		layout:       e.assign(visitor).Layout,
+		visitor:      visitor,
+		kind:         landingConversion,
+		occurredAt:   time.Now(),
+	})
+
+	return nil
+}
+
+// recordLanding stores a landing page event. Like scans, events from bots
+// are not counted and a failure is logged without failing the visitor's
+// request.
+func (s *Service) recordLanding(ctx context.Context, zlog *zap.Logger, in *LandingReq, e *landingEvent) {
+	if device, _ := classifyAgent(in.userAgent); device == DeviceBot {
+		return
+	}
+	if err := createLandingEvent(ctx, s.db, e); err != nil {
+		zlog.Warn("failed to record landing event", zap.Error(err))
+	}
+}
@@ -0,0 +1,398 @@
+package card
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/phone"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// Lead is a visitor's contact details left for the owner of a card they
+// scanned.
+type Lead struct {
+	ID        int64     `json:"id"`
+	CardID    string    `json:"cardId"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phoneNumber"`
+	Email     string    `json:"emailAddress"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	remoteIP  string
+	userAgent string
+}
+
+type LeadReq struct {
+	// CardID is the public ID of the card the visitor scanned.
+	CardID  string `json:"-" param:"id"`
+	Name    string `json:"name"`
+	Phone   string `json:"phoneNumber"`
+	Email   string `json:"emailAddress"`
+	Message string `json:"message"`
+
+	// Website is a honeypot: the form hides it from people, so only bots
+	// fill it in.
+	Website string `json:"website"`
+
+	// region is the card's company default for numbers sent without a
+	// country code.
+	region    string
+	remoteIP  string
+	userAgent string
+}
+
+// SetClient records where the request came from.
+func (r *LeadReq) SetClient(remoteIP, userAgent string) {
+	r.remoteIP = remoteIP
+	r.userAgent = userAgent
+}
+
+// maxLeadsPerHour is how many leads one address may leave on a card within
+// an hour.
+const maxLeadsPerHour = 5
+
+// Validate checks the request. A visitor leaves a phone number, an email or
+// both.
+func (r *LeadReq) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Phone = strings.TrimSpace(r.Phone)
+	r.Email = strings.TrimSpace(r.Email)
+	r.Message = strings.TrimSpace(r.Message)
+
+	violations, err := validate.Violations(&contactqrPb.SubmitLeadRequest{
+		Name:         r.Name,
+		PhoneNumber:  r.Phone,
+		EmailAddress: r.Email,
+		Message:      r.Message,
+		Website:      r.Website,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.Phone == "" && r.Email == "" {
+		violations = append(violations, i18n.Violation("phoneNumber", i18n.Required))
+	}
+
+	if r.Phone != "" {
+		n, err := phone.Parse(r.Phone, r.region)
+		if err != nil {
+			violations = append(violations, i18n.Violation("phoneNumber", i18n.InvalidPhone))
+		} else {
+			r.Phone = n.International
+		}
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidLead).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// SubmitLead stores the contact details a visitor left on a published card
+// and notifies the card's owner.
+func (s *Service) SubmitLead(ctx context.Context, in *LeadReq) (*Lead, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SubmitLead"),
+		zap.String("card_id", in.CardID),
+		zap.String("remote_ip", in.remoteIP),
+	)
+
+	card, err := s.getPublishedCard(ctx, in.CardID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	in.region = s.regions.For(card.CompanyID)
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	lead := &Lead{
+		CardID:    card.ID,
+		Name:      in.Name,
+		Phone:     in.Phone,
+		Email:     in.Email,
+		Message:   in.Message,
+		CreatedAt: time.Now(),
+		remoteIP:  in.remoteIP,
+		userAgent: in.userAgent,
+	}
+
+	// Bots get the response a person would, so they do not learn to leave
+	// the honeypot empty.
+	if in.Website != "" {
+		zlog.Info("lead dropped by honeypot")
+		return lead, nil
+	}
+
+	n, err := countLeads(ctx, s.db, card.ID, in.remoteIP, lead.CreatedAt.Add(-time.Hour))
+	if err != nil {
+		zlog.Error("failed to count leads", zap.Error(err))
+		return nil, err
+	}
+	if n >= maxLeadsPerHour {
+		zlog.Info("lead rate limited", zap.Int("count", n))
+		return nil, i18n.Error(codes.ResourceExhausted, i18n.TooManyRequests)
+	}
+
+	if err := createLead(ctx, s.db, lead); err != nil {
+		zlog.Error("failed to create lead", zap.Error(err))
+		return nil, err
+	}
+
+	err = s.jobs.Enqueue(ctx, s.db, leadMailJob, &leadMailPayload{
+		CardID: card.ID,
+		LeadID: lead.ID,
+	})
+	if err != nil {
+		// The lead stands; the owner finds it in their list.
+		zlog.Error("failed to enqueue lead notification", zap.Error(err))
+	}
+
+	return lead, nil
+}
+
+var leadTemplate = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "{{.Name}} left their contact on your business card",
+		i18n.Lao:     "{{.Name}} ໄດ້ຝາກຂໍ້ມູນຕິດຕໍ່ໄວ້ໃນນາມບັດຂອງທ່ານ",
+		i18n.Thai:    "{{.Name}} ฝากข้อมูลติดต่อไว้ในนามบัตรของคุณ",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello {{.Owner}},
+
+Someone who scanned your business card would like you to contact them.
+
+Name: {{.Name}}
+Phone: {{.Phone}}
+Email: {{.Email}}
+{{if .Message}}
+{{.Message}}
+{{end}}`,
+		i18n.Lao: `ສະບາຍດີ {{.Owner}},
+
+ມີຄົນສະແກນນາມບັດຂອງທ່ານ ແລະ ຢາກໃຫ້ທ່ານຕິດຕໍ່ກັບ.
+
+ຊື່: {{.Name}}
+ເບີໂທ: {{.Phone}}
+ອີເມວ: {{.Email}}
+{{if .Message}}
+{{.Message}}
+{{end}}`,
+		i18n.Thai: `สวัสดี {{.Owner}},
+
+มีผู้สแกนนามบัตรของคุณและต้องการให้คุณติดต่อกลับ
+
+ชื่อ: {{.Name}}
+โทรศัพท์: {{.Phone}}
+อีเมล: {{.Email}}
+{{if .Message}}
+{{.Message}}
+{{end}}`,
+	},
+}
+
+// leadMailJob is the job of emailing the owner of a card the lead left on
+// it. The payload names the lead rather than carrying its contact details.
+const leadMailJob = "card.lead_mail"
+
+type leadMailPayload struct {
+	CardID string `json:"cardId"`
+	LeadID int64  `json:"leadId"`
+}
+
+// sendLeadMail runs a leadMailJob.
+func (s *Service) sendLeadMail(ctx context.Context, payload []byte) error {
+	var p leadMailPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to decode lead mail: %w", err)
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{ID: p.CardID})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if card.Email == "" {
+		return nil
+	}
+
+	var lead *Lead
+	err = iterLeads(ctx, s.db, &LeadQuery{CardID: p.CardID, leadID: p.LeadID}, 1, func(l *Lead) error {
+		lead = l
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if lead == nil {
+		return nil
+	}
+
+	lang, err := s.employee.NotificationLanguage(ctx, card.EmployeeID)
+	if err != nil {
+		s.zlog.Warn("failed to get notification language", zap.Int64("employee_id", card.EmployeeID), zap.Error(err))
+		lang = i18n.English
+	}
+
+	msg, err := leadTemplate.Render(lang, map[string]string{
+		"Owner":   card.DisplayName,
+		"Name":    lead.Name,
+		"Phone":   lead.Phone,
+		"Email":   lead.Email,
+		"Message": lead.Message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render lead notification: %w", err)
+	}
+	msg.To = []string{card.Email}
+
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("failed to notify lead: %w", err)
+	}
+
+	return nil
+}
+
+type LeadQuery struct {
+	// CardID is the internal ID of the caller's card.
+	CardID    string `json:"-" param:"id"`
+	PageToken string `json:"pageToken" query:"pageToken"`
+	PageSize  uint64 `json:"pageSize" query:"pageSize"`
+
+	// leadID narrows the query to one lead.
+	leadID int64
+}
+
+type ListLeadsResult struct {
+	Leads         []*Lead `json:"leads"`
+	NextPageToken string  `json:"nextPageToken"`
+}
+
+// ListMyLeads lists the leads visitors left on one of the caller's cards,
+// newest first.
+func (s *Service) ListMyLeads(ctx context.Context, in *LeadQuery) (*ListLeadsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListMyLeads"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := s.checkLeadOwner(ctx, in.CardID); err != nil {
+		return nil, err
+	}
+
+	leads, err := listLeads(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to list leads", zap.Error(err))
+		return nil, err
+	}
+
+	var pageToken string
+	if l := len(leads); l > 0 && l == int(pager.Size(in.PageSize)) {
+		last := leads[l-1]
+		pageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   strconv.FormatInt(last.ID, 10),
+			Time: last.CreatedAt,
+		})
+	}
+
+	return &ListLeadsResult{
+		Leads:         leads,
+		NextPageToken: pageToken,
+	}, nil
+}
+
+// StreamMyLeads calls fn for every lead on one of the caller's cards,
+// ignoring the page size, for export.
+func (s *Service) StreamMyLeads(ctx context.Context, in *LeadQuery, fn func(*Lead) error) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "StreamMyLeads"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := s.checkLeadOwner(ctx, in.CardID); err != nil {
+		return err
+	}
+
+	if err := iterLeads(ctx, s.db, in, 0, fn); err != nil {
+		zlog.Error("failed to stream leads", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// checkLeadOwner returns an error unless the caller owns the card.
+func (s *Service) checkLeadOwner(ctx context.Context, cardID string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	_, err := getCard(ctx, s.db, &CardQuery{
+		ID:         cardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		s.zlog.Error("failed to get card by id", zap.String("id", cardID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// LeadCSVHeader is the header row of a lead export.
+var LeadCSVHeader = []string{"created_at", "name", "phone_number", "email_address", "message"}
+
+// CSV returns the lead as a row under LeadCSVHeader, with times in loc.
+// What the visitor typed is escaped with csvText.
+func (l *Lead) CSV(loc *time.Location) []string {
+	return []string{
+		tz.Format(l.CreatedAt, loc),
+		csvText(l.Name),
+		csvText(l.Phone),
+		csvText(l.Email),
+		csvText(l.Message),
+	}
+}
+
+// csvText quotes s with a leading ' when a spreadsheet would run it as a
+// formula, so a visitor cannot leave one such as =HYPERLINK(...) for the
+// owner to open. Spreadsheets hide the quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
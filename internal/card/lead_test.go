@@ -0,0 +1,187 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+// leadDB is a database of the statements SubmitLead runs, keeping the
+// leads in memory.
+type leadDB struct {
+	mu    sync.Mutex
+	leads []*leadRow
+}
+
+type leadRow struct {
+	cardID   string
+	remoteIP string
+	at       time.Time
+}
+
+func (d *leadDB) Connect(context.Context) (driver.Conn, error) { return &leadConn{d}, nil }
+func (d *leadDB) Driver() driver.Driver                        { return nil }
+
+// age moves every lead back by dt.
+func (d *leadDB) age(dt time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, l := range d.leads {
+		l.at = l.at.Add(-dt)
+	}
+}
+
+type leadConn struct {
+	db *leadDB
+}
+
+func (c *leadConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *leadConn) Close() error                        { return nil }
+func (c *leadConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *leadConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if v, ok := nv.Value.(mssql.DateTime1); ok {
+		nv.Value = time.Time(v)
+		return nil
+	}
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	nv.Value = v
+	return err
+}
+
+func (c *leadConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT COUNT(*) FROM dbo.business_card_lead WHERE card_id = @p1 AND remote_ip = @p2"):
+		var n int64
+		for _, l := range c.db.leads {
+			if l.cardID == args[0].Value && l.remoteIP == args[1].Value && !l.at.Before(args[2].Value.(time.Time)) {
+				n++
+			}
+		}
+		return &leadRows{values: []driver.Value{n}}, nil
+
+	case strings.HasPrefix(query, "INSERT INTO dbo.business_card_lead (card_id,name,phone,email,message,remote_ip,user_agent,created_at)"):
+		c.db.leads = append(c.db.leads, &leadRow{
+			cardID:   args[0].Value.(string),
+			remoteIP: args[5].Value.(string),
+			at:       args[7].Value.(time.Time),
+		})
+		return &leadRows{values: []driver.Value{int64(len(c.db.leads))}}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+func (c *leadConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "INSERT INTO dbo.job_queue") {
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected statement %q", query)
+}
+
+// leadRows is a single row of values.
+type leadRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *leadRows) Columns() []string { return make([]string, len(r.values)) }
+func (r *leadRows) Close() error      { return nil }
+
+func (r *leadRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func TestSubmitLeadLimitsEachAddress(t *testing.T) {
+	leads := &leadDB{}
+	db := sql.OpenDB(leads)
+	defer db.Close()
+
+	s := &Service{
+		db:        db,
+		published: newCardCache(10, time.Hour),
+		zlog:      zap.NewNop(),
+	}
+	s.published.set(&Card{ID: "C1", PublicID: "p1"})
+	s.published.set(&Card{ID: "C2", PublicID: "p2"})
+
+	submit := func(publicID, remoteIP string) error {
+		req := &LeadReq{CardID: publicID, Name: "Visitor", Email: "visitor@example.com"}
+		req.SetClient(remoteIP, "test")
+		_, err := s.SubmitLead(context.Background(), req)
+		return err
+	}
+
+	for i := range maxLeadsPerHour {
+		if err := submit("p1", "203.0.113.7"); err != nil {
+			t.Fatalf("lead %d = %v, want nil", i+1, err)
+		}
+	}
+	if err := submit("p1", "203.0.113.7"); grpcStatus.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("lead over the limit = %v, want ResourceExhausted", err)
+	}
+
+	// The limit is per address and card.
+	if err := submit("p1", "203.0.113.8"); err != nil {
+		t.Fatalf("lead from another address = %v, want nil", err)
+	}
+	if err := submit("p2", "203.0.113.7"); err != nil {
+		t.Fatalf("lead on another card = %v, want nil", err)
+	}
+
+	// An hour later the address may leave leads again.
+	leads.age(time.Hour + time.Minute)
+	if err := submit("p1", "203.0.113.7"); err != nil {
+		t.Fatalf("lead an hour later = %v, want nil", err)
+	}
+}
+
+func TestLeadCSVEscapesFormulas(t *testing.T) {
+	l := &Lead{
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Name:      `=HYPERLINK("http://example.com","Open")`,
+		Phone:     "+856 20 5555 5555",
+		Email:     "@SUM(1+1)",
+		Message:   "-2+3",
+	}
+
+	row := l.CSV(time.UTC)
+	want := []string{
+		`'=HYPERLINK("http://example.com","Open")`,
+		"'+856 20 5555 5555",
+		"'@SUM(1+1)",
+		"'-2+3",
+	}
+	for i, w := range want {
+		if row[i+1] != w {
+			t.Errorf("%s = %q, want %q", LeadCSVHeader[i+1], row[i+1], w)
+		}
+	}
+
+	for _, s := range []string{"", "Visitor", "visitor@example.com", "\tTab", "\rReturn"} {
+		got := csvText(s)
+		if quoted := s != "" && (s[0] == '\t' || s[0] == '\r'); quoted != strings.HasPrefix(got, "'") {
+			t.Errorf("csvText(%q) = %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/notify"
+	"go.uber.org/zap"
+)
+
+// Workflow emails, by who receives them.
+const (
+	mailManager = iota
+	mailOwner
+)
+
+// workflowMail returns the emails sent when a card moves from one status to
+// another, by recipient: the manager is asked to approve a submitted card,
+// the owner hears of the decision and both of the card going public.
+func workflowMail(from, to status) map[int]*notify.Template {
+	switch {
+	// Restoring an archived card is not news to anyone.
+	case to == from, from == StatusArchived:
+		return nil
+
+	case to == StatusPending:
+		return map[int]*notify.Template{mailManager: submittedMail}
+
+	case to == StatusApproved:
+		return map[int]*notify.Template{mailOwner: approvedMail}
+
+	case to == StatusRejected:
+		return map[int]*notify.Template{mailOwner: rejectedMail}
+
+	case to == StatusPublished:
+		return map[int]*notify.Template{mailOwner: publishedMail, mailManager: publishedMail}
+	}
+
+	return nil
+}
+
+// mailData is what workflow emails render.
+type mailData struct {
+	CardID      string
+	DisplayName string
+	Actor       string
+	Remark      string
+}
+
+// workflowMailJob is the job of sending one workflow email, see
+// enqueueWorkflowMail.
+const workflowMailJob = "card.workflow_mail"
+
+type workflowMailPayload struct {
+	// EmployeeID is the card's owner, whose manager receives mailManager.
+	EmployeeID int64    `json:"employeeId"`
+	From       status   `json:"from"`
+	To         status   `json:"to"`
+	Recipient  int      `json:"recipient"`
+	Data       mailData `json:"data"`
+}
+
+// enqueueWorkflowMail queues in tx the emails to the people concerned by a
+// card's move from one status to another. They are sent once the change
+// is committed, off the request path, and retried when sending fails.
+func (s *Service) enqueueWorkflowMail(ctx context.Context, tx *sql.Tx, card *Card, from status) error {
+	mails := workflowMail(from, card.Status)
+	// Guest cards have no employee to email.
+	if len(mails) == 0 || card.EmployeeID <= 0 {
+		return nil
+	}
+
+	for to := range mails {
+		err := s.jobs.Enqueue(ctx, tx, workflowMailJob, &workflowMailPayload{
+			EmployeeID: card.EmployeeID,
+			From:       from,
+			To:         card.Status,
+			Recipient:  to,
+			Data: mailData{
+				CardID:      card.ID,
+				DisplayName: card.DisplayName,
+				Actor:       card.updatedBy,
+				Remark:      card.Remark,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendWorkflowMail runs a workflowMailJob.
+func (s *Service) sendWorkflowMail(ctx context.Context, payload []byte) error {
+	var p workflowMailPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("failed to decode workflow mail: %w", err)
+	}
+
+	tmpl, ok := workflowMail(p.From, p.To)[p.Recipient]
+	if !ok {
+		return nil
+	}
+
+	employeeID := p.EmployeeID
+	if p.Recipient == mailManager {
+		manager, err := s.employee.ManagerOf(ctx, p.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get manager: %w", err)
+		}
+		employeeID = manager
+	}
+	if employeeID <= 0 {
+		return nil
+	}
+
+	return s.mail(ctx, employeeID, tmpl, &p.Data)
+}
+
+// mail emails the employee, if they have an address, in their language.
+func (s *Service) mail(ctx context.Context, employeeID int64, tmpl *notify.Template, data *mailData) error {
+	email, err := s.employee.EmailOf(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get email: %w", err)
+	}
+	if email == "" {
+		return nil
+	}
+
+	lang, err := s.employee.NotificationLanguage(ctx, employeeID)
+	if err != nil {
+		s.zlog.Warn("failed to get notification language", zap.Int64("employee_id", employeeID), zap.Error(err))
+		lang = i18n.English
+	}
+
+	msg, err := tmpl.Render(lang, data)
+	if err != nil {
+		return fmt.Errorf("failed to render workflow email: %w", err)
+	}
+	msg.To = []string{email}
+
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send workflow email: %w", err)
+	}
+
+	return nil
+}
+
+var submittedMail = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "Business card of {{.DisplayName}} awaiting your approval",
+		i18n.Lao:     "ນາມບັດຂອງ {{.DisplayName}} ລໍຖ້າການອະນຸມັດຈາກທ່ານ",
+		i18n.Thai:    "นามบัตรของ {{.DisplayName}} รอการอนุมัติจากคุณ",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello,
+
+{{.DisplayName}} submitted business card {{.CardID}}. Please review it in ContactQR and approve or reject it.`,
+		i18n.Lao: `ສະບາຍດີ,
+
+{{.DisplayName}} ໄດ້ສົ່ງນາມບັດ {{.CardID}}. ກະລຸນາກວດສອບໃນ ContactQR ແລະ ອະນຸມັດ ຫຼື ປະຕິເສດ.`,
+		i18n.Thai: `สวัสดี,
+
+{{.DisplayName}} ส่งนามบัตร {{.CardID}} กรุณาตรวจสอบใน ContactQR และอนุมัติหรือปฏิเสธ`,
+	},
+}
+
+var approvedMail = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "Your business card was approved",
+		i18n.Lao:     "ນາມບັດຂອງທ່ານໄດ້ຮັບການອະນຸມັດ",
+		i18n.Thai:    "นามบัตรของคุณได้รับการอนุมัติ",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello {{.DisplayName}},
+
+Your business card {{.CardID}} was approved by {{.Actor}}. It will be available once HR publishes it.`,
+		i18n.Lao: `ສະບາຍດີ {{.DisplayName}},
+
+ນາມບັດ {{.CardID}} ຂອງທ່ານໄດ້ຮັບການອະນຸມັດໂດຍ {{.Actor}}. ນາມບັດຈະພ້ອມໃຊ້ເມື່ອ HR ເຜີຍແຜ່.`,
+		i18n.Thai: `สวัสดี {{.DisplayName}},
+
+นามบัตร {{.CardID}} ของคุณได้รับการอนุมัติโดย {{.Actor}} นามบัตรจะพร้อมใช้งานเมื่อ HR เผยแพร่`,
+	},
+}
+
+var rejectedMail = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "Your business card was rejected",
+		i18n.Lao:     "ນາມບັດຂອງທ່ານຖືກປະຕິເສດ",
+		i18n.Thai:    "นามบัตรของคุณถูกปฏิเสธ",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello {{.DisplayName}},
+
+Your business card {{.CardID}} was rejected by {{.Actor}}.
+{{if .Remark}}
+Reason: {{.Remark}}
+{{end}}
+Update the card in ContactQR to submit it again.`,
+		i18n.Lao: `ສະບາຍດີ {{.DisplayName}},
+
+ນາມບັດ {{.CardID}} ຂອງທ່ານຖືກປະຕິເສດໂດຍ {{.Actor}}.
+{{if .Remark}}
+ເຫດຜົນ: {{.Remark}}
+{{end}}
+ແກ້ໄຂນາມບັດໃນ ContactQR ເພື່ອສົ່ງໃໝ່.`,
+		i18n.Thai: `สวัสดี {{.DisplayName}},
+
+นามบัตร {{.CardID}} ของคุณถูกปฏิเสธโดย {{.Actor}}
+{{if .Remark}}
+เหตุผล: {{.Remark}}
+{{end}}
+แก้ไขนามบัตรใน ContactQR เพื่อส่งอีกครั้ง`,
+	},
+}
+
+var publishedMail = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "Business card of {{.DisplayName}} published",
+		i18n.Lao:     "ນາມບັດຂອງ {{.DisplayName}} ໄດ້ຖືກເຜີຍແຜ່",
+		i18n.Thai:    "นามบัตรของ {{.DisplayName}} เผยแพร่แล้ว",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello,
+
+Business card {{.CardID}} of {{.DisplayName}} was published by {{.Actor}} and can now be shared with its QR code.`,
+		i18n.Lao: `ສະບາຍດີ,
+
+ນາມບັດ {{.CardID}} ຂອງ {{.DisplayName}} ໄດ້ຖືກເຜີຍແຜ່ໂດຍ {{.Actor}} ແລະ ສາມາດແບ່ງປັນດ້ວຍ QR ໂຄດໄດ້ແລ້ວ.`,
+		i18n.Thai: `สวัสดี,
+
+นามบัตร {{.CardID}} ของ {{.DisplayName}} เผยแพร่แล้วโดย {{.Actor}} และสามารถแชร์ด้วย QR โค้ดได้แล้ว`,
+	},
+}
@@ -0,0 +1,157 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/ndef"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// NDEF payload formats.
+const (
+	// NDEFFormatURL links to the card's public page, which phones open in
+	// the browser. It fits any tag.
+	NDEFFormatURL = "url"
+
+	// NDEFFormatVCard carries the vCard itself, so the phone needs no
+	// network to save the contact.
+	NDEFFormatVCard = "vcard"
+
+	// NDEFFormatAuto carries the vCard if it fits the tag, the URL
+	// otherwise.
+	NDEFFormatAuto = "auto"
+)
+
+// defaultTag is the tag fitted to premium printed cards.
+const defaultTag = "ntag215"
+
+type NDEFReq struct {
+	ID     string `json:"id" param:"id"`
+	Format string `json:"format" query:"format"` // url, vcard or auto. Default: auto.
+	Tag    string `json:"tag" query:"tag"`       // ntag213, ntag215 or ntag216. Default: ntag215.
+
+	// baseURL is where the public routes are served, e.g.
+	// https://cards.example.com.
+	baseURL string
+}
+
+// SetBaseURL records where the public routes are served for URL records.
+func (r *NDEFReq) SetBaseURL(u string) {
+	r.baseURL = strings.TrimRight(u, "/")
+}
+
+func (r *NDEFReq) Validate() error {
+	r.Format = strings.ToLower(strings.TrimSpace(r.Format))
+	if r.Format == "" {
+		r.Format = NDEFFormatAuto
+	}
+
+	r.Tag = strings.ToLower(strings.TrimSpace(r.Tag))
+	if r.Tag == "" {
+		r.Tag = defaultTag
+	}
+
+	violations, err := validate.Violations(&contactqrPb.GetNDEFRequest{
+		Id:     r.ID,
+		Format: r.Format,
+		Tag:    r.Tag,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidNDEF).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// NDEF is an NDEF message ready to be written to a tag.
+type NDEF struct {
+	// Format is the payload chosen, url or vcard.
+	Format string
+
+	// Size is the bytes the message takes on the tag, out of Budget.
+	Size   int
+	Budget int
+
+	Data []byte
+}
+
+// GetNDEFBusinessCard returns the NDEF message to write to the NFC tag of
+// a published card. HR and the card's owner may request it.
+func (s *Service) GetNDEFBusinessCard(ctx context.Context, in *NDEFReq) (*NDEF, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetNDEFBusinessCard"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := &CardQuery{ID: in.ID}
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	// A card edited since it was published is written as last published.
+	if card.PublicID == "" || card.Status == StatusArchived {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+
+	budget := ndef.Tags[in.Tag]
+
+	var vcard []byte
+	if in.Format != NDEFFormatURL {
+		card.vcf, card.vcfHash, err = getCardVCF(ctx, s.db, card.ID)
+		if err != nil {
+			zlog.Error("failed to get card vcf", zap.Error(err))
+			return nil, err
+		}
+		vcard = ndef.Encode(ndef.MediaRecord("text/vcard", card.vcf))
+	}
+
+	msg := &NDEF{Format: NDEFFormatVCard, Budget: budget, Data: vcard}
+	if in.Format == NDEFFormatURL || (in.Format == NDEFFormatAuto && ndef.TagSize(len(vcard)) > budget) {
+		link := in.baseURL + "/p/" + url.PathEscape(card.PublicID)
+		msg.Format = NDEFFormatURL
+		msg.Data = ndef.Encode(ndef.URIRecord(link))
+	}
+	msg.Size = ndef.TagSize(len(msg.Data))
+
+	if msg.Size > budget {
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.NDEFTooLarge,
+			"size", strconv.Itoa(msg.Size),
+			"budget", strconv.Itoa(budget),
+			"tag", in.Tag,
+		)
+	}
+
+	return msg, nil
+}
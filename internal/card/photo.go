@@ -0,0 +1,279 @@
+package card
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// renderPhotoVCF renders the download vCard of a card with its photo: the
+// one uploaded for the card, or else its owner's directory photo. Cards of
+// guests and of employees without a synced photo may have none and are
+// downloaded as their plain vCard.
+func (s *Service) renderPhotoVCF(ctx context.Context, c *Card) error {
+	c.vcfPhoto, c.photoHash = nil, ""
+
+	photo, err := s.photoOf(ctx, c)
+	if errors.Is(err, employee.ErrPhotoNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	byt, err := genVCF(c, &vcfOptions{photo: photo})
+	if err != nil {
+		return err
+	}
+
+	c.vcfPhoto = byt
+	c.photoHash = photo.Hash
+	return nil
+}
+
+// photoOf returns the photo shown on a card, or employee.ErrPhotoNotFound
+// if it has none.
+func (s *Service) photoOf(ctx context.Context, c *Card) (*employee.Photo, error) {
+	if c.photoKey == "" {
+		if c.EmployeeID <= 0 {
+			return nil, employee.ErrPhotoNotFound
+		}
+		return s.employee.PhotoOf(ctx, c.EmployeeID)
+	}
+
+	obj, err := s.assets.Get(ctx, c.photoKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(obj.Data)
+
+	return &employee.Photo{
+		EmployeeID:  c.EmployeeID,
+		ContentType: obj.ContentType,
+		Hash:        hex.EncodeToString(sum[:]),
+		UpdatedAt:   obj.ModTime,
+		Data:        obj.Data,
+	}, nil
+}
+
+// maxPhotoSize bounds an uploaded photo, like a directory photo, so that
+// the vCards embedding it stay small enough for phones to import.
+const maxPhotoSize = 256 << 10
+
+// photoPathPrefix is where photos are stored in the asset storage.
+const photoPathPrefix = "photos/"
+
+// setPhoto records the uploaded photo stored at key, if any.
+func (c *Card) setPhoto(key string) {
+	c.photoKey = key
+	c.PhotoURL = ""
+	if key != "" {
+		c.PhotoURL = "/v1/business-cards/" + c.ID + "/photo"
+	}
+}
+
+// UploadBusinessCardPhoto stores a JPEG or PNG photo for a card, shown in
+// place of its owner's directory photo. The owner and HR may upload one
+// until the card is archived. The download vCard of a published card is
+// rendered again with the new photo right away.
+func (s *Service) UploadBusinessCardPhoto(ctx context.Context, id string, data []byte) (*Card, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "UploadBusinessCardPhoto"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+		zap.Int("size", len(data)),
+	)
+
+	contentType := http.DetectContentType(data)
+	ext := ".jpg"
+	switch contentType {
+	case "image/jpeg":
+	case "image/png":
+		ext = ".png"
+	default:
+		return nil, i18n.Error(codes.InvalidArgument, i18n.InvalidPhoto)
+	}
+	if len(data) > maxPhotoSize {
+		return nil, i18n.Error(codes.InvalidArgument, i18n.PhotoTooLarge, "size", strconv.Itoa(maxPhotoSize))
+	}
+
+	q := &CardQuery{ID: id}
+	if !rbac.Can(ctx, rbac.ManageCards) {
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	if card.Status == StatusArchived {
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.CardNotUpdatable, "status", card.Status.String())
+	}
+
+	// Photos are stored by content, so uploading the same photo again
+	// rewrites the same object.
+	sum := sha256.Sum256(data)
+	key := photoPathPrefix + hex.EncodeToString(sum[:]) + ext
+	if err := s.assets.Put(ctx, key, data); err != nil {
+		zlog.Error("failed to store photo", zap.Error(err))
+		return nil, err
+	}
+
+	card.setPhoto(key)
+	card.UpdatedAt = time.Now()
+	card.updatedBy = claims.Code
+	if err := updateCardPhotoKey(ctx, s.db, card); err != nil {
+		zlog.Error("failed to update card photo key", zap.Error(err))
+		return nil, err
+	}
+
+	if card.Status == StatusPublished {
+		if err := s.renderPhotoVCF(ctx, card); err != nil {
+			zlog.Error("failed to gen photo vcf", zap.Error(err))
+			return nil, err
+		}
+		if err := updateCardPhoto(ctx, s.db, card); err != nil {
+			zlog.Error("failed to update card photo", zap.Error(err))
+			return nil, err
+		}
+		s.published.delete(card.PublicID)
+		s.storeArtifacts(ctx, zlog, card)
+	}
+
+	return s.shapeCard(ctx, card, false), nil
+}
+
+// GetBusinessCardPhoto returns the photo of a card, see photoOf. HR, the
+// card's owner and the manager approving it may see it.
+func (s *Service) GetBusinessCardPhoto(ctx context.Context, id string) (*storage.Object, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetBusinessCardPhoto"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	readAll := rbac.Can(ctx, rbac.ReadAllCards)
+	q := &CardQuery{ID: id}
+	if !readAll {
+		// Without an employee ID the manager filter would match any card.
+		if claims.ID <= 0 {
+			return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+		}
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) && !readAll {
+		card, err = getCard(ctx, s.db, &CardQuery{ID: id, managerID: claims.ID})
+	}
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	return s.cardPhoto(ctx, zlog, card)
+}
+
+// GetPublicBusinessCardPhoto returns the photo of a published card, which
+// vCard 4.0 downloads link to.
+func (s *Service) GetPublicBusinessCardPhoto(ctx context.Context, publicID string) (*storage.Object, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPublicBusinessCardPhoto"),
+		zap.String("public_id", publicID),
+	)
+
+	card, err := s.getPublishedCard(ctx, publicID)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	return s.cardPhoto(ctx, zlog, card)
+}
+
+func (s *Service) cardPhoto(ctx context.Context, zlog *zap.Logger, card *Card) (*storage.Object, error) {
+	photo, err := s.photoOf(ctx, card)
+	if errors.Is(err, employee.ErrPhotoNotFound) || errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.PhotoNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get photo", zap.Error(err))
+		return nil, err
+	}
+
+	return &storage.Object{
+		Key:         photo.Hash,
+		ContentType: photo.ContentType,
+		Data:        photo.Data,
+		ModTime:     photo.UpdatedAt,
+	}, nil
+}
+
+// RefreshPhotos re-renders the download vCards of published cards whose
+// owner's directory photo changed since they were rendered. It runs as a
+// scheduled job after the directory sync pushes new photos.
+func (s *Service) RefreshPhotos(ctx context.Context) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "RefreshPhotos"),
+	)
+
+	ids, err := listStalePhotoCards(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list stale photo cards", zap.Error(err))
+		return err
+	}
+
+	for _, id := range ids {
+		card, err := getCard(ctx, s.db, &CardQuery{ID: id})
+		if errors.Is(err, ErrCardNotFound) {
+			continue
+		}
+		if err != nil {
+			zlog.Error("failed to get card", zap.String("card_id", id), zap.Error(err))
+			return err
+		}
+
+		if err := s.renderPhotoVCF(ctx, card); err != nil {
+			zlog.Error("failed to gen photo vcf", zap.String("card_id", id), zap.Error(err))
+			return err
+		}
+		if err := updateCardPhoto(ctx, s.db, card); err != nil {
+			zlog.Error("failed to update card photo", zap.String("card_id", id), zap.Error(err))
+			return err
+		}
+
+		s.published.delete(card.PublicID)
+		s.storeArtifacts(ctx, zlog.With(zap.String("card_id", id)), card)
+	}
+
+	if len(ids) > 0 {
+		zlog.Info("refreshed card photos", zap.Int("cards", len(ids)))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/poster"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// maxPosterCards is the most cards a department poster shows.
+const maxPosterCards = 200
+
+type PosterReq struct {
+	// ID is the card's ID, or the department's for a department poster.
+	ID   string `json:"id" param:"id"`
+	Size string `json:"size" query:"size"` // a4 or a5. Default: a4.
+
+	size poster.Size
+}
+
+func (r *PosterReq) Validate() error {
+	r.Size = strings.ToLower(strings.TrimSpace(r.Size))
+	if r.Size == "" {
+		r.Size = string(poster.A4)
+	}
+
+	violations, err := validate.Violations(&contactqrPb.GetPosterRequest{
+		Id:   r.ID,
+		Size: r.Size,
+	})
+	if err != nil {
+		return err
+	}
+	r.size, _ = poster.ParseSize(r.Size)
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPoster).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// GetPosterBusinessCard renders a poster of a published card's QR code.
+// HR and the card's owner may request it.
+func (s *Service) GetPosterBusinessCard(ctx context.Context, in *PosterReq) (*storage.Object, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetPosterBusinessCard"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := &CardQuery{ID: in.ID}
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	if card.Status != StatusPublished {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+
+	obj, err := s.renderPoster(ctx, in.size, "card-"+card.ID, []*Card{card})
+	if err != nil {
+		zlog.Error("failed to render poster", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// GetDepartmentPoster renders a poster of the QR codes of every published
+// card in a department. It is for HR only.
+func (s *Service) GetDepartmentPoster(ctx context.Context, in *PosterReq) (*storage.Object, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetDepartmentPoster"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageCards) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	departmentID, err := strconv.ParseInt(in.ID, 10, 64)
+	if err != nil || departmentID <= 0 {
+		return nil, i18n.Error(codes.NotFound, i18n.NoPublishedCards, "departmentId", in.ID)
+	}
+
+	cards, err := listCards(ctx, s.db, &CardQuery{
+		DepartmentID: departmentID,
+		Status:       StatusPublished.String(),
+		PageSize:     maxPosterCards,
+	})
+	if err != nil {
+		zlog.Error("failed to list cards", zap.Error(err))
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, i18n.Error(codes.NotFound, i18n.NoPublishedCards, "departmentId", in.ID)
+	}
+
+	obj, err := s.renderPoster(ctx, in.size, "department-"+in.ID, cards)
+	if err != nil {
+		zlog.Error("failed to render poster", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// renderPoster renders the cards' QR codes, branded for the company of the
+// first card.
+func (s *Service) renderPoster(ctx context.Context, size poster.Size, name string, cards []*Card) (*storage.Object, error) {
+	p := &poster.Poster{
+		Size:    size,
+		Brand:   s.brands.For(cards[0].CompanyID),
+		Company: cards[0].CompanyName,
+		Entries: make([]poster.Entry, 0, len(cards)),
+	}
+
+	for _, c := range cards {
+		vcf, _, err := getCardVCF(ctx, s.db, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.Entries = append(p.Entries, poster.Entry{
+			Name:     c.DisplayName,
+			Title:    c.PositionName,
+			Subtitle: c.DepartmentName,
+			Phone:    displayNumber(c.PhoneE164, c.PhoneNumber, s.styles.For(c.CompanyID)),
+			Content:  vcf,
+		})
+	}
+
+	data, err := poster.Render(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.Object{
+		Key:         fmt.Sprintf("poster-%s-%s.pdf", name, size),
+		ContentType: "application/pdf",
+		Data:        data,
+		ModTime:     time.Now(),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package card
+
+import (
+	"context"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/reqid"
+	qrcode "github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+)
+
+// Preview is a card as it would be created, with the vCard and QR code it
+// would be published with.
+type Preview struct {
+	Card *Card  `json:"card"`
+	VCF  string `json:"vcf"`
+	QR   string `json:"qrSvg"`
+}
+
+func (p *Preview) Proto() *contactqrPb.BusinessCardPreview {
+	return &contactqrPb.BusinessCardPreview{
+		BusinessCard: p.Card.Proto(),
+		Vcf:          p.VCF,
+		QrSvg:        p.QR,
+	}
+}
+
+// PreviewBusinessCard builds the card the caller would create from in,
+// without saving it, so they can check how it reads before submitting it
+// for approval. The card has no ID yet and the limit on cards held is not
+// checked.
+func (s *Service) PreviewBusinessCard(ctx context.Context, in *CardReq) (*Preview, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "PreviewBusinessCard"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	in.region = s.regions.For(claims.CompanyID)
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	employee, err := s.employee.GetMyEmployeeProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged, err := s.checkEmail(employee)
+	if err != nil {
+		return nil, err
+	}
+
+	employee.SetPhone(in.Phone.Number)
+	employee.SetMobile(in.Mobile.Number)
+	card := newCardFromEmployee(employee)
+	card.ID = ""
+	card.setPhones(in.phone, in.mobile)
+	card.EmailFlagged = flagged
+	card.PhoneticGivenName = in.PhoneticGivenName
+	card.PhoneticFamilyName = in.PhoneticFamilyName
+	card.setNameLanguages(in.nameLanguages())
+	card.SocialLinks = in.SocialLinks
+	card.Address, err = getCompanyAddress(ctx, s.db, card.CompanyID)
+	if err != nil {
+		zlog.Error("failed to get company address", zap.Error(err))
+		return nil, err
+	}
+
+	if err := card.renderVCF(); err != nil {
+		zlog.Error("failed to gen vcf", zap.Error(err))
+		return nil, err
+	}
+	qr, err := genQRSVG(card.vcf, qrcode.Medium, 0)
+	if err != nil {
+		zlog.Error("failed to gen qr", zap.Error(err))
+		return nil, err
+	}
+
+	return &Preview{
+		Card: s.shapeCard(ctx, card, false),
+		VCF:  string(card.vcf),
+		QR:   string(qr),
+	}, nil
+}
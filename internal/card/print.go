@@ -0,0 +1,254 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrPrintRequestNotFound = errors.New("print request not found")
+
+// PrintStatus is where a print request is in its way to the delivery
+// office.
+type PrintStatus string
+
+const (
+	PrintRequested PrintStatus = "REQUESTED"
+	PrintOrdered   PrintStatus = "ORDERED"
+	PrintDelivered PrintStatus = "DELIVERED"
+)
+
+// PrintRequest asks for physical cards printed from a published card. HR
+// orders them from the print vendor and marks them delivered once they
+// reach the delivery office.
+type PrintRequest struct {
+	ID             int64       `json:"id"`
+	CardID         string      `json:"cardId"`
+	Quantity       int32       `json:"quantity"`
+	DeliveryOffice string      `json:"deliveryOffice"`
+	Status         PrintStatus `json:"status"`
+	CreatedBy      string      `json:"createdBy"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedBy      string      `json:"updatedBy"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+	OrderedAt      *time.Time  `json:"orderedAt,omitempty"`
+	DeliveredAt    *time.Time  `json:"deliveredAt,omitempty"`
+}
+
+type PrintRequestReq struct {
+	CardID         string `json:"-" param:"id"`
+	Quantity       int32  `json:"quantity"`
+	DeliveryOffice string `json:"deliveryOffice"`
+}
+
+func (r *PrintRequestReq) Validate() error {
+	r.DeliveryOffice = strings.TrimSpace(r.DeliveryOffice)
+
+	violations, err := validate.Violations(&contactqrPb.CreatePrintRequestRequest{
+		Quantity:       r.Quantity,
+		DeliveryOffice: r.DeliveryOffice,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPrintRequest).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreatePrintRequest asks for physical cards of a published card. Employees
+// request them for their own cards, HR for anyone's. A card has at most one
+// request that is not delivered yet.
+func (s *Service) CreatePrintRequest(ctx context.Context, in *PrintRequestReq) (*PrintRequest, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreatePrintRequest"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := &CardQuery{ID: in.CardID}
+	if !rbac.Can(ctx, rbac.ManageCards) {
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	if card.Status != StatusPublished {
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.CardNotPublished, "status", card.Status.String())
+	}
+
+	now := time.Now()
+	pr := &PrintRequest{
+		CardID:         card.ID,
+		Quantity:       in.Quantity,
+		DeliveryOffice: in.DeliveryOffice,
+		Status:         PrintRequested,
+		CreatedBy:      claims.Code,
+		CreatedAt:      now,
+		UpdatedBy:      claims.Code,
+		UpdatedAt:      now,
+	}
+	created, err := createPrintRequest(ctx, s.db, pr)
+	if err != nil {
+		zlog.Error("failed to create print request", zap.Error(err))
+		return nil, err
+	}
+	if !created {
+		open, err := getOpenPrintRequest(ctx, s.db, card.ID)
+		if err != nil {
+			zlog.Error("failed to get open print request", zap.Error(err))
+			return nil, err
+		}
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.PrintRequestOpen,
+			"printRequestId", strconv.FormatInt(open.ID, 10),
+			"status", string(open.Status),
+		)
+	}
+
+	return pr, nil
+}
+
+type PrintRequestQuery struct {
+	Status         string `json:"status" query:"status"`
+	CardID         string `json:"cardId" query:"cardId"`
+	DeliveryOffice string `json:"deliveryOffice" query:"deliveryOffice"`
+	PageToken      string `json:"pageToken" query:"pageToken"`
+	PageSize       uint64 `json:"pageSize" query:"pageSize"`
+}
+
+func (q *PrintRequestQuery) Validate() error {
+	q.Status = strings.ToUpper(strings.TrimSpace(q.Status))
+	q.DeliveryOffice = strings.TrimSpace(q.DeliveryOffice)
+
+	switch PrintStatus(q.Status) {
+	case "", PrintRequested, PrintOrdered, PrintDelivered:
+		return nil
+	}
+
+	violations := []*edPb.BadRequest_FieldViolation{
+		i18n.Violation("status", i18n.InvalidValue),
+	}
+	s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPrintQuery).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+	return s.Err()
+}
+
+type ListPrintRequestsResult struct {
+	PrintRequests []*PrintRequest `json:"printRequests"`
+	NextPageToken string          `json:"nextPageToken"`
+}
+
+// ListPrintRequests lists the print requests matching in, newest first,
+// for HR and the print coordinator.
+func (s *Service) ListPrintRequests(ctx context.Context, in *PrintRequestQuery) (*ListPrintRequestsResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListPrintRequests"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadPrintRequests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.PrintRequestsForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	requests, err := listPrintRequests(ctx, s.db, in)
+	if err != nil {
+		zlog.Error("failed to list print requests", zap.Error(err))
+		return nil, err
+	}
+
+	var pageToken string
+	if l := len(requests); l > 0 && l == int(pager.Size(in.PageSize)) {
+		last := requests[l-1]
+		pageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   strconv.FormatInt(last.ID, 10),
+			Time: last.CreatedAt,
+		})
+	}
+
+	return &ListPrintRequestsResult{
+		PrintRequests: requests,
+		NextPageToken: pageToken,
+	}, nil
+}
+
+// OrderPrintRequest marks a request ORDERED once it is sent to the print
+// vendor. It is for HR only.
+func (s *Service) OrderPrintRequest(ctx context.Context, id int64) (*PrintRequest, error) {
+	return s.movePrintRequest(ctx, "OrderPrintRequest", id, PrintRequested, PrintOrdered, i18n.PrintRequestNotOrderable)
+}
+
+// DeliverPrintRequest marks an ordered request DELIVERED once the cards
+// reach the delivery office. It is for HR only.
+func (s *Service) DeliverPrintRequest(ctx context.Context, id int64) (*PrintRequest, error) {
+	return s.movePrintRequest(ctx, "DeliverPrintRequest", id, PrintOrdered, PrintDelivered, i18n.PrintRequestNotDeliverable)
+}
+
+// movePrintRequest moves the request from one status to the next, failing
+// with notMovable when it is in another status.
+func (s *Service) movePrintRequest(ctx context.Context, method string, id int64, from, to PrintStatus, notMovable i18n.Key) (*PrintRequest, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", method),
+		zap.String("username", claims.Code),
+		zap.Int64("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManagePrintRequests) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.PrintRequestsForbidden)
+	}
+
+	moved, err := updatePrintStatus(ctx, s.db, id, from, to, claims.Code, time.Now())
+	if err != nil {
+		zlog.Error("failed to update print request status", zap.Error(err))
+		return nil, err
+	}
+
+	pr, err := getPrintRequest(ctx, s.db, id)
+	if errors.Is(err, ErrPrintRequestNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.PrintRequestNotFound, "printRequestId", strconv.FormatInt(id, 10))
+	}
+	if err != nil {
+		zlog.Error("failed to get print request by id", zap.Error(err))
+		return nil, err
+	}
+	if !moved {
+		return nil, i18n.Error(codes.FailedPrecondition, notMovable, "status", string(pr.Status))
+	}
+
+	return pr, nil
+}
@@ -0,0 +1,177 @@
+package card
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/validate"
+	qrcode "github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+const qrSize = 512
+
+// qrLevels are the error correction levels by name. Higher levels survive
+// more damage, such as a logo printed over the code, at the cost of a
+// denser code.
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+func genQRPNG(content []byte, level qrcode.RecoveryLevel, size int) ([]byte, error) {
+	return qrcode.Encode(string(content), level, size)
+}
+
+// genQRSVG renders the QR code as SVG. A size of 0 leaves the width and
+// height to the page.
+func genQRSVG(content []byte, level qrcode.RecoveryLevel, size int) ([]byte, error) {
+	q, err := qrcode.New(string(content), level)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap := q.Bitmap()
+	n := len(bitmap)
+
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg"`)
+	if size > 0 {
+		fmt.Fprintf(&b, ` width="%d" height="%d"`, size, size)
+	}
+	fmt.Fprintf(&b, ` viewBox="0 0 %d %d" shape-rendering="crispEdges">`, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, n, n)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+
+	return []byte(b.String()), nil
+}
+
+// qrKey returns the storage key of a QR artifact for the given vCard hash.
+func qrKey(hash, format string) string {
+	return fmt.Sprintf("qr/%s.%s", hash, format)
+}
+
+type CardQRReq struct {
+	ID     string `json:"id" param:"id"`
+	Format string `json:"format" query:"format"` // png or svg. Default: png.
+	Size   int32  `json:"size" query:"size"`     // Pixels, 128 to 2048. Default: 512.
+	Level  string `json:"level" query:"level"`   // L, M, Q or H. Default: M.
+
+	// baseURL is where the public routes are served, e.g.
+	// https://cards.example.com.
+	baseURL string
+}
+
+// SetBaseURL records where the public routes are served for the QR URL.
+func (r *CardQRReq) SetBaseURL(u string) {
+	r.baseURL = strings.TrimRight(u, "/")
+}
+
+func (r *CardQRReq) Validate() error {
+	r.Format = strings.ToLower(strings.TrimSpace(r.Format))
+	if r.Format == "" {
+		r.Format = QRFormatPNG
+	}
+	if r.Size == 0 {
+		r.Size = qrSize
+	}
+	r.Level = strings.ToUpper(strings.TrimSpace(r.Level))
+	if r.Level == "" {
+		r.Level = "M"
+	}
+
+	violations, err := validate.Violations(&contactqrPb.GetCardQRRequest{
+		Id:     r.ID,
+		Format: r.Format,
+		Size:   r.Size,
+		Level:  r.Level,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidQR).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// GetBusinessCardQR renders a QR code linking to the public vCard of a
+// published card, for clients printing or showing the code themselves.
+// HR and the card's owner may request it. Unlike the public QR code, which
+// carries the vCard itself, it stays valid when the card is published
+// again.
+func (s *Service) GetBusinessCardQR(ctx context.Context, in *CardQRReq) (*storage.Object, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetBusinessCardQR"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	q := &CardQuery{ID: in.ID}
+	if !rbac.Can(ctx, rbac.ReadAllCards) {
+		q.EmployeeID = claims.ID
+	}
+	card, err := getCard(ctx, s.db, q)
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	if card.Status != StatusPublished || card.PublicID == "" {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+
+	url := []byte(in.baseURL + "/v1/public/business-cards/" + card.PublicID + "/vcf")
+	level := qrLevels[in.Level]
+
+	obj := &storage.Object{
+		Key:     fmt.Sprintf("%s-%d-%s.%s", card.PublicID, in.Size, in.Level, in.Format),
+		ModTime: card.UpdatedAt,
+	}
+	switch in.Format {
+	case QRFormatSVG:
+		obj.Data, err = genQRSVG(url, level, int(in.Size))
+		obj.ContentType = "image/svg+xml"
+
+	default:
+		obj.Data, err = genQRPNG(url, level, int(in.Size))
+		obj.ContentType = "image/png"
+	}
+	if err != nil {
+		zlog.Error("failed to gen qr", zap.Error(err))
+		return nil, err
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,55 @@
+package card
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/10664kls/contactqr/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxRoles bounds the cards merged into one vCard.
+const maxRoles = 10
+
+// listRoles returns the other published cards of a card's owner, the
+// roles merged into its vCard. Guest cards have none.
+func (s *Service) listRoles(ctx context.Context, card *Card) ([]*Card, error) {
+	if card.EmployeeID <= 0 {
+		return nil, nil
+	}
+
+	cards, err := listCards(ctx, s.db, &CardQuery{
+		EmployeeID: card.EmployeeID,
+		Status:     StatusPublished.String(),
+		PageSize:   maxRoles,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*Card, 0, len(cards))
+	for _, c := range cards {
+		if c.ID != card.ID {
+			roles = append(roles, c)
+		}
+	}
+
+	return roles, nil
+}
+
+// encodeMergedVCF generates one vCard for card and the owner's other roles.
+// It is generated on request since it changes with any of the cards.
+func encodeMergedVCF(ctx context.Context, card *Card, roles []*Card, opts *vcfOptions) (*VCF, error) {
+	_, span := tracing.Start(ctx, "card.encodeMergedVCF", attribute.String("card.id", card.ID))
+	defer span.End()
+
+	opts.roles = roles
+	byt, err := genVCF(card, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VCF{
+		Content: base64.StdEncoding.EncodeToString(byt),
+		Hash:    vcfHash(byt),
+	}, nil
+}
@@ -0,0 +1,369 @@
+package card
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// ShareLink lets whoever holds its token see one card, published or not,
+// until the link expires or is revoked, e.g. for a consultant to hand out
+// their card before HR publishes it. The token is an encrypted PASETO
+// naming the link, and is returned only when the link is created.
+type ShareLink struct {
+	ID        string     `json:"id"`
+	CardID    string     `json:"cardId"`
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty"` // The card page the token opens.
+	ExpiresAt time.Time  `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+
+	createdBy string
+	revokedBy string
+}
+
+// Share link states.
+const (
+	ShareLinkActive  = "ACTIVE"
+	ShareLinkExpired = "EXPIRED"
+	ShareLinkRevoked = "REVOKED"
+)
+
+// StatusAt returns the state of the share link at t.
+func (l *ShareLink) StatusAt(t time.Time) string {
+	switch {
+	case l.RevokedAt != nil:
+		return ShareLinkRevoked
+	case t.Before(l.ExpiresAt):
+		return ShareLinkActive
+	}
+	return ShareLinkExpired
+}
+
+func (l *ShareLink) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.JSONView())
+}
+
+// JSONView returns the share link as MarshalJSON encodes it, with its
+// current state.
+func (l *ShareLink) JSONView() any {
+	type alias ShareLink
+	return &struct {
+		*alias
+		Status string `json:"status"`
+	}{
+		alias:  (*alias)(l),
+		Status: l.StatusAt(time.Now()),
+	}
+}
+
+const (
+	// defaultShareWindow is how long a share link lasts when the request
+	// does not say.
+	defaultShareWindow = 7 * 24 * time.Hour
+
+	// maxShareWindow is the longest a share link lasts.
+	maxShareWindow = 30 * 24 * time.Hour
+)
+
+type ShareLinkReq struct {
+	CardID string `json:"-" param:"id"`
+
+	// ExpiresAt is when the link stops working. Default: in 7 days.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// baseURL is where the card pages are served, e.g.
+	// https://cards.example.com, for the link's URL.
+	baseURL string
+}
+
+// SetBaseURL records where the card pages are served.
+func (r *ShareLinkReq) SetBaseURL(u string) {
+	r.baseURL = strings.TrimRight(u, "/")
+}
+
+func (r *ShareLinkReq) Validate() error {
+	now := time.Now()
+	if r.ExpiresAt.IsZero() {
+		r.ExpiresAt = now.Add(defaultShareWindow)
+	}
+
+	var violations []*edPb.BadRequest_FieldViolation
+	if !r.ExpiresAt.After(now) || r.ExpiresAt.Sub(now) > maxShareWindow {
+		violations = append(violations, i18n.Violation("expiresAt", i18n.InvalidExpiry))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidShareLink).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreateMyShareLink creates a share link to one of the caller's cards.
+func (s *Service) CreateMyShareLink(ctx context.Context, in *ShareLinkReq) (*ShareLink, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateMyShareLink"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if s.shares == nil {
+		return nil, i18n.Error(codes.Unimplemented, i18n.ShareLinksDisabled)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{
+		ID:         in.CardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+	if card.Status == StatusArchived {
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.CardNotUpdatable, "status", card.Status.String())
+	}
+
+	l := &ShareLink{
+		ID:        strings.ToUpper(strings.Split(uuid.NewString(), "-")[4]),
+		CardID:    card.ID,
+		ExpiresAt: in.ExpiresAt,
+		CreatedAt: time.Now(),
+		createdBy: claims.Code,
+	}
+	if err := createShareLink(ctx, s.db, l); err != nil {
+		zlog.Error("failed to create share link", zap.Error(err))
+		return nil, err
+	}
+
+	t := paseto.NewToken()
+	t.SetJti(l.ID)
+	t.SetSubject(l.CardID)
+	t.SetIssuedAt(l.CreatedAt)
+	t.SetExpiration(l.ExpiresAt)
+	l.Token = s.shares.Encrypt(&t)
+	l.URL = in.baseURL + "/s/" + l.Token
+
+	zlog.Info("share link created", zap.String("share_link_id", l.ID))
+	return l, nil
+}
+
+type ListShareLinksResult struct {
+	ShareLinks []*ShareLink `json:"shareLinks"`
+}
+
+// ListMyShareLinks lists the share links to one of the caller's cards,
+// newest first. Their tokens are not returned.
+func (s *Service) ListMyShareLinks(ctx context.Context, cardID string) (*ListShareLinksResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListMyShareLinks"),
+		zap.String("card_id", cardID),
+		zap.String("username", claims.Code),
+	)
+
+	_, err := getCard(ctx, s.db, &CardQuery{
+		ID:         cardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return nil, err
+	}
+
+	links, err := listShareLinks(ctx, s.db, cardID)
+	if err != nil {
+		zlog.Error("failed to list share links", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListShareLinksResult{ShareLinks: links}, nil
+}
+
+// RevokeMyShareLink stops a share link to one of the caller's cards from
+// working. Revoking a revoked link does nothing.
+func (s *Service) RevokeMyShareLink(ctx context.Context, cardID, id string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "RevokeMyShareLink"),
+		zap.String("card_id", cardID),
+		zap.String("id", id),
+		zap.String("username", claims.Code),
+	)
+
+	_, err := getCard(ctx, s.db, &CardQuery{
+		ID:         cardID,
+		EmployeeID: claims.ID,
+	})
+	if errors.Is(err, ErrCardNotFound) {
+		return i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get card by id", zap.Error(err))
+		return err
+	}
+
+	l, err := getShareLink(ctx, s.db, id)
+	if errors.Is(err, ErrShareLinkNotFound) || (err == nil && l.CardID != cardID) {
+		return i18n.Error(codes.NotFound, i18n.ShareLinkNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get share link", zap.Error(err))
+		return err
+	}
+	if l.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	l.RevokedAt = &now
+	l.revokedBy = claims.Code
+	if err := revokeShareLink(ctx, s.db, l); err != nil {
+		zlog.Error("failed to revoke share link", zap.Error(err))
+		return err
+	}
+
+	zlog.Info("share link revoked")
+	return nil
+}
+
+// getSharedCard returns the share link token names and its card, if the
+// link is active and the card not archived. The card is as it is now,
+// with its vCard rendered afresh.
+func (s *Service) getSharedCard(ctx context.Context, token string) (*ShareLink, *Card, error) {
+	if s.shares == nil {
+		return nil, nil, ErrCardNotFound
+	}
+
+	parser := paseto.MakeParser([]paseto.Rule{
+		paseto.NotExpired(),
+		paseto.ValidAt(time.Now()),
+	})
+	t, err := s.shares.Parse(parser, token, nil)
+	if err != nil {
+		return nil, nil, ErrCardNotFound
+	}
+	id, err := t.GetJti()
+	if err != nil {
+		return nil, nil, ErrCardNotFound
+	}
+	cardID, err := t.GetSubject()
+	if err != nil {
+		return nil, nil, ErrCardNotFound
+	}
+
+	l, err := getShareLink(ctx, s.db, id)
+	if errors.Is(err, ErrShareLinkNotFound) {
+		return nil, nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if l.CardID != cardID || l.StatusAt(time.Now()) != ShareLinkActive {
+		return nil, nil, ErrCardNotFound
+	}
+
+	card, err := getCard(ctx, s.db, &CardQuery{ID: l.CardID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if card.Status == StatusArchived {
+		return nil, nil, ErrCardNotFound
+	}
+
+	if err := card.renderVCF(); err != nil {
+		return nil, nil, err
+	}
+
+	return l, card, nil
+}
+
+// GetSharedBusinessCard returns the card a share link token opens, shaped
+// for an anonymous visitor. Visits through share links are not counted
+// as views of the card.
+func (s *Service) GetSharedBusinessCard(ctx context.Context, token, remoteIP string) (*Card, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetSharedBusinessCard"),
+	)
+
+	l, card, err := s.getSharedCard(ctx, token)
+	if errors.Is(err, ErrCardNotFound) {
+		zlog.Info("shared card access denied", zap.String("remote_ip", remoteIP))
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get shared card", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Info("shared card accessed",
+		zap.String("share_link_id", l.ID),
+		zap.String("remote_ip", remoteIP),
+	)
+	return s.shapeCard(ctx, card, false), nil
+}
+
+// GetSharedVCFBusinessCard serves the vCard of the card a share link token
+// opens. The request's ID is the token.
+func (s *Service) GetSharedVCFBusinessCard(ctx context.Context, in *VCFReq) (*VCF, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetSharedVCFBusinessCard"),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	l, card, err := s.getSharedCard(ctx, in.ID)
+	if errors.Is(err, ErrCardNotFound) {
+		zlog.Info("shared card access denied", zap.String("remote_ip", in.remoteIP))
+		return nil, i18n.Error(codes.PermissionDenied, i18n.CardNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get shared card", zap.Error(err))
+		return nil, err
+	}
+
+	zlog.Info("shared card accessed",
+		zap.String("share_link_id", l.ID),
+		zap.String("remote_ip", in.remoteIP),
+		zap.String("user_agent", in.userAgent),
+	)
+
+	vcf, err := encodeVCF(ctx, card, in.vcfOptions(card))
+	if err != nil {
+		zlog.Error("failed to gen vcf", zap.Error(err))
+		return nil, err
+	}
+
+	return vcf, nil
+}
@@ -1,21 +1,33 @@
 package card
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/i18n"
 	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/pii"
 	"github.com/10664kls/contactqr/internal/utils"
 	sq "github.com/Masterminds/squirrel"
 )
 
 var ErrCardNotFound = errors.New("card not found")
 
+// errIdempotencyKeyUsed is returned creating a card with an Idempotency-Key
+// another card was created with.
+var errIdempotencyKeyUsed = errors.New("idempotency key used")
+
 type CardQuery struct {
 	managerID     int64
+	publicID      string
 	EmployeeID    int64     `json:"employeeId" query:"employeeId"`
 	PositionID    int64     `json:"positionId" query:"positionId"`
 	DepartmentID  int64     `json:"departmentId" query:"departmentId"`
@@ -28,6 +40,24 @@ type CardQuery struct {
 	CreatedBefore time.Time `json:"createdBefore" query:"createdBefore"`
 	PageToken     string    `json:"pageToken" query:"pageToken"`
 	PageSize      uint64    `json:"pageSize" query:"pageSize"`
+
+	// Page selects a page by number, from 1, instead of by PageToken.
+	// IncludeTotal counts the matching cards across all pages.
+	Page         uint64 `json:"page" query:"page"`
+	IncludeTotal bool   `json:"includeTotal" query:"includeTotal"`
+
+	// IncludeArchived lists archived cards along with the others. Without
+	// it they are only listed when asked for by status.
+	IncludeArchived bool `json:"includeArchived" query:"includeArchived"`
+
+	// EmployeeChanged lists only the cards flagged out of date by an HR
+	// import.
+	EmployeeChanged bool `json:"employeeChanged" query:"employeeChanged"`
+
+	// since lists the cards changed after the cursor, oldest change first.
+	since *pager.Cursor
+
+	guestID int64
 }
 
 func (q *CardQuery) ToSql() (string, []any, error) {
@@ -65,38 +95,166 @@ func (q *CardQuery) ToSql() (string, []any, error) {
 		and = append(and, sq.Eq{"status": q.Status})
 	}
 
+	if q.hidesArchived() {
+		and = append(and, sq.NotEq{"status": StatusArchived.String()})
+	}
+
+	if q.publicID != "" {
+		and = append(and, sq.Eq{"b.public_id": q.publicID})
+	}
+
+	if q.EmployeeChanged {
+		and = append(and, sq.NotEq{"b.employee_changed_at": nil})
+	}
+
 	if q.managerID > 0 {
 		and = append(and, sq.Eq{"manager_id": q.managerID})
 	}
 
+	if q.guestID > 0 {
+		and = append(and, sq.Eq{"b.guest_id": q.guestID})
+	}
+
 	if !q.CreatedBefore.IsZero() {
-		and = append(and, sq.LtOrEq{"created_at": q.CreatedBefore})
+		and = append(and, sq.LtOrEq{"created_at": pager.DateTime(q.CreatedBefore)})
 	}
 	if !q.CreatedAfter.IsZero() {
-		and = append(and, sq.GtOrEq{"created_at": q.CreatedAfter})
+		and = append(and, sq.GtOrEq{"created_at": pager.DateTime(q.CreatedAfter)})
 	}
 
-	if q.PageToken != "" {
+	if q.PageToken != "" && q.Page == 0 {
 		cursor, err := pager.DecodeCursor(q.PageToken)
 		if err != nil {
 			return "", nil, err
 		}
-		and = append(and, sq.Expr("created_at < ?", cursor.Time))
+		and = append(and, cursor.After("created_at", "id"))
+	}
+
+	if q.since != nil {
+		and = append(and, q.since.Since("updated_at", "id"))
 	}
 
 	return and.ToSql()
 }
 
+// hidesArchived reports whether archived cards are left out. Listings hide
+// them by default; lookups of one card and syncs, which must see a card
+// being archived, never do.
+func (q *CardQuery) hidesArchived() bool {
+	return !q.IncludeArchived && q.Status == "" &&
+		q.ID == "" && q.publicID == "" && q.since == nil
+}
+
+// orderBy returns the listing order: newest first, or the order of changes
+// when syncing.
+func (q *CardQuery) orderBy() []string {
+	if q.since != nil {
+		return []string{"updated_at ASC", "id ASC"}
+	}
+	return []string{"created_at DESC", "id DESC"}
+}
+
+// cardSource is the set of cards iterCards reads: the employees' cards from
+// v_business_card and the guests' cards, shaped like the view's rows. A
+// guest card has no employee, department or position ID, its employee code
+// is the guest ID prefixed with G and its sponsor approves it as manager.
+// Deleted cards are left out.
+const cardSource = `(
+	SELECT id, employee_id, department_id, position_id, company_id, display_name, employee_code,
+		department_name, position_name, company_name, email, phone, mobile, status, remark,
+		created_at, updated_at, created_by, updated_by, manager_id
+	FROM dbo.v_business_card
+	WHERE employee_id IS NOT NULL
+		AND id NOT IN (SELECT id FROM dbo.business_card WHERE deleted_at IS NOT NULL)
+	UNION ALL
+	SELECT c.id, 0, 0, 0, c.company_id, g.display_name, CONCAT('G', g.id),
+		g.department_name, g.position_name, COALESCE(br.BranchName, ''), c.email, c.phone, c.mobile, c.status, c.remark,
+		c.created_at, c.updated_at, c.created_by, c.updated_by, g.sponsor_id
+	FROM dbo.business_card AS c
+	INNER JOIN dbo.guest_person AS g ON g.id = c.guest_id
+	LEFT JOIN dbo.tb_Branch AS br ON br.BID = c.company_id
+	WHERE c.deleted_at IS NULL
+) AS v_business_card`
+
+// cardColumns reads the columns added to dbo.business_card after the view
+// was defined, as b.
+const cardColumns = `CROSS APPLY (
+	SELECT public_id, phone_e164, phone_national, mobile_e164, mobile_national, email_flagged,
+		phonetic_given_name, phonetic_family_name, guest_id, archived_at, archived_from, photo_key,
+		employee_changed_at,
+		display_name_en, display_name_lo, name_languages,
+		whatsapp, line_id, wechat_id, linkedin_url, website_url
+	FROM dbo.business_card
+	WHERE business_card.id = v_business_card.id
+) AS b`
+
+// addressColumns reads the office address of the card's company, as a.
+// Its columns are prefixed so that none clashes with the unqualified
+// columns of v_business_card, such as company_id and updated_at.
+const addressColumns = `OUTER APPLY (
+	SELECT street AS address_street, locality AS address_locality, region AS address_region,
+		postal_code AS address_postal_code, country AS address_country,
+		latitude AS address_latitude, longitude AS address_longitude
+	FROM dbo.company_address
+	WHERE company_address.company_id = v_business_card.company_id
+) AS a`
+
 func listCards(ctx context.Context, db *sql.DB, in *CardQuery) ([]*Card, error) {
-	id := fmt.Sprintf("TOP %d id", pager.Size(in.PageSize))
-	pred, args, err := in.ToSql()
+	cards := make([]*Card, 0)
+	err := iterCards(ctx, db, in, pager.Size(in.PageSize), func(c *Card) error {
+		cards = append(cards, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// countCards returns the number of cards matching in across all pages.
+func countCards(ctx context.Context, db *sql.DB, in *CardQuery) (int64, error) {
+	all := *in
+	all.PageToken = ""
+	pred, args, err := all.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return 0, fmt.Errorf("failed to build query: %w", err)
 	}
 
 	q, args := sq.
+		Select("COUNT(*)").
+		From(cardSource).
+		JoinClause(cardColumns).
+		Where(pred, args...).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var count int64
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return count, nil
+}
+
+// iterCards calls fn for every card matching in, in listing order, without
+// buffering the result set. A limit of 0 returns all matching cards.
+// Rows are read only as fast as fn returns, so a slow consumer throttles
+// the query instead of growing memory.
+func iterCards(ctx context.Context, db *sql.DB, in *CardQuery, limit uint64, fn func(*Card) error) error {
+	id := "id"
+	if limit > 0 && in.Page == 0 {
+		id = fmt.Sprintf("TOP %d id", limit)
+	}
+	pred, args, err := in.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	b := sq.
 		Select(
 			id,
+			"b.public_id",
 			"employee_id",
 			"department_id",
 			"position_id",
@@ -108,31 +266,72 @@ func listCards(ctx context.Context, db *sql.DB, in *CardQuery) ([]*Card, error)
 			"company_name",
 			"email",
 			"phone",
+			"b.phone_e164",
+			"b.phone_national",
 			"mobile",
+			"b.mobile_e164",
+			"b.mobile_national",
+			"b.email_flagged",
+			"b.phonetic_given_name",
+			"b.phonetic_family_name",
+			"b.display_name_en",
+			"b.display_name_lo",
+			"b.name_languages",
+			"b.whatsapp",
+			"b.line_id",
+			"b.wechat_id",
+			"b.linkedin_url",
+			"b.website_url",
+			"a.address_street",
+			"a.address_locality",
+			"a.address_region",
+			"a.address_postal_code",
+			"a.address_country",
+			"a.address_latitude",
+			"a.address_longitude",
+			"b.guest_id",
 			"status",
+			"b.archived_at",
+			"b.archived_from",
+			"b.photo_key",
+			"b.employee_changed_at",
 			"remark",
 			"created_at",
 			"updated_at",
 			"created_by",
 			"updated_by",
 		).
-		From("dbo.v_business_card").
+		From(cardSource).
+		JoinClause(cardColumns).
+		JoinClause(addressColumns).
 		Where(pred, args...).
-		OrderBy("created_at DESC").
-		PlaceholderFormat(sq.AtP).
-		MustSql()
+		OrderBy(in.orderBy()...).
+		PlaceholderFormat(sq.AtP)
+	if limit > 0 && in.Page > 0 {
+		b = b.SuffixExpr(pager.Fetch(in.Page, limit))
+	}
+	q, args := b.MustSql()
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	rows, err := utils.QueryContext(ctx, db, q, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
 
-	cards := make([]*Card, 0)
 	for rows.Next() {
 		var c Card
+		var publicID sql.NullString
+		var guestID sql.NullInt64
+		var archivedAt sql.NullTime
+		var archivedFrom sql.NullString
+		var photoKey sql.NullString
+		var employeeChangedAt sql.NullTime
+		var nameLanguages string
+		var street, locality, region, postalCode, country sql.NullString
+		var latitude, longitude sql.NullFloat64
 		if err := rows.Scan(
 			&c.ID,
+			&publicID,
 			&c.EmployeeID,
 			&c.DepartmentID,
 			&c.PositionID,
@@ -142,30 +341,128 @@ func listCards(ctx context.Context, db *sql.DB, in *CardQuery) ([]*Card, error)
 			&c.DepartmentName,
 			&c.PositionName,
 			&c.CompanyName,
-			&c.Email,
-			&c.PhoneNumber,
-			&c.MobileNumber,
+			(*pii.Text)(&c.Email),
+			(*pii.Text)(&c.PhoneNumber),
+			(*pii.Text)(&c.PhoneE164),
+			(*pii.Text)(&c.PhoneNational),
+			(*pii.Text)(&c.MobileNumber),
+			(*pii.Text)(&c.MobileE164),
+			(*pii.Text)(&c.MobileNational),
+			&c.EmailFlagged,
+			&c.PhoneticGivenName,
+			&c.PhoneticFamilyName,
+			&c.DisplayNameEn,
+			&c.DisplayNameLo,
+			&nameLanguages,
+			(*pii.Text)(&c.SocialLinks.WhatsApp),
+			&c.SocialLinks.Line,
+			&c.SocialLinks.WeChat,
+			&c.SocialLinks.LinkedIn,
+			&c.SocialLinks.Website,
+			&street,
+			&locality,
+			&region,
+			&postalCode,
+			&country,
+			&latitude,
+			&longitude,
+			&guestID,
 			&c.Status,
+			&archivedAt,
+			&archivedFrom,
+			&photoKey,
+			&employeeChangedAt,
 			&c.Remark,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 			&c.createdBy,
 			&c.updatedBy,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		c.PublicID = publicID.String
+		c.GuestID = guestID.Int64
+		if archivedAt.Valid {
+			c.ArchivedAt = &archivedAt.Time
+		}
+		if employeeChangedAt.Valid {
+			c.EmployeeChangedAt = &employeeChangedAt.Time
+		}
+		c.archivedFrom = statusValues[archivedFrom.String]
+		c.setPhoto(photoKey.String)
+		c.fillPhoneFormats()
+		c.fillNames(nameLanguages)
+		if street.Valid {
+			c.Address = &address.Address{
+				CompanyID:  c.CompanyID,
+				Street:     street.String,
+				Locality:   locality.String,
+				Region:     region.String,
+				PostalCode: postalCode.String,
+				Country:    country.String,
+			}
+			if latitude.Valid && longitude.Valid {
+				c.Address.Latitude = &latitude.Float64
+				c.Address.Longitude = &longitude.Float64
+			}
+		}
+		if err := fn(&c); err != nil {
+			return err
 		}
-		cards = append(cards, &c)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+		return fmt.Errorf("failed to iterate rows: %w", err)
 	}
 
-	return cards, nil
+	return nil
+}
+
+// getCompanyAddress returns the office address of a company as iterCards
+// attaches it to its cards, nil if it has none.
+func getCompanyAddress(ctx context.Context, db *sql.DB, companyID int64) (*address.Address, error) {
+	q, args := sq.
+		Select(
+			"street",
+			"locality",
+			"region",
+			"postal_code",
+			"country",
+			"latitude",
+			"longitude",
+		).
+		From("dbo.company_address").
+		Where(sq.Eq{"company_id": companyID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	a := &address.Address{CompanyID: companyID}
+	var latitude, longitude sql.NullFloat64
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan(
+		&a.Street,
+		&a.Locality,
+		&a.Region,
+		&a.PostalCode,
+		&a.Country,
+		&latitude,
+		&longitude,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	if latitude.Valid && longitude.Valid {
+		a.Latitude = &latitude.Float64
+		a.Longitude = &longitude.Float64
+	}
+
+	return a, nil
 }
 
 func getCard(ctx context.Context, db *sql.DB, in *CardQuery) (*Card, error) {
 	in.PageSize = 1
-	if in.ID == "" {
+	if in.ID == "" && in.publicID == "" {
 		return nil, ErrCardNotFound
 	}
 
@@ -181,95 +478,1878 @@ func getCard(ctx context.Context, db *sql.DB, in *CardQuery) (*Card, error) {
 	return cards[0], nil
 }
 
-func createCard(ctx context.Context, db *sql.DB, in *Card) error {
-	return utils.WithTx(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
-		q, args := sq.
-			Insert("dbo.business_card").
-			Columns(
-				"id",
-				"employee_id",
-				"position_id",
-				"department_id",
-				"company_id",
-				"display_name",
-				"email",
-				"phone",
-				"mobile",
-				"status",
-				"remark",
-				"created_at",
-				"updated_at",
-				"created_by",
-				"updated_by",
-			).
-			Values(
-				in.ID,
-				in.EmployeeID,
-				in.PositionID,
-				in.DepartmentID,
-				in.CompanyID,
-				in.DisplayName,
-				in.Email,
-				in.PhoneNumber,
-				in.MobileNumber,
-				in.Status,
-				in.Remark,
-				in.CreatedAt,
-				in.UpdatedAt,
-				in.createdBy,
-				in.updatedBy,
-			).
-			PlaceholderFormat(sq.AtP).
-			MustSql()
-
-		if _, err := tx.ExecContext(ctx, q, args...); err != nil {
-			return fmt.Errorf("failed to execute create card: %w", err)
-		}
+// createIdempotencyKey records that the employee created the card with key,
+// unless they already did since since, and reports whether it did. A key
+// that has expired is taken over.
+func createIdempotencyKey(ctx context.Context, tx *sql.Tx, employeeID int64, key, cardID string, since, now time.Time) (bool, error) {
+	q := `
+DELETE FROM dbo.card_idempotency_key WHERE employee_id = @p1 AND idempotency_key = @p2 AND created_at < @p4;
+INSERT INTO dbo.card_idempotency_key (employee_id, idempotency_key, card_id, created_at)
+SELECT @p1, @p2, @p3, @p5
+WHERE NOT EXISTS (
+  SELECT 1 FROM dbo.card_idempotency_key WITH (UPDLOCK, HOLDLOCK) WHERE employee_id = @p1 AND idempotency_key = @p2
+);
+SELECT @@ROWCOUNT;`
 
-		query, args := sq.
-			Update("dbo.tb_employee").
-			Set("phone_number", in.PhoneNumber).
-			Set("mobile_number", in.MobileNumber).
-			Where(
-				sq.Eq{
-					"eid": in.EmployeeID,
-				},
-			).
-			PlaceholderFormat(sq.AtP).
-			MustSql()
-
-		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
-			return fmt.Errorf("failed to execute update employee: %w", err)
-		}
+	var n int64
+	err := tx.QueryRowContext(ctx, q, employeeID, key, cardID, pager.DateTime(since), pager.DateTime(now)).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute create idempotency key: %w", err)
+	}
 
-		return nil
-	})
+	return n > 0, nil
+}
+
+// getIdempotentCardID returns the ID of the card the employee created with
+// key since since.
+func getIdempotentCardID(ctx context.Context, db *sql.DB, employeeID int64, key string, since time.Time) (string, error) {
+	q, args := sq.
+		Select("card_id").
+		From("dbo.card_idempotency_key").
+		Where(sq.Eq{"employee_id": employeeID, "idempotency_key": key}).
+		Where(sq.GtOrEq{"created_at": pager.DateTime(since)}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var id string
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrCardNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return id, nil
+}
+
+// deleteIdempotencyKeys deletes the keys created before before and returns
+// how many it did.
+func deleteIdempotencyKeys(ctx context.Context, db *sql.DB, before time.Time) (int64, error) {
+	q, args := sq.
+		Delete("dbo.card_idempotency_key").
+		Where(sq.Lt{"created_at": pager.DateTime(before)}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := utils.ExecContext(ctx, db, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n, nil
 }
 
-func updateCard(ctx context.Context, db *sql.DB, in *Card) error {
+func createCard(ctx context.Context, tx *sql.Tx, in *Card) error {
 	q, args := sq.
+		Insert("dbo.business_card").
+		Columns(
+			"id",
+			"employee_id",
+			"position_id",
+			"department_id",
+			"company_id",
+			"display_name",
+			"email",
+			"phone",
+			"phone_e164",
+			"phone_national",
+			"mobile",
+			"mobile_e164",
+			"mobile_national",
+			"email_flagged",
+			"phonetic_given_name",
+			"phonetic_family_name",
+			"display_name_en",
+			"display_name_lo",
+			"name_languages",
+			"whatsapp",
+			"line_id",
+			"wechat_id",
+			"linkedin_url",
+			"website_url",
+			"guest_id",
+			"status",
+			"remark",
+			"created_at",
+			"updated_at",
+			"created_by",
+			"updated_by",
+		).
+		Values(
+			in.ID,
+			nullID(in.EmployeeID),
+			nullID(in.PositionID),
+			nullID(in.DepartmentID),
+			in.CompanyID,
+			in.DisplayName,
+			pii.Text(in.Email),
+			pii.Text(in.PhoneNumber),
+			pii.Text(in.PhoneE164),
+			pii.Text(in.PhoneNational),
+			pii.Text(in.MobileNumber),
+			pii.Text(in.MobileE164),
+			pii.Text(in.MobileNational),
+			in.EmailFlagged,
+			in.PhoneticGivenName,
+			in.PhoneticFamilyName,
+			in.DisplayNameEn,
+			in.DisplayNameLo,
+			joinLangs(in.NameLanguages),
+			pii.Text(in.SocialLinks.WhatsApp),
+			in.SocialLinks.Line,
+			in.SocialLinks.WeChat,
+			in.SocialLinks.LinkedIn,
+			in.SocialLinks.Website,
+			nullID(in.GuestID),
+			in.Status,
+			in.Remark,
+			in.CreatedAt,
+			in.UpdatedAt,
+			in.createdBy,
+			in.updatedBy,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create card: %w", err)
+	}
+
+	// Guests have no employee record to keep in step.
+	if in.EmployeeID == 0 {
+		return nil
+	}
+
+	// The employee's contacts are recorded encrypted in dbo.employee_contact,
+	// not in dbo.tb_employee, which other systems read in plain text.
+	query := `
+MERGE dbo.employee_contact AS t
+USING (SELECT @p1 AS employee_id) AS s ON t.employee_id = s.employee_id
+WHEN MATCHED THEN
+  UPDATE SET email = @p2, phone_number = @p3, mobile_number = @p4, updated_at = @p5
+WHEN NOT MATCHED THEN
+  INSERT (employee_id, email, phone_number, mobile_number, updated_at) VALUES (@p1, @p2, @p3, @p4, @p5);`
+
+	if _, err := tx.ExecContext(ctx, query,
+		in.EmployeeID,
+		pii.Text(in.Email),
+		pii.Text(in.PhoneNumber),
+		pii.Text(in.MobileNumber),
+		in.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to execute save employee contact: %w", err)
+	}
+
+	return nil
+}
+
+func updateCard(ctx context.Context, tx *sql.Tx, in *Card) error {
+	b := sq.
 		Update("dbo.business_card").
 		Set("display_name", in.DisplayName).
-		Set("position_id", in.PositionID).
-		Set("department_id", in.DepartmentID).
+		Set("position_id", nullID(in.PositionID)).
+		Set("department_id", nullID(in.DepartmentID)).
 		Set("company_id", in.CompanyID).
-		Set("email", in.Email).
-		Set("phone", in.PhoneNumber).
-		Set("mobile", in.MobileNumber).
+		Set("email", pii.Text(in.Email)).
+		Set("phone", pii.Text(in.PhoneNumber)).
+		Set("phone_e164", pii.Text(in.PhoneE164)).
+		Set("phone_national", pii.Text(in.PhoneNational)).
+		Set("mobile", pii.Text(in.MobileNumber)).
+		Set("mobile_e164", pii.Text(in.MobileE164)).
+		Set("mobile_national", pii.Text(in.MobileNational)).
+		Set("email_flagged", in.EmailFlagged).
+		Set("phonetic_given_name", in.PhoneticGivenName).
+		Set("phonetic_family_name", in.PhoneticFamilyName).
+		Set("display_name_en", in.DisplayNameEn).
+		Set("display_name_lo", in.DisplayNameLo).
+		Set("name_languages", joinLangs(in.NameLanguages)).
+		Set("whatsapp", pii.Text(in.SocialLinks.WhatsApp)).
+		Set("line_id", in.SocialLinks.Line).
+		Set("wechat_id", in.SocialLinks.WeChat).
+		Set("linkedin_url", in.SocialLinks.LinkedIn).
+		Set("website_url", in.SocialLinks.Website).
 		Set("status", in.Status).
+		Set("archived_at", in.ArchivedAt).
+		Set("employee_changed_at", in.EmployeeChangedAt).
+		Set("archived_from", sql.NullString{String: in.archivedFrom.String(), Valid: in.archivedFrom != StatusUnspecified}).
 		Set("remark", in.Remark).
+		Set("public_id", sql.NullString{String: in.PublicID, Valid: in.PublicID != ""}).
 		Set("updated_at", in.UpdatedAt).
 		Set("updated_by", in.updatedBy).
 		Where(
 			sq.Eq{
 				"id": in.ID,
 			}).
+		PlaceholderFormat(sq.AtP)
+
+	// Cards are read without their vCards, which are only written when
+	// the card was rendered to be published. They hold the owner's contact
+	// details, so they are stored encrypted.
+	if len(in.vcf) > 0 {
+		b = b.
+			Set("vcf", pii.Bytes(in.vcf)).
+			Set("vcf_hash", in.vcfHash).
+			Set("vcf_photo", pii.Bytes(in.vcfPhoto)).
+			Set("photo_hash", sql.NullString{String: in.photoHash, Valid: in.photoHash != ""})
+	}
+	q, args := b.MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// joinLangs stores the name languages of a card, e.g. "lo,en".
+func joinLangs(langs []i18n.Lang) string {
+	ss := make([]string, 0, len(langs))
+	for _, l := range langs {
+		ss = append(ss, string(l))
+	}
+	return strings.Join(ss, ",")
+}
+
+// nullID stores an unset ID as NULL, e.g. the employee ID of a guest card.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id > 0}
+}
+
+// getCardVCF returns the vCard of the version the card was last published
+// as, or the one stored on the card when it was published before versions
+// were kept.
+func getCardVCF(ctx context.Context, db *sql.DB, id string) ([]byte, string, error) {
+	q, args := sq.
+		Select(
+			"COALESCE(v.vcf, c.vcf)",
+			"COALESCE(v.vcf_hash, c.vcf_hash)",
+		).
+		From("dbo.business_card AS c").
+		JoinClause(`OUTER APPLY (
+	SELECT TOP 1 vcf, vcf_hash
+	FROM dbo.business_card_version
+	WHERE card_id = c.id AND vcf IS NOT NULL
+	ORDER BY id DESC
+) AS v`).
+		Where(
+			sq.Eq{
+				"c.id": id,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var vcf []byte
+	var hash string
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan((*pii.Bytes)(&vcf), &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrCardNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return vcf, hash, nil
+}
+
+// getCardPhotoVCF returns the download vCard of a card with its owner's
+// photo, or nil if it has none.
+func getCardPhotoVCF(ctx context.Context, db *sql.DB, id string) ([]byte, error) {
+	q, args := sq.
+		Select("vcf_photo").
+		From("dbo.business_card").
+		Where(
+			sq.Eq{
+				"id": id,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var vcf []byte
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan((*pii.Bytes)(&vcf))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return vcf, nil
+}
+
+// listStalePhotoCards lists the published cards whose photo vCard was not
+// rendered from their owner's current photo. Cards with an uploaded photo
+// never are.
+func listStalePhotoCards(ctx context.Context, db *sql.DB) ([]string, error) {
+	q, args := sq.
+		Select("c.id").
+		From("dbo.business_card AS c").
+		InnerJoin("dbo.employee_photo AS p ON p.employee_id = c.employee_id").
+		Where(
+			sq.And{
+				sq.Eq{"c.status": StatusPublished},
+				sq.Eq{"c.deleted_at": nil},
+				sq.Eq{"c.photo_key": nil},
+				sq.Expr("(c.photo_hash IS NULL OR c.photo_hash <> p.hash)"),
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+func updateCardPhoto(ctx context.Context, db *sql.DB, in *Card) error {
+	q, args := sq.
+		Update("dbo.business_card").
+		Set("vcf_photo", pii.Bytes(in.vcfPhoto)).
+		Set("photo_hash", sql.NullString{String: in.photoHash, Valid: in.photoHash != ""}).
+		Where(
+			sq.Eq{
+				"id": in.ID,
+			}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func updateCardPhotoKey(ctx context.Context, db *sql.DB, in *Card) error {
+	q, args := sq.
+		Update("dbo.business_card").
+		Set("photo_key", in.photoKey).
+		Set("updated_at", in.UpdatedAt).
+		Set("updated_by", in.updatedBy).
+		Where(
+			sq.Eq{
+				"id":         in.ID,
+				"deleted_at": nil,
+			}).
 		PlaceholderFormat(sq.AtP).
 		MustSql()
 
-	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
 
 	return nil
 }
+
+func createLead(ctx context.Context, db *sql.DB, in *Lead) error {
+	q, args := sq.
+		Insert("dbo.business_card_lead").
+		Columns(
+			"card_id",
+			"name",
+			"phone",
+			"email",
+			"message",
+			"remote_ip",
+			"user_agent",
+			"created_at",
+		).
+		Values(
+			in.CardID,
+			in.Name,
+			pii.Text(in.Phone),
+			pii.Text(in.Email),
+			in.Message,
+			in.remoteIP,
+			in.userAgent,
+			in.CreatedAt,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&in.ID); err != nil {
+		return fmt.Errorf("failed to execute create lead: %w", err)
+	}
+
+	return nil
+}
+
+// countLeads counts the leads left on the card from remoteIP since the
+// given time.
+func countLeads(ctx context.Context, db *sql.DB, cardID, remoteIP string, since time.Time) (int, error) {
+	q, args := sq.
+		Select("COUNT(*)").
+		From("dbo.business_card_lead").
+		Where(
+			sq.Eq{
+				"card_id":   cardID,
+				"remote_ip": remoteIP,
+			},
+		).
+		Where(sq.GtOrEq{"created_at": pager.DateTime(since)}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var n int
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return n, nil
+}
+
+func listLeads(ctx context.Context, db *sql.DB, in *LeadQuery) ([]*Lead, error) {
+	leads := make([]*Lead, 0)
+	err := iterLeads(ctx, db, in, pager.Size(in.PageSize), func(l *Lead) error {
+		leads = append(leads, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leads, nil
+}
+
+// iterLeads calls fn for every lead on the card, newest first. A limit of
+// 0 returns all of them.
+func iterLeads(ctx context.Context, db *sql.DB, in *LeadQuery, limit uint64, fn func(*Lead) error) error {
+	id := "id"
+	if limit > 0 {
+		id = fmt.Sprintf("TOP %d id", limit)
+	}
+
+	and := sq.And{
+		sq.Eq{"card_id": in.CardID},
+	}
+	if in.leadID > 0 {
+		and = append(and, sq.Eq{"id": in.leadID})
+	}
+	if in.PageToken != "" {
+		cursor, err := pager.DecodeCursor(in.PageToken)
+		if err != nil {
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+		and = append(and, cursor.After("created_at", "id"))
+	}
+
+	q, args := sq.
+		Select(
+			id,
+			"card_id",
+			"name",
+			"phone",
+			"email",
+			"message",
+			"created_at",
+		).
+		From("dbo.business_card_lead").
+		Where(and).
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l Lead
+		if err := rows.Scan(
+			&l.ID,
+			&l.CardID,
+			&l.Name,
+			(*pii.Text)(&l.Phone),
+			(*pii.Text)(&l.Email),
+			&l.Message,
+			&l.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+// deleteCard marks the card deleted and leaves a tombstone for the syncs of
+// its owner.
+func deleteCard(ctx context.Context, tx *sql.Tx, in *Card, by string, at time.Time) error {
+	q, args := sq.
+		Update("dbo.business_card").
+		Set("deleted_at", at).
+		Set("deleted_by", by).
+		Set("updated_at", at).
+		Set("updated_by", by).
+		Where(
+			sq.Eq{
+				"id":         in.ID,
+				"deleted_at": nil,
+			}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrCardNotFound
+	}
+
+	q, args = sq.
+		Insert("dbo.business_card_tombstone").
+		Columns(
+			"card_id",
+			"employee_id",
+			"deleted_at",
+		).
+		Values(
+			in.ID,
+			in.EmployeeID,
+			at,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create tombstone: %w", err)
+	}
+
+	return nil
+}
+
+// listTombstones lists the cards of the employee deleted after since.
+func listTombstones(ctx context.Context, db *sql.DB, employeeID int64, since time.Time) ([]*Tombstone, error) {
+	q, args := sq.
+		Select(
+			"card_id",
+			"deleted_at",
+		).
+		From("dbo.business_card_tombstone").
+		Where(
+			sq.Eq{
+				"employee_id": employeeID,
+			},
+		).
+		Where(sq.Gt{"deleted_at": pager.DateTime(since)}).
+		OrderBy("deleted_at ASC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	tombstones := make([]*Tombstone, 0)
+	for rows.Next() {
+		var t Tombstone
+		if err := rows.Scan(&t.ID, &t.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tombstones = append(tombstones, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return tombstones, nil
+}
+
+func createEventCard(ctx context.Context, tx *sql.Tx, in *EventCard) error {
+	q, args := sq.
+		Insert("dbo.business_card_event").
+		Columns(
+			"id",
+			"public_id",
+			"card_id",
+			"label",
+			"valid_from",
+			"valid_until",
+			"created_at",
+			"created_by",
+		).
+		Values(
+			in.ID,
+			in.PublicID,
+			in.CardID,
+			in.Label,
+			in.ValidFrom,
+			in.ValidUntil,
+			in.CreatedAt,
+			in.createdBy,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create event card: %w", err)
+	}
+
+	return nil
+}
+
+func selectEventCards() sq.SelectBuilder {
+	return sq.
+		Select(
+			"id",
+			"public_id",
+			"card_id",
+			"label",
+			"valid_from",
+			"valid_until",
+			"created_at",
+			"created_by",
+		).
+		From("dbo.business_card_event").
+		PlaceholderFormat(sq.AtP)
+}
+
+func scanEventCard(row interface{ Scan(...any) error }) (*EventCard, error) {
+	var e EventCard
+	err := row.Scan(
+		&e.ID,
+		&e.PublicID,
+		&e.CardID,
+		&e.Label,
+		&e.ValidFrom,
+		&e.ValidUntil,
+		&e.CreatedAt,
+		&e.createdBy,
+	)
+	return &e, err
+}
+
+func listEventCards(ctx context.Context, db *sql.DB, cardID string) ([]*EventCard, error) {
+	q, args := selectEventCards().
+		Where(sq.Eq{"card_id": cardID}).
+		OrderBy("created_at DESC", "id DESC").
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	eventCards := make([]*EventCard, 0)
+	for rows.Next() {
+		e, err := scanEventCard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		eventCards = append(eventCards, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return eventCards, nil
+}
+
+func getEventCardByPublicID(ctx context.Context, db *sql.DB, publicID string) (*EventCard, error) {
+	q, args := selectEventCards().
+		Where(sq.Eq{"public_id": publicID}).
+		MustSql()
+
+	e, err := scanEventCard(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEventCardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return e, nil
+}
+
+func createGuest(ctx context.Context, db *sql.DB, in *Guest) error {
+	q, args := sq.
+		Insert("dbo.guest_person").
+		Columns(
+			"display_name",
+			"email",
+			"company_id",
+			"department_name",
+			"position_name",
+			"sponsor_id",
+			"created_at",
+			"updated_at",
+			"created_by",
+		).
+		Values(
+			in.DisplayName,
+			pii.Text(in.Email),
+			in.CompanyID,
+			in.DepartmentName,
+			in.PositionName,
+			in.SponsorID,
+			in.CreatedAt,
+			in.UpdatedAt,
+			in.createdBy,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&in.ID); err != nil {
+		return fmt.Errorf("failed to execute create guest: %w", err)
+	}
+
+	return nil
+}
+
+func selectGuests(top string) sq.SelectBuilder {
+	return sq.
+		Select(
+			top+"g.id",
+			"g.display_name",
+			"g.email",
+			"g.company_id",
+			"COALESCE(br.BranchName, '')",
+			"g.department_name",
+			"g.position_name",
+			"g.sponsor_id",
+			"g.created_at",
+			"g.updated_at",
+			"g.created_by",
+		).
+		From("dbo.guest_person AS g").
+		LeftJoin("dbo.tb_Branch AS br ON br.BID = g.company_id").
+		PlaceholderFormat(sq.AtP)
+}
+
+func scanGuest(row interface{ Scan(...any) error }) (*Guest, error) {
+	var g Guest
+	err := row.Scan(
+		&g.ID,
+		&g.DisplayName,
+		(*pii.Text)(&g.Email),
+		&g.CompanyID,
+		&g.CompanyName,
+		&g.DepartmentName,
+		&g.PositionName,
+		&g.SponsorID,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+		&g.createdBy,
+	)
+	return &g, err
+}
+
+func getGuest(ctx context.Context, db *sql.DB, id int64) (*Guest, error) {
+	q, args := selectGuests("").
+		Where(sq.Eq{"g.id": id}).
+		MustSql()
+
+	g, err := scanGuest(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGuestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return g, nil
+}
+
+func listGuests(ctx context.Context, db *sql.DB, in *GuestQuery) ([]*Guest, error) {
+	and := sq.And{}
+	if in.DisplayName != "" {
+		and = append(and, sq.Expr("g.display_name LIKE ?", "%"+in.DisplayName+"%"))
+	}
+	if in.PageToken != "" {
+		cursor, err := pager.DecodeCursor(in.PageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+		and = append(and, cursor.After("g.created_at", "g.id"))
+	}
+
+	q, args := selectGuests(fmt.Sprintf("TOP %d ", pager.Size(in.PageSize))).
+		Where(and).
+		OrderBy("g.created_at DESC", "g.id DESC").
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	guests := make([]*Guest, 0)
+	for rows.Next() {
+		g, err := scanGuest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		guests = append(guests, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return guests, nil
+}
+
+func createScan(ctx context.Context, db *sql.DB, in *scan) error {
+	q, args := sq.
+		Insert("dbo.business_card_scan").
+		Columns(
+			"card_id",
+			"event_card_id",
+			"visitor",
+			"device",
+			"os",
+			"scanned_at",
+		).
+		Values(
+			in.cardID,
+			sql.NullString{String: in.eventCardID, Valid: in.eventCardID != ""},
+			in.visitor,
+			in.device,
+			in.os,
+			in.scannedAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create scan: %w", err)
+	}
+
+	return nil
+}
+
+// iterScans calls fn for every scan made in [from, to), oldest first.
+func iterScans(ctx context.Context, db *sql.DB, from, to time.Time, fn func(*ExportedScan) error) error {
+	and := sq.And{
+		sq.Lt{"scanned_at": pager.DateTime(to)},
+	}
+	if !from.IsZero() {
+		and = append(and, sq.GtOrEq{"scanned_at": pager.DateTime(from)})
+	}
+
+	q, args := sq.
+		Select(
+			"card_id",
+			"event_card_id",
+			"visitor",
+			"device",
+			"os",
+			"scanned_at",
+		).
+		From("dbo.business_card_scan").
+		Where(and).
+		OrderBy("scanned_at ASC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sc ExportedScan
+		var eventCardID sql.NullString
+		if err := rows.Scan(
+			&sc.CardID,
+			&eventCardID,
+			&sc.Visitor,
+			&sc.Device,
+			&sc.OS,
+			&sc.ScannedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		sc.EventCardID = eventCardID.String
+		if err := fn(&sc); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+// scanFilter selects the scans of in's date range, restricted to the cards
+// of its company and department.
+func scanFilter(in *ScanQuery) sq.And {
+	and := sq.And{
+		sq.GtOrEq{"s.scanned_at": pager.DateTime(in.start)},
+		sq.Lt{"s.scanned_at": pager.DateTime(in.end)},
+	}
+
+	cards := sq.And{}
+	if in.CompanyID > 0 {
+		cards = append(cards, sq.Eq{"company_id": in.CompanyID})
+	}
+	if in.DepartmentID > 0 {
+		cards = append(cards, sq.Eq{"department_id": in.DepartmentID})
+	}
+	if len(cards) > 0 {
+		pred, args, _ := cards.ToSql()
+		and = append(and, sq.Expr("s.card_id IN (SELECT id FROM "+cardSource+" WHERE "+pred+")", args...))
+	}
+
+	return and
+}
+
+func summarizeScans(ctx context.Context, db *sql.DB, in *ScanQuery) (*ScanSummary, error) {
+	q, args := sq.
+		Select(
+			"COUNT(*)",
+			"COUNT(DISTINCT s.visitor)",
+		).
+		From("dbo.business_card_scan AS s").
+		Where(scanFilter(in)).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var summary ScanSummary
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&summary.Scans, &summary.UniqueVisitors); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	q, args = sq.
+		Select(
+			"s.device",
+			"s.os",
+			"COUNT(*)",
+		).
+		From("dbo.business_card_scan AS s").
+		Where(scanFilter(in)).
+		GroupBy("s.device", "s.os").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	devices := make(map[string]int64)
+	oses := make(map[string]int64)
+	for rows.Next() {
+		var device, os string
+		var n int64
+		if err := rows.Scan(&device, &os, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		devices[device] += n
+		oses[os] += n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	summary.Devices = breakdown(devices)
+	summary.OS = breakdown(oses)
+	return &summary, nil
+}
+
+// breakdown lists counts by name, most scans first.
+func breakdown(counts map[string]int64) []*Breakdown {
+	b := make([]*Breakdown, 0, len(counts))
+	for name, n := range counts {
+		b = append(b, &Breakdown{Name: name, Scans: n})
+	}
+	slices.SortFunc(b, func(x, y *Breakdown) int {
+		return cmp.Or(cmp.Compare(y.Scans, x.Scans), cmp.Compare(x.Name, y.Name))
+	})
+	return b
+}
+
+func countDailyScans(ctx context.Context, db *sql.DB, in *ScanQuery) ([]*DailyScans, error) {
+	// The offset is a number Validate computed, not client input.
+	day := fmt.Sprintf("CAST(DATEADD(minute, %d, s.scanned_at) AS DATE)", in.offset)
+
+	q, args := sq.
+		Select(
+			"CONVERT(CHAR(10), "+day+", 23)",
+			"COUNT(*)",
+			"COUNT(DISTINCT s.visitor)",
+		).
+		From("dbo.business_card_scan AS s").
+		Where(scanFilter(in)).
+		GroupBy(day).
+		OrderBy(day).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]*DailyScans, 0)
+	for rows.Next() {
+		var d DailyScans
+		if err := rows.Scan(&d.Date, &d.Scans, &d.UniqueVisitors); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		days = append(days, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return days, nil
+}
+
+// listTopCards returns the in.Limit most scanned cards of each department.
+func listTopCards(ctx context.Context, db *sql.DB, in *ScanQuery) ([]*DepartmentTopCards, error) {
+	counted, args := sq.
+		Select(
+			"s.card_id",
+			"COUNT(*) AS scans",
+		).
+		From("dbo.business_card_scan AS s").
+		Where(scanFilter(in)).
+		GroupBy("s.card_id").
+		MustSql()
+
+	q, args := sq.
+		Select(
+			"t.department_id",
+			"t.department_name",
+			"t.id",
+			"t.display_name",
+			"t.scans",
+		).
+		FromSelect(
+			sq.
+				Select(
+					"department_id",
+					"department_name",
+					"id",
+					"display_name",
+					"c.scans",
+					"ROW_NUMBER() OVER (PARTITION BY department_id ORDER BY c.scans DESC, id) AS rank",
+				).
+				From(cardSource).
+				JoinClause("INNER JOIN ("+counted+") AS c ON c.card_id = v_business_card.id", args...),
+			"t",
+		).
+		Where(sq.LtOrEq{"t.rank": in.Limit}).
+		OrderBy("t.department_id", "t.rank").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	departments := make([]*DepartmentTopCards, 0)
+	var dept *DepartmentTopCards
+	for rows.Next() {
+		var deptID int64
+		var deptName string
+		var c TopCard
+		if err := rows.Scan(&deptID, &deptName, &c.CardID, &c.DisplayName, &c.Scans); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if dept == nil || dept.DepartmentID != deptID {
+			dept = &DepartmentTopCards{DepartmentID: deptID, DepartmentName: deptName}
+			if deptID == 0 {
+				dept.DepartmentName = ""
+			}
+			departments = append(departments, dept)
+		}
+		dept.Cards = append(dept.Cards, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return departments, nil
+}
+
+func createExperiment(ctx context.Context, db *sql.DB, in *Experiment) error {
+	variants, err := json.Marshal(in.Variants)
+	if err != nil {
+		return fmt.Errorf("failed to marshal variants: %w", err)
+	}
+
+	q, args := sq.
+		Insert("dbo.landing_experiment").
+		Columns(
+			"id",
+			"name",
+			"card_id",
+			"company_id",
+			"variants",
+			"created_at",
+			"created_by",
+		).
+		Values(
+			in.ID,
+			in.Name,
+			sql.NullString{String: in.CardID, Valid: in.CardID != ""},
+			nullID(in.CompanyID),
+			string(variants),
+			in.CreatedAt,
+			in.createdBy,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create experiment: %w", err)
+	}
+
+	return nil
+}
+
+func selectExperiments() sq.SelectBuilder {
+	return sq.
+		Select(
+			"id",
+			"name",
+			"card_id",
+			"company_id",
+			"variants",
+			"created_at",
+			"ended_at",
+			"created_by",
+		).
+		From("dbo.landing_experiment").
+		PlaceholderFormat(sq.AtP)
+}
+
+func scanExperiment(row interface{ Scan(...any) error }) (*Experiment, error) {
+	var e Experiment
+	var cardID sql.NullString
+	var companyID sql.NullInt64
+	var variants string
+	var endedAt sql.NullTime
+	if err := row.Scan(
+		&e.ID,
+		&e.Name,
+		&cardID,
+		&companyID,
+		&variants,
+		&e.CreatedAt,
+		&endedAt,
+		&e.createdBy,
+	); err != nil {
+		return nil, err
+	}
+
+	e.CardID = cardID.String
+	e.CompanyID = companyID.Int64
+	if endedAt.Valid {
+		e.EndedAt = &endedAt.Time
+	}
+	if err := json.Unmarshal([]byte(variants), &e.Variants); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal variants: %w", err)
+	}
+
+	return &e, nil
+}
+
+func getExperiment(ctx context.Context, db *sql.DB, id string) (*Experiment, error) {
+	q, args := selectExperiments().
+		Where(sq.Eq{"id": id}).
+		MustSql()
+
+	e, err := scanExperiment(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExperimentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return e, nil
+}
+
+// getRunningExperiment returns the experiment running on a card's landing
+// page: the card's own, else its company's. An exact lookup returns only
+// the experiment of the scope given, a card or a company.
+func getRunningExperiment(ctx context.Context, db *sql.DB, cardID string, companyID int64, exact bool) (*Experiment, error) {
+	scope := sq.Or{}
+	switch {
+	case exact && cardID != "":
+		scope = append(scope, sq.Eq{"card_id": cardID})
+	case exact:
+		scope = append(scope, sq.Eq{"card_id": nil, "company_id": companyID})
+	default:
+		scope = append(scope,
+			sq.Eq{"card_id": cardID},
+			sq.Eq{"card_id": nil, "company_id": companyID},
+		)
+	}
+
+	q, args := selectExperiments().
+		Where(sq.And{sq.Eq{"ended_at": nil}, scope}).
+		OrderBy("CASE WHEN card_id IS NULL THEN 1 ELSE 0 END", "created_at DESC").
+		MustSql()
+
+	e, err := scanExperiment(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExperimentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return e, nil
+}
+
+func listExperiments(ctx context.Context, db *sql.DB) ([]*Experiment, error) {
+	q, args := selectExperiments().
+		OrderBy("created_at DESC", "id DESC").
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	experiments := make([]*Experiment, 0)
+	for rows.Next() {
+		e, err := scanExperiment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		experiments = append(experiments, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return experiments, nil
+}
+
+func endExperiment(ctx context.Context, db *sql.DB, id string, at time.Time) error {
+	q, args := sq.
+		Update("dbo.landing_experiment").
+		Set("ended_at", at).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func createLandingEvent(ctx context.Context, db *sql.DB, in *landingEvent) error {
+	q, args := sq.
+		Insert("dbo.landing_event").
+		Columns(
+			"experiment_id",
+			"layout",
+			"visitor",
+			"kind",
+			"occurred_at",
+		).
+		Values(
+			in.experimentID,
+			in.layout,
+			in.visitor,
+			in.kind,
+			in.occurredAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create landing event: %w", err)
+	}
+
+	return nil
+}
+
+// countLandingEvents returns the views, visitors and converted visitors of
+// each layout of an experiment.
+func countLandingEvents(ctx context.Context, db *sql.DB, experimentID string) (map[string]*VariantResult, error) {
+	q, args := sq.
+		Select(
+			"layout",
+			fmt.Sprintf("SUM(CASE WHEN kind = '%s' THEN 1 ELSE 0 END)", landingView),
+			"COUNT(DISTINCT visitor)",
+			fmt.Sprintf("COUNT(DISTINCT CASE WHEN kind = '%s' THEN visitor END)", landingConversion),
+		).
+		From("dbo.landing_event").
+		Where(sq.Eq{"experiment_id": experimentID}).
+		GroupBy("layout").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	results := make(map[string]*VariantResult)
+	for rows.Next() {
+		var r VariantResult
+		if err := rows.Scan(&r.Layout, &r.Views, &r.Visitors, &r.Conversions); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		results[r.Layout] = &r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// addCardHit adds h to the counters of its card and hour.
+func addCardHit(ctx context.Context, db *sql.DB, h *hit) error {
+	q := `
+MERGE dbo.business_card_view WITH (HOLDLOCK) AS t
+USING (SELECT @p1 AS card_id, @p2 AS hour) AS s ON t.card_id = s.card_id AND t.hour = s.hour
+WHEN MATCHED THEN
+  UPDATE SET views = t.views + @p3, downloads = t.downloads + @p4, last_scanned_at = @p5
+WHEN NOT MATCHED THEN
+  INSERT (card_id, hour, views, downloads, last_scanned_at) VALUES (@p1, @p2, @p3, @p4, @p5);`
+
+	hour := h.at.UTC().Truncate(time.Hour)
+	if _, err := utils.ExecContext(ctx, db, q, h.cardID, pager.DateTime(hour), h.views, h.downloads, h.at); err != nil {
+		return fmt.Errorf("failed to execute add card hit: %w", err)
+	}
+
+	return nil
+}
+
+func countDailyCardHits(ctx context.Context, db *sql.DB, cardID string, in *ScanQuery) ([]*DailyCardStats, error) {
+	// The offset is a number Validate computed, not client input.
+	day := fmt.Sprintf("CAST(DATEADD(minute, %d, hour) AS DATE)", in.offset)
+
+	q, args := sq.
+		Select(
+			"CONVERT(CHAR(10), "+day+", 23)",
+			"SUM(views)",
+			"SUM(downloads)",
+		).
+		From("dbo.business_card_view").
+		Where(
+			sq.And{
+				sq.Eq{"card_id": cardID},
+				sq.GtOrEq{"hour": pager.DateTime(in.start)},
+				sq.Lt{"hour": pager.DateTime(in.end)},
+			},
+		).
+		GroupBy(day).
+		OrderBy(day).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]*DailyCardStats, 0)
+	for rows.Next() {
+		var d DailyCardStats
+		if err := rows.Scan(&d.Date, &d.Views, &d.Downloads); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		days = append(days, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return days, nil
+}
+
+// getCardLastHit returns when the card was last viewed or downloaded, nil
+// if it never was.
+func getCardLastHit(ctx context.Context, db *sql.DB, cardID string) (*time.Time, error) {
+	q, args := sq.
+		Select("MAX(last_scanned_at)").
+		From("dbo.business_card_view").
+		Where(sq.Eq{"card_id": cardID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var last sql.NullTime
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&last); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+
+	return &last.Time, nil
+}
+
+// createPrintRequest creates in unless the card has an open request, and
+// reports whether it did.
+func createPrintRequest(ctx context.Context, db *sql.DB, in *PrintRequest) (bool, error) {
+	q := `
+INSERT INTO dbo.business_card_print_request (card_id, quantity, delivery_office, status, created_by, created_at, updated_by, updated_at)
+SELECT @p1, @p2, @p3, @p4, @p5, @p6, @p5, @p6
+WHERE NOT EXISTS (
+  SELECT 1 FROM dbo.business_card_print_request WITH (UPDLOCK, HOLDLOCK)
+  WHERE card_id = @p1 AND status IN ('REQUESTED', 'ORDERED')
+);
+SELECT CAST(SCOPE_IDENTITY() AS BIGINT);`
+
+	var id sql.NullInt64
+	err := utils.QueryRowContext(ctx, db, q,
+		in.CardID,
+		in.Quantity,
+		in.DeliveryOffice,
+		string(in.Status),
+		in.CreatedBy,
+		in.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute create print request: %w", err)
+	}
+	if !id.Valid {
+		return false, nil
+	}
+
+	in.ID = id.Int64
+	return true, nil
+}
+
+func selectPrintRequests(top string) sq.SelectBuilder {
+	return sq.
+		Select(
+			top+"id",
+			"card_id",
+			"quantity",
+			"delivery_office",
+			"status",
+			"created_by",
+			"created_at",
+			"updated_by",
+			"updated_at",
+			"ordered_at",
+			"delivered_at",
+		).
+		From("dbo.business_card_print_request").
+		PlaceholderFormat(sq.AtP)
+}
+
+func scanPrintRequest(row interface{ Scan(...any) error }) (*PrintRequest, error) {
+	var p PrintRequest
+	var orderedAt, deliveredAt sql.NullTime
+	err := row.Scan(
+		&p.ID,
+		&p.CardID,
+		&p.Quantity,
+		&p.DeliveryOffice,
+		&p.Status,
+		&p.CreatedBy,
+		&p.CreatedAt,
+		&p.UpdatedBy,
+		&p.UpdatedAt,
+		&orderedAt,
+		&deliveredAt,
+	)
+	if orderedAt.Valid {
+		p.OrderedAt = &orderedAt.Time
+	}
+	if deliveredAt.Valid {
+		p.DeliveredAt = &deliveredAt.Time
+	}
+	return &p, err
+}
+
+func getPrintRequest(ctx context.Context, db *sql.DB, id int64) (*PrintRequest, error) {
+	q, args := selectPrintRequests("").
+		Where(sq.Eq{"id": id}).
+		MustSql()
+
+	p, err := scanPrintRequest(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPrintRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return p, nil
+}
+
+// getOpenPrintRequest returns the request of the card that is not delivered
+// yet.
+func getOpenPrintRequest(ctx context.Context, db *sql.DB, cardID string) (*PrintRequest, error) {
+	q, args := selectPrintRequests("TOP 1 ").
+		Where(sq.Eq{
+			"card_id": cardID,
+			"status":  []string{string(PrintRequested), string(PrintOrdered)},
+		}).
+		OrderBy("created_at DESC", "id DESC").
+		MustSql()
+
+	p, err := scanPrintRequest(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPrintRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return p, nil
+}
+
+func listPrintRequests(ctx context.Context, db *sql.DB, in *PrintRequestQuery) ([]*PrintRequest, error) {
+	and := sq.And{}
+	if in.Status != "" {
+		and = append(and, sq.Eq{"status": in.Status})
+	}
+	if in.CardID != "" {
+		and = append(and, sq.Eq{"card_id": in.CardID})
+	}
+	if in.DeliveryOffice != "" {
+		and = append(and, sq.Expr("delivery_office LIKE ?", "%"+in.DeliveryOffice+"%"))
+	}
+	if in.PageToken != "" {
+		cursor, err := pager.DecodeCursor(in.PageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+		and = append(and, cursor.After("created_at", "id"))
+	}
+
+	q, args := selectPrintRequests(fmt.Sprintf("TOP %d ", pager.Size(in.PageSize))).
+		Where(and).
+		OrderBy("created_at DESC", "id DESC").
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	requests := make([]*PrintRequest, 0)
+	for rows.Next() {
+		p, err := scanPrintRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		requests = append(requests, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return requests, nil
+}
+
+// updatePrintStatus moves the request from one status to the other,
+// stamping when it was ordered or delivered, and reports whether it was
+// in the from status.
+func updatePrintStatus(ctx context.Context, db *sql.DB, id int64, from, to PrintStatus, by string, at time.Time) (bool, error) {
+	b := sq.
+		Update("dbo.business_card_print_request").
+		Set("status", string(to)).
+		Set("updated_by", by).
+		Set("updated_at", at).
+		Where(sq.Eq{
+			"id":     id,
+			"status": string(from),
+		}).
+		PlaceholderFormat(sq.AtP)
+
+	switch to {
+	case PrintOrdered:
+		b = b.Set("ordered_at", at)
+	case PrintDelivered:
+		b = b.Set("delivered_at", at)
+	}
+
+	q, args := b.MustSql()
+	res, err := utils.ExecContext(ctx, db, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+var errVersionNotFound = errors.New("card version not found")
+
+// createCardVersion stores what card shows as it is published, with the
+// vCard it is published with. Both hold the owner's contact details, so
+// they are stored encrypted.
+func createCardVersion(ctx context.Context, tx *sql.Tx, card *Card) error {
+	data, err := encodeVersion(card)
+	if err != nil {
+		return err
+	}
+
+	q, args := sq.
+		Insert("dbo.business_card_version").
+		Columns(
+			"card_id",
+			"employee_id",
+			"data",
+			"vcf",
+			"vcf_hash",
+			"created_by",
+			"created_at",
+		).
+		Values(
+			card.ID,
+			nullID(card.EmployeeID),
+			pii.Text(data),
+			pii.Bytes(card.vcf),
+			card.vcfHash,
+			card.updatedBy,
+			card.UpdatedAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create card version: %w", err)
+	}
+
+	return nil
+}
+
+// getLastCardVersion returns the version of the card published last or,
+// when it never was, the one of the employee's cards published last.
+func getLastCardVersion(ctx context.Context, db *sql.DB, cardID string, employeeID int64) (*publishedVersion, error) {
+	of := sq.Or{sq.Eq{"card_id": cardID}}
+	if employeeID > 0 {
+		of = append(of, sq.Eq{"employee_id": employeeID})
+	}
+
+	q, args := sq.
+		Select(
+			"TOP 1 card_id",
+			"data",
+			"created_at",
+		).
+		From("dbo.business_card_version").
+		Where(of).
+		OrderByClause("CASE WHEN card_id = ? THEN 0 ELSE 1 END", cardID).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	return scanCardVersion(utils.QueryRowContext(ctx, db, q, args...))
+}
+
+func scanCardVersion(row *utils.Row) (*publishedVersion, error) {
+	var v publishedVersion
+	var data string
+	err := row.Scan(
+		&v.cardID,
+		(*pii.Text)(&data),
+		&v.createdAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	v.version = new(cardVersion)
+	if err := json.Unmarshal([]byte(data), v.version); err != nil {
+		return nil, fmt.Errorf("failed to decode card version: %w", err)
+	}
+
+	return &v, nil
+}
+
+// getCardVersion returns the version the card was last published as.
+func getCardVersion(ctx context.Context, db *sql.DB, cardID string) (*publishedVersion, error) {
+	q, args := sq.
+		Select(
+			"TOP 1 card_id",
+			"data",
+			"created_at",
+		).
+		From("dbo.business_card_version").
+		Where(
+			sq.Eq{
+				"card_id": cardID,
+			},
+		).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	return scanCardVersion(utils.QueryRowContext(ctx, db, q, args...))
+}
+
+func createShareLink(ctx context.Context, db *sql.DB, in *ShareLink) error {
+	q, args := sq.
+		Insert("dbo.business_card_share_link").
+		Columns(
+			"id",
+			"card_id",
+			"expires_at",
+			"created_at",
+			"created_by",
+		).
+		Values(
+			in.ID,
+			in.CardID,
+			in.ExpiresAt,
+			in.CreatedAt,
+			in.createdBy,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create share link: %w", err)
+	}
+
+	return nil
+}
+
+func selectShareLinks() sq.SelectBuilder {
+	return sq.
+		Select(
+			"id",
+			"card_id",
+			"expires_at",
+			"created_at",
+			"created_by",
+			"revoked_at",
+			"COALESCE(revoked_by, '')",
+		).
+		From("dbo.business_card_share_link").
+		PlaceholderFormat(sq.AtP)
+}
+
+func scanShareLink(row interface{ Scan(...any) error }) (*ShareLink, error) {
+	var l ShareLink
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&l.ID,
+		&l.CardID,
+		&l.ExpiresAt,
+		&l.CreatedAt,
+		&l.createdBy,
+		&revokedAt,
+		&l.revokedBy,
+	)
+	if revokedAt.Valid {
+		l.RevokedAt = &revokedAt.Time
+	}
+	return &l, err
+}
+
+func listShareLinks(ctx context.Context, db *sql.DB, cardID string) ([]*ShareLink, error) {
+	q, args := selectShareLinks().
+		Where(sq.Eq{"card_id": cardID}).
+		OrderBy("created_at DESC", "id DESC").
+		MustSql()
+
+	rows, err := utils.QueryContext(ctx, db, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	links := make([]*ShareLink, 0)
+	for rows.Next() {
+		l, err := scanShareLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return links, nil
+}
+
+func getShareLink(ctx context.Context, db *sql.DB, id string) (*ShareLink, error) {
+	q, args := selectShareLinks().
+		Where(sq.Eq{"id": id}).
+		MustSql()
+
+	l, err := scanShareLink(utils.QueryRowContext(ctx, db, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return l, nil
+}
+
+func revokeShareLink(ctx context.Context, db *sql.DB, in *ShareLink) error {
+	q, args := sq.
+		Update("dbo.business_card_share_link").
+		Set("revoked_at", in.RevokedAt).
+		Set("revoked_by", in.revokedBy).
+		Where(
+			sq.Eq{
+				"id":         in.ID,
+				"revoked_at": nil,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := utils.ExecContext(ctx, db, q, args...); err != nil {
+		return fmt.Errorf("failed to execute revoke share link: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package card
+
+import (
+	"context"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"go.uber.org/zap"
+)
+
+// hit is one use of a published card by a visitor: a view of its page or a
+// download of its vCard. Hits are counted per card and hour, in UTC, so the
+// counts can be told by day in any timezone with a whole-hour offset.
+type hit struct {
+	cardID    string
+	views     int
+	downloads int
+	at        time.Time
+}
+
+// countView counts a view of the card's page.
+func (s *Service) countView(ctx context.Context, zlog *zap.Logger, cardID, userAgent string) {
+	s.countHit(ctx, zlog, userAgent, &hit{cardID: cardID, views: 1, at: time.Now()})
+}
+
+// countDownload counts a download of the card's vCard.
+func (s *Service) countDownload(ctx context.Context, zlog *zap.Logger, cardID, userAgent string) {
+	s.countHit(ctx, zlog, userAgent, &hit{cardID: cardID, downloads: 1, at: time.Now()})
+}
+
+// countHit adds h to the card's counters. Like scans, hits by bots or made
+// while the database is down are not counted, and a failure is logged and
+// otherwise ignored.
+func (s *Service) countHit(ctx context.Context, zlog *zap.Logger, userAgent string, h *hit) {
+	if device, _ := classifyAgent(userAgent); device == DeviceBot || s.health.ReadOnly() {
+		return
+	}
+	if err := addCardHit(ctx, s.db, h); err != nil {
+		zlog.Warn("failed to count card hit", zap.Error(err))
+	}
+}
+
+type CardStatsQuery struct {
+	// CardID is the internal ID of the caller's card.
+	CardID string `json:"-" param:"id"`
+
+	// From and To are the first and last day of the series, inclusive, as
+	// YYYY-MM-DD in the caller's timezone. Default: the last 30 days.
+	From string `json:"from" query:"from"`
+	To   string `json:"to" query:"to"`
+}
+
+type DailyCardStats struct {
+	Date      string `json:"date"`
+	Views     int64  `json:"views"`
+	Downloads int64  `json:"downloads"`
+}
+
+type CardStats struct {
+	CardID string `json:"cardId"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+
+	// Views and Downloads are the totals of the date range.
+	Views     int64 `json:"views"`
+	Downloads int64 `json:"downloads"`
+
+	// LastScannedAt is when the card was last viewed or downloaded, at any
+	// time, nil if it never was.
+	LastScannedAt *time.Time `json:"lastScannedAt"`
+
+	Days []*DailyCardStats `json:"days"`
+}
+
+// GetMyCardStats returns how often one of the caller's cards was viewed and
+// downloaded by visitors, in total and for every day in a date range, days
+// without any included.
+func (s *Service) GetMyCardStats(ctx context.Context, in *CardStatsQuery) (*CardStats, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetMyCardStats"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := s.checkLeadOwner(ctx, in.CardID); err != nil {
+		return nil, err
+	}
+
+	// The date range is that of the scan reports.
+	loc := tz.FromContext(ctx)
+	q := &ScanQuery{From: in.From, To: in.To}
+	if err := q.Validate(loc); err != nil {
+		return nil, err
+	}
+
+	counted, err := countDailyCardHits(ctx, s.db, in.CardID, q)
+	if err != nil {
+		zlog.Error("failed to count daily card hits", zap.Error(err))
+		return nil, err
+	}
+
+	stats := &CardStats{
+		CardID: in.CardID,
+		From:   q.From,
+		To:     q.To,
+		Days:   make([]*DailyCardStats, 0),
+	}
+
+	byDate := make(map[string]*DailyCardStats, len(counted))
+	for _, d := range counted {
+		byDate[d.Date] = d
+		stats.Views += d.Views
+		stats.Downloads += d.Downloads
+	}
+	for d := q.start.In(loc); d.Before(q.end); d = d.AddDate(0, 0, 1) {
+		date := d.Format(scanDate)
+		if day, ok := byDate[date]; ok {
+			stats.Days = append(stats.Days, day)
+			continue
+		}
+		stats.Days = append(stats.Days, &DailyCardStats{Date: date})
+	}
+
+	last, err := getCardLastHit(ctx, s.db, in.CardID)
+	if err != nil {
+		zlog.Error("failed to get card last hit", zap.Error(err))
+		return nil, err
+	}
+	stats.LastScannedAt = utcTime(last)
+
+	return stats, nil
+}
@@ -0,0 +1,101 @@
+package card
+
+import (
+	"context"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// Tombstone records that a card was deleted, so clients holding an
+// offline copy drop it.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type SyncReq struct {
+	// UpdatedAfter is the sync token of the previous sync. Empty syncs
+	// everything.
+	UpdatedAfter string `json:"updatedAfter" query:"updatedAfter"`
+	PageSize     uint64 `json:"pageSize" query:"pageSize"`
+}
+
+type SyncResult struct {
+	Cards      []*Card      `json:"businessCards"`
+	Tombstones []*Tombstone `json:"tombstones"`
+
+	// SyncToken is passed as updatedAfter on the next sync.
+	SyncToken string `json:"syncToken"`
+
+	// HasMore is set when changes did not fit in the page; sync again
+	// with the new token right away.
+	HasMore bool `json:"hasMore"`
+}
+
+// SyncMyBusinessCards returns the caller's cards changed since the sync
+// token, oldest change first, and the cards deleted since.
+func (s *Service) SyncMyBusinessCards(ctx context.Context, in *SyncReq) (*SyncResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SyncMyBusinessCards"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	since := &pager.Cursor{}
+	if in.UpdatedAfter != "" {
+		c, err := pager.DecodeCursor(in.UpdatedAfter)
+		if err != nil {
+			return nil, i18n.Error(codes.InvalidArgument, i18n.InvalidSyncToken)
+		}
+		since = c
+	}
+
+	size := pager.Size(in.PageSize)
+	cards, err := listCards(ctx, s.db, &CardQuery{
+		EmployeeID: claims.ID,
+		PageSize:   size,
+		since:      since,
+	})
+	if err != nil {
+		zlog.Error("failed to list cards", zap.Error(err))
+		return nil, err
+	}
+
+	tombstones, err := listTombstones(ctx, s.db, claims.ID, since.Time)
+	if err != nil {
+		zlog.Error("failed to list tombstones", zap.Error(err))
+		return nil, err
+	}
+
+	next := *since
+	if l := len(cards); l > 0 {
+		next = pager.Cursor{
+			ID:   cards[l-1].ID,
+			Time: cards[l-1].UpdatedAt,
+		}
+	}
+	// Tombstones are not paged, so the token only moves past them once
+	// every card change before them was returned.
+	if len(cards) < int(size) {
+		for _, t := range tombstones {
+			if t.DeletedAt.After(next.Time) {
+				next = pager.Cursor{Time: t.DeletedAt}
+			}
+		}
+	}
+
+	return &SyncResult{
+		Cards:      s.shapeCards(ctx, cards, false),
+		Tombstones: tombstones,
+		SyncToken:  pager.EncodeCursor(&next),
+		HasMore:    len(cards) == int(size),
+	}, nil
+}
@@ -14,6 +14,7 @@ const (
 	StatusApproved
 	StatusRejected
 	StatusPublished
+	StatusArchived
 )
 
 var statusNames = map[status]string{
@@ -22,6 +23,7 @@ var statusNames = map[status]string{
 	StatusApproved:    "APPROVED",
 	StatusRejected:    "REJECTED",
 	StatusPublished:   "PUBLISHED",
+	StatusArchived:    "ARCHIVED",
 }
 
 var statusValues = map[string]status{
@@ -29,6 +31,7 @@ var statusValues = map[string]status{
 	"APPROVED":    StatusApproved,
 	"REJECTED":    StatusRejected,
 	"PUBLISHED":   StatusPublished,
+	"ARCHIVED":    StatusArchived,
 	"UNSPECIFIED": StatusUnspecified,
 }
 
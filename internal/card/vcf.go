@@ -2,59 +2,118 @@ package card
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/i18n"
 	vc "github.com/emersion/go-vcard"
 )
 
-func genVCF(card *Card) ([]byte, error) {
-	c := make(vc.Card, 0)
-	c.Set(vc.FieldVersion, &vc.Field{
-		Value: "2.1",
-	})
+// vCard versions served.
+const (
+	VCardV21 = "2.1"
+	VCardV4  = "4.0"
+)
 
-	var displayName string
-	splitDisplayNames := strings.Split(strings.TrimSpace(card.DisplayName), " ")
-	switch ln := len(splitDisplayNames); ln {
-	case 2:
-		displayName = fmt.Sprintf("%s;%s;;;", splitDisplayNames[1], splitDisplayNames[0])
+// vcfOptions controls how a card is encoded into a vCard.
+type vcfOptions struct {
+	// version is the vCard version, VCardV21 when empty.
+	version string
 
-	case 3:
-		displayName = fmt.Sprintf("%s;%s;;%s;", splitDisplayNames[2], splitDisplayNames[1], splitDisplayNames[0])
+	// legacy emits CHARSET and ENCODING=QUOTED-PRINTABLE parameters on text
+	// fields, which older feature phones need to display non-ASCII names.
+	legacy bool
 
-	case 4:
-		displayName = fmt.Sprintf("%s;%s;;%s;", splitDisplayNames[3], splitDisplayNames[2], splitDisplayNames[0])
+	// role is the event role of an event card, e.g. "Speaker – Fintech
+	// Forum 2025".
+	role string
 
-	default:
-		displayName = card.DisplayName
+	// photo is embedded as the contact's picture, in vCard 2.1 only.
+	photo *employee.Photo
+
+	// photoURL links to the contact's picture in vCard 4.0.
+	photoURL string
+
+	// roles are the owner's other published cards, merged in as further
+	// ORG, TITLE and TEL entries after the card's own.
+	roles []*Card
+}
+
+func genVCF(card *Card, opts *vcfOptions) ([]byte, error) {
+	if opts == nil {
+		opts = new(vcfOptions)
 	}
 
-	c.Set(vc.FieldFormattedName, &vc.Field{
-		Value: card.DisplayName,
-	})
+	v4 := opts.version == VCardV4
+
+	version := VCardV21
+	if v4 {
+		version = VCardV4
+	}
 
-	c.Set(vc.FieldName, &vc.Field{
-		Value: displayName,
+	c := make(vc.Card, 0)
+	c.Set(vc.FieldVersion, &vc.Field{
+		Value: version,
 	})
+	if v4 {
+		c.Set(vc.FieldKind, &vc.Field{Value: string(vc.KindIndividual)})
+	}
 
-	tels := make([]*vc.Field, 0)
-	if card.PhoneNumber != "" {
-		tels = append(tels, &vc.Field{
-			Value: card.PhoneNumber,
-			Params: vc.Params{
-				vc.ParamType: []string{vc.TypeWork},
-			},
-		})
+	// Each language of the name gets an FN and N tagged with it, the
+	// display name's first. vCard 4.0 marks them as alternatives of one
+	// another with ALTID.
+	alt := v4 && len(card.NameLanguages) > 1
+	for _, lang := range card.NameLanguages {
+		if name := card.nameIn(lang); name != "" {
+			c.Add(vc.FieldFormattedName, opts.nameField(name, lang, alt))
+			c.Add(vc.FieldName, opts.nameField(structuredName(name), lang, alt))
+		}
+	}
+	// A card without name languages shows its display name untagged.
+	if c.Get(vc.FieldFormattedName) == nil {
+		c.Set(vc.FieldFormattedName, opts.textField(card.DisplayName))
+		c.Set(vc.FieldName, opts.textField(structuredName(card.DisplayName)))
 	}
 
-	if card.MobileNumber != "" {
-		tels = append(tels, &vc.Field{
-			Value: card.MobileNumber,
-			Params: vc.Params{
-				vc.ParamType: []string{vc.TypeCell},
-			},
-		})
+	// iOS reads the X-PHONETIC fields; Android and older phones read SOUND
+	// with the name in N order.
+	if card.PhoneticGivenName != "" || card.PhoneticFamilyName != "" {
+		if card.PhoneticGivenName != "" {
+			c.Set("X-PHONETIC-FIRST-NAME", opts.textField(card.PhoneticGivenName))
+		}
+		if card.PhoneticFamilyName != "" {
+			c.Set("X-PHONETIC-LAST-NAME", opts.textField(card.PhoneticFamilyName))
+		}
+
+		// SOUND is an audio URI in vCard 4.0.
+		if !v4 {
+			sound := opts.textField(fmt.Sprintf("%s;%s;;;", card.PhoneticFamilyName, card.PhoneticGivenName))
+			if sound.Params == nil {
+				sound.Params = make(vc.Params)
+			}
+			sound.Params[vc.ParamType] = []string{"X-IRMC-N"}
+			c.Set(vc.FieldSound, sound)
+		}
+	}
+
+	tels := make([]*vc.Field, 0)
+	seen := make(map[string]bool)
+	for _, r := range append([]*Card{card}, opts.roles...) {
+		if r.PhoneNumber != "" && !seen[r.PhoneNumber] {
+			seen[r.PhoneNumber] = true
+			tels = append(tels, opts.telField(r.PhoneNumber, r.PhoneE164, vc.TypeWork))
+		}
+
+		if r.MobileNumber != "" && !seen[r.MobileNumber] {
+			seen[r.MobileNumber] = true
+			tels = append(tels, opts.telField(r.MobileNumber, r.MobileE164, vc.TypeCell))
+		}
 	}
 	c[vc.FieldTelephone] = tels
 
@@ -62,17 +121,48 @@ func genVCF(card *Card) ([]byte, error) {
 		Value: card.Email,
 	})
 
-	c.Set(vc.FieldOrganization, &vc.Field{
-		Value: fmt.Sprintf("%s;%s;", card.CompanyName, card.DepartmentName),
-	})
+	c.Set(vc.FieldOrganization, opts.textField(fmt.Sprintf("%s;%s;", card.CompanyName, card.DepartmentName)))
 
-	c.Set(vc.FieldTitle, &vc.Field{
-		Value: card.PositionName,
-	})
+	c.Set(vc.FieldTitle, opts.textField(card.PositionName))
+
+	for _, r := range opts.roles {
+		c.Add(vc.FieldOrganization, opts.textField(fmt.Sprintf("%s;%s;", r.CompanyName, r.DepartmentName)))
+		c.Add(vc.FieldTitle, opts.textField(r.PositionName))
+	}
+
+	if opts.role != "" {
+		c.Set(vc.FieldRole, opts.textField(opts.role))
+	}
+
+	if card.Address != nil {
+		opts.addAddress(c, card.Address)
+	}
 
 	c.Set(vc.FieldURL, &vc.Field{
 		Value: "https://krungsrilaos.com",
 	})
+	addSocialLinks(c, &card.SocialLinks)
+
+	// go-vcard escapes the comma of a data: URI, so 4.0 links to the
+	// photo instead of embedding it.
+	if v4 && opts.photoURL != "" {
+		c.Set(vc.FieldPhoto, &vc.Field{
+			Value: opts.photoURL,
+		})
+	}
+	if opts.photo != nil && !v4 {
+		typ := "JPEG"
+		if opts.photo.ContentType == "image/png" {
+			typ = "PNG"
+		}
+		c.Set(vc.FieldPhoto, &vc.Field{
+			Value: base64.StdEncoding.EncodeToString(opts.photo.Data),
+			Params: vc.Params{
+				"ENCODING":   []string{"BASE64"},
+				vc.ParamType: []string{typ},
+			},
+		})
+	}
 
 	buf := new(bytes.Buffer)
 	encoder := vc.NewEncoder(buf)
@@ -82,3 +172,163 @@ func genVCF(card *Card) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// addSocialLinks adds the card's social links: the website as a further
+// URL, the messaging accounts as IMPP fields phones label by their
+// X-SERVICE-TYPE and the LinkedIn profile as an X-SOCIALPROFILE.
+func addSocialLinks(c vc.Card, l *SocialLinks) {
+	if l.Website != "" {
+		c.Add(vc.FieldURL, &vc.Field{
+			Value:  l.Website,
+			Params: vc.Params{vc.ParamType: []string{"home"}},
+		})
+	}
+
+	for _, im := range []struct{ service, scheme, id string }{
+		{"WhatsApp", "whatsapp", l.WhatsApp},
+		{"Line", "line", l.Line},
+		{"WeChat", "weixin", l.WeChat},
+	} {
+		if im.id != "" {
+			c.Add(vc.FieldIMPP, &vc.Field{
+				Value:  im.scheme + ":" + im.id,
+				Params: vc.Params{"X-SERVICE-TYPE": []string{im.service}},
+			})
+		}
+	}
+
+	if l.LinkedIn != "" {
+		c.Add("X-SOCIALPROFILE", &vc.Field{
+			Value:  l.LinkedIn,
+			Params: vc.Params{vc.ParamType: []string{"linkedin"}},
+		})
+	}
+}
+
+// addAddress adds the office address of the card's company as a work ADR
+// and, when it has its coordinates, a GEO. go-vcard escapes the comma of a
+// geo: URI, so 4.0 leaves GEO out.
+func (o *vcfOptions) addAddress(c vc.Card, a *address.Address) {
+	// A semicolon would shift the components that follow it.
+	component := strings.NewReplacer(";", " ").Replace
+	adr := o.textField(fmt.Sprintf(";;%s;%s;%s;%s;%s",
+		component(a.Street),
+		component(a.Locality),
+		component(a.Region),
+		component(a.PostalCode),
+		component(a.Country),
+	))
+	if adr.Params == nil {
+		adr.Params = make(vc.Params)
+	}
+	adr.Params[vc.ParamType] = []string{vc.TypeWork}
+	c.Set(vc.FieldAddress, adr)
+
+	if a.HasGeo() && o.version != VCardV4 {
+		c.Set(vc.FieldGeolocation, &vc.Field{
+			Value: strconv.FormatFloat(*a.Latitude, 'f', -1, 64) + ";" + strconv.FormatFloat(*a.Longitude, 'f', -1, 64),
+		})
+	}
+}
+
+// structuredName returns the N value of a name: family name, given name
+// and, for names of three or four words, the first as a prefix.
+func structuredName(name string) string {
+	words := strings.Split(strings.TrimSpace(name), " ")
+	switch len(words) {
+	case 2:
+		return fmt.Sprintf("%s;%s;;;", words[1], words[0])
+
+	case 3:
+		return fmt.Sprintf("%s;%s;;%s;", words[2], words[1], words[0])
+
+	case 4:
+		return fmt.Sprintf("%s;%s;;%s;", words[3], words[2], words[0])
+
+	default:
+		return name
+	}
+}
+
+// nameField builds an FN or N field of a name in lang. alt marks it as one
+// of the name's alternative languages.
+func (o *vcfOptions) nameField(value string, lang i18n.Lang, alt bool) *vc.Field {
+	f := o.textField(value)
+	if f.Params == nil {
+		f.Params = make(vc.Params)
+	}
+	f.Params["LANGUAGE"] = []string{string(lang)}
+	if alt {
+		f.Params["ALTID"] = []string{"1"}
+	}
+	return f
+}
+
+// textField builds a field holding free text such as names or titles.
+func (o *vcfOptions) textField(value string) *vc.Field {
+	// vCard 4.0 is UTF-8 only and has no CHARSET parameter.
+	if o.version == VCardV4 {
+		return &vc.Field{Value: strings.ToValidUTF8(value, "")}
+	}
+	if !o.legacy {
+		return &vc.Field{Value: value}
+	}
+
+	return &vc.Field{
+		Value: quotedPrintable(value),
+		Params: vc.Params{
+			"CHARSET":  []string{"UTF-8"},
+			"ENCODING": []string{"QUOTED-PRINTABLE"},
+		},
+	}
+}
+
+// telField builds a TEL field of the given type. vCard 4.0 writes the
+// number as a tel: URI, in E.164 when known.
+func (o *vcfOptions) telField(number, e164, typ string) *vc.Field {
+	if o.version != VCardV4 {
+		return &vc.Field{
+			Value: number,
+			Params: vc.Params{
+				vc.ParamType: []string{typ},
+			},
+		}
+	}
+
+	if e164 == "" {
+		e164 = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(number)
+	}
+	return &vc.Field{
+		Value: "tel:" + e164,
+		Params: vc.Params{
+			vc.ParamValue: []string{"uri"},
+			vc.ParamType:  []string{typ},
+		},
+	}
+}
+
+// quotedPrintable encodes s as quoted-printable without soft line breaks,
+// keeping the vCard structural separators (';') readable.
+func quotedPrintable(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= ' ' && ch <= '~' && ch != '=' {
+			b.WriteByte(ch)
+			continue
+		}
+		b.WriteByte('=')
+		b.WriteByte(hex[ch>>4])
+		b.WriteByte(hex[ch&0x0f])
+	}
+
+	return b.String()
+}
+
+// vcfHash returns the hex encoded SHA-256 digest of a generated vCard.
+func vcfHash(vcf []byte) string {
+	sum := sha256.Sum256(vcf)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+package card
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+)
+
+// cardVersion is what a card shows, frozen each time it is published. The
+// public endpoints serve the version last published, so that the card
+// being edited again, the draft, changes nothing public until it is
+// approved and published in turn.
+type cardVersion struct {
+	DisplayName        string      `json:"displayName"`
+	DisplayNameEn      string      `json:"displayNameEn"`
+	DisplayNameLo      string      `json:"displayNameLo"`
+	NameLanguages      []i18n.Lang `json:"nameLanguages"`
+	Email              string      `json:"emailAddress"`
+	PhoneNumber        string      `json:"phoneNumber"`
+	MobileNumber       string      `json:"mobileNumber"`
+	PhoneE164          string      `json:"phoneE164"`
+	PhoneNational      string      `json:"phoneNational"`
+	MobileE164         string      `json:"mobileE164"`
+	MobileNational     string      `json:"mobileNational"`
+	PhoneticGivenName  string      `json:"phoneticGivenName"`
+	PhoneticFamilyName string      `json:"phoneticFamilyName"`
+	PositionName       string      `json:"positionName"`
+	DepartmentName     string      `json:"departmentName"`
+	CompanyName        string      `json:"companyName"`
+	SocialLinks        SocialLinks `json:"socialLinks"`
+}
+
+func versionOf(c *Card) *cardVersion {
+	return &cardVersion{
+		DisplayName:        c.DisplayName,
+		DisplayNameEn:      c.DisplayNameEn,
+		DisplayNameLo:      c.DisplayNameLo,
+		NameLanguages:      c.NameLanguages,
+		Email:              c.Email,
+		PhoneNumber:        c.PhoneNumber,
+		MobileNumber:       c.MobileNumber,
+		PhoneE164:          c.PhoneE164,
+		PhoneNational:      c.PhoneNational,
+		MobileE164:         c.MobileE164,
+		MobileNational:     c.MobileNational,
+		PhoneticGivenName:  c.PhoneticGivenName,
+		PhoneticFamilyName: c.PhoneticFamilyName,
+		PositionName:       c.PositionName,
+		DepartmentName:     c.DepartmentName,
+		CompanyName:        c.CompanyName,
+		SocialLinks:        c.SocialLinks,
+	}
+}
+
+// applyTo shows the version on c in place of its draft.
+func (v *cardVersion) applyTo(c *Card) {
+	c.DisplayName = v.DisplayName
+	c.DisplayNameEn = v.DisplayNameEn
+	c.DisplayNameLo = v.DisplayNameLo
+	c.NameLanguages = v.NameLanguages
+	c.Email = v.Email
+	c.PhoneNumber = v.PhoneNumber
+	c.MobileNumber = v.MobileNumber
+	c.PhoneE164 = v.PhoneE164
+	c.PhoneNational = v.PhoneNational
+	c.MobileE164 = v.MobileE164
+	c.MobileNational = v.MobileNational
+	c.PhoneticGivenName = v.PhoneticGivenName
+	c.PhoneticFamilyName = v.PhoneticFamilyName
+	c.PositionName = v.PositionName
+	c.DepartmentName = v.DepartmentName
+	c.CompanyName = v.CompanyName
+	c.SocialLinks = v.SocialLinks
+	c.fillPhoneFormats()
+}
+
+// publishedVersion is a cardVersion as stored.
+type publishedVersion struct {
+	cardID    string
+	version   *cardVersion
+	createdAt time.Time
+}
+
+func encodeVersion(c *Card) (string, error) {
+	b, err := json.Marshal(versionOf(c))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode card version: %w", err)
+	}
+	return string(b), nil
+}
+
+// showPublished replaces the draft of a card edited since it was published
+// with the version last published. Cards never published or archived are
+// reported as not found.
+func showPublished(ctx context.Context, db *sql.DB, card *Card) error {
+	switch card.Status {
+	case StatusPublished:
+		return nil
+	case StatusArchived:
+		return ErrCardNotFound
+	}
+
+	v, err := getCardVersion(ctx, db, card.ID)
+	if errors.Is(err, errVersionNotFound) {
+		return ErrCardNotFound
+	}
+	if err != nil {
+		return err
+	}
+	v.version.applyTo(card)
+	card.Status = StatusPublished
+
+	return nil
+}
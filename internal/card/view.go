@@ -0,0 +1,262 @@
+package card
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/corpmail"
+	"github.com/10664kls/contactqr/internal/phone"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/tracing"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/visibility"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// cardPolicy lists the card fields that only some viewers may see.
+var cardPolicy = visibility.Policy{
+	"remark":            {visibility.RoleOwner, visibility.RoleApprover, visibility.RoleHR},
+	"emailFlagged":      {visibility.RoleOwner, visibility.RoleApprover, visibility.RoleHR},
+	"employeeChangedAt": {visibility.RoleOwner, visibility.RoleApprover, visibility.RoleHR},
+	"createdBy":         {visibility.RoleHR},
+	"updatedBy":         {visibility.RoleHR},
+}
+
+// MarshalJSON encodes the card with only the fields its viewer may see.
+// Timestamps are given in UTC and again in the viewer's display timezone.
+func (c *Card) MarshalJSON() ([]byte, error) {
+	return cardPolicy.Marshal(c.JSONView(), c.viewer)
+}
+
+// JSONView returns the card as MarshalJSON encodes it, before the fields
+// its viewer may not see are removed.
+func (c *Card) JSONView() any {
+	type card Card
+	return &struct {
+		*card
+		CreatedAt         time.Time  `json:"createdAt"`
+		UpdatedAt         time.Time  `json:"updatedAt"`
+		ArchivedAt        *time.Time `json:"archivedAt,omitempty"`
+		EmployeeChangedAt *time.Time `json:"employeeChangedAt,omitempty"`
+		CreatedAtLocal    string     `json:"createdAtLocal"`
+		UpdatedAtLocal    string     `json:"updatedAtLocal"`
+		Timezone          string     `json:"timezone"`
+		CreatedBy         string     `json:"createdBy"`
+		UpdatedBy         string     `json:"updatedBy"`
+	}{
+		card:              (*card)(c),
+		CreatedAt:         c.CreatedAt.UTC(),
+		UpdatedAt:         c.UpdatedAt.UTC(),
+		ArchivedAt:        utcTime(c.ArchivedAt),
+		EmployeeChangedAt: utcTime(c.EmployeeChangedAt),
+		CreatedAtLocal:    tz.Format(c.CreatedAt, c.loc),
+		UpdatedAtLocal:    tz.Format(c.UpdatedAt, c.loc),
+		Timezone:          c.loc.String(),
+		CreatedBy:         c.createdBy,
+		UpdatedBy:         c.updatedBy,
+	}
+}
+
+// utcTime returns t in UTC, nil if t is.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// Proto returns the card as its viewer may see it, like MarshalJSON.
+func (c *Card) Proto() *contactqrPb.BusinessCard {
+	pb := &contactqrPb.BusinessCard{
+		Id:                 c.ID,
+		PublicId:           c.PublicID,
+		EmployeeId:         c.EmployeeID,
+		DepartmentId:       c.DepartmentID,
+		PositionId:         c.PositionID,
+		CompanyId:          c.CompanyID,
+		EmployeeCode:       c.EmployeeCode,
+		DisplayName:        c.DisplayName,
+		DisplayNameEn:      c.DisplayNameEn,
+		DisplayNameLo:      c.DisplayNameLo,
+		EmailAddress:       c.Email,
+		PhoneNumber:        c.PhoneNumber,
+		PhoneE164:          c.PhoneE164,
+		PhoneNational:      c.PhoneNational,
+		MobileNumber:       c.MobileNumber,
+		MobileE164:         c.MobileE164,
+		MobileNational:     c.MobileNational,
+		PhoneDisplay:       c.PhoneDisplay,
+		MobileDisplay:      c.MobileDisplay,
+		PhoneticGivenName:  c.PhoneticGivenName,
+		PhoneticFamilyName: c.PhoneticFamilyName,
+		PositionName:       c.PositionName,
+		DepartmentName:     c.DepartmentName,
+		CompanyName:        c.CompanyName,
+		PhotoUrl:           c.PhotoURL,
+		SocialLinks:        c.SocialLinks.Proto(),
+		Status:             contactqrPb.BusinessCard_Status(c.Status),
+		CreatedAt:          timestamppb.New(c.CreatedAt),
+		UpdatedAt:          timestamppb.New(c.UpdatedAt),
+		CreatedAtLocal:     tz.Format(c.CreatedAt, c.loc),
+		UpdatedAtLocal:     tz.Format(c.UpdatedAt, c.loc),
+		Timezone:           c.loc.String(),
+	}
+	if c.Template != nil {
+		pb.Template = c.Template.Proto()
+	}
+	if c.Address != nil {
+		pb.Address = c.Address.Proto()
+	}
+	for _, l := range c.NameLanguages {
+		pb.NameLanguages = append(pb.NameLanguages, string(l))
+	}
+	if c.GuestID > 0 {
+		pb.GuestId = proto.Int64(c.GuestID)
+	}
+	if c.ArchivedAt != nil {
+		pb.ArchivedAt = timestamppb.New(*c.ArchivedAt)
+	}
+	if cardPolicy.Allows("remark", c.viewer) {
+		pb.Remark = proto.String(c.Remark)
+	}
+	if cardPolicy.Allows("emailFlagged", c.viewer) {
+		pb.EmailFlagged = proto.Bool(c.EmailFlagged)
+	}
+	if c.EmployeeChangedAt != nil && cardPolicy.Allows("employeeChangedAt", c.viewer) {
+		pb.EmployeeChangedAt = timestamppb.New(*c.EmployeeChangedAt)
+	}
+	if cardPolicy.Allows("createdBy", c.viewer) {
+		pb.CreatedBy = proto.String(c.createdBy)
+	}
+	if cardPolicy.Allows("updatedBy", c.viewer) {
+		pb.UpdatedBy = proto.String(c.updatedBy)
+	}
+
+	return pb
+}
+
+// Proto returns the page as a ListBusinessCardsResponse.
+func (r *ListCardsResult) Proto() *contactqrPb.ListBusinessCardsResponse {
+	cards := make([]*contactqrPb.BusinessCard, 0, len(r.Cards))
+	for _, c := range r.Cards {
+		cards = append(cards, c.Proto())
+	}
+
+	res := &contactqrPb.ListBusinessCardsResponse{
+		BusinessCards: cards,
+		NextPageToken: r.NextPageToken,
+	}
+	if r.TotalSize != nil {
+		res.TotalSize = proto.Int32(int32(*r.TotalSize))
+	}
+	return res
+}
+
+// shapeCard returns the card as the caller may see it. HR, the card owner and
+// the approving manager see the contact details in full; anyone else gets the
+// mobile and WhatsApp numbers and personal email masked. Which other fields
+// are shown is decided by cardPolicy, timestamps are shown in the display timezone of ctx,
+// numbers in the display style of the card's company and the card in its
+// company's template.
+func (s *Service) shapeCard(ctx context.Context, c *Card, approver bool) *Card {
+	claims := auth.ClaimsFromContext(ctx)
+
+	shaped := *c
+	shaped.loc = tz.FromContext(ctx)
+	style := s.styles.For(c.CompanyID)
+	shaped.PhoneDisplay = displayNumber(c.PhoneE164, c.PhoneNumber, style)
+	shaped.MobileDisplay = displayNumber(c.MobileE164, c.MobileNumber, style)
+	shaped.Template = s.templates.For(c.CompanyID).Design()
+	switch {
+	case rbac.Can(ctx, rbac.ReadAllCards):
+		shaped.viewer = visibility.RoleHR
+
+	case approver:
+		shaped.viewer = visibility.RoleApprover
+
+	case claims.ID > 0 && c.EmployeeID == claims.ID:
+		shaped.viewer = visibility.RoleOwner
+
+	case claims.ID > 0:
+		shaped.viewer = visibility.RoleEmployee
+
+	default:
+		shaped.viewer = visibility.RoleAnonymous
+	}
+
+	switch shaped.viewer {
+	case visibility.RoleEmployee, visibility.RoleAnonymous:
+		shaped.MobileNumber = maskPhone(c.MobileNumber)
+		shaped.MobileE164 = maskPhone(c.MobileE164)
+		shaped.MobileNational = maskPhone(c.MobileNational)
+		shaped.MobileDisplay = maskPhone(shaped.MobileDisplay)
+		shaped.SocialLinks.WhatsApp = maskPhone(c.SocialLinks.WhatsApp)
+		if corpmail.IsPersonal(c.Email) {
+			shaped.Email = maskEmail(c.Email)
+		}
+	}
+
+	return &shaped
+}
+
+func (s *Service) shapeCards(ctx context.Context, cards []*Card, approver bool) []*Card {
+	ctx, span := tracing.Start(ctx, "card.shapeCards")
+	defer span.End()
+
+	for i, c := range cards {
+		cards[i] = s.shapeCard(ctx, c, approver)
+	}
+	return cards
+}
+
+// displayNumber formats a stored E.164 number in style, falling back to the
+// number as entered for cards whose number never parsed.
+func displayNumber(e164, entered string, style phone.Style) string {
+	if e164 == "" {
+		return entered
+	}
+	return phone.Format(e164, style)
+}
+
+// maskPhone keeps the country prefix and the last four digits.
+func maskPhone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+
+	var b strings.Builder
+	seen := 0
+	for i, r := range phone {
+		if r < '0' || r > '9' {
+			b.WriteRune(r)
+			continue
+		}
+
+		seen++
+		if (i < 4 && strings.HasPrefix(phone, "+")) || seen > digits-4 {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteRune('*')
+	}
+
+	return b.String()
+}
+
+// maskEmail keeps the first character of the local part and the domain.
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return email
+	}
+
+	return local[:1] + strings.Repeat("*", len(local)-1) + "@" + domain
+}
@@ -0,0 +1,1328 @@
+// Package config loads the settings of the service: the database, the HTTP
+// and gRPC servers, the token keys and those of every feature. They are read
+// from an optional YAML file and then from the environment, which overrides
+// the file, and are validated before anything is started.
+//
+// Secrets may also be read from files named by the *_FILE variables, e.g.
+// PASETO_ACCESS_KEY_FILE, so they can be mounted rather than set inline.
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/corpmail"
+	"github.com/10664kls/contactqr/internal/envelope"
+	"github.com/10664kls/contactqr/internal/middleware"
+	"github.com/10664kls/contactqr/internal/phone"
+	"github.com/10664kls/contactqr/internal/poster"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	DB    DB    `yaml:"db"`
+	HTTP  HTTP  `yaml:"http"`
+	GRPC  GRPC  `yaml:"grpc"`
+	Token Token `yaml:"token"`
+	PII   PII   `yaml:"pii"`
+
+	Tracing Tracing `yaml:"tracing"`
+	LDAP    LDAP    `yaml:"ldap"`
+	Redis   Redis   `yaml:"redis"`
+	AWS     AWS     `yaml:"aws"`
+
+	Server    Server    `yaml:"server"`
+	Secure    Secure    `yaml:"secure"`
+	Assets    Assets    `yaml:"assets"`
+	Jobs      Jobs      `yaml:"jobs"`
+	Schedules Schedules `yaml:"schedules"`
+	Events    Events    `yaml:"events"`
+	Push      Push      `yaml:"push"`
+	Login     Login     `yaml:"login"`
+	Cards     Cards     `yaml:"cards"`
+	SMTP      SMTP      `yaml:"smtp"`
+	Alert     Alert     `yaml:"alert"`
+	HRSync    HRSync    `yaml:"hrSync"`
+	Exports   Exports   `yaml:"exports"`
+	Seed      Seed      `yaml:"seed"`
+}
+
+type DB struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+
+	// BreakerThreshold failures in a row open the breaker for
+	// BreakerCooldown, see package breaker.
+	BreakerThreshold int           `yaml:"breakerThreshold"`
+	BreakerCooldown  time.Duration `yaml:"breakerCooldown"`
+
+	// ConnectAttempts pings at startup, ConnectBackoff apart at first.
+	ConnectAttempts int           `yaml:"connectAttempts"`
+	ConnectBackoff  time.Duration `yaml:"connectBackoff"`
+	WatchInterval   time.Duration `yaml:"watchInterval"`
+
+	// MaxOpenConns bounds the connections of the pool, 0 leaving it
+	// unbounded, of which MaxIdleConns are kept open while idle. A
+	// connection is closed after ConnMaxLifetime, or ConnMaxIdleTime of not
+	// being used, so the pool follows failovers and load balancers.
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+
+	// Migrate is what happens to pending migrations at startup: "check"
+	// refuses to start, "apply" applies them and "off" ignores them.
+	// MigrationsDir replaces the migrations embedded in the binary.
+	Migrate       string `yaml:"migrate"`
+	MigrationsDir string `yaml:"migrationsDir"`
+}
+
+// DSN returns the connection string of the database.
+func (db *DB) DSN() string {
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(db.User, db.Password),
+		Host:     net.JoinHostPort(db.Host, db.Port),
+		RawQuery: url.Values{"database": {db.Name}, "TrustServerCertificate": {"true"}}.Encode(),
+	}
+	return u.String()
+}
+
+type HTTP struct {
+	Port string `yaml:"port"`
+
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+	MaxHeaderBytes    int           `yaml:"maxHeaderBytes"`
+
+	// RateLimit is the number of requests a second allowed per client IP.
+	RateLimit float64 `yaml:"rateLimit"`
+
+	// RateLimits are the requests a second allowed per client IP on single
+	// routes by method and path, e.g. "POST /v1/auth/login", instead of
+	// RateLimit.
+	RateLimits map[string]float64 `yaml:"rateLimits"`
+
+	// CORSOrigins are the origins browsers may call the API from. Empty
+	// allows any origin.
+	CORSOrigins []string `yaml:"corsOrigins"`
+
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers
+	// and proxies in front of the service, whose X-Forwarded-For is
+	// believed. Empty takes the client to be the peer of the connection.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+type GRPC struct {
+	// Port is the port of the gRPC server, which the gateway under /rpc of
+	// the HTTP server calls too. Empty disables both.
+	Port string `yaml:"port"`
+}
+
+type Token struct {
+	// AccessKeys and RefreshKeys are the versions of the PASETO v4 local
+	// keys access and refresh tokens are encrypted with. The newest version
+	// encrypts, all of them decrypt.
+	AccessKeys  []Key `yaml:"accessKeys"`
+	RefreshKeys []Key `yaml:"refreshKeys"`
+
+	// AccessKey and RefreshKey are single keys, used as version 1 when no
+	// versions are configured.
+	AccessKey  string `yaml:"accessKey"`
+	RefreshKey string `yaml:"refreshKey"`
+
+	// ShareKeys are the versions of the key the tokens of card share links
+	// are encrypted with, ShareKey a single key used as version 1. None
+	// disables share links.
+	ShareKeys []Key  `yaml:"shareKeys"`
+	ShareKey  string `yaml:"shareKey"`
+
+	AccessTTL  time.Duration `yaml:"accessTTL"`
+	RefreshTTL time.Duration `yaml:"refreshTTL"`
+}
+
+// Key is one version of a token key, hex encoded.
+type Key struct {
+	Version int    `yaml:"version"`
+	Key     string `yaml:"key"`
+}
+
+type PII struct {
+	// KMSKeyID is the AWS KMS key that wraps the keys personal data is
+	// encrypted with, see package pii. It is reached with the aws
+	// credentials.
+	KMSKeyID    string `yaml:"kmsKeyID"`
+	KMSRegion   string `yaml:"kmsRegion"`
+	KMSEndpoint string `yaml:"kmsEndpoint"`
+
+	// MasterKey wraps the keys in process memory instead, where no KMS is
+	// at hand. With neither, personal data is left unencrypted.
+	MasterKey string `yaml:"masterKey"`
+}
+
+type Tracing struct {
+	// Endpoint is the OTLP/HTTP URL spans are exported to, e.g.
+	// "http://otel-collector:4318/v1/traces". Empty disables tracing, see
+	// package tracing.
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+type LDAP struct {
+	// URL is the Active Directory server logins are checked against before
+	// the database, e.g. "ldaps://ad.example.com:636". Empty disables it,
+	// see package ldap.
+	URL      string `yaml:"url"`
+	StartTLS bool   `yaml:"startTLS"`
+
+	BindDN       string `yaml:"bindDN"`
+	BindPassword string `yaml:"bindPassword"`
+	BaseDN       string `yaml:"baseDN"`
+	UserFilter   string `yaml:"userFilter"`
+
+	// DisplayNameAttr, EmailAttr, PhoneAttr and MobileAttr name the
+	// attributes mapped to the claims.
+	DisplayNameAttr string `yaml:"displayNameAttr"`
+	EmailAttr       string `yaml:"emailAttr"`
+	PhoneAttr       string `yaml:"phoneAttr"`
+	MobileAttr      string `yaml:"mobileAttr"`
+
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type Redis struct {
+	// Addr is the host:port of the Redis server rate limits are counted in
+	// across replicas. Empty counts them in the memory of each replica.
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// AWS are the credentials S3 buckets are reached with, by the asset
+// storage and by exports.
+type AWS struct {
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	SessionToken    string `yaml:"sessionToken"`
+}
+
+type Server struct {
+	// DrainTimeout is how long in-flight requests may take to finish on
+	// shutdown, see package drain.
+	DrainTimeout time.Duration `yaml:"drainTimeout"`
+
+	// ProbeTimeout and DiagnosticsTimeout bound each check of the
+	// readiness probe and of the diagnostics endpoint.
+	ProbeTimeout       time.Duration `yaml:"probeTimeout"`
+	DiagnosticsTimeout time.Duration `yaml:"diagnosticsTimeout"`
+
+	// DisplayTimezone is the IANA zone times are shown in when the client
+	// sends none.
+	DisplayTimezone string `yaml:"displayTimezone"`
+
+	// InternalToken is the bearer token of the /internal endpoints. Empty
+	// serves them to loopback callers only.
+	InternalToken string `yaml:"internalToken"`
+
+	// ResponseEnvelope is the response shape, legacy or v1, of clients that
+	// do not ask for one.
+	ResponseEnvelope string `yaml:"responseEnvelope"`
+
+	// PageTemplatesDir replaces the templates of the public card pages
+	// embedded in the binary.
+	PageTemplatesDir string `yaml:"pageTemplatesDir"`
+}
+
+// Secure are the security headers of API responses and public pages, see
+// middleware.Secure.
+type Secure struct {
+	HSTSMaxAge int `yaml:"hstsMaxAge"`
+
+	APIContentSecurityPolicy string `yaml:"apiContentSecurityPolicy"`
+	APIReferrerPolicy        string `yaml:"apiReferrerPolicy"`
+	APIFrameOptions          string `yaml:"apiFrameOptions"`
+
+	PageContentSecurityPolicy string `yaml:"pageContentSecurityPolicy"`
+	PageReferrerPolicy        string `yaml:"pageReferrerPolicy"`
+	PageFrameOptions          string `yaml:"pageFrameOptions"`
+}
+
+type Assets struct {
+	// S3Bucket is the bucket generated assets and uploaded photos are kept
+	// in. Empty keeps them on local disk under Dir.
+	S3Bucket   string `yaml:"s3Bucket"`
+	S3Region   string `yaml:"s3Region"`
+	S3Prefix   string `yaml:"s3Prefix"`
+	S3Endpoint string `yaml:"s3Endpoint"`
+
+	Dir string `yaml:"dir"`
+}
+
+type Jobs struct {
+	// Workers run the jobs of the queue, which is polled every
+	// PollInterval.
+	Workers      int           `yaml:"workers"`
+	PollInterval time.Duration `yaml:"pollInterval"`
+
+	WebhookDeliveryInterval time.Duration `yaml:"webhookDeliveryInterval"`
+	TemplateReloadInterval  time.Duration `yaml:"templateReloadInterval"`
+}
+
+// Schedules are the cron specs of the recurring jobs, e.g. "@hourly" or
+// "*/15 * * * *".
+type Schedules struct {
+	AuditAnchor         string `yaml:"auditAnchor"`
+	IdempotencyKeyPurge string `yaml:"idempotencyKeyPurge"`
+	PhotoRefresh        string `yaml:"photoRefresh"`
+	RefreshTokenPurge   string `yaml:"refreshTokenPurge"`
+	LoginAttemptPurge   string `yaml:"loginAttemptPurge"`
+}
+
+type Events struct {
+	// Publisher is the broker card events are published to, log or kafka.
+	Publisher    string   `yaml:"publisher"`
+	KafkaBrokers []string `yaml:"kafkaBrokers"`
+	KafkaTopic   string   `yaml:"kafkaTopic"`
+
+	// RelayInterval is how often the outbox is polled for events to
+	// publish.
+	RelayInterval time.Duration `yaml:"relayInterval"`
+}
+
+type Push struct {
+	// Provider is how push notifications are delivered: mobile sends them
+	// through FCM to Android and APNs to iOS, log only logs them, for
+	// development.
+	Provider string `yaml:"provider"`
+
+	// FCMCredentials is the JSON key of the Firebase service account.
+	FCMCredentials string `yaml:"fcmCredentials"`
+
+	// APNsKey is the PEM encoded .p8 signing key of the app, APNsKeyID its
+	// ID, APNsTeamID the ID of the app's team and APNsTopic its bundle ID.
+	// APNsSandbox sends to the development environment.
+	APNsKey     string `yaml:"apnsKey"`
+	APNsKeyID   string `yaml:"apnsKeyID"`
+	APNsTeamID  string `yaml:"apnsTeamID"`
+	APNsTopic   string `yaml:"apnsTopic"`
+	APNsSandbox bool   `yaml:"apnsSandbox"`
+}
+
+type Login struct {
+	// ReportURL and PasswordResetURL are the links sent by email, with %s
+	// standing for the token.
+	ReportURL        string        `yaml:"reportURL"`
+	PasswordResetURL string        `yaml:"passwordResetURL"`
+	PasswordResetTTL time.Duration `yaml:"passwordResetTTL"`
+
+	// LockoutThreshold failed logins within FailureWindow lock the
+	// username out for LockoutBase, doubling up to LockoutMax.
+	LockoutThreshold int           `yaml:"lockoutThreshold"`
+	LockoutBase      time.Duration `yaml:"lockoutBase"`
+	LockoutMax       time.Duration `yaml:"lockoutMax"`
+	FailureWindow    time.Duration `yaml:"failureWindow"`
+
+	// GeoIPDatabase is the MaxMind database, e.g. GeoLite2-Country.mmdb,
+	// logins are located with to warn of logins from a new country. Empty
+	// disables the warning.
+	GeoIPDatabase string `yaml:"geoipDatabase"`
+}
+
+type Cards struct {
+	// IdempotencyWindow is how long an Idempotency-Key is remembered.
+	IdempotencyWindow time.Duration `yaml:"idempotencyWindow"`
+
+	// MaxActive is how many pending, approved or published cards an
+	// employee may hold, 0 for no limit.
+	MaxActive int `yaml:"maxActive"`
+
+	Phone  Phone  `yaml:"phone"`
+	Email  Email  `yaml:"email"`
+	Poster Poster `yaml:"poster"`
+	Wallet Wallet `yaml:"wallet"`
+}
+
+type Phone struct {
+	// Region is assumed for numbers entered without a country, Format is
+	// how numbers are displayed, international or national. Both may be
+	// overridden per company ID.
+	Region         string           `yaml:"region"`
+	CompanyRegions map[int64]string `yaml:"companyRegions"`
+	Format         string           `yaml:"format"`
+	CompanyFormats map[int64]string `yaml:"companyFormats"`
+}
+
+type Email struct {
+	// Policy is what happens to cards with a non-corporate email, flag or
+	// reject. Domains are the corporate domains of every company, unless
+	// overridden per company ID.
+	Policy         string             `yaml:"policy"`
+	Domains        []string           `yaml:"domains"`
+	CompanyDomains map[int64][]string `yaml:"companyDomains"`
+}
+
+type Poster struct {
+	// Color is the band color of printed posters as #RRGGBB, which may be
+	// overridden per company ID.
+	Color         string           `yaml:"color"`
+	CompanyColors map[int64]string `yaml:"companyColors"`
+}
+
+type Wallet struct {
+	// PassTypeID is the Apple Wallet pass type published cards are issued
+	// as, e.g. pass.com.example.contactqr, TeamID the Apple developer team
+	// owning it and Organization the issuer Wallet shows. Empty disables
+	// Wallet passes.
+	PassTypeID   string `yaml:"passTypeID"`
+	TeamID       string `yaml:"teamID"`
+	Organization string `yaml:"organization"`
+
+	// Certificate and Key are the PEM encoded pass type certificate and
+	// its private key, WWDR the Apple WWDR certificate that issued it.
+	Certificate string `yaml:"certificate"`
+	Key         string `yaml:"key"`
+	WWDR        string `yaml:"wwdr"`
+}
+
+type SMTP struct {
+	// Addr is the host:port of the mail server. Empty only logs the
+	// notifications, e.g. in development.
+	Addr     string `yaml:"addr"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type Alert struct {
+	// OfficeHours are the HH:MM-HH:MM in Timezone outside which suspicious
+	// activity is alerted on, to Recipients.
+	Timezone    string   `yaml:"timezone"`
+	OfficeHours string   `yaml:"officeHours"`
+	Recipients  []string `yaml:"recipients"`
+
+	ForbiddenThreshold int           `yaml:"forbiddenThreshold"`
+	ForbiddenWindow    time.Duration `yaml:"forbiddenWindow"`
+	VCFThreshold       int           `yaml:"vcfThreshold"`
+	VCFWindow          time.Duration `yaml:"vcfWindow"`
+}
+
+type HRSync struct {
+	// Source is the drop folder or endpoint HR data is imported from, see
+	// hrsync.ParseSource. Empty disables the import.
+	Source   string `yaml:"source"`
+	Schedule string `yaml:"schedule"`
+
+	// Token is sent as bearer token to an endpoint source.
+	Token string `yaml:"token"`
+}
+
+type Exports struct {
+	// File names the JSON array of recurring exports. Empty exports
+	// nothing.
+	File string `yaml:"file"`
+}
+
+type Seed struct {
+	// Password is the password of the users created by --seed.
+	Password string `yaml:"password"`
+}
+
+// Default returns the settings used when neither the file nor the
+// environment set them.
+func Default() *Config {
+	return &Config{
+		DB: DB{
+			Port:             "1433",
+			BreakerThreshold: 5,
+			BreakerCooldown:  10 * time.Second,
+			ConnectAttempts:  10,
+			ConnectBackoff:   time.Second,
+			WatchInterval:    30 * time.Second,
+			MaxOpenConns:     25,
+			MaxIdleConns:     10,
+			ConnMaxLifetime:  30 * time.Minute,
+			ConnMaxIdleTime:  5 * time.Minute,
+			Migrate:          "check",
+		},
+		HTTP: HTTP{
+			Port:              "8089",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			RateLimit:         10,
+			RateLimits: map[string]float64{
+				"POST /v1/auth/login": 1,
+			},
+		},
+		GRPC: GRPC{
+			Port: "9090",
+		},
+		Token: Token{
+			AccessTTL:  time.Hour,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Tracing: Tracing{
+			ServiceName: "contactqr",
+			SampleRatio: 1,
+		},
+		LDAP: LDAP{
+			UserFilter:      "(&(objectClass=user)(sAMAccountName=%s))",
+			DisplayNameAttr: "displayName",
+			EmailAttr:       "mail",
+			PhoneAttr:       "telephoneNumber",
+			MobileAttr:      "mobile",
+			Timeout:         5 * time.Second,
+		},
+		Server: Server{
+			DrainTimeout:       25 * time.Second,
+			ProbeTimeout:       2 * time.Second,
+			DiagnosticsTimeout: 3 * time.Second,
+			DisplayTimezone:    tz.Default,
+			ResponseEnvelope:   string(envelope.Legacy),
+		},
+		Secure: Secure{
+			HSTSMaxAge:                middleware.DefaultSecureConfig.API.HSTSMaxAge,
+			APIContentSecurityPolicy:  middleware.DefaultSecureConfig.API.ContentSecurityPolicy,
+			APIReferrerPolicy:         middleware.DefaultSecureConfig.API.ReferrerPolicy,
+			APIFrameOptions:           middleware.DefaultSecureConfig.API.XFrameOptions,
+			PageContentSecurityPolicy: middleware.DefaultSecureConfig.Page.ContentSecurityPolicy,
+			PageReferrerPolicy:        middleware.DefaultSecureConfig.Page.ReferrerPolicy,
+			PageFrameOptions:          middleware.DefaultSecureConfig.Page.XFrameOptions,
+		},
+		Assets: Assets{
+			Dir: "data/assets",
+		},
+		Jobs: Jobs{
+			Workers:                 4,
+			PollInterval:            time.Second,
+			WebhookDeliveryInterval: 10 * time.Second,
+			TemplateReloadInterval:  time.Minute,
+		},
+		Schedules: Schedules{
+			AuditAnchor:         "@hourly",
+			IdempotencyKeyPurge: "@hourly",
+			PhotoRefresh:        "@every 15m",
+			RefreshTokenPurge:   "@daily",
+			LoginAttemptPurge:   "@hourly",
+		},
+		Events: Events{
+			Publisher:     "log",
+			KafkaBrokers:  []string{"localhost:9092"},
+			KafkaTopic:    "contactqr.business-card",
+			RelayInterval: time.Second,
+		},
+		Push: Push{
+			Provider: "log",
+		},
+		Login: Login{
+			ReportURL:        "https://contactqr.krungsrilaos.com/report-login?token=%s",
+			PasswordResetURL: "https://contactqr.krungsrilaos.com/reset-password?token=%s",
+			PasswordResetTTL: 24 * time.Hour,
+			LockoutThreshold: 5,
+			LockoutBase:      time.Minute,
+			LockoutMax:       time.Hour,
+			FailureWindow:    24 * time.Hour,
+		},
+		Cards: Cards{
+			IdempotencyWindow: 24 * time.Hour,
+			Phone: Phone{
+				Region: phone.DefaultRegion,
+				Format: string(phone.International),
+			},
+			Email: Email{
+				Policy: string(corpmail.Flag),
+			},
+			Poster: Poster{
+				Color: "#1A3C8C",
+			},
+		},
+		SMTP: SMTP{
+			From: "ContactQR <no-reply@krungsrilaos.com>",
+		},
+		Alert: Alert{
+			Timezone:           "Asia/Vientiane",
+			OfficeHours:        "07:00-19:00",
+			ForbiddenThreshold: 20,
+			ForbiddenWindow:    5 * time.Minute,
+			VCFThreshold:       200,
+			VCFWindow:          time.Minute,
+		},
+		HRSync: HRSync{
+			Schedule: "@hourly",
+		},
+		Seed: Seed{
+			Password: "password",
+		},
+	}
+}
+
+// Load returns the defaults overridden by the YAML file at path, if path
+// is not empty, and then by the environment.
+func Load(path string) (*Config, error) {
+	c := Default()
+
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(b, c); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := c.loadEnv(); err != nil {
+		return nil, err
+	}
+	if len(c.Token.AccessKeys) == 0 && c.Token.AccessKey != "" {
+		c.Token.AccessKeys = []Key{{Version: 1, Key: c.Token.AccessKey}}
+	}
+	if len(c.Token.RefreshKeys) == 0 && c.Token.RefreshKey != "" {
+		c.Token.RefreshKeys = []Key{{Version: 1, Key: c.Token.RefreshKey}}
+	}
+	if len(c.Token.ShareKeys) == 0 && c.Token.ShareKey != "" {
+		c.Token.ShareKeys = []Key{{Version: 1, Key: c.Token.ShareKey}}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Config) loadEnv() error {
+	return errors.Join(
+		envString(&c.DB.Host, "DB_HOST"),
+		envString(&c.DB.Port, "DB_PORT"),
+		envString(&c.DB.User, "DB_USER"),
+		envSecret(&c.DB.Password, "DB_PASSWORD"),
+		envString(&c.DB.Name, "DB_NAME"),
+		envInt(&c.DB.BreakerThreshold, "DB_BREAKER_THRESHOLD"),
+		envDuration(&c.DB.BreakerCooldown, "DB_BREAKER_COOLDOWN"),
+		envInt(&c.DB.ConnectAttempts, "DB_CONNECT_ATTEMPTS"),
+		envDuration(&c.DB.ConnectBackoff, "DB_CONNECT_BACKOFF"),
+		envDuration(&c.DB.WatchInterval, "DB_WATCH_INTERVAL"),
+		envInt(&c.DB.MaxOpenConns, "DB_MAX_OPEN_CONNS"),
+		envInt(&c.DB.MaxIdleConns, "DB_MAX_IDLE_CONNS"),
+		envDuration(&c.DB.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME"),
+		envDuration(&c.DB.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME"),
+
+		envString(&c.HTTP.Port, "PORT"),
+		envDuration(&c.HTTP.ReadHeaderTimeout, "HTTP_READ_HEADER_TIMEOUT"),
+		envDuration(&c.HTTP.ReadTimeout, "HTTP_READ_TIMEOUT"),
+		envDuration(&c.HTTP.WriteTimeout, "HTTP_WRITE_TIMEOUT"),
+		envDuration(&c.HTTP.IdleTimeout, "HTTP_IDLE_TIMEOUT"),
+		envInt(&c.HTTP.MaxHeaderBytes, "HTTP_MAX_HEADER_BYTES"),
+		envFloat(&c.HTTP.RateLimit, "HTTP_RATE_LIMIT"),
+		envRates(&c.HTTP.RateLimits, "HTTP_RATE_LIMITS"),
+		envList(&c.HTTP.CORSOrigins, "CORS_ORIGINS"),
+		envList(&c.HTTP.TrustedProxies, "TRUSTED_PROXIES"),
+
+		envString(&c.GRPC.Port, "GRPC_PORT"),
+
+		envSecret(&c.Token.AccessKey, "PASETO_ACCESS_KEY"),
+		envSecret(&c.Token.RefreshKey, "PASETO_REFRESH_KEY"),
+		envKeys(&c.Token.AccessKeys, "PASETO_ACCESS_KEYS"),
+		envKeys(&c.Token.RefreshKeys, "PASETO_REFRESH_KEYS"),
+		envSecret(&c.Token.ShareKey, "PASETO_SHARE_KEY"),
+		envKeys(&c.Token.ShareKeys, "PASETO_SHARE_KEYS"),
+		envDuration(&c.Token.AccessTTL, "ACCESS_TOKEN_TTL"),
+		envDuration(&c.Token.RefreshTTL, "REFRESH_TOKEN_TTL"),
+
+		envString(&c.PII.KMSKeyID, "PII_KMS_KEY_ID"),
+		envString(&c.PII.KMSRegion, "PII_KMS_REGION"),
+		envString(&c.PII.KMSEndpoint, "PII_KMS_ENDPOINT"),
+		envSecret(&c.PII.MasterKey, "PII_MASTER_KEY"),
+
+		envString(&c.Tracing.Endpoint, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		envString(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME"),
+		envFloat(&c.Tracing.SampleRatio, "OTEL_TRACES_SAMPLER_ARG"),
+
+		envString(&c.LDAP.URL, "LDAP_URL"),
+		envBool(&c.LDAP.StartTLS, "LDAP_START_TLS"),
+		envString(&c.LDAP.BindDN, "LDAP_BIND_DN"),
+		envSecret(&c.LDAP.BindPassword, "LDAP_BIND_PASSWORD"),
+		envString(&c.LDAP.BaseDN, "LDAP_BASE_DN"),
+		envString(&c.LDAP.UserFilter, "LDAP_USER_FILTER"),
+		envDuration(&c.LDAP.Timeout, "LDAP_TIMEOUT"),
+
+		envString(&c.Redis.Addr, "REDIS_ADDR"),
+		envSecret(&c.Redis.Password, "REDIS_PASSWORD"),
+		envInt(&c.Redis.DB, "REDIS_DB"),
+
+		envString(&c.AWS.AccessKeyID, "AWS_ACCESS_KEY_ID"),
+		envSecret(&c.AWS.SecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+		envSecret(&c.AWS.SessionToken, "AWS_SESSION_TOKEN"),
+
+		envString(&c.DB.Migrate, "DB_MIGRATE"),
+		envString(&c.DB.MigrationsDir, "MIGRATIONS_DIR"),
+
+		envDuration(&c.Server.DrainTimeout, "DRAIN_TIMEOUT"),
+		envDuration(&c.Server.ProbeTimeout, "PROBE_TIMEOUT"),
+		envDuration(&c.Server.DiagnosticsTimeout, "DIAGNOSTICS_TIMEOUT"),
+		envString(&c.Server.DisplayTimezone, "DISPLAY_TIMEZONE"),
+		envSecret(&c.Server.InternalToken, "INTERNAL_TOKEN"),
+		envString(&c.Server.ResponseEnvelope, "RESPONSE_ENVELOPE"),
+		envString(&c.Server.PageTemplatesDir, "PAGE_TEMPLATES_DIR"),
+
+		envInt(&c.Secure.HSTSMaxAge, "SECURE_HSTS_MAX_AGE"),
+		envString(&c.Secure.APIContentSecurityPolicy, "SECURE_API_CSP"),
+		envString(&c.Secure.APIReferrerPolicy, "SECURE_API_REFERRER_POLICY"),
+		envString(&c.Secure.APIFrameOptions, "SECURE_API_FRAME_OPTIONS"),
+		envString(&c.Secure.PageContentSecurityPolicy, "SECURE_PAGE_CSP"),
+		envString(&c.Secure.PageReferrerPolicy, "SECURE_PAGE_REFERRER_POLICY"),
+		envString(&c.Secure.PageFrameOptions, "SECURE_PAGE_FRAME_OPTIONS"),
+
+		envString(&c.Assets.S3Bucket, "ASSETS_S3_BUCKET"),
+		envString(&c.Assets.S3Region, "ASSETS_S3_REGION"),
+		envString(&c.Assets.S3Prefix, "ASSETS_S3_PREFIX"),
+		envString(&c.Assets.S3Endpoint, "ASSETS_S3_ENDPOINT"),
+		envString(&c.Assets.Dir, "ASSETS_DIR"),
+
+		envInt(&c.Jobs.Workers, "JOB_WORKERS"),
+		envDuration(&c.Jobs.PollInterval, "JOB_POLL_INTERVAL"),
+		envDuration(&c.Jobs.WebhookDeliveryInterval, "WEBHOOK_DELIVERY_INTERVAL"),
+		envDuration(&c.Jobs.TemplateReloadInterval, "TEMPLATE_RELOAD_INTERVAL"),
+
+		envString(&c.Schedules.AuditAnchor, "AUDIT_ANCHOR_SCHEDULE"),
+		envString(&c.Schedules.IdempotencyKeyPurge, "IDEMPOTENCY_KEY_PURGE_SCHEDULE"),
+		envString(&c.Schedules.PhotoRefresh, "PHOTO_REFRESH_SCHEDULE"),
+		envString(&c.Schedules.RefreshTokenPurge, "REFRESH_TOKEN_PURGE_SCHEDULE"),
+		envString(&c.Schedules.LoginAttemptPurge, "LOGIN_ATTEMPT_PURGE_SCHEDULE"),
+
+		envString(&c.Events.Publisher, "EVENT_PUBLISHER"),
+		envList(&c.Events.KafkaBrokers, "KAFKA_BROKERS"),
+		envString(&c.Events.KafkaTopic, "KAFKA_TOPIC"),
+		envDuration(&c.Events.RelayInterval, "OUTBOX_RELAY_INTERVAL"),
+
+		envString(&c.Push.Provider, "PUSH_PROVIDER"),
+		envSecret(&c.Push.FCMCredentials, "FCM_CREDENTIALS"),
+		envSecret(&c.Push.APNsKey, "APNS_KEY"),
+		envString(&c.Push.APNsKeyID, "APNS_KEY_ID"),
+		envString(&c.Push.APNsTeamID, "APNS_TEAM_ID"),
+		envString(&c.Push.APNsTopic, "APNS_TOPIC"),
+		envBool(&c.Push.APNsSandbox, "APNS_SANDBOX"),
+
+		envString(&c.Login.ReportURL, "LOGIN_REPORT_URL"),
+		envString(&c.Login.PasswordResetURL, "PASSWORD_RESET_URL"),
+		envDuration(&c.Login.PasswordResetTTL, "PASSWORD_RESET_TTL"),
+		envInt(&c.Login.LockoutThreshold, "LOGIN_LOCKOUT_THRESHOLD"),
+		envDuration(&c.Login.LockoutBase, "LOGIN_LOCKOUT_BASE"),
+		envDuration(&c.Login.LockoutMax, "LOGIN_LOCKOUT_MAX"),
+		envDuration(&c.Login.FailureWindow, "LOGIN_FAILURE_WINDOW"),
+		envString(&c.Login.GeoIPDatabase, "GEOIP_DATABASE"),
+
+		envDuration(&c.Cards.IdempotencyWindow, "IDEMPOTENCY_WINDOW"),
+		envInt(&c.Cards.MaxActive, "CARD_MAX_ACTIVE"),
+		envString(&c.Cards.Phone.Region, "PHONE_DEFAULT_REGION"),
+		envCompanies(&c.Cards.Phone.CompanyRegions, "PHONE_COMPANY_REGIONS"),
+		envString(&c.Cards.Phone.Format, "PHONE_DISPLAY_FORMAT"),
+		envCompanies(&c.Cards.Phone.CompanyFormats, "PHONE_COMPANY_FORMATS"),
+		envString(&c.Cards.Email.Policy, "EMAIL_POLICY"),
+		envList(&c.Cards.Email.Domains, "EMAIL_DOMAINS"),
+		envCompanyLists(&c.Cards.Email.CompanyDomains, "EMAIL_COMPANY_DOMAINS"),
+		envString(&c.Cards.Poster.Color, "POSTER_COLOR"),
+		envCompanies(&c.Cards.Poster.CompanyColors, "POSTER_COMPANY_COLORS"),
+		envString(&c.Cards.Wallet.PassTypeID, "WALLET_PASS_TYPE_ID"),
+		envString(&c.Cards.Wallet.TeamID, "WALLET_TEAM_ID"),
+		envString(&c.Cards.Wallet.Organization, "WALLET_ORGANIZATION"),
+		envSecret(&c.Cards.Wallet.Certificate, "WALLET_CERTIFICATE"),
+		envSecret(&c.Cards.Wallet.Key, "WALLET_KEY"),
+		envSecret(&c.Cards.Wallet.WWDR, "WALLET_WWDR"),
+
+		envString(&c.SMTP.Addr, "SMTP_ADDR"),
+		envString(&c.SMTP.Username, "SMTP_USERNAME"),
+		envSecret(&c.SMTP.Password, "SMTP_PASSWORD"),
+		envString(&c.SMTP.From, "SMTP_FROM"),
+
+		envString(&c.Alert.Timezone, "ALERT_TIMEZONE"),
+		envString(&c.Alert.OfficeHours, "ALERT_OFFICE_HOURS"),
+		envList(&c.Alert.Recipients, "ALERT_RECIPIENTS"),
+		envInt(&c.Alert.ForbiddenThreshold, "ALERT_FORBIDDEN_THRESHOLD"),
+		envDuration(&c.Alert.ForbiddenWindow, "ALERT_FORBIDDEN_WINDOW"),
+		envInt(&c.Alert.VCFThreshold, "ALERT_VCF_THRESHOLD"),
+		envDuration(&c.Alert.VCFWindow, "ALERT_VCF_WINDOW"),
+
+		envString(&c.HRSync.Source, "HR_SYNC_SOURCE"),
+		envString(&c.HRSync.Schedule, "HR_SYNC_SCHEDULE"),
+		envSecret(&c.HRSync.Token, "HR_SYNC_TOKEN"),
+
+		envString(&c.Exports.File, "EXPORTS_FILE"),
+
+		envSecret(&c.Seed.Password, "SEED_PASSWORD"),
+	)
+}
+
+// Validate reports every setting that is missing or out of range.
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, f := range []struct{ name, v string }{
+		{"db.host", c.DB.Host},
+		{"db.port", c.DB.Port},
+		{"db.user", c.DB.User},
+		{"db.name", c.DB.Name},
+	} {
+		if f.v == "" {
+			errs = append(errs, fmt.Errorf("%s is required", f.name))
+		}
+	}
+	if c.DB.BreakerThreshold <= 0 {
+		errs = append(errs, errors.New("db.breakerThreshold must be positive"))
+	}
+	if c.DB.ConnectAttempts <= 0 {
+		errs = append(errs, errors.New("db.connectAttempts must be positive"))
+	}
+	if c.DB.MaxOpenConns < 0 {
+		errs = append(errs, errors.New("db.maxOpenConns must not be negative"))
+	}
+	if c.DB.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("db.maxIdleConns must not be negative"))
+	} else if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		errs = append(errs, errors.New("db.maxIdleConns must not exceed db.maxOpenConns"))
+	}
+	if c.DB.ConnMaxLifetime < 0 || c.DB.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("db.connMaxLifetime and db.connMaxIdleTime must not be negative"))
+	}
+
+	if _, err := strconv.ParseUint(c.HTTP.Port, 10, 16); err != nil {
+		errs = append(errs, fmt.Errorf("http.port %q is not a port", c.HTTP.Port))
+	}
+	if c.HTTP.RateLimit <= 0 {
+		errs = append(errs, errors.New("http.rateLimit must be positive"))
+	}
+	for route, rate := range c.HTTP.RateLimits {
+		if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("http.rateLimits route %q is not a method and path", route))
+		}
+		if rate <= 0 {
+			errs = append(errs, fmt.Errorf("http.rateLimits of %q must be positive", route))
+		}
+	}
+	for _, origin := range c.HTTP.CORSOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("http.corsOrigins entry %q is not an origin", origin))
+		}
+	}
+
+	if _, err := middleware.ParseNetworks(c.HTTP.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("http.trustedProxies: %w", err))
+	}
+
+	if c.GRPC.Port != "" {
+		if _, err := strconv.ParseUint(c.GRPC.Port, 10, 16); err != nil {
+			errs = append(errs, fmt.Errorf("grpc.port %q is not a port", c.GRPC.Port))
+		} else if c.GRPC.Port == c.HTTP.Port {
+			errs = append(errs, errors.New("grpc.port must differ from http.port"))
+		}
+	}
+
+	errs = append(errs, validateKeys("token.accessKeys", c.Token.AccessKeys)...)
+	errs = append(errs, validateKeys("token.refreshKeys", c.Token.RefreshKeys)...)
+	for _, a := range c.Token.AccessKeys {
+		for _, r := range c.Token.RefreshKeys {
+			if strings.EqualFold(a.Key, r.Key) {
+				errs = append(errs, errors.New("token.accessKeys and token.refreshKeys must not share a key"))
+			}
+		}
+	}
+	if len(c.Token.ShareKeys) > 0 {
+		errs = append(errs, validateKeys("token.shareKeys", c.Token.ShareKeys)...)
+		for _, s := range c.Token.ShareKeys {
+			for _, k := range slices.Concat(c.Token.AccessKeys, c.Token.RefreshKeys) {
+				if strings.EqualFold(s.Key, k.Key) {
+					errs = append(errs, errors.New("token.shareKeys must not share a key with token.accessKeys or token.refreshKeys"))
+				}
+			}
+		}
+	}
+	if c.Token.AccessTTL <= 0 {
+		errs = append(errs, errors.New("token.accessTTL must be positive"))
+	}
+	if c.Token.RefreshTTL < c.Token.AccessTTL {
+		errs = append(errs, errors.New("token.refreshTTL must not be shorter than token.accessTTL"))
+	}
+
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("tracing.endpoint %q is not an http(s) URL", c.Tracing.Endpoint))
+		}
+		if c.Tracing.ServiceName == "" {
+			errs = append(errs, errors.New("tracing.serviceName is required"))
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing.sampleRatio must be between 0 and 1"))
+	}
+
+	if c.LDAP.URL != "" {
+		if u, err := url.Parse(c.LDAP.URL); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("ldap.url %q is not an ldap(s) URL", c.LDAP.URL))
+		} else if c.LDAP.StartTLS && u.Scheme == "ldaps" {
+			errs = append(errs, errors.New("ldap.startTLS is for ldap URLs only"))
+		}
+		if c.LDAP.BaseDN == "" {
+			errs = append(errs, errors.New("ldap.baseDN is required"))
+		}
+		if strings.Count(c.LDAP.UserFilter, "%s") != 1 {
+			errs = append(errs, errors.New("ldap.userFilter must contain one %s"))
+		}
+		if c.LDAP.Timeout <= 0 {
+			errs = append(errs, errors.New("ldap.timeout must be positive"))
+		}
+	}
+
+	if c.Redis.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("redis.addr %q is not a host:port", c.Redis.Addr))
+		}
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, errors.New("redis.db must not be negative"))
+	}
+
+	if (c.AWS.AccessKeyID == "") != (c.AWS.SecretAccessKey == "") {
+		errs = append(errs, errors.New("aws.accessKeyID and aws.secretAccessKey must be set together"))
+	}
+	if c.Assets.S3Bucket != "" && c.AWS.AccessKeyID == "" {
+		errs = append(errs, errors.New("aws.accessKeyID and aws.secretAccessKey are required with assets.s3Bucket"))
+	}
+
+	if c.PII.KMSKeyID != "" {
+		if c.PII.MasterKey != "" {
+			errs = append(errs, errors.New("pii.kmsKeyID and pii.masterKey must not both be set"))
+		}
+		if c.PII.KMSRegion == "" {
+			errs = append(errs, errors.New("pii.kmsRegion is required with pii.kmsKeyID"))
+		}
+		if c.AWS.AccessKeyID == "" {
+			errs = append(errs, errors.New("aws.accessKeyID and aws.secretAccessKey are required with pii.kmsKeyID"))
+		}
+	}
+
+	errs = append(errs, c.validateService()...)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+// validateService checks the settings of the service itself, beyond its
+// connections and keys.
+func (c *Config) validateService() []error {
+	var errs []error
+
+	switch c.DB.Migrate {
+	case "check", "apply", "off":
+	default:
+		errs = append(errs, fmt.Errorf("db.migrate %q must be check, apply or off", c.DB.Migrate))
+	}
+
+	for _, f := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"server.drainTimeout", c.Server.DrainTimeout},
+		{"server.probeTimeout", c.Server.ProbeTimeout},
+		{"server.diagnosticsTimeout", c.Server.DiagnosticsTimeout},
+		{"jobs.pollInterval", c.Jobs.PollInterval},
+		{"jobs.webhookDeliveryInterval", c.Jobs.WebhookDeliveryInterval},
+		{"jobs.templateReloadInterval", c.Jobs.TemplateReloadInterval},
+		{"events.relayInterval", c.Events.RelayInterval},
+		{"login.passwordResetTTL", c.Login.PasswordResetTTL},
+		{"login.lockoutBase", c.Login.LockoutBase},
+		{"login.failureWindow", c.Login.FailureWindow},
+		{"cards.idempotencyWindow", c.Cards.IdempotencyWindow},
+		{"alert.forbiddenWindow", c.Alert.ForbiddenWindow},
+		{"alert.vcfWindow", c.Alert.VCFWindow},
+	} {
+		if f.d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", f.name))
+		}
+	}
+	for _, f := range []struct {
+		name string
+		n    int
+	}{
+		{"jobs.workers", c.Jobs.Workers},
+		{"login.lockoutThreshold", c.Login.LockoutThreshold},
+		{"alert.forbiddenThreshold", c.Alert.ForbiddenThreshold},
+		{"alert.vcfThreshold", c.Alert.VCFThreshold},
+	} {
+		if f.n <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", f.name))
+		}
+	}
+	if c.Login.LockoutMax < c.Login.LockoutBase {
+		errs = append(errs, errors.New("login.lockoutMax must not be shorter than login.lockoutBase"))
+	}
+	if c.Cards.MaxActive < 0 {
+		errs = append(errs, errors.New("cards.maxActive must not be negative"))
+	}
+	if c.Secure.HSTSMaxAge < 0 {
+		errs = append(errs, errors.New("secure.hstsMaxAge must not be negative"))
+	}
+
+	for _, f := range []struct{ name, v string }{
+		{"server.displayTimezone", c.Server.DisplayTimezone},
+		{"alert.timezone", c.Alert.Timezone},
+	} {
+		if _, err := time.LoadLocation(f.v); err != nil {
+			errs = append(errs, fmt.Errorf("%s %q is not a time zone", f.name, f.v))
+		}
+	}
+	if _, ok := envelope.ParseShape(c.Server.ResponseEnvelope); !ok {
+		errs = append(errs, fmt.Errorf("server.responseEnvelope %q must be legacy or v1", c.Server.ResponseEnvelope))
+	}
+
+	schedules := []struct{ name, spec string }{
+		{"schedules.auditAnchor", c.Schedules.AuditAnchor},
+		{"schedules.idempotencyKeyPurge", c.Schedules.IdempotencyKeyPurge},
+		{"schedules.photoRefresh", c.Schedules.PhotoRefresh},
+		{"schedules.refreshTokenPurge", c.Schedules.RefreshTokenPurge},
+		{"schedules.loginAttemptPurge", c.Schedules.LoginAttemptPurge},
+	}
+	if c.HRSync.Source != "" {
+		schedules = append(schedules, struct{ name, spec string }{"hrSync.schedule", c.HRSync.Schedule})
+	}
+	for _, f := range schedules {
+		if _, err := cron.ParseStandard(f.spec); err != nil {
+			errs = append(errs, fmt.Errorf("%s %q is not a cron spec", f.name, f.spec))
+		}
+	}
+
+	switch c.Events.Publisher {
+	case "log":
+	case "kafka":
+		if len(c.Events.KafkaBrokers) == 0 || c.Events.KafkaTopic == "" {
+			errs = append(errs, errors.New("events.kafkaBrokers and events.kafkaTopic are required by kafka"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("events.publisher %q must be log or kafka", c.Events.Publisher))
+	}
+
+	switch c.Push.Provider {
+	case "log":
+	case "mobile":
+		if c.Push.FCMCredentials == "" {
+			errs = append(errs, errors.New("push.fcmCredentials is required by mobile"))
+		}
+		if c.Push.APNsKey == "" || c.Push.APNsKeyID == "" || c.Push.APNsTeamID == "" || c.Push.APNsTopic == "" {
+			errs = append(errs, errors.New("push.apnsKey, push.apnsKeyID, push.apnsTeamID and push.apnsTopic are required by mobile"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("push.provider %q must be log or mobile", c.Push.Provider))
+	}
+
+	for _, f := range []struct{ name, v string }{
+		{"login.reportURL", c.Login.ReportURL},
+		{"login.passwordResetURL", c.Login.PasswordResetURL},
+	} {
+		if strings.Count(f.v, "%s") != 1 {
+			errs = append(errs, fmt.Errorf("%s must contain one %%s", f.name))
+		}
+	}
+
+	if c.Cards.Phone.Region == "" {
+		errs = append(errs, errors.New("cards.phone.region is required"))
+	}
+	for id, region := range c.Cards.Phone.CompanyRegions {
+		if region == "" {
+			errs = append(errs, fmt.Errorf("cards.phone.companyRegions of company %d is empty", id))
+		}
+	}
+	if _, ok := phone.ParseStyle(c.Cards.Phone.Format); !ok {
+		errs = append(errs, fmt.Errorf("cards.phone.format %q must be international or national", c.Cards.Phone.Format))
+	}
+	for id, format := range c.Cards.Phone.CompanyFormats {
+		if _, ok := phone.ParseStyle(format); !ok {
+			errs = append(errs, fmt.Errorf("cards.phone.companyFormats of company %d %q must be international or national", id, format))
+		}
+	}
+	if m := corpmail.Mode(c.Cards.Email.Policy); m != corpmail.Flag && m != corpmail.Reject {
+		errs = append(errs, fmt.Errorf("cards.email.policy %q must be flag or reject", c.Cards.Email.Policy))
+	}
+	for id, domains := range c.Cards.Email.CompanyDomains {
+		if len(domains) == 0 {
+			errs = append(errs, fmt.Errorf("cards.email.companyDomains of company %d is empty", id))
+		}
+	}
+	if _, err := poster.ParseColor(c.Cards.Poster.Color); err != nil {
+		errs = append(errs, fmt.Errorf("cards.poster.color: %w", err))
+	}
+	for id, color := range c.Cards.Poster.CompanyColors {
+		if _, err := poster.ParseColor(color); err != nil {
+			errs = append(errs, fmt.Errorf("cards.poster.companyColors of company %d: %w", id, err))
+		}
+	}
+	if w := c.Cards.Wallet; w != (Wallet{}) && (w.PassTypeID == "" || w.TeamID == "" || w.Organization == "" || w.Certificate == "" || w.Key == "" || w.WWDR == "") {
+		errs = append(errs, errors.New("cards.wallet passTypeID, teamID, organization, certificate, key and wwdr must be set together"))
+	}
+
+	if c.SMTP.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.SMTP.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("smtp.addr %q is not a host:port", c.SMTP.Addr))
+		}
+	}
+
+	if _, _, err := c.Alert.Office(); err != nil {
+		errs = append(errs, fmt.Errorf("alert.officeHours %q must be HH:MM-HH:MM", c.Alert.OfficeHours))
+	}
+
+	return errs
+}
+
+// Office returns the office hours as offsets from midnight.
+func (a *Alert) Office() (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(a.OfficeHours, "-")
+	if !ok {
+		return 0, 0, errors.New("expected HH:MM-HH:MM")
+	}
+	if start, err = clockOffset(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = clockOffset(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// clockOffset parses a HH:MM wall clock time as an offset from midnight.
+func clockOffset(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func validateKeys(name string, keys []Key) []error {
+	if len(keys) == 0 {
+		return []error{fmt.Errorf("%s is required", name)}
+	}
+
+	var errs []error
+	seen := make(map[int]bool, len(keys))
+	for _, k := range keys {
+		if k.Version <= 0 {
+			errs = append(errs, fmt.Errorf("%s version %d must be positive", name, k.Version))
+		}
+		if seen[k.Version] {
+			errs = append(errs, fmt.Errorf("%s version %d is duplicated", name, k.Version))
+		}
+		seen[k.Version] = true
+		if b, err := hex.DecodeString(k.Key); err != nil || len(b) != 32 {
+			errs = append(errs, fmt.Errorf("%s version %d must be 32 hex encoded bytes", name, k.Version))
+		}
+	}
+	return errs
+}
+
+func envString(dst *string, key string) error {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+	}
+	return nil
+}
+
+// envSecret reads key, or else the file named by key_FILE.
+func envSecret(dst *string, key string) error {
+	if v, ok := os.LookupEnv(key); ok {
+		*dst = v
+		return nil
+	}
+
+	path, ok := os.LookupEnv(key + "_FILE")
+	if !ok {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	*dst = strings.TrimSpace(string(b))
+	return nil
+}
+
+func envInt(dst *int, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid integer for %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func envFloat(dst *float64, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number for %s: %w", key, err)
+	}
+	*dst = f
+	return nil
+}
+
+func envBool(dst *bool, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid boolean for %s: %w", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func envDuration(dst *time.Duration, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid duration for %s: %w", key, err)
+	}
+	*dst = d
+	return nil
+}
+
+// envList reads a comma separated list. An empty variable clears the list.
+func envList(dst *[]string, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	list := make([]string, 0)
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	*dst = list
+	return nil
+}
+
+// envRates reads route limits as "METHOD /path=rate,...". An empty variable
+// clears them.
+func envRates(dst *map[string]float64, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(v, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		route, r, ok := strings.Cut(pair, "=")
+		f, err := strconv.ParseFloat(strings.TrimSpace(r), 64)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid %s entry, expected route=rate", key)
+		}
+		rates[strings.TrimSpace(route)] = f
+	}
+	*dst = rates
+	return nil
+}
+
+// envCompanies reads values per company as "companyID:value,...". An empty
+// variable clears them.
+func envCompanies(dst *map[int64]string, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+
+	values := make(map[int64]string)
+	for _, pair := range strings.Split(v, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		id, value, ok := strings.Cut(pair, ":")
+		companyID, err := strconv.ParseInt(id, 10, 64)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid %s entry %q, expected companyID:value", key, pair)
+		}
+		values[companyID] = value
+	}
+	*dst = values
+	return nil
+}
+
+// envCompanyLists reads lists per company as "companyID:a|b,...". An empty
+// variable clears them.
+func envCompanyLists(dst *map[int64][]string, key string) error {
+	var values map[int64]string
+	if err := envCompanies(&values, key); err != nil || values == nil {
+		return err
+	}
+
+	lists := make(map[int64][]string, len(values))
+	for id, v := range values {
+		list := make([]string, 0)
+		for _, s := range strings.Split(v, "|") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		lists[id] = list
+	}
+	*dst = lists
+	return nil
+}
+
+// envKeys reads versioned keys as "version:key,...", or from the file named
+// by key_FILE.
+func envKeys(dst *[]Key, key string) error {
+	var v string
+	if err := envSecret(&v, key); err != nil || v == "" {
+		return err
+	}
+
+	keys := make([]Key, 0)
+	for _, pair := range strings.Split(v, ",") {
+		version, hexKey, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		n, err := strconv.Atoi(version)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid %s entry, expected version:key", key)
+		}
+		keys = append(keys, Key{Version: n, Key: hexKey})
+	}
+	*dst = keys
+	return nil
+}
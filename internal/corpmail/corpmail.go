@@ -0,0 +1,77 @@
+// Package corpmail decides whether an email address belongs to the company
+// a card is issued for, so cards do not go out with staff's personal
+// mailboxes on them.
+package corpmail
+
+import "strings"
+
+// personalDomains are free mail providers whose addresses are treated as
+// personal rather than corporate.
+var personalDomains = map[string]bool{
+	"gmail.com":   true,
+	"yahoo.com":   true,
+	"hotmail.com": true,
+	"outlook.com": true,
+	"icloud.com":  true,
+	"live.com":    true,
+}
+
+// IsPersonal reports whether email is at a free mail provider.
+func IsPersonal(email string) bool {
+	return personalDomains[domainOf(email)]
+}
+
+// Mode is what happens to a card submitted with a non-corporate email.
+type Mode string
+
+const (
+	// Flag accepts the card and marks it for the approver.
+	Flag Mode = "flag"
+
+	// Reject refuses the card with a NON_CORPORATE_EMAIL violation.
+	Reject Mode = "reject"
+)
+
+// Policy lists the corporate email domains of each company.
+type Policy struct {
+	Mode Mode
+
+	// Default are the domains of companies without their own list.
+	Default []string
+
+	Companies map[int64][]string
+}
+
+// Corporate reports whether email belongs to the company. Subdomains of a
+// corporate domain count. Without any configured domain only free mail
+// providers are refused.
+func (p Policy) Corporate(companyID int64, email string) bool {
+	domain := domainOf(email)
+	if domain == "" {
+		return false
+	}
+
+	domains, ok := p.Companies[companyID]
+	if !ok {
+		domains = p.Default
+	}
+	if len(domains) == 0 {
+		return !personalDomains[domain]
+	}
+
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func domainOf(email string) string {
+	_, domain, ok := strings.Cut(strings.TrimSpace(email), "@")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(domain)
+}
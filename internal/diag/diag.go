@@ -0,0 +1,170 @@
+// Package diag checks the dependencies card publishing relies on, so ops
+// can tell which one is degrading it.
+package diag
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// Check reports whether a dependency is usable. It must return once ctx is
+// done.
+type Check func(ctx context.Context) error
+
+const (
+	StatusOK     = "OK"
+	StatusFailed = "FAILED"
+)
+
+// Result is the outcome of one dependency check. LastError is the most
+// recent failure seen by this instance, which may be older than the check.
+type Result struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	LatencyMS   int64      `json:"latencyMs"`
+	Error       string     `json:"error,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+type Report struct {
+	Healthy   bool      `json:"healthy"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Checks    []*Result `json:"checks"`
+}
+
+type lastError struct {
+	msg string
+	at  time.Time
+}
+
+type Diagnostics struct {
+	timeout time.Duration
+	zlog    *zap.Logger
+
+	mu     sync.Mutex
+	names  []string
+	checks map[string]Check
+	last   map[string]lastError
+}
+
+// New returns Diagnostics that give each check at most timeout.
+func New(timeout time.Duration, zlog *zap.Logger) (*Diagnostics, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Diagnostics{
+		timeout: timeout,
+		zlog:    zlog,
+		checks:  make(map[string]Check),
+		last:    make(map[string]lastError),
+	}, nil
+}
+
+// Register adds a dependency check. Checks are reported in the order they
+// were registered.
+func (d *Diagnostics) Register(name string, check Check) error {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	if check == nil {
+		return errors.New("check is nil")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.checks[name]; ok {
+		return errors.New("check " + name + " is already registered")
+	}
+	d.names = append(d.names, name)
+	d.checks[name] = check
+	return nil
+}
+
+// Run checks every dependency concurrently. It is for HR only.
+func (d *Diagnostics) Run(ctx context.Context) (*Report, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := d.zlog.With(
+		zap.String("method", "Run"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadDiagnostics) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.DiagnosticsForbidden)
+	}
+
+	d.mu.Lock()
+	names := append([]string(nil), d.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = d.checks[name]
+	}
+	d.mu.Unlock()
+
+	results := make([]*Result, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = d.run(ctx, names[i], checks[i])
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{
+		Healthy:   true,
+		CheckedAt: time.Now(),
+		Checks:    results,
+	}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			report.Healthy = false
+			zlog.Warn("dependency check failed", zap.String("check", r.Name), zap.String("error", r.Error))
+		}
+	}
+
+	return report, nil
+}
+
+func (d *Diagnostics) run(ctx context.Context, name string, check Check) *Result {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	r := &Result{
+		Name:      name,
+		Status:    StatusOK,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		r.Status = StatusFailed
+		r.Error = err.Error()
+		d.last[name] = lastError{msg: r.Error, at: start}
+	}
+	if last, ok := d.last[name]; ok {
+		r.LastError = last.msg
+		r.LastErrorAt = &last.at
+	}
+
+	return r
+}
@@ -0,0 +1,148 @@
+// Package drain takes an instance out of rotation for a zero-downtime
+// deploy: it marks the instance not ready, then waits for in-flight requests
+// and background work to finish.
+package drain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type State struct {
+	Draining  bool       `json:"draining"`
+	Drained   bool       `json:"drained"`
+	InFlight  int64      `json:"inFlight"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+type Drainer struct {
+	timeout time.Duration
+	zlog    *zap.Logger
+
+	inFlight atomic.Int64
+
+	mu        sync.Mutex
+	startedAt *time.Time
+	drained   bool
+	hooks     []func(ctx context.Context) error
+}
+
+// New returns a Drainer that waits at most timeout for work to finish.
+func New(timeout time.Duration, zlog *zap.Logger) (*Drainer, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Drainer{
+		timeout: timeout,
+		zlog:    zlog,
+	}, nil
+}
+
+// OnDrain registers fn to stop and wait for background work when draining.
+// fn must return once ctx is done.
+func (d *Drainer) OnDrain(fn func(ctx context.Context) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks = append(d.hooks, fn)
+}
+
+// Middleware counts in-flight requests. Requests to /internal/ are not
+// counted so the drain request does not wait for itself.
+func (d *Drainer) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if strings.HasPrefix(c.Request().URL.Path, "/internal/") {
+				return next(c)
+			}
+
+			d.inFlight.Add(1)
+			defer d.inFlight.Add(-1)
+			return next(c)
+		}
+	}
+}
+
+// Ready reports whether the instance should receive traffic.
+func (d *Drainer) Ready() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.startedAt == nil
+}
+
+func (d *Drainer) State() *State {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &State{
+		Draining:  d.startedAt != nil,
+		Drained:   d.drained,
+		InFlight:  d.inFlight.Load(),
+		StartedAt: d.startedAt,
+	}
+}
+
+// Drain marks the instance not ready and waits, up to the drain timeout,
+// for in-flight requests and registered background work to finish. It can
+// be called again to wait for a drain that timed out.
+func (d *Drainer) Drain(ctx context.Context) *State {
+	d.mu.Lock()
+	if d.startedAt == nil {
+		now := time.Now()
+		d.startedAt = &now
+		d.zlog.Info("draining")
+	}
+	hooks := d.hooks
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(hooks))
+	for i, fn := range hooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for d.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+	wg.Wait()
+
+	drained := d.inFlight.Load() == 0
+	if err := errors.Join(errs...); err != nil {
+		d.zlog.Warn("background work did not finish while draining", zap.Error(err))
+		drained = false
+	}
+
+	d.mu.Lock()
+	d.drained = drained
+	d.mu.Unlock()
+
+	st := d.State()
+	d.zlog.Info("drain finished", zap.Bool("drained", st.Drained), zap.Int64("inFlight", st.InFlight))
+	return st
+}
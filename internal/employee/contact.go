@@ -0,0 +1,75 @@
+package employee
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/10664kls/contactqr/internal/pii"
+	"github.com/10664kls/contactqr/internal/utils"
+)
+
+// RestoreLegacyContacts undoes the encryption earlier versions applied to
+// the phone numbers in dbo.tb_employee, which other systems read in plain
+// text. Each employee's encrypted numbers are first kept, still encrypted,
+// in dbo.employee_contact, unless the employee already has contacts there,
+// then written back to dbo.tb_employee in plain text. It needs the global
+// pii Cipher that encrypted them and returns how many employees it
+// restored.
+func RestoreLegacyContacts(ctx context.Context, db *sql.DB) (int, error) {
+	q := `
+SELECT EID, COALESCE(Emails, ''), phone_number, mobile_number
+FROM dbo.tb_employee
+WHERE phone_number LIKE 'enc:v1:%' OR mobile_number LIKE 'enc:v1:%'`
+
+	rows, err := utils.QueryContext(ctx, db, q)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	type contact struct {
+		employeeID int64
+		email      pii.Text
+		phone      pii.Text
+		mobile     pii.Text
+	}
+
+	var contacts []contact
+	for rows.Next() {
+		var c contact
+		if err := rows.Scan(&c.employeeID, &c.email, &c.phone, &c.mobile); err != nil {
+			return 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+	rows.Close()
+
+	keep := `
+IF NOT EXISTS (SELECT 1 FROM dbo.employee_contact WHERE employee_id = @p1)
+  INSERT INTO dbo.employee_contact (employee_id, email, phone_number, mobile_number)
+  VALUES (@p1, @p2, @p3, @p4);`
+
+	restore := `
+UPDATE dbo.tb_employee SET phone_number = @p2, mobile_number = @p3 WHERE EID = @p1;`
+
+	for _, c := range contacts {
+		err := utils.WithTx(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, keep, c.employeeID, c.email, c.phone, c.mobile); err != nil {
+				return fmt.Errorf("failed to execute keep employee contact: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, restore, c.employeeID, string(c.phone), string(c.mobile)); err != nil {
+				return fmt.Errorf("failed to execute restore employee contact: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(contacts), nil
+}
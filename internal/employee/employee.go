@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
 	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/visibility"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
-	rpcStatus "google.golang.org/grpc/status"
 )
 
 type Service struct {
@@ -38,17 +42,14 @@ func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Service, erro
 func (s *Service) ListEmployees(ctx context.Context, req *EmployeeQuery) (*ListEmployeesResult, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "ListEmployees"),
 		zap.String("username", claims.Code),
 		zap.Any("req", req),
 	)
 
-	if !claims.IsHR {
-		return nil, rpcStatus.Error(
-			codes.PermissionDenied,
-			"You are not allowed to access theses employees.",
-		)
+	if !rbac.Can(ctx, rbac.ReadEmployees) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeesForbidden)
 	}
 
 	employees, err := listEmployees(ctx, s.db, req)
@@ -57,45 +58,54 @@ func (s *Service) ListEmployees(ctx context.Context, req *EmployeeQuery) (*ListE
 		return nil, err
 	}
 
-	var pageToken string
-	if l := len(employees); l > 0 && l == int(pager.Size(req.PageSize)) {
+	for _, e := range employees {
+		e.viewer = viewerOf(ctx, e)
+		e.loc = tz.FromContext(ctx)
+	}
+
+	res := &ListEmployeesResult{Employees: employees}
+	if l := len(employees); req.Page == 0 && l > 0 && l == int(pager.Size(req.PageSize)) {
 		last := employees[l-1]
-		pageToken = pager.EncodeCursor(&pager.Cursor{
+		res.NextPageToken = pager.EncodeCursor(&pager.Cursor{
 			ID:   strconv.FormatInt(last.ID, 10),
 			Time: last.CreatedAt,
 		})
 	}
+	if req.IncludeTotal {
+		total, err := countEmployees(ctx, s.db, req)
+		if err != nil {
+			zlog.Error("failed to count employees", zap.Error(err))
+			return nil, err
+		}
+		res.TotalSize = &total
+	}
 
-	return &ListEmployeesResult{
-		Employees:     employees,
-		NextPageToken: pageToken,
-	}, nil
+	return res, nil
 }
 
 func (s *Service) GetEmployeeByID(ctx context.Context, id int64) (*Employee, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetEmployeeByID"),
 		zap.String("username", claims.Code),
 		zap.Int64("id", id),
 	)
 
-	if !claims.IsHR {
-		return nil, rpcStatus.Error(
-			codes.PermissionDenied,
-			"You are not allowed to access this employee or (it may not exist)",
-		)
+	if !rbac.Can(ctx, rbac.ReadEmployees) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeeNotFound)
 	}
 
 	employee, err := getEmployee(ctx, s.db, &EmployeeQuery{ID: id})
 	if errors.Is(err, ErrEmployeeNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this employee or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeeNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get employee by id", zap.Error(err))
 		return nil, err
 	}
+	employee.viewer = viewerOf(ctx, employee)
+	employee.loc = tz.FromContext(ctx)
 
 	return employee, nil
 }
@@ -103,19 +113,21 @@ func (s *Service) GetEmployeeByID(ctx context.Context, id int64) (*Employee, err
 func (s *Service) GetMyEmployeeProfile(ctx context.Context) (*Employee, error) {
 	claims := auth.ClaimsFromContext(ctx)
 
-	zlog := s.zlog.With(
+	zlog := reqid.Logger(ctx, s.zlog).With(
 		zap.String("method", "GetMyEmployeeProfile"),
 		zap.String("username", claims.Code),
 	)
 
 	employee, err := getEmployee(ctx, s.db, &EmployeeQuery{ID: claims.ID})
 	if errors.Is(err, ErrEmployeeNotFound) {
-		return nil, rpcStatus.Error(codes.PermissionDenied, "You are not allowed to access this employee or (it may not exist)")
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeeNotFound)
 	}
 	if err != nil {
 		zlog.Error("failed to get employee by id", zap.Error(err))
 		return nil, err
 	}
+	employee.viewer = viewerOf(ctx, employee)
+	employee.loc = tz.FromContext(ctx)
 
 	return employee, nil
 }
@@ -128,6 +140,8 @@ type Employee struct {
 	CompanyID      int64     `json:"companyId"`
 	Code           string    `json:"code"`
 	DisplayName    string    `json:"displayName"`
+	DisplayNameEn  string    `json:"displayNameEn"`
+	DisplayNameLo  string    `json:"displayNameLo"` // Empty when HR has no Lao name.
 	DepartmentName string    `json:"departmentName"`
 	PositionName   string    `json:"positionName"`
 	CompanyName    string    `json:"companyName"`
@@ -135,6 +149,11 @@ type Employee struct {
 	Phone          string    `json:"phoneNumber"`
 	Mobile         string    `json:"mobileNumber"`
 	CreatedAt      time.Time `json:"createdAt"`
+
+	// viewer is who the employee is being shown to, see viewerOf, and loc
+	// the timezone its timestamps are displayed in.
+	viewer visibility.Role
+	loc    *time.Location
 }
 
 func (e *Employee) SetPhone(phone string) {
@@ -148,6 +167,10 @@ func (e *Employee) SetMobile(mobile string) {
 type ListEmployeesResult struct {
 	Employees     []*Employee `json:"employees"`
 	NextPageToken string      `json:"nextPageToken"`
+
+	// TotalSize is the number of matching employees across all pages, set
+	// when the query asks for it.
+	TotalSize *int64 `json:"totalSize,omitempty"`
 }
 
 func makeEmailFromDisplayName(originalEmail, employeeCode, displayName string) string {
@@ -163,3 +186,24 @@ func makeEmailFromDisplayName(originalEmail, employeeCode, displayName string) s
 
 	return strings.Replace(originalEmail, employeeCode, displayName, len(employeeCode))
 }
+
+// ManagerOf returns the ID of the employee's manager, 0 if they have none.
+// It is used to route approval requests, so it does not check the caller.
+func (s *Service) ManagerOf(ctx context.Context, employeeID int64) (int64, error) {
+	employee, err := getEmployee(ctx, s.db, &EmployeeQuery{ID: employeeID})
+	if err != nil {
+		return 0, err
+	}
+	return employee.ManagerID, nil
+}
+
+// EmailOf returns the employee's email address, empty if they have none.
+// It is used to notify employees of what others did to their cards, so it
+// does not check the caller.
+func (s *Service) EmailOf(ctx context.Context, employeeID int64) (string, error) {
+	employee, err := getEmployee(ctx, s.db, &EmployeeQuery{ID: employeeID})
+	if err != nil {
+		return "", err
+	}
+	return employee.Email, nil
+}
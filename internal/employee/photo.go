@@ -0,0 +1,81 @@
+package employee
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrPhotoNotFound = errors.New("photo not found")
+
+// Photo is an employee's directory photo, as synced from Active Directory.
+type Photo struct {
+	EmployeeID  int64     `json:"employeeId"`
+	ContentType string    `json:"contentType"`
+	Hash        string    `json:"hash"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	Data []byte `json:"-"`
+}
+
+// maxPhotoSize bounds a photo so that the vCards embedding it stay small
+// enough for phones to import.
+const maxPhotoSize = 256 << 10
+
+// SavePhoto stores the latest directory photo of an employee, a JPEG or PNG
+// image. It is called by the directory sync through the internal API, so it
+// does not check the caller. Saving the photo already stored is a no-op.
+func (s *Service) SavePhoto(ctx context.Context, employeeID int64, data []byte) (*Photo, error) {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SavePhoto"),
+		zap.Int64("employee_id", employeeID),
+		zap.Int("size", len(data)),
+	)
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, i18n.Error(codes.InvalidArgument, i18n.InvalidPhoto)
+	}
+	if len(data) > maxPhotoSize {
+		return nil, i18n.Error(codes.InvalidArgument, i18n.PhotoTooLarge, "size", strconv.Itoa(maxPhotoSize))
+	}
+
+	if _, err := getEmployee(ctx, s.db, &EmployeeQuery{ID: employeeID}); err != nil {
+		if errors.Is(err, ErrEmployeeNotFound) {
+			return nil, i18n.Error(codes.NotFound, i18n.EmployeeNotFound)
+		}
+		zlog.Error("failed to get employee", zap.Error(err))
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	p := &Photo{
+		EmployeeID:  employeeID,
+		ContentType: contentType,
+		Hash:        hex.EncodeToString(sum[:]),
+		UpdatedAt:   time.Now(),
+		Data:        data,
+	}
+	if err := savePhoto(ctx, s.db, p); err != nil {
+		zlog.Error("failed to save photo", zap.Error(err))
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// PhotoOf returns the latest directory photo of an employee, or
+// ErrPhotoNotFound if none was synced. Like NotificationLanguage it is used
+// on behalf of other users and does not check the caller.
+func (s *Service) PhotoOf(ctx context.Context, employeeID int64) (*Photo, error) {
+	return getPhoto(ctx, s.db, employeeID)
+}
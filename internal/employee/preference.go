@@ -0,0 +1,117 @@
+package employee
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// Preferences are settings an employee manages for themselves.
+type Preferences struct {
+	// NotificationLanguage is the language emails and messages to the
+	// employee are written in.
+	NotificationLanguage i18n.Lang `json:"notificationLanguage"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+func (s *Service) GetMyPreferences(ctx context.Context) (*Preferences, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetMyPreferences"),
+		zap.String("username", claims.Code),
+	)
+
+	if claims.ID <= 0 {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeeNotFound)
+	}
+
+	p, err := getPreferences(ctx, s.db, claims.ID)
+	if err != nil {
+		zlog.Error("failed to get preferences", zap.Error(err))
+		return nil, err
+	}
+
+	return p, nil
+}
+
+type PreferencesReq struct {
+	NotificationLanguage string `json:"notificationLanguage"`
+
+	lang i18n.Lang
+}
+
+func (r *PreferencesReq) Validate() error {
+	r.NotificationLanguage = strings.TrimSpace(r.NotificationLanguage)
+
+	violations, err := validate.Violations(&contactqrPb.PreferencesRequest{
+		NotificationLanguage: r.NotificationLanguage,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.NotificationLanguage != "" {
+		if lang, ok := i18n.ParseLang(r.NotificationLanguage); ok {
+			r.lang = lang
+		} else {
+			violations = append(violations, i18n.Violation("notificationLanguage", i18n.UnsupportedLang))
+		}
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPreferences).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+func (s *Service) UpdateMyPreferences(ctx context.Context, in *PreferencesReq) (*Preferences, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "UpdateMyPreferences"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if claims.ID <= 0 {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.EmployeeNotFound)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &Preferences{
+		NotificationLanguage: in.lang,
+		UpdatedAt:            time.Now(),
+	}
+	if err := savePreferences(ctx, s.db, claims.ID, p); err != nil {
+		zlog.Error("failed to save preferences", zap.Error(err))
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// NotificationLanguage returns the language notifications to the employee
+// are written in. It is used when notifying an employee of something
+// another user did, so it does not check the caller.
+func (s *Service) NotificationLanguage(ctx context.Context, employeeID int64) (i18n.Lang, error) {
+	p, err := getPreferences(ctx, s.db, employeeID)
+	if err != nil {
+		return "", err
+	}
+	return p.NotificationLanguage, nil
+}
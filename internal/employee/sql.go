@@ -8,7 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/10664kls/contactqr/internal/i18n"
 	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/pii"
+	"github.com/10664kls/contactqr/internal/utils"
 	sq "github.com/Masterminds/squirrel"
 )
 
@@ -25,6 +28,11 @@ type EmployeeQuery struct {
 	CreatedAfter  time.Time `json:"createdAfter" query:"createdAfter"`
 	PageToken     string    `json:"pageToken" query:"pageToken"`
 	PageSize      uint64    `json:"pageSize" query:"pageSize"`
+
+	// Page selects a page by number, from 1, instead of by PageToken.
+	// IncludeTotal counts the matching employees across all pages.
+	Page         uint64 `json:"page" query:"page"`
+	IncludeTotal bool   `json:"includeTotal" query:"includeTotal"`
 }
 
 func (q *EmployeeQuery) ToSql() (string, []any, error) {
@@ -55,18 +63,18 @@ func (q *EmployeeQuery) ToSql() (string, []any, error) {
 	}
 
 	if !q.CreatedBefore.IsZero() {
-		and = append(and, sq.LtOrEq{"createdate": q.CreatedBefore})
+		and = append(and, sq.LtOrEq{"createdate": pager.DateTime(q.CreatedBefore)})
 	}
 	if !q.CreatedAfter.IsZero() {
-		and = append(and, sq.GtOrEq{"createdate": q.CreatedAfter})
+		and = append(and, sq.GtOrEq{"createdate": pager.DateTime(q.CreatedAfter)})
 	}
 
-	if q.PageToken != "" {
+	if q.PageToken != "" && q.Page == 0 {
 		cursor, err := pager.DecodeCursor(q.PageToken)
 		if err != nil {
 			return "", nil, err
 		}
-		and = append(and, sq.Expr("EID < ?", cursor.ID))
+		and = append(and, cursor.After("createdate", "EID"))
 	}
 
 	return and.ToSql()
@@ -74,12 +82,15 @@ func (q *EmployeeQuery) ToSql() (string, []any, error) {
 
 func listEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) ([]*Employee, error) {
 	id := fmt.Sprintf("TOP %d EID", pager.Size(in.PageSize))
+	if in.Page > 0 {
+		id = "EID"
+	}
 	pred, args, err := in.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	q, args := sq.
+	b := sq.
 		Select(
 			id,
 			"EMPNO",
@@ -91,19 +102,25 @@ func listEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) ([]*Emplo
 			"Positionname",
 			"nameeng",
 			"surnameeng",
-			"Emails",
-			"phone_number",
-			"mobile_number",
+			"COALESCE(namelao, '')",
+			"COALESCE(surnamelao, '')",
+			"CASE WHEN c.employee_id IS NULL THEN vm_employee.Emails ELSE c.email END",
+			"CASE WHEN c.employee_id IS NULL THEN vm_employee.phone_number ELSE c.phone_number END",
+			"CASE WHEN c.employee_id IS NULL THEN vm_employee.mobile_number ELSE c.mobile_number END",
 			"COALESCE(approveby, 0) AS manager_id",
 			"createdate",
 		).
 		From("dbo.vm_employee").
+		LeftJoin("dbo.employee_contact AS c ON c.employee_id = vm_employee.EID").
 		PlaceholderFormat(sq.AtP).
 		Where(pred, args...).
-		OrderBy("EID DESC").
-		MustSql()
+		OrderBy("createdate DESC", "EID DESC")
+	if in.Page > 0 {
+		b = b.SuffixExpr(pager.Fetch(in.Page, in.PageSize))
+	}
+	q, args := b.MustSql()
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	rows, err := utils.QueryContext(ctx, db, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -112,7 +129,7 @@ func listEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) ([]*Emplo
 	employees := make([]*Employee, 0)
 	for rows.Next() {
 		var e Employee
-		var firstName, surname string
+		var firstName, surname, firstNameLo, surnameLo string
 		if err := rows.Scan(
 			&e.ID,
 			&e.Code,
@@ -124,9 +141,11 @@ func listEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) ([]*Emplo
 			&e.PositionName,
 			&firstName,
 			&surname,
-			&e.Email,
-			&e.Phone,
-			&e.Mobile,
+			&firstNameLo,
+			&surnameLo,
+			(*pii.Text)(&e.Email),
+			(*pii.Text)(&e.Phone),
+			(*pii.Text)(&e.Mobile),
 			&e.ManagerID,
 			&e.CreatedAt,
 		); err != nil {
@@ -136,6 +155,8 @@ func listEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) ([]*Emplo
 		firstName = strings.TrimSpace(firstName)
 		surname = strings.TrimSpace(surname)
 		e.DisplayName = fmt.Sprintf("%s %s", firstName, surname)
+		e.DisplayNameEn = e.DisplayName
+		e.DisplayNameLo = strings.TrimSpace(strings.TrimSpace(firstNameLo) + " " + strings.TrimSpace(surnameLo))
 		e.Email = makeEmailFromDisplayName(e.Email, e.Code, e.DisplayName)
 		employees = append(employees, &e)
 	}
@@ -164,3 +185,121 @@ func getEmployee(ctx context.Context, db *sql.DB, in *EmployeeQuery) (*Employee,
 
 	return employees[0], nil
 }
+
+// getPreferences returns the employee's preferences, or the defaults when
+// they never changed any.
+func getPreferences(ctx context.Context, db *sql.DB, employeeID int64) (*Preferences, error) {
+	q, args := sq.
+		Select(
+			"notification_language",
+			"updated_at",
+		).
+		From("dbo.employee_preference").
+		Where(
+			sq.Eq{
+				"employee_id": employeeID,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var p Preferences
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan(&p.NotificationLanguage, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Preferences{NotificationLanguage: i18n.English}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return &p, nil
+}
+
+func savePreferences(ctx context.Context, db *sql.DB, employeeID int64, in *Preferences) error {
+	q := `
+MERGE dbo.employee_preference AS t
+USING (SELECT @p1 AS employee_id) AS s ON t.employee_id = s.employee_id
+WHEN MATCHED THEN
+  UPDATE SET notification_language = @p2, updated_at = @p3
+WHEN NOT MATCHED THEN
+  INSERT (employee_id, notification_language, updated_at) VALUES (@p1, @p2, @p3);`
+
+	if _, err := utils.ExecContext(ctx, db, q, employeeID, string(in.NotificationLanguage), in.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to execute save preferences: %w", err)
+	}
+
+	return nil
+}
+
+func getPhoto(ctx context.Context, db *sql.DB, employeeID int64) (*Photo, error) {
+	q, args := sq.
+		Select(
+			"employee_id",
+			"content_type",
+			"hash",
+			"data",
+			"updated_at",
+		).
+		From("dbo.employee_photo").
+		Where(sq.Eq{"employee_id": employeeID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var p Photo
+	err := utils.QueryRowContext(ctx, db, q, args...).Scan(
+		&p.EmployeeID,
+		&p.ContentType,
+		&p.Hash,
+		&p.Data,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return &p, nil
+}
+
+func savePhoto(ctx context.Context, db *sql.DB, in *Photo) error {
+	q := `
+MERGE dbo.employee_photo AS t
+USING (SELECT @p1 AS employee_id) AS s ON t.employee_id = s.employee_id
+WHEN MATCHED AND t.hash <> @p3 THEN
+  UPDATE SET content_type = @p2, hash = @p3, data = @p4, updated_at = @p5
+WHEN NOT MATCHED THEN
+  INSERT (employee_id, content_type, hash, data, updated_at) VALUES (@p1, @p2, @p3, @p4, @p5);`
+
+	if _, err := utils.ExecContext(ctx, db, q, in.EmployeeID, in.ContentType, in.Hash, in.Data, in.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to execute save photo: %w", err)
+	}
+
+	return nil
+}
+
+// countEmployees returns the number of employees matching in across all
+// pages.
+func countEmployees(ctx context.Context, db *sql.DB, in *EmployeeQuery) (int64, error) {
+	all := *in
+	all.PageToken = ""
+	pred, args, err := all.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	q, args := sq.
+		Select("COUNT(*)").
+		From("dbo.vm_employee").
+		Where(pred, args...).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var count int64
+	if err := utils.QueryRowContext(ctx, db, q, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,60 @@
+package employee
+
+import (
+	"context"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/visibility"
+)
+
+// employeePolicy lists the employee fields that only some viewers may see.
+var employeePolicy = visibility.Policy{
+	"managerId":      {visibility.RoleOwner, visibility.RoleHR},
+	"createdAt":      {visibility.RoleOwner, visibility.RoleHR},
+	"createdAtLocal": {visibility.RoleOwner, visibility.RoleHR},
+}
+
+// MarshalJSON encodes the employee with only the fields its viewer may see.
+// Timestamps are given in UTC and again in the viewer's display timezone.
+func (e *Employee) MarshalJSON() ([]byte, error) {
+	return employeePolicy.Marshal(e.JSONView(), e.viewer)
+}
+
+// JSONView returns the employee as MarshalJSON encodes it, before the
+// fields its viewer may not see are removed.
+func (e *Employee) JSONView() any {
+	type employee Employee
+	return &struct {
+		*employee
+		CreatedAt      time.Time `json:"createdAt"`
+		CreatedAtLocal string    `json:"createdAtLocal"`
+		Timezone       string    `json:"timezone"`
+	}{
+		employee:       (*employee)(e),
+		CreatedAt:      e.CreatedAt.UTC(),
+		CreatedAtLocal: tz.Format(e.CreatedAt, e.loc),
+		Timezone:       e.loc.String(),
+	}
+}
+
+// viewerOf returns the role the caller of ctx has when looking at e.
+func viewerOf(ctx context.Context, e *Employee) visibility.Role {
+	claims := auth.ClaimsFromContext(ctx)
+
+	switch {
+	case rbac.Can(ctx, rbac.ReadEmployees):
+		return visibility.RoleHR
+
+	case claims.ID > 0 && claims.ID == e.ID:
+		return visibility.RoleOwner
+
+	case claims.ID > 0:
+		return visibility.RoleEmployee
+
+	default:
+		return visibility.RoleAnonymous
+	}
+}
@@ -0,0 +1,205 @@
+// Package envelope writes API responses in one consistent shape:
+//
+//	{"apiVersion": "1", "data": ..., "metadata": {...}, "error": {...}}
+//
+// Handlers written before the envelope returned bare structs or maps keyed
+// by resource name. That legacy shape stays available while clients
+// migrate: the server default is configurable and a client picks its shape
+// per request with the X-Response-Envelope header.
+package envelope
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Header lets a client choose the response shape, "legacy" or "v1".
+const Header = "X-Response-Envelope"
+
+// Shape is how a response body is laid out.
+type Shape string
+
+const (
+	// Legacy is the shape each handler used before the envelope.
+	Legacy Shape = "legacy"
+
+	// V1 wraps every response in an Envelope with apiVersion "1".
+	V1 Shape = "v1"
+)
+
+// ParseShape returns the shape named s.
+func ParseShape(s string) (Shape, bool) {
+	switch sh := Shape(strings.ToLower(strings.TrimSpace(s))); sh {
+	case Legacy, V1:
+		return sh, true
+	}
+	return "", false
+}
+
+// Envelope is the body of every response in the V1 shape. Exactly one of
+// Data and Error is set.
+type Envelope struct {
+	APIVersion string          `json:"apiVersion"`
+	Data       any             `json:"data,omitempty"`
+	Metadata   *Metadata       `json:"metadata,omitempty"`
+	Error      json.RawMessage `json:"error,omitempty"`
+}
+
+// Metadata describes a response rather than the resource in it.
+type Metadata struct {
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	TotalSize     *int64 `json:"totalSize,omitempty"`
+	RequestID     string `json:"requestId,omitempty"`
+}
+
+const shapeKey = "envelope.shape"
+
+// Middleware records the response shape of the request: the one named in
+// the X-Response-Envelope header, or def without one.
+func Middleware(def Shape) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			shape := def
+			if v := c.Request().Header.Get(Header); v != "" {
+				sh, ok := ParseShape(v)
+				if !ok {
+					return i18n.Error(codes.InvalidArgument, i18n.InvalidEnvelope, "envelope", v)
+				}
+				shape = sh
+			}
+
+			c.Set(shapeKey, shape)
+			return next(c)
+		}
+	}
+}
+
+// ShapeOf returns the response shape of the request, Legacy if the
+// middleware did not run.
+func ShapeOf(c echo.Context) Shape {
+	if sh, ok := c.Get(shapeKey).(Shape); ok {
+		return sh
+	}
+	return Legacy
+}
+
+// JSON responds with v. The legacy shape keys it by name, e.g.
+// {"businessCard": {...}}; an empty name writes v bare.
+func JSON(c echo.Context, code int, name string, v any) error {
+	if ShapeOf(c) == Legacy {
+		if name == "" {
+			return c.JSON(code, v)
+		}
+		return c.JSON(code, echo.Map{name: v})
+	}
+
+	return c.JSON(code, &Envelope{
+		APIVersion: "1",
+		Data:       v,
+		Metadata:   metadata(c, "", nil),
+	})
+}
+
+// Page responds with one page of a list. The legacy shape writes the
+// service's result struct as is; the envelope carries the items as data and
+// the next page token and the total, if counted, as metadata.
+func Page(c echo.Context, code int, legacy, items any, nextPageToken string, totalSize *int64) error {
+	if ShapeOf(c) == Legacy {
+		return c.JSON(code, legacy)
+	}
+
+	return c.JSON(code, &Envelope{
+		APIVersion: "1",
+		Data:       items,
+		Metadata:   metadata(c, nextPageToken, totalSize),
+	})
+}
+
+// protoJSON renders response messages with camelCase field names and enum
+// names, and with zero values present, as the encoding/json handlers do.
+var protoJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Message responds with the response message res, e.g. a
+// BusinessCardResponse. The legacy shape writes res as is; the envelope
+// carries data, the resource res wraps.
+func Message(c echo.Context, code int, res, data proto.Message) error {
+	if ShapeOf(c) == Legacy {
+		return writeMessage(c, code, res)
+	}
+
+	byt, err := protoJSON.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(code, &Envelope{
+		APIVersion: "1",
+		Data:       json.RawMessage(byt),
+		Metadata:   metadata(c, "", nil),
+	})
+}
+
+// MessagePage responds with one page of a list like Page, where res is the
+// list response message and items the resources in it.
+func MessagePage[T proto.Message](c echo.Context, code int, res proto.Message, items []T, nextPageToken string, totalSize *int64) error {
+	if ShapeOf(c) == Legacy {
+		return writeMessage(c, code, res)
+	}
+
+	data := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		byt, err := protoJSON.Marshal(item)
+		if err != nil {
+			return err
+		}
+		data = append(data, byt)
+	}
+
+	return c.JSON(code, &Envelope{
+		APIVersion: "1",
+		Data:       data,
+		Metadata:   metadata(c, nextPageToken, totalSize),
+	})
+}
+
+func writeMessage(c echo.Context, code int, m proto.Message) error {
+	byt, err := protoJSON.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONBlob(code, byt)
+}
+
+// Error responds with the JSON encoded error e. The legacy shape is
+// {"error": e}, which the envelope extends.
+func Error(c echo.Context, code int, e json.RawMessage) error {
+	if ShapeOf(c) == Legacy {
+		return c.JSON(code, echo.Map{"error": e})
+	}
+
+	return c.JSON(code, &Envelope{
+		APIVersion: "1",
+		Metadata:   metadata(c, "", nil),
+		Error:      e,
+	})
+}
+
+func metadata(c echo.Context, nextPageToken string, totalSize *int64) *Metadata {
+	m := &Metadata{
+		NextPageToken: nextPageToken,
+		TotalSize:     totalSize,
+		RequestID:     reqid.FromContext(c.Request().Context()),
+	}
+	if *m == (Metadata{}) {
+		return nil
+	}
+	return m
+}
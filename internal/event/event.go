@@ -0,0 +1,68 @@
+// Package event publishes business card lifecycle events to downstream
+// systems such as badge printing and the staff directory.
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Type string
+
+const (
+	TypeCreated   Type = "CREATED"
+	TypeApproved  Type = "APPROVED"
+	TypeRejected  Type = "REJECTED"
+	TypePublished Type = "PUBLISHED"
+
+	// TypeRevoked is emitted when a published card stops being public.
+	TypeRevoked Type = "REVOKED"
+)
+
+type Event struct {
+	ID         string    `json:"id"`
+	Type       Type      `json:"type"`
+	CardID     string    `json:"cardId"`
+	PublicID   string    `json:"publicId,omitempty"`
+	EmployeeID int64     `json:"employeeId"`
+	Status     string    `json:"status"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurredAt"`
+	// RequestID is the ID of the request that caused the event, see
+	// package reqid.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Publisher delivers events to a message broker. Implementations must be
+// safe for concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, e *Event) error
+}
+
+// LogPublisher writes events to the log instead of a broker. It is used when
+// no broker is configured.
+type LogPublisher struct {
+	zlog *zap.Logger
+}
+
+func NewLogPublisher(zlog *zap.Logger) (*LogPublisher, error) {
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &LogPublisher{zlog: zlog}, nil
+}
+
+func (p *LogPublisher) Publish(_ context.Context, e *Event) error {
+	byt, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	p.zlog.Info("event", zap.String("type", string(e.Type)), zap.ByteString("event", byt))
+	return nil
+}
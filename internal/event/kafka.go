@@ -0,0 +1,78 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka publishes events as JSON to a Kafka topic. Events are keyed by card
+// ID so all events of a card land on the same partition, in order.
+type Kafka struct {
+	w       *kafka.Writer
+	brokers []string
+}
+
+func NewKafka(brokers []string, topic string) (*Kafka, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("brokers is empty")
+	}
+	if topic == "" {
+		return nil, errors.New("topic is empty")
+	}
+
+	return &Kafka{
+		w: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+		brokers: brokers,
+	}, nil
+}
+
+func (k *Kafka) Publish(ctx context.Context, e *Event) error {
+	byt, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	err = k.w.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(e.CardID),
+		Value: byt,
+		Headers: []kafka.Header{
+			{Key: "type", Value: []byte(e.Type)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	return nil
+}
+
+// Ping connects to the brokers and succeeds once any of them accepts.
+func (k *Kafka) Ping(ctx context.Context) error {
+	var errs []error
+	for _, broker := range k.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return conn.Close()
+	}
+
+	return fmt.Errorf("failed to reach brokers: %w", errors.Join(errs...))
+}
+
+// Close flushes pending messages and closes the connection to the brokers.
+func (k *Kafka) Close() error {
+	return k.w.Close()
+}
@@ -0,0 +1,217 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// relayBatch is the number of events the relay publishes per round.
+const relayBatch = 100
+
+const (
+	// relayLease is how long a replica relays before another may take
+	// over. The relay renews it before each event.
+	relayLease = 2 * time.Minute
+
+	// publishTimeout bounds the delivery of an event to one subscriber,
+	// so the subscribers of an event are done well within the lease.
+	publishTimeout = 30 * time.Second
+)
+
+// Subscriber is a Publisher the outbox relays events to. Name records the
+// events it received, so it must stay the same across releases.
+type Subscriber struct {
+	Name      string
+	Publisher Publisher
+}
+
+// Outbox stores events in the same transaction as the change that caused
+// them and relays them to its subscribers afterwards, so an event is never
+// lost when the process dies between commit and publish.
+//
+// Delivery is recorded per subscriber, so an event one subscriber rejects
+// is retried for that one only. It is still at least once: a crash after
+// publishing but before recording it publishes the event again. Consumers
+// deduplicate by Event.ID.
+type Outbox struct {
+	db          *sql.DB
+	subscribers []Subscriber
+	zlog        *zap.Logger
+
+	// holder names this replica in the relay lease.
+	holder string
+}
+
+func NewOutbox(_ context.Context, db *sql.DB, subscribers []Subscriber, zlog *zap.Logger) (*Outbox, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if len(subscribers) == 0 {
+		return nil, errors.New("subscribers are empty")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	names := make(map[string]bool, len(subscribers))
+	for _, s := range subscribers {
+		if s.Name == "" || len(s.Name) > 32 {
+			return nil, fmt.Errorf("subscriber name %q must be 1 to 32 characters", s.Name)
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("subscriber %q is duplicated", s.Name)
+		}
+		if s.Publisher == nil {
+			return nil, fmt.Errorf("publisher of subscriber %q is nil", s.Name)
+		}
+		names[s.Name] = true
+	}
+
+	return &Outbox{
+		db:          db,
+		subscribers: subscribers,
+		zlog:        zlog,
+		holder:      uuid.NewString(),
+	}, nil
+}
+
+// Enqueue stores e in tx. It is published once tx commits.
+func (o *Outbox) Enqueue(ctx context.Context, tx *sql.Tx, e *Event) error {
+	return createOutboxEntry(ctx, tx, e)
+}
+
+// Relay publishes pending events in the order they were stored and returns
+// how many were published to every subscriber. It stops at the first event
+// a subscriber rejects so later events of the same card are not delivered
+// out of order. It publishes nothing while another replica holds the relay
+// lease.
+//
+// No transaction is held while publishing: each delivery is recorded as it
+// succeeds.
+func (o *Outbox) Relay(ctx context.Context) (int, error) {
+	// One replica relays at a time, so events leave in the order they were
+	// stored. The others find the lease taken and leave it to that one.
+	ok, err := acquireRelayLease(ctx, o.db, o.holder, relayLease)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, nil
+	}
+
+	pending, err := listPendingEntries(ctx, o.db, relayBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var sent int
+	for i, p := range pending {
+		if i > 0 {
+			ok, err := acquireRelayLease(ctx, o.db, o.holder, relayLease)
+			if err != nil {
+				return sent, err
+			}
+			if !ok {
+				return sent, errors.New("relay lease taken over by another replica")
+			}
+		}
+
+		if err := o.deliver(ctx, p); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// deliver publishes the entry to the subscribers it has not reached yet and
+// marks it sent once it reached them all.
+func (o *Outbox) deliver(ctx context.Context, p *outboxEntry) error {
+	for _, s := range o.subscribers {
+		if p.delivered[s.Name] {
+			continue
+		}
+
+		pctx, cancel := context.WithTimeout(ctx, publishTimeout)
+		err := s.Publisher.Publish(pctx, p.event)
+		cancel()
+		if err != nil {
+			err = fmt.Errorf("%s: %w", s.Name, err)
+			if ferr := failOutboxEntry(ctx, o.db, p.seq, err); ferr != nil {
+				o.zlog.Error("failed to record outbox failure", zap.Int64("seq", p.seq), zap.Error(ferr))
+			}
+			return err
+		}
+
+		if err := createOutboxDelivery(ctx, o.db, p.seq, s.Name, time.Now()); err != nil {
+			return err
+		}
+		p.delivered[s.Name] = true
+	}
+
+	return markOutboxEntrySent(ctx, o.db, p.seq, time.Now())
+}
+
+// RunRelay relays pending events every interval until ctx is done. A full
+// batch is followed immediately by the next one to drain a backlog quickly.
+// The relay lease is released on the way out, so another replica takes
+// over without waiting for it to expire.
+func (o *Outbox) RunRelay(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := releaseRelayLease(rctx, o.db, o.holder); err != nil {
+				o.zlog.Warn("failed to release relay lease", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			n, err := o.Relay(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				o.zlog.Error("failed to relay events", zap.Int("published", n), zap.Error(err))
+			}
+			if err != nil || n < relayBatch {
+				break
+			}
+		}
+	}
+}
+
+// stuckAfter is how long an event may wait in the outbox before Check
+// reports the relay as stuck.
+const stuckAfter = 5 * time.Minute
+
+// Check reports an error when the oldest pending event has waited longer
+// than the relay should take, with the error its last publish failed with.
+func (o *Outbox) Check(ctx context.Context) error {
+	oldest, err := oldestPendingEntry(ctx, o.db)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if age := time.Since(oldest.createdAt); age > stuckAfter {
+		if oldest.lastError != "" {
+			return fmt.Errorf("event %d pending for %s: %s", oldest.seq, age.Round(time.Second), oldest.lastError)
+		}
+		return fmt.Errorf("event %d pending for %s", oldest.seq, age.Round(time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// outboxDB is a database of the statements the relay runs, keeping the
+// outbox in memory. It has no transactions, so a relay that opens one
+// fails.
+type outboxDB struct {
+	mu        sync.Mutex
+	entries   []*outboxRow
+	delivered map[int64][]string
+
+	holder    string
+	expiresAt time.Time
+}
+
+type outboxRow struct {
+	seq       int64
+	payload   string
+	published bool
+	attempts  int
+	lastError string
+}
+
+func newOutboxDB(events ...*Event) *outboxDB {
+	d := &outboxDB{delivered: make(map[int64][]string)}
+	for i, e := range events {
+		payload, _ := json.Marshal(e)
+		d.entries = append(d.entries, &outboxRow{seq: int64(i + 1), payload: string(payload)})
+	}
+	return d
+}
+
+func (d *outboxDB) Connect(context.Context) (driver.Conn, error) { return &outboxConn{d}, nil }
+func (d *outboxDB) Driver() driver.Driver                        { return nil }
+
+// expire ends the relay lease, as if its holder died.
+func (d *outboxDB) expire() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.expiresAt = time.Time{}
+}
+
+type outboxConn struct {
+	db *outboxDB
+}
+
+func (c *outboxConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *outboxConn) Close() error                        { return nil }
+
+func (c *outboxConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("the relay must not hold a transaction")
+}
+
+func (c *outboxConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT TOP 100 seq, payload FROM dbo.event_outbox WITH (READCOMMITTEDLOCK) WHERE published_at IS NULL ORDER BY seq"):
+		rows := &outboxRows{}
+		for _, e := range c.db.entries {
+			if !e.published {
+				rows.values = append(rows.values, []driver.Value{e.seq, e.payload})
+			}
+		}
+		return rows, nil
+
+	case strings.HasPrefix(query, "SELECT seq, subscriber FROM dbo.event_outbox_delivery WHERE seq IN"):
+		rows := &outboxRows{}
+		for _, a := range args {
+			seq := a.Value.(int64)
+			for _, s := range c.db.delivered[seq] {
+				rows.values = append(rows.values, []driver.Value{seq, s})
+			}
+		}
+		return rows, nil
+	}
+
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+func (c *outboxConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "UPDATE dbo.event_outbox_lease SET holder = @p1, expires_at = DATEADD(SECOND, @p2, CURRENT_TIMESTAMP) WHERE id = @p3 AND (holder = @p4 OR expires_at < CURRENT_TIMESTAMP)"):
+		holder := args[0].Value.(string)
+		now := time.Now()
+		if c.db.holder != holder && !c.db.expiresAt.Before(now) {
+			return driver.RowsAffected(0), nil
+		}
+		c.db.holder = holder
+		c.db.expiresAt = now.Add(time.Duration(args[1].Value.(int64)) * time.Second)
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "INSERT INTO dbo.event_outbox_delivery (seq,subscriber,delivered_at)"):
+		seq, subscriber := args[0].Value.(int64), args[1].Value.(string)
+		if slices.Contains(c.db.delivered[seq], subscriber) {
+			return nil, fmt.Errorf("delivery of %d to %s recorded twice", seq, subscriber)
+		}
+		c.db.delivered[seq] = append(c.db.delivered[seq], subscriber)
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "UPDATE dbo.event_outbox SET published_at = @p1, attempts = attempts + 1 WHERE seq = @p2"):
+		e := c.db.entry(args[1].Value.(int64))
+		e.published = true
+		e.attempts++
+		return driver.RowsAffected(1), nil
+
+	case strings.HasPrefix(query, "UPDATE dbo.event_outbox SET attempts = attempts + 1, last_error = @p1 WHERE seq = @p2"):
+		e := c.db.entry(args[1].Value.(int64))
+		e.attempts++
+		e.lastError = args[0].Value.(string)
+		return driver.RowsAffected(1), nil
+	}
+
+	return nil, fmt.Errorf("unexpected statement %q", query)
+}
+
+func (d *outboxDB) entry(seq int64) *outboxRow {
+	return d.entries[seq-1]
+}
+
+type outboxRows struct {
+	values [][]driver.Value
+}
+
+func (r *outboxRows) Columns() []string { return []string{"", ""} }
+func (r *outboxRows) Close() error      { return nil }
+
+func (r *outboxRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// recorder is a Publisher recording the events it received, failing while
+// err is set.
+type recorder struct {
+	mu  sync.Mutex
+	ids []string
+	err error
+}
+
+func (r *recorder) Publish(_ context.Context, e *Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	r.ids = append(r.ids, e.ID)
+	return nil
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.ids)
+}
+
+func newTestOutbox(t *testing.T, db *sql.DB, subscribers ...Subscriber) *Outbox {
+	t.Helper()
+
+	o, err := NewOutbox(context.Background(), db, subscribers, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+	return o
+}
+
+func TestRelayRetriesOnlyFailedSubscribers(t *testing.T) {
+	outbox := newOutboxDB(&Event{ID: "e1"}, &Event{ID: "e2"})
+	db := sql.OpenDB(outbox)
+	defer db.Close()
+
+	broker := &recorder{}
+	webhook := &recorder{err: errors.New("connection refused")}
+	o := newTestOutbox(t, db, Subscriber{Name: "broker", Publisher: broker}, Subscriber{Name: "webhook", Publisher: webhook})
+
+	n, err := o.Relay(context.Background())
+	if err == nil || n != 0 {
+		t.Fatalf("Relay = %d, %v, want 0 and an error", n, err)
+	}
+	if got := broker.received(); !slices.Equal(got, []string{"e1"}) {
+		t.Fatalf("broker received %v, want [e1]: the relay stops at a rejected event", got)
+	}
+	if e := outbox.entry(1); e.published || !strings.HasPrefix(e.lastError, "webhook: ") {
+		t.Fatalf("entry 1 published %v with last error %q, want pending with the webhook's error", e.published, e.lastError)
+	}
+
+	webhook.err = nil
+	n, err = o.Relay(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("Relay = %d, %v, want 2 and nil", n, err)
+	}
+	if got := broker.received(); !slices.Equal(got, []string{"e1", "e2"}) {
+		t.Fatalf("broker received %v, want [e1 e2] without e1 again", got)
+	}
+	if got := webhook.received(); !slices.Equal(got, []string{"e1", "e2"}) {
+		t.Fatalf("webhook received %v, want [e1 e2]", got)
+	}
+	for _, e := range outbox.entries {
+		if !e.published {
+			t.Fatalf("entry %d pending, want it published", e.seq)
+		}
+	}
+
+	if n, err := o.Relay(context.Background()); err != nil || n != 0 {
+		t.Fatalf("Relay of an empty outbox = %d, %v, want 0 and nil", n, err)
+	}
+}
+
+func TestRelayOneReplicaAtATime(t *testing.T) {
+	outbox := newOutboxDB(&Event{ID: "e1"})
+	db := sql.OpenDB(outbox)
+	defer db.Close()
+
+	// The first replica holds the lease but fails to publish.
+	first := &recorder{err: errors.New("connection refused")}
+	a := newTestOutbox(t, db, Subscriber{Name: "broker", Publisher: first})
+	if _, err := a.Relay(context.Background()); err == nil {
+		t.Fatal("Relay succeeded, want an error")
+	}
+
+	second := &recorder{}
+	b := newTestOutbox(t, db, Subscriber{Name: "broker", Publisher: second})
+	if n, err := b.Relay(context.Background()); err != nil || n != 0 {
+		t.Fatalf("Relay while another replica holds the lease = %d, %v, want 0 and nil", n, err)
+	}
+
+	// The first replica dies; once its lease expires the second takes over.
+	outbox.expire()
+	if n, err := b.Relay(context.Background()); err != nil || n != 1 {
+		t.Fatalf("Relay after the lease expired = %d, %v, want 1 and nil", n, err)
+	}
+	if got := second.received(); !slices.Equal(got, []string{"e1"}) {
+		t.Fatalf("second replica published %v, want [e1]", got)
+	}
+}
+
+func TestNewOutboxRejectsDuplicateSubscribers(t *testing.T) {
+	db := sql.OpenDB(newOutboxDB())
+	defer db.Close()
+
+	_, err := NewOutbox(context.Background(), db, []Subscriber{
+		{Name: "broker", Publisher: &recorder{}},
+		{Name: "broker", Publisher: &recorder{}},
+	}, zap.NewNop())
+	if err == nil {
+		t.Fatal("NewOutbox with duplicate subscribers succeeded, want an error")
+	}
+}
@@ -0,0 +1,281 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type outboxEntry struct {
+	seq   int64
+	event *Event
+
+	// delivered are the names of the subscribers the event reached.
+	delivered map[string]bool
+}
+
+func createOutboxEntry(ctx context.Context, tx *sql.Tx, in *Event) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	q, args := sq.
+		Insert("dbo.event_outbox").
+		Columns(
+			"id",
+			"type",
+			"card_id",
+			"payload",
+			"created_at",
+		).
+		Values(
+			in.ID,
+			in.Type,
+			in.CardID,
+			string(payload),
+			in.OccurredAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// acquireRelayLease makes holder the only relay for ttl, or extends its
+// lease. It reports false when another replica holds an unexpired lease.
+func acquireRelayLease(ctx context.Context, db *sql.DB, holder string, ttl time.Duration) (bool, error) {
+	q, args := sq.
+		Update("dbo.event_outbox_lease").
+		Set("holder", holder).
+		Set("expires_at", sq.Expr("DATEADD(SECOND, ?, CURRENT_TIMESTAMP)", int(ttl.Seconds()))).
+		Where(sq.Eq{"id": 1}).
+		Where(sq.Or{
+			sq.Eq{"holder": holder},
+			sq.Expr("expires_at < CURRENT_TIMESTAMP"),
+		}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire relay lease: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire relay lease: %w", err)
+	}
+
+	return n == 1, nil
+}
+
+// releaseRelayLease ends the lease of holder, so another replica need not
+// wait for it to expire.
+func releaseRelayLease(ctx context.Context, db *sql.DB, holder string) error {
+	q, args := sq.
+		Update("dbo.event_outbox_lease").
+		Set("expires_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{
+			"id":     1,
+			"holder": holder,
+		}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to release relay lease: %w", err)
+	}
+
+	return nil
+}
+
+// listPendingEntries returns the first pending entries in order, with the
+// subscribers each was delivered to. It waits for transactions still
+// storing entries rather than skip them, so an entry is never relayed
+// before one stored ahead of it.
+func listPendingEntries(ctx context.Context, db *sql.DB, limit uint64) ([]*outboxEntry, error) {
+	q, args := sq.
+		Select(
+			fmt.Sprintf("TOP %d seq", limit),
+			"payload",
+		).
+		From("dbo.event_outbox WITH (READCOMMITTEDLOCK)").
+		Where("published_at IS NULL").
+		OrderBy("seq").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*outboxEntry, 0)
+	for rows.Next() {
+		e := outboxEntry{delivered: make(map[string]bool)}
+		var payload string
+		if err := rows.Scan(&e.seq, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		e.event = new(Event)
+		if err := json.Unmarshal([]byte(payload), e.event); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox entry %d: %w", e.seq, err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	if err := listOutboxDeliveries(ctx, db, entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// listOutboxDeliveries fills in the subscribers entries were delivered to.
+func listOutboxDeliveries(ctx context.Context, db *sql.DB, entries []*outboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	bySeq := make(map[int64]*outboxEntry, len(entries))
+	seqs := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		bySeq[e.seq] = e
+		seqs = append(seqs, e.seq)
+	}
+
+	q, args := sq.
+		Select(
+			"seq",
+			"subscriber",
+		).
+		From("dbo.event_outbox_delivery").
+		Where(sq.Eq{"seq": seqs}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seq int64
+		var subscriber string
+		if err := rows.Scan(&seq, &subscriber); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if e, ok := bySeq[seq]; ok {
+			e.delivered[subscriber] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+// createOutboxDelivery records that the entry was delivered to subscriber,
+// so a retry of the entry skips it.
+func createOutboxDelivery(ctx context.Context, db *sql.DB, seq int64, subscriber string, at time.Time) error {
+	q, args := sq.
+		Insert("dbo.event_outbox_delivery").
+		Columns(
+			"seq",
+			"subscriber",
+			"delivered_at",
+		).
+		Values(
+			seq,
+			subscriber,
+			at,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create outbox delivery: %w", err)
+	}
+
+	return nil
+}
+
+func markOutboxEntrySent(ctx context.Context, db *sql.DB, seq int64, at time.Time) error {
+	q, args := sq.
+		Update("dbo.event_outbox").
+		Set("published_at", at).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Eq{"seq": seq}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func failOutboxEntry(ctx context.Context, db *sql.DB, seq int64, cause error) error {
+	msg := []rune(cause.Error())
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+
+	q, args := sq.
+		Update("dbo.event_outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", string(msg)).
+		Where(sq.Eq{"seq": seq}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+type pendingEntry struct {
+	seq       int64
+	createdAt time.Time
+	lastError string
+}
+
+func oldestPendingEntry(ctx context.Context, db *sql.DB) (*pendingEntry, error) {
+	q, args := sq.
+		Select(
+			"TOP 1 seq",
+			"created_at",
+			"last_error",
+		).
+		From("dbo.event_outbox").
+		Where("published_at IS NULL").
+		OrderBy("seq").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var e pendingEntry
+	err := db.QueryRowContext(ctx, q, args...).Scan(&e.seq, &e.createdAt, &e.lastError)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
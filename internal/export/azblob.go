@@ -0,0 +1,83 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// azureVersion is the Blob service API version uploads are made with.
+const azureVersion = "2021-08-06"
+
+type azureBlobDestination struct {
+	u         *url.URL
+	container string
+	prefix    string
+	sas       string
+}
+
+func newAzureBlob(u *url.URL) (*azureBlobDestination, error) {
+	if u.Host == "" {
+		return nil, errors.New("azblob destination has no account")
+	}
+	container, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if container == "" {
+		return nil, errors.New("azblob destination has no container")
+	}
+	if u.Query().Get("sig") == "" {
+		return nil, errors.New("azblob destination has no SAS token")
+	}
+
+	return &azureBlobDestination{
+		u:         u,
+		container: container,
+		prefix:    prefix,
+		sas:       u.RawQuery,
+	}, nil
+}
+
+func (d *azureBlobDestination) Put(ctx context.Context, name string, r io.Reader, size int64, sum string) error {
+	blob := &url.URL{
+		Scheme:   "https",
+		Host:     d.u.Host + ".blob.core.windows.net",
+		Path:     "/" + path.Join(d.container, d.prefix, name),
+		RawQuery: d.sas,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, blob.String(), io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", azureVersion)
+	req.Header.Set("X-Ms-Meta-Sha256", sum)
+
+	return do(req)
+}
+
+func (d *azureBlobDestination) String() string {
+	return redact(d.u)
+}
+
+// do sends an upload request and turns a non-2xx response into an error.
+func do(req *http.Request) error {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to upload: %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package export delivers recurring exports of cards, scan analytics and
+// the audit log to BI systems. A scheduled job writes the export to a
+// temporary file, uploads it with a SHA-256 checksum file to an SFTP server
+// or an S3 or Azure Blob bucket, and records the delivery.
+//
+// It also writes tables, e.g. the card directory HR downloads, as CSV or
+// XLSX files.
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// Source writes one export as newline-delimited JSON. Incremental sources,
+// such as scans, write the records of [from, to); from is zero on the first
+// delivery. Snapshot sources ignore both.
+type Source func(ctx context.Context, w io.Writer, from, to time.Time) error
+
+// Config is a recurring export, as configured by admins.
+type Config struct {
+	// Name identifies the export in file names and delivery records.
+	Name string `json:"name"`
+
+	// Source is the registered source exported, e.g. cards.
+	Source string `json:"source"`
+
+	// Schedule is a cron spec such as "0 2 1 * *" or "@monthly".
+	Schedule string `json:"schedule"`
+
+	// Destination is where files are delivered, see ParseDestination.
+	Destination string `json:"destination"`
+}
+
+type Exporter struct {
+	db   *sql.DB
+	zlog *zap.Logger
+
+	// creds reach the S3 destinations.
+	creds aws.Credentials
+
+	sources map[string]Source
+}
+
+func New(_ context.Context, db *sql.DB, creds aws.Credentials, zlog *zap.Logger) (*Exporter, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Exporter{
+		db:      db,
+		zlog:    zlog,
+		creds:   creds,
+		sources: make(map[string]Source),
+	}, nil
+}
+
+// AddSource registers a source exports may name.
+func (e *Exporter) AddSource(name string, src Source) error {
+	if name == "" {
+		return errors.New("source name is empty")
+	}
+	if src == nil {
+		return errors.New("source is nil")
+	}
+	if _, ok := e.sources[name]; ok {
+		return fmt.Errorf("source %s is already registered", name)
+	}
+
+	e.sources[name] = src
+	return nil
+}
+
+// Job returns the run func of the scheduled job delivering cfg.
+func (e *Exporter) Job(cfg *Config) (func(ctx context.Context) error, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("export name is empty")
+	}
+	src, ok := e.sources[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("export %s has unknown source %q", cfg.Name, cfg.Source)
+	}
+	dest, err := ParseDestination(cfg.Destination, e.creds)
+	if err != nil {
+		return nil, fmt.Errorf("export %s: %w", cfg.Name, err)
+	}
+
+	return func(ctx context.Context) error {
+		return e.deliver(ctx, cfg, src, dest)
+	}, nil
+}
+
+func (e *Exporter) deliver(ctx context.Context, cfg *Config, src Source, dest Destination) error {
+	zlog := e.zlog.With(
+		zap.String("method", "deliver"),
+		zap.String("export", cfg.Name),
+		zap.String("destination", dest.String()),
+	)
+
+	from, err := lastDelivered(ctx, e.db, cfg.Name)
+	if err != nil {
+		zlog.Error("failed to get last delivery", zap.Error(err))
+		return err
+	}
+
+	d := &Delivery{
+		Export:      cfg.Name,
+		Source:      cfg.Source,
+		Destination: dest.String(),
+		Status:      StatusRunning,
+		StartedAt:   time.Now(),
+	}
+	d.FileName = fmt.Sprintf("%s-%s.ndjson", cfg.Name, d.StartedAt.UTC().Format("20060102T150405Z"))
+	if err := createDelivery(ctx, e.db, d); err != nil {
+		zlog.Error("failed to create delivery", zap.Error(err))
+		return err
+	}
+
+	err = e.upload(ctx, d, src, dest, from)
+	d.Status = StatusDelivered
+	if err != nil {
+		d.Status = StatusFailed
+		d.Error = err.Error()
+		zlog.Error("failed to deliver export", zap.Error(err))
+	}
+
+	now := time.Now()
+	d.FinishedAt = &now
+	// The record is kept even when the job's context is done, so a timed
+	// out delivery shows as failed rather than running.
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if ferr := finishDelivery(rctx, e.db, d); ferr != nil {
+		zlog.Error("failed to finish delivery", zap.Error(ferr))
+	}
+	if err != nil {
+		return err
+	}
+
+	zlog.Info("delivered export",
+		zap.String("file", d.FileName),
+		zap.Int64("size", d.SizeBytes),
+		zap.String("sha256", d.SHA256),
+	)
+	return nil
+}
+
+// upload writes the export to a temporary file, hashing it on the way, then
+// sends it followed by its checksum file in sha256sum format. Collectors
+// take a file once its checksum file is there.
+func (e *Exporter) upload(ctx context.Context, d *Delivery, src Source, dest Destination, from time.Time) error {
+	f, err := os.CreateTemp("", "export-*.ndjson")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	cw := &countWriter{w: io.MultiWriter(f, h)}
+	if err := src(ctx, cw, from, d.StartedAt); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	d.SizeBytes = cw.n
+	d.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := dest.Put(ctx, d.FileName, f, d.SizeBytes, d.SHA256); err != nil {
+		return err
+	}
+
+	sumFile := []byte(d.SHA256 + "  " + d.FileName + "\n")
+	sumHash := sha256.Sum256(sumFile)
+	return dest.Put(ctx, d.FileName+".sha256", bytes.NewReader(sumFile), int64(len(sumFile)), hex.EncodeToString(sumHash[:]))
+}
+
+const (
+	StatusRunning   = "RUNNING"
+	StatusDelivered = "DELIVERED"
+	StatusFailed    = "FAILED"
+)
+
+type Delivery struct {
+	ID          int64      `json:"id"`
+	Export      string     `json:"export"`
+	Source      string     `json:"source"`
+	Destination string     `json:"destination"`
+	FileName    string     `json:"fileName"`
+	SizeBytes   int64      `json:"sizeBytes"`
+	SHA256      string     `json:"sha256"`
+	Status      string     `json:"status"` // RUNNING, DELIVERED or FAILED.
+	Error       string     `json:"error"`
+	StartedAt   time.Time  `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt"`
+}
+
+type DeliveryQuery struct {
+	Export    string `json:"export" query:"export"`
+	Status    string `json:"status" query:"status"`
+	PageToken string `json:"pageToken" query:"pageToken"`
+	PageSize  uint64 `json:"pageSize" query:"pageSize"`
+}
+
+type ListDeliveriesResult struct {
+	Deliveries    []*Delivery `json:"deliveries"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// ListDeliveries lists export deliveries, newest first. It is for HR only.
+func (e *Exporter) ListDeliveries(ctx context.Context, in *DeliveryQuery) (*ListDeliveriesResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := e.zlog.With(
+		zap.String("method", "ListDeliveries"),
+		zap.String("username", claims.Code),
+		zap.Any("req", in),
+	)
+
+	if !rbac.Can(ctx, rbac.ReadExports) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.ExportsForbidden)
+	}
+
+	deliveries, err := listDeliveries(ctx, e.db, in)
+	if err != nil {
+		zlog.Error("failed to list deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	var pageToken string
+	if l := len(deliveries); l > 0 && l == int(pager.Size(in.PageSize)) {
+		last := deliveries[l-1]
+		pageToken = pager.EncodeCursor(&pager.Cursor{
+			ID:   fmt.Sprint(last.ID),
+			Time: last.StartedAt,
+		})
+	}
+
+	return &ListDeliveriesResult{
+		Deliveries:    deliveries,
+		NextPageToken: pageToken,
+	}, nil
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+type s3Destination struct {
+	u      *url.URL
+	bucket *storage.S3
+}
+
+func newS3(u *url.URL, creds aws.Credentials) (*s3Destination, error) {
+	if u.Host == "" {
+		return nil, errors.New("s3 destination has no bucket")
+	}
+
+	q := u.Query()
+	if q.Get("region") == "" {
+		return nil, errors.New("s3 destination has no region")
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("s3 destination has no credentials")
+	}
+
+	bucket, err := storage.NewS3(storage.S3Config{
+		Bucket:      u.Host,
+		Region:      q.Get("region"),
+		Prefix:      u.Path,
+		Endpoint:    q.Get("endpoint"),
+		Credentials: creds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Destination{
+		u:      u,
+		bucket: bucket,
+	}, nil
+}
+
+func (d *s3Destination) Put(ctx context.Context, name string, r io.Reader, size int64, sum string) error {
+	return d.bucket.Upload(ctx, name, r, size, sum)
+}
+
+func (d *s3Destination) String() string {
+	return redact(d.u)
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const sftpDialTimeout = 30 * time.Second
+
+type sftpDestination struct {
+	u      *url.URL
+	addr   string
+	dir    string
+	config *ssh.ClientConfig
+}
+
+func newSFTP(u *url.URL) (*sftpDestination, error) {
+	if u.User == nil || u.User.Username() == "" {
+		return nil, errors.New("sftp destination has no user")
+	}
+
+	q := u.Query()
+	if q.Get("known_hosts") == "" {
+		return nil, errors.New("sftp destination has no known_hosts file")
+	}
+	hostKey, err := knownhosts.New(q.Get("known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if keyFile := q.Get("key"); keyFile != "" {
+		pem, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sftp key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sftp key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password, ok := u.User.Password(); ok {
+		auth = append(auth, ssh.Password(password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("sftp destination has neither a key nor a password")
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	return &sftpDestination{
+		u:    u,
+		addr: addr,
+		dir:  u.Path,
+		config: &ssh.ClientConfig{
+			User:            u.User.Username(),
+			Auth:            auth,
+			HostKeyCallback: hostKey,
+			Timeout:         sftpDialTimeout,
+		},
+	}, nil
+}
+
+// Put uploads the file under a temporary name and renames it once
+// complete, so collectors polling the directory never pick up part of it.
+func (d *sftpDestination) Put(ctx context.Context, name string, r io.Reader, _ int64, _ string) error {
+	conn, err := ssh.Dial("tcp", d.addr, d.config)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	// Closing the connection aborts a transfer still running when ctx is
+	// done.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return fmt.Errorf("failed to start sftp: %w", err)
+	}
+	defer client.Close()
+
+	if d.dir != "" {
+		if err := client.MkdirAll(d.dir); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	dst := path.Join(d.dir, name)
+	tmp := dst + ".part"
+	f, err := client.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := f.ReadFrom(r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := client.PosixRename(tmp, dst); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+func (d *sftpDestination) String() string {
+	return redact(d.u)
+}
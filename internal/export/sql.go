@@ -0,0 +1,161 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	sq "github.com/Masterminds/squirrel"
+)
+
+func createDelivery(ctx context.Context, db *sql.DB, in *Delivery) error {
+	q, args := sq.
+		Insert("dbo.export_delivery").
+		Columns(
+			"export_name",
+			"source",
+			"destination",
+			"file_name",
+			"status",
+			"started_at",
+		).
+		Values(
+			in.Export,
+			in.Source,
+			in.Destination,
+			in.FileName,
+			in.Status,
+			in.StartedAt,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if err := db.QueryRowContext(ctx, q, args...).Scan(&in.ID); err != nil {
+		return fmt.Errorf("failed to execute create delivery: %w", err)
+	}
+
+	return nil
+}
+
+func finishDelivery(ctx context.Context, db *sql.DB, in *Delivery) error {
+	msg := []rune(in.Error)
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+
+	q, args := sq.
+		Update("dbo.export_delivery").
+		Set("status", in.Status).
+		Set("error", string(msg)).
+		Set("size_bytes", in.SizeBytes).
+		Set("sha256", in.SHA256).
+		Set("finished_at", in.FinishedAt).
+		Where(sq.Eq{"id": in.ID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute update delivery: %w", err)
+	}
+
+	return nil
+}
+
+// lastDelivered returns when the last successful delivery of the export
+// started, or the zero time if there has been none.
+func lastDelivered(ctx context.Context, db *sql.DB, name string) (time.Time, error) {
+	q, args := sq.
+		Select("TOP 1 started_at").
+		From("dbo.export_delivery").
+		Where(sq.Eq{
+			"export_name": name,
+			"status":      StatusDelivered,
+		}).
+		OrderBy("started_at DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var t time.Time
+	err := db.QueryRowContext(ctx, q, args...).Scan(&t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return t, nil
+}
+
+func listDeliveries(ctx context.Context, db *sql.DB, in *DeliveryQuery) ([]*Delivery, error) {
+	and := sq.And{}
+	if in.Export != "" {
+		and = append(and, sq.Eq{"export_name": in.Export})
+	}
+	if in.Status != "" {
+		and = append(and, sq.Eq{"status": in.Status})
+	}
+	if in.PageToken != "" {
+		cursor, err := pager.DecodeCursor(in.PageToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+		and = append(and, cursor.After("started_at", "id"))
+	}
+
+	q, args := sq.
+		Select(
+			fmt.Sprintf("TOP %d id", pager.Size(in.PageSize)),
+			"export_name",
+			"source",
+			"destination",
+			"file_name",
+			"size_bytes",
+			"sha256",
+			"status",
+			"error",
+			"started_at",
+			"finished_at",
+		).
+		From("dbo.export_delivery").
+		Where(and).
+		OrderBy("started_at DESC", "id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*Delivery, 0)
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.Export,
+			&d.Source,
+			&d.Destination,
+			&d.FileName,
+			&d.SizeBytes,
+			&d.SHA256,
+			&d.Status,
+			&d.Error,
+			&d.StartedAt,
+			&d.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Format is the file format of a table export.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// ParseFormat returns the format named s.
+func ParseFormat(s string) (Format, bool) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case CSV, XLSX:
+		return f, true
+	}
+	return "", false
+}
+
+// ContentType returns the media type of files in the format.
+func (f Format) ContentType() string {
+	if f == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Column is a column of a table export of Ts.
+type Column[T any] struct {
+	// Name heads the column and selects it, e.g. displayName.
+	Name  string
+	Value func(T) string
+}
+
+// SelectColumns returns the columns of all named in names, in that order,
+// or all of them without names. It returns the first unknown name.
+func SelectColumns[T any](all []Column[T], names []string) ([]Column[T], string) {
+	if len(names) == 0 {
+		return all, ""
+	}
+
+	selected := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(c Column[T]) bool { return c.Name == name })
+		if i < 0 {
+			return nil, name
+		}
+		selected = append(selected, all[i])
+	}
+	return selected, ""
+}
+
+// Table writes the rows of a table export as they are read, so exports of
+// any size are streamed rather than buffered.
+type Table[T any] struct {
+	columns []Column[T]
+	w       rowWriter
+}
+
+type rowWriter interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewTable writes a table of columns to w in format f, starting with the
+// header row.
+func NewTable[T any](w io.Writer, f Format, columns []Column[T]) (*Table[T], error) {
+	var rw rowWriter
+	switch f {
+	case CSV:
+		// A BOM makes Excel read the file as UTF-8, keeping Lao and Thai
+		// names intact.
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return nil, err
+		}
+		rw = &csvWriter{w: csv.NewWriter(w)}
+	case XLSX:
+		x, err := newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		rw = x
+	default:
+		return nil, fmt.Errorf("unknown format %q", f)
+	}
+
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.Name)
+	}
+	if err := rw.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &Table[T]{columns: columns, w: rw}, nil
+}
+
+// Write writes v as a row.
+func (t *Table[T]) Write(v T) error {
+	row := make([]string, 0, len(t.columns))
+	for _, c := range t.columns {
+		row = append(row, c.Value(v))
+	}
+	return t.w.Write(row)
+}
+
+// Close completes the file. It does not close the underlying writer.
+func (t *Table[T]) Close() error {
+	return t.w.Close()
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
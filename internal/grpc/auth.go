@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type authServer struct {
+	contactqrPb.UnimplementedAuthServiceServer
+
+	auth *auth.Auth
+
+	// trusted are the proxies trusted to forward the caller's address.
+	trusted []*net.IPNet
+}
+
+func (s *authServer) Login(ctx context.Context, in *contactqrPb.LoginRequest) (*contactqrPb.Token, error) {
+	req := &auth.LoginReq{
+		Username: in.GetUsername(),
+		Password: in.GetPassword(),
+	}
+	req.SetClient(clientInfo(ctx, s.trusted))
+
+	token, err := s.auth.Login(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenProto(token), nil
+}
+
+func (s *authServer) RefreshToken(ctx context.Context, in *contactqrPb.RefreshTokenRequest) (*contactqrPb.Token, error) {
+	token, err := s.auth.RefreshToken(ctx, &auth.NewTokenReq{Token: in.GetToken()})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenProto(token), nil
+}
+
+func (s *authServer) Logout(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	if err := s.auth.Logout(ctx); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func tokenProto(t *auth.Token) *contactqrPb.Token {
+	return &contactqrPb.Token{
+		AccessToken:  t.Access,
+		RefreshToken: t.Refresh,
+	}
+}
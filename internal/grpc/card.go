@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/card"
+)
+
+type cardServer struct {
+	contactqrPb.UnimplementedCardServiceServer
+
+	card *card.Service
+}
+
+func (s *cardServer) CreateBusinessCard(ctx context.Context, in *contactqrPb.BusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.CreateBusinessCard(ctx, &card.CardReq{
+		Phone:              phoneNumber(in.GetPhone()),
+		Mobile:             phoneNumber(in.GetMobile()),
+		PhoneticGivenName:  in.GetPhoneticGivenName(),
+		PhoneticFamilyName: in.GetPhoneticFamilyName(),
+		NameLanguages:      in.GetNameLanguages(),
+		SocialLinks:        socialLinks(in.GetSocialLinks()),
+		IdempotencyKey:     in.GetIdempotencyKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func (s *cardServer) ListMyBusinessCards(ctx context.Context, in *contactqrPb.ListMyBusinessCardsRequest) (*contactqrPb.ListBusinessCardsResponse, error) {
+	cards, err := s.card.ListMyBusinessCards(ctx, &card.CardQuery{
+		PageToken: in.GetPageToken(),
+		PageSize:  in.GetPageSize(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cards.Proto(), nil
+}
+
+func (s *cardServer) GetMyBusinessCard(ctx context.Context, in *contactqrPb.GetBusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.GetMyBusinessCardByID(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func (s *cardServer) GetBusinessCard(ctx context.Context, in *contactqrPb.GetBusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.GetBusinessCardByID(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func (s *cardServer) ApproveBusinessCard(ctx context.Context, in *contactqrPb.ApproveBusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.ApproveBusinessCard(ctx, &card.ApproveBusinessCardReq{ID: in.GetCardId()})
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func (s *cardServer) RejectBusinessCard(ctx context.Context, in *contactqrPb.RejectBusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.RejectBusinessCard(ctx, &card.RejectBusinessCardReq{
+		ID:     in.GetCardId(),
+		Remark: in.GetRemark(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func (s *cardServer) PublishBusinessCard(ctx context.Context, in *contactqrPb.PublishBusinessCardRequest) (*contactqrPb.BusinessCardResponse, error) {
+	c, err := s.card.PublishBusinessCard(ctx, &card.PublishBusinessCardReq{ID: in.GetCardId()})
+	if err != nil {
+		return nil, err
+	}
+
+	return cardResponse(c), nil
+}
+
+func phoneNumber(pb *contactqrPb.PhoneNumber) card.PhoneNumber {
+	return card.PhoneNumber{
+		Country: pb.GetCountry(),
+		Number:  pb.GetNumber(),
+	}
+}
+
+func socialLinks(pb *contactqrPb.SocialLinks) card.SocialLinks {
+	return card.SocialLinks{
+		WhatsApp: pb.GetWhatsapp(),
+		Line:     pb.GetLine(),
+		WeChat:   pb.GetWechat(),
+		LinkedIn: pb.GetLinkedin(),
+		Website:  pb.GetWebsite(),
+	}
+}
+
+func cardResponse(c *card.Card) *contactqrPb.BusinessCardResponse {
+	return &contactqrPb.BusinessCardResponse{BusinessCard: c.Proto()}
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/employee"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type employeeServer struct {
+	contactqrPb.UnimplementedEmployeeServiceServer
+
+	employee *employee.Service
+}
+
+func (s *employeeServer) GetMyPreferences(ctx context.Context, _ *emptypb.Empty) (*contactqrPb.Preferences, error) {
+	p, err := s.employee.GetMyPreferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferencesProto(p), nil
+}
+
+func (s *employeeServer) UpdateMyPreferences(ctx context.Context, in *contactqrPb.PreferencesRequest) (*contactqrPb.Preferences, error) {
+	p, err := s.employee.UpdateMyPreferences(ctx, &employee.PreferencesReq{
+		NotificationLanguage: in.GetNotificationLanguage(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return preferencesProto(p), nil
+}
+
+func preferencesProto(p *employee.Preferences) *contactqrPb.Preferences {
+	return &contactqrPb.Preferences{
+		NotificationLanguage: string(p.NotificationLanguage),
+		UpdateTime:           timestamppb.New(p.UpdatedAt),
+	}
+}
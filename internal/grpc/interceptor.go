@@ -0,0 +1,174 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/breaker"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// public are the methods callers use without an access token.
+var public = map[string]bool{
+	contactqrPb.AuthService_Login_FullMethodName:        true,
+	contactqrPb.AuthService_RefreshToken_FullMethodName: true,
+}
+
+// Intercept does for gRPC calls what the middleware of the HTTP API does
+// for requests: it authenticates the caller, resolves their roles, sets the
+// display timezone, and turns errors into localized statuses.
+func (s *Server) Intercept(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+	lang := i18n.Negotiate(header(ctx, "accept-language"))
+
+	id := header(ctx, strings.ToLower(reqid.Header))
+	if !reqid.Valid(id) {
+		id = reqid.New()
+	}
+	ctx = reqid.ContextWithID(ctx, id)
+	gogrpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(reqid.Header), id))
+
+	resp, err := s.intercept(ctx, req, info, handler)
+	if err == nil {
+		return resp, nil
+	}
+
+	if errors.Is(err, breaker.ErrOpen) {
+		err = i18n.Error(codes.Unavailable, i18n.DBUnavailable)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		reqid.Logger(ctx, s.zlog).Error("unexpected error",
+			zap.String("method", info.FullMethod),
+			zap.Error(err),
+		)
+		st = i18n.Status(codes.Internal, i18n.Internal)
+	}
+	return nil, reqid.Status(ctx, i18n.Localize(st, lang)).Err()
+}
+
+func (s *Server) intercept(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+	loc := s.loc
+	if name := strings.TrimSpace(header(ctx, strings.ToLower(tz.Header))); name != "" {
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, i18n.Error(codes.InvalidArgument, i18n.InvalidTimezone, "timezone", name)
+		}
+		loc = l
+	}
+	ctx = tz.ContextWithLocation(ctx, loc)
+
+	if !public[info.FullMethod] {
+		claims, err := s.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ctx = auth.ContextWithClaims(ctx, claims)
+		ctx = rbac.ContextWithRoles(ctx, s.roles.Resolve(ctx, claims))
+	}
+
+	return handler(ctx, req)
+}
+
+// authenticate returns the claims of the access token in the authorization
+// metadata, "Bearer <token>" as in the HTTP API.
+func (s *Server) authenticate(ctx context.Context) (*auth.Claims, error) {
+	tainted, ok := strings.CutPrefix(header(ctx, "authorization"), "Bearer ")
+	if !ok || tainted == "" {
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidToken)
+	}
+
+	parser := paseto.MakeParser([]paseto.Rule{paseto.NotExpired(), paseto.ValidAt(time.Now())})
+	token, err := s.keys.Parse(parser, tainted, nil)
+	if err != nil {
+		return nil, i18n.Error(codes.Unauthenticated, i18n.InvalidToken)
+	}
+
+	claims := new(auth.Claims)
+	token.Get("profile", claims)
+	return claims, nil
+}
+
+// clientInfo describes where a login call came from: the peer, unless it
+// is the gateway or a trusted proxy. Those forward the caller's address in
+// x-forwarded-for, which is read right to left up to the first address
+// that is not a trusted proxy, as the HTTP API does.
+func clientInfo(ctx context.Context, trusted []*net.IPNet) auth.ClientInfo {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	if addr := net.ParseIP(ip); addr != nil && (addr.IsLoopback() || trusts(trusted, addr)) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		hops := strings.Split(strings.Join(md.Get("x-forwarded-for"), ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			ip = hop.String()
+			if !trusts(trusted, hop) {
+				break
+			}
+		}
+	}
+
+	return auth.ClientInfo{
+		IP:        ip,
+		UserAgent: header(ctx, "user-agent"),
+		DeviceID:  header(ctx, "x-device-id"),
+	}
+}
+
+func trusts(trusted []*net.IPNet, ip net.IP) bool {
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// header returns the first value of the metadata key, or of the HTTP header
+// the gateway forwarded under it.
+func header(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(runtime.MetadataPrefix + key); len(v) > 0 {
+		return v[0]
+	}
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// headerMatcher forwards the headers the HTTP API reads to the gRPC server,
+// besides those the gateway forwards by default.
+func headerMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case tz.Header, "X-Device-Id", "X-Request-Id":
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/10664kls/contactqr/internal/middleware"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientInfoIP(t *testing.T) {
+	trusted, err := middleware.ParseNetworks([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("ParseNetworks: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		peer string
+		xff  string
+		want string
+	}{
+		{"direct", "203.0.113.7:5000", "", "203.0.113.7"},
+		{"direct with a spoofed address", "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		// The gateway appends the address the HTTP request came from.
+		{"gateway", "127.0.0.1:5000", "198.51.100.1, 203.0.113.7", "203.0.113.7"},
+		{"gateway behind a trusted proxy", "127.0.0.1:5000", "198.51.100.1, 203.0.113.7, 10.0.0.2", "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:5000", "203.0.113.7", "203.0.113.7"},
+		{"trusted proxy without an address", "10.0.0.2:5000", "", "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatalf("ResolveTCPAddr: %v", err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.xff != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", tt.xff))
+			}
+
+			if got := clientInfo(ctx, trusted).IP; got != tt.want {
+				t.Errorf("clientInfo IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
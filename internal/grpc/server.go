@@ -0,0 +1,97 @@
+// Package grpc serves the gRPC API. It calls the same services as the HTTP
+// API of package server, and the gateway generated from the protos serves
+// it over HTTP/JSON as well, so both protocols share one service layer.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/card"
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Server struct {
+	auth     *auth.Auth
+	card     *card.Service
+	employee *employee.Service
+
+	keys    *auth.KeyRing
+	roles   *rbac.Resolver
+	trusted []*net.IPNet
+	loc     *time.Location
+	zlog    *zap.Logger
+}
+
+// NewServer returns the gRPC API of the services. Callers authenticate
+// with access tokens encrypted with keys, and timestamps are displayed in
+// loc unless they ask for another timezone. Callers' addresses forwarded by
+// trusted proxies are believed, like those forwarded by the gateway.
+func NewServer(auth *auth.Auth, card *card.Service, employee *employee.Service, keys *auth.KeyRing, roles *rbac.Resolver, trusted []*net.IPNet, loc *time.Location, zlog *zap.Logger) (*Server, error) {
+	if auth == nil {
+		return nil, errors.New("auth service is nil")
+	}
+	if card == nil {
+		return nil, errors.New("card service is nil")
+	}
+	if employee == nil {
+		return nil, errors.New("employee service is nil")
+	}
+	if keys == nil {
+		return nil, errors.New("keys are nil")
+	}
+	if roles == nil {
+		return nil, errors.New("roles are nil")
+	}
+	if loc == nil {
+		return nil, errors.New("loc is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Server{
+		auth:     auth,
+		card:     card,
+		employee: employee,
+		keys:     keys,
+		roles:    roles,
+		trusted:  trusted,
+		loc:      loc,
+		zlog:     zlog,
+	}, nil
+}
+
+// Register registers the services on g, which must intercept unary calls
+// with s.Intercept.
+func (s *Server) Register(g *gogrpc.Server) {
+	contactqrPb.RegisterAuthServiceServer(g, &authServer{auth: s.auth, trusted: s.trusted})
+	contactqrPb.RegisterCardServiceServer(g, &cardServer{card: s.card})
+	contactqrPb.RegisterEmployeeServiceServer(g, &employeeServer{employee: s.employee})
+}
+
+// NewGateway returns the HTTP/JSON gateway of the gRPC API served at
+// endpoint. Calls go through the gRPC server, interceptors included.
+func NewGateway(ctx context.Context, endpoint string) (*runtime.ServeMux, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher))
+	opts := []gogrpc.DialOption{gogrpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	if err := errors.Join(
+		contactqrPb.RegisterAuthServiceHandlerFromEndpoint(ctx, mux, endpoint, opts),
+		contactqrPb.RegisterCardServiceHandlerFromEndpoint(ctx, mux, endpoint, opts),
+		contactqrPb.RegisterEmployeeServiceHandlerFromEndpoint(ctx, mux, endpoint, opts),
+	); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
@@ -0,0 +1,51 @@
+// Package health holds the state of the primary database, shared by the
+// services that change behavior while it is unreachable: mutations are
+// refused with one structured read-only error, published cards keep being
+// served from cache and scheduled jobs skip their runs.
+package health
+
+import (
+	"errors"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/breaker"
+	"github.com/10664kls/contactqr/internal/i18n"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type State struct {
+	db *breaker.Breaker
+}
+
+// New returns the health state tracked by db, the breaker guarding
+// connections to the primary database.
+func New(db *breaker.Breaker) (*State, error) {
+	if db == nil {
+		return nil, errors.New("db breaker is nil")
+	}
+
+	return &State{db: db}, nil
+}
+
+// ReadOnly reports whether the primary database is unreachable, so only
+// reads that can be served without it succeed.
+func (s *State) ReadOnly() bool {
+	return s.db.Open()
+}
+
+// ReadOnlyError is the error mutations get while the service is read-only.
+// Its RetryInfo tells clients when the database is tried again.
+func (s *State) ReadOnlyError() error {
+	st, _ := i18n.Status(codes.Unavailable, i18n.ReadOnly).WithDetails(&edPb.RetryInfo{
+		RetryDelay: durationpb.New(s.RetryAfter()),
+	})
+	return st.Err()
+}
+
+// RetryAfter returns how long until the database is tried again, zero when
+// it is reachable.
+func (s *State) RetryAfter() time.Duration {
+	return s.db.RetryAfter()
+}
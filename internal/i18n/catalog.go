@@ -0,0 +1,899 @@
+package i18n
+
+// Key identifies an error. It is sent to clients as the ErrorInfo reason or
+// the field violation reason, and listed by Catalog, so clients map it to
+// their own UX. Keys are part of the API: never rename or reuse one.
+type Key string
+
+const (
+	InvalidJSON      Key = "INVALID_JSON"
+	InvalidParameter Key = "INVALID_PARAMETER"
+	NotFound         Key = "NOT_FOUND"
+	TooManyRequests  Key = "TOO_MANY_REQUESTS"
+	Internal         Key = "INTERNAL"
+	Unknown          Key = "UNKNOWN"
+	ReadOnly         Key = "READ_ONLY"
+	DBUnavailable    Key = "DB_UNAVAILABLE"
+	InternalOnly     Key = "INTERNAL_ONLY"
+	Draining         Key = "DRAINING"
+	InvalidTimezone  Key = "INVALID_TIMEZONE"
+	InvalidEnvelope  Key = "INVALID_RESPONSE_ENVELOPE"
+	SchemaNotCurrent Key = "SCHEMA_NOT_CURRENT"
+
+	InvalidToken        Key = "INVALID_TOKEN"
+	InvalidCredentials  Key = "INVALID_CREDENTIALS"
+	InvalidRefreshToken Key = "INVALID_REFRESH_TOKEN"
+	TokenRevoked        Key = "TOKEN_REVOKED"
+	AccountLocked       Key = "ACCOUNT_LOCKED"
+	InvalidLogin        Key = "INVALID_LOGIN"
+	UserNotFound        Key = "USER_NOT_FOUND"
+	InvalidReport       Key = "INVALID_SESSION_REPORT"
+	SessionNotFound     Key = "SESSION_NOT_FOUND"
+
+	InvalidPasswordChange Key = "INVALID_PASSWORD_CHANGE"
+	IncorrectPassword     Key = "INCORRECT_PASSWORD"
+	PasswordsForbidden    Key = "PASSWORD_RESET_FORBIDDEN"
+	NoEmailAddress        Key = "NO_EMAIL_ADDRESS"
+	InvalidPasswordReset  Key = "INVALID_PASSWORD_RESET"
+	InvalidResetToken     Key = "INVALID_RESET_TOKEN"
+
+	EmployeesForbidden Key = "EMPLOYEES_FORBIDDEN"
+	EmployeeNotFound   Key = "EMPLOYEE_NOT_FOUND"
+	InvalidPreferences Key = "INVALID_PREFERENCES"
+	InvalidDevice      Key = "INVALID_PUSH_DEVICE"
+	InvalidPhoto       Key = "INVALID_PHOTO"
+	PhotoTooLarge      Key = "PHOTO_TOO_LARGE"
+	PhotoNotFound      Key = "PHOTO_NOT_FOUND"
+
+	GuestsForbidden Key = "GUESTS_FORBIDDEN"
+	GuestNotFound   Key = "GUEST_NOT_FOUND"
+	InvalidGuest    Key = "INVALID_GUEST"
+
+	CardNotFound       Key = "CARD_NOT_FOUND"
+	CardsForbidden     Key = "CARDS_FORBIDDEN"
+	InvalidCard        Key = "INVALID_CARD"
+	InvalidApproval    Key = "INVALID_APPROVAL"
+	InvalidRejection   Key = "INVALID_REJECTION"
+	InvalidPublication Key = "INVALID_PUBLICATION"
+	InvalidArchive     Key = "INVALID_ARCHIVE"
+	InvalidBulkArchive Key = "INVALID_BULK_ARCHIVE"
+	InvalidQR          Key = "INVALID_QR_REQUEST"
+	CardNotApprovable  Key = "CARD_NOT_APPROVABLE"
+	CardNotRejectable  Key = "CARD_NOT_REJECTABLE"
+	CardNotPublishable Key = "CARD_NOT_PUBLISHABLE"
+	CardNotUpdatable   Key = "CARD_NOT_UPDATABLE"
+	DuplicateCard      Key = "DUPLICATE_CARD"
+	CardLimitReached   Key = "CARD_LIMIT_REACHED"
+	InvalidLead        Key = "INVALID_LEAD"
+	InvalidNDEF        Key = "INVALID_NDEF_REQUEST"
+	NDEFTooLarge       Key = "NDEF_TOO_LARGE"
+	InvalidSyncToken   Key = "INVALID_SYNC_TOKEN"
+	InvalidPoster      Key = "INVALID_POSTER_REQUEST"
+	InvalidCardExport  Key = "INVALID_EXPORT_REQUEST"
+	InvalidVCF         Key = "INVALID_VCF_REQUEST"
+	NoPublishedCards   Key = "NO_PUBLISHED_CARDS"
+	InvalidEventCard   Key = "INVALID_EVENT_CARD"
+	CardNotPublished   Key = "CARD_NOT_PUBLISHED"
+	InvalidShareLink   Key = "INVALID_SHARE_LINK"
+	ShareLinkNotFound  Key = "SHARE_LINK_NOT_FOUND"
+	ShareLinksDisabled Key = "SHARE_LINKS_DISABLED"
+	WalletDisabled     Key = "WALLET_PASSES_DISABLED"
+
+	AnalyticsForbidden Key = "ANALYTICS_FORBIDDEN"
+	InvalidScanQuery   Key = "INVALID_SCAN_QUERY"
+
+	ExperimentsForbidden Key = "EXPERIMENTS_FORBIDDEN"
+	ExperimentNotFound   Key = "EXPERIMENT_NOT_FOUND"
+	ExperimentRunning    Key = "EXPERIMENT_RUNNING"
+	InvalidExperiment    Key = "INVALID_EXPERIMENT"
+
+	AuditForbidden  Key = "AUDIT_FORBIDDEN"
+	JobsForbidden   Key = "JOBS_FORBIDDEN"
+	JobRunForbidden Key = "JOB_RUN_FORBIDDEN"
+	JobNotFound     Key = "JOB_NOT_FOUND"
+	JobRunning      Key = "JOB_RUNNING"
+
+	DiagnosticsForbidden Key = "DIAGNOSTICS_FORBIDDEN"
+
+	ExportsForbidden Key = "EXPORTS_FORBIDDEN"
+
+	WebhooksForbidden Key = "WEBHOOKS_FORBIDDEN"
+	InvalidWebhook    Key = "INVALID_WEBHOOK"
+	WebhookNotFound   Key = "WEBHOOK_NOT_FOUND"
+
+	TemplatesForbidden Key = "CARD_TEMPLATES_FORBIDDEN"
+	InvalidTemplate    Key = "INVALID_CARD_TEMPLATE"
+	TemplateNotFound   Key = "CARD_TEMPLATE_NOT_FOUND"
+	TemplateExists     Key = "CARD_TEMPLATE_EXISTS"
+
+	AddressesForbidden Key = "COMPANY_ADDRESSES_FORBIDDEN"
+	InvalidAddress     Key = "INVALID_COMPANY_ADDRESS"
+	AddressNotFound    Key = "COMPANY_ADDRESS_NOT_FOUND"
+
+	PrintRequestsForbidden     Key = "PRINT_REQUESTS_FORBIDDEN"
+	InvalidPrintRequest        Key = "INVALID_PRINT_REQUEST"
+	InvalidPrintQuery          Key = "INVALID_PRINT_REQUEST_QUERY"
+	PrintRequestNotFound       Key = "PRINT_REQUEST_NOT_FOUND"
+	PrintRequestOpen           Key = "PRINT_REQUEST_OPEN"
+	PrintRequestNotOrderable   Key = "PRINT_REQUEST_NOT_ORDERABLE"
+	PrintRequestNotDeliverable Key = "PRINT_REQUEST_NOT_DELIVERABLE"
+
+	InvalidTransliteration   Key = "INVALID_TRANSLITERATION"
+	TransliterationForbidden Key = "TRANSLITERATION_FORBIDDEN"
+	OverrideNotFound         Key = "TRANSLITERATION_OVERRIDE_NOT_FOUND"
+)
+
+// Field violation keys. Their messages use {field} for the violating
+// field's path. Add new ones to violationKeys too.
+const (
+	Required          Key = "REQUIRED"
+	InvalidPhone      Key = "INVALID_PHONE_NUMBER"
+	UnsupportedFormat Key = "UNSUPPORTED_QR_FORMAT"
+	NonCorporateEmail Key = "NON_CORPORATE_EMAIL"
+	InvalidWord       Key = "INVALID_WORD"
+	UnsupportedLang   Key = "UNSUPPORTED_LANGUAGE"
+	TooLong           Key = "TOO_LONG"
+	InvalidEmail      Key = "INVALID_EMAIL_ADDRESS"
+	UnsupportedNDEF   Key = "UNSUPPORTED_NDEF_FORMAT"
+	UnsupportedTag    Key = "UNSUPPORTED_NFC_TAG"
+	UnsupportedOS     Key = "UNSUPPORTED_PLATFORM"
+	UnsupportedPaper  Key = "UNSUPPORTED_PAPER_SIZE"
+	InvalidWindow     Key = "INVALID_VALIDITY_WINDOW"
+	InvalidExpiry     Key = "INVALID_EXPIRY"
+	InvalidDate       Key = "INVALID_DATE"
+	InvalidDateRange  Key = "INVALID_DATE_RANGE"
+	InvalidScope      Key = "INVALID_EXPERIMENT_SCOPE"
+	InvalidLayout     Key = "INVALID_LAYOUT"
+	InvalidWeight     Key = "INVALID_VARIANT_WEIGHT"
+	InvalidVariants   Key = "INVALID_VARIANTS"
+	InvalidValue      Key = "INVALID_VALUE"
+	InvalidQRSize     Key = "INVALID_QR_SIZE"
+	UnsupportedLevel  Key = "UNSUPPORTED_QR_LEVEL"
+	UnsupportedVCard  Key = "UNSUPPORTED_VCARD_VERSION"
+	UnsupportedExport Key = "UNSUPPORTED_EXPORT_FORMAT"
+	UnsupportedBundle Key = "UNSUPPORTED_VCF_BUNDLE_FORMAT"
+	UnknownColumn     Key = "UNKNOWN_COLUMN"
+	TooShort          Key = "TOO_SHORT"
+	HTTPSRequired     Key = "HTTPS_REQUIRED"
+	UnsupportedEvent  Key = "UNSUPPORTED_EVENT_TYPE"
+	InvalidQuantity   Key = "INVALID_PRINT_QUANTITY"
+	PasswordUnchanged Key = "PASSWORD_UNCHANGED"
+	InvalidColor      Key = "INVALID_COLOR"
+	InvalidHandle     Key = "INVALID_HANDLE"
+	InvalidLinkedIn   Key = "INVALID_LINKEDIN_URL"
+	InvalidGeo        Key = "INVALID_COORDINATES"
+)
+
+var catalog = map[Key]map[Lang]string{
+	InvalidJSON: {
+		English: "Request body must be a valid JSON.",
+		Lao:     "ຂໍ້ມູນທີ່ສົ່ງມາຕ້ອງເປັນ JSON ທີ່ຖືກຕ້ອງ.",
+		Thai:    "เนื้อหาคำขอต้องเป็น JSON ที่ถูกต้อง",
+	},
+	InvalidParameter: {
+		English: "Request parameters must be a valid type.",
+		Lao:     "ພາລາມິເຕີຂອງຄຳຂໍຕ້ອງມີປະເພດທີ່ຖືກຕ້ອງ.",
+		Thai:    "พารามิเตอร์ของคำขอต้องเป็นชนิดที่ถูกต้อง",
+	},
+	NotFound: {
+		English: "Not found!",
+		Lao:     "ບໍ່ພົບຂໍ້ມູນ!",
+		Thai:    "ไม่พบข้อมูล!",
+	},
+	TooManyRequests: {
+		English: "Too many requests.",
+		Lao:     "ມີຄຳຂໍຫຼາຍເກີນໄປ.",
+		Thai:    "มีคำขอมากเกินไป",
+	},
+	Internal: {
+		English: "An internal error occurred.",
+		Lao:     "ເກີດຂໍ້ຜິດພາດພາຍໃນລະບົບ.",
+		Thai:    "เกิดข้อผิดพลาดภายในระบบ",
+	},
+	Unknown: {
+		English: "Unknown error!",
+		Lao:     "ເກີດຂໍ້ຜິດພາດທີ່ບໍ່ຮູ້ສາເຫດ!",
+		Thai:    "เกิดข้อผิดพลาดที่ไม่ทราบสาเหตุ!",
+	},
+	ReadOnly: {
+		English: "The service is temporarily read-only. Please try again later.",
+		Lao:     "ລະບົບອ່ານໄດ້ຢ່າງດຽວຊົ່ວຄາວ. ກະລຸນາລອງໃໝ່ພາຍຫຼັງ.",
+		Thai:    "ระบบอ่านได้อย่างเดียวชั่วคราว กรุณาลองใหม่ภายหลัง",
+	},
+	DBUnavailable: {
+		English: "The database is temporarily unavailable. Please try again later.",
+		Lao:     "ຖານຂໍ້ມູນບໍ່ພ້ອມໃຊ້ງານຊົ່ວຄາວ. ກະລຸນາລອງໃໝ່ພາຍຫຼັງ.",
+		Thai:    "ฐานข้อมูลไม่พร้อมใช้งานชั่วคราว กรุณาลองใหม่ภายหลัง",
+	},
+	InternalOnly: {
+		English: "You are not allowed to access this endpoint.",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງ endpoint ນີ້.",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึง endpoint นี้",
+	},
+	SchemaNotCurrent: {
+		English: "The database schema is dirty or ahead of this release and must be fixed by hand.",
+		Lao:     "ໂຄງສ້າງຖານຂໍ້ມູນເສຍຫາຍ ຫຼື ໃໝ່ກວ່າເວີຊັນນີ້ ແລະ ຕ້ອງແກ້ໄຂດ້ວຍມື.",
+		Thai:    "โครงสร้างฐานข้อมูลเสียหายหรือใหม่กว่ารุ่นนี้ และต้องแก้ไขด้วยตนเอง",
+	},
+	Draining: {
+		English: "The server is draining. Please try again on another instance.",
+		Lao:     "ເຊີບເວີກຳລັງປິດການໃຫ້ບໍລິການ. ກະລຸນາລອງໃໝ່ກັບເຊີບເວີອື່ນ.",
+		Thai:    "เซิร์ฟเวอร์กำลังหยุดให้บริการ กรุณาลองใหม่กับเซิร์ฟเวอร์อื่น",
+	},
+	InvalidTimezone: {
+		English: "Timezone {timezone} is not a valid IANA timezone name.",
+		Lao:     "ເຂດເວລາ {timezone} ບໍ່ແມ່ນຊື່ເຂດເວລາ IANA ທີ່ຖືກຕ້ອງ.",
+		Thai:    "เขตเวลา {timezone} ไม่ใช่ชื่อเขตเวลา IANA ที่ถูกต้อง",
+	},
+	InvalidEnvelope: {
+		English: "Response envelope {envelope} is not supported. Use legacy or v1.",
+		Lao:     "ບໍ່ຮອງຮັບຮູບແບບການຕອບກັບ {envelope}. ກະລຸນາໃຊ້ legacy ຫຼື v1.",
+		Thai:    "ไม่รองรับรูปแบบการตอบกลับ {envelope} กรุณาใช้ legacy หรือ v1",
+	},
+
+	InvalidToken: {
+		English: "Your provided token is not valid. Please provide a valid token and try again.",
+		Lao:     "ໂທເຄັນທີ່ທ່ານສົ່ງມາບໍ່ຖືກຕ້ອງ. ກະລຸນາສົ່ງໂທເຄັນທີ່ຖືກຕ້ອງແລ້ວລອງໃໝ່.",
+		Thai:    "โทเค็นที่คุณส่งมาไม่ถูกต้อง กรุณาส่งโทเค็นที่ถูกต้องแล้วลองใหม่",
+	},
+	InvalidCredentials: {
+		English: "Your credentials not valid. Please check your username and password and try again.",
+		Lao:     "ຂໍ້ມູນເຂົ້າລະບົບບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຊື່ຜູ້ໃຊ້ ແລະ ລະຫັດຜ່ານແລ້ວລອງໃໝ່.",
+		Thai:    "ข้อมูลเข้าสู่ระบบไม่ถูกต้อง กรุณาตรวจสอบชื่อผู้ใช้และรหัสผ่านแล้วลองใหม่",
+	},
+	InvalidRefreshToken: {
+		English: "Your credentials not valid. Please check your token and try again.",
+		Lao:     "ຂໍ້ມູນເຂົ້າລະບົບບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບໂທເຄັນແລ້ວລອງໃໝ່.",
+		Thai:    "ข้อมูลเข้าสู่ระบบไม่ถูกต้อง กรุณาตรวจสอบโทเค็นแล้วลองใหม่",
+	},
+	TokenRevoked: {
+		English: "Your session was revoked. Please sign in again.",
+		Lao:     "ເຊດຊັນຂອງທ່ານຖືກຍົກເລີກແລ້ວ. ກະລຸນາເຂົ້າສູ່ລະບົບໃໝ່.",
+		Thai:    "เซสชันของคุณถูกเพิกถอนแล้ว กรุณาเข้าสู่ระบบใหม่",
+	},
+	AccountLocked: {
+		English: "Too many failed sign-in attempts. Please wait before trying again, see details for when.",
+		Lao:     "ພະຍາຍາມເຂົ້າສູ່ລະບົບບໍ່ສຳເລັດຫຼາຍເກີນໄປ. ກະລຸນາລໍຖ້າກ່ອນລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພື່ອຮູ້ເວລາ.",
+		Thai:    "พยายามเข้าสู่ระบบไม่สำเร็จหลายครั้งเกินไป กรุณารอก่อนลองใหม่ ดูรายละเอียดเพื่อทราบเวลา",
+	},
+	InvalidLogin: {
+		English: "Credentials are not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຂໍ້ມູນເຂົ້າລະບົບບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "ข้อมูลเข้าสู่ระบบไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	UserNotFound: {
+		English: "Your are not allowed to access this user or (it may not exist)",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງຜູ້ໃຊ້ນີ້ ຫຼື (ອາດບໍ່ມີຢູ່)",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงผู้ใช้นี้ หรือ (อาจไม่มีอยู่)",
+	},
+	InvalidReport: {
+		English: "Your report is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ການລາຍງານຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "รายงานของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	SessionNotFound: {
+		English: "The reported sign-in does not exist or was already revoked.",
+		Lao:     "ການເຂົ້າລະບົບທີ່ລາຍງານບໍ່ມີຢູ່ ຫຼື ຖືກຍົກເລີກແລ້ວ.",
+		Thai:    "การเข้าสู่ระบบที่รายงานไม่มีอยู่หรือถูกเพิกถอนแล้ว",
+	},
+	InvalidPasswordChange: {
+		English: "The password change is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ການປ່ຽນລະຫັດຜ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "การเปลี่ยนรหัสผ่านไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	IncorrectPassword: {
+		English: "Your current password is not correct. Please check it and try again.",
+		Lao:     "ລະຫັດຜ່ານປັດຈຸບັນຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບແລ້ວລອງໃໝ່.",
+		Thai:    "รหัสผ่านปัจจุบันของคุณไม่ถูกต้อง กรุณาตรวจสอบแล้วลองใหม่",
+	},
+	PasswordsForbidden: {
+		English: "You are not allowed to reset the passwords of employees.",
+		Lao:     "ທ່ານບໍ່ມີສິດຕັ້ງລະຫັດຜ່ານຂອງພະນັກງານໃໝ່.",
+		Thai:    "คุณไม่มีสิทธิ์รีเซ็ตรหัสผ่านของพนักงาน",
+	},
+	NoEmailAddress: {
+		English: "The employee has no email address to send the password reset link to.",
+		Lao:     "ພະນັກງານບໍ່ມີທີ່ຢູ່ອີເມວສຳລັບສົ່ງລິ້ງຕັ້ງລະຫັດຜ່ານໃໝ່.",
+		Thai:    "พนักงานไม่มีที่อยู่อีเมลสำหรับส่งลิงก์รีเซ็ตรหัสผ่าน",
+	},
+	InvalidPasswordReset: {
+		English: "The password reset is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ການຕັ້ງລະຫັດຜ່ານໃໝ່ບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "การรีเซ็ตรหัสผ่านไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidResetToken: {
+		English: "The password reset link is not valid, was already used or has expired. Please ask HR for a new one.",
+		Lao:     "ລິ້ງຕັ້ງລະຫັດຜ່ານໃໝ່ບໍ່ຖືກຕ້ອງ, ຖືກໃຊ້ແລ້ວ ຫຼື ໝົດອາຍຸ. ກະລຸນາຂໍລິ້ງໃໝ່ຈາກ HR.",
+		Thai:    "ลิงก์รีเซ็ตรหัสผ่านไม่ถูกต้อง ถูกใช้แล้ว หรือหมดอายุ กรุณาขอลิงก์ใหม่จาก HR",
+	},
+
+	EmployeesForbidden: {
+		English: "You are not allowed to access theses employees.",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງຂໍ້ມູນພະນັກງານເຫຼົ່ານີ້.",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงข้อมูลพนักงานเหล่านี้",
+	},
+	EmployeeNotFound: {
+		English: "You are not allowed to access this employee or (it may not exist)",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງພະນັກງານນີ້ ຫຼື (ອາດບໍ່ມີຢູ່)",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงพนักงานนี้ หรือ (อาจไม่มีอยู่)",
+	},
+	InvalidPhoto: {
+		English: "The photo must be a JPEG or PNG image.",
+		Lao:     "ຮູບຕ້ອງເປັນໄຟລ໌ JPEG ຫຼື PNG.",
+		Thai:    "รูปต้องเป็นไฟล์ JPEG หรือ PNG",
+	},
+	PhotoTooLarge: {
+		English: "The photo is too large; it must be at most {size} bytes.",
+		Lao:     "ຮູບໃຫຍ່ເກີນໄປ; ຕ້ອງບໍ່ເກີນ {size} ໄບ.",
+		Thai:    "รูปใหญ่เกินไป ต้องไม่เกิน {size} ไบต์",
+	},
+	PhotoNotFound: {
+		English: "This card has no photo.",
+		Lao:     "ນາມບັດນີ້ບໍ່ມີຮູບ.",
+		Thai:    "นามบัตรนี้ไม่มีรูป",
+	},
+	GuestsForbidden: {
+		English: "You are not allowed to manage guests.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການແຂກ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการแขก",
+	},
+	GuestNotFound: {
+		English: "Guest {guestId} does not exist.",
+		Lao:     "ບໍ່ມີແຂກ {guestId}.",
+		Thai:    "ไม่มีแขก {guestId}",
+	},
+	InvalidGuest: {
+		English: "Your guest is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຂໍ້ມູນແຂກຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "ข้อมูลแขกของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidPreferences: {
+		English: "Invalid preferences.",
+		Lao:     "ການຕັ້ງຄ່າບໍ່ຖືກຕ້ອງ.",
+		Thai:    "การตั้งค่าไม่ถูกต้อง",
+	},
+	InvalidDevice: {
+		English: "Your device registration is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ການລົງທະບຽນອຸປະກອນຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "การลงทะเบียนอุปกรณ์ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+
+	CardNotFound: {
+		English: "You are not allowed to access this card or (it may not exist)",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງບັດນີ້ ຫຼື (ອາດບໍ່ມີຢູ່)",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงบัตรนี้ หรือ (อาจไม่มีอยู่)",
+	},
+	CardsForbidden: {
+		English: "You are not allowed to access theses business cards.",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງນາມບັດເຫຼົ່ານີ້.",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงนามบัตรเหล่านี้",
+	},
+	InvalidCard: {
+		English: "Card is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ບັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "บัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidLead: {
+		English: "Your contact details are not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຂໍ້ມູນຕິດຕໍ່ຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "ข้อมูลติดต่อของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidNDEF: {
+		English: "Your NFC request is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍ NFC ຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอ NFC ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	NDEFTooLarge: {
+		English: "The NFC message takes {size} bytes but a {tag} tag holds {budget}. Choose the url format or a larger tag.",
+		Lao:     "ຂໍ້ຄວາມ NFC ໃຊ້ {size} ໄບຕ໌ ແຕ່ແທັກ {tag} ຈຸໄດ້ {budget}. ກະລຸນາເລືອກຮູບແບບ url ຫຼື ແທັກທີ່ໃຫຍ່ກວ່າ.",
+		Thai:    "ข้อความ NFC ใช้ {size} ไบต์ แต่แท็ก {tag} จุได้ {budget} กรุณาเลือกรูปแบบ url หรือแท็กที่ใหญ่กว่า",
+	},
+	InvalidSyncToken: {
+		English: "The sync token is not valid. Sync again without one to get a full copy.",
+		Lao:     "ໂທເຄັນຊິງບໍ່ຖືກຕ້ອງ. ກະລຸນາຊິງໃໝ່ໂດຍບໍ່ມີໂທເຄັນເພື່ອຮັບຂໍ້ມູນທັງໝົດ.",
+		Thai:    "โทเค็นซิงก์ไม่ถูกต้อง กรุณาซิงก์ใหม่โดยไม่ใช้โทเค็นเพื่อรับข้อมูลทั้งหมด",
+	},
+	InvalidVCF: {
+		English: "Your vCard request is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍ vCard ຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอ vCard ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidPoster: {
+		English: "Your poster request is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍໂປສເຕີຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอโปสเตอร์ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidCardExport: {
+		English: "Your card export request is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍສົ່ງອອກບັດຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอส่งออกบัตรของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	NoPublishedCards: {
+		English: "Department {departmentId} has no published business cards.",
+		Lao:     "ພະແນກ {departmentId} ບໍ່ມີນາມບັດທີ່ເຜີຍແຜ່ແລ້ວ.",
+		Thai:    "แผนก {departmentId} ไม่มีนามบัตรที่เผยแพร่แล้ว",
+	},
+	InvalidEventCard: {
+		English: "Your event card is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ນາມບັດງານຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "นามบัตรงานของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	CardNotPublished: {
+		English: "Business card is {status}; only published cards can be used.",
+		Lao:     "ນາມບັດຢູ່ໃນສະຖານະ {status}; ໃຊ້ໄດ້ສະເພາະນາມບັດທີ່ເຜີຍແຜ່ແລ້ວ.",
+		Thai:    "นามบัตรอยู่ในสถานะ {status} ใช้ได้เฉพาะนามบัตรที่เผยแพร่แล้ว",
+	},
+	InvalidShareLink: {
+		English: "Your share link request is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍລິ້ງແບ່ງປັນຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอลิงก์แชร์ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	ShareLinkNotFound: {
+		English: "Share link not found.",
+		Lao:     "ບໍ່ພົບລິ້ງແບ່ງປັນ.",
+		Thai:    "ไม่พบลิงก์แชร์",
+	},
+	ShareLinksDisabled: {
+		English: "Share links are not enabled.",
+		Lao:     "ລິ້ງແບ່ງປັນບໍ່ໄດ້ເປີດໃຊ້ງານ.",
+		Thai:    "ไม่ได้เปิดใช้งานลิงก์แชร์",
+	},
+	WalletDisabled: {
+		English: "Wallet passes are not enabled.",
+		Lao:     "ບັດ Wallet ບໍ່ໄດ້ເປີດໃຊ້ງານ.",
+		Thai:    "ไม่ได้เปิดใช้งานบัตร Wallet",
+	},
+	InvalidApproval: {
+		English: "Your approval business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍອະນຸມັດນາມບັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขออนุมัตินามบัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidRejection: {
+		English: "Your reject business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍປະຕິເສດນາມບັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอปฏิเสธนามบัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidPublication: {
+		English: "Your publish business card is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍເຜີຍແຜ່ນາມບັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอเผยแพร่นามบัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidArchive: {
+		English: "Your archive business card request is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍເກັບນາມບັດເຂົ້າຄັງບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอจัดเก็บนามบัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidBulkArchive: {
+		English: "Your bulk archive request is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍເກັບນາມບັດເຂົ້າຄັງເປັນຊຸດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอจัดเก็บนามบัตรแบบกลุ่มไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidQR: {
+		English: "QR code request is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍ QR code ບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอ QR code ไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	CardNotApprovable: {
+		English: "Card is in {status} status. Only PENDING status can be APPROVED.",
+		Lao:     "ບັດຢູ່ໃນສະຖານະ {status}. ສາມາດອະນຸມັດໄດ້ສະເພາະບັດທີ່ຢູ່ໃນສະຖານະ PENDING.",
+		Thai:    "บัตรอยู่ในสถานะ {status} อนุมัติได้เฉพาะบัตรที่อยู่ในสถานะ PENDING",
+	},
+	CardNotRejectable: {
+		English: "Card is in {status} status. Only PENDING status can be REJECTED.",
+		Lao:     "ບັດຢູ່ໃນສະຖານະ {status}. ສາມາດປະຕິເສດໄດ້ສະເພາະບັດທີ່ຢູ່ໃນສະຖານະ PENDING.",
+		Thai:    "บัตรอยู่ในสถานะ {status} ปฏิเสธได้เฉพาะบัตรที่อยู่ในสถานะ PENDING",
+	},
+	CardNotPublishable: {
+		English: "Card is in {status} status. Only APPROVED status can be PUBLISHED.",
+		Lao:     "ບັດຢູ່ໃນສະຖານະ {status}. ສາມາດເຜີຍແຜ່ໄດ້ສະເພາະບັດທີ່ຢູ່ໃນສະຖານະ APPROVED.",
+		Thai:    "บัตรอยู่ในสถานะ {status} เผยแพร่ได้เฉพาะบัตรที่อยู่ในสถานะ APPROVED",
+	},
+	CardNotUpdatable: {
+		English: "Card is in {status} status. Only PENDING, REJECTED and PUBLISHED status can be updated.",
+		Lao:     "ບັດຢູ່ໃນສະຖານະ {status}. ສາມາດແກ້ໄຂໄດ້ສະເພາະບັດທີ່ຢູ່ໃນສະຖານະ PENDING, REJECTED ແລະ PUBLISHED.",
+		Thai:    "บัตรอยู่ในสถานะ {status} แก้ไขได้เฉพาะบัตรที่อยู่ในสถานะ PENDING, REJECTED และ PUBLISHED",
+	},
+	DuplicateCard: {
+		English: "Card {cardId} is already waiting for approval. Update it instead of creating a new one.",
+		Lao:     "ບັດ {cardId} ກຳລັງລໍຖ້າການອະນຸມັດຢູ່ແລ້ວ. ກະລຸນາແກ້ໄຂບັດນັ້ນແທນການສ້າງບັດໃໝ່.",
+		Thai:    "บัตร {cardId} กำลังรอการอนุมัติอยู่แล้ว กรุณาแก้ไขบัตรนั้นแทนการสร้างบัตรใหม่",
+	},
+	CardLimitReached: {
+		English: "You already hold the most active cards allowed, {maxActive}. Card {cardId} must be archived before you can create a new one.",
+		Lao:     "ທ່ານມີບັດທີ່ໃຊ້ງານຢູ່ຄົບຈຳນວນສູງສຸດ {maxActive} ໃບແລ້ວ. ບັດ {cardId} ຕ້ອງຖືກເກັບເຂົ້າແຟ້ມກ່ອນທ່ານຈຶ່ງຈະສ້າງບັດໃໝ່ໄດ້.",
+		Thai:    "คุณมีบัตรที่ใช้งานอยู่ครบจำนวนสูงสุด {maxActive} ใบแล้ว ต้องเก็บถาวรบัตร {cardId} ก่อนจึงจะสร้างบัตรใหม่ได้",
+	},
+
+	AnalyticsForbidden: {
+		English: "You are not allowed to view the scan analytics.",
+		Lao:     "ທ່ານບໍ່ມີສິດເບິ່ງສະຖິຕິການສະແກນ.",
+		Thai:    "คุณไม่มีสิทธิ์ดูสถิติการสแกน",
+	},
+	InvalidScanQuery: {
+		English: "Your scan analytics query is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍສະຖິຕິການສະແກນຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอสถิติการสแกนของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	ExperimentsForbidden: {
+		English: "You are not allowed to manage landing page experiments.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການການທົດລອງໜ້າລົງຈອດ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการการทดลองหน้าแลนดิ้ง",
+	},
+	ExperimentNotFound: {
+		English: "Experiment {experimentId} does not exist.",
+		Lao:     "ບໍ່ມີການທົດລອງ {experimentId}.",
+		Thai:    "ไม่มีการทดลอง {experimentId}",
+	},
+	ExperimentRunning: {
+		English: "Experiment {experimentId} is already running here; stop it before starting another.",
+		Lao:     "ການທົດລອງ {experimentId} ກຳລັງດຳເນີນຢູ່; ກະລຸນາຢຸດກ່ອນເລີ່ມອັນໃໝ່.",
+		Thai:    "การทดลอง {experimentId} กำลังทำงานอยู่ กรุณาหยุดก่อนเริ่มใหม่",
+	},
+	InvalidExperiment: {
+		English: "Your experiment is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ການທົດລອງຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "การทดลองของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	AuditForbidden: {
+		English: "You are not allowed to verify the audit log.",
+		Lao:     "ທ່ານບໍ່ມີສິດກວດສອບບັນທຶກການກວດສອບ.",
+		Thai:    "คุณไม่มีสิทธิ์ตรวจสอบบันทึกการตรวจสอบ",
+	},
+	JobsForbidden: {
+		English: "You are not allowed to access the scheduled jobs.",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງວຽກທີ່ຕັ້ງເວລາໄວ້.",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงงานที่ตั้งเวลาไว้",
+	},
+	JobRunForbidden: {
+		English: "You are not allowed to run scheduled jobs.",
+		Lao:     "ທ່ານບໍ່ມີສິດເອີ້ນໃຊ້ວຽກທີ່ຕັ້ງເວລາໄວ້.",
+		Thai:    "คุณไม่มีสิทธิ์สั่งรันงานที่ตั้งเวลาไว้",
+	},
+	JobNotFound: {
+		English: "Job not found.",
+		Lao:     "ບໍ່ພົບວຽກ.",
+		Thai:    "ไม่พบงาน",
+	},
+	JobRunning: {
+		English: "Job is already running.",
+		Lao:     "ວຽກນີ້ກຳລັງເຮັດວຽກຢູ່ແລ້ວ.",
+		Thai:    "งานนี้กำลังทำงานอยู่แล้ว",
+	},
+	DiagnosticsForbidden: {
+		English: "You are not allowed to view the service diagnostics.",
+		Lao:     "ທ່ານບໍ່ມີສິດເບິ່ງການວິເຄາະລະບົບ.",
+		Thai:    "คุณไม่มีสิทธิ์ดูการวินิจฉัยระบบ",
+	},
+	ExportsForbidden: {
+		English: "You are not allowed to access the export deliveries.",
+		Lao:     "ທ່ານບໍ່ມີສິດເຂົ້າເຖິງການສົ່ງອອກຂໍ້ມູນ.",
+		Thai:    "คุณไม่มีสิทธิ์เข้าถึงการส่งออกข้อมูล",
+	},
+
+	WebhooksForbidden: {
+		English: "You are not allowed to manage webhooks.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການ webhook.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการ webhook",
+	},
+	InvalidWebhook: {
+		English: "Your webhook is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "webhook ຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "webhook ของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	WebhookNotFound: {
+		English: "Webhook {id} does not exist.",
+		Lao:     "ບໍ່ມີ webhook {id}.",
+		Thai:    "ไม่มี webhook {id}",
+	},
+
+	TemplatesForbidden: {
+		English: "You are not allowed to manage card templates.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການແມ່ແບບນາມບັດ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการแม่แบบนามบัตร",
+	},
+	InvalidTemplate: {
+		English: "Card template is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ແມ່ແບບນາມບັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "แม่แบบนามบัตรไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	TemplateNotFound: {
+		English: "Card template {id} does not exist.",
+		Lao:     "ບໍ່ມີແມ່ແບບນາມບັດ {id}.",
+		Thai:    "ไม่มีแม่แบบนามบัตร {id}",
+	},
+	TemplateExists: {
+		English: "Company {companyId} already has a card template. Update it instead.",
+		Lao:     "ບໍລິສັດ {companyId} ມີແມ່ແບບນາມບັດແລ້ວ. ກະລຸນາແກ້ໄຂແມ່ແບບນັ້ນແທນ.",
+		Thai:    "บริษัท {companyId} มีแม่แบบนามบัตรแล้ว กรุณาแก้ไขแม่แบบนั้นแทน",
+	},
+
+	AddressesForbidden: {
+		English: "You are not allowed to manage company addresses.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການທີ່ຢູ່ບໍລິສັດ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการที่อยู่บริษัท",
+	},
+	InvalidAddress: {
+		English: "Company address is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ທີ່ຢູ່ບໍລິສັດບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "ที่อยู่บริษัทไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	AddressNotFound: {
+		English: "Company {companyId} has no address.",
+		Lao:     "ບໍລິສັດ {companyId} ບໍ່ມີທີ່ຢູ່.",
+		Thai:    "บริษัท {companyId} ไม่มีที่อยู่",
+	},
+
+	PrintRequestsForbidden: {
+		English: "You are not allowed to manage print requests.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການຄຳຂໍພິມນາມບັດ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการคำขอพิมพ์นามบัตร",
+	},
+	InvalidPrintRequest: {
+		English: "Your print request is not valid or incomplete. Please check the errors and try again, see details for more information.",
+		Lao:     "ຄຳຂໍພິມນາມບັດຂອງທ່ານບໍ່ຖືກຕ້ອງ ຫຼື ບໍ່ຄົບຖ້ວນ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "คำขอพิมพ์นามบัตรของคุณไม่ถูกต้องหรือไม่ครบถ้วน กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	InvalidPrintQuery: {
+		English: "Your print request query is not valid. Please check the errors and try again, see details for more information.",
+		Lao:     "ການຄົ້ນຫາຄຳຂໍພິມນາມບັດຂອງທ່ານບໍ່ຖືກຕ້ອງ. ກະລຸນາກວດສອບຂໍ້ຜິດພາດແລ້ວລອງໃໝ່, ເບິ່ງລາຍລະອຽດເພີ່ມເຕີມ.",
+		Thai:    "การค้นหาคำขอพิมพ์นามบัตรของคุณไม่ถูกต้อง กรุณาตรวจสอบข้อผิดพลาดแล้วลองใหม่ ดูรายละเอียดเพิ่มเติม",
+	},
+	PrintRequestNotFound: {
+		English: "Print request {printRequestId} does not exist.",
+		Lao:     "ບໍ່ມີຄຳຂໍພິມນາມບັດ {printRequestId}.",
+		Thai:    "ไม่มีคำขอพิมพ์นามบัตร {printRequestId}",
+	},
+	PrintRequestOpen: {
+		English: "Print request {printRequestId} for this card is still {status}. Wait for its delivery before requesting more cards.",
+		Lao:     "ຄຳຂໍພິມນາມບັດ {printRequestId} ຂອງບັດນີ້ຍັງຢູ່ໃນສະຖານະ {status}. ກະລຸນາລໍຖ້າການຈັດສົ່ງກ່ອນຂໍພິມເພີ່ມ.",
+		Thai:    "คำขอพิมพ์นามบัตร {printRequestId} ของบัตรนี้ยังอยู่ในสถานะ {status} กรุณารอการจัดส่งก่อนขอพิมพ์เพิ่ม",
+	},
+	PrintRequestNotOrderable: {
+		English: "Print request is in {status} status. Only REQUESTED status can be ORDERED.",
+		Lao:     "ຄຳຂໍພິມນາມບັດຢູ່ໃນສະຖານະ {status}. ສາມາດສັ່ງພິມໄດ້ສະເພາະຄຳຂໍທີ່ຢູ່ໃນສະຖານະ REQUESTED.",
+		Thai:    "คำขอพิมพ์นามบัตรอยู่ในสถานะ {status} สั่งพิมพ์ได้เฉพาะคำขอที่อยู่ในสถานะ REQUESTED",
+	},
+	PrintRequestNotDeliverable: {
+		English: "Print request is in {status} status. Only ORDERED status can be DELIVERED.",
+		Lao:     "ຄຳຂໍພິມນາມບັດຢູ່ໃນສະຖານະ {status}. ສາມາດຢືນຢັນການຈັດສົ່ງໄດ້ສະເພາະຄຳຂໍທີ່ຢູ່ໃນສະຖານະ ORDERED.",
+		Thai:    "คำขอพิมพ์นามบัตรอยู่ในสถานะ {status} ยืนยันการจัดส่งได้เฉพาะคำขอที่อยู่ในสถานะ ORDERED",
+	},
+
+	InvalidTransliteration: {
+		English: "Invalid transliteration request.",
+		Lao:     "ຄຳຮ້ອງຂໍການຖອດຕົວອັກສອນບໍ່ຖືກຕ້ອງ.",
+		Thai:    "คำขอถอดอักษรไม่ถูกต้อง",
+	},
+	TransliterationForbidden: {
+		English: "You are not allowed to manage transliteration overrides.",
+		Lao:     "ທ່ານບໍ່ມີສິດຈັດການການແກ້ໄຂການຖອດຕົວອັກສອນ.",
+		Thai:    "คุณไม่มีสิทธิ์จัดการการแก้ไขการถอดอักษร",
+	},
+	OverrideNotFound: {
+		English: "No transliteration override exists for {lao}.",
+		Lao:     "ບໍ່ມີການແກ້ໄຂການຖອດຕົວອັກສອນສຳລັບ {lao}.",
+		Thai:    "ไม่มีการแก้ไขการถอดอักษรสำหรับ {lao}",
+	},
+
+	Required: {
+		English: "{field} must not be empty",
+		Lao:     "ຕ້ອງລະບຸ {field}",
+		Thai:    "ต้องระบุ {field}",
+	},
+	InvalidPhone: {
+		English: "{field} must be a valid number",
+		Lao:     "{field} ຕ້ອງເປັນເບີໂທທີ່ຖືກຕ້ອງ",
+		Thai:    "{field} ต้องเป็นหมายเลขที่ถูกต้อง",
+	},
+	UnsupportedFormat: {
+		English: "{field} must be one of png or svg",
+		Lao:     "{field} ຕ້ອງເປັນ png ຫຼື svg",
+		Thai:    "{field} ต้องเป็น png หรือ svg",
+	},
+	NonCorporateEmail: {
+		English: "{field} must be a company email address, not a personal one",
+		Lao:     "{field} ຕ້ອງເປັນອີເມວຂອງບໍລິສັດ, ບໍ່ແມ່ນອີເມວສ່ວນຕົວ",
+		Thai:    "{field} ต้องเป็นอีเมลของบริษัท ไม่ใช่อีเมลส่วนตัว",
+	},
+	InvalidWord: {
+		English: "{field} must be a single word in its script",
+		Lao:     "{field} ຕ້ອງເປັນຄຳດຽວໃນຕົວອັກສອນຂອງມັນ",
+		Thai:    "{field} ต้องเป็นคำเดียวในอักษรของตัวเอง",
+	},
+	UnsupportedLang: {
+		English: "{field} must be one of en, lo or th",
+		Lao:     "{field} ຕ້ອງເປັນ en, lo ຫຼື th",
+		Thai:    "{field} ต้องเป็น en, lo หรือ th",
+	},
+	TooLong: {
+		English: "{field} is too long",
+		Lao:     "{field} ຍາວເກີນໄປ",
+		Thai:    "{field} ยาวเกินไป",
+	},
+	InvalidEmail: {
+		English: "{field} must be a valid email address",
+		Lao:     "{field} ຕ້ອງເປັນອີເມວທີ່ຖືກຕ້ອງ",
+		Thai:    "{field} ต้องเป็นอีเมลที่ถูกต้อง",
+	},
+	UnsupportedNDEF: {
+		English: "{field} must be one of url, vcard or auto",
+		Lao:     "{field} ຕ້ອງເປັນ url, vcard ຫຼື auto",
+		Thai:    "{field} ต้องเป็น url, vcard หรือ auto",
+	},
+	UnsupportedTag: {
+		English: "{field} must be one of ntag213, ntag215 or ntag216",
+		Lao:     "{field} ຕ້ອງເປັນ ntag213, ntag215 ຫຼື ntag216",
+		Thai:    "{field} ต้องเป็น ntag213, ntag215 หรือ ntag216",
+	},
+	UnsupportedOS: {
+		English: "{field} must be one of android or ios",
+		Lao:     "{field} ຕ້ອງເປັນ android ຫຼື ios",
+		Thai:    "{field} ต้องเป็น android หรือ ios",
+	},
+	UnsupportedPaper: {
+		English: "{field} must be one of a4 or a5",
+		Lao:     "{field} ຕ້ອງເປັນ a4 ຫຼື a5",
+		Thai:    "{field} ต้องเป็น a4 หรือ a5",
+	},
+	InvalidWindow: {
+		English: "{field} must be after validFrom and at most 90 days later",
+		Lao:     "{field} ຕ້ອງຫຼັງຈາກ validFrom ແລະ ບໍ່ເກີນ 90 ມື້",
+		Thai:    "{field} ต้องอยู่หลัง validFrom และไม่เกิน 90 วัน",
+	},
+	InvalidExpiry: {
+		English: "{field} must be in the future and at most 30 days away",
+		Lao:     "{field} ຕ້ອງເປັນເວລາໃນອະນາຄົດ ແລະ ບໍ່ເກີນ 30 ມື້",
+		Thai:    "{field} ต้องเป็นเวลาในอนาคตและไม่เกิน 30 วัน",
+	},
+	InvalidDate: {
+		English: "{field} must be a date in YYYY-MM-DD format",
+		Lao:     "{field} ຕ້ອງເປັນວັນທີໃນຮູບແບບ YYYY-MM-DD",
+		Thai:    "{field} ต้องเป็นวันที่ในรูปแบบ YYYY-MM-DD",
+	},
+	InvalidDateRange: {
+		English: "{field} must not be before from and at most 366 days later",
+		Lao:     "{field} ຕ້ອງບໍ່ກ່ອນ from ແລະ ບໍ່ເກີນ 366 ມື້",
+		Thai:    "{field} ต้องไม่อยู่ก่อน from และไม่เกิน 366 วัน",
+	},
+	InvalidScope: {
+		English: "{field} or companyId must be set, but not both",
+		Lao:     "ຕ້ອງລະບຸ {field} ຫຼື companyId ຢ່າງໃດຢ່າງໜຶ່ງ",
+		Thai:    "ต้องระบุ {field} หรือ companyId อย่างใดอย่างหนึ่ง",
+	},
+	InvalidLayout: {
+		English: "{field} must be 1 to 32 lowercase letters, digits or dashes",
+		Lao:     "{field} ຕ້ອງເປັນຕົວອັກສອນພິມນ້ອຍ, ຕົວເລກ ຫຼື ຂີດ 1 ຫາ 32 ຕົວ",
+		Thai:    "{field} ต้องเป็นตัวอักษรพิมพ์เล็ก ตัวเลข หรือขีด 1 ถึง 32 ตัว",
+	},
+	InvalidWeight: {
+		English: "{field} must be between 1 and 100",
+		Lao:     "{field} ຕ້ອງຢູ່ລະຫວ່າງ 1 ຫາ 100",
+		Thai:    "{field} ต้องอยู่ระหว่าง 1 ถึง 100",
+	},
+	InvalidVariants: {
+		English: "{field} must list 2 to 5 variants with different layouts",
+		Lao:     "{field} ຕ້ອງມີ 2 ຫາ 5 ແບບທີ່ມີໂຄງຮ່າງຕ່າງກັນ",
+		Thai:    "{field} ต้องมี 2 ถึง 5 แบบที่มีเลย์เอาต์ต่างกัน",
+	},
+	InvalidValue: {
+		English: "{field} is not valid",
+		Lao:     "{field} ບໍ່ຖືກຕ້ອງ",
+		Thai:    "{field} ไม่ถูกต้อง",
+	},
+	InvalidQRSize: {
+		English: "{field} must be between 128 and 2048",
+		Lao:     "{field} ຕ້ອງຢູ່ລະຫວ່າງ 128 ຫາ 2048",
+		Thai:    "{field} ต้องอยู่ระหว่าง 128 ถึง 2048",
+	},
+	UnsupportedLevel: {
+		English: "{field} must be one of L, M, Q or H",
+		Lao:     "{field} ຕ້ອງເປັນ L, M, Q ຫຼື H",
+		Thai:    "{field} ต้องเป็น L, M, Q หรือ H",
+	},
+	UnsupportedVCard: {
+		English: "{field} must be 2.1 or 4.0",
+		Lao:     "{field} ຕ້ອງເປັນ 2.1 ຫຼື 4.0",
+		Thai:    "{field} ต้องเป็น 2.1 หรือ 4.0",
+	},
+	UnsupportedExport: {
+		English: "{field} must be one of csv or xlsx",
+		Lao:     "{field} ຕ້ອງເປັນ csv ຫຼື xlsx",
+		Thai:    "{field} ต้องเป็น csv หรือ xlsx",
+	},
+	UnsupportedBundle: {
+		English: "{field} must be one of vcf or zip",
+		Lao:     "{field} ຕ້ອງເປັນ vcf ຫຼື zip",
+		Thai:    "{field} ต้องเป็น vcf หรือ zip",
+	},
+	TooShort: {
+		English: "{field} is too short",
+		Lao:     "{field} ສັ້ນເກີນໄປ",
+		Thai:    "{field} สั้นเกินไป",
+	},
+	HTTPSRequired: {
+		English: "{field} must be an https URL",
+		Lao:     "{field} ຕ້ອງເປັນ URL https",
+		Thai:    "{field} ต้องเป็น URL https",
+	},
+	UnsupportedEvent: {
+		English: "{field} must be one of CREATED, APPROVED, REJECTED or PUBLISHED",
+		Lao:     "{field} ຕ້ອງເປັນ CREATED, APPROVED, REJECTED ຫຼື PUBLISHED",
+		Thai:    "{field} ต้องเป็น CREATED, APPROVED, REJECTED หรือ PUBLISHED",
+	},
+	UnknownColumn: {
+		English: "{field} names a column that does not exist",
+		Lao:     "{field} ລະບຸຖັນທີ່ບໍ່ມີຢູ່",
+		Thai:    "{field} ระบุคอลัมน์ที่ไม่มีอยู่",
+	},
+	InvalidQuantity: {
+		English: "{field} must be between 1 and 1000",
+		Lao:     "{field} ຕ້ອງຢູ່ລະຫວ່າງ 1 ຫາ 1000",
+		Thai:    "{field} ต้องอยู่ระหว่าง 1 ถึง 1000",
+	},
+	PasswordUnchanged: {
+		English: "{field} must differ from the current password",
+		Lao:     "{field} ຕ້ອງແຕກຕ່າງຈາກລະຫັດຜ່ານປັດຈຸບັນ",
+		Thai:    "{field} ต้องแตกต่างจากรหัสผ่านปัจจุบัน",
+	},
+	InvalidColor: {
+		English: "{field} must be a hex color such as #1a3c8c",
+		Lao:     "{field} ຕ້ອງເປັນສີແບບ hex ເຊັ່ນ #1a3c8c",
+		Thai:    "{field} ต้องเป็นสีแบบ hex เช่น #1a3c8c",
+	},
+	InvalidHandle: {
+		English: "{field} must be a valid account ID",
+		Lao:     "{field} ຕ້ອງເປັນ ID ບັນຊີທີ່ຖືກຕ້ອງ",
+		Thai:    "{field} ต้องเป็น ID บัญชีที่ถูกต้อง",
+	},
+	InvalidLinkedIn: {
+		English: "{field} must be a LinkedIn profile URL such as https://www.linkedin.com/in/name",
+		Lao:     "{field} ຕ້ອງເປັນ URL ໂປຣໄຟລ໌ LinkedIn ເຊັ່ນ https://www.linkedin.com/in/name",
+		Thai:    "{field} ต้องเป็น URL โปรไฟล์ LinkedIn เช่น https://www.linkedin.com/in/name",
+	},
+	InvalidGeo: {
+		English: "{field} must give both latitude and longitude, within -90 to 90 and -180 to 180",
+		Lao:     "{field} ຕ້ອງມີທັງ latitude ແລະ longitude, ໃນຊ່ວງ -90 ຫາ 90 ແລະ -180 ຫາ 180",
+		Thai:    "{field} ต้องมีทั้ง latitude และ longitude ในช่วง -90 ถึง 90 และ -180 ถึง 180",
+	},
+}
+
+// violationKeys are the keys used as field violation reasons rather than
+// error reasons.
+var violationKeys = map[Key]bool{
+	Required:          true,
+	InvalidPhone:      true,
+	UnsupportedFormat: true,
+	NonCorporateEmail: true,
+	InvalidWord:       true,
+	UnsupportedLang:   true,
+	TooLong:           true,
+	InvalidEmail:      true,
+	UnsupportedNDEF:   true,
+	UnsupportedTag:    true,
+	UnsupportedOS:     true,
+	UnsupportedPaper:  true,
+	InvalidWindow:     true,
+	InvalidExpiry:     true,
+	InvalidDate:       true,
+	InvalidDateRange:  true,
+	InvalidScope:      true,
+	InvalidLayout:     true,
+	InvalidWeight:     true,
+	InvalidVariants:   true,
+	InvalidValue:      true,
+	InvalidQRSize:     true,
+	UnsupportedLevel:  true,
+	UnsupportedVCard:  true,
+	UnsupportedExport: true,
+	UnsupportedBundle: true,
+	UnknownColumn:     true,
+	TooShort:          true,
+	HTTPSRequired:     true,
+	UnsupportedEvent:  true,
+	InvalidQuantity:   true,
+	PasswordUnchanged: true,
+	InvalidColor:      true,
+	InvalidHandle:     true,
+	InvalidLinkedIn:   true,
+	InvalidGeo:        true,
+}
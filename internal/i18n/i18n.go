@@ -0,0 +1,230 @@
+// Package i18n translates API error messages. Errors are built from a
+// catalog key, which travels with the error as its ErrorInfo reason, and
+// the message is translated into the caller's language when the error is
+// written to the response.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	rpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Domain is the ErrorInfo domain of errors built by this package.
+const Domain = "contactqr"
+
+type Lang string
+
+const (
+	English Lang = "en"
+	Lao     Lang = "lo"
+	Thai    Lang = "th"
+)
+
+// ParseLang returns the supported language tagged s, e.g. "lo" or "lo-LA".
+func ParseLang(s string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	switch lang := Lang(primary); lang {
+	case English, Lao, Thai:
+		return lang, true
+	}
+	return "", false
+}
+
+// Negotiate picks the supported language the Accept-Language header
+// prefers most. It falls back to English.
+func Negotiate(acceptLanguage string) Lang {
+	type pref struct {
+		lang Lang
+		q    float64
+	}
+
+	prefs := make([]pref, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, ok := ParseLang(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{lang: lang, q: q})
+		}
+	}
+	if len(prefs) == 0 {
+		return English
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+	return prefs[0].lang
+}
+
+// Status returns a status for key with the English message. args are
+// name/value pairs filling the {name} placeholders of the message; they are
+// kept in the ErrorInfo metadata so the message can be translated later.
+func Status(c codes.Code, key Key, args ...string) *rpcStatus.Status {
+	metadata := make(map[string]string, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		metadata[args[i]] = args[i+1]
+	}
+
+	s, _ := rpcStatus.New(c, Message(key, English, metadata)).
+		WithDetails(&edPb.ErrorInfo{
+			Reason:   string(key),
+			Domain:   Domain,
+			Metadata: metadata,
+		})
+	return s
+}
+
+// Error is Status as an error.
+func Error(c codes.Code, key Key, args ...string) error {
+	return Status(c, key, args...).Err()
+}
+
+// Message returns the message of key in lang, or in English when it has no
+// translation.
+func Message(key Key, lang Lang, metadata map[string]string) string {
+	msgs, ok := catalog[key]
+	if !ok {
+		return string(key)
+	}
+
+	msg, ok := msgs[lang]
+	if !ok {
+		msg = msgs[English]
+	}
+	for k, v := range metadata {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", v)
+	}
+
+	return msg
+}
+
+// IsViolation reports whether key is a field violation key.
+func IsViolation(key Key) bool {
+	return violationKeys[key]
+}
+
+// Violation returns a field violation with key as its reason, so clients
+// can tell violations apart without matching on the description.
+func Violation(field string, key Key) *edPb.BadRequest_FieldViolation {
+	return &edPb.BadRequest_FieldViolation{
+		Field:       field,
+		Description: Message(key, English, map[string]string{"field": field}),
+		Reason:      string(key),
+	}
+}
+
+// Localize returns s with its message translated into lang and a localized
+// message added to each field violation. Statuses not built by this package
+// are returned unchanged.
+func Localize(s *rpcStatus.Status, lang Lang) *rpcStatus.Status {
+	if lang == English {
+		return s
+	}
+
+	var info *edPb.ErrorInfo
+	for _, d := range s.Details() {
+		if d, ok := d.(*edPb.ErrorInfo); ok && d.GetDomain() == Domain {
+			info = d
+		}
+	}
+	if info == nil {
+		return s
+	}
+	if _, ok := catalog[Key(info.GetReason())]; !ok {
+		return s
+	}
+
+	p := s.Proto()
+	p.Message = Message(Key(info.GetReason()), lang, info.GetMetadata())
+	for i, d := range p.GetDetails() {
+		br := new(edPb.BadRequest)
+		if !d.MessageIs(br) || d.UnmarshalTo(br) != nil {
+			continue
+		}
+
+		for _, v := range br.GetFieldViolations() {
+			if _, ok := catalog[Key(v.GetReason())]; !ok {
+				continue
+			}
+			v.LocalizedMessage = &edPb.LocalizedMessage{
+				Locale:  string(lang),
+				Message: Message(Key(v.GetReason()), lang, map[string]string{"field": v.GetField()}),
+			}
+		}
+		if a, err := anypb.New(br); err == nil {
+			p.Details[i] = a
+		}
+	}
+
+	return rpcStatus.FromProto(p)
+}
+
+// Entry describes one key of the catalog.
+type Entry struct {
+	Reason string `json:"reason"`
+
+	// Violation is set for field violation reasons, which appear in a
+	// BadRequest detail rather than as the error's reason.
+	Violation bool `json:"violation"`
+
+	// Metadata names the placeholders the message is filled with: ErrorInfo
+	// metadata for errors, the field path for violations.
+	Metadata []string `json:"metadata"`
+
+	Messages map[Lang]string `json:"messages"`
+}
+
+// Catalog returns every key clients may receive, sorted by reason.
+func Catalog() []*Entry {
+	entries := make([]*Entry, 0, len(catalog))
+	for key, msgs := range catalog {
+		e := &Entry{
+			Reason:    string(key),
+			Violation: violationKeys[key],
+			Metadata:  placeholders(msgs[English]),
+			Messages:  make(map[Lang]string, len(msgs)),
+		}
+		for lang, msg := range msgs {
+			e.Messages[lang] = msg
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Reason < entries[j].Reason
+	})
+	return entries
+}
+
+// placeholders returns the {name} placeholders of msg in order.
+func placeholders(msg string) []string {
+	names := make([]string, 0)
+	for {
+		_, rest, ok := strings.Cut(msg, "{")
+		if !ok {
+			return names
+		}
+		name, rest, ok := strings.Cut(rest, "}")
+		if !ok {
+			return names
+		}
+		names = append(names, name)
+		msg = rest
+	}
+}
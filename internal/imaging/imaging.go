@@ -0,0 +1,80 @@
+// Package imaging decodes card photos and scales them for the images
+// cards are published with, using the standard library only.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+)
+
+// Decode decodes a JPEG or PNG image.
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// EncodePNG encodes img as PNG.
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Fill scales img to cover w by h pixels, cropping the longer side around
+// its center. Each pixel is the average of the source pixels it covers, so
+// photos scaled down stay smooth.
+func Fill(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	// The source rectangle with the aspect ratio of w by h.
+	cw, ch := sw, sw*h/w
+	if ch > sh {
+		cw, ch = sh*w/h, sh
+	}
+	x0, y0 := b.Min.X+(sw-cw)/2, b.Min.Y+(sh-ch)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		sy0, sy1 := y0+y*ch/h, y0+(y+1)*ch/h
+		sy1 = max(sy1, sy0+1)
+		for x := range w {
+			sx0, sx1 := x0+x*cw/w, x0+(x+1)*cw/w
+			sx1 = max(sx1, sx0+1)
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// Solid returns a w by h image of color c.
+func Solid(c color.Color, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return dst
+}
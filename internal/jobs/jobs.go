@@ -0,0 +1,266 @@
+// Package jobs runs side effects such as emails in the background, off the
+// request path. A job is stored in the same transaction as the change that
+// causes it, so it is neither lost when the process dies after the commit
+// nor run for a change that was rolled back.
+//
+// Workers on every replica claim jobs from the shared table. A job that
+// fails is retried with exponential backoff and, after maxAttempts, kept as
+// dead for someone to look into, see Check. Delivery is at least once:
+// a worker dying mid-job leaves it to be run again once its lease ends,
+// so handlers should tolerate running twice.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxAttempts is how many times a job is run before it is dead.
+	maxAttempts = 8
+
+	// lease is how long a worker may run a job before another one may
+	// claim it again. Handlers are stopped after it.
+	lease = 5 * time.Minute
+
+	// firstRetry is the wait after the first failure, doubled after each
+	// further one up to maxRetry.
+	firstRetry = 30 * time.Second
+	maxRetry   = time.Hour
+)
+
+// Handler runs a job of its kind from the payload it was enqueued with.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Execer is where a job is stored: the transaction of the change causing
+// it or, for side effects of no change, the database.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type Queue struct {
+	db   *sql.DB
+	zlog *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	draining bool
+	running  sync.WaitGroup
+}
+
+func NewQueue(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Queue, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Queue{
+		db:       db,
+		zlog:     zlog,
+		handlers: make(map[string]Handler),
+	}, nil
+}
+
+// Handle registers the handler of the jobs of kind, e.g. "card.mail". It
+// must be called before Run.
+func (q *Queue) Handle(kind string, h Handler) error {
+	if kind == "" {
+		return errors.New("job kind is empty")
+	}
+	if h == nil {
+		return errors.New("handler is nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.handlers[kind]; ok {
+		return fmt.Errorf("job kind %s already has a handler", kind)
+	}
+	q.handlers[kind] = h
+
+	return nil
+}
+
+// Enqueue stores a job of kind with payload encoded as JSON. It is run once
+// ex, if a transaction, commits.
+func (q *Queue) Enqueue(ctx context.Context, ex Execer, kind string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	return createJob(ctx, ex, kind, b, time.Now())
+}
+
+// Run starts workers claiming jobs every interval until ctx is done. A
+// worker that ran a job claims the next one immediately to drain a
+// backlog quickly.
+func (q *Queue) Run(ctx context.Context, workers int, interval time.Duration) {
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, interval)
+		}()
+	}
+	wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for q.begin() {
+			ran, err := q.runNext(ctx)
+			q.running.Done()
+			if err != nil && !errors.Is(err, context.Canceled) {
+				q.zlog.Error("failed to run job", zap.Error(err))
+			}
+			if err != nil || !ran {
+				break
+			}
+		}
+	}
+}
+
+// begin reports whether a worker may claim a job, counting it as running
+// until Done.
+func (q *Queue) begin() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.draining {
+		return false
+	}
+	q.running.Add(1)
+	return true
+}
+
+// runNext claims the next due job and runs it, reporting whether there was
+// one.
+func (q *Queue) runNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	j, err := claimJob(ctx, q.db, now, now.Add(lease))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	zlog := q.zlog.With(
+		zap.Int64("job_id", j.id),
+		zap.String("kind", j.kind),
+		zap.Int("attempt", j.attempts),
+	)
+
+	runErr := q.exec(ctx, j)
+
+	// Record the result even when ctx was cancelled mid-run.
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	switch {
+	case runErr == nil:
+		if err := deleteJob(rctx, q.db, j.id); err != nil {
+			return true, err
+		}
+
+	case j.attempts >= maxAttempts:
+		zlog.Error("job dead", zap.Error(runErr))
+		if err := failJob(rctx, q.db, j.id, runErr, time.Time{}); err != nil {
+			return true, err
+		}
+
+	default:
+		retryAt := time.Now().Add(retryAfter(j.attempts))
+		zlog.Warn("job failed", zap.Time("retry_at", retryAt), zap.Error(runErr))
+		if err := failJob(rctx, q.db, j.id, runErr, retryAt); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
+func (q *Queue) exec(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	q.mu.RLock()
+	h, ok := q.handlers[j.kind]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler for job kind %s", j.kind)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+
+	return h(ctx, j.payload)
+}
+
+// retryAfter is the wait after a job failed its attempts-th run.
+func retryAfter(attempts int) time.Duration {
+	d := firstRetry << (attempts - 1)
+	if d <= 0 || d > maxRetry {
+		return maxRetry
+	}
+	return d
+}
+
+// Drain stops claiming jobs and waits for the ones in progress on this
+// replica to finish or for ctx to be done.
+func (q *Queue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	q.draining = true
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running: %w", ctx.Err())
+	}
+}
+
+// Check reports an error while jobs are dead, with the error the latest of
+// them failed with.
+func (q *Queue) Check(ctx context.Context) error {
+	n, latest, err := countDeadJobs(ctx, q.db)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%d jobs dead, latest %d %s: %s", n, latest.id, latest.kind, latest.lastError)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Job statuses. Jobs that ran are deleted.
+const (
+	statusPending = "PENDING"
+	statusDead    = "DEAD"
+)
+
+type job struct {
+	id        int64
+	kind      string
+	payload   []byte
+	attempts  int
+	lastError string
+}
+
+func createJob(ctx context.Context, ex Execer, kind string, payload []byte, now time.Time) error {
+	q, args := sq.
+		Insert("dbo.job_queue").
+		Columns(
+			"kind",
+			"payload",
+			"status",
+			"run_at",
+			"created_at",
+		).
+		Values(
+			kind,
+			string(payload),
+			statusPending,
+			pager.DateTime(now),
+			pager.DateTime(now),
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := ex.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create job: %w", err)
+	}
+
+	return nil
+}
+
+// claimJob takes the next due job whose lease, if any, has ended and leases
+// it until until. Rows locked by other workers are skipped, so each job is
+// claimed by one worker at a time.
+func claimJob(ctx context.Context, db *sql.DB, now, until time.Time) (*job, error) {
+	q := `
+WITH next AS (
+  SELECT TOP 1 * FROM dbo.job_queue WITH (UPDLOCK, READPAST, ROWLOCK)
+  WHERE status = @p1 AND run_at <= @p2 AND (locked_until IS NULL OR locked_until <= @p2)
+  ORDER BY run_at, id
+)
+UPDATE next SET attempts = attempts + 1, locked_until = @p3
+OUTPUT inserted.id, inserted.kind, inserted.payload, inserted.attempts;`
+
+	var j job
+	var payload string
+	err := db.QueryRowContext(ctx, q, statusPending, pager.DateTime(now), pager.DateTime(until)).Scan(
+		&j.id,
+		&j.kind,
+		&payload,
+		&j.attempts,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to execute claim job: %w", err)
+	}
+	j.payload = []byte(payload)
+
+	return &j, nil
+}
+
+func deleteJob(ctx context.Context, db *sql.DB, id int64) error {
+	q, args := sq.
+		Delete("dbo.job_queue").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// failJob records why a job failed and releases it to run again at retryAt,
+// or, with a zero retryAt, marks it dead.
+func failJob(ctx context.Context, db *sql.DB, id int64, cause error, retryAt time.Time) error {
+	msg := []rune(cause.Error())
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+
+	b := sq.
+		Update("dbo.job_queue").
+		Set("locked_until", nil).
+		Set("last_error", string(msg)).
+		Where(sq.Eq{"id": id})
+	if retryAt.IsZero() {
+		b = b.Set("status", statusDead)
+	} else {
+		b = b.Set("run_at", pager.DateTime(retryAt))
+	}
+	q, args := b.PlaceholderFormat(sq.AtP).MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// countDeadJobs returns the number of dead jobs and the latest of them.
+func countDeadJobs(ctx context.Context, db *sql.DB) (int64, *job, error) {
+	q, args := sq.
+		Select(
+			"TOP 1 COUNT(*) OVER ()",
+			"id",
+			"kind",
+			"last_error",
+		).
+		From("dbo.job_queue").
+		Where(sq.Eq{"status": statusDead}).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var n int64
+	var j job
+	err := db.QueryRowContext(ctx, q, args...).Scan(&n, &j.id, &j.kind, &j.lastError)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return n, &j, nil
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+)
+
+// InternalOnly guards operational endpoints meant for the load balancer and
+// deploy tooling. With a token set, requests must send it in the
+// X-Internal-Token header; without one, only loopback clients are allowed.
+// The client is the peer of the connection: X-Forwarded-For and X-Real-IP
+// are set by whoever sends the request, so they are not trusted.
+func InternalOnly(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token != "" {
+				got := c.Request().Header.Get("X-Internal-Token")
+				if subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+					return next(c)
+				}
+			} else if ip := net.ParseIP(echo.ExtractIPDirect()(c.Request())); ip != nil && ip.IsLoopback() {
+				return next(c)
+			}
+
+			return i18n.Error(codes.PermissionDenied, i18n.InternalOnly)
+		}
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"time"
 
 	"aidanwoods.dev/go-paseto"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"google.golang.org/grpc/codes"
-	rpcStatus "google.golang.org/grpc/status"
 )
 
 type pasetoExtractor func(echo.Context) (string, error)
@@ -30,7 +31,8 @@ type PASETOConfig struct {
 
 	ErrorHandler func(echo.Context, error) error
 
-	SymmetricKey paseto.V4SymmetricKey
+	// Keys are the versions of the key tokens are encrypted with.
+	Keys *auth.KeyRing
 
 	Implicit []byte
 
@@ -62,24 +64,18 @@ func PASETO(config PASETOConfig) echo.MiddlewareFunc {
 					return config.ErrorHandler(c, err)
 				}
 
-				return rpcStatus.Error(
-					codes.Unauthenticated,
-					"Your provided token is not valid. Please provide a valid token and try again.",
-				)
+				return i18n.Error(codes.Unauthenticated, i18n.InvalidToken)
 			}
 
 			rules := append(config.Rules, paseto.NotExpired(), paseto.ValidAt(time.Now()))
 			parser := paseto.MakeParser(rules)
-			token, err := parser.ParseV4Local(config.SymmetricKey, tainted, config.Implicit)
+			token, err := config.Keys.Parse(parser, tainted, config.Implicit)
 			if err != nil {
 				if config.ErrorHandler != nil {
 					return config.ErrorHandler(c, err)
 				}
 
-				return rpcStatus.Error(
-					codes.Unauthenticated,
-					"Your provided token is not valid. Please provide a valid token and try again.",
-				)
+				return i18n.Error(codes.Unauthenticated, i18n.InvalidToken)
 			}
 
 			c.Set(config.ContextKey, token)
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// RateLimitConfig limits the requests of each client IP.
+type RateLimitConfig struct {
+	Store ratelimit.Store
+
+	// Default is the limit of routes not in Routes, counted across them.
+	Default ratelimit.Limit
+
+	// Routes are the limits of single routes by method and path, e.g.
+	// "POST /v1/auth/login", counted per route instead of Default.
+	Routes map[string]ratelimit.Limit
+}
+
+// RateLimit rejects requests over the limit of their route. Requests are
+// let through when the store fails, so an outage of Redis does not take the
+// API down with it.
+func RateLimit(cfg RateLimitConfig, zlog *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route := c.Request().Method + " " + c.Path()
+			key, limit := c.RealIP(), cfg.Default
+			if l, ok := cfg.Routes[route]; ok {
+				key, limit = route+"|"+key, l
+			}
+
+			ok, err := cfg.Store.Allow(c.Request().Context(), key, limit)
+			if err != nil {
+				zlog.Warn("failed to check rate limit", zap.Error(err))
+				return next(c)
+			}
+			if !ok {
+				return i18n.Error(codes.ResourceExhausted, i18n.TooManyRequests)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// newLimited returns a server whose login allows one request a second,
+// finding clients with ClientIP(trusted).
+func newLimited(t *testing.T, trusted []string) *echo.Echo {
+	t.Helper()
+
+	nets, err := ParseNetworks(trusted)
+	if err != nil {
+		t.Fatalf("ParseNetworks: %v", err)
+	}
+
+	e := echo.New()
+	e.IPExtractor = ClientIP(nets)
+	e.Use(RateLimit(RateLimitConfig{
+		Store:   ratelimit.NewMemoryStore(time.Minute),
+		Default: ratelimit.Per(100),
+		Routes: map[string]ratelimit.Limit{
+			"POST /v1/auth/login": ratelimit.Per(1),
+		},
+	}, zap.NewNop()))
+	e.POST("/v1/auth/login", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	return e
+}
+
+// login sends a login from the peer remote with X-Forwarded-For xff, if not
+// empty, and returns the status.
+func login(e *echo.Echo, remote, xff string) int {
+	req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", nil)
+	req.RemoteAddr = net.JoinHostPort(remote, "40000")
+	if xff != "" {
+		req.Header.Set(echo.HeaderXForwardedFor, xff)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	e := newLimited(t, nil)
+
+	if code := login(e, "203.0.113.7", ""); code != http.StatusNoContent {
+		t.Fatalf("first login = %d, want %d", code, http.StatusNoContent)
+	}
+
+	// Each retry claims to come from somewhere else.
+	for _, xff := range []string{"198.51.100.1", "198.51.100.2", "10.0.0.3, 198.51.100.4"} {
+		if code := login(e, "203.0.113.7", xff); code == http.StatusNoContent {
+			t.Fatalf("login with X-Forwarded-For %q = %d, want it limited", xff, code)
+		}
+	}
+
+	// Another client has its own limit.
+	if code := login(e, "203.0.113.8", ""); code != http.StatusNoContent {
+		t.Fatalf("login of another client = %d, want %d", code, http.StatusNoContent)
+	}
+}
+
+func TestRateLimitBehindTrustedProxy(t *testing.T) {
+	e := newLimited(t, []string{"10.0.0.0/8"})
+
+	// The proxy forwards two clients, each with its own limit.
+	if code := login(e, "10.1.2.3", "203.0.113.7"); code != http.StatusNoContent {
+		t.Fatalf("first login = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := login(e, "10.1.2.3", "203.0.113.8"); code != http.StatusNoContent {
+		t.Fatalf("login of another client = %d, want %d", code, http.StatusNoContent)
+	}
+
+	// A client prepending an address of its choice is still itself: the
+	// proxy appends the address it saw.
+	if code := login(e, "10.1.2.3", "198.51.100.1, 203.0.113.7"); code == http.StatusNoContent {
+		t.Fatalf("login with a spoofed X-Forwarded-For = %d, want it limited", code)
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	nets, err := ParseNetworks([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("ParseNetworks: %v", err)
+	}
+	for i, ip := range []string{"10.200.0.1", "192.0.2.1", "2001:db8::1"} {
+		if !nets[i].Contains(net.ParseIP(ip)) {
+			t.Fatalf("%v does not contain %s", nets[i], ip)
+		}
+	}
+	if nets[1].Contains(net.ParseIP("192.0.2.2")) {
+		t.Fatalf("%v contains 192.0.2.2, want the single address", nets[1])
+	}
+
+	if _, err := ParseNetworks([]string{"proxy.internal"}); err == nil {
+		t.Fatal("ParseNetworks of a host name succeeded, want an error")
+	}
+}
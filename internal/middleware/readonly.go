@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/10664kls/contactqr/internal/health"
+	"github.com/labstack/echo/v4"
+)
+
+// ReadOnlyOnOutage rejects mutating requests while the database is
+// unreachable, so clients get an immediate answer instead of a timeout. The
+// Retry-After header tells them when to try again.
+func ReadOnlyOnOutage(h *health.State) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			if !h.ReadOnly() {
+				return next(c)
+			}
+
+			secs := int(math.Ceil(h.RetryAfter().Seconds()))
+			c.Response().Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+			return h.ReadOnlyError()
+		}
+	}
+}
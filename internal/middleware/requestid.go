@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestID gives every request an ID: the one in the X-Request-ID header,
+// if it is a valid one, or a new one. The ID is echoed in the response and
+// stored in the request context, see package reqid.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(reqid.Header)
+			if !reqid.Valid(id) {
+				id = reqid.New()
+			}
+
+			c.Response().Header().Set(reqid.Header, id)
+			c.SetRequest(req.WithContext(reqid.ContextWithID(req.Context(), id)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/labstack/echo/v4"
+)
+
+// SetContextRoles resolves the roles of the caller, whose claims are
+// already in the context, for the services' permission checks.
+func SetContextRoles(resolver *rbac.Resolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+			roles := resolver.Resolve(ctx, auth.ClaimsFromContext(ctx))
+			c.SetRequest(req.WithContext(rbac.ContextWithRoles(ctx, roles)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// SecureConfig holds the security headers for the JSON API and for the HTML
+// pages served to people scanning a card.
+type SecureConfig struct {
+	API  middleware.SecureConfig
+	Page middleware.SecureConfig
+
+	// PagePrefixes are the path prefixes served as HTML pages. Every other
+	// path gets the API headers.
+	PagePrefixes []string
+}
+
+// DefaultSecureConfig locks the API down completely, since it never renders
+// HTML, and allows public pages to load their own assets and inline styles.
+var DefaultSecureConfig = SecureConfig{
+	API: middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            31536000,
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:        "no-referrer",
+	},
+	Page: middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "SAMEORIGIN",
+		HSTSMaxAge:            31536000,
+		ContentSecurityPolicy: "default-src 'self'; img-src 'self' data:; style-src 'self' 'unsafe-inline'; frame-ancestors 'self'",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	},
+	PagePrefixes: []string{"/p/"},
+}
+
+func Secure(config SecureConfig) echo.MiddlewareFunc {
+	isPage := func(c echo.Context) bool {
+		path := c.Request().URL.Path
+		for _, prefix := range config.PagePrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	api := config.API
+	api.Skipper = isPage
+	apiMw := middleware.SecureWithConfig(api)
+
+	page := config.Page
+	page.Skipper = func(c echo.Context) bool { return !isPage(c) }
+	pageMw := middleware.SecureWithConfig(page)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return apiMw(pageMw(next))
+	}
+}
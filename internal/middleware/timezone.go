@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+)
+
+// Timezone sets the timezone timestamps are displayed in for the request:
+// the IANA name in the X-Timezone header, or def without one.
+func Timezone(def *time.Location) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			loc := def
+			if name := strings.TrimSpace(c.Request().Header.Get(tz.Header)); name != "" {
+				l, err := time.LoadLocation(name)
+				if err != nil {
+					return i18n.Error(codes.InvalidArgument, i18n.InvalidTimezone, "timezone", name)
+				}
+				loc = l
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(tz.ContextWithLocation(req.Context(), loc)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing records a span of every request, named after its route, e.g.
+// "GET /v1/business-cards". A traceparent header continues the caller's
+// trace. Errors are handled here, so the span has the final status.
+func Tracing() echo.MiddlewareFunc {
+	tracer := otel.Tracer("github.com/10664kls/contactqr")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+					attribute.String("http.request_id", reqid.FromContext(ctx)),
+				),
+			)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+				c.Error(err)
+			}
+
+			n := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(n))
+			if n >= 500 {
+				span.SetStatus(codes.Error, http.StatusText(n))
+			}
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package migrate checks and applies the SQL migrations in migrations/,
+// which are embedded in the binary.
+//
+// It keeps its state in dbo.schema_migrations using the same layout as the
+// golang-migrate CLI, so databases migrated by hand with that tool are
+// recognised and can be taken over.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrDirty is returned when a previous migration failed halfway and the
+	// schema needs fixing by hand.
+	ErrDirty = errors.New("database schema is dirty")
+
+	// ErrAhead is returned when the database has migrations this binary does
+	// not know about, i.e. a newer release already ran against it.
+	ErrAhead = errors.New("database schema is ahead of this binary")
+
+	// ErrPending is returned by Check when migrations have not been applied.
+	ErrPending = errors.New("database schema has pending migrations")
+)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+}
+
+// Status is the database schema version compared to the migrations known to
+// the binary.
+type Status struct {
+	Current uint64
+	Latest  uint64
+	Dirty   bool
+	Pending []*Migration
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	zlog       *zap.Logger
+}
+
+func NewMigrator(_ context.Context, db *sql.DB, fsys fs.FS, zlog *zap.Logger) (*Migrator, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if fsys == nil {
+		return nil, errors.New("fsys is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		zlog:       zlog,
+	}, nil
+}
+
+// load reads the <version>_<name>.up.sql files in fsys, ordered by version.
+func load(fsys fs.FS) ([]*Migration, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]*Migration, 0, len(files))
+	seen := make(map[uint64]string)
+	for _, f := range files {
+		base := strings.TrimSuffix(path.Base(f), ".up.sql")
+		v, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", f)
+		}
+		version, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", f, err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %q and %q share version %d", other, f, version)
+		}
+		seen[version] = f
+
+		up, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, &Migration{
+			Version: version,
+			Name:    name,
+			Up:      string(up),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	current, dirty, err := getVersion(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Status{
+		Current: current,
+		Dirty:   dirty,
+	}
+	for _, mig := range m.migrations {
+		st.Latest = mig.Version
+		if mig.Version > current {
+			st.Pending = append(st.Pending, mig)
+		}
+	}
+
+	return st, nil
+}
+
+// Check returns an error unless the database schema matches the binary.
+func (m *Migrator) Check(ctx context.Context) (*Status, error) {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case st.Dirty:
+		return st, fmt.Errorf("%w at version %d, fix it by hand and reset the version", ErrDirty, st.Current)
+
+	case st.Current > st.Latest:
+		return st, fmt.Errorf("%w: database is at version %d, latest known is %d", ErrAhead, st.Current, st.Latest)
+
+	case len(st.Pending) > 0:
+		return st, fmt.Errorf("%w: database is at version %d, %d to apply up to %d", ErrPending, st.Current, len(st.Pending), st.Latest)
+	}
+
+	return st, nil
+}
+
+// Up applies pending migrations in order. Each migration runs in its own
+// transaction under an application lock, so replicas starting together
+// apply it once.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := createVersionTable(ctx, m.db); err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		applied, err := m.apply(ctx, mig)
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if applied {
+			m.zlog.Info("applied migration",
+				zap.Uint64("version", mig.Version),
+				zap.String("name", mig.Name),
+			)
+		}
+	}
+
+	_, err := m.Check(ctx)
+	return err
+}
+
+func (m *Migrator) apply(ctx context.Context, mig *Migration) (bool, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := lock(ctx, tx); err != nil {
+		return false, err
+	}
+
+	current, dirty, err := getVersion(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if dirty {
+		return false, fmt.Errorf("%w at version %d", ErrDirty, current)
+	}
+	if mig.Version <= current {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+		return false, err
+	}
+	if err := setVersion(ctx, tx, mig.Version); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func createVersionTable(ctx context.Context, db *sql.DB) error {
+	q := `
+IF OBJECT_ID('dbo.schema_migrations', 'U') IS NULL
+  CREATE TABLE dbo.schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    dirty BIT NOT NULL
+  )`
+
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("failed to execute create version table: %w", err)
+	}
+
+	return nil
+}
+
+// getVersion returns the schema version, or 0 when no migration was ever
+// applied.
+func getVersion(ctx context.Context, db querier) (uint64, bool, error) {
+	q := `
+IF OBJECT_ID('dbo.schema_migrations', 'U') IS NULL
+  SELECT CAST(0 AS BIGINT), CAST(0 AS BIT)
+ELSE
+  SELECT TOP 1 version, dirty FROM dbo.schema_migrations ORDER BY version DESC`
+
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, q).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return uint64(version), dirty, nil
+}
+
+func setVersion(ctx context.Context, tx *sql.Tx, version uint64) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM dbo.schema_migrations"); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	q := "INSERT INTO dbo.schema_migrations (version, dirty) VALUES (@p1, 0)"
+	if _, err := tx.ExecContext(ctx, q, int64(version)); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// lock serializes migrators until tx ends.
+func lock(ctx context.Context, tx *sql.Tx) error {
+	q := `
+DECLARE @result INT;
+EXEC @result = sp_getapplock @Resource = 'contactqr-migrate', @LockMode = 'Exclusive', @LockOwner = 'Transaction', @LockTimeout = 60000;
+IF @result < 0 THROW 50000, 'failed to acquire migration lock', 1;`
+
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package ndef encodes NFC Data Exchange Format messages for writing to
+// the NFC tags embedded in printed cards.
+package ndef
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// Type name formats of a record.
+const (
+	TNFWellKnown = 0x01
+	TNFMedia     = 0x02
+)
+
+const (
+	flagMB = 0x80 // message begin
+	flagME = 0x40 // message end
+	flagSR = 0x10 // short record
+)
+
+// Record is one record of an NDEF message.
+type Record struct {
+	TNF     byte
+	Type    []byte
+	Payload []byte
+}
+
+// uriPrefixes are the abbreviations of the URI record type, in the order
+// they should be tried: longer prefixes first.
+var uriPrefixes = []struct {
+	code   byte
+	prefix string
+}{
+	{0x02, "https://www."},
+	{0x01, "http://www."},
+	{0x04, "https://"},
+	{0x03, "http://"},
+	{0x05, "tel:"},
+	{0x06, "mailto:"},
+}
+
+// URIRecord returns a well-known URI record, abbreviating its scheme as the
+// spec allows to save tag space.
+func URIRecord(uri string) Record {
+	code := byte(0x00)
+	for _, p := range uriPrefixes {
+		if strings.HasPrefix(uri, p.prefix) {
+			code = p.code
+			uri = uri[len(p.prefix):]
+			break
+		}
+	}
+
+	return Record{
+		TNF:     TNFWellKnown,
+		Type:    []byte("U"),
+		Payload: append([]byte{code}, uri...),
+	}
+}
+
+// MediaRecord returns a record carrying payload of the given MIME type,
+// e.g. text/vcard.
+func MediaRecord(mimeType string, payload []byte) Record {
+	return Record{
+		TNF:     TNFMedia,
+		Type:    []byte(mimeType),
+		Payload: payload,
+	}
+}
+
+// Encode returns the NDEF message made of records.
+func Encode(records ...Record) []byte {
+	var b []byte
+	for i, r := range records {
+		header := r.TNF
+		if i == 0 {
+			header |= flagMB
+		}
+		if i == len(records)-1 {
+			header |= flagME
+		}
+
+		short := len(r.Payload) < 256
+		if short {
+			header |= flagSR
+		}
+
+		b = append(b, header, byte(len(r.Type)))
+		if short {
+			b = append(b, byte(len(r.Payload)))
+		} else {
+			b = binary.BigEndian.AppendUint32(b, uint32(len(r.Payload)))
+		}
+		b = append(b, r.Type...)
+		b = append(b, r.Payload...)
+	}
+
+	return b
+}
+
+// Tags are the user memory sizes, in bytes, of the NFC Forum Type 2 tags
+// used in printed cards.
+var Tags = map[string]int{
+	"ntag213": 144,
+	"ntag215": 504,
+	"ntag216": 888,
+}
+
+// TagSize returns the bytes a message of n bytes takes on a Type 2 tag:
+// the message wrapped in an NDEF TLV, followed by a terminator TLV.
+func TagSize(n int) int {
+	if n < 0xFF {
+		return n + 3
+	}
+	return n + 5
+}
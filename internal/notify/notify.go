@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"go.uber.org/zap"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+
+	// Lang is the language the message is written in, for channels that
+	// localize their own framing.
+	Lang i18n.Lang
+}
+
+// Notifier delivers messages to people.
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// LogNotifier writes messages to the log instead of delivering them. It is
+// used when no delivery channel is configured.
+type LogNotifier struct {
+	zlog *zap.Logger
+}
+
+func NewLogNotifier(zlog *zap.Logger) (*LogNotifier, error) {
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &LogNotifier{zlog: zlog}, nil
+}
+
+func (n *LogNotifier) Notify(_ context.Context, msg *Message) error {
+	n.zlog.Info("notification",
+		zap.String("to", strings.Join(msg.To, ",")),
+		zap.String("lang", string(msg.Lang)),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+	)
+	return nil
+}
@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig is the mail server notifications are sent through.
+type SMTPConfig struct {
+	// Addr is the server's host:port, e.g. smtp.example.com:587.
+	Addr string
+
+	// Username and Password authenticate with PLAIN auth. Both empty
+	// sends without authenticating, e.g. through an internal relay.
+	Username string
+	Password string
+
+	// From is the sender, e.g. "ContactQR <no-reply@example.com>".
+	From string
+}
+
+// SMTPNotifier emails messages. It upgrades the connection with STARTTLS
+// whenever the server offers it and refuses to authenticate without it.
+type SMTPNotifier struct {
+	config SMTPConfig
+	from   *mail.Address
+	host   string
+}
+
+func NewSMTPNotifier(config SMTPConfig) (*SMTPNotifier, error) {
+	host, _, err := net.SplitHostPort(config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address: %w", err)
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp sender: %w", err)
+	}
+
+	return &SMTPNotifier{
+		config: config,
+		from:   from,
+		host:   host,
+	}, nil
+}
+
+// smtpTimeout bounds a delivery when ctx has no deadline.
+const smtpTimeout = 30 * time.Second
+
+func (n *SMTPNotifier) Notify(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("message has no recipient")
+	}
+
+	c, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Mail(n.from.Address); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to send to %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	if _, err := w.Write(n.compose(msg)); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// Ping connects and authenticates to the server without sending mail.
+func (n *SMTPNotifier) Ping(ctx context.Context) error {
+	c, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.Quit()
+}
+
+// dial connects to the server, upgrading to TLS and authenticating when
+// configured. The connection stops working once ctx's deadline, or
+// smtpTimeout, passes.
+func (n *SMTPNotifier) dial(ctx context.Context) (*smtp.Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", n.config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start smtp: %w", err)
+	}
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if n.config.Username != "" || n.config.Password != "" {
+		// smtp.PlainAuth refuses to send credentials in the clear.
+		if err := c.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.host)); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// compose writes msg as a plain text UTF-8 email.
+func (n *SMTPNotifier) compose(msg *Message) []byte {
+	var b strings.Builder
+	header := func(k, v string) {
+		b.WriteString(k + ": " + v + "\r\n")
+	}
+
+	header("From", n.from.String())
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	if msg.Lang != "" {
+		header("Content-Language", string(msg.Lang))
+	}
+	b.WriteString("\r\n")
+
+	// SMTP requires CRLF line endings.
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+)
+
+// Template is a notification written in each supported language. Subject
+// and body are text/template sources executed with the message's data.
+type Template struct {
+	Subject map[i18n.Lang]string
+	Body    map[i18n.Lang]string
+}
+
+// Render returns the message in lang, or in English when the template has
+// no translation. The recipients are left to the caller.
+func (t *Template) Render(lang i18n.Lang, data any) (*Message, error) {
+	if _, ok := t.Subject[lang]; !ok {
+		lang = i18n.English
+	}
+
+	subject, err := execute(t.Subject[lang], data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	body, err := execute(t.Body[lang], data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return &Message{
+		Lang:    lang,
+		Subject: subject,
+		Body:    body,
+	}, nil
+}
+
+func execute(src string, data any) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
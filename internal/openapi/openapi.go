@@ -0,0 +1,337 @@
+// Package openapi generates the OpenAPI 3 document of the REST API from the
+// Go types its handlers bind and respond with, so the contract cannot drift
+// from the code, and embeds a Swagger UI to browse it.
+package openapi
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// UI is the Swagger UI page, index.html, and its script, init.js, which
+// loads the document from /v1/openapi.json.
+//
+//go:embed ui/*
+var UI embed.FS
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       *Info                `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lowercase HTTP method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// Route describes one route of the API.
+type Route struct {
+	Method string
+
+	// Path is the Echo path of the route, e.g. /v1/business-cards/:id.
+	Path string
+
+	// OperationID names the operation in generated clients, by convention
+	// the name of its handler.
+	OperationID string
+	Summary     string
+
+	// Public routes are called without an access token.
+	Public bool
+
+	// Params is the struct the handler binds, whose param tags name path
+	// parameters and query tags query parameters. Path parameters without
+	// a field are strings.
+	Params any
+
+	// Body is the JSON request body. Consumes names the media type of a
+	// raw request body instead, e.g. a photo.
+	Body     any
+	Consumes string
+
+	// Response is the data of a successful response, and for a Page the
+	// type of its items. Produces names the media types, comma separated,
+	// of a response that is not JSON, e.g. image/png.
+	Response any
+	Page     bool
+	Produces string
+
+	// Status is the status of a successful response. Default: 200.
+	Status int
+}
+
+// bearer is the security scheme of routes called with an access token.
+const bearer = "bearerAuth"
+
+// Build returns the document of routes. Responses are described in the v1
+// envelope, with errors as errType.
+func Build(info *Info, errType any, routes []*Route) (*Document, error) {
+	g := &generator{schemas: make(map[string]*Schema)}
+
+	g.schemas["Metadata"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"nextPageToken": {Type: "string"},
+			"totalSize":     {Type: "integer", Format: "int64"},
+			"requestId":     {Type: "string"},
+		},
+	}
+	g.schemas["Envelope"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"apiVersion": {Type: "string"},
+			"data":       {},
+			"metadata":   ref("Metadata"),
+			"error":      g.schema(reflect.TypeOf(errType)),
+		},
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: &Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearer: {Type: "http", Scheme: "bearer", BearerFormat: "PASETO"},
+			},
+		},
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		key := r.Method + " " + r.Path
+		switch {
+		case r.OperationID == "":
+			errs = append(errs, fmt.Errorf("route %s has no operation id", key))
+			continue
+		case seen[key]:
+			errs = append(errs, fmt.Errorf("route %s is described twice", key))
+			continue
+		}
+		seen[key] = true
+
+		path, names := parsePath(r.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(r.Method)] = g.operation(r, names)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Missing returns the routes, given as "METHOD path" with Echo paths, that
+// the document does not describe.
+func (d *Document) Missing(routes []string) []string {
+	missing := make([]string, 0)
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		path, _ = parsePath(path)
+		item, ok := d.Paths[path]
+		if !ok || (*item)[strings.ToLower(method)] == nil {
+			missing = append(missing, route)
+		}
+	}
+	return missing
+}
+
+// parsePath turns an Echo path into an OpenAPI path and returns the names
+// of its parameters.
+func parsePath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	names := make([]string, 0)
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			segments[i] = "{" + name + "}"
+			names = append(names, name)
+			continue
+		}
+		segments[i] = strings.ReplaceAll(s, `\:`, ":")
+	}
+	return strings.Join(segments, "/"), names
+}
+
+func (g *generator) operation(r *Route, pathParams []string) *Operation {
+	op := &Operation{
+		OperationID: r.OperationID,
+		Summary:     r.Summary,
+		Tags:        []string{tag(r.Path)},
+		Parameters:  g.parameters(r.Params, pathParams),
+		Responses: map[string]*Response{
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(ref("Envelope")),
+			},
+		},
+	}
+	if !r.Public {
+		op.Security = []map[string][]string{{bearer: {}}}
+	}
+
+	switch {
+	case r.Consumes != "":
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{r.Consumes: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	case r.Body != nil:
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(g.schema(reflect.TypeOf(r.Body))),
+		}
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	res := &Response{Description: http.StatusText(status)}
+	switch {
+	case r.Produces != "":
+		res.Content = make(map[string]*MediaType)
+		for _, media := range strings.Split(r.Produces, ",") {
+			res.Content[media] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	case r.Response != nil:
+		data := g.schema(reflect.TypeOf(r.Response))
+		if r.Page {
+			data = &Schema{Type: "array", Items: data}
+		}
+		res.Content = jsonContent(&Schema{AllOf: []*Schema{
+			ref("Envelope"),
+			{Type: "object", Properties: map[string]*Schema{"data": data}},
+		}})
+	}
+	op.Responses[fmt.Sprint(status)] = res
+
+	return op
+}
+
+func (g *generator) parameters(params any, pathParams []string) []*Parameter {
+	byName := make(map[string]*Parameter)
+	query := make([]*Parameter, 0)
+	var bind func(t reflect.Type)
+	bind = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for f := range fields(t) {
+			// Echo binds the fields of embedded structs too.
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				bind(f.Type)
+				continue
+			}
+			if name := f.Tag.Get("param"); name != "" {
+				byName[name] = &Parameter{Name: name, In: "path", Required: true, Schema: g.schema(f.Type)}
+			}
+			if name := f.Tag.Get("query"); name != "" {
+				query = append(query, &Parameter{Name: name, In: "query", Schema: g.schema(f.Type)})
+			}
+		}
+	}
+	if params != nil {
+		bind(reflect.TypeOf(params))
+	}
+
+	out := make([]*Parameter, 0, len(pathParams)+len(query))
+	for _, name := range pathParams {
+		p, ok := byName[name]
+		if !ok {
+			p = &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		}
+		out = append(out, p)
+	}
+	slices.SortFunc(query, func(a, b *Parameter) int { return strings.Compare(a.Name, b.Name) })
+	return append(out, query...)
+}
+
+// tag groups a route by the first segment of its path after the version,
+// e.g. business-cards.
+func tag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) < 2 {
+		return segments[0]
+	}
+	name, _, _ := strings.Cut(segments[1], `\:`)
+	return name
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"iter"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Viewer is implemented by types whose MarshalJSON encodes a view of them
+// rather than their fields, e.g. with timestamps in the viewer's timezone.
+// The document describes the type of the view JSONView returns.
+type Viewer interface {
+	JSONView() any
+}
+
+// generator describes Go types and proto messages as schemas, named types
+// once in the components they reference.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	rawType       = reflect.TypeFor[json.RawMessage]()
+	messageType   = reflect.TypeFor[proto.Message]()
+	viewerType    = reflect.TypeFor[Viewer]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+	textType      = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch {
+	case t.Implements(messageType):
+		md := reflect.New(t.Elem()).Interface().(proto.Message).ProtoReflect().Descriptor()
+		return g.message(md)
+
+	case reflect.PointerTo(t).Implements(viewerType) && t.Kind() == reflect.Struct:
+		return g.named(t, func() *Schema {
+			view := reflect.New(t).Interface().(Viewer).JSONView()
+			return g.object(reflect.TypeOf(view))
+		})
+	}
+
+	if t.Kind() == reflect.Pointer {
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType:
+		return &Schema{}
+	case t.Kind() != reflect.Struct && (t.Implements(marshalerType) || t.Implements(textType)):
+		// Enumerations such as a card's status encode as their name.
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.named(t, func() *Schema { return g.object(t) })
+	}
+	return &Schema{}
+}
+
+// named returns a reference to the component of the named type t, which
+// describe adds on first use. Anonymous types are described inline.
+func (g *generator) named(t reflect.Type, describe func() *Schema) *Schema {
+	if t.Name() == "" {
+		return describe()
+	}
+
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	if _, ok := g.schemas[name]; !ok {
+		// A placeholder first, for types that refer to themselves.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *describe()
+	}
+	return ref(name)
+}
+
+// object describes the struct t as encoding/json encodes it.
+func (g *generator) object(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for f := range fields(t) {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, v := range g.object(ft).Properties {
+				if _, ok := s.Properties[k]; !ok {
+					s.Properties[k] = v
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schema(f.Type)
+	}
+	return s
+}
+
+// fields yields the fields of the struct t.
+func fields(t reflect.Type) iter.Seq[reflect.StructField] {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			if !yield(t.Field(i)) {
+				return
+			}
+		}
+	}
+}
+
+// message describes md as protojson encodes it.
+func (g *generator) message(md protoreflect.MessageDescriptor) *Schema {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return &Schema{Type: "string"}
+	case "google.protobuf.Empty", "google.protobuf.Struct", "google.protobuf.Any":
+		return &Schema{Type: "object"}
+	case "google.protobuf.Value":
+		return &Schema{}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &Schema{Type: "string", Nullable: true}
+	case "google.protobuf.BoolValue":
+		return &Schema{Type: "boolean", Nullable: true}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &Schema{Type: "integer", Format: "int32", Nullable: true}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue":
+		return &Schema{Type: "number", Nullable: true}
+	}
+
+	name := string(md.FullName())
+	if _, ok := g.schemas[name]; !ok {
+		g.schemas[name] = &Schema{}
+
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		fds := md.Fields()
+		for i := range fds.Len() {
+			fd := fds.Get(i)
+			switch {
+			case fd.IsMap():
+				s.Properties[fd.JSONName()] = &Schema{Type: "object", AdditionalProperties: g.field(fd.MapValue())}
+			case fd.IsList():
+				s.Properties[fd.JSONName()] = &Schema{Type: "array", Items: g.field(fd)}
+			default:
+				s.Properties[fd.JSONName()] = g.field(fd)
+			}
+		}
+		*g.schemas[name] = *s
+	}
+	return ref(name)
+}
+
+func (g *generator) field(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson encodes 64-bit integers as strings.
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &Schema{Type: "number"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		s := &Schema{Type: "string", Enum: make([]string, 0, values.Len())}
+		for i := range values.Len() {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.message(fd.Message())
+	}
+	return &Schema{Type: "string"}
+}
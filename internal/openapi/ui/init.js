@@ -0,0 +1,9 @@
+window.ui = SwaggerUIBundle({
+  url: "/v1/openapi.json",
+  dom_id: "#swagger-ui",
+  deepLinking: true,
+  requestInterceptor: function (req) {
+    req.headers["X-Response-Envelope"] = "v1";
+    return req;
+  },
+});
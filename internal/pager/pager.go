@@ -4,6 +4,9 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	mssql "github.com/denisenkom/go-mssqldb"
 )
 
 // Size returns the size of the page.
@@ -19,6 +22,16 @@ func Size(size uint64) uint64 {
 	return size
 }
 
+// Fetch returns the clause selecting the page-th page of size rows, counting
+// from 1, for lists in offset mode. Offset mode lets a client jump to any
+// page and tell how many there are, but the database still reads the rows
+// it skips and pages shift as rows are added, so cursors stay the default.
+// The query must be ordered and must not select TOP rows.
+func Fetch(page, size uint64) sq.Sqlizer {
+	size = Size(size)
+	return sq.Expr("OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", (max(page, 1)-1)*size, size)
+}
+
 // Cursor is designed for this project only, if you need to filter or order-by
 // other field than id you must change this.
 type Cursor struct {
@@ -26,6 +39,40 @@ type Cursor struct {
 	Time time.Time `json:"time"`
 }
 
+// After returns a predicate that selects the rows following the cursor when
+// rows are ordered by (timeColumn DESC, idColumn DESC). Comparing on both
+// columns keeps pages stable when many rows share the same timestamp.
+func (c *Cursor) After(timeColumn, idColumn string) sq.Sqlizer {
+	t := DateTime(c.Time)
+	return sq.Or{
+		sq.Lt{timeColumn: t},
+		sq.And{
+			sq.Eq{timeColumn: t},
+			sq.Lt{idColumn: c.ID},
+		},
+	}
+}
+
+// Since returns a predicate that selects the rows following the cursor when
+// rows are ordered by (timeColumn ASC, idColumn ASC), as sync does.
+func (c *Cursor) Since(timeColumn, idColumn string) sq.Sqlizer {
+	t := DateTime(c.Time)
+	return sq.Or{
+		sq.Gt{timeColumn: t},
+		sq.And{
+			sq.Eq{timeColumn: t},
+			sq.Gt{idColumn: c.ID},
+		},
+	}
+}
+
+// DateTime binds t as a DATETIME parameter. The driver sends time.Time as
+// DATETIMEOFFSET by default, which forces SQL Server to convert the column
+// and prevents index seeks on DATETIME columns.
+func DateTime(t time.Time) any {
+	return mssql.DateTime1(t)
+}
+
 // EncodeCursor encodes the cursor.
 func EncodeCursor(c *Cursor) string {
 	cj, _ := json.Marshal(c)
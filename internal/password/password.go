@@ -0,0 +1,311 @@
+// Package password lets users change their password and HR send employees
+// a one-time link to reset theirs. Passwords are stored bcrypt hashed and
+// take over from the legacy ones of the HR login table, see
+// auth.User.Compare.
+package password
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrResetTokenNotFound = errors.New("reset token not found")
+)
+
+// Checker checks the current password of a user, see auth.Auth.
+type Checker interface {
+	CheckPassword(ctx context.Context, username, password string) (bool, error)
+}
+
+type Service struct {
+	db       *sql.DB
+	checker  Checker
+	notifier notify.Notifier
+	zlog     *zap.Logger
+	resetURL string
+	resetTTL time.Duration
+}
+
+// NewService creates a Service. resetURL is a format string receiving the
+// reset token, linking to the page where an employee sets a new password;
+// the link works once, within resetTTL.
+func NewService(_ context.Context, db *sql.DB, checker Checker, notifier notify.Notifier, zlog *zap.Logger, resetURL string, resetTTL time.Duration) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if checker == nil {
+		return nil, errors.New("checker is nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+	if resetTTL <= 0 {
+		return nil, errors.New("reset ttl must be positive")
+	}
+
+	return &Service{
+		db:       db,
+		checker:  checker,
+		notifier: notifier,
+		zlog:     zlog,
+		resetURL: resetURL,
+		resetTTL: resetTTL,
+	}, nil
+}
+
+type ChangeReq struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (r *ChangeReq) Validate() error {
+	// Login trims passwords too.
+	r.OldPassword = strings.TrimSpace(r.OldPassword)
+	r.NewPassword = strings.TrimSpace(r.NewPassword)
+
+	violations, err := validate.Violations(&contactqrPb.ChangePasswordRequest{
+		OldPassword: r.OldPassword,
+		NewPassword: r.NewPassword,
+	})
+	if err != nil {
+		return err
+	}
+	if r.NewPassword != "" && r.NewPassword == r.OldPassword {
+		violations = append(violations, i18n.Violation("newPassword", i18n.PasswordUnchanged))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPasswordChange).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// Change sets the caller's password, once they prove they know the current
+// one.
+func (s *Service) Change(ctx context.Context, in *ChangeReq) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "Change"),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	passed, err := s.checker.CheckPassword(ctx, claims.Code, in.OldPassword)
+	if err != nil {
+		zlog.Error("failed to check password", zap.Error(err))
+		return err
+	}
+	if !passed {
+		zlog.Info("old password is incorrect")
+		return i18n.Error(codes.InvalidArgument, i18n.IncorrectPassword)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(in.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := savePassword(ctx, s.db, claims.Code, string(hash), claims.Code, time.Now()); err != nil {
+		zlog.Error("failed to save password", zap.Error(err))
+		return err
+	}
+
+	zlog.Info("password changed")
+	return nil
+}
+
+// IssueReset emails the employee a link to set a new password. It is for
+// HR only.
+func (s *Service) IssueReset(ctx context.Context, employeeID int64) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "IssueReset"),
+		zap.String("username", claims.Code),
+		zap.Int64("employee_id", employeeID),
+	)
+
+	if !rbac.Can(ctx, rbac.ResetPasswords) {
+		return i18n.Error(codes.PermissionDenied, i18n.PasswordsForbidden)
+	}
+
+	u, err := getUserByEmployeeID(ctx, s.db, employeeID)
+	if errors.Is(err, ErrUserNotFound) {
+		return i18n.Error(codes.NotFound, i18n.UserNotFound)
+	}
+	if err != nil {
+		zlog.Error("failed to get user by employee id", zap.Error(err))
+		return err
+	}
+	if u.email == "" {
+		return i18n.Error(codes.FailedPrecondition, i18n.NoEmailAddress)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	now := time.Now()
+	if err := createReset(ctx, s.db, &reset{
+		tokenHash: hashToken(token),
+		username:  u.username,
+		createdBy: claims.Code,
+		createdAt: now,
+		expiresAt: now.Add(s.resetTTL),
+	}); err != nil {
+		zlog.Error("failed to create password reset", zap.Error(err))
+		return err
+	}
+
+	msg, err := resetTemplate.Render(u.lang, map[string]string{
+		"Name":    u.displayName,
+		"URL":     fmt.Sprintf(s.resetURL, token),
+		"Expires": now.Add(s.resetTTL).Format(time.RFC1123),
+	})
+	if err != nil {
+		zlog.Error("failed to render password reset notification", zap.Error(err))
+		return err
+	}
+	msg.To = []string{u.email}
+
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		zlog.Error("failed to notify password reset", zap.Error(err))
+		return err
+	}
+
+	zlog.Info("password reset issued", zap.String("employee", u.username))
+	return nil
+}
+
+type ResetReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (r *ResetReq) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	r.NewPassword = strings.TrimSpace(r.NewPassword)
+
+	violations, err := validate.Violations(&contactqrPb.ResetPasswordRequest{
+		Token:       r.Token,
+		NewPassword: r.NewPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidPasswordReset).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// Reset sets the password of the employee a reset token was issued to. The
+// token works once.
+func (s *Service) Reset(ctx context.Context, in *ResetReq) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "Reset"),
+	)
+
+	if err := in.Validate(); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(in.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	username, err := resetPassword(ctx, s.db, hashToken(in.Token), string(hash), time.Now())
+	if errors.Is(err, ErrResetTokenNotFound) {
+		zlog.Info("reset token is not valid")
+		return i18n.Error(codes.InvalidArgument, i18n.InvalidResetToken)
+	}
+	if err != nil {
+		zlog.Error("failed to reset password", zap.Error(err))
+		return err
+	}
+
+	zlog.Info("password reset", zap.String("username", username))
+	return nil
+}
+
+// hashToken returns the hex SHA-256 of a reset token, which is all that is
+// stored of it.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+var resetTemplate = &notify.Template{
+	Subject: map[i18n.Lang]string{
+		i18n.English: "Reset your business card account password",
+		i18n.Lao:     "ຕັ້ງລະຫັດຜ່ານບັນຊີນາມບັດຂອງທ່ານໃໝ່",
+		i18n.Thai:    "รีเซ็ตรหัสผ่านบัญชีนามบัตรของคุณ",
+	},
+	Body: map[i18n.Lang]string{
+		i18n.English: `Hello {{.Name}},
+
+HR asked for your password to be reset. Set a new one here:
+{{.URL}}
+
+The link works once and expires at {{.Expires}}.
+`,
+		i18n.Lao: `ສະບາຍດີ {{.Name}},
+
+HR ໄດ້ຂໍໃຫ້ຕັ້ງລະຫັດຜ່ານຂອງທ່ານໃໝ່. ກະລຸນາຕັ້ງລະຫັດຜ່ານໃໝ່ທີ່ນີ້:
+{{.URL}}
+
+ລິ້ງນີ້ໃຊ້ໄດ້ຄັ້ງດຽວ ແລະ ໝົດອາຍຸເວລາ {{.Expires}}.
+`,
+		i18n.Thai: `สวัสดี {{.Name}},
+
+HR ได้ขอให้รีเซ็ตรหัสผ่านของคุณ กรุณาตั้งรหัสผ่านใหม่ที่นี่:
+{{.URL}}
+
+ลิงก์นี้ใช้ได้ครั้งเดียวและหมดอายุเวลา {{.Expires}}
+`,
+	},
+}
+
+// user is the login of an employee and where to reach them.
+type user struct {
+	username    string
+	displayName string
+	email       string
+	lang        i18n.Lang
+}
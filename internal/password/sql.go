@@ -0,0 +1,130 @@
+package password
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// reset is an issued reset token.
+type reset struct {
+	tokenHash string
+	username  string
+	createdBy string
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+func getUserByEmployeeID(ctx context.Context, db *sql.DB, employeeID int64) (*user, error) {
+	q, args := sq.
+		Select(
+			"TOP 1 u.username",
+			"CONCAT(e.nameeng, ' ', e.surnameeng) AS display_name",
+			// Reset links go to the address HR keeps, not one set on a card.
+			"COALESCE(e.Emails, '')",
+			"COALESCE(p.notification_language, 'en')",
+		).
+		From("dbo.tb_userlogin AS u").
+		InnerJoin("dbo.vm_employee AS e ON u.eid = e.EID").
+		LeftJoin("dbo.employee_preference AS p ON p.employee_id = e.EID").
+		Where(sq.Eq{"e.EID": employeeID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var u user
+	err := db.QueryRowContext(ctx, q, args...).Scan(
+		&u.username,
+		&u.displayName,
+		&u.email,
+		&u.lang,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return &u, nil
+}
+
+func createReset(ctx context.Context, db *sql.DB, in *reset) error {
+	q, args := sq.
+		Insert("dbo.password_reset").
+		Columns(
+			"token_hash",
+			"username",
+			"created_by",
+			"created_at",
+			"expires_at",
+		).
+		Values(
+			in.tokenHash,
+			in.username,
+			in.createdBy,
+			pager.DateTime(in.createdAt),
+			pager.DateTime(in.expiresAt),
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create password reset: %w", err)
+	}
+
+	return nil
+}
+
+// savePasswordQuery sets the password hash of a user, @p1, to @p2.
+const savePasswordQuery = `
+MERGE dbo.user_password WITH (HOLDLOCK) AS t
+USING (SELECT @p1 AS username) AS s ON t.username = s.username
+WHEN MATCHED THEN UPDATE SET password_hash = @p2, changed_by = @p3, changed_at = @p4
+WHEN NOT MATCHED THEN INSERT (username, password_hash, changed_by, changed_at) VALUES (@p1, @p2, @p3, @p4);`
+
+func savePassword(ctx context.Context, db *sql.DB, username, hash, by string, at time.Time) error {
+	if _, err := db.ExecContext(ctx, savePasswordQuery, username, hash, by, pager.DateTime(at)); err != nil {
+		return fmt.Errorf("failed to execute save password: %w", err)
+	}
+
+	return nil
+}
+
+// resetPassword uses the reset token unless it was used or has expired,
+// sets the password of its user to hash and returns the user.
+func resetPassword(ctx context.Context, db *sql.DB, tokenHash, hash string, now time.Time) (string, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := `
+UPDATE dbo.password_reset SET used_at = @p2
+OUTPUT inserted.username
+WHERE token_hash = @p1 AND used_at IS NULL AND expires_at > @p2;`
+
+	var username string
+	err = tx.QueryRowContext(ctx, q, tokenHash, pager.DateTime(now)).Scan(&username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrResetTokenNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute use reset token: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, savePasswordQuery, username, hash, username, pager.DateTime(now)); err != nil {
+		return "", fmt.Errorf("failed to execute save password: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return username, nil
+}
@@ -0,0 +1,180 @@
+// Package phone parses phone numbers as staff type them and formats them
+// for storage and display.
+package phone
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	e164 "github.com/nyaruka/phonenumbers"
+)
+
+// DefaultRegion is used when neither the caller nor the company configures
+// a region.
+const DefaultRegion = "LA"
+
+var ErrInvalid = errors.New("invalid phone number")
+
+type Number struct {
+	// E164 is the canonical form, e.g. +8562055123456.
+	E164 string `json:"e164"`
+
+	// International is the display form used on cards and in vCards,
+	// e.g. +856 20 55 123 456.
+	International string `json:"international"`
+
+	// National is the display form dialled inside the country,
+	// e.g. 020 55 123 456.
+	National string `json:"national"`
+
+	// Region is the ISO 3166-1 alpha-2 code of the number's country.
+	Region string `json:"region"`
+}
+
+// Parse parses raw as a number in region unless it carries its own country
+// code. Besides the forms libphonenumber accepts it tolerates the ways
+// numbers are commonly written locally: a 00 international prefix, the
+// country code without a plus and a missing trunk 0 (20 55 123 456 for
+// 020 55 123 456).
+func Parse(raw, region string) (*Number, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	digits := clean(raw)
+	if digits == "" {
+		return nil, ErrInvalid
+	}
+
+	for _, candidate := range candidates(digits, region) {
+		n, err := e164.Parse(candidate, region)
+		if err != nil || !e164.IsValidNumber(n) {
+			continue
+		}
+
+		return &Number{
+			E164:          e164.Format(n, e164.E164),
+			International: e164.Format(n, e164.INTERNATIONAL),
+			National:      e164.Format(n, e164.NATIONAL),
+			Region:        e164.GetRegionCodeForNumber(n),
+		}, nil
+	}
+
+	return nil, ErrInvalid
+}
+
+// clean drops separators and turns a leading 00 into +.
+func clean(raw string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		}
+	}
+
+	s := b.String()
+	if rest, ok := strings.CutPrefix(s, "00"); ok {
+		s = "+" + rest
+	}
+	return s
+}
+
+// candidates returns the readings of digits to try, most likely first.
+func candidates(digits, region string) []string {
+	if strings.HasPrefix(digits, "+") {
+		return []string{digits}
+	}
+
+	out := []string{digits}
+	if cc := e164.GetCountryCodeForRegion(region); cc > 0 {
+		prefix := strconv.Itoa(cc)
+		if strings.HasPrefix(digits, prefix) {
+			out = append(out, "+"+digits)
+		}
+	}
+	if !strings.HasPrefix(digits, "0") {
+		out = append(out, "0"+digits)
+	}
+
+	return out
+}
+
+// Regions picks the default region of a company's phone numbers.
+type Regions struct {
+	Default   string
+	Companies map[int64]string
+}
+
+// For returns the default region for numbers entered by staff of the
+// company.
+func (r Regions) For(companyID int64) string {
+	if region, ok := r.Companies[companyID]; ok {
+		return region
+	}
+	if r.Default != "" {
+		return r.Default
+	}
+	return DefaultRegion
+}
+
+// Style is how a number is displayed.
+type Style string
+
+const (
+	// International displays numbers with their country code, e.g.
+	// +856 20 55 123 456.
+	International Style = "international"
+
+	// National displays numbers as dialled inside their country, e.g.
+	// 020 55 123 456.
+	National Style = "national"
+)
+
+// ParseStyle returns the style named s.
+func ParseStyle(s string) (Style, bool) {
+	switch st := Style(strings.ToLower(strings.TrimSpace(s))); st {
+	case International, National:
+		return st, true
+	}
+	return "", false
+}
+
+// Format displays the E.164 number n in style st. A number that does not
+// parse is returned as is.
+func Format(n string, st Style) string {
+	if n == "" {
+		return ""
+	}
+
+	pn, err := e164.Parse(n, "")
+	if err != nil {
+		return n
+	}
+
+	if st == National {
+		return e164.Format(pn, e164.NATIONAL)
+	}
+	return e164.Format(pn, e164.INTERNATIONAL)
+}
+
+// Styles picks the display style of a company's phone numbers.
+type Styles struct {
+	Default   Style
+	Companies map[int64]Style
+}
+
+// For returns the style numbers on the company's cards are displayed in.
+func (s Styles) For(companyID int64) Style {
+	if st, ok := s.Companies[companyID]; ok {
+		return st
+	}
+	if s.Default != "" {
+		return s.Default
+	}
+	return International
+}
@@ -0,0 +1,89 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// KMSConfig is the AWS KMS key data keys are wrapped with.
+type KMSConfig struct {
+	// KeyID is the ID, ARN or alias of a symmetric key, e.g.
+	// alias/contactqr-pii.
+	KeyID  string
+	Region string
+
+	// Endpoint replaces the regional endpoint, e.g. for a VPC endpoint.
+	Endpoint string
+
+	Credentials aws.Credentials
+}
+
+// KMSKeyWrapper wraps data keys with a key held by AWS KMS, which never
+// leaves it. Unwrapped keys are cached by the Cipher, so KMS is called
+// once per data key.
+type KMSKeyWrapper struct {
+	client *kms.Client
+	keyID  string
+}
+
+func NewKMSKeyWrapper(cfg KMSConfig) (*KMSKeyWrapper, error) {
+	if cfg.KeyID == "" {
+		return nil, errors.New("key ID is empty")
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("region is empty")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, errors.New("credentials are empty")
+	}
+
+	creds := cfg.Credentials
+	client := kms.New(kms.Options{
+		Region: cfg.Region,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return creds, nil
+		}),
+		BaseEndpoint: baseEndpoint(cfg.Endpoint),
+	})
+
+	return &KMSKeyWrapper{
+		client: client,
+		keyID:  cfg.KeyID,
+	}, nil
+}
+
+func (w *KMSKeyWrapper) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	out, err := w.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(w.keyID),
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with KMS: %w", err)
+	}
+
+	return out.CiphertextBlob, nil
+}
+
+func (w *KMSKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	// The wrapped key names the KMS key it was wrapped with, which may be
+	// one the key ID pointed to before it was rotated.
+	out, err := w.client.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with KMS: %w", err)
+	}
+
+	return out.Plaintext, nil
+}
+
+func baseEndpoint(endpoint string) *string {
+	if endpoint == "" {
+		return nil
+	}
+	return aws.String(endpoint)
+}
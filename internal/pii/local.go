@@ -0,0 +1,40 @@
+package pii
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// LocalKeyWrapper wraps data keys with a key-encryption key held in process
+// memory. It is meant for environments without a KMS.
+type LocalKeyWrapper struct {
+	kek []byte
+}
+
+// NewLocalKeyWrapper creates a LocalKeyWrapper from a hex encoded 32 byte key.
+func NewLocalKeyWrapper(hexKey string) (*LocalKeyWrapper, error) {
+	kek, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key: %w", err)
+	}
+	if len(kek) != 32 {
+		return nil, errors.New("key must be 32 bytes")
+	}
+
+	return &LocalKeyWrapper{kek: kek}, nil
+}
+
+func (w *LocalKeyWrapper) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	nonce, ct, err := seal(w.kek, dek)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nonce, ct...), nil
+}
+
+func (w *LocalKeyWrapper) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(w.kek, wrapped)
+}
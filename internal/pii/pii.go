@@ -0,0 +1,293 @@
+// Package pii encrypts personal data such as phone numbers and emails before
+// it is written to the database.
+//
+// Values are protected with envelope encryption: a random data key encrypts
+// the value with AES-GCM and is itself wrapped by a key-encryption key held by
+// a KeyWrapper (e.g. a KMS). The wrapped data key is stored with every value
+// so keys can be rotated without re-encrypting old rows.
+package pii
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const prefix = "enc:v1:"
+
+// KeyWrapper wraps and unwraps data keys with a key-encryption key.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+type Cipher struct {
+	kek     KeyWrapper
+	dek     []byte
+	wrapped []byte
+
+	mu   sync.RWMutex
+	deks map[string][]byte
+}
+
+// NewCipher creates a Cipher with a fresh data key wrapped by kek.
+func NewCipher(ctx context.Context, kek KeyWrapper) (*Cipher, error) {
+	if kek == nil {
+		return nil, errors.New("kek is nil")
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := kek.WrapKey(ctx, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	return &Cipher{
+		kek:     kek,
+		dek:     dek,
+		wrapped: wrapped,
+		deks: map[string][]byte{
+			string(wrapped): dek,
+		},
+	}, nil
+}
+
+// Encrypt returns the encrypted form of s. Empty strings are kept as is so
+// optional columns stay empty.
+func (c *Cipher) Encrypt(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	nonce, ct, err := seal(c.dek, []byte(s))
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, 2+len(c.wrapped)+len(nonce)+len(ct))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(c.wrapped)))
+	buf = append(buf, c.wrapped...)
+	buf = append(buf, nonce...)
+	buf = append(buf, ct...)
+
+	return prefix + base64.RawStdEncoding.EncodeToString(buf), nil
+}
+
+// Decrypt reverses Encrypt. Values written before encryption was enabled
+// do not carry the prefix and are returned unchanged.
+func (c *Cipher) Decrypt(ctx context.Context, s string) (string, error) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, nil
+	}
+
+	buf, err := base64.RawStdEncoding.DecodeString(s[len(prefix):])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode value: %w", err)
+	}
+	if len(buf) < 2 {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	n := int(binary.BigEndian.Uint16(buf))
+	buf = buf[2:]
+	if len(buf) < n {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	dek, err := c.dataKey(ctx, buf[:n])
+	if err != nil {
+		return "", err
+	}
+
+	pt, err := open(dek, buf[n:])
+	if err != nil {
+		return "", err
+	}
+
+	return string(pt), nil
+}
+
+func (c *Cipher) dataKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	c.mu.RLock()
+	dek, ok := c.deks[string(wrapped)]
+	c.mu.RUnlock()
+	if ok {
+		return dek, nil
+	}
+
+	dek, err := c.kek.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	c.mu.Lock()
+	c.deks[string(wrapped)] = dek
+	c.mu.Unlock()
+
+	return dek, nil
+}
+
+func seal(key, plaintext []byte) (nonce, ciphertext []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+
+	nonce, ct := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ct, nil)
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Cipher
+)
+
+// ReplaceGlobal sets the Cipher used by Text. A nil Cipher disables
+// encryption and values are stored in plain text.
+func ReplaceGlobal(c *Cipher) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	global = c
+}
+
+func globalCipher() *Cipher {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+
+	return global
+}
+
+// Text is a string column holding personal data. It is encrypted with the
+// global Cipher when written and decrypted when scanned.
+type Text string
+
+func (t Text) Value() (driver.Value, error) {
+	c := globalCipher()
+	if c == nil {
+		return string(t), nil
+	}
+
+	return c.Encrypt(string(t))
+}
+
+func (t *Text) Scan(src any) error {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		*t = ""
+		return nil
+
+	case string:
+		s = src
+
+	case []byte:
+		s = string(src)
+
+	default:
+		return fmt.Errorf("pii: cannot scan %T into Text", src)
+	}
+
+	c := globalCipher()
+	if c == nil {
+		*t = Text(s)
+		return nil
+	}
+
+	pt, err := c.Decrypt(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	*t = Text(pt)
+	return nil
+}
+
+// Bytes is a binary column holding personal data, such as a rendered
+// vCard. It is encrypted with the global Cipher when written and decrypted
+// when scanned, like Text.
+type Bytes []byte
+
+func (b Bytes) Value() (driver.Value, error) {
+	c := globalCipher()
+	if c == nil || len(b) == 0 {
+		return []byte(b), nil
+	}
+
+	s, err := c.Encrypt(string(b))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+func (b *Bytes) Scan(src any) error {
+	var v []byte
+	switch src := src.(type) {
+	case nil:
+		*b = nil
+		return nil
+
+	case []byte:
+		v = src
+
+	case string:
+		v = []byte(src)
+
+	default:
+		return fmt.Errorf("pii: cannot scan %T into Bytes", src)
+	}
+
+	c := globalCipher()
+	if c == nil || !strings.HasPrefix(string(v), prefix) {
+		*b = slices.Clone(v)
+		return nil
+	}
+
+	pt, err := c.Decrypt(context.Background(), string(v))
+	if err != nil {
+		return err
+	}
+
+	*b = Bytes(pt)
+	return nil
+}
@@ -0,0 +1,184 @@
+package pii
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func newTestCipher(t *testing.T) *Cipher {
+	t.Helper()
+
+	kek, err := NewLocalKeyWrapper(testKey)
+	if err != nil {
+		t.Fatalf("NewLocalKeyWrapper: %v", err)
+	}
+	c, err := NewCipher(context.Background(), kek)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+// useCipher makes c the global Cipher for the rest of the test.
+func useCipher(t *testing.T, c *Cipher) {
+	t.Helper()
+
+	ReplaceGlobal(c)
+	t.Cleanup(func() { ReplaceGlobal(nil) })
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t)
+
+	for _, s := range []string{"", "+856 20 5555 1234", "somchai@example.com", "ສົມໃຈ"} {
+		enc, err := c.Encrypt(s)
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", s, err)
+		}
+		if s != "" && (enc == s || !strings.HasPrefix(enc, prefix)) {
+			t.Fatalf("Encrypt(%q) = %q, want it encrypted", s, enc)
+		}
+
+		dec, err := c.Decrypt(context.Background(), enc)
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", enc, err)
+		}
+		if dec != s {
+			t.Fatalf("Decrypt(Encrypt(%q)) = %q", s, dec)
+		}
+	}
+}
+
+func TestCipherDecryptsOtherDataKeys(t *testing.T) {
+	// A restarted process has a fresh data key but must read the values
+	// written with the old one.
+	old, cur := newTestCipher(t), newTestCipher(t)
+
+	enc, err := old.Encrypt("020 5555 1234")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	dec, err := cur.Decrypt(context.Background(), enc)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if dec != "020 5555 1234" {
+		t.Fatalf("Decrypt = %q, want %q", dec, "020 5555 1234")
+	}
+}
+
+func TestCipherPassesPlainTextThrough(t *testing.T) {
+	c := newTestCipher(t)
+
+	dec, err := c.Decrypt(context.Background(), "020 5555 1234")
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if dec != "020 5555 1234" {
+		t.Fatalf("Decrypt = %q, want the value unchanged", dec)
+	}
+}
+
+func TestCipherRejectsTamperedValue(t *testing.T) {
+	c := newTestCipher(t)
+
+	enc, err := c.Encrypt("somchai@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	buf, err := base64.RawStdEncoding.DecodeString(enc[len(prefix):])
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	buf[len(buf)-1] ^= 1
+	tampered := prefix + base64.RawStdEncoding.EncodeToString(buf)
+	if _, err := c.Decrypt(context.Background(), tampered); err == nil {
+		t.Fatal("Decrypt of a tampered value succeeded, want an error")
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	useCipher(t, newTestCipher(t))
+
+	v, err := Text("020 5555 1234").Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	stored, ok := v.(string)
+	if !ok || !strings.HasPrefix(stored, prefix) {
+		t.Fatalf("Value = %#v, want an encrypted string", v)
+	}
+
+	var got Text
+	if err := got.Scan(stored); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if got != "020 5555 1234" {
+		t.Fatalf("Scan = %q, want %q", got, "020 5555 1234")
+	}
+
+	if err := got.Scan(nil); err != nil || got != "" {
+		t.Fatalf("Scan(nil) = %q, %v, want empty", got, err)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	useCipher(t, newTestCipher(t))
+
+	vcf := []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nTEL:+856205551234\r\nEND:VCARD\r\n")
+	v, err := Bytes(vcf).Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	stored, ok := v.([]byte)
+	if !ok || !bytes.HasPrefix(stored, []byte(prefix)) || bytes.Contains(stored, []byte("TEL")) {
+		t.Fatalf("Value = %q, want it encrypted", v)
+	}
+
+	var got Bytes
+	if err := got.Scan(stored); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !bytes.Equal(got, vcf) {
+		t.Fatalf("Scan = %q, want %q", got, vcf)
+	}
+
+	// vCards stored before encryption was enabled are read as they are.
+	if err := got.Scan(vcf); err != nil || !bytes.Equal(got, vcf) {
+		t.Fatalf("Scan of plain text = %q, %v, want it unchanged", got, err)
+	}
+}
+
+func TestBytesKeepsEmpty(t *testing.T) {
+	useCipher(t, newTestCipher(t))
+
+	for _, b := range []Bytes{nil, {}} {
+		v, err := b.Value()
+		if err != nil {
+			t.Fatalf("Value: %v", err)
+		}
+		if got, _ := v.([]byte); len(got) != 0 {
+			t.Fatalf("Value(%q) = %q, want it empty", b, got)
+		}
+	}
+
+	var got Bytes = []byte("x")
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Fatalf("Scan(nil) = %q, %v, want nil", got, err)
+	}
+}
+
+func TestValuesWithoutCipher(t *testing.T) {
+	useCipher(t, nil)
+
+	v, err := Text("020 5555 1234").Value()
+	if err != nil || v != driver.Value("020 5555 1234") {
+		t.Fatalf("Value = %#v, %v, want the plain text", v, err)
+	}
+}
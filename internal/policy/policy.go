@@ -0,0 +1,60 @@
+// Package policy decides how many business cards an employee may hold, so
+// an employee cannot pile up cards waiting for approval or in circulation.
+package policy
+
+import (
+	"strconv"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+	"google.golang.org/grpc/codes"
+)
+
+// Card is a card an employee holds, as the policy sees it.
+type Card struct {
+	ID string
+
+	// Status is the name of the card's status, e.g. PENDING.
+	Status string
+}
+
+// active are the statuses of cards that count against the limit. Rejected
+// and archived cards do not.
+var active = map[string]bool{
+	"PENDING":   true,
+	"APPROVED":  true,
+	"PUBLISHED": true,
+}
+
+// Cards limits the cards an employee may hold.
+type Cards struct {
+	// MaxActive is the most active cards, pending, approved or published,
+	// an employee may hold. 0 is no limit.
+	MaxActive int
+}
+
+// CheckNew returns a FailedPrecondition error if an employee holding held,
+// newest first, may not create another card: while one of them is still
+// pending, which they should update instead, or when they hold MaxActive
+// active cards, naming the oldest one to archive.
+func (p Cards) CheckNew(held []Card) error {
+	holding := make([]Card, 0, len(held))
+	for _, c := range held {
+		if c.Status == "PENDING" {
+			return i18n.Error(codes.FailedPrecondition, i18n.DuplicateCard, "cardId", c.ID, "status", c.Status)
+		}
+		if active[c.Status] {
+			holding = append(holding, c)
+		}
+	}
+
+	if p.MaxActive > 0 && len(holding) >= p.MaxActive {
+		oldest := holding[len(holding)-1]
+		return i18n.Error(codes.FailedPrecondition, i18n.CardLimitReached,
+			"maxActive", strconv.Itoa(p.MaxActive),
+			"cardId", oldest.ID,
+			"status", oldest.Status,
+		)
+	}
+
+	return nil
+}
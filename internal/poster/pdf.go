@@ -0,0 +1,143 @@
+package poster
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// pdf writes a minimal PDF 1.4 document: pages of vector drawing and text
+// in the standard Helvetica font, which every viewer has, so nothing needs
+// embedding.
+type pdf struct {
+	width, height float64
+	pages         []string
+}
+
+func (d *pdf) addPage(content string) {
+	d.pages = append(d.pages, content)
+}
+
+func (d *pdf) bytes() []byte {
+	// Objects 1 and 2 are the catalog and page tree, 3 the font, then a
+	// page object and its content stream for every page.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, written once the page numbers are known
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	kids := make([]string, 0, len(d.pages))
+	for _, content := range d.pages {
+		page := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				num(d.width), num(d.height), page+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content)+1, content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return b.Bytes()
+}
+
+// canvas builds the content stream of one page. Coordinates are in points
+// from the bottom left corner.
+type canvas struct {
+	b strings.Builder
+}
+
+func (c *canvas) fill(col Color) {
+	fmt.Fprintf(&c.b, "%s %s %s rg\n", num(float64(col.R)/255), num(float64(col.G)/255), num(float64(col.B)/255))
+}
+
+func (c *canvas) rect(x, y, w, h float64) {
+	fmt.Fprintf(&c.b, "%s %s %s %s re f\n", num(x), num(y), num(w), num(h))
+}
+
+// text writes s centered on x with its baseline at y.
+func (c *canvas) text(s string, x, y, size float64) {
+	s = winAnsi(s)
+	x -= textWidth(s, size) / 2
+	fmt.Fprintf(&c.b, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n", num(size), num(x), num(y), escape(s))
+}
+
+// qr draws a QR bitmap as a square of side size with its bottom left
+// corner at x, y. Runs of dark modules are drawn as one rectangle to keep
+// the file small.
+func (c *canvas) qr(bitmap [][]bool, x, y, size float64) {
+	module := size / float64(len(bitmap))
+	for row, line := range bitmap {
+		top := y + size - float64(row+1)*module
+		for col := 0; col < len(line); {
+			if !line[col] {
+				col++
+				continue
+			}
+			start := col
+			for col < len(line) && line[col] {
+				col++
+			}
+			c.rect(x+float64(start)*module, top, float64(col-start)*module, module)
+		}
+	}
+}
+
+func (c *canvas) String() string {
+	return c.b.String()
+}
+
+func num(f float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+	return r.Replace(s)
+}
+
+// winAnsi replaces the characters Helvetica cannot show with a question
+// mark. Names on posters come from the English name fields.
+func winAnsi(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 32 || r > 126 {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+// helvetica are the widths of the printable ASCII characters in Helvetica,
+// in thousandths of the font size.
+var helvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+func textWidth(s string, size float64) float64 {
+	var w int
+	for _, r := range s {
+		w += helvetica[r-32]
+	}
+	return float64(w) * size / 1000
+}
@@ -0,0 +1,211 @@
+// Package poster renders printable PDF posters of card QR codes for
+// reception desks, table tents and event booths.
+package poster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Size is a paper size.
+type Size string
+
+const (
+	A4 Size = "a4"
+	A5 Size = "a5"
+)
+
+// dimensions returns the page width and height in points.
+func (s Size) dimensions() (float64, float64) {
+	if s == A5 {
+		return 419.53, 595.28
+	}
+	return 595.28, 841.89
+}
+
+// ParseSize returns the paper size named s.
+func ParseSize(s string) (Size, bool) {
+	switch sz := Size(strings.ToLower(strings.TrimSpace(s))); sz {
+	case A4, A5:
+		return sz, true
+	}
+	return "", false
+}
+
+// Color is an RGB color.
+type Color struct {
+	R, G, B uint8
+}
+
+// ParseColor parses a color written as #RRGGBB.
+func ParseColor(s string) (Color, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return Color{}, fmt.Errorf("invalid color %q, expected #RRGGBB", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q, expected #RRGGBB", s)
+	}
+	return Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+var (
+	black = Color{}
+	white = Color{R: 255, G: 255, B: 255}
+	grey  = Color{R: 96, G: 96, B: 96}
+)
+
+// Brands are the poster colors of each company, keyed by company ID.
+type Brands struct {
+	Default   Color
+	Companies map[int64]Color
+}
+
+// For returns the brand color of the company.
+func (b Brands) For(companyID int64) Color {
+	if c, ok := b.Companies[companyID]; ok {
+		return c
+	}
+	return b.Default
+}
+
+// Entry is one QR code on a poster.
+type Entry struct {
+	Name     string
+	Title    string
+	Subtitle string
+	Phone    string
+
+	// Content is what the QR code encodes, e.g. the card's vCard.
+	Content []byte
+}
+
+// Poster describes a poster to render.
+type Poster struct {
+	Size    Size
+	Brand   Color
+	Company string
+
+	// Heading replaces the default "Scan to save contact".
+	Heading string
+
+	Entries []Entry
+}
+
+// Render returns the poster as a PDF. A single entry fills the page;
+// several are laid out in a grid over as many pages as needed.
+func Render(p *Poster) ([]byte, error) {
+	if len(p.Entries) == 0 {
+		return nil, fmt.Errorf("poster has no entries")
+	}
+	if p.Heading == "" {
+		p.Heading = "Scan to save contact"
+	}
+
+	w, h := p.Size.dimensions()
+	doc := &pdf{width: w, height: h}
+
+	cols, rows := 1, 1
+	if len(p.Entries) > 1 {
+		cols, rows = 2, 3
+		if p.Size == A5 {
+			rows = 2
+		}
+	}
+	perPage := cols * rows
+
+	for start := 0; start < len(p.Entries); start += perPage {
+		end := min(start+perPage, len(p.Entries))
+
+		c := new(canvas)
+		body := header(c, p, w, h)
+
+		cellW := w / float64(cols)
+		cellH := body / float64(rows)
+		for i, e := range p.Entries[start:end] {
+			x := float64(i%cols) * cellW
+			y := body - float64(i/cols+1)*cellH
+			if err := cell(c, e, x, y, cellW, cellH, cols == 1); err != nil {
+				return nil, err
+			}
+		}
+
+		doc.addPage(c.String())
+	}
+
+	return doc.bytes(), nil
+}
+
+// header draws the brand band and the instructions, returning the height
+// left below them.
+func header(c *canvas, p *Poster, w, h float64) float64 {
+	band := h * 0.12
+	c.fill(p.Brand)
+	c.rect(0, h-band, w, band)
+
+	c.fill(white)
+	c.text(p.Company, w/2, h-band/2-fit(p.Company, w*0.9, band*0.3)/3, fit(p.Company, w*0.9, band*0.3))
+
+	c.fill(black)
+	size := fit(p.Heading, w*0.9, h*0.045)
+	c.text(p.Heading, w/2, h-band-size*1.8, size)
+
+	return h - band - size*2.8
+}
+
+// cell draws one entry in the box with its bottom left corner at x, y.
+func cell(c *canvas, e Entry, x, y, w, h float64, large bool) error {
+	q, err := qrcode.New(string(e.Content), qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to encode qr: %w", err)
+	}
+
+	name := h * 0.06
+	if large {
+		name = h * 0.045
+	}
+	name = fit(e.Name, w*0.9, name)
+	small := name * 0.7
+
+	lines := 3.5
+	if e.Phone != "" {
+		lines += 1.5
+	}
+	side := min(w*0.8, h-name*1.5-small*lines)
+	cx := x + w/2
+	top := y + h
+
+	c.fill(black)
+	c.qr(q.Bitmap(), cx-side/2, top-side, side)
+
+	line := top - side - name*1.2
+	c.text(e.Name, cx, line, name)
+
+	c.fill(grey)
+	if e.Title != "" {
+		line -= small * 1.5
+		c.text(e.Title, cx, line, fit(e.Title, w*0.9, small))
+	}
+	if e.Subtitle != "" {
+		line -= small * 1.5
+		c.text(e.Subtitle, cx, line, fit(e.Subtitle, w*0.9, small))
+	}
+	if e.Phone != "" {
+		line -= small * 1.5
+		c.text(e.Phone, cx, line, fit(e.Phone, w*0.9, small))
+	}
+
+	return nil
+}
+
+// fit returns the font size, at most size, at which s fits in width.
+func fit(s string, width, size float64) float64 {
+	if w := textWidth(winAnsi(s), size); w > width {
+		return size * width / w
+	}
+	return size
+}
@@ -0,0 +1,124 @@
+// Package probe answers the liveness and readiness probes of the
+// orchestrator. Liveness only tells that the process serves requests;
+// readiness runs the checks of the dependencies the instance cannot serve
+// without, each registered by whoever sets the dependency up.
+package probe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Check reports whether a dependency is usable. It must return once ctx is
+// done.
+type Check func(ctx context.Context) error
+
+const (
+	StatusOK     = "OK"
+	StatusFailed = "FAILED"
+)
+
+// Result is the outcome of one readiness check. Errors are logged, not
+// returned, as the probes are served without authentication.
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type Report struct {
+	Ready  bool      `json:"ready"`
+	Checks []*Result `json:"checks"`
+}
+
+type Probes struct {
+	timeout time.Duration
+	zlog    *zap.Logger
+
+	mu     sync.Mutex
+	names  []string
+	checks map[string]Check
+}
+
+// New returns Probes that give each readiness check at most timeout.
+func New(timeout time.Duration, zlog *zap.Logger) (*Probes, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Probes{
+		timeout: timeout,
+		zlog:    zlog,
+		checks:  make(map[string]Check),
+	}, nil
+}
+
+// Register adds a readiness check. Checks are reported in the order they
+// were registered.
+func (p *Probes) Register(name string, check Check) error {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	if check == nil {
+		return errors.New("check is nil")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.checks[name]; ok {
+		return errors.New("check " + name + " is already registered")
+	}
+	p.names = append(p.names, name)
+	p.checks[name] = check
+	return nil
+}
+
+// Ready runs every readiness check concurrently. The instance is ready
+// when all of them pass.
+func (p *Probes) Ready(ctx context.Context) *Report {
+	p.mu.Lock()
+	names := append([]string(nil), p.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = p.checks[name]
+	}
+	p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	results := make([]*Result, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results[i] = &Result{Name: names[i], Status: StatusOK}
+			if err := checks[i](ctx); err != nil {
+				results[i].Status = StatusFailed
+				p.zlog.Warn("readiness check failed", zap.String("check", names[i]), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{
+		Ready:  true,
+		Checks: results,
+	}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
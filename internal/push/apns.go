@@ -0,0 +1,179 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	apnsEndpoint        = "https://api.push.apple.com"
+	apnsSandboxEndpoint = "https://api.sandbox.push.apple.com"
+
+	// apnsTokenLifetime is how long a provider token is used. APNs rejects
+	// tokens older than an hour and ones renewed more often than every 20
+	// minutes.
+	apnsTokenLifetime = 40 * time.Minute
+)
+
+// APNsConfig is the token-based connection of the app to APNs.
+type APNsConfig struct {
+	// Key is the PEM encoded .p8 signing key, KeyID its ID and TeamID the
+	// ID of the team of the app.
+	Key    []byte
+	KeyID  string
+	TeamID string
+
+	// Topic is the bundle ID of the app.
+	Topic string
+
+	// Sandbox sends to the development environment, for builds of the
+	// app signed for development.
+	Sandbox bool
+}
+
+// APNsProvider sends notifications to iOS devices through the Apple Push
+// Notification service.
+type APNsProvider struct {
+	key    crypto.Signer
+	keyID  string
+	teamID string
+	topic  string
+
+	endpoint string
+	client   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	issued time.Time
+}
+
+func NewAPNsProvider(cfg APNsConfig) (*APNsProvider, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, errors.New("key ID, team ID and topic are required")
+	}
+
+	key, err := parsePrivateKey(cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := key.(*ecdsa.PrivateKey); !ok {
+		return nil, errors.New("key is not an ECDSA key")
+	}
+
+	endpoint := apnsEndpoint
+	if cfg.Sandbox {
+		endpoint = apnsSandboxEndpoint
+	}
+
+	// APNs speaks HTTP/2 only, which the default transport negotiates.
+	return &APNsProvider{
+		key:      key,
+		keyID:    cfg.KeyID,
+		teamID:   cfg.TeamID,
+		topic:    cfg.Topic,
+		endpoint: endpoint,
+		client: &http.Client{
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+func (p *APNsProvider) Send(ctx context.Context, _ Platform, token string, msg *Message) error {
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	auth, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+auth)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Apns-Topic", p.topic)
+	req.Header.Set("Apns-Push-Type", "alert")
+	req.Header.Set("Apns-Priority", "10")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send to APNs: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var out struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&out)
+
+	switch {
+	case res.StatusCode == http.StatusGone,
+		out.Reason == "BadDeviceToken",
+		out.Reason == "DeviceTokenNotForTopic",
+		out.Reason == "Unregistered":
+		return ErrUnregistered
+
+	case out.Reason == "ExpiredProviderToken" || out.Reason == "InvalidProviderToken":
+		p.mu.Lock()
+		p.token = ""
+		p.mu.Unlock()
+	}
+
+	return fmt.Errorf("APNs responded %s: %s", res.Status, out.Reason)
+}
+
+// providerToken returns the JWT APNs requests are authorized with,
+// renewed every apnsTokenLifetime.
+func (p *APNsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Sub(p.issued) < apnsTokenLifetime {
+		return p.token, nil
+	}
+
+	token, err := signJWT(p.key, p.keyID, map[string]any{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign provider token: %w", err)
+	}
+
+	p.token, p.issued = token, now
+	return token, nil
+}
@@ -0,0 +1,284 @@
+package push
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/notify"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// Service keeps the devices registered for push and notifies them of card
+// events. It is an event.Publisher, so it receives events from the outbox
+// like the message broker does.
+type Service struct {
+	db       *sql.DB
+	provider Provider
+	employee *employee.Service
+	zlog     *zap.Logger
+}
+
+func NewService(_ context.Context, db *sql.DB, provider Provider, employee *employee.Service, zlog *zap.Logger) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if provider == nil {
+		return nil, errors.New("provider is nil")
+	}
+	if employee == nil {
+		return nil, errors.New("employee is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Service{
+		db:       db,
+		provider: provider,
+		employee: employee,
+		zlog:     zlog,
+	}, nil
+}
+
+// Device is a phone registered to receive push notifications.
+type Device struct {
+	Token     string    `json:"token"`
+	Platform  Platform  `json:"platform"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	employeeID int64
+}
+
+type DeviceReq struct {
+	Token    string   `json:"token" query:"token"`
+	Platform Platform `json:"platform"`
+}
+
+func (r *DeviceReq) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	r.Platform = Platform(strings.ToLower(strings.TrimSpace(string(r.Platform))))
+
+	violations, err := validate.Violations(&contactqrPb.DeviceRequest{
+		Token:    r.Token,
+		Platform: string(r.Platform),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidDevice).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// RegisterDevice registers the caller's device for push. A token already
+// registered, e.g. by the previous user of a shared phone, moves to the
+// caller.
+func (s *Service) RegisterDevice(ctx context.Context, in *DeviceReq) (*Device, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "RegisterDevice"),
+		zap.String("platform", string(in.Platform)),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	d := &Device{
+		Token:      in.Token,
+		Platform:   in.Platform,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		employeeID: claims.ID,
+	}
+	if err := saveDevice(ctx, s.db, d); err != nil {
+		zlog.Error("failed to save device", zap.Error(err))
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnregisterDevice stops push to one of the caller's devices, e.g. on
+// logout.
+func (s *Service) UnregisterDevice(ctx context.Context, token string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "UnregisterDevice"),
+		zap.String("username", claims.Code),
+	)
+
+	if err := deleteDevice(ctx, s.db, token, claims.ID); err != nil {
+		zlog.Error("failed to delete device", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Publish notifies the people concerned by a card event: the approver of
+// a new card, the owner otherwise. Delivery failures are logged rather
+// than returned so they never hold up the outbox.
+func (s *Service) Publish(ctx context.Context, e *event.Event) error {
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "Publish"),
+		zap.String("event_id", e.ID),
+		zap.String("type", string(e.Type)),
+	)
+
+	tmpl, ok := templates[e.Type]
+	if !ok {
+		return nil
+	}
+	// Guest cards have no employee to notify.
+	if e.EmployeeID <= 0 {
+		return nil
+	}
+
+	to := e.EmployeeID
+	if e.Type == event.TypeCreated {
+		manager, err := s.employee.ManagerOf(ctx, e.EmployeeID)
+		if err != nil {
+			zlog.Warn("failed to get manager", zap.Error(err))
+			return nil
+		}
+		to = manager
+	}
+	if to <= 0 {
+		return nil
+	}
+
+	s.notify(ctx, zlog, to, tmpl, e)
+	return nil
+}
+
+func (s *Service) notify(ctx context.Context, zlog *zap.Logger, employeeID int64, tmpl *notify.Template, e *event.Event) {
+	devices, err := listDevices(ctx, s.db, employeeID)
+	if err != nil {
+		zlog.Error("failed to list devices", zap.Error(err))
+		return
+	}
+	if len(devices) == 0 {
+		return
+	}
+
+	lang, err := s.employee.NotificationLanguage(ctx, employeeID)
+	if err != nil {
+		zlog.Warn("failed to get notification language", zap.Error(err))
+		lang = i18n.English
+	}
+
+	rendered, err := tmpl.Render(lang, e)
+	if err != nil {
+		zlog.Error("failed to render push", zap.Error(err))
+		return
+	}
+	msg := &Message{
+		Title: rendered.Subject,
+		Body:  rendered.Body,
+		Data: map[string]string{
+			"type":   string(e.Type),
+			"cardId": e.CardID,
+		},
+	}
+
+	for _, d := range devices {
+		err := s.provider.Send(ctx, d.Platform, d.Token, msg)
+		if errors.Is(err, ErrUnregistered) {
+			zlog.Info("removing unregistered device", zap.Int64("employee_id", employeeID))
+			if err := deleteDevice(ctx, s.db, d.Token, employeeID); err != nil {
+				zlog.Error("failed to delete device", zap.Error(err))
+			}
+			continue
+		}
+		if err != nil {
+			zlog.Error("failed to send push", zap.Error(err))
+		}
+	}
+}
+
+// templates are the notifications sent for each event type. They render
+// the event.
+var templates = map[event.Type]*notify.Template{
+	event.TypeCreated: {
+		Subject: map[i18n.Lang]string{
+			i18n.English: "Business card awaiting your approval",
+			i18n.Lao:     "ນາມບັດລໍຖ້າການອະນຸມັດຈາກທ່ານ",
+			i18n.Thai:    "นามบัตรรอการอนุมัติจากคุณ",
+		},
+		Body: map[i18n.Lang]string{
+			i18n.English: "Card {{.CardID}} was submitted and needs your approval.",
+			i18n.Lao:     "ນາມບັດ {{.CardID}} ຖືກສົ່ງມາ ແລະ ຕ້ອງການການອະນຸມັດຈາກທ່ານ.",
+			i18n.Thai:    "นามบัตร {{.CardID}} ถูกส่งมาและต้องการการอนุมัติจากคุณ",
+		},
+	},
+	event.TypeApproved: {
+		Subject: map[i18n.Lang]string{
+			i18n.English: "Business card approved",
+			i18n.Lao:     "ນາມບັດໄດ້ຮັບການອະນຸມັດ",
+			i18n.Thai:    "นามบัตรได้รับการอนุมัติ",
+		},
+		Body: map[i18n.Lang]string{
+			i18n.English: "Your card {{.CardID}} was approved by {{.Actor}}.",
+			i18n.Lao:     "ນາມບັດ {{.CardID}} ຂອງທ່ານໄດ້ຮັບການອະນຸມັດໂດຍ {{.Actor}}.",
+			i18n.Thai:    "นามบัตร {{.CardID}} ของคุณได้รับการอนุมัติโดย {{.Actor}}",
+		},
+	},
+	event.TypeRejected: {
+		Subject: map[i18n.Lang]string{
+			i18n.English: "Business card rejected",
+			i18n.Lao:     "ນາມບັດຖືກປະຕິເສດ",
+			i18n.Thai:    "นามบัตรถูกปฏิเสธ",
+		},
+		Body: map[i18n.Lang]string{
+			i18n.English: "Your card {{.CardID}} was rejected by {{.Actor}}. Open the app to see why.",
+			i18n.Lao:     "ນາມບັດ {{.CardID}} ຂອງທ່ານຖືກປະຕິເສດໂດຍ {{.Actor}}. ເປີດແອັບເພື່ອເບິ່ງເຫດຜົນ.",
+			i18n.Thai:    "นามบัตร {{.CardID}} ของคุณถูกปฏิเสธโดย {{.Actor}} เปิดแอปเพื่อดูเหตุผล",
+		},
+	},
+	event.TypePublished: {
+		Subject: map[i18n.Lang]string{
+			i18n.English: "Business card published",
+			i18n.Lao:     "ນາມບັດຖືກເຜີຍແຜ່ແລ້ວ",
+			i18n.Thai:    "นามบัตรเผยแพร่แล้ว",
+		},
+		Body: map[i18n.Lang]string{
+			i18n.English: "Your card {{.CardID}} is live. Its QR code is ready to share.",
+			i18n.Lao:     "ນາມບັດ {{.CardID}} ຂອງທ່ານພ້ອມໃຊ້ແລ້ວ. QR code ພ້ອມແບ່ງປັນ.",
+			i18n.Thai:    "นามบัตร {{.CardID}} ของคุณพร้อมใช้งานแล้ว QR code พร้อมแชร์",
+		},
+	},
+	event.TypeRevoked: {
+		Subject: map[i18n.Lang]string{
+			i18n.English: "Business card no longer public",
+			i18n.Lao:     "ນາມບັດບໍ່ເປັນສາທາລະນະອີກຕໍ່ໄປ",
+			i18n.Thai:    "นามบัตรไม่เป็นสาธารณะอีกต่อไป",
+		},
+		Body: map[i18n.Lang]string{
+			i18n.English: "Your card {{.CardID}} was changed to {{.Status}} and its QR code no longer works.",
+			i18n.Lao:     "ນາມບັດ {{.CardID}} ຂອງທ່ານຖືກປ່ຽນເປັນ {{.Status}} ແລະ QR code ໃຊ້ບໍ່ໄດ້ອີກ.",
+			i18n.Thai:    "นามบัตร {{.CardID}} ของคุณถูกเปลี่ยนเป็น {{.Status}} และ QR code ใช้ไม่ได้อีก",
+		},
+	},
+}
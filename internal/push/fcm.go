@@ -0,0 +1,197 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmEndpoint = "https://fcm.googleapis.com"
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCMProvider sends notifications through Firebase Cloud Messaging with
+// the HTTP v1 API, authorized as a Google service account.
+type FCMProvider struct {
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	key         crypto.Signer
+
+	endpoint string
+	client   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewFCMProvider creates an FCMProvider from the JSON key of a service
+// account of the Firebase project, as downloaded from its console.
+func NewFCMProvider(credentials []byte) (*FCMProvider, error) {
+	var sa struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &sa); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+	if sa.ProjectID == "" || sa.ClientEmail == "" || sa.PrivateKey == "" || sa.TokenURI == "" {
+		return nil, errors.New("credentials lack project_id, client_email, private_key or token_uri")
+	}
+
+	key, err := parsePrivateKey([]byte(sa.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		return nil, errors.New("private_key is not an RSA key")
+	}
+
+	return &FCMProvider{
+		projectID:   sa.ProjectID,
+		clientEmail: sa.ClientEmail,
+		tokenURI:    sa.TokenURI,
+		key:         key,
+		endpoint:    fcmEndpoint,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *FCMProvider) Send(ctx context.Context, _ Platform, token string, msg *Message) error {
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token": token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	access, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/v1/projects/"+url.PathEscape(p.projectID)+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+access)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send to FCM: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var out struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&out)
+
+	if res.StatusCode == http.StatusUnauthorized {
+		p.mu.Lock()
+		p.token = ""
+		p.mu.Unlock()
+	}
+	if out.Error.Status == "NOT_FOUND" {
+		return ErrUnregistered
+	}
+	for _, d := range out.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" {
+			return ErrUnregistered
+		}
+	}
+
+	return fmt.Errorf("FCM responded %s: %s", res.Status, out.Error.Message)
+}
+
+// accessToken returns the OAuth 2 access token FCM is called with,
+// exchanging a JWT signed by the service account for a new one shortly
+// before the last expires.
+func (p *FCMProvider) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Add(time.Minute).Before(p.expires) {
+		return p.token, nil
+	}
+
+	assertion, err := signJWT(p.key, "", map[string]any{
+		"iss":   p.clientEmail,
+		"scope": fcmScope,
+		"aud":   p.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get FCM access token: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return "", fmt.Errorf("failed to get FCM access token: %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode FCM access token: %w", err)
+	}
+	if out.AccessToken == "" {
+		return "", errors.New("FCM access token is empty")
+	}
+
+	p.token = out.AccessToken
+	p.expires = now.Add(time.Duration(out.ExpiresIn) * time.Second)
+	return p.token, nil
+}
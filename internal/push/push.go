@@ -0,0 +1,75 @@
+// Package push sends mobile push notifications about business cards to
+// the devices staff registered in the app.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ErrUnregistered is returned by a Provider when the device token is no
+// longer valid, e.g. the app was uninstalled. The token is then removed.
+var ErrUnregistered = errors.New("device token is unregistered")
+
+// Platform is the operating system of a device.
+type Platform string
+
+const (
+	Android Platform = "android"
+	IOS     Platform = "ios"
+)
+
+// Message is a push notification. Data is handed to the app, e.g. the ID
+// of the card to open.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Provider delivers push notifications, e.g. through FCM or APNs.
+// Implementations must be safe for concurrent use.
+type Provider interface {
+	Send(ctx context.Context, platform Platform, token string, msg *Message) error
+}
+
+// Providers sends each notification through the provider of the device's
+// platform, e.g. FCM for Android and APNs for iOS.
+type Providers map[Platform]Provider
+
+func (p Providers) Send(ctx context.Context, platform Platform, token string, msg *Message) error {
+	provider, ok := p[platform]
+	if !ok {
+		return fmt.Errorf("no push provider for platform %q", platform)
+	}
+
+	return provider.Send(ctx, platform, token, msg)
+}
+
+// LogProvider writes notifications to the log instead of sending them, for
+// development.
+type LogProvider struct {
+	zlog *zap.Logger
+}
+
+func NewLogProvider(zlog *zap.Logger) (*LogProvider, error) {
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &LogProvider{zlog: zlog}, nil
+}
+
+func (p *LogProvider) Send(_ context.Context, platform Platform, token string, msg *Message) error {
+	p.zlog.Info("push",
+		zap.String("platform", string(platform)),
+		zap.String("token", token),
+		zap.String("title", msg.Title),
+		zap.String("body", msg.Body),
+		zap.Any("data", msg.Data),
+	)
+	return nil
+}
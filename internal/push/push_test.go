@@ -0,0 +1,176 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func pemKey(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestFCMProvider(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	var exchanges int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			exchanges++
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.FormValue("assertion") == "" {
+				http.Error(w, "bad grant", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "expires_in": 3600})
+
+		case "/v1/projects/contactqr/messages:send":
+			if r.Header.Get("Authorization") != "Bearer at" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var body struct {
+				Message struct {
+					Token string `json:"token"`
+				} `json:"message"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Message.Token == "gone" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			w.Write([]byte(`{"name":"projects/contactqr/messages/1"}`))
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	creds, _ := json.Marshal(map[string]string{
+		"project_id":   "contactqr",
+		"client_email": "push@contactqr.iam.gserviceaccount.com",
+		"private_key":  pemKey(t, key),
+		"token_uri":    srv.URL + "/token",
+	})
+	p, err := NewFCMProvider(creds)
+	if err != nil {
+		t.Fatalf("NewFCMProvider: %v", err)
+	}
+	p.endpoint = srv.URL
+
+	msg := &Message{Title: "Business card approved", Data: map[string]string{"cardId": "C1"}}
+	if err := p.Send(context.Background(), Android, "device", msg); err != nil {
+		t.Fatalf("Send = %v, want nil", err)
+	}
+	if err := p.Send(context.Background(), Android, "gone", msg); !errors.Is(err, ErrUnregistered) {
+		t.Fatalf("Send to an unregistered token = %v, want ErrUnregistered", err)
+	}
+	if exchanges != 1 {
+		t.Fatalf("access token exchanged %d times, want it cached", exchanges)
+	}
+}
+
+func TestAPNsProvider(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verifyES256(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), &key.PublicKey) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason":"InvalidProviderToken"}`))
+			return
+		}
+		if r.Header.Get("Apns-Topic") != "com.example.contactqr" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason":"BadTopic"}`))
+			return
+		}
+		if r.URL.Path == "/3/device/gone" {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered"}`))
+			return
+		}
+	}))
+	defer srv.Close()
+
+	p, err := NewAPNsProvider(APNsConfig{
+		Key:    []byte(pemKey(t, key)),
+		KeyID:  "ABC123DEFG",
+		TeamID: "DEF123GHIJ",
+		Topic:  "com.example.contactqr",
+	})
+	if err != nil {
+		t.Fatalf("NewAPNsProvider: %v", err)
+	}
+	p.endpoint = srv.URL
+
+	msg := &Message{Title: "Business card approved", Data: map[string]string{"cardId": "C1"}}
+	if err := p.Send(context.Background(), IOS, "device", msg); err != nil {
+		t.Fatalf("Send = %v, want nil", err)
+	}
+	if err := p.Send(context.Background(), IOS, "gone", msg); !errors.Is(err, ErrUnregistered) {
+		t.Fatalf("Send to an unregistered token = %v, want ErrUnregistered", err)
+	}
+}
+
+func TestProvidersRoutesByPlatform(t *testing.T) {
+	var got Platform
+	p := Providers{
+		Android: providerFunc(func(platform Platform) error { got = platform; return nil }),
+	}
+
+	if err := p.Send(context.Background(), Android, "device", &Message{}); err != nil || got != Android {
+		t.Fatalf("Send to android = %v, routed to %q", err, got)
+	}
+	if err := p.Send(context.Background(), IOS, "device", &Message{}); err == nil {
+		t.Fatal("Send to a platform without provider succeeded, want an error")
+	}
+}
+
+type providerFunc func(Platform) error
+
+func (f providerFunc) Send(_ context.Context, platform Platform, _ string, _ *Message) error {
+	return f(platform)
+}
+
+// verifyES256 reports whether token is a JWT signed by pub with ES256.
+func verifyES256(token string, pub *ecdsa.PublicKey) bool {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || len(sig) != 64 {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(token[:i]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	return ecdsa.Verify(pub, digest[:], r, s)
+}
@@ -0,0 +1,82 @@
+package push
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func saveDevice(ctx context.Context, db *sql.DB, in *Device) error {
+	q := `
+MERGE dbo.push_device AS t
+USING (SELECT @p1 AS token) AS s ON t.token = s.token
+WHEN MATCHED THEN
+  UPDATE SET employee_id = @p2, platform = @p3, updated_at = @p5
+WHEN NOT MATCHED THEN
+  INSERT (token, employee_id, platform, created_at, updated_at) VALUES (@p1, @p2, @p3, @p4, @p5);`
+
+	if _, err := db.ExecContext(ctx, q, in.Token, in.employeeID, string(in.Platform), in.CreatedAt, in.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to execute save device: %w", err)
+	}
+
+	return nil
+}
+
+func deleteDevice(ctx context.Context, db *sql.DB, token string, employeeID int64) error {
+	q, args := sq.
+		Delete("dbo.push_device").
+		Where(
+			sq.Eq{
+				"token":       token,
+				"employee_id": employeeID,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func listDevices(ctx context.Context, db *sql.DB, employeeID int64) ([]*Device, error) {
+	q, args := sq.
+		Select(
+			"token",
+			"platform",
+			"created_at",
+			"updated_at",
+		).
+		From("dbo.push_device").
+		Where(
+			sq.Eq{
+				"employee_id": employeeID,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	devices := make([]*Device, 0)
+	for rows.Next() {
+		d := Device{employeeID: employeeID}
+		if err := rows.Scan(&d.Token, &d.Platform, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,83 @@
+// Package ratelimit counts the requests of each client against a rate, in
+// memory for a single replica or in Redis across all of them, so limits
+// hold however requests are balanced and survive restarts.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limit is the requests a second allowed and how many may come at once.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Per returns the limit of r requests a second, bursting to as many as
+// arrive in a second.
+func Per(r float64) Limit {
+	return Limit{Rate: r, Burst: max(1, int(math.Ceil(r)))}
+}
+
+// interval is the time one request takes up.
+func (l Limit) interval() time.Duration {
+	return time.Duration(float64(time.Second) / l.Rate)
+}
+
+// Store decides whether another request counted under key is within l.
+type Store interface {
+	Allow(ctx context.Context, key string, l Limit) (bool, error)
+}
+
+// MemoryStore counts requests in memory. Every replica counts its own and
+// starts over when restarted.
+type MemoryStore struct {
+	mu       sync.Mutex
+	visitors map[string]*visitor
+
+	// expiresIn is how long a key is kept after its last request.
+	expiresIn time.Duration
+	lastSweep time.Time
+}
+
+type visitor struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+func NewMemoryStore(expiresIn time.Duration) *MemoryStore {
+	return &MemoryStore{
+		visitors:  make(map[string]*visitor),
+		expiresIn: expiresIn,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, l Limit) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	v, ok := s.visitors[key]
+	if !ok {
+		v = &visitor{Limiter: rate.NewLimiter(rate.Limit(l.Rate), l.Burst)}
+		s.visitors[key] = v
+	}
+	v.lastSeen = now
+
+	if now.Sub(s.lastSweep) > s.expiresIn {
+		for k, v := range s.visitors {
+			if now.Sub(v.lastSeen) > s.expiresIn {
+				delete(s.visitors, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	return v.AllowN(now, 1), nil
+}
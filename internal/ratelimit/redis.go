@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcra admits a request under KEYS[1] by the generic cell rate algorithm:
+// the key holds the theoretical arrival time of the next request in
+// microseconds, ARGV[1] is the interval of one request and ARGV[2] how far
+// ahead of now it may run, the burst. It returns 1 when admitted.
+var gcra = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + interval
+if new_tat - now > tonumber(ARGV[2]) then
+  return 0
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return 1
+`)
+
+// RedisStore counts requests in Redis, shared by every replica. Keys expire
+// once their requests no longer count.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisStore returns a store keeping its counts under prefix, e.g.
+// "contactqr:ratelimit:".
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, l Limit) (bool, error) {
+	interval := l.interval().Microseconds()
+	n, err := gcra.Run(ctx, s.client, []string{s.prefix + key}, interval, interval*int64(l.Burst)).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+
+	return n == 1, nil
+}
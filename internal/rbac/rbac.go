@@ -0,0 +1,183 @@
+// Package rbac decides what a caller may do from their roles. Everyone
+// signed in is an EMPLOYEE; further roles are granted in the employee_role
+// table and HR is also implied by the HR flag of the login, which predates
+// roles. The roles of a request are resolved once by middleware and read
+// by the services through Can.
+package rbac
+
+import (
+	"context"
+	"slices"
+
+	"github.com/10664kls/contactqr/internal/auth"
+)
+
+type Role string
+
+const (
+	Employee Role = "EMPLOYEE"
+
+	// Manager approves the cards of their reports. Which cards those are
+	// is decided by the reporting line, not by a permission.
+	Manager Role = "MANAGER"
+
+	HR    Role = "HR"
+	Admin Role = "ADMIN"
+
+	// PrintCoordinator follows the print requests with the print vendor,
+	// sending it the cards to print.
+	PrintCoordinator Role = "PRINT_COORDINATOR"
+)
+
+// Permission is something only some roles may do.
+type Permission string
+
+const (
+	// ReadAllCards is seeing any card, not only one's own.
+	ReadAllCards Permission = "cards.read_all"
+
+	// ManageCards is acting on the cards of others: uploading their photo,
+	// issuing event cards and printing department posters.
+	ManageCards  Permission = "cards.manage"
+	PublishCards Permission = "cards.publish"
+	ArchiveCards Permission = "cards.archive"
+
+	ManageGuests      Permission = "guests.manage"
+	ReadAnalytics     Permission = "analytics.read"
+	ManageExperiments Permission = "experiments.manage"
+	ReadEmployees     Permission = "employees.read"
+
+	ReadAudit             Permission = "audit.read"
+	ManageJobs            Permission = "jobs.manage"
+	ReadDiagnostics       Permission = "diagnostics.read"
+	ReadExports           Permission = "exports.read"
+	ManageTransliteration Permission = "transliteration.manage"
+	ManageWebhooks        Permission = "webhooks.manage"
+
+	// ManageTemplates is designing the cards of each company.
+	ManageTemplates Permission = "templates.manage"
+
+	// ManageAddresses is keeping the office address of each company, which
+	// cards carry.
+	ManageAddresses Permission = "addresses.manage"
+
+	// ResetPasswords is sending employees a password reset link.
+	ResetPasswords Permission = "passwords.reset"
+
+	// ReadPrintRequests is listing the print requests of all cards, and
+	// ManagePrintRequests marking them ordered and delivered.
+	ReadPrintRequests   Permission = "print_requests.read"
+	ManagePrintRequests Permission = "print_requests.manage"
+)
+
+// grants are the permissions of each role. EMPLOYEE and MANAGER have none.
+var grants = map[Role][]Permission{
+	HR: {
+		ReadAllCards,
+		ManageCards,
+		PublishCards,
+		ArchiveCards,
+		ManageGuests,
+		ReadAnalytics,
+		ManageExperiments,
+		ReadEmployees,
+		ReadAudit,
+		ManageJobs,
+		ReadDiagnostics,
+		ReadExports,
+		ManageTransliteration,
+		ReadPrintRequests,
+		ManagePrintRequests,
+		ResetPasswords,
+		ManageTemplates,
+		ManageAddresses,
+	},
+	Admin: {
+		ReadAllCards,
+		ManageCards,
+		PublishCards,
+		ArchiveCards,
+		ManageGuests,
+		ReadAnalytics,
+		ManageExperiments,
+		ReadEmployees,
+		ReadAudit,
+		ManageJobs,
+		ReadDiagnostics,
+		ReadExports,
+		ManageTransliteration,
+		ManageWebhooks,
+		ReadPrintRequests,
+		ManagePrintRequests,
+		ResetPasswords,
+		ManageTemplates,
+		ManageAddresses,
+	},
+	PrintCoordinator: {
+		ReadAllCards,
+		ReadPrintRequests,
+	},
+}
+
+// Roles is the role set of a caller.
+type Roles []Role
+
+// Has reports whether the set includes role.
+func (r Roles) Has(role Role) bool {
+	return slices.Contains(r, role)
+}
+
+// Can reports whether any role of the set grants p.
+func (r Roles) Can(p Permission) bool {
+	for _, role := range r {
+		if slices.Contains(grants[role], p) {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey int
+
+const (
+	rolesKey ctxKey = iota
+)
+
+func ContextWithRoles(ctx context.Context, roles Roles) context.Context {
+	return context.WithValue(ctx, rolesKey, roles)
+}
+
+// RolesFromContext returns the roles resolved for the request. Without
+// them, e.g. in jobs and seeds, the roles implied by the claims are used.
+func RolesFromContext(ctx context.Context) Roles {
+	if roles, ok := ctx.Value(rolesKey).(Roles); ok {
+		return roles
+	}
+	return impliedRoles(auth.ClaimsFromContext(ctx))
+}
+
+// Can reports whether the caller of ctx is granted p.
+func Can(ctx context.Context, p Permission) bool {
+	return RolesFromContext(ctx).Can(p)
+}
+
+func CanPublishCard(ctx context.Context) bool {
+	return Can(ctx, PublishCards)
+}
+
+func CanArchiveCard(ctx context.Context) bool {
+	return Can(ctx, ArchiveCards)
+}
+
+// impliedRoles returns the roles claims carry without any grant.
+func impliedRoles(claims *auth.Claims) Roles {
+	if claims.ID <= 0 {
+		return nil
+	}
+
+	roles := Roles{Employee}
+	if claims.IsHR {
+		roles = append(roles, HR)
+	}
+	return roles
+}
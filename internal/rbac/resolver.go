@@ -0,0 +1,94 @@
+package rbac
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"go.uber.org/zap"
+)
+
+// grantTTL is how long the granted roles of an employee are cached, and so
+// how long a grant or revocation takes to apply.
+const grantTTL = time.Minute
+
+// Resolver resolves the roles of callers from their claims and the roles
+// granted to them.
+type Resolver struct {
+	db   *sql.DB
+	zlog *zap.Logger
+
+	mu    sync.Mutex
+	cache map[int64]*cachedGrants
+}
+
+type cachedGrants struct {
+	roles     []Role
+	expiresAt time.Time
+}
+
+func NewResolver(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Resolver, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Resolver{
+		db:    db,
+		zlog:  zlog,
+		cache: make(map[int64]*cachedGrants),
+	}, nil
+}
+
+// Resolve returns the roles of the caller with claims. When the grants
+// cannot be read the last ones read are used, or none, so a database
+// outage never grants more than the claims imply.
+func (r *Resolver) Resolve(ctx context.Context, claims *auth.Claims) Roles {
+	roles := impliedRoles(claims)
+	if len(roles) == 0 {
+		return roles
+	}
+
+	for _, role := range r.granted(ctx, claims.ID) {
+		if !roles.Has(role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func (r *Resolver) granted(ctx context.Context, employeeID int64) []Role {
+	r.mu.Lock()
+	cached, ok := r.cache[employeeID]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.roles
+	}
+
+	roles, err := listGrants(ctx, r.db, employeeID)
+	if err != nil {
+		r.zlog.Warn("failed to list granted roles",
+			zap.String("method", "Resolve"),
+			zap.Int64("employee_id", employeeID),
+			zap.Error(err),
+		)
+		if ok {
+			return cached.roles
+		}
+		return nil
+	}
+
+	r.mu.Lock()
+	r.cache[employeeID] = &cachedGrants{
+		roles:     roles,
+		expiresAt: time.Now().Add(grantTTL),
+	}
+	r.mu.Unlock()
+
+	return roles
+}
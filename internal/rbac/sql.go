@@ -0,0 +1,38 @@
+package rbac
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func listGrants(ctx context.Context, db *sql.DB, employeeID int64) ([]Role, error) {
+	q, args := sq.
+		Select("role").
+		From("dbo.employee_role").
+		Where(sq.Eq{"employee_id": employeeID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]Role, 0)
+	for rows.Next() {
+		var role Role
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,87 @@
+// Package reqid carries the ID of the request being served, so the log
+// lines, errors and events of one request can be correlated across the card
+// workflow. Clients may send their own ID to trace a call end to end;
+// without one the server generates it.
+package reqid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	rpcStatus "google.golang.org/grpc/status"
+)
+
+// Header carries the request ID in both directions, e.g.
+// "X-Request-ID: 5f0c...".
+const Header = "X-Request-ID"
+
+// maxLen is the longest ID accepted from a client.
+const maxLen = 128
+
+type ctxKey int
+
+const (
+	idKey ctxKey = iota
+)
+
+// New returns a new request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether id, sent by a client, is safe to log and echo: at
+// most 128 printable ASCII characters without spaces.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// ContextWithID returns a copy of ctx serving the request id.
+func ContextWithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey, id)
+}
+
+// FromContext returns the ID of the request ctx serves, "" outside of one.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey).(string)
+	return id
+}
+
+// Logger returns zlog logging the ID of the request ctx serves with every
+// line.
+func Logger(ctx context.Context, zlog *zap.Logger) *zap.Logger {
+	id := FromContext(ctx)
+	if id == "" {
+		return zlog
+	}
+	return zlog.With(zap.String("request_id", id))
+}
+
+// Status returns s with a RequestInfo detail naming the request ctx serves,
+// so clients can quote the ID when reporting the error.
+func Status(ctx context.Context, s *rpcStatus.Status) *rpcStatus.Status {
+	id := FromContext(ctx)
+	if id == "" {
+		return s
+	}
+	for _, d := range s.Details() {
+		if _, ok := d.(*edPb.RequestInfo); ok {
+			return s
+		}
+	}
+
+	withID, err := s.WithDetails(&edPb.RequestInfo{RequestId: id})
+	if err != nil {
+		return s
+	}
+	return withID
+}
@@ -0,0 +1,381 @@
+// Package scheduler runs periodic background jobs. Every replica schedules
+// every job, but a run only starts on the replica that wins the job's lease
+// in the database, so a job runs once per tick however many replicas serve
+// traffic.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/health"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+var errJobRunning = errors.New("job is already running")
+
+type Job struct {
+	Name string
+
+	// Spec is a standard five field cron expression or a descriptor such as
+	// "@hourly" or "@every 15m", evaluated in the server's local time.
+	Spec string
+
+	// Timeout bounds a single run and how long its lease is held.
+	// Default: 1 hour.
+	Timeout time.Duration
+
+	Run func(ctx context.Context) error
+}
+
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Spec           string     `json:"spec"`
+	NextRunAt      time.Time  `json:"nextRunAt"`
+	Running        bool       `json:"running"`
+	LastRunBy      string     `json:"lastRunBy"`
+	LastStartedAt  *time.Time `json:"lastStartedAt"`
+	LastFinishedAt *time.Time `json:"lastFinishedAt"`
+	LastDuration   string     `json:"lastDuration"`
+	LastError      string     `json:"lastError"`
+}
+
+type entry struct {
+	job      *Job
+	schedule cron.Schedule
+	next     time.Time
+}
+
+type Scheduler struct {
+	db     *sql.DB
+	health *health.State
+	zlog   *zap.Logger
+
+	// holder identifies this replica in job leases.
+	holder string
+
+	mu    sync.Mutex
+	jobs  map[string]*entry
+	order []string
+
+	// ctx is the context passed to Run. Triggered runs use it so they
+	// outlive the request that started them.
+	ctx context.Context
+
+	// running tracks runs on this replica; draining stops new ones.
+	running  sync.WaitGroup
+	draining bool
+}
+
+func NewScheduler(_ context.Context, db *sql.DB, health *health.State, zlog *zap.Logger) (*Scheduler, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if health == nil {
+		return nil, errors.New("health is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+
+	return &Scheduler{
+		db:     db,
+		health: health,
+		zlog:   zlog,
+		holder: fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b)),
+		jobs:   make(map[string]*entry),
+		ctx:    context.Background(),
+	}, nil
+}
+
+// Register adds job to the scheduler. It must be called before Run.
+func (s *Scheduler) Register(job *Job) error {
+	if job.Name == "" {
+		return errors.New("job name is empty")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s has no run func", job.Name)
+	}
+
+	schedule, err := cron.ParseStandard(job.Spec)
+	if err != nil {
+		return fmt.Errorf("invalid spec for job %s: %w", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = time.Hour
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.jobs[job.Name] = &entry{job: job, schedule: schedule}
+	s.order = append(s.order, job.Name)
+
+	return nil
+}
+
+// Run runs the registered jobs on their schedules until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	entries := make([]*entry, 0, len(s.order))
+	for _, name := range s.order {
+		entries = append(entries, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		if err := createJob(ctx, s.db, e.job.Name); err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		next := e.schedule.Next(time.Now())
+		s.mu.Lock()
+		e.next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Runs need the database for their lease; while it is down they
+		// are skipped rather than logged as failures every tick.
+		if s.health.ReadOnly() {
+			s.zlog.Warn("scheduled job skipped, database unavailable", zap.String("job", e.job.Name))
+			continue
+		}
+
+		err := s.run(ctx, e.job)
+		if errors.Is(err, errJobRunning) {
+			continue
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.zlog.Error("scheduled job failed", zap.String("job", e.job.Name), zap.Error(err))
+		}
+	}
+}
+
+// run runs job if no replica holds its lease, and records the result.
+func (s *Scheduler) run(ctx context.Context, job *Job) error {
+	if !s.begin() {
+		return nil
+	}
+	defer s.running.Done()
+
+	ok, err := acquireLease(ctx, s.db, job.Name, s.holder, job.Timeout)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errJobRunning
+	}
+
+	return s.exec(ctx, job)
+}
+
+// begin registers a run unless the scheduler is draining.
+func (s *Scheduler) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return false
+	}
+	s.running.Add(1)
+	return true
+}
+
+// Drain stops starting runs and waits for the ones in progress on this
+// replica to finish or for ctx to be done.
+func (s *Scheduler) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) exec(ctx context.Context, job *Job) (err error) {
+	started := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+
+		// Record the result even when ctx was cancelled mid-run.
+		rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if rerr := releaseLease(rctx, s.db, job.Name, s.holder, time.Since(started), err); rerr != nil {
+			s.zlog.Error("failed to record job result", zap.String("job", job.Name), zap.Error(rerr))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	s.zlog.Info("running job", zap.String("job", job.Name))
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) ListJobs(ctx context.Context) ([]*JobStatus, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListJobs"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageJobs) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.JobsForbidden)
+	}
+
+	rows, err := listJobs(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list jobs", zap.Error(err))
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]*JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.status(name, rows[name]))
+	}
+
+	return jobs, nil
+}
+
+type TriggerJobReq struct {
+	Name string `json:"name" param:"name"`
+}
+
+// TriggerJob starts a run of a job now, outside its schedule. The run
+// continues after the request returns; its result shows up in ListJobs.
+func (s *Scheduler) TriggerJob(ctx context.Context, in *TriggerJobReq) (*JobStatus, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "TriggerJob"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageJobs) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.JobRunForbidden)
+	}
+
+	s.mu.Lock()
+	e, ok := s.jobs[in.Name]
+	runCtx := s.ctx
+	s.mu.Unlock()
+	if !ok {
+		return nil, i18n.Error(codes.NotFound, i18n.JobNotFound)
+	}
+
+	if !s.begin() {
+		return nil, i18n.Error(codes.Unavailable, i18n.Draining)
+	}
+
+	ok, err := acquireLease(ctx, s.db, e.job.Name, s.holder, e.job.Timeout)
+	if err != nil {
+		s.running.Done()
+		zlog.Error("failed to acquire job lease", zap.Error(err))
+		return nil, err
+	}
+	if !ok {
+		s.running.Done()
+		return nil, i18n.Error(codes.FailedPrecondition, i18n.JobRunning)
+	}
+
+	zlog.Info("job triggered")
+	go func() {
+		defer s.running.Done()
+		if err := s.exec(runCtx, e.job); err != nil {
+			s.zlog.Error("triggered job failed", zap.String("job", e.job.Name), zap.Error(err))
+		}
+	}()
+
+	rows, err := listJobs(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list jobs", zap.Error(err))
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status(e.job.Name, rows[e.job.Name]), nil
+}
+
+// status merges a job's schedule with its last result. s.mu must be held.
+func (s *Scheduler) status(name string, r *jobRow) *JobStatus {
+	e := s.jobs[name]
+	st := &JobStatus{
+		Name:      name,
+		Spec:      e.job.Spec,
+		NextRunAt: e.next,
+	}
+	if r == nil {
+		return st
+	}
+
+	st.Running = r.running
+	st.LastRunBy = r.lastRunBy
+	st.LastStartedAt = r.lastStartedAt
+	st.LastFinishedAt = r.lastFinishedAt
+	st.LastDuration = r.lastDuration.String()
+	st.LastError = r.lastError
+
+	return st
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type jobRow struct {
+	running        bool
+	lastRunBy      string
+	lastStartedAt  *time.Time
+	lastFinishedAt *time.Time
+	lastDuration   time.Duration
+	lastError      string
+}
+
+func createJob(ctx context.Context, db *sql.DB, name string) error {
+	q := `
+IF NOT EXISTS (SELECT 1 FROM dbo.scheduled_job WHERE name = @p1)
+  INSERT INTO dbo.scheduled_job (name) VALUES (@p1)`
+
+	if _, err := db.ExecContext(ctx, q, name); err != nil {
+		return fmt.Errorf("failed to execute create job: %w", err)
+	}
+
+	return nil
+}
+
+// acquireLease takes the job's lease for holder unless another holder has
+// an unexpired one. Lease times use the database clock so replicas with
+// skewed clocks agree on expiry.
+func acquireLease(ctx context.Context, db *sql.DB, name, holder string, ttl time.Duration) (bool, error) {
+	q, args := sq.
+		Update("dbo.scheduled_job").
+		Set("holder", holder).
+		Set("lease_until", sq.Expr("DATEADD(second, ?, GETDATE())", int64(ttl/time.Second))).
+		Set("last_run_by", holder).
+		Set("last_started_at", sq.Expr("GETDATE()")).
+		Where(sq.Eq{"name": name}).
+		Where(sq.Or{
+			sq.Eq{"lease_until": nil},
+			sq.Expr("lease_until < GETDATE()"),
+		}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+func releaseLease(ctx context.Context, db *sql.DB, name, holder string, took time.Duration, cause error) error {
+	var msg string
+	if cause != nil {
+		r := []rune(cause.Error())
+		if len(r) > 1024 {
+			r = r[:1024]
+		}
+		msg = string(r)
+	}
+
+	q, args := sq.
+		Update("dbo.scheduled_job").
+		Set("holder", nil).
+		Set("lease_until", nil).
+		Set("last_finished_at", sq.Expr("GETDATE()")).
+		Set("last_duration_ms", took.Milliseconds()).
+		Set("last_error", msg).
+		Where(sq.Eq{
+			"name":   name,
+			"holder": holder,
+		}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+func listJobs(ctx context.Context, db *sql.DB) (map[string]*jobRow, error) {
+	q, args := sq.
+		Select(
+			"name",
+			"CAST(CASE WHEN lease_until >= GETDATE() THEN 1 ELSE 0 END AS BIT)",
+			"last_run_by",
+			"last_started_at",
+			"last_finished_at",
+			"last_duration_ms",
+			"last_error",
+		).
+		From("dbo.scheduled_job").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make(map[string]*jobRow)
+	for rows.Next() {
+		var name string
+		var r jobRow
+		var started, finished sql.NullTime
+		var ms int64
+		if err := rows.Scan(
+			&name,
+			&r.running,
+			&r.lastRunBy,
+			&started,
+			&finished,
+			&ms,
+			&r.lastError,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if started.Valid {
+			r.lastStartedAt = &started.Time
+		}
+		if finished.Valid {
+			r.lastFinishedAt = &finished.Time
+		}
+		r.lastDuration = time.Duration(ms) * time.Millisecond
+		jobs[name] = &r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return jobs, nil
+}
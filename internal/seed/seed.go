@@ -0,0 +1,278 @@
+// Package seed fills a development database with fake employees, logins and
+// business cards so the frontend has meaningful data to work against.
+//
+// Organisation and employee rows are written straight into the HR tables the
+// service reads from; cards are created and moved through their statuses by
+// the card service itself, so audit entries, events and published vCards
+// exist exactly as they would in production. Never run it against a
+// production database.
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/card"
+	"go.uber.org/zap"
+)
+
+// codePrefix marks seeded employees so a second run can detect them.
+const codePrefix = "SEED"
+
+type Config struct {
+	// Employees is the number of staff to create, excluding managers and HR.
+	Employees int
+
+	// Password is set on every seeded login.
+	Password string
+
+	// Rand seeds the fake data generator so runs are reproducible.
+	Rand uint64
+}
+
+type Seeder struct {
+	db   *sql.DB
+	card *card.Service
+	zlog *zap.Logger
+}
+
+func NewSeeder(_ context.Context, db *sql.DB, card *card.Service, zlog *zap.Logger) (*Seeder, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if card == nil {
+		return nil, errors.New("card is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Seeder{
+		db:   db,
+		card: card,
+		zlog: zlog,
+	}, nil
+}
+
+// Seed creates a company with departments, a manager per department, one HR
+// officer and cfg.Employees staff, each with a login and a card. Staff cards
+// are spread evenly over PENDING, APPROVED, REJECTED and PUBLISHED.
+func (s *Seeder) Seed(ctx context.Context, cfg Config) error {
+	if cfg.Employees <= 0 {
+		return errors.New("employees must be positive")
+	}
+	if cfg.Password == "" {
+		return errors.New("password is empty")
+	}
+
+	n, err := countSeededEmployees(ctx, s.db)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("database already has %d seeded employees", n)
+	}
+
+	r := rand.New(rand.NewPCG(cfg.Rand, cfg.Rand))
+	g := &generator{r: r}
+
+	var hr *person
+	var staff []*person
+	err = withTx(ctx, s.db, func(tx *sql.Tx) error {
+		companyID, err := createCompany(ctx, tx, "Krungsri Laos (Demo)")
+		if err != nil {
+			return err
+		}
+
+		managerPos, err := createPosition(ctx, tx, "Manager")
+		if err != nil {
+			return err
+		}
+		officerPos, err := createPosition(ctx, tx, "Officer")
+		if err != nil {
+			return err
+		}
+
+		hrDep, err := createDepartment(ctx, tx, "Human Resources")
+		if err != nil {
+			return err
+		}
+		hr = g.person(companyID, hrDep, managerPos, 0)
+		hr.isHR = true
+		if err := createPerson(ctx, tx, hr, cfg.Password); err != nil {
+			return err
+		}
+
+		managers := make([]*person, 0, len(departments))
+		for _, name := range departments {
+			dep, err := createDepartment(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+			m := g.person(companyID, dep, managerPos, hr.id)
+			if err := createPerson(ctx, tx, m, cfg.Password); err != nil {
+				return err
+			}
+			managers = append(managers, m)
+		}
+
+		for i := range cfg.Employees {
+			m := managers[i%len(managers)]
+			p := g.person(companyID, m.departmentID, officerPos, m.id)
+			p.manager = m
+			if err := createPerson(ctx, tx, p, cfg.Password); err != nil {
+				return err
+			}
+			staff = append(staff, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, p := range staff {
+		if err := s.seedCard(ctx, g, p, hr, i%4); err != nil {
+			return fmt.Errorf("failed to seed card of %s: %w", p.code, err)
+		}
+	}
+
+	s.zlog.Info("seeded database",
+		zap.Int("employees", len(staff)),
+		zap.Int("managers", len(departments)),
+		zap.String("hr", hr.code),
+	)
+	return nil
+}
+
+// seedCard creates p's card and moves it to the status picked by step:
+// 0 pending, 1 approved, 2 rejected, 3 published.
+func (s *Seeder) seedCard(ctx context.Context, g *generator, p, hr *person, step int) error {
+	c, err := s.card.CreateBusinessCard(as(ctx, p), &card.CardReq{
+		Phone:  card.PhoneNumber{Country: "LA", Number: g.phone()},
+		Mobile: card.PhoneNumber{Country: "LA", Number: g.mobile()},
+	})
+	if err != nil {
+		return err
+	}
+
+	switch step {
+	case 1, 3:
+		_, err = s.card.ApproveBusinessCard(as(ctx, p.manager), &card.ApproveBusinessCardReq{ID: c.ID})
+		if err == nil && step == 3 {
+			_, err = s.card.PublishBusinessCard(as(ctx, hr), &card.PublishBusinessCardReq{ID: c.ID})
+		}
+
+	case 2:
+		_, err = s.card.RejectBusinessCard(as(ctx, p.manager), &card.RejectBusinessCardReq{
+			ID:     c.ID,
+			Remark: "Please use your work mobile number.",
+		})
+	}
+
+	return err
+}
+
+// as returns ctx signed in as p.
+func as(ctx context.Context, p *person) context.Context {
+	return auth.ContextWithClaims(ctx, &auth.Claims{
+		ID:           p.id,
+		ManagerID:    p.managerID,
+		PositionID:   p.positionID,
+		DepartmentID: p.departmentID,
+		CompanyID:    p.companyID,
+		Code:         p.code,
+		DisplayName:  p.firstName + " " + p.lastName,
+		Email:        p.email,
+		IsHR:         p.isHR,
+	})
+}
+
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+var departments = []string{
+	"Retail Banking",
+	"Corporate Banking",
+	"Information Technology",
+	"Finance",
+	"Operations",
+}
+
+var (
+	firstNames = []string{
+		"Somchai", "Souksavanh", "Phonesavanh", "Vilayphone", "Khamla",
+		"Bounmy", "Chanthavong", "Malaythong", "Noy", "Sengphet",
+		"Thongdam", "Viengkham", "Anousone", "Dalavanh", "Keo",
+	}
+	lastNames = []string{
+		"Phommachanh", "Sisouphanh", "Vongsa", "Keomany", "Inthavong",
+		"Sayavong", "Douangchanh", "Phetsavong", "Luangrath", "Souvannavong",
+	}
+)
+
+type person struct {
+	id           int64
+	code         string
+	firstName    string
+	lastName     string
+	email        string
+	companyID    int64
+	departmentID int64
+	positionID   int64
+	managerID    int64
+	isHR         bool
+	createdAt    time.Time
+
+	manager *person
+}
+
+type generator struct {
+	r   *rand.Rand
+	seq int
+}
+
+func (g *generator) person(companyID, departmentID, positionID, managerID int64) *person {
+	g.seq++
+	first := firstNames[g.r.IntN(len(firstNames))]
+	last := lastNames[g.r.IntN(len(lastNames))]
+
+	return &person{
+		code:         fmt.Sprintf("%s%04d", codePrefix, g.seq),
+		firstName:    first,
+		lastName:     last,
+		email:        fmt.Sprintf("%s.%s%d@example.com", first, last[:1], g.seq),
+		companyID:    companyID,
+		departmentID: departmentID,
+		positionID:   positionID,
+		managerID:    managerID,
+		createdAt:    time.Now().AddDate(0, 0, -g.r.IntN(365)),
+	}
+}
+
+// phone returns a Vientiane landline number.
+func (g *generator) phone() string {
+	return fmt.Sprintf("021 %06d", 200000+g.r.IntN(800000))
+}
+
+// mobile returns a Lao mobile number.
+func (g *generator) mobile() string {
+	return fmt.Sprintf("020 %d%07d", 2+g.r.IntN(8), g.r.IntN(10000000))
+}
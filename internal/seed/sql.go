@@ -0,0 +1,117 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func countSeededEmployees(ctx context.Context, db *sql.DB) (int64, error) {
+	q, args := sq.
+		Select("COUNT(*)").
+		From("dbo.tb_employee").
+		Where(sq.Like{"EMPNO": codePrefix + "%"}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var n int64
+	if err := db.QueryRowContext(ctx, q, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return n, nil
+}
+
+// insertID runs an insert into an identity keyed table and returns the new
+// key.
+func insertID(ctx context.Context, tx *sql.Tx, b sq.InsertBuilder) (int64, error) {
+	q, args := b.
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return id, nil
+}
+
+func createCompany(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	return insertID(ctx, tx, sq.Insert("dbo.tb_Branch").Columns("BranchName").Values(name))
+}
+
+func createDepartment(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	return insertID(ctx, tx, sq.Insert("dbo.tb_department").Columns("Departname").Values(name))
+}
+
+func createPosition(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	return insertID(ctx, tx, sq.Insert("dbo.tb_position").Columns("Positionname").Values(name))
+}
+
+func createPerson(ctx context.Context, tx *sql.Tx, p *person, password string) error {
+	b := sq.
+		Insert("dbo.tb_employee").
+		Columns(
+			"EMPNO",
+			"nameeng",
+			"surnameeng",
+			"Emails",
+			"bid",
+			"depid",
+			"poid",
+			"approveby",
+			"mgrid",
+			"createdate",
+		).
+		Values(
+			p.code,
+			p.firstName,
+			p.lastName,
+			p.email,
+			p.companyID,
+			p.departmentID,
+			p.positionID,
+			sql.NullInt64{Int64: p.managerID, Valid: p.managerID > 0},
+			p.managerID,
+			p.createdAt,
+		)
+
+	id, err := insertID(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+	p.id = id
+
+	// hrkey 0 and 1 are HR roles, see auth.getUserByUsername.
+	hrKey := 2
+	if p.isHR {
+		hrKey = 1
+	}
+
+	q, args := sq.
+		Insert("dbo.tb_userlogin").
+		Columns(
+			"eid",
+			"username",
+			"tokenkey",
+			"hrkey",
+		).
+		Values(
+			p.id,
+			p.code,
+			password,
+			hrKey,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create login: %w", err)
+	}
+
+	return nil
+}
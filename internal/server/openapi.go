@@ -0,0 +1,199 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	httpPb "github.com/10664kls/contactqr/genproto/go/http/v1"
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/audit"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/card"
+	"github.com/10664kls/contactqr/internal/diag"
+	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/export"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/openapi"
+	"github.com/10664kls/contactqr/internal/password"
+	"github.com/10664kls/contactqr/internal/push"
+	"github.com/10664kls/contactqr/internal/scheduler"
+	"github.com/10664kls/contactqr/internal/template"
+	"github.com/10664kls/contactqr/internal/translit"
+	"github.com/10664kls/contactqr/internal/webhook"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// routes describes every /v1 route for the OpenAPI document. Install fails
+// when a route is missing, so a new route must be described here too.
+var routes = []*openapi.Route{
+	{Method: http.MethodPost, Path: "/v1/auth/login", OperationID: "login", Summary: "Log in with a username and password", Public: true, Body: new(auth.LoginReq), Response: new(auth.Token)},
+	{Method: http.MethodPost, Path: "/v1/auth/token", OperationID: "refreshToken", Summary: "Exchange a refresh token for a new token pair", Public: true, Body: new(auth.NewTokenReq), Response: new(auth.Token)},
+	{Method: http.MethodPost, Path: "/v1/auth/logout", OperationID: "logout", Summary: "Revoke the caller's session", Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/auth/profile", OperationID: "authProfile", Summary: "Get the caller's profile", Response: new(auth.User)},
+	{Method: http.MethodPost, Path: "/v1/auth/sessions/report", OperationID: "reportSession", Summary: "Report a login the user does not recognize", Public: true, Body: new(auth.ReportSessionReq), Response: new(emptypb.Empty)},
+	{Method: http.MethodPost, Path: `/v1/auth/password\:change`, OperationID: "changePassword", Summary: "Change the caller's password", Body: new(password.ChangeReq), Response: new(emptypb.Empty)},
+	{Method: http.MethodPost, Path: `/v1/auth/password\:reset`, OperationID: "resetPassword", Summary: "Set a new password with a reset token", Public: true, Body: new(password.ResetReq), Response: new(emptypb.Empty)},
+	{Method: http.MethodPost, Path: `/v1/users/:id/password\:reset`, OperationID: "issuePasswordReset", Summary: "Email an employee a password reset link", Response: new(emptypb.Empty)},
+
+	{Method: http.MethodGet, Path: "/v1/errors", OperationID: "listErrors", Summary: "List the error reasons and their messages", Public: true, Response: []*i18n.Entry{}},
+
+	{Method: http.MethodGet, Path: "/v1/employees", OperationID: "listEmployees", Summary: "List employees", Params: new(employee.EmployeeQuery), Response: new(employee.Employee), Page: true},
+	{Method: http.MethodGet, Path: "/v1/employees/:id", OperationID: "getEmployeeByID", Summary: "Get an employee", Response: new(employee.Employee)},
+	{Method: http.MethodGet, Path: "/v1/employees/me/profile", OperationID: "getMyEmployeeProfile", Summary: "Get the caller's employee profile", Response: new(employee.Employee)},
+	{Method: http.MethodGet, Path: "/v1/employees/me/preferences", OperationID: "getMyPreferences", Summary: "Get the caller's preferences", Response: new(employee.Preferences)},
+	{Method: http.MethodPut, Path: "/v1/employees/me/preferences", OperationID: "updateMyPreferences", Summary: "Update the caller's preferences", Body: new(employee.PreferencesReq), Response: new(employee.Preferences)},
+
+	{Method: http.MethodGet, Path: "/v1/guests", OperationID: "listGuests", Summary: "List guests", Params: new(card.GuestQuery), Response: new(card.Guest), Page: true},
+	{Method: http.MethodPost, Path: "/v1/guests", OperationID: "createGuest", Summary: "Create a guest", Body: new(card.GuestReq), Response: new(card.Guest)},
+	{Method: http.MethodGet, Path: "/v1/guests/:id", OperationID: "getGuestByID", Summary: "Get a guest", Response: new(card.Guest)},
+	{Method: http.MethodPost, Path: "/v1/guests/:id/business-cards", OperationID: "createGuestBusinessCard", Summary: "Create a business card for a guest", Body: new(card.CardReq), Response: new(contactqrPb.BusinessCard)},
+
+	{Method: http.MethodPost, Path: "/v1/business-cards", OperationID: "createBusinessCard", Summary: "Create a business card", Body: new(card.CardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: `/v1/business-cards\:preview`, OperationID: "previewBusinessCard", Summary: "Preview a business card and its vCard and QR code without creating it", Body: new(card.CardReq), Response: new(contactqrPb.BusinessCardPreview)},
+	{Method: http.MethodPut, Path: "/v1/business-cards/:id", OperationID: "updateBusinessCard", Summary: "Update a business card", Body: new(card.CardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me", OperationID: "listMyBusinessCards", Summary: "List the caller's business cards", Params: new(card.CardQuery), Response: new(contactqrPb.BusinessCard), Page: true},
+	{Method: http.MethodGet, Path: `/v1/business-cards/me\:sync`, OperationID: "syncMyBusinessCards", Summary: "Get the caller's cards changed since the last sync", Params: new(card.SyncReq), Response: new(card.SyncResult)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/vcf/:id", OperationID: "getMyVCFBusinessCardByID", Summary: "Get the vCard of one of the caller's cards", Params: new(card.VCFReq), Response: new(card.VCF)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/approval", OperationID: "listMyApprovalBusinessCards", Summary: "List the cards the caller approves", Params: new(card.CardQuery), Response: new(contactqrPb.BusinessCard), Page: true},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/approval/:id", OperationID: "getMyApprovalBusinessCardByID", Summary: "Get a card the caller approves", Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/approval/:id/diff", OperationID: "getMyApprovalBusinessCardDiff", Summary: "Compare a card the caller approves with its last published version", Response: new(card.CardDiff)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id", OperationID: "getMyBusinessCardByID", Summary: "Get one of the caller's cards", Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodDelete, Path: "/v1/business-cards/me/:id", OperationID: "deleteMyBusinessCard", Summary: "Delete one of the caller's cards", Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id/stats", OperationID: "getMyCardStats", Summary: "Get the views and downloads of one of the caller's cards", Params: new(card.CardStatsQuery), Response: new(card.CardStats)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id/leads", OperationID: "listMyLeads", Summary: "List the leads of one of the caller's cards", Params: new(card.LeadQuery), Response: new(card.Lead), Page: true},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id/leads/csv", OperationID: "exportMyLeads", Summary: "Export the leads of one of the caller's cards", Params: new(card.LeadQuery), Produces: "text/csv"},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id/events", OperationID: "listMyEventCards", Summary: "List the event cards of one of the caller's cards", Response: new(card.ListEventCardsResult)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/me/:id/events", OperationID: "createMyEventCard", Summary: "Create an event card", Body: new(card.EventCardReq), Response: new(card.EventCard)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/me/:id/share-links", OperationID: "listMyShareLinks", Summary: "List the share links of one of the caller's cards", Response: new(card.ListShareLinksResult)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/me/:id/share-links", OperationID: "createMyShareLink", Summary: "Create an expiring link to one of the caller's cards, published or not", Body: new(card.ShareLinkReq), Response: new(card.ShareLink)},
+	{Method: http.MethodDelete, Path: "/v1/business-cards/me/:id/share-links/:linkId", OperationID: "revokeMyShareLink", Summary: "Revoke a share link", Response: new(emptypb.Empty)},
+	{Method: http.MethodPost, Path: "/v1/event-cards/issue", OperationID: "issueEventCards", Summary: "Issue event cards to employees", Body: new(card.EventCardReq), Response: new(card.IssueEventCardsResult)},
+	{Method: http.MethodGet, Path: "/v1/business-cards", OperationID: "listBusinessCards", Summary: "List business cards", Params: new(card.CardQuery), Response: new(contactqrPb.BusinessCard), Page: true},
+	{Method: http.MethodGet, Path: "/v1/business-cards/stream", OperationID: "streamBusinessCards", Summary: "Stream business cards as JSON lines", Params: new(card.CardQuery), Produces: "application/x-ndjson"},
+	{Method: http.MethodGet, Path: `/v1/business-cards\:export`, OperationID: "exportBusinessCards", Summary: "Export business cards as a CSV or XLSX file", Params: new(card.ExportCardsReq), Produces: "text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id", OperationID: "getBusinessCardByID", Summary: "Get a business card", Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id/history", OperationID: "getBusinessCardHistory", Summary: "Get the history of a business card", Response: new(card.CardHistory)},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id/qr", OperationID: "getQRBusinessCard", Summary: "Get the QR code of a business card", Params: new(card.CardQRReq), Produces: "image/*"},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id/ndef", OperationID: "getNDEFBusinessCard", Summary: "Get the NFC tag message of a business card", Params: new(card.NDEFReq), Produces: "application/octet-stream"},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id/poster", OperationID: "getPosterBusinessCard", Summary: "Get the printable poster of a business card", Params: new(card.PosterReq), Produces: "application/pdf"},
+	{Method: http.MethodGet, Path: "/v1/business-cards/:id/photo", OperationID: "getBusinessCardPhoto", Summary: "Get the photo of a business card", Produces: "image/*"},
+	{Method: http.MethodPost, Path: "/v1/business-cards/:id/photo", OperationID: "uploadBusinessCardPhoto", Summary: "Upload the photo of a business card", Consumes: "image/*", Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodGet, Path: `/v1/departments/:id/business-cards\:vcf`, OperationID: "exportDepartmentVCF", Summary: "Export the vCards of a department's published cards as one file or a zip", Params: new(card.DepartmentVCFReq), Produces: "text/vcard,application/zip"},
+	{Method: http.MethodGet, Path: "/v1/departments/:id/poster", OperationID: "getDepartmentPoster", Summary: "Get the printable poster of a department's cards", Params: new(card.PosterReq), Produces: "application/pdf"},
+
+	{Method: http.MethodPost, Path: "/v1/business-cards/approve", OperationID: "approveBusinessCard", Summary: "Approve a business card", Params: new(card.ApproveBusinessCardReq), Body: new(card.ApproveBusinessCardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/reject", OperationID: "rejectBusinessCard", Summary: "Reject a business card", Params: new(card.RejectBusinessCardReq), Body: new(card.RejectBusinessCardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/publish", OperationID: "publishBusinessCard", Summary: "Publish a business card", Params: new(card.PublishBusinessCardReq), Body: new(card.PublishBusinessCardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/archive", OperationID: "archiveBusinessCard", Summary: "Archive a business card", Body: new(card.ArchiveBusinessCardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: "/v1/business-cards/unarchive", OperationID: "unarchiveBusinessCard", Summary: "Unarchive a business card", Body: new(card.ArchiveBusinessCardReq), Response: new(contactqrPb.BusinessCard)},
+	{Method: http.MethodPost, Path: `/v1/business-cards\:batchArchive`, OperationID: "batchArchiveBusinessCards", Summary: "Archive the business cards matching a filter", Params: new(card.BatchArchiveReq), Body: new(card.BatchArchiveReq), Response: new(card.BatchArchiveResult)},
+
+	{Method: http.MethodGet, Path: "/v1/transliterations/suggest", OperationID: "suggestTransliteration", Summary: "Suggest the Latin spelling of a Lao name", Params: new(translit.SuggestReq), Response: new(translit.Suggestion)},
+	{Method: http.MethodGet, Path: "/v1/transliterations/overrides", OperationID: "listTransliterationOverrides", Summary: "List transliteration overrides", Response: new(translit.Override), Page: true},
+	{Method: http.MethodPost, Path: "/v1/transliterations/overrides", OperationID: "saveTransliterationOverride", Summary: "Save a transliteration override", Body: new(translit.OverrideReq), Response: new(translit.Override)},
+	{Method: http.MethodDelete, Path: "/v1/transliterations/overrides", OperationID: "deleteTransliterationOverride", Summary: "Delete a transliteration override", Params: new(translit.DeleteOverrideReq), Response: new(emptypb.Empty)},
+
+	{Method: http.MethodPost, Path: "/v1/devices", OperationID: "registerDevice", Summary: "Register a device for push notifications", Body: new(push.DeviceReq), Response: new(push.Device)},
+	{Method: http.MethodDelete, Path: "/v1/devices", OperationID: "unregisterDevice", Summary: "Unregister a device", Params: new(push.DeviceReq), Response: new(emptypb.Empty)},
+
+	{Method: http.MethodGet, Path: "/v1/analytics/scans/summary", OperationID: "getScanSummary", Summary: "Get the scan totals of a date range", Params: new(card.ScanQuery), Response: new(card.ScanSummary)},
+	{Method: http.MethodGet, Path: "/v1/analytics/scans/daily", OperationID: "listDailyScans", Summary: "List scans by day", Params: new(card.ScanQuery), Response: new(card.ListDailyScansResult)},
+	{Method: http.MethodGet, Path: "/v1/analytics/scans/top-cards", OperationID: "listTopCards", Summary: "List the most scanned cards by department", Params: new(card.ScanQuery), Response: new(card.ListTopCardsResult)},
+
+	{Method: http.MethodPost, Path: "/v1/business-cards/:id/print-requests", OperationID: "createPrintRequest", Summary: "Request physical prints of a published card", Body: new(card.PrintRequestReq), Response: new(card.PrintRequest)},
+	{Method: http.MethodGet, Path: "/v1/print-requests", OperationID: "listPrintRequests", Summary: "List print requests", Params: new(card.PrintRequestQuery), Response: new(card.PrintRequest), Page: true},
+	{Method: http.MethodPost, Path: "/v1/print-requests/:id/order", OperationID: "orderPrintRequest", Summary: "Mark a print request ordered", Response: new(card.PrintRequest)},
+	{Method: http.MethodPost, Path: "/v1/print-requests/:id/deliver", OperationID: "deliverPrintRequest", Summary: "Mark a print request delivered", Response: new(card.PrintRequest)},
+	{Method: http.MethodGet, Path: "/v1/card-templates", OperationID: "listTemplates", Summary: "List card templates", Response: new(template.Template), Page: true},
+	{Method: http.MethodPost, Path: "/v1/card-templates", OperationID: "createTemplate", Summary: "Create the card template of a company", Body: new(template.TemplateReq), Response: new(template.Template)},
+	{Method: http.MethodGet, Path: "/v1/card-templates/:id", OperationID: "getTemplate", Summary: "Get a card template", Response: new(template.Template)},
+	{Method: http.MethodPut, Path: "/v1/card-templates/:id", OperationID: "updateTemplate", Summary: "Update a card template", Body: new(template.TemplateReq), Response: new(template.Template)},
+	{Method: http.MethodDelete, Path: "/v1/card-templates/:id", OperationID: "deleteTemplate", Summary: "Delete a card template", Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/company-addresses", OperationID: "listAddresses", Summary: "List company addresses", Response: new(address.CompanyAddress), Page: true},
+	{Method: http.MethodGet, Path: "/v1/company-addresses/:id", OperationID: "getAddress", Summary: "Get the address of a company", Response: new(address.CompanyAddress)},
+	{Method: http.MethodPut, Path: "/v1/company-addresses/:id", OperationID: "saveAddress", Summary: "Set the address of a company", Body: new(address.AddressReq), Response: new(address.CompanyAddress)},
+	{Method: http.MethodDelete, Path: "/v1/company-addresses/:id", OperationID: "deleteAddress", Summary: "Delete the address of a company", Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/landing-experiments", OperationID: "listExperiments", Summary: "List landing page experiments", Response: new(card.ListExperimentsResult)},
+	{Method: http.MethodPost, Path: "/v1/landing-experiments", OperationID: "createExperiment", Summary: "Create a landing page experiment", Body: new(card.ExperimentReq), Response: new(card.Experiment)},
+	{Method: http.MethodPost, Path: "/v1/landing-experiments/:id/stop", OperationID: "stopExperiment", Summary: "Stop a landing page experiment", Response: new(card.Experiment)},
+	{Method: http.MethodGet, Path: "/v1/landing-experiments/:id/results", OperationID: "getExperimentResults", Summary: "Get the results of a landing page experiment", Response: new(card.ExperimentResults)},
+
+	{Method: http.MethodGet, Path: "/v1/audit/verify", OperationID: "verifyAuditLog", Summary: "Verify the audit log's hash chain", Response: new(audit.Verification)},
+
+	{Method: http.MethodGet, Path: "/v1/admin/jobs", OperationID: "listJobs", Summary: "List scheduled jobs", Response: []*scheduler.JobStatus{}},
+	{Method: http.MethodPost, Path: "/v1/admin/jobs/:name/run", OperationID: "triggerJob", Summary: "Run a scheduled job now", Params: new(scheduler.TriggerJobReq), Response: new(scheduler.JobStatus), Status: http.StatusAccepted},
+	{Method: http.MethodGet, Path: "/v1/admin/diagnostics", OperationID: "getDiagnostics", Summary: "Run the diagnostics", Response: new(diag.Report)},
+	{Method: http.MethodGet, Path: "/v1/admin/webhooks", OperationID: "listWebhooks", Summary: "List webhooks", Response: new(webhook.Webhook), Page: true},
+	{Method: http.MethodPost, Path: "/v1/admin/webhooks", OperationID: "createWebhook", Summary: "Register a webhook for card lifecycle events", Body: new(webhook.WebhookReq), Response: new(webhook.Webhook)},
+	{Method: http.MethodDelete, Path: "/v1/admin/webhooks/:id", OperationID: "deleteWebhook", Summary: "Delete a webhook", Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/admin/exports/deliveries", OperationID: "listExportDeliveries", Summary: "List export deliveries", Params: new(export.DeliveryQuery), Response: new(export.Delivery), Page: true},
+
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/vcf", OperationID: "getPublicVCFBusinessCard", Summary: "Get the vCard of a published card", Public: true, Params: new(card.VCFReq), Response: new(card.VCF)},
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/qr", OperationID: "getPublicQRBusinessCard", Summary: "Get the QR code of a published card", Public: true, Params: new(card.QRReq), Produces: "image/*"},
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/photo", OperationID: "getPublicBusinessCardPhoto", Summary: "Get the photo of a published card", Public: true, Produces: "image/*"},
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/preview", OperationID: "getPublicBusinessCardPreview", Summary: "Get the preview image of a published card", Public: true, Produces: "image/png"},
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/pass", OperationID: "getPublicBusinessCardPass", Summary: "Get the Apple Wallet pass of a published card", Public: true, Produces: "application/vnd.apple.pkpass"},
+	{Method: http.MethodPost, Path: "/v1/public/business-cards/:id/leads", OperationID: "submitLead", Summary: "Leave contact details for a card's owner", Public: true, Body: new(card.LeadReq), Response: new(card.Lead)},
+	{Method: http.MethodGet, Path: "/v1/public/business-cards/:id/landing", OperationID: "getLanding", Summary: "Get the landing page of a published card", Public: true, Params: new(card.LandingReq), Response: new(card.Landing)},
+	{Method: http.MethodPost, Path: "/v1/public/business-cards/:id/landing/conversions", OperationID: "saveLandingConversion", Summary: "Record a landing page conversion", Public: true, Body: new(card.LandingReq), Response: new(emptypb.Empty)},
+	{Method: http.MethodGet, Path: "/v1/public/event-cards/:id/vcf", OperationID: "getPublicVCFEventCard", Summary: "Get the vCard of an active event card", Public: true, Params: new(card.VCFReq), Response: new(card.VCF)},
+	{Method: http.MethodGet, Path: "/v1/public/event-cards/:id/qr", OperationID: "getPublicQREventCard", Summary: "Get the QR code of an active event card", Public: true, Params: new(card.QRReq), Produces: "image/*"},
+}
+
+// docsCSP lets the Swagger UI page load its assets from the CDN and the
+// document from the API.
+const docsCSP = "default-src 'none'; script-src 'self' https://cdn.jsdelivr.net; style-src https://cdn.jsdelivr.net; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"
+
+// installDocs builds the OpenAPI document of the /v1 routes installed on e
+// and serves it, with a Swagger UI to browse it.
+func (s *Server) installDocs(e *echo.Echo) error {
+	doc, err := openapi.Build(&openapi.Info{
+		Title:   "ContactQR API",
+		Version: "1",
+		Description: "Responses are described in the v1 envelope, which clients ask for with " +
+			"the X-Response-Envelope: v1 header. The legacy shape returns the data bare " +
+			"or keyed by resource name.",
+	}, new(httpPb.Status), routes)
+	if err != nil {
+		return fmt.Errorf("failed to build openapi document: %w", err)
+	}
+
+	installed := make([]string, 0)
+	for _, r := range e.Routes() {
+		if strings.HasPrefix(r.Path, "/v1/") {
+			installed = append(installed, r.Method+" "+r.Path)
+		}
+	}
+	if missing := doc.Missing(installed); len(missing) > 0 {
+		return fmt.Errorf("routes missing from the openapi document: %s", strings.Join(missing, ", "))
+	}
+
+	s.openAPI, err = json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode openapi document: %w", err)
+	}
+
+	// Installed after the check: they serve the document, not the API.
+	e.GET("/v1/openapi.json", s.getOpenAPI)
+	e.GET("/v1/docs", s.getAPIDocs)
+	e.FileFS("/v1/docs/init.js", "ui/init.js", openapi.UI)
+	return nil
+}
+
+func (s *Server) getOpenAPI(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, s.openAPI)
+}
+
+func (s *Server) getAPIDocs(c echo.Context) error {
+	page, err := openapi.UI.ReadFile("ui/index.html")
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Content-Security-Policy", docsCSP)
+	return c.HTMLBlob(http.StatusOK, page)
+}
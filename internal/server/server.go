@@ -1,25 +1,67 @@
 package server
 
 import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/address"
+	"github.com/10664kls/contactqr/internal/audit"
 	"github.com/10664kls/contactqr/internal/auth"
 	"github.com/10664kls/contactqr/internal/card"
+	"github.com/10664kls/contactqr/internal/corpmail"
+	"github.com/10664kls/contactqr/internal/diag"
+	"github.com/10664kls/contactqr/internal/drain"
 	"github.com/10664kls/contactqr/internal/employee"
+	"github.com/10664kls/contactqr/internal/envelope"
+	"github.com/10664kls/contactqr/internal/export"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/migrate"
+	"github.com/10664kls/contactqr/internal/password"
+	"github.com/10664kls/contactqr/internal/push"
+	"github.com/10664kls/contactqr/internal/scheduler"
+	"github.com/10664kls/contactqr/internal/storage"
+	"github.com/10664kls/contactqr/internal/template"
+	"github.com/10664kls/contactqr/internal/translit"
+	"github.com/10664kls/contactqr/internal/tz"
+	"github.com/10664kls/contactqr/internal/web"
+	"github.com/10664kls/contactqr/internal/webhook"
 	"github.com/labstack/echo/v4"
-	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
-	rpcStatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type Server struct {
-	employee *employee.Service
-	card     *card.Service
-	auth     *auth.Auth
+	employee  *employee.Service
+	card      *card.Service
+	auth      *auth.Auth
+	audit     *audit.Log
+	scheduler *scheduler.Scheduler
+	drainer   *drain.Drainer
+	translit  *translit.Service
+	push      *push.Service
+	diag      *diag.Diagnostics
+	export    *export.Exporter
+	webhook   *webhook.Service
+	password  *password.Service
+	templates *template.Service
+	addresses *address.Service
+	pages     *web.Renderer
+	migrator  *migrate.Migrator
+
+	// openAPI is the encoded OpenAPI document, built by Install.
+	openAPI []byte
 }
 
-func NewServer(emp *employee.Service, card *card.Service, auth *auth.Auth) (*Server, error) {
+func NewServer(emp *employee.Service, card *card.Service, auth *auth.Auth, audit *audit.Log, scheduler *scheduler.Scheduler, drainer *drain.Drainer, translit *translit.Service, push *push.Service, diag *diag.Diagnostics, export *export.Exporter, webhook *webhook.Service, password *password.Service, templates *template.Service, addresses *address.Service, pages *web.Renderer, migrator *migrate.Migrator) (*Server, error) {
 	if emp == nil {
 		return nil, errors.New("employee service is nil")
 	}
@@ -29,11 +71,63 @@ func NewServer(emp *employee.Service, card *card.Service, auth *auth.Auth) (*Ser
 	if auth == nil {
 		return nil, errors.New("auth service is nil")
 	}
+	if audit == nil {
+		return nil, errors.New("audit log is nil")
+	}
+	if scheduler == nil {
+		return nil, errors.New("scheduler is nil")
+	}
+	if drainer == nil {
+		return nil, errors.New("drainer is nil")
+	}
+	if translit == nil {
+		return nil, errors.New("translit service is nil")
+	}
+	if push == nil {
+		return nil, errors.New("push service is nil")
+	}
+	if diag == nil {
+		return nil, errors.New("diagnostics is nil")
+	}
+	if export == nil {
+		return nil, errors.New("exporter is nil")
+	}
+	if webhook == nil {
+		return nil, errors.New("webhook service is nil")
+	}
+	if password == nil {
+		return nil, errors.New("password service is nil")
+	}
+	if templates == nil {
+		return nil, errors.New("template service is nil")
+	}
+	if addresses == nil {
+		return nil, errors.New("address service is nil")
+	}
+	if pages == nil {
+		return nil, errors.New("pages renderer is nil")
+	}
+	if migrator == nil {
+		return nil, errors.New("migrator is nil")
+	}
 
 	return &Server{
-		employee: emp,
-		card:     card,
-		auth:     auth,
+		employee:  emp,
+		card:      card,
+		auth:      auth,
+		audit:     audit,
+		scheduler: scheduler,
+		drainer:   drainer,
+		translit:  translit,
+		push:      push,
+		diag:      diag,
+		export:    export,
+		webhook:   webhook,
+		password:  password,
+		templates: templates,
+		addresses: addresses,
+		pages:     pages,
+		migrator:  migrator,
 	}, nil
 }
 
@@ -45,47 +139,187 @@ func (s *Server) Install(e *echo.Echo, mws ...echo.MiddlewareFunc) error {
 	v1 := e.Group("/v1")
 	v1.POST("/auth/login", s.login)
 	v1.POST("/auth/token", s.refreshToken)
+	v1.POST("/auth/logout", s.logout, mws...)
 	v1.GET("/auth/profile", s.authProfile, mws...)
+	v1.POST("/auth/sessions/report", s.reportSession)
+	v1.POST("/auth/password\\:change", s.changePassword, mws...)
+	v1.POST("/auth/password\\:reset", s.resetPassword)
+	v1.POST("/users/:id/password\\:reset", s.issuePasswordReset, mws...)
+
+	v1.GET("/errors", s.listErrors)
 
 	v1.GET("/employees", s.listEmployees, mws...)
 	v1.GET("/employees/:id", s.getEmployeeByID, mws...)
 	v1.GET("/employees/me/profile", s.getMyEmployeeProfile, mws...)
+	v1.GET("/employees/me/preferences", s.getMyPreferences, mws...)
+	v1.PUT("/employees/me/preferences", s.updateMyPreferences, mws...)
+
+	v1.GET("/guests", s.listGuests, mws...)
+	v1.POST("/guests", s.createGuest, mws...)
+	v1.GET("/guests/:id", s.getGuestByID, mws...)
+	v1.POST("/guests/:id/business-cards", s.createGuestBusinessCard, mws...)
 
 	v1.POST("/business-cards", s.createBusinessCard, mws...)
+	v1.POST("/business-cards\\:preview", s.previewBusinessCard, mws...)
 	v1.PUT("/business-cards/:id", s.updateBusinessCard, mws...)
 	v1.GET("/business-cards/me", s.listMyBusinessCards, mws...)
-	v1.GET("/business-cards/me/vcf/:id", s.getMyVCFBusinessCardByID)
+	v1.GET("/business-cards/me\\:sync", s.syncMyBusinessCards, mws...)
+	v1.GET("/business-cards/me/vcf/:id", s.getMyVCFBusinessCardByID, mws...)
 	v1.GET("/business-cards/me/approval", s.listMyApprovalBusinessCards, mws...)
 	v1.GET("/business-cards/me/approval/:id", s.getMyApprovalBusinessCardByID, mws...)
+	v1.GET("/business-cards/me/approval/:id/diff", s.getMyApprovalBusinessCardDiff, mws...)
 	v1.GET("/business-cards/me/:id", s.getMyBusinessCardByID, mws...)
+	v1.DELETE("/business-cards/me/:id", s.deleteMyBusinessCard, mws...)
+	v1.GET("/business-cards/me/:id/stats", s.getMyCardStats, mws...)
+	v1.GET("/business-cards/me/:id/leads", s.listMyLeads, mws...)
+	v1.GET("/business-cards/me/:id/leads/csv", s.exportMyLeads, mws...)
+	v1.GET("/business-cards/me/:id/events", s.listMyEventCards, mws...)
+	v1.POST("/business-cards/me/:id/events", s.createMyEventCard, mws...)
+	v1.GET("/business-cards/me/:id/share-links", s.listMyShareLinks, mws...)
+	v1.POST("/business-cards/me/:id/share-links", s.createMyShareLink, mws...)
+	v1.DELETE("/business-cards/me/:id/share-links/:linkId", s.revokeMyShareLink, mws...)
+	v1.POST("/event-cards/issue", s.issueEventCards, mws...)
 	v1.GET("/business-cards", s.listBusinessCards, mws...)
+	v1.GET("/business-cards/stream", s.streamBusinessCards, mws...)
+	v1.GET("/business-cards\\:export", s.exportBusinessCards, mws...)
 	v1.GET("/business-cards/:id", s.getBusinessCardByID, mws...)
+	v1.GET("/business-cards/:id/history", s.getBusinessCardHistory, mws...)
+	v1.GET("/business-cards/:id/qr", s.getQRBusinessCard, mws...)
+	v1.GET("/business-cards/:id/ndef", s.getNDEFBusinessCard, mws...)
+	v1.GET("/business-cards/:id/poster", s.getPosterBusinessCard, mws...)
+	v1.GET("/business-cards/:id/photo", s.getBusinessCardPhoto, mws...)
+	v1.POST("/business-cards/:id/photo", s.uploadBusinessCardPhoto, mws...)
+	v1.GET("/departments/:id/poster", s.getDepartmentPoster, mws...)
+	v1.GET("/departments/:id/business-cards\\:vcf", s.exportDepartmentVCF, mws...)
 
 	v1.POST("/business-cards/approve", s.approveBusinessCard, mws...)
 	v1.POST("/business-cards/reject", s.rejectBusinessCard, mws...)
 	v1.POST("/business-cards/publish", s.publishBusinessCard, mws...)
+	v1.POST("/business-cards/archive", s.archiveBusinessCard, mws...)
+	v1.POST("/business-cards/unarchive", s.unarchiveBusinessCard, mws...)
+	v1.POST("/business-cards\\:batchArchive", s.batchArchiveBusinessCards, mws...)
+
+	v1.GET("/transliterations/suggest", s.suggestTransliteration, mws...)
+	v1.GET("/transliterations/overrides", s.listTransliterationOverrides, mws...)
+	v1.POST("/transliterations/overrides", s.saveTransliterationOverride, mws...)
+	v1.DELETE("/transliterations/overrides", s.deleteTransliterationOverride, mws...)
+
+	v1.POST("/devices", s.registerDevice, mws...)
+	v1.DELETE("/devices", s.unregisterDevice, mws...)
+
+	v1.GET("/analytics/scans/summary", s.getScanSummary, mws...)
+	v1.GET("/analytics/scans/daily", s.listDailyScans, mws...)
+	v1.GET("/analytics/scans/top-cards", s.listTopCards, mws...)
+
+	v1.POST("/business-cards/:id/print-requests", s.createPrintRequest, mws...)
+	v1.GET("/print-requests", s.listPrintRequests, mws...)
+	v1.POST("/print-requests/:id/order", s.orderPrintRequest, mws...)
+	v1.POST("/print-requests/:id/deliver", s.deliverPrintRequest, mws...)
+
+	v1.GET("/card-templates", s.listTemplates, mws...)
+	v1.POST("/card-templates", s.createTemplate, mws...)
+	v1.GET("/card-templates/:id", s.getTemplate, mws...)
+	v1.PUT("/card-templates/:id", s.updateTemplate, mws...)
+	v1.DELETE("/card-templates/:id", s.deleteTemplate, mws...)
+
+	v1.GET("/company-addresses", s.listAddresses, mws...)
+	v1.GET("/company-addresses/:id", s.getAddress, mws...)
+	v1.PUT("/company-addresses/:id", s.saveAddress, mws...)
+	v1.DELETE("/company-addresses/:id", s.deleteAddress, mws...)
+
+	v1.GET("/landing-experiments", s.listExperiments, mws...)
+	v1.POST("/landing-experiments", s.createExperiment, mws...)
+	v1.POST("/landing-experiments/:id/stop", s.stopExperiment, mws...)
+	v1.GET("/landing-experiments/:id/results", s.getExperimentResults, mws...)
+
+	v1.GET("/audit/verify", s.verifyAuditLog, mws...)
+
+	v1.GET("/admin/jobs", s.listJobs, mws...)
+	v1.POST("/admin/jobs/:name/run", s.triggerJob, mws...)
+	v1.GET("/admin/diagnostics", s.getDiagnostics, mws...)
+	v1.GET("/admin/exports/deliveries", s.listExportDeliveries, mws...)
+	v1.GET("/admin/webhooks", s.listWebhooks, mws...)
+	v1.POST("/admin/webhooks", s.createWebhook, mws...)
+	v1.DELETE("/admin/webhooks/:id", s.deleteWebhook, mws...)
+
+	// Public routes are reached by scanning a card's QR code and take the
+	// card's public ID, never its internal ID.
+	v1.GET("/public/business-cards/:id/vcf", s.getPublicVCFBusinessCard)
+	v1.GET("/public/business-cards/:id/qr", s.getPublicQRBusinessCard)
+	v1.GET("/public/business-cards/:id/photo", s.getPublicBusinessCardPhoto)
+	v1.GET("/public/business-cards/:id/preview", s.getPublicBusinessCardPreview)
+	v1.GET("/public/business-cards/:id/pass", s.getPublicBusinessCardPass)
+	v1.POST("/public/business-cards/:id/leads", s.submitLead)
+	v1.GET("/public/business-cards/:id/landing", s.getLanding)
+	v1.POST("/public/business-cards/:id/landing/conversions", s.saveLandingConversion)
+	v1.GET("/public/event-cards/:id/vcf", s.getPublicVCFEventCard)
+	v1.GET("/public/event-cards/:id/qr", s.getPublicQREventCard)
+
+	// Card pages are HTML for visitors opening a card's link in a browser.
+	e.GET("/p/:cardId", s.getCardPage)
+	e.GET("/p/:cardId/vcf", s.downloadCardPageVCF)
+
+	// Shared pages are card pages opened through a share link, whose
+	// token stands in for the card's public ID.
+	e.GET("/s/:token", s.getSharedCardPage)
+	e.GET("/s/:token/vcf", s.downloadSharedCardPageVCF)
+
+	return s.installDocs(e)
+}
+
+// InstallInternal installs the operational endpoints used by the load
+// balancer and deploy tooling. They are not part of the public API.
+func (s *Server) InstallInternal(e *echo.Echo, mws ...echo.MiddlewareFunc) error {
+	if e == nil {
+		return errors.New("echo is nil")
+	}
+
+	internal := e.Group("/internal", mws...)
+	internal.GET("/ready", s.ready)
+	internal.GET("/drain", s.drainState)
+	internal.POST("/drain", s.drain)
+	internal.POST("/token-keys/rotate", s.rotateTokenKeys)
+	internal.POST("/users/:username/sessions/revoke", s.revokeUserSessions)
+	internal.GET("/migrations", s.migrationStatus)
+	internal.POST("/migrations/apply", s.applyMigrations)
+	internal.PUT("/employees/:id/photo", s.saveEmployeePhoto)
 
 	return nil
 }
 
 func badJSON() error {
-	s, _ := rpcStatus.New(codes.InvalidArgument, "Request body must be a valid JSON.").
-		WithDetails(&edPb.ErrorInfo{
-			Reason: "BINDING_ERROR",
-			Domain: "http",
-		})
-
-	return s.Err()
+	return i18n.Error(codes.InvalidArgument, i18n.InvalidJSON)
 }
 
 func badParam() error {
-	s, _ := rpcStatus.New(codes.InvalidArgument, "Request parameters must be a valid type.").
-		WithDetails(&edPb.ErrorInfo{
-			Reason: "BINDING_ERROR",
-			Domain: "http",
-		})
+	return i18n.Error(codes.InvalidArgument, i18n.InvalidParameter)
+}
+
+// bindDryRun reads the dryRun query parameter of card actions, which
+// c.Bind only binds on GET and DELETE requests.
+func bindDryRun(c echo.Context, dryRun *bool) error {
+	if err := echo.QueryParamsBinder(c).Bool("dryRun", dryRun).BindError(); err != nil {
+		return badParam()
+	}
+	return nil
+}
+
+// businessCard responds with a BusinessCardResponse.
+func businessCard(c echo.Context, bc *card.Card) error {
+	pb := bc.Proto()
+	return envelope.Message(c, http.StatusOK, &contactqrPb.BusinessCardResponse{BusinessCard: pb}, pb)
+}
+
+// businessCards responds with a ListBusinessCardsResponse.
+func businessCards(c echo.Context, cards *card.ListCardsResult) error {
+	res := cards.Proto()
+	return envelope.MessagePage(c, http.StatusOK, res, res.BusinessCards, res.NextPageToken, cards.TotalSize)
+}
 
-	return s.Err()
+// listErrors lists the error reasons clients may receive, with their
+// messages in every supported language.
+func (s *Server) listErrors(c echo.Context) error {
+	return envelope.JSON(c, http.StatusOK, "errors", i18n.Catalog())
 }
 
 func (s *Server) listEmployees(c echo.Context) error {
@@ -99,7 +333,7 @@ func (s *Server) listEmployees(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, employees)
+	return envelope.Page(c, http.StatusOK, employees, employees.Employees, employees.NextPageToken, employees.TotalSize)
 }
 
 func (s *Server) getEmployeeByID(c echo.Context) error {
@@ -114,9 +348,7 @@ func (s *Server) getEmployeeByID(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, echo.Map{
-		"employee": employee,
-	})
+	return envelope.JSON(c, http.StatusOK, "employee", employee)
 }
 
 func (s *Server) getMyEmployeeProfile(c echo.Context) error {
@@ -125,9 +357,82 @@ func (s *Server) getMyEmployeeProfile(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, echo.Map{
-		"employeeProfile": employee,
-	})
+	return envelope.JSON(c, http.StatusOK, "employeeProfile", employee)
+}
+
+func (s *Server) getMyPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+	preferences, err := s.employee.GetMyPreferences(ctx)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "preferences", preferences)
+}
+
+func (s *Server) updateMyPreferences(c echo.Context) error {
+	req := new(employee.PreferencesReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	preferences, err := s.employee.UpdateMyPreferences(ctx, req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "preferences", preferences)
+}
+
+func (s *Server) listGuests(c echo.Context) error {
+	req := new(card.GuestQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	guests, err := s.card.ListGuests(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, guests, guests.Guests, guests.NextPageToken, nil)
+}
+
+func (s *Server) createGuest(c echo.Context) error {
+	req := new(card.GuestReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	guest, err := s.card.CreateGuest(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "guest", guest)
+}
+
+func (s *Server) getGuestByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	guest, err := s.card.GetGuestByID(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "guest", guest)
+}
+
+func (s *Server) createGuestBusinessCard(c echo.Context) error {
+	req := new(card.CardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	card, err := s.card.CreateGuestBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return businessCard(c, card)
 }
 
 func (s *Server) createBusinessCard(c echo.Context) error {
@@ -135,6 +440,7 @@ func (s *Server) createBusinessCard(c echo.Context) error {
 	if err := c.Bind(req); err != nil {
 		return badJSON()
 	}
+	req.IdempotencyKey = c.Request().Header.Get("Idempotency-Key")
 
 	ctx := c.Request().Context()
 	card, err := s.card.CreateBusinessCard(ctx, req)
@@ -142,9 +448,22 @@ func (s *Server) createBusinessCard(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
+}
+
+func (s *Server) previewBusinessCard(c echo.Context) error {
+	req := new(card.CardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	p, err := s.card.PreviewBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	pb := p.Proto()
+	return envelope.Message(c, http.StatusOK, &contactqrPb.BusinessCardPreviewResponse{Preview: pb}, pb)
 }
 
 func (s *Server) updateBusinessCard(c echo.Context) error {
@@ -159,9 +478,7 @@ func (s *Server) updateBusinessCard(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
 }
 
 func (s *Server) listMyBusinessCards(c echo.Context) error {
@@ -176,7 +493,29 @@ func (s *Server) listMyBusinessCards(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, cards)
+	return businessCards(c, cards)
+}
+
+func (s *Server) syncMyBusinessCards(c echo.Context) error {
+	req := new(card.SyncReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res, err := s.card.SyncMyBusinessCards(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) deleteMyBusinessCard(c echo.Context) error {
+	if err := s.card.DeleteMyBusinessCard(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
 }
 
 func (s *Server) getMyBusinessCardByID(c echo.Context) error {
@@ -191,9 +530,7 @@ func (s *Server) getMyBusinessCardByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
 }
 
 func (s *Server) listBusinessCards(c echo.Context) error {
@@ -208,7 +545,93 @@ func (s *Server) listBusinessCards(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, cards)
+	return businessCards(c, cards)
+}
+
+// streamBusinessCards writes matching cards as newline-delimited JSON,
+// flushing periodically so clients receive rows while the query runs.
+func (s *Server) streamBusinessCards(c echo.Context) error {
+	req := new(card.CardQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	const flushEvery = 100
+
+	res := c.Response()
+	enc := json.NewEncoder(res)
+	var n int
+	err := s.card.StreamBusinessCards(c.Request().Context(), req, func(bc *card.Card) error {
+		if n == 0 {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.WriteHeader(http.StatusOK)
+		}
+		if err := enc.Encode(bc); err != nil {
+			return err
+		}
+		n++
+		if n%flushEvery == 0 {
+			res.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Once rows were written the status is committed and the error can
+		// only be reported by cutting the stream short.
+		if res.Committed {
+			return nil
+		}
+		return err
+	}
+
+	if n == 0 {
+		res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		res.WriteHeader(http.StatusOK)
+	}
+	res.Flush()
+
+	return nil
+}
+
+// exportBusinessCards downloads the matching cards as a CSV or XLSX file.
+func (s *Server) exportBusinessCards(c echo.Context) error {
+	req := new(card.ExportCardsReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res := c.Response()
+	w := &download{res: res, start: func() {
+		f, _ := export.ParseFormat(req.Format)
+		res.Header().Set(echo.HeaderContentType, f.ContentType())
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "business-cards."+string(f)))
+		res.WriteHeader(http.StatusOK)
+	}}
+
+	if err := s.card.ExportBusinessCards(c.Request().Context(), req, w); err != nil {
+		// Once the file was started the error can only be reported by
+		// cutting it short.
+		if res.Committed {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// download sends the headers of a file download with its first write, so
+// errors before it are still reported as such.
+type download struct {
+	res   *echo.Response
+	start func()
+}
+
+func (d *download) Write(p []byte) (int, error) {
+	if !d.res.Committed {
+		d.start()
+	}
+	return d.res.Write(p)
 }
 
 func (s *Server) getBusinessCardByID(c echo.Context) error {
@@ -223,9 +646,7 @@ func (s *Server) getBusinessCardByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
 }
 
 func (s *Server) listMyApprovalBusinessCards(c echo.Context) error {
@@ -240,7 +661,7 @@ func (s *Server) listMyApprovalBusinessCards(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, cards)
+	return businessCards(c, cards)
 }
 
 func (s *Server) login(c echo.Context) error {
@@ -249,13 +670,19 @@ func (s *Server) login(c echo.Context) error {
 		return badJSON()
 	}
 
+	req.SetClient(auth.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+		DeviceID:  c.Request().Header.Get("X-Device-ID"),
+	})
+
 	ctx := c.Request().Context()
 	token, err := s.auth.Login(ctx, req)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, token)
+	return envelope.JSON(c, http.StatusOK, "", token)
 }
 
 func (s *Server) refreshToken(c echo.Context) error {
@@ -270,7 +697,72 @@ func (s *Server) refreshToken(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, token)
+	return envelope.JSON(c, http.StatusOK, "", token)
+}
+
+func (s *Server) logout(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := s.auth.Logout(ctx); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) reportSession(c echo.Context) error {
+	req := new(auth.ReportSessionReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	if err := s.auth.ReportSession(ctx, req); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) changePassword(c echo.Context) error {
+	req := new(password.ChangeReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	if err := s.password.Change(ctx, req); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) resetPassword(c echo.Context) error {
+	req := new(password.ResetReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	if err := s.password.Reset(ctx, req); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) issuePasswordReset(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	ctx := c.Request().Context()
+	if err := s.password.IssueReset(ctx, id); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
 }
 
 func (s *Server) authProfile(c echo.Context) error {
@@ -280,9 +772,7 @@ func (s *Server) authProfile(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"profile": profile,
-	})
+	return envelope.JSON(c, http.StatusOK, "profile", profile)
 }
 
 func (s *Server) approveBusinessCard(c echo.Context) error {
@@ -290,6 +780,9 @@ func (s *Server) approveBusinessCard(c echo.Context) error {
 	if err := c.Bind(req); err != nil {
 		return badJSON()
 	}
+	if err := bindDryRun(c, &req.DryRun); err != nil {
+		return err
+	}
 
 	ctx := c.Request().Context()
 	card, err := s.card.ApproveBusinessCard(ctx, req)
@@ -297,9 +790,7 @@ func (s *Server) approveBusinessCard(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
 }
 
 func (s *Server) rejectBusinessCard(c echo.Context) error {
@@ -307,6 +798,9 @@ func (s *Server) rejectBusinessCard(c echo.Context) error {
 	if err := c.Bind(req); err != nil {
 		return badJSON()
 	}
+	if err := bindDryRun(c, &req.DryRun); err != nil {
+		return err
+	}
 
 	ctx := c.Request().Context()
 	card, err := s.card.RejectBusinessCard(ctx, req)
@@ -314,9 +808,7 @@ func (s *Server) rejectBusinessCard(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
 }
 
 func (s *Server) publishBusinessCard(c echo.Context) error {
@@ -324,6 +816,9 @@ func (s *Server) publishBusinessCard(c echo.Context) error {
 	if err := c.Bind(req); err != nil {
 		return badJSON()
 	}
+	if err := bindDryRun(c, &req.DryRun); err != nil {
+		return err
+	}
 
 	ctx := c.Request().Context()
 	card, err := s.card.PublishBusinessCard(ctx, req)
@@ -331,9 +826,54 @@ func (s *Server) publishBusinessCard(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
+}
+
+func (s *Server) archiveBusinessCard(c echo.Context) error {
+	req := new(card.ArchiveBusinessCardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	card, err := s.card.ArchiveBusinessCard(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return businessCard(c, card)
+}
+
+func (s *Server) unarchiveBusinessCard(c echo.Context) error {
+	req := new(card.ArchiveBusinessCardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	card, err := s.card.UnarchiveBusinessCard(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return businessCard(c, card)
+}
+
+func (s *Server) batchArchiveBusinessCards(c echo.Context) error {
+	req := new(card.BatchArchiveReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+	if err := bindDryRun(c, &req.DryRun); err != nil {
+		return err
+	}
+
+	res, err := s.card.BatchArchiveBusinessCards(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", res)
 }
 
 func (s *Server) getMyApprovalBusinessCardByID(c echo.Context) error {
@@ -348,16 +888,1114 @@ func (s *Server) getMyApprovalBusinessCardByID(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"businessCard": card,
-	})
+	return businessCard(c, card)
+}
+
+func (s *Server) getMyApprovalBusinessCardDiff(c echo.Context) error {
+	req := new(card.CardQuery)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	diff, err := s.card.GetMyApprovalBusinessCardDiff(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "diff", diff)
 }
 
 func (s *Server) getMyVCFBusinessCardByID(c echo.Context) error {
-	vcf, err := s.card.GetMyVCFBusinessCardByID(c.Request().Context(), c.Param("id"))
+	req := new(card.VCFReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	vcf, err := s.card.GetMyVCFBusinessCardByID(c.Request().Context(), req)
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, vcf)
+	return envelope.JSON(c, http.StatusOK, "", vcf)
+}
+
+func (s *Server) getPublicVCFBusinessCard(c echo.Context) error {
+	req := new(card.VCFReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	vcf, err := s.card.GetPublicVCFBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", vcf)
+}
+
+func (s *Server) getCardPage(c echo.Context) error {
+	lang := i18n.Negotiate(c.Request().Header.Get("Accept-Language"))
+
+	cc, err := s.card.GetPublicBusinessCard(c.Request().Context(), c.Param("cardId"), c.Request().UserAgent())
+	if status.Code(err) == codes.PermissionDenied {
+		page, err := s.pages.RenderNotFound(lang)
+		if err != nil {
+			return err
+		}
+		return c.HTMLBlob(http.StatusNotFound, page)
+	}
+	if err != nil {
+		return err
+	}
+
+	page, err := s.pages.RenderCard(cc.CompanyID, lang, cardPage(cc))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	c.Response().Header().Set("Vary", "Accept-Language")
+	return c.HTMLBlob(http.StatusOK, page)
+}
+
+// cardPage returns what the card page shows of c. Personal email
+// addresses are masked for visitors, so they are left out rather than
+// shown as a broken link.
+func cardPage(c *card.Card) *web.Card {
+	page := &web.Card{
+		DisplayName:    c.DisplayName,
+		PositionName:   c.PositionName,
+		DepartmentName: c.DepartmentName,
+		CompanyName:    c.CompanyName,
+		PhoneNumber:    c.PhoneDisplay,
+		PhoneURI:       c.PhoneE164,
+		VCFURL:         "/p/" + url.PathEscape(c.PublicID) + "/vcf",
+	}
+	t := c.Template
+	if t == nil {
+		t = template.Default.Design()
+	}
+	page.LogoURL = t.LogoURL
+	page.PrimaryColor = t.PrimaryColor
+	page.AccentColor = t.AccentColor
+	if page.PhoneURI == "" {
+		page.PhoneURI = c.PhoneNumber
+	}
+	if !corpmail.IsPersonal(c.Email) {
+		page.Email = c.Email
+	}
+	return page
+}
+
+// downloadCardPageVCF serves the vCard of the card page's "save contact"
+// button as a file phones offer to add to their contacts.
+func (s *Server) downloadCardPageVCF(c echo.Context) error {
+	req := &card.VCFReq{ID: c.Param("cardId")}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	vcf, err := s.card.GetPublicVCFBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(vcf.Content)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", req.ID+".vcf"))
+	return c.Blob(http.StatusOK, "text/vcard; charset=utf-8", data)
+}
+
+// getSharedCardPage serves the card page of a share link. Share links are
+// for cards not yet public, so the page is neither cached nor indexed.
+func (s *Server) getSharedCardPage(c echo.Context) error {
+	lang := i18n.Negotiate(c.Request().Header.Get("Accept-Language"))
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("X-Robots-Tag", "noindex")
+
+	cc, err := s.card.GetSharedBusinessCard(c.Request().Context(), c.Param("token"), c.RealIP())
+	if status.Code(err) == codes.PermissionDenied {
+		page, err := s.pages.RenderNotFound(lang)
+		if err != nil {
+			return err
+		}
+		return c.HTMLBlob(http.StatusNotFound, page)
+	}
+	if err != nil {
+		return err
+	}
+
+	page := cardPage(cc)
+	page.VCFURL = "/s/" + url.PathEscape(c.Param("token")) + "/vcf"
+	html, err := s.pages.RenderCard(cc.CompanyID, lang, page)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Vary", "Accept-Language")
+	return c.HTMLBlob(http.StatusOK, html)
+}
+
+// downloadSharedCardPageVCF serves the vCard of a shared card page's "save
+// contact" button.
+func (s *Server) downloadSharedCardPageVCF(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	req := &card.VCFReq{ID: c.Param("token")}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	vcf, err := s.card.GetSharedVCFBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(vcf.Content)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="card.vcf"`)
+	return c.Blob(http.StatusOK, "text/vcard; charset=utf-8", data)
+}
+
+func (s *Server) submitLead(c echo.Context) error {
+	req := new(card.LeadReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+
+	lead, err := s.card.SubmitLead(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "lead", lead)
+}
+
+func (s *Server) getMyCardStats(c echo.Context) error {
+	req := new(card.CardStatsQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	stats, err := s.card.GetMyCardStats(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "stats", stats)
+}
+
+func (s *Server) listMyLeads(c echo.Context) error {
+	req := new(card.LeadQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	leads, err := s.card.ListMyLeads(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.Page(c, http.StatusOK, leads, leads.Leads, leads.NextPageToken, nil)
+}
+
+// exportMyLeads writes the leads on a card as CSV for follow-up in a
+// spreadsheet or CRM.
+func (s *Server) exportMyLeads(c echo.Context) error {
+	req := new(card.LeadQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	ctx := c.Request().Context()
+	loc := tz.FromContext(ctx)
+
+	res := c.Response()
+	w := csv.NewWriter(res)
+	start := func() {
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "leads-"+req.CardID+".csv"))
+		res.WriteHeader(http.StatusOK)
+		// A BOM makes Excel read the file as UTF-8, keeping Lao and Thai
+		// names intact.
+		res.Write([]byte("\ufeff"))
+		w.Write(card.LeadCSVHeader)
+	}
+
+	var n int
+	err := s.card.StreamMyLeads(ctx, req, func(l *card.Lead) error {
+		if n == 0 {
+			start()
+		}
+		n++
+		return w.Write(l.CSV(loc))
+	})
+	if err != nil {
+		if res.Committed {
+			return nil
+		}
+		return err
+	}
+
+	if n == 0 {
+		start()
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+func (s *Server) getBusinessCardHistory(c echo.Context) error {
+	history, err := s.card.GetBusinessCardHistory(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "history", history)
+}
+
+func (s *Server) getQRBusinessCard(c echo.Context) error {
+	req := new(card.CardQRReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	qr, err := s.card.GetBusinessCardQR(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	// The code links to the card's public ID, which never changes, so it
+	// only depends on the rendering options.
+	etag := fmt.Sprintf("%q", qr.Key)
+	res := c.Response()
+	res.Header().Set("ETag", etag)
+	res.Header().Set("Cache-Control", "private, max-age=86400")
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, qr.ContentType, qr.Data)
+}
+
+// getNDEFBusinessCard serves the raw NDEF message for NFC encoders. URL
+// records point at this server as the client reached it.
+func (s *Server) getNDEFBusinessCard(c echo.Context) error {
+	req := new(card.NDEFReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	msg, err := s.card.GetNDEFBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set("X-NDEF-Format", msg.Format)
+	res.Header().Set("X-NDEF-Size", strconv.Itoa(msg.Size))
+	res.Header().Set("X-NDEF-Budget", strconv.Itoa(msg.Budget))
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", req.ID+".ndef"))
+
+	return c.Blob(http.StatusOK, "application/octet-stream", msg.Data)
+}
+
+func (s *Server) getPosterBusinessCard(c echo.Context) error {
+	req := new(card.PosterReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	pdf, err := s.card.GetPosterBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return attachment(c, pdf)
+}
+
+func (s *Server) getDepartmentPoster(c echo.Context) error {
+	req := new(card.PosterReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	pdf, err := s.card.GetDepartmentPoster(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return attachment(c, pdf)
+}
+
+func (s *Server) exportDepartmentVCF(c echo.Context) error {
+	req := new(card.DepartmentVCFReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res := c.Response()
+	w := &download{res: res, start: func() {
+		contentType := "text/vcard; charset=utf-8"
+		if req.Format == card.BundleZip {
+			contentType = "application/zip"
+		}
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "department-"+req.ID+"."+req.Format))
+		res.WriteHeader(http.StatusOK)
+	}}
+
+	if err := s.card.ExportDepartmentVCF(c.Request().Context(), req, w); err != nil {
+		// Once the file was started the error can only be reported by
+		// cutting it short.
+		if res.Committed {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) getBusinessCardPhoto(c echo.Context) error {
+	photo, err := s.card.GetBusinessCardPhoto(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return image(c, photo, "private")
+}
+
+func (s *Server) getPublicBusinessCardPhoto(c echo.Context) error {
+	photo, err := s.card.GetPublicBusinessCardPhoto(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return image(c, photo, "public")
+}
+
+func (s *Server) getPublicBusinessCardPreview(c echo.Context) error {
+	preview, err := s.card.GetPublicBusinessCardPreview(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return image(c, preview, "public")
+}
+
+func (s *Server) getPublicBusinessCardPass(c echo.Context) error {
+	pass, err := s.card.GetPublicBusinessCardPass(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return attachment(c, pass)
+}
+
+// uploadBusinessCardPhoto receives a card's photo as the raw request body.
+func (s *Server) uploadBusinessCardPhoto(c echo.Context) error {
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPhotoBody))
+	if err != nil {
+		return badParam()
+	}
+
+	card, err := s.card.UploadBusinessCardPhoto(c.Request().Context(), c.Param("id"), data)
+	if err != nil {
+		return err
+	}
+
+	return businessCard(c, card)
+}
+
+// image responds with obj, an image whose key is the hash of its content,
+// cacheable as given until it changes.
+func image(c echo.Context, obj *storage.Object, cache string) error {
+	etag := fmt.Sprintf("%q", obj.Key)
+	res := c.Response()
+	res.Header().Set("ETag", etag)
+	res.Header().Set("Cache-Control", cache+", max-age=3600")
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, obj.ContentType, obj.Data)
+}
+
+// attachment responds with obj as a file to download.
+func attachment(c echo.Context, obj *storage.Object) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", obj.Key))
+	return c.Blob(http.StatusOK, obj.ContentType, obj.Data)
+}
+
+func (s *Server) listMyEventCards(c echo.Context) error {
+	res, err := s.card.ListMyEventCards(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) createMyEventCard(c echo.Context) error {
+	req := new(card.EventCardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ec, err := s.card.CreateMyEventCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "eventCard", ec)
+}
+
+func (s *Server) listMyShareLinks(c echo.Context) error {
+	res, err := s.card.ListMyShareLinks(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) createMyShareLink(c echo.Context) error {
+	req := new(card.ShareLinkReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	l, err := s.card.CreateMyShareLink(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "shareLink", l)
+}
+
+func (s *Server) revokeMyShareLink(c echo.Context) error {
+	if err := s.card.RevokeMyShareLink(c.Request().Context(), c.Param("id"), c.Param("linkId")); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) issueEventCards(c echo.Context) error {
+	req := new(card.EventCardReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	res, err := s.card.IssueEventCards(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) getScanSummary(c echo.Context) error {
+	req := new(card.ScanQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	summary, err := s.card.GetScanSummary(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "scanSummary", summary)
+}
+
+func (s *Server) listDailyScans(c echo.Context) error {
+	req := new(card.ScanQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res, err := s.card.ListDailyScans(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) listTopCards(c echo.Context) error {
+	req := new(card.ScanQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res, err := s.card.ListTopCards(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) listExperiments(c echo.Context) error {
+	res, err := s.card.ListExperiments(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) createExperiment(c echo.Context) error {
+	req := new(card.ExperimentReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	experiment, err := s.card.CreateExperiment(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "experiment", experiment)
+}
+
+func (s *Server) stopExperiment(c echo.Context) error {
+	experiment, err := s.card.StopExperiment(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "experiment", experiment)
+}
+
+func (s *Server) getExperimentResults(c echo.Context) error {
+	res, err := s.card.GetExperimentResults(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "", res)
+}
+
+func (s *Server) createPrintRequest(c echo.Context) error {
+	req := new(card.PrintRequestReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	pr, err := s.card.CreatePrintRequest(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "printRequest", pr)
+}
+
+func (s *Server) listPrintRequests(c echo.Context) error {
+	req := new(card.PrintRequestQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res, err := s.card.ListPrintRequests(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, res, res.PrintRequests, res.NextPageToken, nil)
+}
+
+func (s *Server) orderPrintRequest(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	pr, err := s.card.OrderPrintRequest(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "printRequest", pr)
+}
+
+func (s *Server) deliverPrintRequest(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	pr, err := s.card.DeliverPrintRequest(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "printRequest", pr)
+}
+
+func (s *Server) getLanding(c echo.Context) error {
+	req := new(card.LandingReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+
+	landing, err := s.card.GetLanding(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "landing", landing)
+}
+
+func (s *Server) saveLandingConversion(c echo.Context) error {
+	req := new(card.LandingReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+
+	if err := s.card.SaveLandingConversion(c.Request().Context(), req); err != nil {
+		return err
+	}
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) getPublicVCFEventCard(c echo.Context) error {
+	req := new(card.VCFReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+	req.SetClient(c.RealIP(), c.Request().UserAgent())
+	req.SetBaseURL(c.Scheme() + "://" + c.Request().Host)
+
+	vcf, err := s.card.GetPublicVCFEventCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "", vcf)
+}
+
+func (s *Server) getPublicQREventCard(c echo.Context) error {
+	req := new(card.QRReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	qr, err := s.card.GetPublicQREventCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	// Unlike card QR codes these are not cached for long: the event card
+	// expires.
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.Blob(http.StatusOK, qr.ContentType, qr.Data)
+}
+
+func (s *Server) getPublicQRBusinessCard(c echo.Context) error {
+	req := new(card.QRReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	qr, err := s.card.GetPublicQRBusinessCard(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	// The key is derived from the vCard hash, so it changes whenever the
+	// card is published again.
+	etag := fmt.Sprintf("%q", qr.Key)
+	res := c.Response()
+	res.Header().Set("ETag", etag)
+	res.Header().Set("Cache-Control", "public, max-age=86400")
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, qr.ContentType, qr.Data)
+}
+
+func (s *Server) suggestTransliteration(c echo.Context) error {
+	req := new(translit.SuggestReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	ctx := c.Request().Context()
+	suggestion, err := s.translit.SuggestTransliteration(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "transliteration", suggestion)
+}
+
+func (s *Server) listTransliterationOverrides(c echo.Context) error {
+	ctx := c.Request().Context()
+	overrides, err := s.translit.ListOverrides(ctx)
+	if err != nil {
+		return err
+	}
+
+	return envelope.Page(c, http.StatusOK, overrides, overrides.Overrides, "", nil)
+}
+
+func (s *Server) saveTransliterationOverride(c echo.Context) error {
+	req := new(translit.OverrideReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	ctx := c.Request().Context()
+	override, err := s.translit.SaveOverride(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "override", override)
+}
+
+func (s *Server) deleteTransliterationOverride(c echo.Context) error {
+	req := new(translit.DeleteOverrideReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	ctx := c.Request().Context()
+	if err := s.translit.DeleteOverride(ctx, req); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) verifyAuditLog(c echo.Context) error {
+	ctx := c.Request().Context()
+	verification, err := s.audit.VerifyAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "verification", verification)
+}
+
+func (s *Server) listJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobs, err := s.scheduler.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "jobs", jobs)
+}
+
+func (s *Server) listExportDeliveries(c echo.Context) error {
+	req := new(export.DeliveryQuery)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	res, err := s.export.ListDeliveries(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, res, res.Deliveries, res.NextPageToken, nil)
+}
+
+func (s *Server) listWebhooks(c echo.Context) error {
+	res, err := s.webhook.ListWebhooks(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, res, res.Webhooks, "", nil)
+}
+
+func (s *Server) createWebhook(c echo.Context) error {
+	req := new(webhook.WebhookReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	w, err := s.webhook.CreateWebhook(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "webhook", w)
+}
+
+func (s *Server) deleteWebhook(c echo.Context) error {
+	if err := s.webhook.DeleteWebhook(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) listTemplates(c echo.Context) error {
+	res, err := s.templates.ListTemplates(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, res, res.Templates, "", nil)
+}
+
+func (s *Server) createTemplate(c echo.Context) error {
+	req := new(template.TemplateReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	t, err := s.templates.CreateTemplate(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "template", t)
+}
+
+func (s *Server) getTemplate(c echo.Context) error {
+	t, err := s.templates.GetTemplate(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "template", t)
+}
+
+func (s *Server) updateTemplate(c echo.Context) error {
+	req := new(template.TemplateReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	t, err := s.templates.UpdateTemplate(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "template", t)
+}
+
+func (s *Server) deleteTemplate(c echo.Context) error {
+	if err := s.templates.DeleteTemplate(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) listAddresses(c echo.Context) error {
+	res, err := s.addresses.ListAddresses(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return envelope.Page(c, http.StatusOK, res, res.Addresses, "", nil)
+}
+
+func (s *Server) getAddress(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	a, err := s.addresses.GetAddress(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "address", a)
+}
+
+func (s *Server) saveAddress(c echo.Context) error {
+	if _, err := strconv.ParseInt(c.Param("id"), 10, 64); err != nil {
+		return badParam()
+	}
+
+	req := new(address.AddressReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	a, err := s.addresses.SaveAddress(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+	return envelope.JSON(c, http.StatusOK, "address", a)
+}
+
+func (s *Server) deleteAddress(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	if err := s.addresses.DeleteAddress(c.Request().Context(), id); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+func (s *Server) triggerJob(c echo.Context) error {
+	req := new(scheduler.TriggerJobReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	ctx := c.Request().Context()
+	job, err := s.scheduler.TriggerJob(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusAccepted, "job", job)
+}
+
+func (s *Server) getDiagnostics(c echo.Context) error {
+	report, err := s.diag.Run(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "diagnostics", report)
+}
+
+type readyResponse struct {
+	Ready bool `json:"ready"`
+}
+
+type drainResponse struct {
+	Drain *drain.State `json:"drain"`
+}
+
+func (s *Server) ready(c echo.Context) error {
+	if !s.drainer.Ready() {
+		return c.JSON(http.StatusServiceUnavailable, &readyResponse{Ready: false})
+	}
+
+	return c.JSON(http.StatusOK, &readyResponse{Ready: true})
+}
+
+func (s *Server) drainState(c echo.Context) error {
+	return c.JSON(http.StatusOK, &drainResponse{Drain: s.drainer.State()})
+}
+
+func (s *Server) drain(c echo.Context) error {
+	ctx := c.Request().Context()
+	return c.JSON(http.StatusOK, &drainResponse{Drain: s.drainer.Drain(ctx)})
+}
+
+func (s *Server) rotateTokenKeys(c echo.Context) error {
+	keys, err := s.auth.RotateKeys(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (s *Server) revokeUserSessions(c echo.Context) error {
+	res, err := s.auth.RevokeUserSessions(c.Request().Context(), c.Param("username"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+type migrationResponse struct {
+	Version uint64 `json:"version"`
+	Name    string `json:"name"`
+}
+
+type migrationStatusResponse struct {
+	Current uint64               `json:"current"`
+	Latest  uint64               `json:"latest"`
+	Dirty   bool                 `json:"dirty"`
+	Pending []*migrationResponse `json:"pending"`
+}
+
+func (s *Server) migrationStatus(c echo.Context) error {
+	st, err := s.migrator.Status(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	res := &migrationStatusResponse{
+		Current: st.Current,
+		Latest:  st.Latest,
+		Dirty:   st.Dirty,
+		Pending: make([]*migrationResponse, 0, len(st.Pending)),
+	}
+	for _, m := range st.Pending {
+		res.Pending = append(res.Pending, &migrationResponse{Version: m.Version, Name: m.Name})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// applyMigrations applies the pending migrations shipped with the binary,
+// and responds with the resulting status.
+func (s *Server) applyMigrations(c echo.Context) error {
+	err := s.migrator.Up(c.Request().Context())
+	if errors.Is(err, migrate.ErrDirty) || errors.Is(err, migrate.ErrAhead) {
+		return i18n.Error(codes.FailedPrecondition, i18n.SchemaNotCurrent)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.migrationStatus(c)
+}
+
+// saveEmployeePhoto receives an employee's directory photo from the
+// directory sync as the raw request body.
+func (s *Server) saveEmployeePhoto(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return badParam()
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPhotoBody))
+	if err != nil {
+		return badParam()
+	}
+
+	photo, err := s.employee.SavePhoto(c.Request().Context(), id, data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, photo)
+}
+
+// maxPhotoBody bounds the photo body read; larger photos are rejected by
+// the employee and card services.
+const maxPhotoBody = 1 << 20
+
+func (s *Server) registerDevice(c echo.Context) error {
+	req := new(push.DeviceReq)
+	if err := c.Bind(req); err != nil {
+		return badJSON()
+	}
+
+	device, err := s.push.RegisterDevice(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, "device", device)
+}
+
+func (s *Server) unregisterDevice(c echo.Context) error {
+	req := new(push.DeviceReq)
+	if err := c.Bind(req); err != nil {
+		return badParam()
+	}
+
+	if err := s.push.UnregisterDevice(c.Request().Context(), req.Token); err != nil {
+		return err
+	}
+
+	return envelope.Message(c, http.StatusOK, &emptypb.Empty{}, &emptypb.Empty{})
 }
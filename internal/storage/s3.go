@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// S3Config is an S3 or S3-compatible bucket objects are stored in.
+type S3Config struct {
+	Bucket string
+	Region string
+
+	// Prefix is prepended to every object key, e.g. contactqr/assets.
+	Prefix string
+
+	// Endpoint is the server of an S3-compatible store such as MinIO,
+	// e.g. https://minio.internal:9000. Empty addresses AWS itself.
+	Endpoint string
+
+	// Credentials sign the requests.
+	Credentials aws.Credentials
+}
+
+// objectTimeout bounds a Put or Get. Uploads are bounded by their context
+// only, as they may be large.
+const objectTimeout = 30 * time.Second
+
+// S3 stores objects in an S3 or S3-compatible bucket. It is the one S3
+// client of the service, also used by the exports.
+type S3 struct {
+	endpoint string
+	prefix   string
+	region   string
+	creds    aws.Credentials
+	signer   *v4.Signer
+	client   *http.Client
+}
+
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is empty")
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("region is empty")
+	}
+	if cfg.Credentials.AccessKeyID == "" || cfg.Credentials.SecretAccessKey == "" {
+		return nil, errors.New("credentials are empty")
+	}
+
+	// Compatible stores are addressed path-style.
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	if cfg.Endpoint != "" {
+		endpoint = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	}
+
+	return &S3{
+		endpoint: endpoint,
+		prefix:   strings.Trim(cfg.Prefix, "/"),
+		region:   cfg.Region,
+		creds:    cfg.Credentials,
+		signer:   v4.NewSigner(),
+		client:   &http.Client{},
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, objectTimeout)
+	defer cancel()
+
+	sum := sha256.Sum256(data)
+	req, err := s.request(ctx, http.MethodPut, key, bytes.NewReader(data), int64(len(data)), hex.EncodeToString(sum[:]))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType(key))
+
+	res, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+// Upload stores the size bytes read from r as the object key. sum is
+// their SHA-256 in hex; S3 rejects the object if it does not match.
+func (s *S3) Upload(ctx context.Context, key string, r io.Reader, size int64, sum string) error {
+	digest, err := hex.DecodeString(sum)
+	if err != nil || len(digest) != sha256.Size {
+		return fmt.Errorf("invalid checksum %q", sum)
+	}
+
+	req, err := s.request(ctx, http.MethodPut, key, io.NopCloser(r), size, sum)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(digest))
+
+	res, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (*Object, error) {
+	ctx, cancel := context.WithTimeout(ctx, objectTimeout)
+	defer cancel()
+
+	empty := sha256.Sum256(nil)
+	req, err := s.request(ctx, http.MethodGet, key, http.NoBody, 0, hex.EncodeToString(empty[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	modTime, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+
+	return &Object{
+		Key:         key,
+		ContentType: res.Header.Get("Content-Type"),
+		Data:        data,
+		ModTime:     modTime,
+	}, nil
+}
+
+// request builds a signed request for the object key with the size bytes
+// of body, whose SHA-256 in hex is payloadHash.
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader, size int64, payloadHash string) (*http.Request, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return nil, fmt.Errorf("invalid object key %q", key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+"/"+path.Join(s.prefix, key), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if err := s.signer.SignHTTP(ctx, s.creds, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	return req, nil
+}
+
+// do sends req and turns a non-2xx response into an error, 404 into
+// ErrObjectNotFound.
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return res, nil
+}
+
+// contentType returns the media type of an object by its key's extension.
+func contentType(key string) string {
+	if t := mime.TypeByExtension(path.Ext(key)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage persists generated assets such as QR images, and uploaded card
+// photos.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (*Object, error)
+}
+
+type Object struct {
+	Key         string
+	ContentType string
+	Data        []byte
+	ModTime     time.Time
+}
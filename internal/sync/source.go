@@ -0,0 +1,245 @@
+package sync
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxSize bounds what is read of a CSV file or an endpoint's response.
+const maxSize = 32 << 20
+
+// Source is where the HR records come from.
+type Source interface {
+	// Records returns the records waiting to be imported, none if there
+	// are none.
+	Records(ctx context.Context) ([]*Record, error)
+
+	// Done is called once the records last returned are imported.
+	Done(ctx context.Context) error
+
+	// String describes the source without its credentials.
+	String() string
+}
+
+// ParseSource returns the source raw names:
+//
+//	file:///var/lib/contactqr/hr
+//	https://hr.internal/api/employees
+//
+// A folder is a drop folder of CSV files, imported in name order and then
+// moved into its processed subfolder. An endpoint is fetched with GET and
+// responds with a JSON array of records, sent with token as bearer token
+// when it is not empty.
+func ParseSource(raw, token string) (Source, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.New("source is not a URL")
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, errors.New("file source has no folder")
+		}
+		return &dirSource{dir: u.Path}, nil
+
+	case "http", "https":
+		return &httpSource{
+			u:      u,
+			token:  token,
+			client: &http.Client{Timeout: time.Minute},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported source scheme %q", u.Scheme)
+}
+
+// dirSource reads the CSV files HR drops in dir. Their first row names the
+// columns, in any order:
+//
+//	code,first_name,last_name,first_name_lo,last_name_lo,email,company,department,position,manager_code
+//
+// HR should write a file elsewhere and move it in, so that a half written
+// file is never read.
+type dirSource struct {
+	dir string
+
+	// files are the files last read.
+	files []string
+}
+
+func (d *dirSource) Records(ctx context.Context) ([]*Record, error) {
+	files, err := filepath.Glob(filepath.Join(d.dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	records := make([]*Record, 0)
+	for _, name := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rs, err := readCSVFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(name), err)
+		}
+		records = append(records, rs...)
+	}
+	d.files = files
+
+	return records, nil
+}
+
+func (d *dirSource) Done(_ context.Context) error {
+	processed := filepath.Join(d.dir, "processed")
+	if err := os.MkdirAll(processed, 0o755); err != nil {
+		return err
+	}
+
+	for _, name := range d.files {
+		if err := os.Rename(name, filepath.Join(processed, filepath.Base(name))); err != nil {
+			return err
+		}
+	}
+	d.files = nil
+
+	return nil
+}
+
+func (d *dirSource) String() string {
+	return "file://" + d.dir
+}
+
+func readCSVFile(name string) ([]*Record, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readCSV(io.LimitReader(f, maxSize))
+}
+
+// csvColumns are the CSV columns of each Record field.
+var csvColumns = map[string]func(*Record) *string{
+	"code":          func(r *Record) *string { return &r.Code },
+	"first_name":    func(r *Record) *string { return &r.FirstName },
+	"last_name":     func(r *Record) *string { return &r.LastName },
+	"first_name_lo": func(r *Record) *string { return &r.FirstNameLo },
+	"last_name_lo":  func(r *Record) *string { return &r.LastNameLo },
+	"email":         func(r *Record) *string { return &r.Email },
+	"company":       func(r *Record) *string { return &r.Company },
+	"department":    func(r *Record) *string { return &r.Department },
+	"position":      func(r *Record) *string { return &r.Position },
+	"manager_code":  func(r *Record) *string { return &r.ManagerCode },
+}
+
+func readCSV(r io.Reader) ([]*Record, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]func(*Record) *string, len(header))
+	for i, col := range header {
+		// Excel saves UTF-8 with a byte order mark.
+		col = strings.TrimPrefix(col, "\ufeff")
+		header[i] = strings.ToLower(strings.TrimSpace(col))
+		fields[i] = csvColumns[header[i]]
+	}
+	for _, col := range []string{"code", "first_name", "last_name", "company", "department", "position"} {
+		if !slices.Contains(header, col) {
+			return nil, fmt.Errorf("column %s is missing", col)
+		}
+	}
+
+	records := make([]*Record, 0)
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := new(Record)
+		for i, v := range row {
+			if fields[i] != nil {
+				*fields[i](rec) = v
+			}
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// httpSource fetches the records from an HR endpoint. The endpoint returns
+// all employees, so there is nothing to mark done.
+type httpSource struct {
+	u      *url.URL
+	token  string
+	client *http.Client
+}
+
+func (h *httpSource) Records(ctx context.Context) ([]*Record, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "contactqr-sync")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("endpoint responded %s", res.Status)
+	}
+
+	var records []*Record
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxSize)).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (h *httpSource) Done(_ context.Context) error {
+	return nil
+}
+
+func (h *httpSource) String() string {
+	r := url.URL{
+		Scheme: h.u.Scheme,
+		Host:   h.u.Host,
+		Path:   h.u.Path,
+	}
+	return r.String()
+}
@@ -0,0 +1,284 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// employee is the part of a dbo.tb_employee row HR records set.
+type employee struct {
+	ID           int64
+	Code         string
+	FirstName    string
+	LastName     string
+	FirstNameLo  string
+	LastNameLo   string
+	Email        string
+	CompanyID    int64
+	DepartmentID int64
+	PositionID   int64
+	ManagerID    int64
+}
+
+func listEmployees(ctx context.Context, tx *sql.Tx) (map[string]*employee, error) {
+	q, args := sq.
+		Select(
+			"EID",
+			"EMPNO",
+			"COALESCE(nameeng, '')",
+			"COALESCE(surnameeng, '')",
+			"COALESCE(namelao, '')",
+			"COALESCE(surnamelao, '')",
+			"COALESCE(Emails, '')",
+			"COALESCE(bid, 0)",
+			"COALESCE(depid, 0)",
+			"COALESCE(poid, 0)",
+			"COALESCE(approveby, 0)",
+		).
+		From("dbo.tb_employee").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	employees := make(map[string]*employee)
+	for rows.Next() {
+		var e employee
+		if err := rows.Scan(
+			&e.ID,
+			&e.Code,
+			&e.FirstName,
+			&e.LastName,
+			&e.FirstNameLo,
+			&e.LastNameLo,
+			&e.Email,
+			&e.CompanyID,
+			&e.DepartmentID,
+			&e.PositionID,
+			&e.ManagerID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		// Padding is not a change.
+		for _, s := range []*string{&e.Code, &e.FirstName, &e.LastName, &e.FirstNameLo, &e.LastNameLo, &e.Email} {
+			*s = strings.TrimSpace(*s)
+		}
+		employees[e.Code] = &e
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return employees, nil
+}
+
+// createEmployee inserts e without a manager and returns its ID.
+func createEmployee(ctx context.Context, tx *sql.Tx, e *employee, now time.Time) (int64, error) {
+	q, args := sq.
+		Insert("dbo.tb_employee").
+		Columns(
+			"EMPNO",
+			"nameeng",
+			"surnameeng",
+			"namelao",
+			"surnamelao",
+			"Emails",
+			"bid",
+			"depid",
+			"poid",
+			"mgrid",
+			"createdate",
+		).
+		Values(
+			e.Code,
+			e.FirstName,
+			e.LastName,
+			e.FirstNameLo,
+			e.LastNameLo,
+			e.Email,
+			e.CompanyID,
+			e.DepartmentID,
+			e.PositionID,
+			0,
+			now,
+		).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to execute create employee: %w", err)
+	}
+
+	return id, nil
+}
+
+func updateEmployee(ctx context.Context, tx *sql.Tx, e *employee) error {
+	q, args := sq.
+		Update("dbo.tb_employee").
+		Set("nameeng", e.FirstName).
+		Set("surnameeng", e.LastName).
+		Set("namelao", e.FirstNameLo).
+		Set("surnamelao", e.LastNameLo).
+		Set("Emails", e.Email).
+		Set("bid", e.CompanyID).
+		Set("depid", e.DepartmentID).
+		Set("poid", e.PositionID).
+		Set("approveby", sql.NullInt64{Int64: e.ManagerID, Valid: e.ManagerID > 0}).
+		Set("mgrid", e.ManagerID).
+		Where(
+			sq.Eq{
+				"EID": e.ID,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute update employee: %w", err)
+	}
+
+	return nil
+}
+
+// orgTable is a table of named organisation units, keyed by their name
+// in lower case.
+type orgTable struct {
+	table  string
+	idCol  string
+	name   string
+	byName map[string]int64
+}
+
+type orgs struct {
+	companies   *orgTable
+	departments *orgTable
+	positions   *orgTable
+}
+
+func listOrgs(ctx context.Context, tx *sql.Tx) (*orgs, error) {
+	o := &orgs{
+		companies:   &orgTable{table: "dbo.tb_Branch", idCol: "BID", name: "BranchName"},
+		departments: &orgTable{table: "dbo.tb_department", idCol: "DEPID", name: "Departname"},
+		positions:   &orgTable{table: "dbo.tb_position", idCol: "POID", name: "Positionname"},
+	}
+	for _, t := range []*orgTable{o.companies, o.departments, o.positions} {
+		if err := t.load(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+func (t *orgTable) load(ctx context.Context, tx *sql.Tx) error {
+	q, args := sq.
+		Select(t.idCol, t.name).
+		From(t.table).
+		OrderBy(t.idCol).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	t.byName = make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var name sql.NullString
+		if err := rows.Scan(&id, &name); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		// Of units sharing a name, the oldest is used.
+		key := orgKey(name.String)
+		if _, ok := t.byName[key]; !ok {
+			t.byName[key] = id
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+// id returns the ID of the unit named name, creating it when there is none.
+func (t *orgTable) id(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+	key := orgKey(name)
+	if id, ok := t.byName[key]; ok {
+		return id, nil
+	}
+
+	q, args := sq.
+		Insert(t.table).
+		Columns(t.name).
+		Values(name).
+		Suffix("SELECT CAST(SCOPE_IDENTITY() AS BIGINT)").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to execute create %s: %w", t.table, err)
+	}
+	t.byName[key] = id
+
+	return id, nil
+}
+
+func orgKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// flagBatch bounds the employees flagged per statement, under the 2100
+// parameters SQL Server takes.
+const flagBatch = 1000
+
+// flagCards marks the published cards of the employees out of date and
+// returns how many were. Cards already marked keep the time they were.
+func flagCards(ctx context.Context, tx *sql.Tx, employeeIDs []int64, now time.Time) (int64, error) {
+	var flagged int64
+	for ids := range slices.Chunk(employeeIDs, flagBatch) {
+		q, args := sq.
+			Update("dbo.business_card").
+			Set("employee_changed_at", now).
+			Where(
+				sq.And{
+					sq.Eq{"employee_id": ids},
+					sq.Eq{"status": "PUBLISHED"},
+					sq.Eq{"employee_changed_at": nil},
+					sq.Eq{"deleted_at": nil},
+				},
+			).
+			PlaceholderFormat(sq.AtP).
+			MustSql()
+
+		res, err := tx.ExecContext(ctx, q, args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to execute flag cards: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		flagged += n
+	}
+
+	return flagged, nil
+}
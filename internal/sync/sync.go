@@ -0,0 +1,264 @@
+// Package sync imports the employees of the HR system into the employee
+// tables the service reads. HR hands over its records as CSV files dropped
+// in a folder or from a REST endpoint, see ParseSource; each import creates
+// the employees not seen before and updates the others, moving them to the
+// departments and positions HR now has them in. Departments, positions and
+// companies are matched by name and created when new.
+//
+// A published card keeps the name, email and organisation it was approved
+// with. When an import changes those of its owner, the card is flagged as
+// out of date until it is replaced.
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Record is an employee as HR has them. Code is the employee number, which
+// employees are matched by.
+type Record struct {
+	Code        string `json:"code"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	FirstNameLo string `json:"firstNameLo"`
+	LastNameLo  string `json:"lastNameLo"`
+	Email       string `json:"email"`
+	Company     string `json:"company"`
+	Department  string `json:"department"`
+	Position    string `json:"position"`
+
+	// ManagerCode is the employee number of the manager approving the
+	// employee's cards, empty if they have none.
+	ManagerCode string `json:"managerCode"`
+}
+
+// problem returns why the record cannot be imported, empty if it can.
+func (r *Record) problem() string {
+	switch {
+	case r.Code == "":
+		return "code is empty"
+	case r.FirstName == "", r.LastName == "":
+		return "name is empty"
+	case r.Company == "":
+		return "company is empty"
+	case r.Department == "":
+		return "department is empty"
+	case r.Position == "":
+		return "position is empty"
+	case r.ManagerCode == r.Code:
+		return "employee is their own manager"
+	}
+	return ""
+}
+
+func (r *Record) trim() {
+	for _, s := range []*string{
+		&r.Code,
+		&r.FirstName,
+		&r.LastName,
+		&r.FirstNameLo,
+		&r.LastNameLo,
+		&r.Email,
+		&r.Company,
+		&r.Department,
+		&r.Position,
+		&r.ManagerCode,
+	} {
+		*s = strings.TrimSpace(*s)
+	}
+}
+
+type Importer struct {
+	db   *sql.DB
+	src  Source
+	zlog *zap.Logger
+}
+
+func NewImporter(_ context.Context, db *sql.DB, src Source, zlog *zap.Logger) (*Importer, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if src == nil {
+		return nil, errors.New("source is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Importer{
+		db:   db,
+		src:  src,
+		zlog: zlog,
+	}, nil
+}
+
+// Import reads the records waiting at the source and imports them in one
+// transaction. It runs as a scheduled job.
+func (im *Importer) Import(ctx context.Context) error {
+	zlog := reqid.Logger(ctx, im.zlog).With(
+		zap.String("method", "Import"),
+		zap.Stringer("source", im.src),
+	)
+
+	records, err := im.src.Records(ctx)
+	if err != nil {
+		zlog.Error("failed to read records", zap.Error(err))
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	// A later record of the same employee replaces an earlier one.
+	byCode := make(map[string]*Record, len(records))
+	codes := make([]string, 0, len(records))
+	skipped := 0
+	for i, r := range records {
+		r.trim()
+		if p := r.problem(); p != "" {
+			zlog.Warn("skipped record", zap.Int("record", i+1), zap.String("code", r.Code), zap.String("problem", p))
+			skipped++
+			continue
+		}
+		if _, ok := byCode[r.Code]; !ok {
+			codes = append(codes, r.Code)
+		}
+		byCode[r.Code] = r
+	}
+
+	var res result
+	err = utils.WithTx(ctx, im.db, func(ctx context.Context, tx *sql.Tx) error {
+		res = result{}
+		return im.apply(ctx, tx, codes, byCode, &res)
+	})
+	if err != nil {
+		zlog.Error("failed to import records", zap.Error(err))
+		return err
+	}
+
+	if err := im.src.Done(ctx); err != nil {
+		zlog.Error("failed to mark records imported", zap.Error(err))
+		return err
+	}
+
+	zlog.Info("imported employees",
+		zap.Int("records", len(records)),
+		zap.Int("skipped", skipped),
+		zap.Int("created", res.created),
+		zap.Int("updated", res.updated),
+		zap.Int64("flagged_cards", res.flagged),
+	)
+	return nil
+}
+
+type result struct {
+	created int
+	updated int
+	flagged int64
+}
+
+// apply brings the employees of codes in line with their records.
+func (im *Importer) apply(ctx context.Context, tx *sql.Tx, codes []string, records map[string]*Record, res *result) error {
+	orgs, err := listOrgs(ctx, tx)
+	if err != nil {
+		return err
+	}
+	employees, err := listEmployees(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	// Employees are created first so that any of them can be the manager
+	// of another.
+	wanted := make(map[string]*employee, len(codes))
+	created := make(map[string]bool)
+	for _, code := range codes {
+		r := records[code]
+		e := &employee{
+			Code:        r.Code,
+			FirstName:   r.FirstName,
+			LastName:    r.LastName,
+			FirstNameLo: r.FirstNameLo,
+			LastNameLo:  r.LastNameLo,
+			Email:       r.Email,
+		}
+		if e.CompanyID, err = orgs.companies.id(ctx, tx, r.Company); err != nil {
+			return err
+		}
+		if e.DepartmentID, err = orgs.departments.id(ctx, tx, r.Department); err != nil {
+			return err
+		}
+		if e.PositionID, err = orgs.positions.id(ctx, tx, r.Position); err != nil {
+			return err
+		}
+		wanted[code] = e
+
+		if _, ok := employees[code]; ok {
+			continue
+		}
+		if e.ID, err = createEmployee(ctx, tx, e, now); err != nil {
+			return err
+		}
+		// Their manager is set below, once every employee exists.
+		c := *e
+		employees[code] = &c
+		created[code] = true
+		res.created++
+	}
+
+	changed := make([]int64, 0)
+	for _, code := range codes {
+		e, current := wanted[code], employees[code]
+		e.ID = current.ID
+
+		r := records[code]
+		e.ManagerID = current.ManagerID
+		if r.ManagerCode == "" {
+			e.ManagerID = 0
+		} else if m, ok := employees[r.ManagerCode]; ok {
+			e.ManagerID = m.ID
+		} else {
+			reqid.Logger(ctx, im.zlog).Warn("manager not found, manager kept", zap.String("code", code), zap.String("manager_code", r.ManagerCode))
+		}
+
+		if *e == *current {
+			continue
+		}
+		if err := updateEmployee(ctx, tx, e); err != nil {
+			return err
+		}
+		if created[code] {
+			continue
+		}
+		res.updated++
+		if !sameCard(current, e) {
+			changed = append(changed, e.ID)
+		}
+	}
+
+	res.flagged, err = flagCards(ctx, tx, changed, now)
+	return err
+}
+
+// sameCard reports whether a card shows the two employees alike: the same
+// names, email and organisation.
+func sameCard(a, b *employee) bool {
+	return a.FirstName == b.FirstName &&
+		a.LastName == b.LastName &&
+		a.FirstNameLo == b.FirstNameLo &&
+		a.LastNameLo == b.LastNameLo &&
+		a.Email == b.Email &&
+		a.CompanyID == b.CompanyID &&
+		a.DepartmentID == b.DepartmentID &&
+		a.PositionID == b.PositionID
+}
@@ -0,0 +1,177 @@
+package template
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/10664kls/contactqr/internal/pager"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// createTemplate creates the template unless its company has one, and
+// reports whether it did.
+func createTemplate(ctx context.Context, db *sql.DB, in *Template) (bool, error) {
+	q := `
+INSERT INTO dbo.card_template (id, company_id, name, logo_url, primary_color, accent_color, layout, created_by, created_at, updated_by, updated_at)
+SELECT @p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p8, @p9
+WHERE NOT EXISTS (
+  SELECT 1 FROM dbo.card_template WITH (UPDLOCK, HOLDLOCK) WHERE company_id = @p2
+);`
+
+	res, err := db.ExecContext(ctx, q,
+		in.ID,
+		in.CompanyID,
+		in.Name,
+		in.LogoURL,
+		in.PrimaryColor,
+		in.AccentColor,
+		in.Layout,
+		in.CreatedBy,
+		pager.DateTime(in.CreatedAt),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute create template: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func selectTemplates() sq.SelectBuilder {
+	return sq.
+		Select(
+			"id",
+			"company_id",
+			"name",
+			"logo_url",
+			"RTRIM(primary_color)",
+			"RTRIM(accent_color)",
+			"layout",
+			"created_by",
+			"created_at",
+			"updated_by",
+			"updated_at",
+		).
+		From("dbo.card_template")
+}
+
+func scanTemplate(row interface{ Scan(...any) error }) (*Template, error) {
+	var t Template
+	err := row.Scan(
+		&t.ID,
+		&t.CompanyID,
+		&t.Name,
+		&t.LogoURL,
+		&t.PrimaryColor,
+		&t.AccentColor,
+		&t.Layout,
+		&t.CreatedBy,
+		&t.CreatedAt,
+		&t.UpdatedBy,
+		&t.UpdatedAt,
+	)
+	return &t, err
+}
+
+func listTemplates(ctx context.Context, db *sql.DB) ([]*Template, error) {
+	q, args := selectTemplates().
+		OrderBy("company_id").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*Template, 0)
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return templates, nil
+}
+
+func getTemplate(ctx context.Context, db *sql.DB, id string) (*Template, error) {
+	q, args := selectTemplates().
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	t, err := scanTemplate(db.QueryRowContext(ctx, q, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return t, nil
+}
+
+func updateTemplate(ctx context.Context, db *sql.DB, in *Template) error {
+	q, args := sq.
+		Update("dbo.card_template").
+		Set("name", in.Name).
+		Set("logo_url", in.LogoURL).
+		Set("primary_color", in.PrimaryColor).
+		Set("accent_color", in.AccentColor).
+		Set("layout", in.Layout).
+		Set("updated_by", in.UpdatedBy).
+		Set("updated_at", pager.DateTime(in.UpdatedAt)).
+		Where(sq.Eq{"id": in.ID}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func deleteTemplate(ctx context.Context, db *sql.DB, id string) error {
+	q, args := sq.
+		Delete("dbo.card_template").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrTemplateNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,385 @@
+// Package template keeps the card design of each company: the logo and
+// colors its card pages are shown with and the layout of its landing
+// pages. HR designs them; cards of companies without one use Default.
+//
+// Templates are few and read on every card shown, so they are served from
+// memory, reloaded after every change and every interval of RunReload,
+// which picks up the changes made on other replicas.
+package template
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+// Template is the card design of a company.
+type Template struct {
+	ID           string    `json:"id"`
+	CompanyID    int64     `json:"companyId"`
+	Name         string    `json:"name"`
+	LogoURL      string    `json:"logoUrl"`
+	PrimaryColor string    `json:"primaryColor"`
+	AccentColor  string    `json:"accentColor"`
+	Layout       string    `json:"layout"`
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedBy    string    `json:"updatedBy"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Default is the design of the cards of companies without a template, the
+// built-in card page and the default landing layout.
+var Default = &Template{
+	Name:         "Default",
+	PrimaryColor: "#1a3c8c",
+	AccentColor:  "#f2f4f8",
+	Layout:       "default",
+}
+
+// Design is a template as cards carry it, without who edited it.
+type Design struct {
+	ID           string `json:"id,omitempty"` // Empty for Default.
+	CompanyID    int64  `json:"companyId,omitempty"`
+	Name         string `json:"name"`
+	LogoURL      string `json:"logoUrl"`
+	PrimaryColor string `json:"primaryColor"`
+	AccentColor  string `json:"accentColor"`
+	Layout       string `json:"layout"`
+}
+
+func (t *Template) Design() *Design {
+	return &Design{
+		ID:           t.ID,
+		CompanyID:    t.CompanyID,
+		Name:         t.Name,
+		LogoURL:      t.LogoURL,
+		PrimaryColor: t.PrimaryColor,
+		AccentColor:  t.AccentColor,
+		Layout:       t.Layout,
+	}
+}
+
+func (d *Design) Proto() *contactqrPb.CardTemplate {
+	return &contactqrPb.CardTemplate{
+		Id:           d.ID,
+		CompanyId:    d.CompanyID,
+		Name:         d.Name,
+		LogoUrl:      d.LogoURL,
+		PrimaryColor: d.PrimaryColor,
+		AccentColor:  d.AccentColor,
+		Layout:       d.Layout,
+	}
+}
+
+type Service struct {
+	db   *sql.DB
+	zlog *zap.Logger
+
+	// byCompany holds the templates by company ID.
+	byCompany atomic.Pointer[map[int64]*Template]
+}
+
+// NewService loads the templates, which must succeed for cards to be shown
+// with their design.
+func NewService(ctx context.Context, db *sql.DB, zlog *zap.Logger) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	s := &Service{
+		db:   db,
+		zlog: zlog,
+	}
+	if err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// For returns the template of the company's cards, Default if it has none.
+func (s *Service) For(companyID int64) *Template {
+	if t, ok := (*s.byCompany.Load())[companyID]; ok {
+		return t
+	}
+	return Default
+}
+
+// Reload reads the templates again.
+func (s *Service) Reload(ctx context.Context) error {
+	templates, err := listTemplates(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	byCompany := make(map[int64]*Template, len(templates))
+	for _, t := range templates {
+		byCompany[t.CompanyID] = t
+	}
+	s.byCompany.Store(&byCompany)
+
+	return nil
+}
+
+// RunReload reloads the templates every interval until ctx is done.
+func (s *Service) RunReload(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.Reload(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.zlog.Error("failed to reload templates", zap.Error(err))
+		}
+	}
+}
+
+// reload reads the templates after a change. A failure leaves the old ones
+// until RunReload's next one.
+func (s *Service) reload(ctx context.Context, zlog *zap.Logger) {
+	if err := s.Reload(ctx); err != nil {
+		zlog.Warn("failed to reload templates", zap.Error(err))
+	}
+}
+
+type TemplateReq struct {
+	ID           string `json:"-" param:"id"`
+	CompanyID    int64  `json:"companyId"`
+	Name         string `json:"name"`
+	LogoURL      string `json:"logoUrl"`
+	PrimaryColor string `json:"primaryColor"`
+	AccentColor  string `json:"accentColor"`
+	Layout       string `json:"layout"`
+}
+
+func (r *TemplateReq) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.LogoURL = strings.TrimSpace(r.LogoURL)
+	r.PrimaryColor = strings.ToLower(strings.TrimSpace(r.PrimaryColor))
+	r.AccentColor = strings.ToLower(strings.TrimSpace(r.AccentColor))
+	r.Layout = strings.ToLower(strings.TrimSpace(r.Layout))
+	if r.Layout == "" {
+		r.Layout = Default.Layout
+	}
+
+	violations, err := validate.Violations(&contactqrPb.CardTemplateRequest{
+		CompanyId:    r.CompanyID,
+		Name:         r.Name,
+		LogoUrl:      r.LogoURL,
+		PrimaryColor: r.PrimaryColor,
+		AccentColor:  r.AccentColor,
+		Layout:       r.Layout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidTemplate).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreateTemplate designs the cards of a company, which may have one
+// template. It is for HR only.
+func (s *Service) CreateTemplate(ctx context.Context, in *TemplateReq) (*Template, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateTemplate"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTemplates) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TemplatesForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	t := &Template{
+		ID:           uuid.NewString(),
+		CompanyID:    in.CompanyID,
+		Name:         in.Name,
+		LogoURL:      in.LogoURL,
+		PrimaryColor: in.PrimaryColor,
+		AccentColor:  in.AccentColor,
+		Layout:       in.Layout,
+		CreatedBy:    claims.Code,
+		CreatedAt:    now,
+		UpdatedBy:    claims.Code,
+		UpdatedAt:    now,
+	}
+	created, err := createTemplate(ctx, s.db, t)
+	if err != nil {
+		zlog.Error("failed to create template", zap.Error(err))
+		return nil, err
+	}
+	if !created {
+		return nil, i18n.Error(codes.AlreadyExists, i18n.TemplateExists, "companyId", strconv.FormatInt(in.CompanyID, 10))
+	}
+	s.reload(ctx, zlog)
+
+	return t, nil
+}
+
+type ListTemplatesResult struct {
+	Templates []*Template `json:"templates"`
+}
+
+// ListTemplates lists the templates by company. It is for HR only.
+func (s *Service) ListTemplates(ctx context.Context) (*ListTemplatesResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListTemplates"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTemplates) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TemplatesForbidden)
+	}
+
+	templates, err := listTemplates(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list templates", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListTemplatesResult{Templates: templates}, nil
+}
+
+// GetTemplate returns a template. It is for HR only.
+func (s *Service) GetTemplate(ctx context.Context, id string) (*Template, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "GetTemplate"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTemplates) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TemplatesForbidden)
+	}
+
+	t, err := getTemplate(ctx, s.db, id)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.TemplateNotFound, "id", id)
+	}
+	if err != nil {
+		zlog.Error("failed to get template", zap.Error(err))
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// UpdateTemplate redesigns the cards of a template's company. The company
+// of a template does not change. It is for HR only.
+func (s *Service) UpdateTemplate(ctx context.Context, in *TemplateReq) (*Template, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "UpdateTemplate"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTemplates) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TemplatesForbidden)
+	}
+
+	t, err := getTemplate(ctx, s.db, in.ID)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.TemplateNotFound, "id", in.ID)
+	}
+	if err != nil {
+		zlog.Error("failed to get template", zap.Error(err))
+		return nil, err
+	}
+
+	in.CompanyID = t.CompanyID
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	t.Name = in.Name
+	t.LogoURL = in.LogoURL
+	t.PrimaryColor = in.PrimaryColor
+	t.AccentColor = in.AccentColor
+	t.Layout = in.Layout
+	t.UpdatedBy = claims.Code
+	t.UpdatedAt = time.Now()
+
+	err = updateTemplate(ctx, s.db, t)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return nil, i18n.Error(codes.NotFound, i18n.TemplateNotFound, "id", in.ID)
+	}
+	if err != nil {
+		zlog.Error("failed to update template", zap.Error(err))
+		return nil, err
+	}
+	s.reload(ctx, zlog)
+
+	return t, nil
+}
+
+// DeleteTemplate returns the cards of a template's company to the default
+// design. It is for HR only.
+func (s *Service) DeleteTemplate(ctx context.Context, id string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "DeleteTemplate"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTemplates) {
+		return i18n.Error(codes.PermissionDenied, i18n.TemplatesForbidden)
+	}
+
+	err := deleteTemplate(ctx, s.db, id)
+	if errors.Is(err, ErrTemplateNotFound) {
+		return i18n.Error(codes.NotFound, i18n.TemplateNotFound, "id", id)
+	}
+	if err != nil {
+		zlog.Error("failed to delete template", zap.Error(err))
+		return err
+	}
+	s.reload(ctx, zlog)
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package tracing records OpenTelemetry spans of HTTP requests, service
+// methods and SQL queries and exports them over OTLP, so the latency of a
+// request can be attributed to the database, vCard generation or the
+// handler itself.
+//
+// Until Setup is called spans are not recorded and cost next to nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// name is the instrumentation scope of the spans of this service.
+const name = "github.com/10664kls/contactqr"
+
+// Options are the settings of the span exporter.
+type Options struct {
+	// Endpoint is the OTLP/HTTP URL spans are sent to, e.g.
+	// "http://otel-collector:4318/v1/traces".
+	Endpoint string
+
+	// ServiceName names the service in the tracing backend.
+	ServiceName string
+
+	// SampleRatio is the share of traces started here that are recorded.
+	// Traces started by a caller follow the caller's decision.
+	SampleRatio float64
+}
+
+// Setup exports spans as opts say and makes trace context headers of
+// incoming requests continue the caller's trace. The returned function
+// flushes the spans not yet exported and stops the exporter.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create span exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
+
+// Start starts a span of the operation named spanName as a child of the
+// span in ctx, e.g.
+//
+//	ctx, span := tracing.Start(ctx, "card.listPage")
+//	defer span.End()
+func Start(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(name).Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// Fail marks span as failed with err.
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,365 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The rules below follow the romanization commonly used in Lao names on
+// passports and bank records (Somphone, Vilaysack, Keomany) rather than a
+// strict academic system. They give a starting point the user corrects;
+// names that keep coming out wrong belong in the override table.
+
+// laoInitials maps consonants at the start of a syllable.
+var laoInitials = map[rune]string{
+	'ກ': "k", 'ຂ': "kh", 'ຄ': "kh", 'ງ': "ng", 'ຈ': "ch", 'ສ': "s", 'ຊ': "x",
+	'ຍ': "ny", 'ດ': "d", 'ຕ': "t", 'ຖ': "th", 'ທ': "th", 'ນ': "n", 'ບ': "b",
+	'ປ': "p", 'ຜ': "ph", 'ຝ': "f", 'ພ': "ph", 'ຟ': "f", 'ມ': "m", 'ຢ': "y",
+	'ຣ': "r", 'ລ': "l", 'ວ': "v", 'ຫ': "h", 'ອ': "", 'ຮ': "h", 'ໜ': "n",
+	'ໝ': "m",
+}
+
+// laoFinals maps consonants closing a syllable.
+var laoFinals = map[rune]string{
+	'ກ': "k", 'ງ': "ng", 'ດ': "t", 'ຕ': "t", 'ສ': "t", 'ບ': "p", 'ປ': "p",
+	'ນ': "n", 'ລ': "n", 'ຣ': "n", 'ມ': "m", 'ຍ': "y", 'ວ': "o",
+}
+
+// laoVowels maps vowel signs written after or above the consonant.
+var laoVowels = map[rune]string{
+	'ະ': "a", 'ັ': "a", 'າ': "a", 'ຳ': "am", 'ິ': "i", 'ີ': "i", 'ຶ': "u",
+	'ື': "u", 'ຸ': "ou", 'ູ': "ou", 'ົ': "o", 'ໍ': "o", 'ຽ': "ia",
+}
+
+// laoPreVowels maps vowel signs written before the consonant they follow
+// in speech.
+var laoPreVowels = map[rune]string{
+	'ເ': "e", 'ແ': "ae", 'ໂ': "o", 'ໄ': "ai", 'ໃ': "ai",
+}
+
+func isLao(r rune) bool {
+	return r >= 0x0E80 && r <= 0x0EFF
+}
+
+func isToneMark(r rune) bool {
+	return (r >= 0x0EC8 && r <= 0x0ECB) || r == 0x0ECC
+}
+
+// sonorants are the consonants ຫ silently precedes to change their tone.
+var sonorants = map[rune]bool{
+	'ນ': true, 'ມ': true, 'ລ': true, 'ງ': true, 'ຍ': true, 'ວ': true, 'ຣ': true,
+}
+
+// romanize spells a Lao word in Latin letters.
+func romanize(word string) string {
+	rs := make([]rune, 0, len(word))
+	for _, r := range word {
+		if !isToneMark(r) {
+			rs = append(rs, r)
+		}
+	}
+
+	var b strings.Builder
+	var (
+		pre     rune // pre-posed vowel waiting for its consonant
+		initial bool // the syllable has its initial consonant
+		vowel   bool // the syllable has its vowel
+	)
+	next := func(i int) rune {
+		if i+1 < len(rs) {
+			return rs[i+1]
+		}
+		return 0
+	}
+
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+
+		switch {
+		case r >= '໐' && r <= '໙':
+			b.WriteRune('0' + r - '໐')
+			initial, vowel = false, false
+
+		case laoPreVowels[r] != "":
+			pre = r
+			initial, vowel = false, false
+
+		case laoVowels[r] != "":
+			v := laoVowels[r]
+			// ົວ and ັວ spell a single vowel.
+			if (r == 'ົ' || r == 'ັ') && next(i) == 'ວ' {
+				v = "oua"
+				i++
+			}
+			if r == 'ື' && next(i) == 'ອ' {
+				v = "ua"
+				i++
+			}
+			b.WriteString(v)
+			vowel = true
+
+		case r == 'ຼ':
+			b.WriteString("l")
+
+		case laoInitials[r] != "" || r == 'ອ':
+			switch {
+			case initial && !vowel && r == 'ອ':
+				// ອ after a consonant is the vowel o.
+				b.WriteString("o")
+				vowel = true
+				continue
+
+			case initial && !vowel && r == 'ວ':
+				// ◌ວາ as in ຄວາມ is the same vowel.
+				b.WriteString("oua")
+				if next(i) == 'າ' {
+					i++
+				}
+				vowel = true
+				continue
+
+			case initial && vowel && laoFinals[r] != "" && laoVowels[next(i)] == "" && next(i) != 'ຼ':
+				b.WriteString(laoFinals[r])
+				initial, vowel = false, false
+				continue
+			}
+
+			// ຫ is silent before a sonorant and makes ຫຼ an l.
+			switch {
+			case r == 'ຫ' && next(i) == 'ຼ':
+				b.WriteString("l")
+				i++
+			case r == 'ຫ' && sonorants[next(i)]:
+				i++
+				b.WriteString(laoInitials[rs[i]])
+			default:
+				b.WriteString(laoInitials[r])
+			}
+			initial, vowel = true, false
+
+			if pre != 0 {
+				b.WriteString(preVowel(pre, rs, &i))
+				pre = 0
+				vowel = true
+			}
+
+		default:
+			// ໆ, ຯ and other marks carry no sound of their own.
+		}
+	}
+
+	return capitalize(b.String())
+}
+
+// preVowel returns the vowel pre-posed vowel p forms with the signs after
+// the consonant at rs[*i], consuming them.
+func preVowel(p rune, rs []rune, i *int) string {
+	at := func(k int) rune {
+		if *i+k < len(rs) {
+			return rs[*i+k]
+		}
+		return 0
+	}
+
+	if p != 'ເ' {
+		return laoPreVowels[p]
+	}
+
+	switch {
+	case at(1) == 'ົ' && at(2) == 'າ':
+		*i += 2
+		return "ao"
+	case at(1) == 'ື' && at(2) == 'ອ':
+		*i += 2
+		return "ua"
+	case at(1) == 'າ' && at(2) == 'ະ':
+		*i += 2
+		return "o"
+	case at(1) == 'ີ' || at(1) == 'ິ':
+		*i++
+		return "oe"
+	case at(1) == 'ຍ':
+		*i++
+		return "ia"
+	case at(1) == 'ັ' || at(1) == 'ະ':
+		*i++
+		return "e"
+	}
+	return "e"
+}
+
+// latinInitials maps syllable-initial spellings, longest first.
+var latinInitials = []struct {
+	latin string
+	lao   string
+}{
+	{"kh", "ຄ"}, {"ng", "ງ"}, {"ny", "ຍ"}, {"ch", "ຈ"}, {"th", "ທ"}, {"ph", "ພ"},
+	{"k", "ກ"}, {"c", "ກ"}, {"g", "ກ"}, {"q", "ກ"}, {"j", "ຈ"}, {"s", "ສ"},
+	{"x", "ຊ"}, {"z", "ຊ"}, {"d", "ດ"}, {"t", "ຕ"}, {"n", "ນ"}, {"b", "ບ"},
+	{"p", "ປ"}, {"f", "ຟ"}, {"m", "ມ"}, {"y", "ຢ"}, {"r", "ຣ"}, {"l", "ລ"},
+	{"v", "ວ"}, {"w", "ວ"}, {"h", "ຫ"},
+}
+
+// latinVowels are the vowel spellings, longest first.
+var latinVowels = []string{
+	"oua", "ao", "ae", "ai", "ay", "am", "eo", "ia", "oe", "eu", "ue", "ua",
+	"ou", "a", "e", "i", "o", "u", "y",
+}
+
+// latinFinals maps syllable-final spellings, longest first.
+var latinFinals = []struct {
+	latin string
+	lao   string
+}{
+	{"ng", "ງ"}, {"nh", "ນ"}, {"ck", "ກ"}, {"n", "ນ"}, {"m", "ມ"}, {"k", "ກ"},
+	{"c", "ກ"}, {"t", "ດ"}, {"d", "ດ"}, {"s", "ດ"}, {"p", "ບ"}, {"b", "ບ"},
+	{"y", "ຍ"}, {"w", "ວ"}, {"l", "ນ"},
+}
+
+// isLatinVowel reports whether b spells a vowel. A y does too, as in Bounmy.
+func isLatinVowel(b byte) bool {
+	return strings.IndexByte("aeiouy", b) >= 0
+}
+
+// laoize spells a romanized Lao word in Lao script.
+func laoize(word string) string {
+	w := strings.ToLower(word)
+	// A trailing e after a consonant is silent, as in Somphone.
+	if n := len(w); n > 2 && w[n-1] == 'e' && !isLatinVowel(w[n-2]) {
+		w = w[:n-1]
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(w); {
+		if w[i] < 'a' || w[i] > 'z' {
+			b.WriteByte(w[i])
+			i++
+			continue
+		}
+
+		initial, n := latinInitial(w[i:])
+		i += n
+
+		vowel := ""
+		for _, v := range latinVowels {
+			if strings.HasPrefix(w[i:], v) {
+				vowel = v
+				i += len(v)
+				break
+			}
+		}
+		if vowel == "" {
+			// A consonant without a vowel, e.g. a stray letter.
+			b.WriteString(initial)
+			continue
+		}
+
+		final := ""
+		for _, f := range latinFinals {
+			// ph in Somphone starts the next syllable.
+			if _, n := latinInitial(w[i:]); n > 1 && i+n < len(w) && isLatinVowel(w[i+n]) {
+				break
+			}
+			if !strings.HasPrefix(w[i:], f.latin) {
+				continue
+			}
+			// The consonant starts the next syllable if a vowel follows.
+			j := i + len(f.latin)
+			if j < len(w) && isLatinVowel(w[j]) {
+				break
+			}
+			final = f.lao
+			i = j
+			break
+		}
+
+		b.WriteString(laoSyllable(initial, vowel, final))
+	}
+
+	return b.String()
+}
+
+// latinInitial returns the Lao consonant w starts with and the length of
+// its spelling, preferring the longest one followed by a vowel: the n of
+// Keomany is an initial, not the ny of Nyot. Words starting with a vowel
+// get ອ.
+func latinInitial(w string) (string, int) {
+	lao, n := "ອ", 0
+	for _, c := range latinInitials {
+		if !strings.HasPrefix(w, c.latin) {
+			continue
+		}
+		if len(c.latin) < len(w) && isLatinVowel(w[len(c.latin)]) {
+			return c.lao, len(c.latin)
+		}
+		if n == 0 {
+			lao, n = c.lao, len(c.latin)
+		}
+	}
+	return lao, n
+}
+
+// laoSyllable writes a syllable, placing the vowel around the initial.
+func laoSyllable(c, vowel, final string) string {
+	closed := final != ""
+
+	switch vowel {
+	case "a":
+		if closed {
+			return c + "ັ" + final
+		}
+		return c + "າ"
+	case "e":
+		return "ເ" + c + final
+	case "ae":
+		return "ແ" + c + final
+	case "o":
+		if final == "ຍ" {
+			return c + "ອຍ"
+		}
+		if closed {
+			return c + "ົ" + final
+		}
+		return "ໂ" + c
+	case "ai", "ay":
+		return "ໄ" + c + final
+	case "ao":
+		return c + "າວ"
+	case "eo":
+		return "ແ" + c + "ວ"
+	case "i", "y":
+		if closed {
+			return c + "ິ" + final
+		}
+		return c + "ີ"
+	case "ou", "u":
+		if closed {
+			return c + "ຸ" + final
+		}
+		return c + "ູ"
+	case "eu", "ue":
+		return c + "ື" + final
+	case "am":
+		return c + "ຳ" + final
+	case "ia":
+		if closed {
+			return c + "ຽ" + final
+		}
+		return "ເ" + c + "ຍ"
+	case "oe":
+		return "ເ" + c + "ີ" + final
+	case "oua", "ua":
+		if closed {
+			return c + "ວ" + final
+		}
+		return c + "ົວ"
+	}
+	return c + final
+}
+
+func capitalize(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package translit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func listOverrides(ctx context.Context, db *sql.DB) ([]*Override, error) {
+	q, args := sq.
+		Select(
+			"lao",
+			"latin",
+			"updated_by",
+			"updated_at",
+		).
+		From("dbo.transliteration_override").
+		OrderBy("lao").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := make([]*Override, 0)
+	for rows.Next() {
+		var o Override
+		if err := rows.Scan(
+			&o.Lao,
+			&o.Latin,
+			&o.UpdatedBy,
+			&o.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		overrides = append(overrides, &o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func saveOverride(ctx context.Context, db *sql.DB, in *Override) error {
+	q := `
+MERGE dbo.transliteration_override AS t
+USING (SELECT @p1 AS lao) AS s ON t.lao = s.lao
+WHEN MATCHED THEN
+  UPDATE SET latin = @p2, updated_by = @p3, updated_at = @p4
+WHEN NOT MATCHED THEN
+  INSERT (lao, latin, updated_by, updated_at) VALUES (@p1, @p2, @p3, @p4);`
+
+	if _, err := db.ExecContext(ctx, q, in.Lao, in.Latin, in.UpdatedBy, in.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to execute save override: %w", err)
+	}
+
+	return nil
+}
+
+func deleteOverride(ctx context.Context, db *sql.DB, lao string) error {
+	q, args := sq.
+		Delete("dbo.transliteration_override").
+		Where(
+			sq.Eq{
+				"lao": lao,
+			},
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrOverrideNotFound
+	}
+
+	return nil
+}
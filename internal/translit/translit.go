@@ -0,0 +1,299 @@
+// Package translit suggests how an employee's name is spelled in the other
+// script: a romanized spelling for a name typed in Lao, and the Lao spelling
+// for one typed in Latin letters. Suggestions come from spelling rules,
+// except for the words HR has recorded an override for.
+package translit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrOverrideNotFound = errors.New("override not found")
+
+// Script is the writing system of a text.
+type Script string
+
+const (
+	ScriptLao   Script = "lao"
+	ScriptLatin Script = "latin"
+)
+
+type Service struct {
+	db   *sql.DB
+	zlog *zap.Logger
+}
+
+func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Service{
+		db:   db,
+		zlog: zlog,
+	}, nil
+}
+
+type SuggestReq struct {
+	Text string `json:"text" query:"text"`
+}
+
+func (r *SuggestReq) Validate() error {
+	r.Text = strings.Join(strings.Fields(r.Text), " ")
+
+	violations, err := validate.Violations(&contactqrPb.SuggestTransliterationRequest{
+		Text: r.Text,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidTransliteration).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+type Suggestion struct {
+	Text       string  `json:"text"`
+	From       Script  `json:"from"`
+	To         Script  `json:"to"`
+	Suggestion string  `json:"suggestion"`
+	Words      []*Word `json:"words"`
+}
+
+type Word struct {
+	Text       string `json:"text"`
+	Suggestion string `json:"suggestion"`
+
+	// Overridden is set when the suggestion comes from an HR override
+	// rather than the spelling rules.
+	Overridden bool `json:"overridden"`
+}
+
+// SuggestTransliteration suggests how the name in the request is written in
+// the other script. Text containing any Lao letter is romanized; anything
+// else is written in Lao.
+func (s *Service) SuggestTransliteration(ctx context.Context, in *SuggestReq) (*Suggestion, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SuggestTransliteration"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	overrides, err := listOverrides(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list overrides", zap.Error(err))
+		return nil, err
+	}
+
+	from := scriptOf(in.Text)
+	out := &Suggestion{
+		Text:  in.Text,
+		From:  from,
+		To:    ScriptLatin,
+		Words: make([]*Word, 0),
+	}
+	if from == ScriptLatin {
+		out.To = ScriptLao
+	}
+
+	suggested := make([]string, 0)
+	for _, w := range strings.Fields(in.Text) {
+		word := &Word{Text: w}
+		if o := findOverride(overrides, from, w); o != nil {
+			word.Overridden = true
+			word.Suggestion = o.Latin
+			if from == ScriptLatin {
+				word.Suggestion = o.Lao
+			}
+		} else if from == ScriptLao {
+			word.Suggestion = romanize(w)
+		} else {
+			word.Suggestion = laoize(w)
+		}
+
+		out.Words = append(out.Words, word)
+		suggested = append(suggested, word.Suggestion)
+	}
+	out.Suggestion = strings.Join(suggested, " ")
+
+	return out, nil
+}
+
+func scriptOf(text string) Script {
+	for _, r := range text {
+		if isLao(r) {
+			return ScriptLao
+		}
+	}
+	return ScriptLatin
+}
+
+func findOverride(overrides []*Override, from Script, word string) *Override {
+	for _, o := range overrides {
+		if from == ScriptLao && o.Lao == word {
+			return o
+		}
+		if from == ScriptLatin && strings.EqualFold(o.Latin, word) {
+			return o
+		}
+	}
+	return nil
+}
+
+// Override fixes the spelling of a word the rules get wrong. It applies in
+// both directions.
+type Override struct {
+	Lao       string    `json:"lao"`
+	Latin     string    `json:"latin"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ListOverridesResult struct {
+	Overrides []*Override `json:"overrides"`
+}
+
+func (s *Service) ListOverrides(ctx context.Context) (*ListOverridesResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListOverrides"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTransliteration) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TransliterationForbidden)
+	}
+
+	overrides, err := listOverrides(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list overrides", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListOverridesResult{
+		Overrides: overrides,
+	}, nil
+}
+
+type OverrideReq struct {
+	Lao   string `json:"lao"`
+	Latin string `json:"latin"`
+}
+
+func (r *OverrideReq) Validate() error {
+	r.Lao = strings.TrimSpace(r.Lao)
+	r.Latin = strings.TrimSpace(r.Latin)
+
+	violations, err := validate.Violations(&contactqrPb.TransliterationOverrideRequest{
+		Lao:   r.Lao,
+		Latin: r.Latin,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.Lao != "" && (strings.IndexFunc(r.Lao, unicode.IsSpace) >= 0 || scriptOf(r.Lao) != ScriptLao) {
+		violations = append(violations, i18n.Violation("lao", i18n.InvalidWord))
+	}
+	if r.Latin != "" && (strings.IndexFunc(r.Latin, unicode.IsSpace) >= 0 || scriptOf(r.Latin) != ScriptLatin) {
+		violations = append(violations, i18n.Violation("latin", i18n.InvalidWord))
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidTransliteration).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// SaveOverride records how a Lao word is romanized, replacing any earlier
+// override of the word.
+func (s *Service) SaveOverride(ctx context.Context, in *OverrideReq) (*Override, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "SaveOverride"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTransliteration) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.TransliterationForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	o := &Override{
+		Lao:       in.Lao,
+		Latin:     in.Latin,
+		UpdatedBy: claims.Code,
+		UpdatedAt: time.Now(),
+	}
+	if err := saveOverride(ctx, s.db, o); err != nil {
+		zlog.Error("failed to save override", zap.Error(err))
+		return nil, err
+	}
+
+	return o, nil
+}
+
+type DeleteOverrideReq struct {
+	Lao string `json:"lao" query:"lao"`
+}
+
+func (s *Service) DeleteOverride(ctx context.Context, in *DeleteOverrideReq) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "DeleteOverride"),
+		zap.Any("req", in),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageTransliteration) {
+		return i18n.Error(codes.PermissionDenied, i18n.TransliterationForbidden)
+	}
+
+	err := deleteOverride(ctx, s.db, strings.TrimSpace(in.Lao))
+	if errors.Is(err, ErrOverrideNotFound) {
+		return i18n.Error(codes.NotFound, i18n.OverrideNotFound, "lao", in.Lao)
+	}
+	if err != nil {
+		zlog.Error("failed to delete override", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
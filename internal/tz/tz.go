@@ -0,0 +1,48 @@
+// Package tz carries the timezone timestamps are displayed in. Timestamps
+// are always stored and returned in UTC; the display timezone only adds a
+// local rendering next to them, so a report read in Vientiane shows the day
+// the event happened there.
+package tz
+
+import (
+	"context"
+	"time"
+)
+
+// Header lets a client ask for timestamps in a timezone other than the
+// server's default, e.g. "X-Timezone: Asia/Bangkok".
+const Header = "X-Timezone"
+
+// Default is the display timezone used when none is configured.
+const Default = "Asia/Vientiane"
+
+type ctxKey int
+
+const (
+	locationKey ctxKey = iota
+)
+
+// ContextWithLocation returns a copy of ctx displaying timestamps in loc.
+func ContextWithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey, loc)
+}
+
+// FromContext returns the display timezone of ctx, or UTC when none was set.
+func FromContext(ctx context.Context) *time.Location {
+	loc, ok := ctx.Value(locationKey).(*time.Location)
+	if !ok || loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Format renders t in loc as RFC 3339, or "" for the zero time.
+func Format(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+)
+
+// maxQueryAttempts bounds how many times a query failing with transient
+// errors is run.
+const maxQueryAttempts = 3
+
+// QueryContext runs db.QueryContext, retrying transient errors with
+// jittered backoff. Errors while reading the rows are not retried.
+func QueryContext(ctx context.Context, db *sql.DB, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retry(ctx, func() error {
+		var err error
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// ExecContext runs db.ExecContext, retrying transient errors with jittered
+// backoff. SQL Server rolls a failed statement back, so running it again
+// does not apply it twice.
+func ExecContext(ctx context.Context, db *sql.DB, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := retry(ctx, func() error {
+		var err error
+		res, err = db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+// Row is the result of QueryRowContext, run when scanned.
+type Row struct {
+	ctx   context.Context
+	db    *sql.DB
+	query string
+	args  []any
+}
+
+// QueryRowContext returns the row of db.QueryRowContext, whose Scan retries
+// transient errors with jittered backoff. Like sql.Row, Scan returns
+// sql.ErrNoRows when there is no row.
+func QueryRowContext(ctx context.Context, db *sql.DB, query string, args ...any) *Row {
+	return &Row{
+		ctx:   ctx,
+		db:    db,
+		query: query,
+		args:  args,
+	}
+}
+
+func (r *Row) Scan(dest ...any) error {
+	return retry(r.ctx, func() error {
+		return r.db.QueryRowContext(r.ctx, r.query, r.args...).Scan(dest...)
+	})
+}
+
+// retry runs fn until it succeeds, fails with a lasting error or
+// maxQueryAttempts are spent.
+func retry(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxQueryAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isTransient(err) || attempt == maxQueryAttempts {
+			return err
+		}
+
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// backoff waits before the attempt after attempt, longer the more attempts
+// were made, with jitter so competing callers do not collide again.
+func backoff(ctx context.Context, attempt int) error {
+	d := time.Duration(attempt) * 50 * time.Millisecond
+	d += rand.N(d)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// isTransient reports whether err is a SQL Server error that goes away on
+// its own: a deadlock victim (1205), a database being moved or failed over
+// (40613, 40197, 40501) or a resource limit being hit (10928, 10929,
+// 49918, 49919, 49920).
+func isTransient(err error) bool {
+	var merr mssql.Error
+	if !errors.As(err, &merr) {
+		return false
+	}
+
+	switch merr.Number {
+	case 1205, 40613, 40197, 40501, 10928, 10929, 49918, 49919, 49920:
+		return true
+	}
+	return false
+}
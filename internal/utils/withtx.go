@@ -3,10 +3,58 @@ package utils
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+
+	mssql "github.com/denisenkom/go-mssqldb"
 )
 
-func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
+// maxTxAttempts bounds how many times WithTx runs a transaction that keeps
+// failing with serialization errors.
+const maxTxAttempts = 3
+
+// TxRetryError is returned by WithTx when every attempt failed with a
+// serialization or deadlock error.
+type TxRetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *TxRetryError) Error() string {
+	return fmt.Sprintf("transaction failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *TxRetryError) Unwrap() error {
+	return e.Err
+}
+
+// WithTx runs fn in a serializable transaction. Deadlocks and update
+// conflicts are retried with jittered backoff since SQL Server resolves them
+// by aborting one of the competing transactions.
+func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = withTx(ctx, db, fn)
+		if err == nil || !isSerializationFailure(err) {
+			return err
+		}
+
+		if attempt == maxTxAttempts {
+			break
+		}
+
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+
+	return &TxRetryError{
+		Attempts: maxTxAttempts,
+		Err:      err,
+	}
+}
+
+func withTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
 	tx, err := db.BeginTx(
 		ctx,
 		&sql.TxOptions{
@@ -18,6 +66,7 @@ func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sq
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
+			panic(p)
 		} else if err != nil {
 			tx.Rollback()
 		}
@@ -33,3 +82,18 @@ func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sq
 
 	return nil
 }
+
+// isSerializationFailure reports whether err is a SQL Server deadlock victim
+// (1205) or snapshot update conflict (3960) error.
+func isSerializationFailure(err error) bool {
+	var merr mssql.Error
+	if !errors.As(err, &merr) {
+		return false
+	}
+
+	switch merr.Number {
+	case 1205, 3960:
+		return true
+	}
+	return false
+}
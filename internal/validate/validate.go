@@ -0,0 +1,104 @@
+// Package validate checks requests against the rules declared on their
+// messages in proto/contactqr/v1, the contract the REST and gRPC APIs share.
+// Rules that need more than the request itself, such as parsing a phone
+// number in the caller's region, stay in the Go Validate methods.
+package validate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	pvPb "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"buf.build/go/protovalidate"
+	"github.com/10664kls/contactqr/internal/i18n"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+// ruleKeys are the violation keys of the standard rules. Custom CEL rules
+// are reported by their id, which is a violation key itself.
+var ruleKeys = map[string]i18n.Key{
+	"required":           i18n.Required,
+	"string.max_len":     i18n.TooLong,
+	"string.min_len":     i18n.TooShort,
+	"string.max_bytes":   i18n.TooLong,
+	"string.email":       i18n.InvalidEmail,
+	"repeated.min_items": i18n.Required,
+	"repeated.max_items": i18n.TooLong,
+}
+
+// Violations returns a field violation for every rule m breaks, with the
+// field's JSON path. The error is only set when the rules themselves are
+// broken, e.g. a CEL expression does not compile.
+func Violations(m proto.Message) ([]*edPb.BadRequest_FieldViolation, error) {
+	err := protovalidate.Validate(m)
+	if err == nil {
+		return nil, nil
+	}
+
+	var ve *protovalidate.ValidationError
+	if !errors.As(err, &ve) {
+		return nil, err
+	}
+
+	violations := make([]*edPb.BadRequest_FieldViolation, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		violations = append(violations, i18n.Violation(jsonPath(v.Proto.GetField()), key(v.Proto.GetRuleId())))
+	}
+
+	return violations, nil
+}
+
+func key(ruleID string) i18n.Key {
+	if k, ok := ruleKeys[ruleID]; ok {
+		return k
+	}
+	if k := i18n.Key(ruleID); i18n.IsViolation(k) {
+		return k
+	}
+	return i18n.InvalidValue
+}
+
+// jsonPath returns a field path with the JSON names of the fields, e.g.
+// variants[1].layout.
+func jsonPath(path *pvPb.FieldPath) string {
+	var b strings.Builder
+	for i, e := range path.GetElements() {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(jsonName(e.GetFieldName()))
+
+		switch s := e.GetSubscript().(type) {
+		case *pvPb.FieldPathElement_Index:
+			b.WriteString("[" + strconv.FormatUint(s.Index, 10) + "]")
+		case *pvPb.FieldPathElement_StringKey:
+			b.WriteString("[" + strconv.Quote(s.StringKey) + "]")
+		case *pvPb.FieldPathElement_IntKey:
+			b.WriteString("[" + strconv.FormatInt(s.IntKey, 10) + "]")
+		}
+	}
+
+	return b.String()
+}
+
+// jsonName converts a proto field name to its JSON name the way protoc
+// does: phone_number becomes phoneNumber.
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper && 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
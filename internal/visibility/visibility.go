@@ -0,0 +1,63 @@
+// Package visibility decides which fields of an API resource each kind of
+// viewer may see. Resources declare a Policy once and filter themselves
+// through it when they are serialized.
+package visibility
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+type Role string
+
+const (
+	// RoleAnonymous is a caller without a token, e.g. someone scanning a card.
+	RoleAnonymous Role = "ANONYMOUS"
+
+	// RoleEmployee is any signed-in employee without a closer relation to
+	// the resource.
+	RoleEmployee Role = "EMPLOYEE"
+
+	// RoleOwner is the employee the resource belongs to.
+	RoleOwner Role = "OWNER"
+
+	// RoleApprover is the manager approving the resource.
+	RoleApprover Role = "APPROVER"
+
+	RoleHR Role = "HR"
+)
+
+// Policy maps JSON field names to the roles allowed to see them. Fields not
+// listed are visible to everyone.
+type Policy map[string][]Role
+
+// Marshal encodes v as JSON without the fields role may not see.
+func (p Policy) Marshal(v any, role Role) ([]byte, error) {
+	byt, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(byt))
+	dec.UseNumber()
+
+	fields := make(map[string]json.RawMessage)
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	for name, roles := range p {
+		if !slices.Contains(roles, role) {
+			delete(fields, name)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// Allows reports whether role may see the field name.
+func (p Policy) Allows(name string, role Role) bool {
+	roles, ok := p[name]
+	return !ok || slices.Contains(roles, role)
+}
@@ -0,0 +1,245 @@
+// Package wallet builds Apple Wallet passes of business cards: a .pkpass
+// holding the card's name, its photo and a QR code of its vCard, signed
+// with the organization's pass type certificate so Wallet accepts it.
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/imaging"
+)
+
+// ContentType is the media type of a pass.
+const ContentType = "application/vnd.apple.pkpass"
+
+// Config is the pass type passes are issued as, from the Apple developer
+// account of the organization.
+type Config struct {
+	// PassTypeID is the pass type identifier, e.g.
+	// pass.com.example.contactqr, and TeamID the ID of the team owning it.
+	PassTypeID string
+	TeamID     string
+
+	// Organization is the name Wallet shows as the issuer of a pass.
+	Organization string
+
+	// Certificate and Key are the PEM encoded pass type certificate and
+	// its RSA private key. WWDR is the PEM encoded Apple Worldwide
+	// Developer Relations certificate that issued it.
+	Certificate []byte
+	Key         []byte
+	WWDR        []byte
+}
+
+// Signer issues passes of one pass type.
+type Signer struct {
+	passTypeID   string
+	teamID       string
+	organization string
+
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+	wwdr *x509.Certificate
+}
+
+func NewSigner(cfg Config) (*Signer, error) {
+	if cfg.PassTypeID == "" || cfg.TeamID == "" || cfg.Organization == "" {
+		return nil, errors.New("pass type ID, team ID and organization are required")
+	}
+
+	cert, err := parseCertificate(cfg.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pass type certificate: %w", err)
+	}
+	wwdr, err := parseCertificate(cfg.WWDR)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WWDR certificate: %w", err)
+	}
+	key, err := parseKey(cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pass type key: %w", err)
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, errors.New("pass type key does not match its certificate")
+	}
+
+	return &Signer{
+		passTypeID:   cfg.PassTypeID,
+		teamID:       cfg.TeamID,
+		organization: cfg.Organization,
+		cert:         cert,
+		key:          key,
+		wwdr:         wwdr,
+	}, nil
+}
+
+// Pass is what a pass shows.
+type Pass struct {
+	// SerialNumber identifies the pass among those of its type; a pass
+	// added again with the same one replaces the first.
+	SerialNumber string
+	Description  string
+
+	Name   string
+	Fields []Field
+
+	// Barcode is the content of the QR code shown on the pass.
+	Barcode string
+
+	// Color is the background; text is white.
+	Color color.RGBA
+
+	// Photo is shown next to the name, nil for none.
+	Photo image.Image
+}
+
+// Field is a labelled line under the name.
+type Field struct {
+	Key   string
+	Label string
+	Value string
+}
+
+// Image sizes of a generic pass, in points; files are added at 1x and 2x.
+const (
+	iconSize      = 29
+	thumbnailSize = 90
+)
+
+// Build returns the signed .pkpass of p.
+func (s *Signer) Build(p *Pass) ([]byte, error) {
+	files := make(map[string][]byte)
+
+	pass, err := s.passJSON(p)
+	if err != nil {
+		return nil, err
+	}
+	files["pass.json"] = pass
+
+	for scale, suffix := range map[int]string{1: "", 2: "@2x"} {
+		icon, err := imaging.EncodePNG(imaging.Solid(p.Color, iconSize*scale, iconSize*scale))
+		if err != nil {
+			return nil, err
+		}
+		files["icon"+suffix+".png"] = icon
+
+		if p.Photo != nil {
+			thumb, err := imaging.EncodePNG(imaging.Fill(p.Photo, thumbnailSize*scale, thumbnailSize*scale))
+			if err != nil {
+				return nil, err
+			}
+			files["thumbnail"+suffix+".png"] = thumb
+		}
+	}
+
+	manifest := make(map[string]string, len(files))
+	for name, data := range files {
+		sum := sha1.Sum(data)
+		manifest[name] = hex.EncodeToString(sum[:])
+	}
+	files["manifest.json"], err = json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	files["signature"], err = signDetached(files["manifest.json"], s.cert, s.key, []*x509.Certificate{s.wwdr}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type passField struct {
+	Key   string `json:"key"`
+	Label string `json:"label,omitempty"`
+	Value string `json:"value"`
+}
+
+func (s *Signer) passJSON(p *Pass) ([]byte, error) {
+	secondary := make([]passField, 0, len(p.Fields))
+	for _, f := range p.Fields {
+		if f.Value != "" {
+			secondary = append(secondary, passField(f))
+		}
+	}
+
+	rgb := fmt.Sprintf("rgb(%d, %d, %d)", p.Color.R, p.Color.G, p.Color.B)
+	return json.Marshal(map[string]any{
+		"formatVersion":      1,
+		"passTypeIdentifier": s.passTypeID,
+		"teamIdentifier":     s.teamID,
+		"organizationName":   s.organization,
+		"serialNumber":       p.SerialNumber,
+		"description":        p.Description,
+		"backgroundColor":    rgb,
+		"foregroundColor":    "rgb(255, 255, 255)",
+		"labelColor":         "rgb(255, 255, 255)",
+		"generic": map[string]any{
+			"primaryFields":   []passField{{Key: "name", Value: p.Name}},
+			"secondaryFields": secondary,
+		},
+		"barcodes": []map[string]string{{
+			"format":          "PKBarcodeFormatQR",
+			"message":         p.Barcode,
+			"messageEncoding": "utf-8",
+		}},
+	})
+}
+
+func parseCertificate(b []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("not a PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("not a PEM encoded key")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA key")
+	}
+	return rsaKey, nil
+}
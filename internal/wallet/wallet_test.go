@@ -0,0 +1,203 @@
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"image"
+	"image/color"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestConfig returns a pass type certificate issued by a stand-in for
+// the WWDR certificate.
+func newTestConfig(t *testing.T) Config {
+	t.Helper()
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test WWDR"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Pass Type ID: pass.com.example.contactqr"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	return Config{
+		PassTypeID:   "pass.com.example.contactqr",
+		TeamID:       "DEF123GHIJ",
+		Organization: "Example",
+		Certificate:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+		Key:          pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		WWDR:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cfg := newTestConfig(t)
+	s, err := NewSigner(cfg)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	photo := image.NewRGBA(image.Rect(0, 0, 300, 400))
+	b, err := s.Build(&Pass{
+		SerialNumber: "p1",
+		Description:  "Business card",
+		Name:         "Visitor",
+		Fields:       []Field{{Key: "position", Label: "Position", Value: "Engineer"}},
+		Barcode:      "BEGIN:VCARD\r\nEND:VCARD\r\n",
+		Color:        color.RGBA{R: 0, G: 82, B: 155, A: 255},
+		Photo:        photo,
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("pass is not a zip: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Open %s: %v", f.Name, err)
+		}
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+
+	var pass struct {
+		PassTypeIdentifier string `json:"passTypeIdentifier"`
+		SerialNumber       string `json:"serialNumber"`
+		Barcodes           []struct {
+			Message string `json:"message"`
+		} `json:"barcodes"`
+	}
+	if err := json.Unmarshal(files["pass.json"], &pass); err != nil {
+		t.Fatalf("pass.json: %v", err)
+	}
+	if pass.PassTypeIdentifier != cfg.PassTypeID || pass.SerialNumber != "p1" || len(pass.Barcodes) != 1 {
+		t.Fatalf("pass.json = %s", files["pass.json"])
+	}
+
+	var manifest map[string]string
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	for _, name := range []string{"pass.json", "icon.png", "icon@2x.png", "thumbnail.png", "thumbnail@2x.png"} {
+		sum := sha1.Sum(files[name])
+		if manifest[name] != hex.EncodeToString(sum[:]) {
+			t.Errorf("manifest of %s = %q, want its SHA-1", name, manifest[name])
+		}
+	}
+
+	if _, _, err := image.Decode(bytes.NewReader(files["thumbnail@2x.png"])); err != nil {
+		t.Errorf("thumbnail@2x.png: %v", err)
+	}
+
+	verifySignature(t, files["signature"], files["manifest.json"], s.cert)
+}
+
+// verifySignature checks that sig is a detached signature of content by
+// cert, over the signed attributes.
+func verifySignature(t *testing.T, sig, content []byte, cert *x509.Certificate) {
+	t.Helper()
+
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(sig, &ci); err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		t.Fatalf("signature content type = %v, want signedData", ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatalf("signed data: %v", err)
+	}
+	if len(sd.SignerInfos) != 1 || len(sd.ContentInfo.Content.Bytes) != 0 {
+		t.Fatalf("signed data has %d signers and %d bytes of content, want 1 and none", len(sd.SignerInfos), len(sd.ContentInfo.Content.Bytes))
+	}
+
+	si := sd.SignerInfos[0]
+	if si.IssuerAndSerialNumber.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Fatalf("signer serial number = %v, want %v", si.IssuerAndSerialNumber.SerialNumber, cert.SerialNumber)
+	}
+
+	signed, _ := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: si.AuthenticatedAttributes.Bytes})
+	h := sha256.Sum256(signed)
+	if err := rsa.VerifyPKCS1v15(cert.PublicKey.(*rsa.PublicKey), crypto.SHA256, h[:], si.EncryptedDigest); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	digest := sha256.Sum256(content)
+	rest := si.AuthenticatedAttributes.Bytes
+	for len(rest) > 0 {
+		var a attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &a); err != nil {
+			t.Fatalf("attribute: %v", err)
+		}
+		if !a.Type.Equal(oidMessageDigest) {
+			continue
+		}
+		var got []byte
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &got); err != nil {
+			t.Fatalf("message digest: %v", err)
+		}
+		if !bytes.Equal(got, digest[:]) {
+			t.Fatal("message digest does not match the manifest")
+		}
+		return
+	}
+	t.Fatal("signature has no message digest")
+}
+
+func TestNewSignerRejectsMismatchedKey(t *testing.T) {
+	cfg := newTestConfig(t)
+	other := newTestConfig(t)
+	cfg.Key = other.Key
+
+	if _, err := NewSigner(cfg); err == nil {
+		t.Fatal("NewSigner with another certificate's key succeeded, want an error")
+	}
+}
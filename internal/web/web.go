@@ -0,0 +1,167 @@
+// Package web renders the HTML pages served to people who open a card's
+// link instead of scanning its vCard straight into their contacts.
+package web
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/10664kls/contactqr/internal/i18n"
+)
+
+//go:embed templates/*.html
+var templates embed.FS
+
+// Card is what the card page shows of a published card.
+type Card struct {
+	DisplayName    string
+	PositionName   string
+	DepartmentName string
+	CompanyName    string
+
+	// PhoneNumber is the number as the company displays it and PhoneURI
+	// the number dialled, in E.164 when known.
+	PhoneNumber string
+	PhoneURI    string
+
+	Email string
+
+	// VCFURL is where the "save contact" button downloads the vCard.
+	VCFURL string
+
+	// LogoURL, PrimaryColor and AccentColor are the card template of the
+	// company, see template.Design. Colors are "#rrggbb".
+	LogoURL      string
+	PrimaryColor string
+	AccentColor  string
+}
+
+// Labels are the page texts in the visitor's language.
+type Labels struct {
+	SaveContact  string
+	NotFound     string
+	NotFoundHint string
+}
+
+var labels = map[i18n.Lang]*Labels{
+	i18n.English: {
+		SaveContact:  "Save contact",
+		NotFound:     "Card not found",
+		NotFoundHint: "This card does not exist or is no longer published.",
+	},
+	i18n.Lao: {
+		SaveContact:  "ບັນທຶກລາຍຊື່ຕິດຕໍ່",
+		NotFound:     "ບໍ່ພົບນາມບັດ",
+		NotFoundHint: "ນາມບັດນີ້ບໍ່ມີຢູ່ ຫຼື ບໍ່ໄດ້ເຜີຍແຜ່ແລ້ວ.",
+	},
+	i18n.Thai: {
+		SaveContact:  "บันทึกรายชื่อติดต่อ",
+		NotFound:     "ไม่พบนามบัตร",
+		NotFoundHint: "นามบัตรนี้ไม่มีอยู่หรือไม่ได้เผยแพร่แล้ว",
+	},
+}
+
+type page struct {
+	Lang   i18n.Lang
+	Labels *Labels
+	Card   *Card
+}
+
+// Renderer renders the pages from the built-in templates or, for the
+// companies that have one, their own card template.
+type Renderer struct {
+	card      *template.Template
+	notFound  *template.Template
+	companies map[int64]*template.Template
+}
+
+// NewRenderer parses the built-in templates and the company templates in
+// overrides, which may be nil. A company's template is named
+// company-<companyID>.html. It may redefine the "style" and "body" blocks of
+// the built-in card page, or be a whole page of its own, and is executed
+// with the same data.
+func NewRenderer(overrides fs.FS) (*Renderer, error) {
+	card, err := template.ParseFS(templates, "templates/card.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse card template: %w", err)
+	}
+	notFound, err := template.ParseFS(templates, "templates/not_found.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse not found template: %w", err)
+	}
+
+	r := &Renderer{
+		card:      card,
+		notFound:  notFound,
+		companies: make(map[int64]*template.Template),
+	}
+	if overrides == nil {
+		return r, nil
+	}
+
+	names, err := fs.Glob(overrides, "company-*.html")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		id := strings.TrimSuffix(strings.TrimPrefix(path.Base(name), "company-"), ".html")
+		companyID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || companyID <= 0 {
+			return nil, fmt.Errorf("invalid company template name %s", name)
+		}
+
+		src, err := fs.ReadFile(overrides, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		t, err := template.Must(card.Clone()).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		r.companies[companyID] = t
+	}
+
+	return r, nil
+}
+
+// RenderCard renders the card page of a card of the company in lang.
+func (r *Renderer) RenderCard(companyID int64, lang i18n.Lang, c *Card) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("card is nil")
+	}
+
+	t, ok := r.companies[companyID]
+	if !ok {
+		t = r.card
+	}
+	return execute(t, lang, c)
+}
+
+// RenderNotFound renders the page shown for cards that do not exist or are
+// not published.
+func (r *Renderer) RenderNotFound(lang i18n.Lang) ([]byte, error) {
+	return execute(r.notFound, lang, nil)
+}
+
+// execute renders the whole page before anything is written, so a
+// template error never leaves a half-written page.
+func execute(t *template.Template, lang i18n.Lang, c *Card) ([]byte, error) {
+	l, ok := labels[lang]
+	if !ok {
+		lang = i18n.English
+		l = labels[lang]
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, &page{Lang: lang, Labels: l, Card: c}); err != nil {
+		return nil, fmt.Errorf("failed to render page: %w", err)
+	}
+	return b.Bytes(), nil
+}
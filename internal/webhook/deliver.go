@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/reqid"
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryBatch is the number of deliveries sent per transaction.
+	deliveryBatch = 20
+
+	// maxAttempts is how often a delivery is tried before it fails.
+	maxAttempts = 10
+
+	// The wait after the n-th failed attempt is firstRetry * 2^(n-1), at
+	// most maxRetry, which spreads the attempts over about six hours.
+	firstRetry = time.Minute
+	maxRetry   = 2 * time.Hour
+)
+
+// backoff returns the wait after the attempts-th failed attempt.
+func backoff(attempts int) time.Duration {
+	d := firstRetry << (attempts - 1)
+	if d <= 0 || d > maxRetry {
+		return maxRetry
+	}
+	return d
+}
+
+// Deliver sends the deliveries that are due and returns how many it sent,
+// successfully or not.
+func (s *Service) Deliver(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Rows are locked until commit and skipped by other replicas, so each
+	// delivery is sent by one replica at a time.
+	due, err := listDueDeliveries(ctx, tx, deliveryBatch, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range due {
+		zlog := reqid.Logger(ctx, s.zlog).With(
+			zap.String("method", "Deliver"),
+			zap.String("webhook_id", d.webhookID),
+			zap.String("event_id", d.eventID),
+			zap.Int("attempt", d.attempts+1),
+		)
+
+		sendErr := s.send(ctx, d)
+		if sendErr == nil {
+			if err := markDelivered(ctx, tx, d.id, time.Now()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		d.attempts++
+		if d.attempts >= maxAttempts {
+			zlog.Error("webhook delivery failed", zap.Error(sendErr))
+		} else {
+			zlog.Warn("webhook delivery attempt failed", zap.Error(sendErr))
+		}
+		if err := failDelivery(ctx, tx, d, time.Now().Add(backoff(d.attempts)), sendErr); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(due), nil
+}
+
+// RunDeliveries sends due deliveries every interval until ctx is done. A
+// full batch is followed immediately by the next one.
+func (s *Service) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			n, err := s.Deliver(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				s.zlog.Error("failed to deliver webhooks", zap.Error(err))
+			}
+			if err != nil || n < deliveryBatch {
+				break
+			}
+		}
+	}
+}
+
+func (s *Service) send(ctx context.Context, d *delivery) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(d.payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "contactqr-webhook")
+	req.Header.Set("X-Webhook-Id", d.eventID)
+	req.Header.Set("X-Webhook-Event", d.eventType)
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", "sha256="+sign(d.secret, ts, d.payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// Drained so the connection is reused.
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded %s", res.Status)
+	}
+	return nil
+}
+
+// Check reports the registered endpoints that cannot be reached. An
+// endpoint answering HEAD with anything but a 5xx is reachable: receivers
+// need not accept HEAD, only respond.
+func (s *Service) Check(ctx context.Context) error {
+	webhooks, err := listWebhooks(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(webhooks))
+	var wg sync.WaitGroup
+	for i, w := range webhooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.probe(ctx, w.URL); err != nil {
+				errs[i] = fmt.Errorf("webhook %s: %w", w.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Service) probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "contactqr-webhook")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("endpoint responded %s", res.Status)
+	}
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of "<ts>.<payload>" keyed with secret.
+func sign(secret, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
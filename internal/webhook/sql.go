@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/pager"
+	"github.com/10664kls/contactqr/internal/pii"
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusDelivered = "DELIVERED"
+	StatusFailed    = "FAILED"
+)
+
+func createWebhook(ctx context.Context, db *sql.DB, in *Webhook) error {
+	events := make([]string, 0, len(in.Events))
+	for _, e := range in.Events {
+		events = append(events, string(e))
+	}
+
+	q, args := sq.
+		Insert("dbo.webhook").
+		Columns(
+			"id",
+			"url",
+			"secret",
+			"events",
+			"created_by",
+			"created_at",
+		).
+		Values(
+			in.ID,
+			in.URL,
+			pii.Text(in.secret),
+			strings.Join(events, ","),
+			in.CreatedBy,
+			in.CreatedAt,
+		).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func listWebhooks(ctx context.Context, db *sql.DB) ([]*Webhook, error) {
+	q, args := sq.
+		Select(
+			"id",
+			"url",
+			"events",
+			"created_by",
+			"created_at",
+		).
+		From("dbo.webhook").
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*Webhook, 0)
+	for rows.Next() {
+		var w Webhook
+		var events string
+		if err := rows.Scan(
+			&w.ID,
+			&w.URL,
+			&events,
+			&w.CreatedBy,
+			&w.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		w.Events = make([]event.Type, 0)
+		for _, e := range strings.Split(events, ",") {
+			if e != "" {
+				w.Events = append(w.Events, event.Type(e))
+			}
+		}
+		webhooks = append(webhooks, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func deleteWebhook(ctx context.Context, db *sql.DB, id string) error {
+	q, args := sq.
+		Delete("dbo.webhook").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// createDelivery queues the delivery of e to the webhook, unless it was
+// queued before.
+func createDelivery(ctx context.Context, db *sql.DB, webhookID string, e *event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	q := `
+INSERT INTO dbo.webhook_delivery (webhook_id, event_id, event_type, payload, next_attempt_at, created_at)
+SELECT @p1, @p2, @p3, @p4, @p5, @p5
+WHERE NOT EXISTS (
+  SELECT 1 FROM dbo.webhook_delivery WHERE webhook_id = @p1 AND event_id = @p2
+);`
+
+	now := time.Now()
+	if _, err := db.ExecContext(ctx, q, webhookID, e.ID, string(e.Type), string(payload), pager.DateTime(now)); err != nil {
+		return fmt.Errorf("failed to execute create delivery: %w", err)
+	}
+
+	return nil
+}
+
+// delivery is a queued delivery with what sending it takes.
+type delivery struct {
+	id        int64
+	webhookID string
+	eventID   string
+	eventType string
+	payload   []byte
+	attempts  int
+	url       string
+	secret    string
+}
+
+func listDueDeliveries(ctx context.Context, tx *sql.Tx, limit uint64, now time.Time) ([]*delivery, error) {
+	q, args := sq.
+		Select(
+			fmt.Sprintf("TOP %d d.id", limit),
+			"d.webhook_id",
+			"d.event_id",
+			"d.event_type",
+			"d.payload",
+			"d.attempts",
+			"w.url",
+			"w.secret",
+		).
+		From("dbo.webhook_delivery AS d WITH (UPDLOCK, READPAST, ROWLOCK)").
+		Join("dbo.webhook AS w ON w.id = d.webhook_id").
+		Where(sq.Eq{"d.status": StatusPending}).
+		Where(sq.LtOrEq{"d.next_attempt_at": pager.DateTime(now)}).
+		OrderBy("d.next_attempt_at", "d.id").
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*delivery, 0)
+	for rows.Next() {
+		var d delivery
+		var payload string
+		if err := rows.Scan(
+			&d.id,
+			&d.webhookID,
+			&d.eventID,
+			&d.eventType,
+			&payload,
+			&d.attempts,
+			&d.url,
+			(*pii.Text)(&d.secret),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		d.payload = []byte(payload)
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func markDelivered(ctx context.Context, tx *sql.Tx, id int64, at time.Time) error {
+	q, args := sq.
+		Update("dbo.webhook_delivery").
+		Set("status", StatusDelivered).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("delivered_at", at).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
+
+// failDelivery records a failed attempt of d, whose attempts count it, and
+// schedules the next one at next, or fails d after maxAttempts.
+func failDelivery(ctx context.Context, tx *sql.Tx, d *delivery, next time.Time, cause error) error {
+	msg := []rune(cause.Error())
+	if len(msg) > 1024 {
+		msg = msg[:1024]
+	}
+
+	status := StatusPending
+	if d.attempts >= maxAttempts {
+		status = StatusFailed
+	}
+
+	q, args := sq.
+		Update("dbo.webhook_delivery").
+		Set("status", status).
+		Set("attempts", d.attempts).
+		Set("next_attempt_at", next).
+		Set("last_error", string(msg)).
+		Where(sq.Eq{"id": d.id}).
+		PlaceholderFormat(sq.AtP).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return nil
+}
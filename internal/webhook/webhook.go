@@ -0,0 +1,239 @@
+// Package webhook notifies HTTPS endpoints registered by admins, such as
+// the intranet or the printing vendor, of business card lifecycle events.
+//
+// Each event is POSTed as the JSON of an event.Event with the headers
+//
+//	X-Webhook-Id:        the event ID, the same on every retry
+//	X-Webhook-Event:     the event type, e.g. APPROVED
+//	X-Webhook-Timestamp: the Unix time of the attempt
+//	X-Webhook-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// keyed with the webhook's secret. Receivers verify the signature, reject
+// stale timestamps and deduplicate by ID. A delivery that does not get a
+// 2xx response is retried with exponential backoff.
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	contactqrPb "github.com/10664kls/contactqr/genproto/go/contactqr/v1"
+	"github.com/10664kls/contactqr/internal/auth"
+	"github.com/10664kls/contactqr/internal/event"
+	"github.com/10664kls/contactqr/internal/i18n"
+	"github.com/10664kls/contactqr/internal/rbac"
+	"github.com/10664kls/contactqr/internal/reqid"
+	"github.com/10664kls/contactqr/internal/validate"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	edPb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// eventTypes are the events webhooks may subscribe to.
+var eventTypes = []event.Type{
+	event.TypeCreated,
+	event.TypeApproved,
+	event.TypeRejected,
+	event.TypePublished,
+}
+
+// Service keeps the registered webhooks and delivers card events to them.
+// It is an event.Publisher: events from the outbox are queued as
+// deliveries, which RunDeliveries sends.
+type Service struct {
+	db     *sql.DB
+	client *http.Client
+	zlog   *zap.Logger
+}
+
+func NewService(_ context.Context, db *sql.DB, zlog *zap.Logger) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if zlog == nil {
+		return nil, errors.New("zlog is nil")
+	}
+
+	return &Service{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+		zlog:   zlog,
+	}, nil
+}
+
+// Webhook is a registered endpoint. Its secret is never shown again once
+// registered.
+type Webhook struct {
+	ID        string       `json:"id"`
+	URL       string       `json:"url"`
+	Events    []event.Type `json:"events"`
+	CreatedBy string       `json:"createdBy"`
+	CreatedAt time.Time    `json:"createdAt"`
+
+	secret string
+}
+
+// Subscribes reports whether w is notified of events of type t.
+func (w *Webhook) Subscribes(t event.Type) bool {
+	return slices.Contains(w.Events, t)
+}
+
+type WebhookReq struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+func (r *WebhookReq) Validate() error {
+	r.URL = strings.TrimSpace(r.URL)
+	for i, e := range r.Events {
+		r.Events[i] = strings.ToUpper(strings.TrimSpace(e))
+	}
+
+	violations, err := validate.Violations(&contactqrPb.WebhookRequest{
+		Url:    r.URL,
+		Secret: r.Secret,
+		Events: r.Events,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		s, _ := i18n.Status(codes.InvalidArgument, i18n.InvalidWebhook).WithDetails(&edPb.BadRequest{FieldViolations: violations})
+		return s.Err()
+	}
+
+	return nil
+}
+
+// CreateWebhook registers an endpoint for the events it names, every card
+// lifecycle event without any. It is for admins only.
+func (s *Service) CreateWebhook(ctx context.Context, in *WebhookReq) (*Webhook, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "CreateWebhook"),
+		zap.String("url", in.URL),
+		zap.Strings("events", in.Events),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageWebhooks) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.WebhooksForbidden)
+	}
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	w := &Webhook{
+		ID:        uuid.NewString(),
+		URL:       in.URL,
+		Events:    slices.Clone(eventTypes),
+		CreatedBy: claims.Code,
+		CreatedAt: time.Now(),
+		secret:    in.Secret,
+	}
+	if len(in.Events) > 0 {
+		w.Events = make([]event.Type, 0, len(in.Events))
+		for _, e := range in.Events {
+			if t := event.Type(e); !slices.Contains(w.Events, t) {
+				w.Events = append(w.Events, t)
+			}
+		}
+	}
+
+	if err := createWebhook(ctx, s.db, w); err != nil {
+		zlog.Error("failed to create webhook", zap.Error(err))
+		return nil, err
+	}
+
+	return w, nil
+}
+
+type ListWebhooksResult struct {
+	Webhooks []*Webhook `json:"webhooks"`
+}
+
+// ListWebhooks lists the registered webhooks, newest first. It is for
+// admins only.
+func (s *Service) ListWebhooks(ctx context.Context) (*ListWebhooksResult, error) {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "ListWebhooks"),
+		zap.String("username", claims.Code),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageWebhooks) {
+		return nil, i18n.Error(codes.PermissionDenied, i18n.WebhooksForbidden)
+	}
+
+	webhooks, err := listWebhooks(ctx, s.db)
+	if err != nil {
+		zlog.Error("failed to list webhooks", zap.Error(err))
+		return nil, err
+	}
+
+	return &ListWebhooksResult{Webhooks: webhooks}, nil
+}
+
+// DeleteWebhook unregisters a webhook. Its pending deliveries are dropped.
+// It is for admins only.
+func (s *Service) DeleteWebhook(ctx context.Context, id string) error {
+	claims := auth.ClaimsFromContext(ctx)
+
+	zlog := reqid.Logger(ctx, s.zlog).With(
+		zap.String("method", "DeleteWebhook"),
+		zap.String("username", claims.Code),
+		zap.String("id", id),
+	)
+
+	if !rbac.Can(ctx, rbac.ManageWebhooks) {
+		return i18n.Error(codes.PermissionDenied, i18n.WebhooksForbidden)
+	}
+
+	err := deleteWebhook(ctx, s.db, id)
+	if errors.Is(err, ErrWebhookNotFound) {
+		return i18n.Error(codes.NotFound, i18n.WebhookNotFound, "id", id)
+	}
+	if err != nil {
+		zlog.Error("failed to delete webhook", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Publish queues a delivery of e to every webhook subscribed to its type.
+// The outbox may publish an event again, which queues nothing new.
+func (s *Service) Publish(ctx context.Context, e *event.Event) error {
+	if !slices.Contains(eventTypes, e.Type) {
+		return nil
+	}
+
+	webhooks, err := listWebhooks(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range webhooks {
+		if !w.Subscribes(e.Type) {
+			continue
+		}
+		if err := createDelivery(ctx, s.db, w.ID, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN vcf, vcf_hash;
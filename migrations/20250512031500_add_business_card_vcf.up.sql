@@ -0,0 +1,3 @@
+ALTER TABLE dbo.business_card
+  ADD vcf VARBINARY(MAX) NULL,
+      vcf_hash VARCHAR(64) NOT NULL DEFAULT '';
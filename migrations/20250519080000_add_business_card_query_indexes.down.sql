@@ -0,0 +1,7 @@
+DROP INDEX ix_tb_employee_approveby ON dbo.tb_employee;
+
+DROP INDEX ix_business_card_status_created_at ON dbo.business_card;
+
+DROP INDEX ix_business_card_employee_id_created_at ON dbo.business_card;
+
+DROP INDEX ix_business_card_created_at ON dbo.business_card;
@@ -0,0 +1,15 @@
+CREATE INDEX ix_business_card_created_at
+  ON dbo.business_card (created_at DESC, id DESC)
+  INCLUDE (employee_id, status);
+
+CREATE INDEX ix_business_card_employee_id_created_at
+  ON dbo.business_card (employee_id, created_at DESC, id DESC)
+  INCLUDE (status);
+
+CREATE INDEX ix_business_card_status_created_at
+  ON dbo.business_card (status, created_at DESC, id DESC)
+  INCLUDE (employee_id);
+
+CREATE INDEX ix_tb_employee_approveby
+  ON dbo.tb_employee (approveby)
+  INCLUDE (EID);
@@ -0,0 +1,3 @@
+DROP TABLE dbo.business_card_history_anchor;
+
+DROP TABLE dbo.business_card_history;
@@ -0,0 +1,23 @@
+CREATE TABLE dbo.business_card_history (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL,
+  from_status VARCHAR(15) NOT NULL DEFAULT '',
+  to_status VARCHAR(15) NOT NULL,
+  remark NVARCHAR(MAX) NOT NULL DEFAULT '',
+  actor VARCHAR(50) NOT NULL DEFAULT '',
+  created_at DATETIME2(3) NOT NULL,
+  prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+  hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX ix_business_card_history_card_id
+  ON dbo.business_card_history (card_id, id);
+
+CREATE TABLE dbo.business_card_history_anchor (
+  history_id BIGINT NOT NULL PRIMARY KEY,
+  hash VARCHAR(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE dbo.business_card_history
+  ADD CONSTRAINT fk_business_card_history_card_id FOREIGN KEY (card_id) REFERENCES dbo.business_card(id);
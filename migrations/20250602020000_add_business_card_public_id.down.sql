@@ -0,0 +1,4 @@
+DROP INDEX ux_business_card_public_id ON dbo.business_card;
+
+ALTER TABLE dbo.business_card
+  DROP COLUMN public_id;
@@ -0,0 +1,14 @@
+ALTER TABLE dbo.business_card
+  ADD public_id VARCHAR(32) NULL;
+
+CREATE UNIQUE INDEX ux_business_card_public_id
+  ON dbo.business_card (public_id)
+  WHERE public_id IS NOT NULL;
+
+-- Cards published before public IDs existed keep their card ID as public ID
+-- so links and codes that were already printed keep working. The file runs
+-- as one batch, compiled before public_id exists, so the backfill is
+-- compiled on its own.
+EXEC('UPDATE dbo.business_card
+  SET public_id = id
+  WHERE status = ''PUBLISHED''');
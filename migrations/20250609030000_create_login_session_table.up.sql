@@ -0,0 +1,15 @@
+CREATE TABLE dbo.login_session (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  username VARCHAR(50) NOT NULL,
+  device_hash VARCHAR(64) NOT NULL,
+  user_agent NVARCHAR(512) NOT NULL DEFAULT '',
+  ip VARCHAR(45) NOT NULL DEFAULT '',
+  country VARCHAR(2) NOT NULL DEFAULT '',
+  report_token VARCHAR(64) NOT NULL UNIQUE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  revoked_at DATETIME NULL
+);
+
+CREATE INDEX ix_login_session_username
+  ON dbo.login_session (username)
+  INCLUDE (device_hash, country);
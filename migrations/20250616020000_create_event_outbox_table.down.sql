@@ -0,0 +1 @@
+DROP TABLE dbo.event_outbox;
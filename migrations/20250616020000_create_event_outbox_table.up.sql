@@ -0,0 +1,15 @@
+CREATE TABLE dbo.event_outbox (
+  seq BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  id VARCHAR(36) NOT NULL UNIQUE,
+  type VARCHAR(20) NOT NULL,
+  card_id VARCHAR(12) NOT NULL,
+  payload NVARCHAR(MAX) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  published_at DATETIME NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error NVARCHAR(1024) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ix_event_outbox_pending
+  ON dbo.event_outbox (seq)
+  WHERE published_at IS NULL;
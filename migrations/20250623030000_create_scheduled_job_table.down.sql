@@ -0,0 +1 @@
+DROP TABLE dbo.scheduled_job;
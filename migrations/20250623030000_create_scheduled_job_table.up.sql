@@ -0,0 +1,10 @@
+CREATE TABLE dbo.scheduled_job (
+  name VARCHAR(50) NOT NULL PRIMARY KEY,
+  holder VARCHAR(100) NULL,
+  lease_until DATETIME NULL,
+  last_run_by VARCHAR(100) NOT NULL DEFAULT '',
+  last_started_at DATETIME NULL,
+  last_finished_at DATETIME NULL,
+  last_duration_ms BIGINT NOT NULL DEFAULT 0,
+  last_error NVARCHAR(1024) NOT NULL DEFAULT ''
+);
@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN phone_e164, phone_national, mobile_e164, mobile_national;
@@ -0,0 +1,5 @@
+ALTER TABLE dbo.business_card
+  ADD phone_e164 VARCHAR(512) NOT NULL DEFAULT '',
+      phone_national VARCHAR(512) NOT NULL DEFAULT '',
+      mobile_e164 VARCHAR(512) NOT NULL DEFAULT '',
+      mobile_national VARCHAR(512) NOT NULL DEFAULT '';
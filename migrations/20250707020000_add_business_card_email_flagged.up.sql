@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  ADD email_flagged BIT NOT NULL DEFAULT 0;
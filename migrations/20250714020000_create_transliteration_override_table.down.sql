@@ -0,0 +1 @@
+DROP TABLE dbo.transliteration_override;
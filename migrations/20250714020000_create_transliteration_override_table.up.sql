@@ -0,0 +1,6 @@
+CREATE TABLE dbo.transliteration_override (
+  lao NVARCHAR(100) NOT NULL PRIMARY KEY,
+  latin NVARCHAR(100) NOT NULL,
+  updated_by VARCHAR(50) NOT NULL DEFAULT '',
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+CREATE TABLE dbo.employee_preference (
+  employee_id BIGINT NOT NULL PRIMARY KEY,
+  notification_language VARCHAR(5) NOT NULL DEFAULT 'en',
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
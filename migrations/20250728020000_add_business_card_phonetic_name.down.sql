@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN phonetic_given_name, phonetic_family_name;
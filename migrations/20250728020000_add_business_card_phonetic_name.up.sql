@@ -0,0 +1,3 @@
+ALTER TABLE dbo.business_card
+  ADD phonetic_given_name NVARCHAR(100) NOT NULL DEFAULT '',
+      phonetic_family_name NVARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+CREATE TABLE dbo.business_card_lead (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  name NVARCHAR(200) NOT NULL,
+  phone VARCHAR(512) NOT NULL DEFAULT '',
+  email VARCHAR(512) NOT NULL DEFAULT '',
+  message NVARCHAR(1000) NOT NULL DEFAULT '',
+  remote_ip VARCHAR(45) NOT NULL DEFAULT '',
+  user_agent NVARCHAR(512) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_business_card_lead_card_id
+  ON dbo.business_card_lead (card_id, created_at DESC, id DESC);
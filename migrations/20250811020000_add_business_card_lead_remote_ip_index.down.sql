@@ -0,0 +1 @@
+DROP INDEX ix_business_card_lead_remote_ip ON dbo.business_card_lead;
@@ -0,0 +1,2 @@
+CREATE INDEX ix_business_card_lead_remote_ip
+  ON dbo.business_card_lead (card_id, remote_ip, created_at);
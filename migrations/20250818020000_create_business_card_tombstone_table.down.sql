@@ -0,0 +1,3 @@
+DROP INDEX ix_business_card_employee_id_updated_at ON dbo.business_card;
+
+DROP TABLE dbo.business_card_tombstone;
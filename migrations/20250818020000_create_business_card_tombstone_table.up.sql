@@ -0,0 +1,13 @@
+-- Cards are kept here after deletion so the mobile app's delta sync can
+-- drop them from its offline copy.
+CREATE TABLE dbo.business_card_tombstone (
+  card_id VARCHAR(12) NOT NULL PRIMARY KEY,
+  employee_id INT NOT NULL,
+  deleted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_business_card_tombstone_employee_id_deleted_at
+  ON dbo.business_card_tombstone (employee_id, deleted_at);
+
+CREATE INDEX ix_business_card_employee_id_updated_at
+  ON dbo.business_card (employee_id, updated_at, id);
@@ -0,0 +1 @@
+DROP TABLE dbo.push_device;
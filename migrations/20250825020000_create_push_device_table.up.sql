@@ -0,0 +1,10 @@
+CREATE TABLE dbo.push_device (
+  token VARCHAR(512) NOT NULL PRIMARY KEY,
+  employee_id INT NOT NULL,
+  platform VARCHAR(16) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_push_device_employee_id
+  ON dbo.push_device (employee_id);
@@ -0,0 +1 @@
+DROP TABLE dbo.business_card_event;
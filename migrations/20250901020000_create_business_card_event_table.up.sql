@@ -0,0 +1,16 @@
+CREATE TABLE dbo.business_card_event (
+  id VARCHAR(12) NOT NULL PRIMARY KEY,
+  public_id VARCHAR(32) NOT NULL,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  label NVARCHAR(200) NOT NULL,
+  valid_from DATETIME NOT NULL,
+  valid_until DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(50) NOT NULL
+);
+
+CREATE UNIQUE INDEX ux_business_card_event_public_id
+  ON dbo.business_card_event (public_id);
+
+CREATE INDEX ix_business_card_event_card_id
+  ON dbo.business_card_event (card_id, created_at DESC);
@@ -0,0 +1,38 @@
+-- Fails while guest cards remain: their employee_id cannot become NOT NULL.
+
+DROP INDEX ix_business_card_guest_id ON dbo.business_card;
+DROP INDEX ix_business_card_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_employee_id_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_status_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_employee_id_updated_at ON dbo.business_card;
+
+ALTER TABLE dbo.business_card
+  DROP CONSTRAINT ck_business_card_holder, fk_guest_id, fk_employee_id, fk_department_id, fk_position_id;
+
+ALTER TABLE dbo.business_card DROP COLUMN guest_id;
+
+ALTER TABLE dbo.business_card ALTER COLUMN employee_id INT NOT NULL;
+ALTER TABLE dbo.business_card ALTER COLUMN department_id INT NOT NULL;
+ALTER TABLE dbo.business_card ALTER COLUMN position_id INT NOT NULL;
+
+ALTER TABLE dbo.business_card
+  ADD CONSTRAINT fk_employee_id FOREIGN KEY (employee_id) REFERENCES dbo.tb_employee(EID),
+      CONSTRAINT fk_department_id FOREIGN KEY (department_id) REFERENCES dbo.tb_department(DEPID),
+      CONSTRAINT fk_position_id FOREIGN KEY (position_id) REFERENCES dbo.tb_position(POID);
+
+CREATE INDEX ix_business_card_created_at
+  ON dbo.business_card (created_at DESC, id DESC)
+  INCLUDE (employee_id, status);
+
+CREATE INDEX ix_business_card_employee_id_created_at
+  ON dbo.business_card (employee_id, created_at DESC, id DESC)
+  INCLUDE (status);
+
+CREATE INDEX ix_business_card_status_created_at
+  ON dbo.business_card (status, created_at DESC, id DESC)
+  INCLUDE (employee_id);
+
+CREATE INDEX ix_business_card_employee_id_updated_at
+  ON dbo.business_card (employee_id, updated_at, id);
+
+DROP TABLE dbo.guest_person;
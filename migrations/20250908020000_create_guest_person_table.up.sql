@@ -0,0 +1,60 @@
+CREATE TABLE dbo.guest_person (
+  id INT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  display_name NVARCHAR(200) NOT NULL,
+  email VARCHAR(512) NOT NULL DEFAULT '',
+  company_id INT NOT NULL REFERENCES dbo.tb_Branch(BID),
+  department_name NVARCHAR(200) NOT NULL DEFAULT '',
+  position_name NVARCHAR(200) NOT NULL,
+  sponsor_id INT NOT NULL REFERENCES dbo.tb_employee(EID),
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(50) NOT NULL
+);
+
+CREATE INDEX ix_guest_person_created_at
+  ON dbo.guest_person (created_at DESC, id DESC);
+
+-- Guest cards have no employee, department or position. The indexes and
+-- foreign keys on those columns are recreated once they allow NULL.
+DROP INDEX ix_business_card_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_employee_id_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_status_created_at ON dbo.business_card;
+DROP INDEX ix_business_card_employee_id_updated_at ON dbo.business_card;
+
+ALTER TABLE dbo.business_card
+  DROP CONSTRAINT fk_employee_id, fk_department_id, fk_position_id;
+
+ALTER TABLE dbo.business_card ALTER COLUMN employee_id INT NULL;
+ALTER TABLE dbo.business_card ALTER COLUMN department_id INT NULL;
+ALTER TABLE dbo.business_card ALTER COLUMN position_id INT NULL;
+
+ALTER TABLE dbo.business_card
+  ADD guest_id INT NULL;
+
+ALTER TABLE dbo.business_card
+  ADD CONSTRAINT fk_employee_id FOREIGN KEY (employee_id) REFERENCES dbo.tb_employee(EID),
+      CONSTRAINT fk_department_id FOREIGN KEY (department_id) REFERENCES dbo.tb_department(DEPID),
+      CONSTRAINT fk_position_id FOREIGN KEY (position_id) REFERENCES dbo.tb_position(POID),
+      CONSTRAINT fk_guest_id FOREIGN KEY (guest_id) REFERENCES dbo.guest_person(id),
+      CONSTRAINT ck_business_card_holder CHECK (
+        (employee_id IS NOT NULL AND guest_id IS NULL) OR
+        (employee_id IS NULL AND guest_id IS NOT NULL)
+      );
+
+CREATE INDEX ix_business_card_created_at
+  ON dbo.business_card (created_at DESC, id DESC)
+  INCLUDE (employee_id, status);
+
+CREATE INDEX ix_business_card_employee_id_created_at
+  ON dbo.business_card (employee_id, created_at DESC, id DESC)
+  INCLUDE (status);
+
+CREATE INDEX ix_business_card_status_created_at
+  ON dbo.business_card (status, created_at DESC, id DESC)
+  INCLUDE (employee_id);
+
+CREATE INDEX ix_business_card_employee_id_updated_at
+  ON dbo.business_card (employee_id, updated_at, id);
+
+CREATE INDEX ix_business_card_guest_id
+  ON dbo.business_card (guest_id, created_at DESC, id DESC);
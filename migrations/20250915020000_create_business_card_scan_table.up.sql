@@ -0,0 +1,13 @@
+CREATE TABLE dbo.business_card_scan (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  event_card_id VARCHAR(12) NULL REFERENCES dbo.business_card_event(id),
+  visitor CHAR(64) NOT NULL,
+  device VARCHAR(10) NOT NULL,
+  os VARCHAR(10) NOT NULL,
+  scanned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_business_card_scan_scanned_at
+  ON dbo.business_card_scan (scanned_at)
+  INCLUDE (card_id, visitor, device, os);
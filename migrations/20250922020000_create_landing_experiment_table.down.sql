@@ -0,0 +1,2 @@
+DROP TABLE dbo.landing_event;
+DROP TABLE dbo.landing_experiment;
@@ -0,0 +1,33 @@
+CREATE TABLE dbo.landing_experiment (
+  id VARCHAR(12) NOT NULL PRIMARY KEY,
+  name NVARCHAR(100) NOT NULL,
+  card_id VARCHAR(12) NULL REFERENCES dbo.business_card(id),
+  company_id INT NULL REFERENCES dbo.tb_Branch(BID),
+  variants NVARCHAR(MAX) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ended_at DATETIME NULL,
+  created_by VARCHAR(50) NOT NULL,
+  CONSTRAINT ck_landing_experiment_scope CHECK (
+    (card_id IS NOT NULL AND company_id IS NULL) OR
+    (card_id IS NULL AND company_id IS NOT NULL)
+  )
+);
+
+CREATE INDEX ix_landing_experiment_card_id
+  ON dbo.landing_experiment (card_id, ended_at);
+
+CREATE INDEX ix_landing_experiment_company_id
+  ON dbo.landing_experiment (company_id, ended_at);
+
+CREATE TABLE dbo.landing_event (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  experiment_id VARCHAR(12) NOT NULL REFERENCES dbo.landing_experiment(id),
+  layout VARCHAR(32) NOT NULL,
+  visitor VARCHAR(64) NOT NULL,
+  kind VARCHAR(10) NOT NULL CHECK (kind IN ('VIEW', 'CONVERSION')),
+  occurred_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_landing_event_experiment_id
+  ON dbo.landing_event (experiment_id, layout)
+  INCLUDE (kind, visitor);
@@ -0,0 +1,4 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN vcf_photo, photo_hash;
+
+DROP TABLE dbo.employee_photo;
@@ -0,0 +1,11 @@
+CREATE TABLE dbo.employee_photo (
+  employee_id INT NOT NULL PRIMARY KEY REFERENCES dbo.tb_employee(EID),
+  content_type VARCHAR(20) NOT NULL,
+  hash CHAR(64) NOT NULL,
+  data VARBINARY(MAX) NOT NULL,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE dbo.business_card
+  ADD vcf_photo VARBINARY(MAX) NULL,
+      photo_hash CHAR(64) NULL;
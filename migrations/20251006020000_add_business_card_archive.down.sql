@@ -0,0 +1,10 @@
+-- Fails while archived cards remain: restore or delete them first.
+
+ALTER TABLE dbo.business_card
+  DROP CONSTRAINT ck_business_card_status;
+
+ALTER TABLE dbo.business_card
+  DROP COLUMN archived_at, archived_from;
+
+ALTER TABLE dbo.business_card
+  ADD CONSTRAINT ck_business_card_status CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'PUBLISHED'));
@@ -0,0 +1,14 @@
+-- The status check was created unnamed, so its generated name is looked up.
+DECLARE @ck sysname = (
+  SELECT cc.name
+  FROM sys.check_constraints AS cc
+  INNER JOIN sys.columns AS c ON c.object_id = cc.parent_object_id AND c.column_id = cc.parent_column_id
+  WHERE cc.parent_object_id = OBJECT_ID('dbo.business_card') AND c.name = 'status'
+);
+IF @ck IS NOT NULL
+  EXEC('ALTER TABLE dbo.business_card DROP CONSTRAINT ' + @ck);
+
+ALTER TABLE dbo.business_card
+  ADD archived_at DATETIME NULL,
+      archived_from VARCHAR(15) NULL,
+      CONSTRAINT ck_business_card_status CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'PUBLISHED', 'ARCHIVED'));
@@ -0,0 +1 @@
+DROP TABLE dbo.export_delivery;
@@ -0,0 +1,16 @@
+CREATE TABLE dbo.export_delivery (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  export_name VARCHAR(100) NOT NULL,
+  source VARCHAR(20) NOT NULL,
+  destination NVARCHAR(400) NOT NULL,
+  file_name VARCHAR(200) NOT NULL,
+  size_bytes BIGINT NOT NULL DEFAULT 0,
+  sha256 VARCHAR(64) NOT NULL DEFAULT '',
+  status VARCHAR(10) NOT NULL CHECK (status IN ('RUNNING', 'DELIVERED', 'FAILED')),
+  error NVARCHAR(1024) NOT NULL DEFAULT '',
+  started_at DATETIME NOT NULL,
+  finished_at DATETIME NULL
+);
+
+CREATE INDEX ix_export_delivery_export ON dbo.export_delivery (export_name, status, started_at DESC);
+CREATE INDEX ix_export_delivery_started ON dbo.export_delivery (started_at DESC, id DESC);
@@ -0,0 +1,4 @@
+-- Soft-deleted cards come back once the columns are dropped: remove them
+-- first if they must stay gone.
+ALTER TABLE dbo.business_card
+  DROP COLUMN deleted_at, deleted_by;
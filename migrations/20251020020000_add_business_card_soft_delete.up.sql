@@ -0,0 +1,4 @@
+-- Deleted cards are kept with their history but left out of every query.
+ALTER TABLE dbo.business_card
+  ADD deleted_at DATETIME NULL,
+      deleted_by VARCHAR(50) NULL;
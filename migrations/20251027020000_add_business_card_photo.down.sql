@@ -0,0 +1,3 @@
+-- The uploaded photos stay in the asset storage.
+ALTER TABLE dbo.business_card
+  DROP COLUMN photo_key;
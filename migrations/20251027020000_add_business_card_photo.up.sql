@@ -0,0 +1,4 @@
+-- The photo uploaded for a card, stored in the asset storage. It takes
+-- precedence over the owner's directory photo.
+ALTER TABLE dbo.business_card
+  ADD photo_key VARCHAR(255) NULL;
@@ -0,0 +1,9 @@
+-- Roles granted to employees on top of EMPLOYEE, which everyone has. HR is
+-- also implied by the HR flag of the login.
+CREATE TABLE dbo.employee_role (
+  employee_id BIGINT NOT NULL,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('EMPLOYEE', 'MANAGER', 'HR', 'ADMIN')),
+  granted_by VARCHAR(50) NOT NULL,
+  granted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT pk_employee_role PRIMARY KEY (employee_id, role)
+);
@@ -0,0 +1,12 @@
+-- Every refresh token issued, by its jti. A token is used once: refreshing
+-- marks it used and issues its successor in the same session, the token
+-- family. Presenting a used token again revokes the session.
+CREATE TABLE dbo.refresh_token (
+  jti VARCHAR(32) NOT NULL PRIMARY KEY,
+  session_id BIGINT NOT NULL,
+  issued_at DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL,
+  used_at DATETIME NULL
+);
+
+CREATE INDEX ix_refresh_token_expires ON dbo.refresh_token (expires_at);
@@ -0,0 +1,10 @@
+-- Views of published card pages and downloads of their vCards, counted
+-- per card and hour in UTC.
+CREATE TABLE dbo.business_card_view (
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  hour DATETIME NOT NULL,
+  views INT NOT NULL DEFAULT 0,
+  downloads INT NOT NULL DEFAULT 0,
+  last_scanned_at DATETIME NOT NULL,
+  CONSTRAINT pk_business_card_view PRIMARY KEY (card_id, hour)
+);
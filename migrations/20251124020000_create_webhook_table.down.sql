@@ -0,0 +1,2 @@
+DROP TABLE dbo.webhook_delivery;
+DROP TABLE dbo.webhook;
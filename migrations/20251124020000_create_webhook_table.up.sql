@@ -0,0 +1,31 @@
+-- Endpoints notified of card lifecycle events. The secret signs payloads
+-- and is encrypted like personal data.
+CREATE TABLE dbo.webhook (
+  id VARCHAR(36) NOT NULL PRIMARY KEY,
+  url NVARCHAR(2048) NOT NULL,
+  secret NVARCHAR(1024) NOT NULL,
+  events VARCHAR(100) NOT NULL,
+  created_by VARCHAR(50) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One delivery per webhook and event, retried until it is delivered or
+-- has failed too many times.
+CREATE TABLE dbo.webhook_delivery (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  webhook_id VARCHAR(36) NOT NULL REFERENCES dbo.webhook(id) ON DELETE CASCADE,
+  event_id VARCHAR(36) NOT NULL,
+  event_type VARCHAR(20) NOT NULL,
+  payload NVARCHAR(MAX) NOT NULL,
+  status VARCHAR(10) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at DATETIME NOT NULL,
+  last_error NVARCHAR(1024) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  delivered_at DATETIME NULL,
+  CONSTRAINT uq_webhook_delivery UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX ix_webhook_delivery_due
+  ON dbo.webhook_delivery (next_attempt_at)
+  WHERE status = 'PENDING';
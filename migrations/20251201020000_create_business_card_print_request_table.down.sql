@@ -0,0 +1,9 @@
+-- Fails while print coordinators remain: revoke the role first.
+
+ALTER TABLE dbo.employee_role
+  DROP CONSTRAINT ck_employee_role_role;
+
+ALTER TABLE dbo.employee_role
+  ADD CONSTRAINT ck_employee_role_role CHECK (role IN ('EMPLOYEE', 'MANAGER', 'HR', 'ADMIN'));
+
+DROP TABLE dbo.business_card_print_request;
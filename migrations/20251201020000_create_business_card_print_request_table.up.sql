@@ -0,0 +1,37 @@
+-- Physical cards employees ask to have printed from a published card. HR
+-- marks a request ORDERED when it is sent to the print vendor and
+-- DELIVERED once the cards reach the delivery office.
+CREATE TABLE dbo.business_card_print_request (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  quantity INT NOT NULL CHECK (quantity > 0),
+  delivery_office NVARCHAR(200) NOT NULL,
+  status VARCHAR(10) NOT NULL DEFAULT 'REQUESTED' CHECK (status IN ('REQUESTED', 'ORDERED', 'DELIVERED')),
+  created_by VARCHAR(50) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(50) NOT NULL,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  ordered_at DATETIME NULL,
+  delivered_at DATETIME NULL
+);
+
+CREATE INDEX ix_business_card_print_request_created_at
+  ON dbo.business_card_print_request (created_at DESC, id DESC)
+  INCLUDE (status);
+
+CREATE INDEX ix_business_card_print_request_card_id
+  ON dbo.business_card_print_request (card_id, status);
+
+-- The print coordinator follows the requests with the vendor. The role
+-- check was created unnamed, so its generated name is looked up.
+DECLARE @ck sysname = (
+  SELECT cc.name
+  FROM sys.check_constraints AS cc
+  INNER JOIN sys.columns AS c ON c.object_id = cc.parent_object_id AND c.column_id = cc.parent_column_id
+  WHERE cc.parent_object_id = OBJECT_ID('dbo.employee_role') AND c.name = 'role'
+);
+IF @ck IS NOT NULL
+  EXEC('ALTER TABLE dbo.employee_role DROP CONSTRAINT ' + @ck);
+
+ALTER TABLE dbo.employee_role
+  ADD CONSTRAINT ck_employee_role_role CHECK (role IN ('EMPLOYEE', 'MANAGER', 'HR', 'ADMIN', 'PRINT_COORDINATOR'));
@@ -0,0 +1,11 @@
+-- Failed logins in a row per username, known or not. Reaching the lockout
+-- threshold locks the username until locked_until; a successful login
+-- deletes the row.
+CREATE TABLE dbo.login_attempt (
+  username NVARCHAR(100) NOT NULL PRIMARY KEY,
+  failures INT NOT NULL,
+  last_failure_at DATETIME NOT NULL,
+  locked_until DATETIME NULL
+);
+
+CREATE INDEX ix_login_attempt_last_failure_at ON dbo.login_attempt (last_failure_at);
@@ -0,0 +1,2 @@
+DROP TABLE dbo.password_reset;
+DROP TABLE dbo.user_password;
@@ -0,0 +1,22 @@
+-- Passwords set through the API, bcrypt hashed. They replace the legacy
+-- password of the HR login table, which is checked only for users without
+-- one here.
+CREATE TABLE dbo.user_password (
+  username NVARCHAR(100) NOT NULL PRIMARY KEY,
+  password_hash VARCHAR(60) NOT NULL,
+  changed_by NVARCHAR(100) NOT NULL,
+  changed_at DATETIME NOT NULL
+);
+
+-- One-time password reset tokens issued by HR, by the SHA-256 of the token
+-- emailed to the employee.
+CREATE TABLE dbo.password_reset (
+  token_hash CHAR(64) NOT NULL PRIMARY KEY,
+  username NVARCHAR(100) NOT NULL,
+  created_by NVARCHAR(100) NOT NULL,
+  created_at DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL,
+  used_at DATETIME NULL
+);
+
+CREATE INDEX ix_password_reset_expires_at ON dbo.password_reset (expires_at);
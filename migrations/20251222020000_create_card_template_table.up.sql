@@ -0,0 +1,16 @@
+-- The card design of a company: the logo and colors of its card pages and
+-- the layout of its landing pages. Companies without one use the default.
+CREATE TABLE dbo.card_template (
+  id VARCHAR(36) NOT NULL PRIMARY KEY,
+  company_id BIGINT NOT NULL,
+  name NVARCHAR(100) NOT NULL,
+  logo_url NVARCHAR(500) NOT NULL DEFAULT '',
+  primary_color CHAR(7) NOT NULL,
+  accent_color CHAR(7) NOT NULL,
+  layout VARCHAR(32) NOT NULL,
+  created_by VARCHAR(50) NOT NULL,
+  created_at DATETIME NOT NULL,
+  updated_by VARCHAR(50) NOT NULL,
+  updated_at DATETIME NOT NULL,
+  CONSTRAINT uq_card_template_company_id UNIQUE (company_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN display_name_en, display_name_lo, name_languages;
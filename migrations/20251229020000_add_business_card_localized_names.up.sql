@@ -0,0 +1,7 @@
+-- The name in English and Lao, and the languages the card shows it in,
+-- comma separated with the display name's first. Cards saved before have
+-- their English name in display_name only.
+ALTER TABLE dbo.business_card
+  ADD display_name_en NVARCHAR(200) NOT NULL DEFAULT '',
+      display_name_lo NVARCHAR(200) NOT NULL DEFAULT '',
+      name_languages VARCHAR(16) NOT NULL DEFAULT 'en';
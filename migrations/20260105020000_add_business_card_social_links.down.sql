@@ -0,0 +1,2 @@
+ALTER TABLE dbo.business_card
+  DROP COLUMN whatsapp, line_id, wechat_id, linkedin_url, website_url;
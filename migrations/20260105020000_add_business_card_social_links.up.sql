@@ -0,0 +1,8 @@
+-- The owner's accounts a card links to. whatsapp is a phone number, stored
+-- encrypted like the card's other numbers.
+ALTER TABLE dbo.business_card
+  ADD whatsapp VARCHAR(512) NOT NULL DEFAULT '',
+      line_id NVARCHAR(20) NOT NULL DEFAULT '',
+      wechat_id NVARCHAR(20) NOT NULL DEFAULT '',
+      linkedin_url NVARCHAR(200) NOT NULL DEFAULT '',
+      website_url NVARCHAR(200) NOT NULL DEFAULT '';
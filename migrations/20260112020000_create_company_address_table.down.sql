@@ -0,0 +1 @@
+DROP TABLE dbo.company_address;
@@ -0,0 +1,14 @@
+-- The office address of a company, which its cards carry in their vCard.
+-- latitude and longitude are both set or both NULL.
+CREATE TABLE dbo.company_address (
+  company_id BIGINT NOT NULL PRIMARY KEY,
+  street NVARCHAR(200) NOT NULL,
+  locality NVARCHAR(100) NOT NULL DEFAULT '',
+  region NVARCHAR(100) NOT NULL DEFAULT '',
+  postal_code NVARCHAR(20) NOT NULL DEFAULT '',
+  country NVARCHAR(100) NOT NULL,
+  latitude DECIMAL(9, 6) NULL,
+  longitude DECIMAL(9, 6) NULL,
+  updated_by VARCHAR(50) NOT NULL,
+  updated_at DATETIME NOT NULL
+);
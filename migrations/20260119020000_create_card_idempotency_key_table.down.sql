@@ -0,0 +1 @@
+DROP TABLE dbo.card_idempotency_key;
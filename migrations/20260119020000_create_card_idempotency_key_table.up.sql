@@ -0,0 +1,13 @@
+-- The cards created with an Idempotency-Key, so a retried create returns
+-- the card instead of creating another. Keys are per employee and expire
+-- after the window of card.NewService, when they are purged.
+CREATE TABLE dbo.card_idempotency_key (
+  employee_id BIGINT NOT NULL,
+  idempotency_key NVARCHAR(255) NOT NULL,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  created_at DATETIME NOT NULL,
+  CONSTRAINT pk_card_idempotency_key PRIMARY KEY (employee_id, idempotency_key)
+);
+
+CREATE INDEX ix_card_idempotency_key_created_at
+  ON dbo.card_idempotency_key (created_at);
@@ -0,0 +1 @@
+DROP TABLE dbo.job_queue;
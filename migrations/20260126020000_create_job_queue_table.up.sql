@@ -0,0 +1,17 @@
+-- Background jobs, see package jobs. Jobs that ran are deleted; DEAD jobs
+-- failed every attempt and are kept for someone to look into.
+CREATE TABLE dbo.job_queue (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  kind VARCHAR(50) NOT NULL,
+  payload NVARCHAR(MAX) NOT NULL,
+  status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+  attempts INT NOT NULL DEFAULT 0,
+  run_at DATETIME NOT NULL,
+  locked_until DATETIME NULL,
+  last_error NVARCHAR(1024) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL
+);
+
+CREATE INDEX ix_job_queue_pending
+  ON dbo.job_queue (run_at, id)
+  WHERE status = 'PENDING';
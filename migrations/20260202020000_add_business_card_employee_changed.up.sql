@@ -0,0 +1,4 @@
+-- Set on a published card when an HR import changes its owner's name, email
+-- or organisation.
+ALTER TABLE dbo.business_card
+  ADD employee_changed_at DATETIME NULL;
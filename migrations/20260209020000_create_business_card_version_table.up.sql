@@ -0,0 +1,18 @@
+-- What a card showed each time it was published, encrypted as it holds the
+-- owner's contact details.
+CREATE TABLE dbo.business_card_version (
+  id BIGINT IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL,
+  employee_id INT NULL,
+  data NVARCHAR(MAX) NOT NULL,
+  created_by VARCHAR(50) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL,
+  CONSTRAINT fk_business_card_version_card_id FOREIGN KEY (card_id) REFERENCES dbo.business_card(id)
+);
+
+CREATE INDEX ix_business_card_version_card_id
+  ON dbo.business_card_version (card_id, id);
+
+CREATE INDEX ix_business_card_version_employee_id
+  ON dbo.business_card_version (employee_id, id)
+  WHERE employee_id IS NOT NULL;
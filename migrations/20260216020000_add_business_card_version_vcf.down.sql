@@ -0,0 +1,4 @@
+DROP TRIGGER dbo.tr_business_card_version_immutable;
+
+ALTER TABLE dbo.business_card_version
+  DROP COLUMN vcf, vcf_hash;
@@ -0,0 +1,13 @@
+-- Versions freeze the vCard the card was published with, which is what its
+-- QR code and downloads serve.
+ALTER TABLE dbo.business_card_version
+  ADD vcf VARBINARY(MAX) NULL,
+      vcf_hash VARCHAR(64) NULL;
+
+-- A version is never changed once written. CREATE TRIGGER must start a
+-- batch, hence EXEC.
+EXEC('CREATE TRIGGER tr_business_card_version_immutable
+  ON dbo.business_card_version
+  INSTEAD OF UPDATE, DELETE
+AS
+  THROW 51000, ''card versions cannot be changed'', 1;');
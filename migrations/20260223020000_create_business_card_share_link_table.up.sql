@@ -0,0 +1,12 @@
+CREATE TABLE dbo.business_card_share_link (
+  id VARCHAR(12) NOT NULL PRIMARY KEY,
+  card_id VARCHAR(12) NOT NULL REFERENCES dbo.business_card(id),
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(50) NOT NULL,
+  revoked_at DATETIME NULL,
+  revoked_by VARCHAR(50) NULL
+);
+
+CREATE INDEX ix_business_card_share_link_card_id
+  ON dbo.business_card_share_link (card_id, created_at DESC);
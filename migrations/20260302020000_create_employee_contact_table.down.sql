@@ -0,0 +1 @@
+DROP TABLE dbo.employee_contact;
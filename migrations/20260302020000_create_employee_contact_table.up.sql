@@ -0,0 +1,10 @@
+-- The contact details this service records for an employee, encrypted
+-- like the cards' (see package pii). They are kept apart from
+-- dbo.tb_employee, whose columns other systems read in plain text.
+CREATE TABLE dbo.employee_contact (
+  employee_id BIGINT NOT NULL PRIMARY KEY,
+  email VARCHAR(512) NOT NULL DEFAULT '',
+  phone_number VARCHAR(512) NOT NULL DEFAULT '',
+  mobile_number VARCHAR(512) NOT NULL DEFAULT '',
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+DROP TABLE dbo.event_outbox_lease;
+DROP TABLE dbo.event_outbox_delivery;
@@ -0,0 +1,18 @@
+-- The subscribers each outbox entry was delivered to, so a retry of the
+-- entry publishes it only to those that failed.
+CREATE TABLE dbo.event_outbox_delivery (
+  seq BIGINT NOT NULL REFERENCES dbo.event_outbox(seq) ON DELETE CASCADE,
+  subscriber VARCHAR(32) NOT NULL,
+  delivered_at DATETIME NOT NULL,
+  CONSTRAINT pk_event_outbox_delivery PRIMARY KEY (seq, subscriber)
+);
+
+-- The lease of the replica relaying the outbox, held without keeping a
+-- transaction open while it publishes.
+CREATE TABLE dbo.event_outbox_lease (
+  id TINYINT NOT NULL PRIMARY KEY CHECK (id = 1),
+  holder VARCHAR(36) NOT NULL DEFAULT '',
+  expires_at DATETIME NOT NULL DEFAULT '1900-01-01'
+);
+
+INSERT INTO dbo.event_outbox_lease (id) VALUES (1);
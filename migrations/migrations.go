@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations of the service, so the
+// binary can check and apply them without the files next to it.
+package migrations
+
+import "embed"
+
+// FS holds the <version>_<name>.up.sql and .down.sql files.
+//
+//go:embed *.sql
+var FS embed.FS